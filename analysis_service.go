@@ -1,18 +1,87 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	analysispkg "RepoDoctor/internal/analysis"
+	"RepoDoctor/internal/languages"
+	"RepoDoctor/internal/model"
 )
 
+// AnalyzeRequest holds everything an analyze run needs. The analyze flags
+// fill it once and it passes unchanged to the service.
 type AnalyzeRequest struct {
-	Path            string
-	Format          string
-	Verbose         bool
-	ColorEnabled    bool
+	Path    string
+	Format  string
+	Verbose bool
+	// Mode runs the analysis once, on every change, for the graph
+	// statistics only or on each directory listed on stdin
+	Mode            analyzeMode
 	ExitOnViolation bool
+	// Quiet suppresses progress and the report, for callers that print the
+	// report analyze returns themselves
+	Quiet   bool
+	Report  ReportOptions
+	Options AnalyzeOptions
+}
+
+// ReportOptions decides how the report is printed and which files it is
+// also written to
+type ReportOptions struct {
+	ColorEnabled bool
+	Width        int
+	BasePath     string
+	PrintScore   bool
+	RuleOutputs  []RuleOutput
+	// Outputs write the report to files in further formats
+	Outputs []ReportOutput
+	// Listing shortens and groups the violation listings of the text report
+	Listing ViolationListing
+	// Explain adds the score explanation to text and JSON output
+	Explain bool
+	// ASCII prints the text report without box drawing, arrows and emoji
+	ASCII bool
+}
+
+// AnalyzeOptions selects the rules that run and what the report includes.
+type AnalyzeOptions struct {
+	Rules             *RuleSelection
+	Sample            *SampleSpec
+	SelfCheck         bool
+	ForceHistoryEntry bool
+	// NoLargest omits the largest files and functions from the report
+	NoLargest bool
+	// IncludeTestEdges lets the graph rules follow imports of test files,
+	// which they otherwise ignore
+	IncludeTestEdges bool
+	// TreeDepth limits -format tree to this many directory levels below
+	// the root; 0 shows the whole tree
+	TreeDepth int
+	// ProvidedImports supplies the imports of files the caller already
+	// parsed, keyed by file path. Those files are neither walked nor parsed;
+	// every other file is discovered on disk as usual.
+	ProvidedImports map[string]*ImportMetadata
+	// ProvidedFiles supplies file contents keyed by file path, used instead
+	// of reading the file. Go files without provided imports get their
+	// imports parsed from the content.
+	ProvidedFiles map[string]string
+	// Exit decides the exit code: the -fail-on level, the -fail-under score
+	// floor and whether a rule hitting its rules.timeouts entry fails the run
+	Exit ExitPolicy
+	// NoNotices suppresses notices on stderr, such as format deprecations
+	NoNotices bool
+	// Deterministic fixes timestamps, zeroes rule durations and sorts every
+	// violation list, so repeated runs on the same files print identical
+	// output
+	Deterministic bool
+	// MinSeverity leaves the violations below it out of the printed report;
+	// the score and exit code still count them
+	MinSeverity severityThreshold
+	// AbsPaths keeps reported file paths absolute instead of relative to
+	// the analyzed directory; -base-path still takes precedence
+	AbsPaths bool
 }
 
 type AnalysisService struct{}
@@ -22,22 +91,41 @@ func NewAnalysisService() *AnalysisService {
 }
 
 func (s *AnalysisService) Run(request AnalyzeRequest) int {
-	absPath := validatePath(request.Path)
-	InitColorFormatter(request.ColorEnabled)
+	_, exitCode := s.analyze(request)
+	return exitCode
+}
+
+// analyze runs the analysis and returns the report with the exit code. The
+// report is nil when the analysis failed.
+func (s *AnalysisService) analyze(request AnalyzeRequest) (*StructuralReport, int) {
+	InitColorFormatter(request.Report.ColorEnabled)
+
+	// Score-only output and documents meant for other tools must keep stdout
+	// free of progress and diagnostics
+	quiet := request.Quiet || request.Report.PrintScore || isDocumentFormat(OutputFormat(request.Format))
+	if quiet {
+		request.Verbose = false
+	}
+
+	absPath, config, code := resolveAnalyzeTarget(request)
+	if code != exitCodeClean {
+		return nil, code
+	}
 
-	progress := NewProgressReporter(!request.Verbose)
+	progress := newRequestProgress(request, quiet)
 	progress.Start("Scanning repository", getStageCount("Scanning repository", absPath))
 	if request.Verbose {
 		fmt.Printf(ColorInfo("Extracting imports from: ")+"%s\n", absPath)
 	}
 
-	analysisResult, err := runAdapterPipeline(absPath)
+	analysisResult, provided, err := runAnalysisExtraction(absPath, config, request.Options)
 	if err != nil {
+		emitEnvError(request.Format, WrapError(err, ErrorAnalysis, "Analysis pipeline failed", ""))
 		fmt.Fprintf(os.Stderr, "%s", ColorError(fmt.Sprintf("Error: analysis pipeline failed: %v\n", err)))
 		if request.ExitOnViolation {
-			os.Exit(1)
+			os.Exit(exitCodeFailure)
 		}
-		return 1
+		return nil, exitCodeFailure
 	}
 
 	if request.Verbose {
@@ -45,12 +133,11 @@ func (s *AnalysisService) Run(request AnalyzeRequest) int {
 	}
 
 	graph := s.reportAdapterGraph(progress, analysisResult, request.Verbose)
+	if request.Options.IncludeTestEdges {
+		graph = withTestEdgesAsProduction(graph)
+	}
 
 	progress.Start("Collecting metrics", getStageCount("Collecting metrics", absPath))
-	totalFiles, goFiles, totalLines := scanDirectory(absPath, false)
-	_ = totalFiles
-	_ = goFiles
-	_ = totalLines
 	progress.SetProgress(progress.totalSteps)
 	progress.Complete()
 
@@ -58,30 +145,141 @@ func (s *AnalysisService) Run(request AnalyzeRequest) int {
 	progress.SetProgress(progress.totalSteps)
 	progress.Complete()
 
-	config := loadConfiguration(absPath, request.Verbose)
+	if request.Verbose {
+		fmt.Printf("%s", ColorInfo(fmt.Sprintf("Configuration loaded from: %s\n", GetConfigPath(absPath))))
+	}
 
 	progress.Start("Running rules", getStageCount("Running rules", absPath))
-	ruleSummary := runInternalRulePipeline(absPath, graph)
+	ruleSummary := runInternalRulePipelineWithSources(absPath, graph, config, request.Options.Rules, request.Options.Sample, provided)
 	progress.SetProgress(progress.totalSteps / 2)
 
-	report := generateRuleEngineReport(absPath, request.Format, request.Verbose, request.ColorEnabled, config, ruleSummary)
+	report, err := generateRuleEngineReport(absPath, request, config, ruleSummary)
+	if err != nil {
+		return nil, abortRun(request, err, exitCodeForError(err))
+	}
 	progress.SetProgress(progress.totalSteps)
 	progress.Complete()
 
-	handleTrendAnalysis(absPath, report, request.Verbose)
+	// A sampled score is not comparable with full runs, so it stays out of
+	// the trend history
+	if request.Options.Sample == nil {
+		handleTrendAnalysis(absPath, report, config, request)
+	}
+	persistLatestReport(absPath, report, config, request)
+	persistBadge(absPath, report, config, request)
+	persistParseCache(absPath, config, request)
 
 	exitCode := determineExitCode(report)
 	if request.ExitOnViolation && exitCode != 0 {
 		os.Exit(exitCode)
 	}
 
-	return exitCode
+	return report, exitCode
+}
+
+// runAnalysisExtraction runs the adapter pipeline. Sampled runs skip adapter
+// metrics and only build the dependency graph from imports-only parses.
+// Files provided in options are left out of the pipeline and merged into
+// the graph from the caller's data, and are returned for the rules; a
+// directory without supported files is fine when files were provided.
+func runAnalysisExtraction(absPath string, config *Config, options AnalyzeOptions) (*analysispkg.Result, *providedSources, error) {
+	provided := newProvidedSources(absPath, options)
+	orchestrator := newAnalysisOrchestratorWithTestEdges(absPath, options.IncludeTestEdges)
+	orchestrator.SkipFiles(provided.paths())
+	run := orchestrator.Analyze
+	if options.Sample != nil {
+		run = orchestrator.AnalyzeGraph
+	}
+	result, err := run(absPath)
+	if err != nil && provided != nil && errors.Is(err, languages.ErrNoSupportedLanguage) {
+		result, err = &analysispkg.Result{AdapterName: "provided", Graph: model.NewDependencyGraph()}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	provided.addToGraph(result.Graph, testEdgeModeFor(config, options.IncludeTestEdges))
+	return result, provided, nil
+}
+
+// resolveAnalyzeTarget returns the canonical path of the directory to
+// analyze and its configuration, loaded once for the whole run. A path that
+// is not a readable directory and a config file that does not load end the
+// analysis with their own exit codes.
+func resolveAnalyzeTarget(request AnalyzeRequest) (string, *Config, int) {
+	absPath, pathErr := resolveDirectoryPath(request.Path)
+	if pathErr != nil {
+		return "", nil, abortRun(request, pathErr, exitCodePathError)
+	}
+	configPath := GetConfigPath(absPath)
+	config, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		configErr := NewCLIError(ErrorConfiguration, fmt.Sprintf("Invalid configuration: %s", configPath), "Fix the config file or remove it to use the defaults", err)
+		return "", nil, abortRun(request, configErr, exitCodeConfigError)
+	}
+	return absPath, config, exitCodeClean
+}
+
+// abortRun reports an error that ends the analysis and returns code,
+// exiting the process with it when the request asks for it
+func abortRun(request AnalyzeRequest, err error, code int) int {
+	emitEnvError(request.Format, err)
+	PrintError(err)
+	if request.ExitOnViolation {
+		os.Exit(code)
+	}
+	return code
+}
+
+// graphStatsTopN is the number of fan-in/fan-out entries shown in graph-only mode
+const graphStatsTopN = 5
+
+// RunGraphOnly extracts the dependency graph and prints its statistics.
+// Metrics collection and rule execution are skipped and no history is written.
+func (s *AnalysisService) RunGraphOnly(request AnalyzeRequest) error {
+	absPath := validatePath(request.Path)
+
+	result, err := newAnalysisOrchestratorWithTestEdges(absPath, request.Options.IncludeTestEdges).AnalyzeGraph(absPath)
+	if err != nil {
+		return WrapError(err, ErrorAnalysis, "Dependency graph extraction failed", GetSuggestion(err.Error()))
+	}
+
+	graph := buildDependencyGraphFromModel(result.Graph, request.Verbose)
+	stats := ComputeGraphStats(graph, buildDependencyInventory(absPath, graph, nil, nil), graphStatsTopN)
+	if base := reportBase(&StructuralReport{Path: absPath}, request.Report.BasePath, request.Options.AbsPaths); base != "" {
+		stats.relativeTo(base)
+	}
+	fmt.Print(formatGraphStats(stats, request.Format))
+	return nil
+}
+
+// newRequestProgress creates the progress bar of an analyze request, shown
+// unless the request is verbose or quiet
+func newRequestProgress(request AnalyzeRequest, quiet bool) *ProgressReporter {
+	progress := NewProgressReporter(!request.Verbose && !quiet)
+	progress.ascii = request.Report.ASCII
+	return progress
 }
 
 func (s *AnalysisService) reportAdapterGraph(progress *ProgressReporter, result *analysispkg.Result, verbose bool) Graph {
 	progress.SetProgress(progress.totalSteps / 2)
 	graph := buildDependencyGraphFromModel(result.Graph, verbose)
+	if verbose && result.TestOverlay != nil {
+		fmt.Printf("%s", ColorInfo(fmt.Sprintf("Built test overlay graph with %d nodes and %d edges\n",
+			result.TestOverlay.NodeCount(), result.TestOverlay.EdgeCount())))
+	}
 	progress.SetProgress(progress.totalSteps)
 	progress.Complete()
 	return graph
 }
+
+// isDocumentFormat reports whether a format prints a document that is piped
+// into another tool or published as is: every JSON format, env, fix plan,
+// SARIF, JUnit, HTML, the JSON tree, Mermaid, checkstyle, Markdown, JSON
+// Lines, ndjson, TAP and badge
+func isDocumentFormat(format OutputFormat) bool {
+	switch format {
+	case FormatJSON, FormatJSONV1, FormatJSONLegacy, FormatEnv, FormatFixPlan, FormatSARIF, FormatJUnit, FormatHTML, FormatTreeJSON, FormatMermaid, FormatCheckstyle, FormatMarkdown, FormatJSONL, FormatNDJSON, FormatTAP, FormatBadge:
+		return true
+	}
+	return false
+}
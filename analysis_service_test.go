@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureStdout runs fn and returns everything it wrote to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	original := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = original }()
+
+	fn()
+
+	writer.Close()
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read captured stdout: %v", err)
+	}
+	return string(out)
+}
+
+func writeServiceFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create fixture dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write fixture %s: %v", name, err)
+		}
+	}
+}
+
+// analyzeFixture writes files to a temporary directory and analyzes it
+// quietly as JSON, failing the test when the analysis returns no report
+func analyzeFixture(t *testing.T, files map[string]string, options AnalyzeOptions) (*StructuralReport, int) {
+	t.Helper()
+	dir := t.TempDir()
+	writeServiceFixture(t, dir, files)
+	report, code := NewAnalysisService().analyze(AnalyzeRequest{Path: dir, Format: string(FormatJSON), Quiet: true, Options: options})
+	if report == nil {
+		t.Fatalf("analysis failed with exit code %d", code)
+	}
+	return report, code
+}
+
+func TestAnalysisService_PrintScoreOutputsOnlyScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		wantOut  string
+		wantExit int
+	}{
+		{
+			name: "clean",
+			files: map[string]string{
+				"go.mod":  "module example.com/app\n\ngo 1.21\n",
+				"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"ok\") }\n",
+			},
+			wantOut:  "100.0\n",
+			wantExit: 0,
+		},
+		{
+			name: "layer violation",
+			files: map[string]string{
+				"go.mod":        "module example.com/app\n\ngo 1.21\n",
+				"repo/store.go": "package repo\n\nimport _ \"example.com/app/handler\"\n",
+				"handler/h.go":  "package handler\n",
+			},
+			wantOut:  "95.0\n",
+			wantExit: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "project")
+			writeServiceFixture(t, root, tt.files)
+
+			exitCode := 0
+			out := captureStdout(t, func() {
+				exitCode = NewAnalysisService().Run(AnalyzeRequest{
+					Path:   root,
+					Format: "text",
+					Report: ReportOptions{PrintScore: true},
+				})
+			})
+
+			if out != tt.wantOut {
+				t.Fatalf("expected stdout %q, got %q", tt.wantOut, out)
+			}
+			if exitCode != tt.wantExit {
+				t.Fatalf("expected exit code %d, got %d", tt.wantExit, exitCode)
+			}
+		})
+	}
+}
+
+func TestDetermineExitCode_FailUnderReplacesViolationCheck(t *testing.T) {
+	critical := []LayerViolation{{From: "store/s.go", To: "api/h.go"}}
+	tests := []struct {
+		name      string
+		score     float64
+		layer     []LayerViolation
+		failUnder float64
+		want      int
+	}{
+		{name: "unset fails on critical violations", score: 95, layer: critical, want: 1},
+		{name: "unset passes without critical violations", score: 80, want: 0},
+		{name: "score above floor passes despite critical violations", score: 95, layer: critical, failUnder: 90, want: 0},
+		{name: "score at floor passes", score: 90, failUnder: 90, want: 0},
+		{name: "score below floor fails without critical violations", score: 80, failUnder: 90, want: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := &StructuralReport{
+				Score:         &StructuralScore{TotalScore: tc.score, MaxScore: 100},
+				Layer:         tc.layer,
+				Size:          []SizeViolation{{File: "big.go"}},
+				HasViolations: true,
+				Metrics:       ReportMetrics{Exit: ExitPolicy{FailUnder: tc.failUnder}},
+			}
+			if got := determineExitCode(report); got != tc.want {
+				t.Fatalf("expected exit code %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalysisService_FailUnderDecidesExitCode(t *testing.T) {
+	files := map[string]string{"big.go": "package big\n" + strings.Repeat("// filler\n", 600)}
+
+	for _, tc := range []struct {
+		failUnder float64
+		want      int
+	}{{0, 0}, {99, 2}, {90, 0}} {
+		report, code := analyzeFixture(t, files, AnalyzeOptions{NoLargest: true, Exit: ExitPolicy{FailUnder: tc.failUnder}})
+		if report.Score.TotalScore != 97 {
+			t.Fatalf("expected one size violation scoring 97, got %+v", report)
+		}
+		if code != tc.want {
+			t.Fatalf("-fail-under %g: expected exit code %d, got %d", tc.failUnder, tc.want, code)
+		}
+	}
+}
+
+func TestComposeAnalyzeRequest_FailUnder(t *testing.T) {
+	req, err := parseAnalyzeFlags([]string{"-fail-under", "85.5", "."})
+	if err != nil || req.Options.Exit.FailUnder != 85.5 {
+		t.Fatalf("expected -fail-under 85.5, got %+v, %v", req, err)
+	}
+	if _, err := parseAnalyzeFlags([]string{"-fail-under", "-1", "."}); err == nil {
+		t.Fatal("expected a negative -fail-under to be rejected")
+	}
+}
+
+func TestAnalysisService_JSONFormatsKeepStdoutValid(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, map[string]string{"go.mod": "module example.com/app\n\ngo 1.24\n", "main.go": "package main\n\nfunc main() {}\n"})
+
+	for _, format := range []OutputFormat{FormatJSON, FormatJSONV1, FormatJSONLegacy, FormatTreeJSON} {
+		out := captureStdout(t, func() {
+			NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(format)})
+		})
+		if !json.Valid([]byte(out)) {
+			t.Errorf("%s: expected only the json document on stdout, got:\n%s", format, out)
+		}
+	}
+}
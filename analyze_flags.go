@@ -0,0 +1,300 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// analyzeOptionFlags holds the analyze flags that fill AnalyzeOptions
+type analyzeOptionFlags struct {
+	onlyRules         *string
+	skipRules         *string
+	selfCheck         *bool
+	forceHistoryEntry *bool
+	sampleFraction    *float64
+	sampleSeed        *int64
+	tests             *testFileFlags
+	noLargest         *bool
+	includeTestEdges  *bool
+	depth             *int
+	exit              *exitPolicyFlags
+	quiet             *bool
+	deterministic     *bool
+	minSeverity       *string
+	absPaths          *bool
+}
+
+// bindAnalyzeOptionFlags registers the AnalyzeOptions flags on fs
+func bindAnalyzeOptionFlags(fs *flag.FlagSet) *analyzeOptionFlags {
+	return &analyzeOptionFlags{
+		onlyRules:         fs.String("only", "", "Run only these rules (comma-separated)"),
+		skipRules:         fs.String("skip", "", "Skip these rules (comma-separated)"),
+		selfCheck:         fs.Bool("self-check", false, "Verify report counts and penalties are consistent before printing"),
+		forceHistoryEntry: fs.Bool("force-history-entry", false, "Always append a history entry, bypassing deduplication"),
+		sampleFraction:    fs.Float64("sample", 0, "Run per-file rules on this fraction of files (0 < f <= 1)"),
+		sampleSeed:        fs.Int64("seed", 0, "Seed for -sample file selection"),
+		tests:             bindTestFileFlags(fs),
+		noLargest:         fs.Bool("no-largest", false, "Omit the largest files and functions from the report"),
+		includeTestEdges:  fs.Bool("include-test-edges", false, "Let graph rules follow imports declared by test files"),
+		depth:             fs.Int("depth", 0, "Limit -format tree to this many directory levels (0: unlimited)"),
+		exit:              bindExitPolicyFlags(fs),
+		quiet:             fs.Bool("quiet", false, "Suppress notices on stderr, such as format deprecations"),
+		deterministic:     fs.Bool("deterministic", false, "Fix timestamps and durations so repeated runs print identical output"),
+		minSeverity:       fs.String("min-severity", "", "Report only violations of this severity (info, warning, error, critical) or section level (low, medium, high) or higher"),
+		absPaths:          fs.Bool("abs-paths", false, "Report absolute file paths instead of paths relative to the analyzed directory"),
+	}
+}
+
+// options validates the parsed flags and returns the AnalyzeOptions they
+// select
+func (f *analyzeOptionFlags) options() (AnalyzeOptions, error) {
+	selection, err := parseRuleSelection(*f.onlyRules, *f.skipRules)
+	if err != nil {
+		return AnalyzeOptions{}, err
+	}
+	if selection.Tests, err = f.tests.mode(); err != nil {
+		return AnalyzeOptions{}, err
+	}
+	sample, err := parseSampleSpec(*f.sampleFraction, *f.sampleSeed)
+	if err != nil {
+		return AnalyzeOptions{}, err
+	}
+	minSeverity, err := parseMinSeverity(*f.minSeverity)
+	if err != nil {
+		return AnalyzeOptions{}, err
+	}
+	exit, err := f.exit.policy()
+	if err != nil {
+		return AnalyzeOptions{}, err
+	}
+	return AnalyzeOptions{
+		Rules:             selection,
+		Sample:            sample,
+		SelfCheck:         *f.selfCheck,
+		ForceHistoryEntry: *f.forceHistoryEntry,
+		NoLargest:         *f.noLargest,
+		IncludeTestEdges:  *f.includeTestEdges,
+		TreeDepth:         *f.depth,
+		Exit:              exit,
+		NoNotices:         *f.quiet,
+		Deterministic:     *f.deterministic,
+		MinSeverity:       minSeverity,
+		AbsPaths:          *f.absPaths,
+	}, nil
+}
+
+// analyzeMode is how the analyze command runs the analysis
+type analyzeMode int
+
+const (
+	// analyzeOnce analyzes the directory once
+	analyzeOnce analyzeMode = iota
+	// analyzeWatch analyzes the directory again on every change
+	analyzeWatch
+	// analyzeGraphOnly prints the dependency graph statistics only
+	analyzeGraphOnly
+	// analyzeStdinTargets analyzes each directory listed on stdin
+	analyzeStdinTargets
+)
+
+// reportFlags holds the analyze flags that fill ReportOptions
+type reportFlags struct {
+	noColor     *bool
+	width       *int
+	basePath    *string
+	printScore  *bool
+	top         *int
+	groupBy     *string
+	explain     *bool
+	ascii       *bool
+	ruleOutputs *ruleOutputFlags
+}
+
+// bindReportFlags registers the ReportOptions flags on fs
+func bindReportFlags(fs *flag.FlagSet) *reportFlags {
+	f := &reportFlags{
+		noColor:     fs.Bool("no-color", false, "Disable colored output"),
+		width:       fs.Int("width", 0, "Force the text report width (default: terminal width)"),
+		basePath:    fs.String("base-path", "", "Report file paths relative to this directory"),
+		printScore:  fs.Bool("print-score", false, "Print only the numeric total score"),
+		top:         fs.Int("top", 0, "List at most this many violations per category in the text report (0: all)"),
+		groupBy:     fs.String("group-by", "", "Add violation counts per dir or package to the text report"),
+		explain:     fs.Bool("explain", false, "Explain the score's weights and penalties in text and JSON output"),
+		ascii:       fs.Bool("ascii", false, "Print the text report in plain ASCII, without box drawing, arrows and emoji"),
+		ruleOutputs: &ruleOutputFlags{},
+	}
+	fs.Var(f.ruleOutputs, "out-rule", "Write one rule's violations to a file as <rule>:<format>:<path> (repeatable)")
+	return f
+}
+
+// report validates the parsed flags and returns the ReportOptions they
+// select; the outputs of -output are planned with the format
+func (f *reportFlags) report() (ReportOptions, error) {
+	listing := ViolationListing{Top: *f.top, GroupBy: *f.groupBy}
+	if err := validateViolationListing(listing); err != nil {
+		return ReportOptions{}, err
+	}
+	basePath := ""
+	if *f.basePath != "" {
+		var err error
+		if basePath, err = normalizeAnalyzePathInput(*f.basePath); err != nil {
+			return ReportOptions{}, err
+		}
+	}
+	ruleOutputs, err := parseRuleOutputs(*f.ruleOutputs)
+	if err != nil {
+		return ReportOptions{}, err
+	}
+	return ReportOptions{
+		ColorEnabled: !*f.noColor,
+		Width:        *f.width,
+		BasePath:     basePath,
+		PrintScore:   *f.printScore,
+		RuleOutputs:  ruleOutputs,
+		Listing:      listing,
+		Explain:      *f.explain,
+		ASCII:        asciiOutput(*f.ascii),
+	}, nil
+}
+
+// parseAnalyzeFlags parses the analyze arguments into the request the
+// service runs
+func parseAnalyzeFlags(args []string) (*AnalyzeRequest, error) {
+	analyzeCmd := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	analyzeCmd.SetOutput(os.Stderr)
+
+	path := analyzeCmd.String("path", ".", "Path to analyze")
+	format := analyzeCmd.String("format", "text", "Output format, or several comma-separated with -output (text, json, json-v1, json-legacy, env, fixplan, sarif, junit, html, tree, tree-json, mermaid, checkstyle, markdown, jsonl, ndjson, tap, badge)")
+	output := analyzeCmd.String("output", "", "Write each non-text format to <output>.<ext>; text is still printed")
+	verbose := analyzeCmd.Bool("verbose", false, "Enable verbose output")
+	jsonOut := analyzeCmd.Bool("json", false, "Output in JSON format")
+	watch := analyzeCmd.Bool("watch", false, "Enable watch mode for continuous analysis")
+	graphOnly := analyzeCmd.Bool("graph-only", false, "Only build the dependency graph and print its statistics")
+	reportFlags := bindReportFlags(analyzeCmd)
+	optionFlags := bindAnalyzeOptionFlags(analyzeCmd)
+
+	if err := analyzeCmd.Parse(args); err != nil {
+		return nil, NewCLIError(
+			ErrorCLIUsage,
+			fmt.Sprintf("Invalid analyze arguments: %v", err),
+			"Run 'repodoctor help' to review analyze command usage",
+			err,
+		)
+	}
+
+	outputFormat := *format
+	if *jsonOut {
+		outputFormat = "json"
+	}
+
+	options, err := optionFlags.options()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTreeDepth(options.TreeDepth); err != nil {
+		return nil, err
+	}
+	report, err := reportFlags.report()
+	if err != nil {
+		return nil, err
+	}
+	printed, outputs, err := planReportOutputs(outputFormat, *output)
+	if err != nil {
+		return nil, err
+	}
+	report.Outputs = outputs
+
+	mode := analyzeOnce
+	if *watch {
+		mode = analyzeWatch
+	} else if *graphOnly {
+		mode = analyzeGraphOnly
+	}
+	resolvedPath := resolveAnalyzePathArg(args, *path, analyzeCmd.Args())
+	if resolvedPath == stdinTargetsPath {
+		if err := validateStdinTargets(mode, outputFormat, *output); err != nil {
+			return nil, err
+		}
+		mode, resolvedPath = analyzeStdinTargets, "."
+	}
+	normalizedPath, err := normalizeAnalyzePathInput(resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnalyzeRequest{
+		Path:    normalizedPath,
+		Format:  string(printed),
+		Verbose: *verbose,
+		Mode:    mode,
+		// Every format goes to a file when text is not among them
+		Quiet:   printed == "",
+		Report:  report,
+		Options: options,
+	}, nil
+}
+
+// analyzeFormats are the output formats analyze accepts
+var analyzeFormats = []OutputFormat{FormatText, FormatJSON, FormatJSONV1, FormatJSONLegacy, FormatEnv, FormatFixPlan, FormatSARIF, FormatJUnit, FormatHTML, FormatTree, FormatTreeJSON, FormatMermaid, FormatCheckstyle, FormatMarkdown, FormatJSONL, FormatNDJSON, FormatTAP, FormatBadge}
+
+// validateAnalyzeFormat rejects unknown formats, which would otherwise fall
+// back to text output
+func validateAnalyzeFormat(format string) error {
+	names := make([]string, len(analyzeFormats))
+	for i, candidate := range analyzeFormats {
+		if OutputFormat(format) == candidate {
+			return nil
+		}
+		names[i] = string(candidate)
+	}
+	return NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid format: %s", format), "Valid formats: "+strings.Join(names, ", "), nil)
+}
+
+func normalizeAnalyzePathInput(pathArg string) (string, error) {
+	if strings.TrimSpace(pathArg) == "" {
+		return "", NewCLIError(
+			ErrorInvalidArgument,
+			"Analyze path cannot be empty",
+			"Provide a valid repository path with -path or positional argument",
+			nil,
+		)
+	}
+
+	cleaned := filepath.Clean(pathArg)
+	absPath, err := filepath.Abs(cleaned)
+	if err != nil {
+		return "", HandleInvalidPathError(pathArg, err)
+	}
+	absPath = filepath.Clean(absPath)
+
+	if resolvedPath, err := filepath.EvalSymlinks(absPath); err == nil {
+		return filepath.Clean(resolvedPath), nil
+	}
+
+	return absPath, nil
+}
+
+func resolveAnalyzePathArg(rawArgs []string, pathFlag string, positional []string) string {
+	if hasExplicitPathFlag(rawArgs) {
+		return pathFlag
+	}
+
+	if len(positional) > 0 {
+		return positional[0]
+	}
+
+	return pathFlag
+}
+
+func hasExplicitPathFlag(rawArgs []string) bool {
+	for _, arg := range rawArgs {
+		if arg == "-path" || arg == "--path" || strings.HasPrefix(arg, "-path=") || strings.HasPrefix(arg, "--path=") {
+			return true
+		}
+	}
+
+	return false
+}
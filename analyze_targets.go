@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinTargetsPath is the analyze path that reads the directories to analyze
+// from stdin, one per line
+const stdinTargetsPath = "-"
+
+// stdinTargetFormats are the output formats that can hold several reports:
+// text prints one report after another, the JSON formats print an array
+var stdinTargetFormats = []OutputFormat{FormatText, FormatJSON, FormatJSONV1, FormatJSONLegacy}
+
+// validateStdinTargets rejects the analyze modes that only make sense for a
+// single directory
+func validateStdinTargets(mode analyzeMode, format, output string) error {
+	if mode != analyzeOnce || output != "" {
+		return NewCLIError(ErrorInvalidArgument, "Reading analyze targets from stdin does not support -watch, -graph-only or -output", "Analyze a single directory with -path instead", nil)
+	}
+	for _, candidate := range stdinTargetFormats {
+		if OutputFormat(format) == candidate {
+			return nil
+		}
+	}
+	return NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Format %s cannot hold several reports", format), "Use -format text, json, json-v1 or json-legacy when reading targets from stdin", nil)
+}
+
+// readAnalyzeTargets returns the non-empty lines of r, trimmed and
+// normalized like an analyze path
+func readAnalyzeTargets(r io.Reader) ([]string, error) {
+	var targets []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		target, err := normalizeAnalyzePathInput(line)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewCLIError(ErrorInvalidArgument, "Failed to read analyze targets from stdin", "Pipe one directory per line into 'repodoctor analyze -'", err)
+	}
+	return targets, nil
+}
+
+// runAnalyzeTargets analyzes every directory listed in r in order, ends text
+// output with a ranking of the targets, and exits with the aggregate code: 0
+// when no target has violations, otherwise the highest target exit code, and
+// at least 1 unless -fail-on or -fail-under sets the policy. A target that
+// fails to analyze counts with its own exit code, such as 3 for a missing
+// directory, and the remaining targets still run. Every array element has
+// the same JSON
+// format, so plain json follows output.default_json of the current
+// directory rather than of each target.
+func runAnalyzeTargets(r io.Reader, req *AnalyzeRequest) error {
+	targets, err := readAnalyzeTargets(r)
+	if err != nil {
+		return err
+	}
+
+	format := resolveJSONFormat(OutputFormat(req.Format), loadConfiguration(".", false))
+	asArray := format == FormatJSONLegacy || format == FormatJSONV1
+	writeLegacyJSONNotice(os.Stderr, format, req.Options.NoNotices)
+	service := NewAnalysisService()
+	exitCode := 0
+	var reports []*StructuralReport
+	for _, target := range targets {
+		request := *req
+		request.Path, request.Quiet = target, asArray
+		report, code := service.analyze(request)
+		if report != nil && report.HasViolations && report.Metrics.Exit.FailUnder == 0 && report.Metrics.Exit.FailOn == "" {
+			code = max(code, exitCodeViolations)
+		}
+		exitCode = max(exitCode, code)
+		if report != nil {
+			reports = append(reports, report)
+		}
+	}
+
+	if asArray {
+		reporter := NewReporter(format)
+		reporter.basePath = req.Report.BasePath
+		reporter.absPaths = req.Options.AbsPaths
+		if reporter.basePath == "" && !req.Options.AbsPaths {
+			// Relative to its own target every element's path would read
+			// ".", so the array is relative to the working directory
+			reporter.basePath, _ = os.Getwd()
+		}
+		fmt.Println(formatReportArray(reporter, reports))
+	} else {
+		fmt.Print(formatTargetRollup(reports, req.Report.BasePath))
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
+// formatReportArray formats the reports as one JSON array, each element in
+// the reporter's JSON format
+func formatReportArray(reporter *Reporter, reports []*StructuralReport) string {
+	elements := make([]json.RawMessage, len(reports))
+	for i, report := range reports {
+		elements[i] = json.RawMessage(reporter.Format(report))
+	}
+	data, err := json.MarshalIndent(elements, "", "  ")
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
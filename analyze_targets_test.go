@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComposeAnalyzeRequest_DashReadsTargetsFromStdin(t *testing.T) {
+	req, err := parseAnalyzeFlags([]string{"-format", "json", "-"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Mode != analyzeStdinTargets {
+		t.Fatal("expected '-' to read analyze targets from stdin")
+	}
+
+	req, err = parseAnalyzeFlags([]string{"-path", "-"})
+	if err != nil || req.Mode != analyzeStdinTargets {
+		t.Fatalf("expected -path - to read targets from stdin, got %+v, %v", req, err)
+	}
+
+	for _, args := range [][]string{{"-format", "env", "-"}, {"-format", "sarif", "-"}, {"-watch", "-"}, {"-graph-only", "-"}} {
+		if _, err := parseAnalyzeFlags(args); err == nil {
+			t.Errorf("expected %v to be rejected with stdin targets", args)
+		}
+	}
+}
+
+func TestReadAnalyzeTargets_SkipsBlankLines(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	targets, err := readAnalyzeTargets(strings.NewReader(a + "\n\n  " + b + "  \r\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{a, b}
+	for i := range want {
+		want[i], _ = normalizeAnalyzePathInput(want[i])
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Fatalf("expected targets %v, got %v", want, targets)
+	}
+}
+
+func TestAnalysisService_QuietReturnsReportWithoutPrinting(t *testing.T) {
+	out := captureStdout(t, func() {
+		analyzeFixture(t, map[string]string{"main.go": "package main\n\nfunc main() {}\n"}, AnalyzeOptions{NoLargest: true})
+	})
+	if strings.TrimSpace(out) != "" {
+		t.Fatalf("expected a quiet run to print nothing, got %q", out)
+	}
+}
+
+func TestFormatReportArray_OneElementPerReport(t *testing.T) {
+	reports := []*StructuralReport{
+		{Version: "0.5.0-dev", Path: filepath.FromSlash("/repos/a"), Score: &StructuralScore{TotalScore: 100, MaxScore: 100}},
+		{Version: "0.5.0-dev", Path: filepath.FromSlash("/repos/b"), Score: &StructuralScore{TotalScore: 97, MaxScore: 100}, HasViolations: true,
+			Size: []SizeViolation{{File: "b.go", Lines: 600, Threshold: 500}}},
+	}
+
+	var payload []struct {
+		Score struct {
+			Total float64 `json:"total"`
+		} `json:"score"`
+		SizeViolations []SizeViolation `json:"sizeViolations"`
+	}
+	for _, format := range []OutputFormat{FormatJSON, FormatJSONV1} {
+		if err := json.Unmarshal([]byte(formatReportArray(NewReporter(format), reports)), &payload); err != nil {
+			t.Fatalf("%s: expected a JSON array: %v", format, err)
+		}
+		if len(payload) != 2 || payload[0].Score.Total != 100 || payload[1].Score.Total != 97 || len(payload[1].SizeViolations) != 1 {
+			t.Fatalf("%s: unexpected array %+v", format, payload)
+		}
+	}
+}
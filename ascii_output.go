@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// asciiReplacer maps the box drawing, arrows and emoji of the text output
+// to plain ASCII. Box drawing maps to single characters, so boxes stay
+// aligned.
+var asciiReplacer = strings.NewReplacer(
+	"┌", "+", "┐", "+", "└", "+", "┘", "+", "├", "+",
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+",
+	"─", "-", "═", "=", "│", "|", "║", "|",
+	"█", "#", "░", ".",
+	"→", "->", "…", "...", "•", "-",
+	"✓", "[OK]", "⚠", "[WARN]", "✗", "[FAIL]", "✨", "*",
+	"📂 ", "", "📄 ", "", "📊 ", "", "📈 ", "", "📦 ", "", "📥 ", "", "🧱 ", "", "💡 ", "",
+)
+
+// toASCII replaces the non-ASCII glyphs of text output with their ASCII
+// equivalents. Everything else, including the order of lines, is kept.
+func toASCII(text string) string {
+	return asciiReplacer.Replace(text)
+}
+
+// asciiWriter writes through toASCII, for commands that print as they go.
+// Each write must hold whole characters, as a single fmt.Fprintf does.
+type asciiWriter struct {
+	w io.Writer
+}
+
+func (a asciiWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(a.w, toASCII(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// outputWriter returns w, or w wrapped to print plain ASCII
+func outputWriter(w io.Writer, ascii bool) io.Writer {
+	if ascii {
+		return asciiWriter{w: w}
+	}
+	return w
+}
+
+// utf8Locale reports whether the locale of the environment can display
+// UTF-8. The first of LC_ALL, LC_CTYPE and LANG that is set decides, as in
+// POSIX. Without any of them the encoding is unknown and assumed to be
+// UTF-8, as is Windows, whose consoles do not use these variables.
+func utf8Locale(getenv func(string) string, goos string) bool {
+	if goos == "windows" {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// asciiOutput reports whether text output should be plain ASCII: when
+// -ascii is set or the locale cannot display UTF-8
+func asciiOutput(flag bool) bool {
+	return flag || !utf8Locale(os.Getenv, runtime.GOOS)
+}
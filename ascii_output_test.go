@@ -0,0 +1,91 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// nonASCII returns the non-ASCII characters of text
+func nonASCII(text string) string {
+	var found []rune
+	for _, r := range text {
+		if r > unicode.MaxASCII && !strings.ContainsRune(string(found), r) {
+			found = append(found, r)
+		}
+	}
+	return string(found)
+}
+
+func TestUTF8Locale(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		goos string
+		want bool
+	}{
+		{env: map[string]string{}, goos: "linux", want: true},
+		{env: map[string]string{"LANG": "en_US.UTF-8"}, goos: "linux", want: true},
+		{env: map[string]string{"LANG": "de_DE.utf8"}, goos: "linux", want: true},
+		{env: map[string]string{"LANG": "C"}, goos: "linux", want: false},
+		{env: map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "POSIX"}, goos: "linux", want: false},
+		{env: map[string]string{"LANG": "C", "LC_CTYPE": "en_US.UTF-8"}, goos: "darwin", want: true},
+		{env: map[string]string{"LANG": "en_US.ISO-8859-1"}, goos: "linux", want: false},
+		{env: map[string]string{"LANG": "C"}, goos: "windows", want: true},
+	}
+	for _, tc := range tests {
+		getenv := func(name string) string { return tc.env[name] }
+		if got := utf8Locale(getenv, tc.goos); got != tc.want {
+			t.Errorf("%v on %s: expected %v, got %v", tc.env, tc.goos, tc.want, got)
+		}
+	}
+}
+
+func TestDeterminism_ASCIITextKeepsContentAndOrder(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, determinismFixture())
+	run := func(format OutputFormat, ascii bool) string {
+		return captureStdout(t, func() {
+			NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(format), Options: AnalyzeOptions{Deterministic: true}, Report: ReportOptions{ASCII: ascii, Explain: true, Width: 100}})
+		})
+	}
+
+	for _, format := range []OutputFormat{FormatText, FormatTree} {
+		unicodeOut, asciiOut := run(format, false), run(format, true)
+		if found := nonASCII(asciiOut); found != "" {
+			t.Errorf("-format %s: expected plain ASCII, found %q in:\n%s", format, found, asciiOut)
+		}
+		if want := toASCII(unicodeOut); asciiOut != want {
+			t.Errorf("-format %s: expected the same content and order as the unicode output\nwant:\n%s\ngot:\n%s", format, want, asciiOut)
+		}
+		if again := run(format, true); again != asciiOut {
+			t.Errorf("-format %s: expected repeated ASCII runs to be byte identical", format)
+		}
+	}
+}
+
+func TestRunExtract_ASCII(t *testing.T) {
+	root := t.TempDir()
+	writeServiceFixture(t, root, map[string]string{"a.go": "package a\n\nimport _ \"example.com/a/b\"\n", "b/b.go": "package b\n"})
+
+	out := captureStdout(t, func() {
+		if err := runExtract(root, "example.com/a", true, false, true); err != nil {
+			t.Errorf("extract failed: %v", err)
+		}
+	})
+	if found := nonASCII(out); found != "" {
+		t.Fatalf("expected plain ASCII, found %q in:\n%s", found, out)
+	}
+	for _, want := range []string{"Import Extraction Results\n" + strings.Repeat("-", 60), "a.go (package: a)", "   - ./b", "   +- Absolute: "} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected extract output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestComposeAnalyzeRequest_ASCII(t *testing.T) {
+	req, err := parseAnalyzeFlags([]string{"-ascii", "."})
+	if err != nil || !req.Report.ASCII {
+		t.Fatalf("expected -ascii to reach the service request, got %+v, %v", req, err)
+	}
+}
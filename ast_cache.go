@@ -0,0 +1,90 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sync"
+)
+
+// parsedSource is a Go file as read and parsed by an astCache
+type parsedSource struct {
+	content []byte
+	fset    *token.FileSet
+	// node is nil for a malformed file, which the rules skip
+	node *ast.File
+	err  error
+}
+
+// astCache reads and parses each Go file once, so the size and god object
+// rules share a single parse. The scorer creates one per analysis and drops
+// it once the rules have checked; a rule checking on its own uses a cache
+// of its own. It is safe for concurrent use.
+type astCache struct {
+	mu      sync.Mutex
+	sources map[string]*cachedSource
+	parses  int
+}
+
+// cachedSource parses a file on first use, however many workers ask for it
+type cachedSource struct {
+	once   sync.Once
+	source parsedSource
+}
+
+// newASTCache creates an empty cache
+func newASTCache() *astCache {
+	return &astCache{sources: make(map[string]*cachedSource)}
+}
+
+// parse returns the file at filePath, reading and parsing it on first use.
+// A file that cannot be read returns its error every time; a malformed file
+// is parsed once and cached with a nil node.
+func (c *astCache) parse(filePath string) *parsedSource {
+	c.mu.Lock()
+	cached, ok := c.sources[filePath]
+	if !ok {
+		cached = &cachedSource{}
+		c.sources[filePath] = cached
+	}
+	c.mu.Unlock()
+
+	cached.once.Do(func() {
+		cached.source = readGoSource(filePath)
+		c.mu.Lock()
+		c.parses++
+		c.mu.Unlock()
+	})
+	return &cached.source
+}
+
+// parseCount returns how many files the cache has read and parsed
+func (c *astCache) parseCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.parses
+}
+
+// readGoSource reads and parses a Go file with a FileSet of its own, as
+// files are parsed concurrently
+func readGoSource(filePath string) parsedSource {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return parsedSource{err: err}
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, filePath, content, 0)
+	if err != nil {
+		node = nil // Skip malformed files
+	}
+	return parsedSource{content: content, fset: fset, node: node}
+}
+
+// orNew returns c, or a new cache when c is nil
+func (c *astCache) orNew() *astCache {
+	if c == nil {
+		return newASTCache()
+	}
+	return c
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// checkRules runs the size and god object rules over root, sharing sources
+// between them when it is not nil
+func checkRules(tb testing.TB, root string, sources *astCache) ([]SizeViolation, []GodObjectViolation) {
+	tb.Helper()
+	size, god := NewSizeRule(), NewGodObjectRule()
+	size.sources, god.sources = sources, sources
+	if err := size.Check(root); err != nil {
+		tb.Fatalf("size Check failed: %v", err)
+	}
+	if err := god.Check(root); err != nil {
+		tb.Fatalf("god object Check failed: %v", err)
+	}
+	return size.Violations(), god.Violations()
+}
+
+func TestASTCache_SharedParseKeepsViolations(t *testing.T) {
+	root := t.TempDir()
+	writeRuleFixture(t, root, 3)
+	malformed := filepath.Join(root, "pkg00", "broken.go")
+	if err := os.WriteFile(malformed, []byte("package pkg\n\nfunc Broken( {\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	separateSize, separateGod := checkRules(t, root, nil)
+	sources := newASTCache()
+	sharedSize, sharedGod := checkRules(t, root, sources)
+	if !reflect.DeepEqual(sharedSize, separateSize) || !reflect.DeepEqual(sharedGod, separateGod) {
+		t.Fatalf("Expected shared parsing to keep the violations\nsize: %+v\ngod objects: %+v", sharedSize, sharedGod)
+	}
+
+	files, err := collectRuleFiles(root, nil, TestFilesExclude)
+	if err != nil {
+		t.Fatalf("collectRuleFiles failed: %v", err)
+	}
+	if got := sources.parseCount(); got != len(files) {
+		t.Errorf("Expected each of the %d files to be parsed once, got %d parses", len(files), got)
+	}
+	if source := sources.parse(malformed); source.node != nil || source.err != nil || sources.parseCount() != len(files) {
+		t.Errorf("Expected the malformed file to be cached as skipped, got %+v", source)
+	}
+}
+
+func TestASTCache_ReadErrorIsReported(t *testing.T) {
+	source := newASTCache().parse(filepath.Join(t.TempDir(), "missing.go"))
+	if source.err == nil || source.node != nil {
+		t.Fatalf("Expected a read error for a missing file, got %+v", source)
+	}
+}
+
+func BenchmarkRuleChecks_SharedAST(b *testing.B) {
+	root := b.TempDir()
+	writeRuleFixture(b, root, 50)
+
+	b.ResetTimer()
+	parses := 0
+	for i := 0; i < b.N; i++ {
+		sources := newASTCache()
+		checkRules(b, root, sources)
+		parses += sources.parseCount()
+	}
+	b.ReportMetric(float64(parses)/float64(b.N), "parses/op")
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// FormatBadge prints a shields.io endpoint badge of the score, for READMEs
+// that show the current architecture score
+const FormatBadge OutputFormat = "badge"
+
+// shieldsBadge is the JSON document a shields.io endpoint badge reads
+type shieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// newShieldsBadge labels the score like "93.5/100", colored like the text
+// report's indicator. A report without a score gets a grey "unknown" badge.
+func newShieldsBadge(report *StructuralReport) shieldsBadge {
+	badge := shieldsBadge{SchemaVersion: 1, Label: "architecture", Message: "unknown", Color: "lightgrey"}
+	if score := report.Score; score != nil {
+		scale := scoreScale(score)
+		badge.Message = fmt.Sprintf("%.1f/%g", score.TotalScore, scale)
+		badge.Color = badgeColor(score.TotalScore, scale)
+	}
+	return badge
+}
+
+// badgeColor colors a score like the text report's indicator: brightgreen
+// from 70% of the maximum, yellow from 50%, red below
+func badgeColor(score, max float64) string {
+	switch htmlScoreTier(score, max) {
+	case "critical":
+		return "red"
+	case "warning":
+		return "yellow"
+	default:
+		return "brightgreen"
+	}
+}
+
+// formatBadge renders the shields.io endpoint JSON of the report's score
+func formatBadge(report *StructuralReport) string {
+	data, _ := json.MarshalIndent(newShieldsBadge(report), "", "  ")
+	return string(data) + "\n"
+}
+
+// badgePath returns the location of badge.json for a repository
+func badgePath(baseDir string) string {
+	return filepath.Join(baseDir, ".repodoctor", "badge.json")
+}
+
+// persistBadge writes badge.json when persist_badge is set, so a CI job can
+// publish it. Like latest.json, failures only produce a warning with
+// -verbose and never fail the analysis.
+func persistBadge(absPath string, report *StructuralReport, cfg *Config, request AnalyzeRequest) {
+	if cfg == nil || !cfg.PersistBadge {
+		return
+	}
+	if err := writeStateFile(badgePath(absPath), []byte(formatBadge(report)), "badge"); err != nil && request.Verbose {
+		fmt.Printf("%s", ColorWarn(fmt.Sprintf("Warning: could not save badge: %v\n", err)))
+	}
+}
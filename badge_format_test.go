@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestBadgeColor_TierBoundaries(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{100, "brightgreen"},
+		{70, "brightgreen"},
+		{69.9, "yellow"},
+		{50, "yellow"},
+		{49.9, "red"},
+		{0, "red"},
+	}
+	for _, tt := range tests {
+		if got := badgeColor(tt.score, 100); got != tt.want {
+			t.Errorf("badgeColor(%v, 100) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+	// Tiers are percentages of the maximum score
+	if got := badgeColor(7, 10); got != "brightgreen" {
+		t.Errorf("expected 7/10 to be brightgreen, got %q", got)
+	}
+}
+
+func TestFormatBadge_ShieldsEndpointJSON(t *testing.T) {
+	out := NewReporter(FormatBadge).Format(&StructuralReport{Score: &StructuralScore{TotalScore: 93.5, MaxScore: 100}})
+
+	var badge map[string]any
+	if err := json.Unmarshal([]byte(out), &badge); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", out, err)
+	}
+	want := map[string]any{"schemaVersion": 1.0, "label": "architecture", "message": "93.5/100", "color": "brightgreen"}
+	for key, value := range want {
+		if badge[key] != value {
+			t.Errorf("expected %s %v, got %v", key, value, badge[key])
+		}
+	}
+
+	if unknown := newShieldsBadge(&StructuralReport{}); unknown.Message != "unknown" || unknown.Color != "lightgrey" {
+		t.Errorf("expected a grey unknown badge without a score, got %+v", unknown)
+	}
+}
+
+func TestPersistBadge_WrittenOnlyWhenEnabled(t *testing.T) {
+	report := &StructuralReport{Score: &StructuralScore{TotalScore: 50, MaxScore: 100}}
+
+	baseDir := t.TempDir()
+	persistBadge(baseDir, report, &Config{}, AnalyzeRequest{})
+	if _, err := os.Stat(badgePath(baseDir)); !os.IsNotExist(err) {
+		t.Fatalf("expected no badge.json unless persist_badge is set, got %v", err)
+	}
+
+	persistBadge(baseDir, report, &Config{PersistBadge: true}, AnalyzeRequest{})
+	data, err := os.ReadFile(badgePath(baseDir))
+	if err != nil {
+		t.Fatalf("expected badge.json: %v", err)
+	}
+	if string(data) != formatBadge(report) {
+		t.Errorf("expected badge.json to hold the badge output, got %s", data)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// BisectStep is the analysis of one commit of the bisected range. A commit
+// regressed when its score is below the threshold or it has critical
+// violations the good commit does not have.
+type BisectStep struct {
+	Commit      string              `json:"commit"`
+	Subject     string              `json:"subject"`
+	Score       float64             `json:"score"`
+	NewCritical []ComparedViolation `json:"newCritical"`
+	Regressed   bool                `json:"regressed"`
+}
+
+// BisectResult is the outcome of the bisect command. Steps lists the
+// commits analyzed, in the order they were analyzed; FirstBad is nil when
+// the bad commit did not regress.
+type BisectResult struct {
+	Good      string       `json:"good"`
+	Bad       string       `json:"bad"`
+	GoodScore float64      `json:"goodScore"`
+	Threshold float64      `json:"threshold"`
+	Commits   int          `json:"commits"`
+	Steps     []BisectStep `json:"steps"`
+	FirstBad  *BisectStep  `json:"firstBad"`
+}
+
+// runBisect finds the first commit after good, up to bad, that regressed.
+// Each commit is analyzed in a temporary worktree, at the same
+// subdirectory repoPath has in its repository. The threshold defaults to
+// the good commit's score. Like git bisect, the search assumes a commit
+// stays regressed once a regression was introduced, so it analyzes about
+// log2(n) commits.
+func runBisect(repoPath, good, bad string, threshold *float64) (*BisectResult, error) {
+	repo := gitRepository{dir: repoPath}
+	prefix, err := repo.run("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	goodCommit, err := repo.resolveCommit(good)
+	if err != nil {
+		return nil, err
+	}
+	badCommit, err := repo.resolveCommit(bad)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.firstParentRange(goodCommit, badCommit)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("%s is not a descendant of %s", bad, good)
+	}
+
+	goodReport, err := analyzeCommit(repo, goodCommit, prefix)
+	if err != nil {
+		return nil, err
+	}
+	result := &BisectResult{Good: goodCommit, Bad: badCommit, GoodScore: reportTotalScore(goodReport), Commits: len(commits)}
+	result.Threshold = result.GoodScore
+	if threshold != nil {
+		result.Threshold = *threshold
+	}
+
+	evaluate := func(commit string) (BisectStep, error) {
+		report, err := analyzeCommit(repo, commit, prefix)
+		if err != nil {
+			return BisectStep{}, err
+		}
+		step := BisectStep{Commit: commit, Subject: repo.subject(commit), Score: reportTotalScore(report), NewCritical: newCriticalViolations(goodReport, report)}
+		step.Regressed = step.Score < result.Threshold || len(step.NewCritical) > 0
+		result.Steps = append(result.Steps, step)
+		return step, nil
+	}
+
+	firstBad, err := evaluate(badCommit)
+	if err != nil || !firstBad.Regressed {
+		return result, err
+	}
+	// commits[hi] regressed; the first regressed commit is in commits[lo:hi+1]
+	lo, hi := 0, len(commits)-1
+	for lo < hi {
+		mid := (lo + hi) / 2
+		step, err := evaluate(commits[mid])
+		if err != nil {
+			return nil, err
+		}
+		if step.Regressed {
+			hi, firstBad = mid, step
+		} else {
+			lo = mid + 1
+		}
+	}
+	result.FirstBad = &firstBad
+	return result, nil
+}
+
+// analyzeCommit runs the rules on one commit, with the configuration of
+// that commit, and returns the report with paths relative to the analyzed
+// directory so reports of different worktrees compare
+func analyzeCommit(repo gitRepository, commit, prefix string) (*StructuralReport, error) {
+	dir, cleanup, err := repo.addWorktree(commit)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	absPath := filepath.Join(dir, filepath.FromSlash(prefix))
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+	result, err := runAdapterPipeline(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing %s: %w", commit, err)
+	}
+	cfg := loadConfiguration(absPath, false)
+	summary := runInternalRulePipeline(absPath, buildDependencyGraphFromModel(result.Graph, false), cfg, nil, nil)
+	report := buildReportFromRuleViolations(absPath, version, cfg, summary.result.Violations)
+	return relativizeReport(report, absPath), nil
+}
+
+// newCriticalViolations returns the critical violations of head that base
+// does not have, matched the way diff matches violations
+func newCriticalViolations(base, head *StructuralReport) []ComparedViolation {
+	critical := &StructuralReport{}
+	for _, v := range head.Circular {
+		if v.Severity == model.SeverityCritical {
+			critical.Circular = append(critical.Circular, v)
+		}
+	}
+	return CompareReports(base, critical).Added
+}
+
+// formatBisectText renders the bisect result for people
+func formatBisectText(result *BisectResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Bisect: %d commits after %s (score %.1f), threshold %.1f\n",
+		result.Commits, shortCommit(result.Good), result.GoodScore, result.Threshold))
+	for _, step := range result.Steps {
+		state := "ok"
+		if step.Regressed {
+			state = "regressed"
+		}
+		sb.WriteString(fmt.Sprintf("  %s  %5.1f  %-9s  %s\n", shortCommit(step.Commit), step.Score, state, step.Subject))
+	}
+
+	if result.FirstBad == nil {
+		sb.WriteString(fmt.Sprintf("No regression: %s scores at least %.1f and adds no critical violations.\n", shortCommit(result.Bad), result.Threshold))
+		return sb.String()
+	}
+	first := result.FirstBad
+	sb.WriteString(fmt.Sprintf("First bad commit: %s %s\n", first.Commit, first.Subject))
+	sb.WriteString(fmt.Sprintf("  Score %.1f (threshold %.1f)\n", first.Score, result.Threshold))
+	for _, v := range first.NewCritical {
+		sb.WriteString(fmt.Sprintf("  New critical violation: %s\n", v.Description))
+	}
+	return sb.String()
+}
+
+func shortCommit(commit string) string {
+	return commit[:min(len(commit), 7)]
+}
+
+// handleBisectCommand finds the commit that introduced a score regression or
+// a critical violation between a good and a bad revision
+func handleBisectCommand(args []string) error {
+	bisectCmd := flag.NewFlagSet("bisect", flag.ExitOnError)
+	path := bisectCmd.String("path", ".", "Path inside the git repository to analyze")
+	good := bisectCmd.String("good", "", "Revision known to be good")
+	bad := bisectCmd.String("bad", "", "Revision known to be bad")
+	threshold := bisectCmd.Float64("threshold", -1, "Score below which a commit is bad (default: the good commit's score)")
+	format := bisectCmd.String("format", "text", "Output format (text, json)")
+	bisectCmd.Parse(args)
+
+	if *good == "" || *bad == "" {
+		return NewCLIError(ErrorCLIUsage, "bisect requires -good and -bad", "Example: repodoctor bisect -good v1.2.0 -bad HEAD", nil)
+	}
+	if *format != "text" && *format != "json" {
+		return NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid format: %s", *format), "Valid formats: text, json", nil)
+	}
+	var minScore *float64
+	if *threshold >= 0 {
+		minScore = threshold
+	}
+
+	result, err := runBisect(validatePath(*path), *good, *bad, minScore)
+	if err != nil {
+		return WrapError(err, ErrorAnalysis, "Bisect failed", "Check that both revisions exist and -bad descends from -good")
+	}
+	if *format == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return WrapError(err, ErrorRuntime, "Failed to encode bisect result", "")
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(formatBisectText(result))
+	return nil
+}
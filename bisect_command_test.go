@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+// bisectFixture creates a git repository whose commits each add one Go file.
+// The third commit adds a file over the size threshold.
+func bisectFixture(t *testing.T) (string, []string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module fixture\n\ngo 1.24\n"), 0o644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	small := "package fixture\n\nfunc F() {}\n"
+	commits := []struct{ file, content string }{
+		{"main.go", small},
+		{"a.go", small},
+		{"big.go", "package fixture\n\n" + strings.Repeat("var _ = 0\n", 600)},
+		{"c.go", small},
+		{"d.go", small},
+	}
+	var hashes []string
+	for _, c := range commits {
+		if err := os.WriteFile(filepath.Join(dir, c.file), []byte(c.content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", c.file, err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", "add "+c.file)
+		hashes = append(hashes, git("rev-parse", "HEAD"))
+	}
+	return dir, hashes
+}
+
+func TestRunBisect_FindsCommitThatDroppedTheScore(t *testing.T) {
+	dir, hashes := bisectFixture(t)
+
+	result, err := runBisect(dir, hashes[0], hashes[4], nil)
+	if err != nil {
+		t.Fatalf("bisect failed: %v", err)
+	}
+	if result.Commits != 4 || result.GoodScore != 100 {
+		t.Fatalf("expected 4 commits after a good score of 100, got %d and %.1f", result.Commits, result.GoodScore)
+	}
+	if result.FirstBad == nil || result.FirstBad.Commit != hashes[2] {
+		t.Fatalf("expected first bad commit %s, got %+v", hashes[2], result.FirstBad)
+	}
+	if result.FirstBad.Subject != "add big.go" || result.FirstBad.Score >= result.Threshold {
+		t.Fatalf("unexpected first bad step: %+v", result.FirstBad)
+	}
+	if len(result.Steps) >= 4 {
+		t.Fatalf("expected bisect to skip some commits, analyzed %d", len(result.Steps))
+	}
+
+	out, err := exec.Command("git", "-C", dir, "worktree", "list").Output()
+	if err != nil || strings.Count(string(out), "\n") != 1 {
+		t.Fatalf("expected temporary worktrees to be removed, got:\n%s", out)
+	}
+}
+
+func TestRunBisect_NoRegressionBelowThreshold(t *testing.T) {
+	dir, hashes := bisectFixture(t)
+
+	threshold := 50.0
+	result, err := runBisect(dir, hashes[0], hashes[4], &threshold)
+	if err != nil {
+		t.Fatalf("bisect failed: %v", err)
+	}
+	if result.FirstBad != nil || len(result.Steps) != 1 {
+		t.Fatalf("expected only the bad commit to be analyzed and pass, got %+v", result)
+	}
+	if !strings.Contains(formatBisectText(result), "No regression") {
+		t.Fatalf("unexpected text:\n%s", formatBisectText(result))
+	}
+
+	if _, err := runBisect(dir, hashes[4], hashes[0], nil); err == nil {
+		t.Fatal("expected an error when -bad does not descend from -good")
+	}
+}
+
+func TestNewCriticalViolations_IgnoresExistingAndNonCritical(t *testing.T) {
+	base := &StructuralReport{Circular: []CycleViolation{{Path: []string{"main.go"}, Severity: model.SeverityCritical}}}
+	head := &StructuralReport{Circular: []CycleViolation{
+		{Path: []string{"main.go"}, Severity: model.SeverityCritical},
+		{Path: []string{"cmd.go"}, Severity: model.SeverityCritical},
+		{Path: []string{"pkg/a.go"}, Severity: model.SeverityError},
+	}}
+
+	added := newCriticalViolations(base, head)
+	if len(added) != 1 || !strings.Contains(added[0].Description, "cmd.go") {
+		t.Fatalf("expected only the new critical cycle, got %+v", added)
+	}
+}
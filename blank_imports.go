@@ -0,0 +1,96 @@
+package main
+
+import (
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
+)
+
+// BlankImportGraph is an optional Graph extension for graphs that know which
+// edges come from blank imports (import _ "pkg"). Blank edges are ordinary
+// edges; the extension only lets violations explain them.
+type BlankImportGraph interface {
+	IsBlankImport(from, to string) bool
+}
+
+// blankImportGraph adds blank import tracking to a DependencyGraph
+type blankImportGraph struct {
+	*DependencyGraph
+	blank map[string]map[string]bool
+}
+
+func (g *blankImportGraph) IsBlankImport(from, to string) bool {
+	return g.blank[from][to]
+}
+
+// withBlankImports returns graph extended with the blank imports recorded
+// on the nodes of languageGraph, or graph itself when there are none
+func withBlankImports(graph *DependencyGraph, languageGraph *model.DependencyGraph) Graph {
+	blank := make(map[string]map[string]bool)
+	for _, node := range languageGraph.GetNodes() {
+		for _, imp := range model.NodeBlankImports(node) {
+			if blank[node.ID] == nil {
+				blank[node.ID] = make(map[string]bool)
+			}
+			blank[node.ID][imp] = true
+		}
+	}
+	if len(blank) == 0 {
+		return graph
+	}
+	return &blankImportGraph{DependencyGraph: graph, blank: blank}
+}
+
+// blankImportsOf returns the dependencies of node that graph records as
+// blank imports
+func blankImportsOf(graph Graph, node string, deps []string) []string {
+	blankGraph, ok := graph.(BlankImportGraph)
+	if !ok {
+		return nil
+	}
+	var blank []string
+	for _, dep := range deps {
+		if blankGraph.IsBlankImport(node, dep) {
+			blank = append(blank, dep)
+		}
+	}
+	return blank
+}
+
+// annotateBlankImportCycles records on every cycle the nodes whose import
+// of the next node is a blank import
+func annotateBlankImportCycles(cycles []CycleViolation, graph Graph) {
+	blankGraph, ok := graph.(BlankImportGraph)
+	if !ok {
+		return
+	}
+	for i := range cycles {
+		path := cycles[i].Path
+		for j, from := range path {
+			if blankGraph.IsBlankImport(from, path[(j+1)%len(path)]) {
+				cycles[i].BlankImportsFrom = append(cycles[i].BlankImportsFrom, from)
+			}
+		}
+	}
+}
+
+// cycleBlankImportNote explains a cycle closed by blank imports, naming each
+// blank import as "from → to" with paths mapped by rel, if set. It returns ""
+// when none of the cycle's edges is a blank import.
+func cycleBlankImportNote(v CycleViolation, rel func(string) string) string {
+	if rel == nil {
+		rel = func(path string) string { return path }
+	}
+	var edges []string
+	for _, from := range v.BlankImportsFrom {
+		for j, node := range v.Path {
+			if node == from {
+				edges = append(edges, rel(from)+" → "+rel(v.Path[(j+1)%len(v.Path)]))
+				break
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return ""
+	}
+	return rules.BlankImportNote(edges...)
+}
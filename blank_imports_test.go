@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBlankImportCycle_CalledOutInEveryFormat(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddEdge("/repo/app/app.go", "/repo/plugins/plugins.go")
+	graph.AddEdge("/repo/plugins/plugins.go", "/repo/app/app.go")
+	blankGraph := &blankImportGraph{
+		DependencyGraph: graph,
+		blank:           map[string]map[string]bool{"/repo/app/app.go": {"/repo/plugins/plugins.go": true}},
+	}
+
+	report := &StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 90, MaxScore: 100}, Circular: []CycleViolation{{Path: []string{"/repo/app/app.go", "/repo/plugins/plugins.go"}}}}
+	annotateBlankImportCycles(report.Circular, blankGraph)
+	if got := report.Circular[0].BlankImportsFrom; len(got) != 1 || got[0] != "/repo/app/app.go" {
+		t.Fatalf("expected app.go's import to be the blank one, got %v", got)
+	}
+
+	if text := NewReporter(FormatText).Format(report); !strings.Contains(text, "via blank import app/app.go → plugins/plugins.go") {
+		t.Fatalf("expected the text report to call out the blank import:\n%s", text)
+	}
+	if sarif := NewReporter(FormatSARIF).Format(report); !strings.Contains(sarif, "via blank import app/app.go → plugins/plugins.go") {
+		t.Fatalf("expected the SARIF message to call out the blank import:\n%s", sarif)
+	}
+
+	var payload struct {
+		Circular []struct {
+			BlankImportsFrom []string `json:"BlankImportsFrom"`
+		} `json:"circularViolations"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSON).Format(report)), &payload); err != nil {
+		t.Fatalf("invalid JSON report: %v", err)
+	}
+	if len(payload.Circular) != 1 || len(payload.Circular[0].BlankImportsFrom) != 1 {
+		t.Fatalf("expected the JSON cycle to list its blank import, got %+v", payload)
+	}
+}
+
+func TestBlankImportCycle_NoNoteWithoutBlankImports(t *testing.T) {
+	report := &StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 90, MaxScore: 100}, Circular: []CycleViolation{{Path: []string{"/repo/a.go", "/repo/b.go"}}}}
+	annotateBlankImportCycles(report.Circular, NewDependencyGraph())
+	if text := NewReporter(FormatText).Format(report); strings.Contains(text, "blank import") {
+		t.Fatalf("expected no blank import note:\n%s", text)
+	}
+}
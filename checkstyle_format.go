@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/xml"
+	"sort"
+
+	"RepoDoctor/internal/model"
+)
+
+// FormatCheckstyle prints the report as checkstyle XML, for lint
+// aggregators such as reviewdog
+const FormatCheckstyle OutputFormat = "checkstyle"
+
+// checkstyleVersion is the checkstyle report version the output follows
+const checkstyleVersion = "4.3"
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleSeverity maps a severity to a checkstyle severity
+func checkstyleSeverity(severity model.Severity) string {
+	switch {
+	case severity >= model.SeverityError:
+		return "error"
+	case severity == model.SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// formatCheckstyle renders one file element per file with circular, layer,
+// size or god object violations and one error per violation, with the
+// severities SARIF uses. A cycle is reported on the first file of its
+// path. Files are sorted by path and errors by line, source and message;
+// paths are relative to the analyzed directory and a violation without a
+// line has line 0.
+func formatCheckstyle(report *StructuralReport) string {
+	relative := func(file string) string {
+		return relativeToBase(file, report.Path)
+	}
+	errors := make(map[string][]checkstyleError)
+	add := func(file, ruleID string, severity model.Severity, message string, line int) {
+		name := relative(file)
+		errors[name] = append(errors[name], checkstyleError{Line: line, Severity: checkstyleSeverity(severity), Message: message, Source: junitClassName(ruleID)})
+	}
+
+	for _, v := range report.Circular {
+		cycle := make([]string, len(v.Path))
+		for i, file := range v.Path {
+			cycle[i] = relative(file)
+		}
+		message := "Circular dependency: " + formatCyclePath(cycle)
+		if note := cycleBlankImportNote(v, relative); note != "" {
+			message += " (" + note + ")"
+		}
+		add(firstOrEmpty(v.Path), "rule.circular-dependency", v.Severity, message, 0)
+	}
+	for _, v := range report.Layer {
+		ruleID := v.RuleID
+		if ruleID == "" {
+			ruleID = "rule.layer-validation"
+		}
+		add(v.From, ruleID, model.SeverityError, v.Message, 0)
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		add(v.From, "rule.feature-isolation", model.SeverityError, v.Message, 0)
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		add(v.Manifest, "rule.dependency-cap", model.SeverityError, v.Message, 0)
+	}
+	for _, v := range report.Size {
+		add(v.File, "rule.size", model.SeverityWarning, sizeViolationMessage(v), v.Line)
+	}
+	for _, v := range report.GodObject {
+		add(v.File, "rule.god-object", model.SeverityWarning, godObjectViolationMessage(v), v.Line)
+	}
+
+	doc := checkstyleReport{Version: checkstyleVersion}
+	for _, name := range sortedKeys(errors) {
+		fileErrors := errors[name]
+		sort.SliceStable(fileErrors, func(i, j int) bool {
+			if fileErrors[i].Line != fileErrors[j].Line {
+				return fileErrors[i].Line < fileErrors[j].Line
+			}
+			if fileErrors[i].Source != fileErrors[j].Source {
+				return fileErrors[i].Source < fileErrors[j].Source
+			}
+			return fileErrors[i].Message < fileErrors[j].Message
+		})
+		doc.Files = append(doc.Files, checkstyleFile{Name: name, Errors: fileErrors})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return xml.Header
+	}
+	return xml.Header + string(data) + "\n"
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func TestFormatCheckstyle_OneErrorPerViolation(t *testing.T) {
+	report := &StructuralReport{
+		Path:     "/repo",
+		Circular: []CycleViolation{{Path: []string{"/repo/b/b.go", "/repo/a/a.go"}, Severity: model.SeverityCritical}},
+		Layer:    []LayerViolation{{From: "/repo/store/s.go", To: "/repo/api/h.go", Message: "store imports api"}},
+		Size: []SizeViolation{
+			{File: `/repo/gen/<weird> & "odd".go`, Lines: 600, Threshold: 500},
+			{File: "/repo/b/b.go", Function: "Run", Line: 40, Lines: 90, Threshold: 80},
+			{File: "/repo/b/b.go", Function: "Build", Line: 12, Lines: 85, Threshold: 80},
+		},
+		GodObject: []GodObjectViolation{{StructName: "Server", File: "/repo/a/a.go", MethodCount: 20, Line: 7}},
+	}
+
+	out := NewReporter(FormatCheckstyle).Format(report)
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Fatalf("expected an XML declaration, got %q", out)
+	}
+	if !strings.Contains(out, `name="gen/&lt;weird&gt; &amp; &#34;odd&#34;.go"`) {
+		t.Fatalf("expected special characters in file paths to be escaped:\n%s", out)
+	}
+
+	var doc checkstyleReport
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid checkstyle XML: %v", err)
+	}
+	names := make([]string, len(doc.Files))
+	for i, file := range doc.Files {
+		names[i] = file.Name
+	}
+	if strings.Join(names, ",") != `a/a.go,b/b.go,gen/<weird> & "odd".go,store/s.go` {
+		t.Fatalf("expected files sorted by path, got %v", names)
+	}
+
+	b := doc.Files[1].Errors
+	if len(b) != 3 {
+		t.Fatalf("expected the cycle and two size errors on b/b.go, got %+v", b)
+	}
+	if b[0].Line != 0 || b[0].Severity != "error" || b[0].Source != "repodoctor.circular-dependency" || b[0].Message != "Circular dependency: b/b.go → a/a.go → b/b.go" {
+		t.Fatalf("unexpected cycle error: %+v", b[0])
+	}
+	if b[1].Line != 12 || b[2].Line != 40 || b[1].Severity != "warning" || b[1].Source != "repodoctor.size" {
+		t.Fatalf("expected size errors sorted by line, got %+v", b[1:])
+	}
+	if god := doc.Files[0].Errors; len(god) != 1 || god[0].Source != "repodoctor.god-object" || god[0].Line != 7 {
+		t.Fatalf("unexpected god object errors: %+v", god)
+	}
+	if layer := doc.Files[3].Errors; len(layer) != 1 || layer[0].Message != "store imports api" || layer[0].Source != "repodoctor.layer-validation" {
+		t.Fatalf("unexpected layer errors: %+v", layer)
+	}
+}
+
+func TestFormatCheckstyle_CleanRepoHasNoFiles(t *testing.T) {
+	var doc checkstyleReport
+	if err := xml.Unmarshal([]byte(NewReporter(FormatCheckstyle).Format(&StructuralReport{Path: "/repo"})), &doc); err != nil {
+		t.Fatalf("invalid checkstyle XML: %v", err)
+	}
+	if doc.Version != checkstyleVersion || len(doc.Files) != 0 {
+		t.Fatalf("expected an empty checkstyle report, got %+v", doc)
+	}
+}
+
+func TestAnalysisService_CheckstyleKeepsStdoutClean(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, map[string]string{"go.mod": "module example.com/app\n\ngo 1.24\n", "main.go": "package main\n\nfunc main() {}\n"})
+
+	out := captureStdout(t, func() {
+		NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatCheckstyle)})
+	})
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Fatalf("expected only the checkstyle document on stdout, got:\n%s", out)
+	}
+}
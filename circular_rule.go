@@ -1,9 +1,14 @@
 package main
 
+import "RepoDoctor/internal/model"
+
 // CycleViolation represents a circular dependency violation
 type CycleViolation struct {
 	Path     []string
-	Severity string
+	Severity model.Severity
+	// BlankImportsFrom lists the nodes of the cycle that import the next
+	// node with a blank import
+	BlankImportsFrom []string `json:",omitempty"`
 }
 
 // CircularDependencyRule detects circular dependencies in a graph
@@ -26,8 +31,8 @@ func (r *CircularDependencyRule) Name() string {
 }
 
 // Severity returns the severity level of this rule
-func (r *CircularDependencyRule) Severity() string {
-	return "critical"
+func (r *CircularDependencyRule) Severity() model.Severity {
+	return model.SeverityCritical
 }
 
 // Check runs the rule and returns true if violations are found
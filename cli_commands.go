@@ -7,56 +7,6 @@ import (
 	"strings"
 )
 
-func scanDirectory(path string, verbose bool) (totalFiles, goFiles, totalLines int) {
-	filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		// Skip hidden directories and files
-		if strings.HasPrefix(info.Name(), ".") {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Skip docs directory (as per user request)
-		if info.IsDir() && info.Name() == "docs" {
-			return filepath.SkipDir
-		}
-
-		if info.IsDir() {
-			if verbose {
-				fmt.Printf("📂 Scanning: %s\n", filePath)
-			}
-			return nil
-		}
-
-		totalFiles++
-
-		// Count Go files
-		if strings.HasSuffix(info.Name(), ".go") {
-			goFiles++
-
-			// Count lines
-			data, err := os.ReadFile(filePath)
-			if err == nil {
-				lines := strings.Split(string(data), "\n")
-				totalLines += len(lines)
-
-				if verbose {
-					fmt.Printf("  📄 %s (%d lines)\n", info.Name(), len(lines))
-				}
-			}
-		}
-
-		return nil
-	})
-
-	return
-}
-
 func runReport(reportPath, format string) error {
 	// Read report file
 	data, err := os.ReadFile(reportPath)
@@ -103,7 +53,7 @@ func runHistory(repoPath string) error {
 	return nil
 }
 
-func runExtract(path, module string, verbose bool, jsonOutput bool) error {
+func runExtract(path, module string, verbose bool, jsonOutput bool, ascii bool) error {
 	// Resolve to absolute path
 	absPath, err := filepath.Abs(path)
 	if err != nil {
@@ -125,20 +75,22 @@ func runExtract(path, module string, verbose bool, jsonOutput bool) error {
 		)
 	}
 
-	fmt.Printf("RepoDoctor v%s\n", version)
-	fmt.Printf("Extracting imports from: %s\n", absPath)
-	fmt.Printf("Module path: %s\n\n", module)
+	out := outputWriter(os.Stdout, ascii)
+	fmt.Fprintf(out, "RepoDoctor v%s\n", version)
+	fmt.Fprintf(out, "Extracting imports from: %s\n", absPath)
+	fmt.Fprintf(out, "Module path: %s\n\n", module)
 
 	// Create extractor and extract imports
 	extractor := NewImportExtractor(module)
+	extractor.Excludes = configExcludes(loadConfiguration(absPath, false))
 	imports, err := extractor.ExtractFromDir(absPath)
 	if err != nil {
 		return WrapError(err, ErrorAnalysis, "Error extracting imports", GetSuggestion(err.Error()))
 	}
 
 	// Display results
-	fmt.Println("📊 Import Extraction Results")
-	fmt.Println(strings.Repeat("─", 60))
+	fmt.Fprintln(out, "📊 Import Extraction Results")
+	fmt.Fprintln(out, strings.Repeat("─", 60))
 
 	totalImports := 0
 	for filePath, metadata := range imports {
@@ -147,26 +99,26 @@ func runExtract(path, module string, verbose bool, jsonOutput bool) error {
 			relPath = filePath
 		}
 
-		fmt.Printf("\n📄 %s (package: %s)\n", relPath, metadata.Package)
+		fmt.Fprintf(out, "\n📄 %s (package: %s)\n", relPath, metadata.Package)
 		if len(metadata.Imports) > 0 {
 			for _, imp := range metadata.Imports {
-				fmt.Printf("   • %s\n", imp)
+				fmt.Fprintf(out, "   • %s\n", imp)
 				totalImports++
 			}
 		} else {
-			fmt.Printf("   (no external imports)\n")
+			fmt.Fprintf(out, "   (no external imports)\n")
 		}
 
 		if verbose {
-			fmt.Printf("   └─ Absolute: %s\n", filePath)
+			fmt.Fprintf(out, "   └─ Absolute: %s\n", filePath)
 		}
 	}
 
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("📦 Total files analyzed: %d\n", len(imports))
-	fmt.Printf("📥 Total unique imports: %d\n", totalImports)
-	fmt.Println("✨ Import extraction completed successfully")
-	fmt.Println()
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+	fmt.Fprintf(out, "📦 Total files analyzed: %d\n", len(imports))
+	fmt.Fprintf(out, "📥 Total unique imports: %d\n", totalImports)
+	fmt.Fprintln(out, "✨ Import extraction completed successfully")
+	fmt.Fprintln(out)
 
 	_ = jsonOutput
 	return nil
@@ -200,6 +152,6 @@ func runWatch(path string) {
 	if err := WatchAndAnalyze(path); err != nil {
 		cliErr := WrapError(err, ErrorRuntime, "Watch mode failed", "Check the target path and try again")
 		cliErr.Display()
-		os.Exit(1)
+		os.Exit(exitCodeFailure)
 	}
 }
@@ -16,6 +16,7 @@ const (
 	ColorBlue   = "\033[34m"
 	ColorCyan   = "\033[36m"
 	ColorWhite  = "\033[37m"
+	ColorDim    = "\033[2m"
 )
 
 // ColorFormatter provides colored output formatting
@@ -31,42 +32,45 @@ func NewColorFormatter(enabled bool) *ColorFormatter {
 }
 
 // isTerminal checks if stdout is connected to a terminal that supports ANSI colors.
+func isTerminal() bool {
+	stdoutIsTTY := false
+	if fileInfo, err := os.Stdout.Stat(); err == nil {
+		stdoutIsTTY = (fileInfo.Mode() & os.ModeCharDevice) != 0
+	}
+	return colorSupported(os.Getenv, runtime.GOOS, stdoutIsTTY)
+}
+
+// colorSupported decides whether to emit ANSI colors.
 //
 // Detection order:
 //  1. NO_COLOR env var (https://no-color.org/) — always disables color.
 //  2. TERM=dumb — indicates a terminal without color support.
-//  3. TERM_PROGRAM — set by modern terminals (vscode, iterm2, etc.).
-//  4. Windows-specific env vars (WT_SESSION, ANSICON, ConEmuANSI).
-//  5. os.Stdout.Stat() — ModeCharDevice detects real terminal vs pipe/redirect.
-func isTerminal() bool {
+//  3. stdout is a character device — a real terminal, not a pipe/redirect.
+//  4. Windows-specific env vars (WT_SESSION, ANSICON, ConEmuANSI) and
+//     TERM_PROGRAM, for Windows terminals whose stdout does not look like a
+//     character device. Elsewhere a pipe or redirect always disables color.
+func colorSupported(getenv func(string) string, goos string, stdoutIsTTY bool) bool {
 	// Respect NO_COLOR convention (https://no-color.org/)
-	if os.Getenv("NO_COLOR") != "" {
+	if getenv("NO_COLOR") != "" {
 		return false
 	}
 
 	// TERM=dumb means the terminal does not support ANSI escape sequences
-	if term := strings.ToLower(os.Getenv("TERM")); term == "dumb" {
+	if term := strings.ToLower(getenv("TERM")); term == "dumb" {
 		return false
 	}
 
-	// TERM_PROGRAM is set by modern terminal emulators (VSCode, iTerm2, Hyper, etc.)
-	if os.Getenv("TERM_PROGRAM") != "" {
+	if stdoutIsTTY {
 		return true
 	}
 
 	// Windows-specific terminal detection
-	if runtime.GOOS == "windows" {
+	if goos == "windows" {
 		// WT_SESSION: Windows Terminal
 		// ANSICON: ANSICON wrapper for older cmd.exe
 		// ConEmuANSI: ConEmu/Cmder terminal
-		if os.Getenv("WT_SESSION") != "" || os.Getenv("ANSICON") != "" || os.Getenv("ConEmuANSI") == "ON" {
-			return true
-		}
-	}
-
-	// Fallback: check if stdout is a character device (real terminal, not pipe/redirect)
-	if fileInfo, err := os.Stdout.Stat(); err == nil {
-		return (fileInfo.Mode() & os.ModeCharDevice) != 0
+		// TERM_PROGRAM: mintty (Git Bash) and other emulators running over pipes
+		return getenv("WT_SESSION") != "" || getenv("ANSICON") != "" || getenv("ConEmuANSI") == "ON" || getenv("TERM_PROGRAM") != ""
 	}
 
 	return false
@@ -100,6 +104,11 @@ func (f *ColorFormatter) Success(message string) string {
 	return f.Color(message, ColorGreen)
 }
 
+// Dim formats low-priority text in a faint style
+func (f *ColorFormatter) Dim(text string) string {
+	return f.Color(text, ColorDim)
+}
+
 // Bold makes text bold
 func (f *ColorFormatter) Bold(text string) string {
 	if !f.enabled {
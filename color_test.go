@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColorSupported(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		goos        string
+		stdoutIsTTY bool
+		want        bool
+	}{
+		{name: "terminal", goos: "linux", stdoutIsTTY: true, want: true},
+		{name: "pipe", goos: "linux", want: false},
+		{name: "NO_COLOR", env: map[string]string{"NO_COLOR": "1"}, goos: "linux", stdoutIsTTY: true, want: false},
+		{name: "dumb terminal", env: map[string]string{"TERM": "dumb"}, goos: "linux", stdoutIsTTY: true, want: false},
+		{name: "TERM_PROGRAM over a pipe", env: map[string]string{"TERM_PROGRAM": "vscode"}, goos: "darwin", want: false},
+		{name: "Windows Terminal", env: map[string]string{"WT_SESSION": "1"}, goos: "windows", want: true},
+		{name: "NO_COLOR in Windows Terminal", env: map[string]string{"WT_SESSION": "1", "NO_COLOR": "1"}, goos: "windows", want: false},
+	}
+	for _, tc := range tests {
+		getenv := func(key string) string { return tc.env[key] }
+		if got := colorSupported(getenv, tc.goos, tc.stdoutIsTTY); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestFormatColoredText_SectionColors(t *testing.T) {
+	report := &StructuralReport{
+		Path:     "/repo",
+		Score:    &StructuralScore{TotalScore: 40, MaxScore: 100, ViolationCount: 3, CircularCount: 1, LayerCount: 1, SizeCount: 1},
+		Circular: []CycleViolation{{Path: []string{"a.go", "b.go"}}},
+		Layer:    []LayerViolation{{From: "store.go", To: "api.go", Message: "store imports api"}},
+		Size:     []SizeViolation{{File: "big.go", Lines: 600, Threshold: 500}},
+	}
+	reporter := &ColoredReporter{Reporter: NewReporter(FormatText), formatter: &ColorFormatter{enabled: true}}
+	reporter.width = 80
+	out := reporter.FormatColoredText(report)
+	layout := newTextLayout(80)
+
+	for _, want := range []string{
+		ColorRed + "✗" + ColorReset + " Score:",
+		ColorRed + layout.boxTitle("CIRCULAR DEPENDENCIES [CRITICAL]"),
+		ColorYellow + "[1] store imports api",
+		ColorDim + layout.boxTitle("SIZE VIOLATIONS [LOW]"),
+		ColorDim + "[1] ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in colored output:\n%q", want, out)
+		}
+	}
+
+	if plain := NewColoredReporter(FormatText, false).FormatColoredText(report); strings.Contains(plain, "\033[") {
+		t.Errorf("expected no escape codes with color disabled:\n%q", plain)
+	}
+}
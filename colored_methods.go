@@ -6,50 +6,45 @@ import (
 )
 
 // writeHeaderWithColor writes the report header with colors
-func writeHeaderWithColor(sb *strings.Builder, formatter *ColorFormatter) {
-	header := "╔═══════════════════════════════════════════════════════════╗"
-	title := "║          RepoDoctor Structural Analysis Report           ║"
-	footer := "╚═══════════════════════════════════════════════════════════╝"
-
-	sb.WriteString(formatter.Color(header, ColorCyan))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color(title, ColorCyan))
+func writeHeaderWithColor(sb *strings.Builder, formatter *ColorFormatter, layout *textLayout) {
+	for _, line := range layout.headerLines() {
+		sb.WriteString(formatter.Color(line, ColorCyan))
+		sb.WriteString("\n")
+	}
 	sb.WriteString("\n")
-	sb.WriteString(formatter.Color(footer, ColorCyan))
-	sb.WriteString("\n\n")
 }
 
 // writeScoreSectionWithColor writes the score section with colors
-func writeScoreSectionWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter) {
+func writeScoreSectionWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
 	sb.WriteString(fmt.Sprintf("Version: %s\n", report.Version))
-	sb.WriteString(fmt.Sprintf("Path: %s\n\n", report.Path))
-
-	sb.WriteString(formatter.Color("┌───────────────────────────────────────────────────────────┐", ColorCyan))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("│  STRUCTURAL HEALTH SCORE                                  │", ColorCyan))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("└───────────────────────────────────────────────────────────┘", ColorCyan))
+	sb.WriteString(fmt.Sprintf("Path: %s\n", layout.fitPath(report.Path, len("Path: "))))
+	if report.Metrics.Sample != nil {
+		sb.WriteString(formatter.Warn(formatSampleLabel(report.Metrics.Sample)))
+	}
 	sb.WriteString("\n")
 
+	writeSectionBoxWithColor(sb, formatter, layout, "STRUCTURAL HEALTH SCORE", ColorCyan)
+
+	scale := scoreScale(report.Score)
+	percent := report.Score.TotalScore / scale * 100
 	scoreIndicator := formatter.Success("✓")
-	if report.Score.TotalScore < 70 {
+	if percent < 70 {
 		scoreIndicator = formatter.Warn("⚠")
 	}
-	if report.Score.TotalScore < 50 {
+	if percent < 50 {
 		scoreIndicator = formatter.Error("✗")
 	}
 
-	sb.WriteString(fmt.Sprintf("%s Score: %s\n\n", scoreIndicator, formatter.Bold(fmt.Sprintf("%.1f / 100.0", report.Score.TotalScore))))
+	sb.WriteString(fmt.Sprintf("%s Score: %s\n", scoreIndicator, formatter.Bold(fmt.Sprintf("%.1f / %.1f", report.Score.TotalScore, scale))))
+	if report.Metrics.Density != nil {
+		sb.WriteString(formatDensityLine(report.Metrics.Density))
+	}
+	sb.WriteString("\n")
 }
 
 // writeViolationsSummaryWithColor writes the violations summary with colors
-func writeViolationsSummaryWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter) {
-	sb.WriteString(formatter.Color("┌───────────────────────────────────────────────────────────┐", ColorCyan))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("│  VIOLATIONS SUMMARY                                       │", ColorCyan))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("└───────────────────────────────────────────────────────────┘", ColorCyan))
-	sb.WriteString("\n")
+func writeViolationsSummaryWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	writeSectionBoxWithColor(sb, formatter, layout, "VIOLATIONS SUMMARY", ColorCyan)
 
 	totalViolations := report.Score.ViolationCount
 	if totalViolations == 0 {
@@ -59,114 +54,222 @@ func writeViolationsSummaryWithColor(sb *strings.Builder, report *StructuralRepo
 		sb.WriteString(fmt.Sprintf("  - Circular Dependencies: %s\n", formatter.Error(fmt.Sprintf("%d", report.Score.CircularCount))))
 		sb.WriteString(fmt.Sprintf("  - Layer Violations: %s\n", formatter.Warn(fmt.Sprintf("%d", report.Score.LayerCount))))
 		sb.WriteString(fmt.Sprintf("  - Size Violations: %s\n", formatter.Info(fmt.Sprintf("%d", report.Score.SizeCount))))
-		sb.WriteString(fmt.Sprintf("  - God Objects: %s\n\n", formatter.Info(fmt.Sprintf("%d", report.Score.GodObjectCount))))
+		sb.WriteString(fmt.Sprintf("  - God Objects: %s\n", formatter.Info(fmt.Sprintf("%d", report.Score.GodObjectCount))))
+		if report.Score.OptIn.FeatureIsolationCount > 0 {
+			sb.WriteString(fmt.Sprintf("  - Feature Isolation Violations: %s\n", formatter.Warn(fmt.Sprintf("%d", report.Score.OptIn.FeatureIsolationCount))))
+		}
+		if report.Score.OptIn.DependencyCapCount > 0 {
+			sb.WriteString(fmt.Sprintf("  - Dependency Cap Violations: %s\n", formatter.Warn(fmt.Sprintf("%d", report.Score.OptIn.DependencyCapCount))))
+		}
+		if report.Summary.Filtered > 0 {
+			sb.WriteString(formatter.Dim(formatFilteredNote(report.Summary.Filtered)) + "\n")
+		}
+		sb.WriteString("\n")
 	}
 }
 
 // writeCircularViolationsWithColor writes circular dependency violations with colors
-func writeCircularViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter) {
+func writeCircularViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
 	if len(report.Circular) == 0 {
 		return
 	}
 
-	sb.WriteString(formatter.Color("┌───────────────────────────────────────────────────────────┐", ColorRed))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("│  CIRCULAR DEPENDENCIES [CRITICAL]                         │", ColorRed))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("└───────────────────────────────────────────────────────────┘", ColorRed))
-	sb.WriteString("\n")
+	writeSectionBoxWithColor(sb, formatter, layout, "CIRCULAR DEPENDENCIES [CRITICAL]", ColorRed)
 
 	for i, v := range report.Circular {
-		sb.WriteString(formatter.Error(fmt.Sprintf("[%d] ", i+1)))
-		sb.WriteString(formatter.Color(formatCyclePath(v.Path), ColorRed))
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(formatter.Error(prefix))
+		sb.WriteString(formatter.Color(layout.wrap(layout.fitCycle(v.Path, len(prefix)), len(prefix)), ColorRed))
 		sb.WriteString("\n")
+		if note := cycleBlankImportNote(v, nil); note != "" {
+			sb.WriteString(strings.Repeat(" ", len(prefix)) + formatter.Warn(note) + "\n")
+		}
+	}
+	if omitted := report.Summary.Omitted.Circular; omitted > 0 {
+		sb.WriteString(formatter.Dim(formatOmittedNote(omitted)) + "\n")
 	}
 	sb.WriteString("\n")
 }
 
 // writeLayerViolationsWithColor writes layer violations with colors
-func writeLayerViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter) {
+func writeLayerViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
 	if len(report.Layer) == 0 {
 		return
 	}
 
-	sb.WriteString(formatter.Color("┌───────────────────────────────────────────────────────────┐", ColorYellow))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("│  LAYER VIOLATIONS [HIGH]                                  │", ColorYellow))
+	writeSectionBoxWithColor(sb, formatter, layout, "LAYER VIOLATIONS [HIGH]", ColorYellow)
+
+	for i, v := range report.Layer {
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(formatter.Warn(prefix + layout.fitMessage(v.Message, len(prefix), v.From, v.To) + "\n"))
+	}
+	if omitted := report.Summary.Omitted.Layer; omitted > 0 {
+		sb.WriteString(formatter.Dim(formatOmittedNote(omitted)) + "\n")
+	}
 	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("└───────────────────────────────────────────────────────────┘", ColorYellow))
+}
+
+// writeFeatureIsolationViolationsWithColor writes feature isolation violations with colors
+func writeFeatureIsolationViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if len(report.OptIn.FeatureIsolation) == 0 {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "FEATURE ISOLATION VIOLATIONS [HIGH]", ColorYellow)
+
+	for i, v := range report.OptIn.FeatureIsolation {
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(formatter.Warn(prefix + layout.fitMessage(v.Message, len(prefix), v.From) + "\n"))
+	}
+	if omitted := report.Summary.Omitted.FeatureIsolation; omitted > 0 {
+		sb.WriteString(formatter.Dim(formatOmittedNote(omitted)) + "\n")
+	}
 	sb.WriteString("\n")
+}
 
-	for i, v := range report.Layer {
-		sb.WriteString(formatter.Warn(fmt.Sprintf("[%d] %s\n", i+1, v.Message)))
+// writeDependencyCapViolationsWithColor writes dependency cap violations with colors
+func writeDependencyCapViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if len(report.OptIn.DependencyCap) == 0 {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "DEPENDENCY CAP VIOLATIONS [HIGH]", ColorYellow)
+
+	for i, v := range report.OptIn.DependencyCap {
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(formatter.Warn(prefix + layout.fitMessage(v.Message, len(prefix)) + "\n"))
 	}
 	sb.WriteString("\n")
 }
 
 // writeSizeViolationsWithColor writes size violations with colors
-func writeSizeViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter) {
+func writeSizeViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
 	if len(report.Size) == 0 {
 		return
 	}
 
-	sb.WriteString(formatter.Color("┌───────────────────────────────────────────────────────────┐", ColorBlue))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("│  SIZE VIOLATIONS [LOW]                                    │", ColorBlue))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("└───────────────────────────────────────────────────────────┘", ColorBlue))
-	sb.WriteString("\n")
+	writeSectionBoxWithColor(sb, formatter, layout, "SIZE VIOLATIONS [LOW]", ColorDim)
 
 	for i, v := range report.Size {
-		if v.Function != "" {
-			sb.WriteString(formatter.Info(fmt.Sprintf("[%d] Function '%s' in %s: %d lines (threshold: %d)\n",
-				i+1, v.Function, v.File, v.Lines, v.Threshold)))
-		} else {
-			sb.WriteString(formatter.Info(fmt.Sprintf("[%d] File %s: %d lines (threshold: %d)\n",
-				i+1, v.File, v.Lines, v.Threshold)))
-		}
+		sb.WriteString(formatter.Dim(formatSizeViolationLine(i+1, v, layout) + "\n"))
+	}
+	if omitted := report.Summary.Omitted.Size; omitted > 0 {
+		sb.WriteString(formatter.Dim(formatOmittedNote(omitted)) + "\n")
 	}
 	sb.WriteString("\n")
 }
 
 // writeGodObjectViolationsWithColor writes god object violations with colors
-func writeGodObjectViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter) {
+func writeGodObjectViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
 	if len(report.GodObject) == 0 {
 		return
 	}
 
-	sb.WriteString(formatter.Color("┌───────────────────────────────────────────────────────────┐", ColorYellow))
+	writeSectionBoxWithColor(sb, formatter, layout, "GOD OBJECT VIOLATIONS [MEDIUM]", ColorYellow)
+
+	for i, v := range report.GodObject {
+		sb.WriteString(formatter.Warn(formatGodObjectViolationLine(i+1, v, layout) + "\n"))
+	}
+	if omitted := report.Summary.Omitted.GodObject; omitted > 0 {
+		sb.WriteString(formatter.Dim(formatOmittedNote(omitted)) + "\n")
+	}
 	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("│  GOD OBJECT VIOLATIONS [MEDIUM]                           │", ColorYellow))
+}
+
+// writeAdvisoryViolationsWithColor writes informational advisories with colors
+func writeAdvisoryViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if len(report.Advisory) == 0 {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "ADVISORIES [INFO]", ColorCyan)
+
+	for i, v := range report.Advisory {
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(formatter.Info(prefix + layout.fitMessage(v.Message, len(prefix), v.File) + "\n"))
+	}
 	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("└───────────────────────────────────────────────────────────┘", ColorYellow))
+}
+
+// writeSingleImplViolationsWithColor writes single-implementation interfaces with colors
+func writeSingleImplViolationsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if len(report.OptIn.SingleImpl) == 0 {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "SINGLE-IMPLEMENTATION INTERFACES [INFO]", ColorCyan)
+
+	for i, v := range report.OptIn.SingleImpl {
+		sb.WriteString(formatter.Info(fmt.Sprintf("[%d] %s is only implemented by %s", i+1, v.Interface, v.Impl)) + "\n")
+	}
 	sb.WriteString("\n")
+}
 
-	for i, v := range report.GodObject {
-		sb.WriteString(formatter.Warn(fmt.Sprintf("[%d] Struct '%s' in %s: %d fields, %d methods\n",
-			i+1, v.StructName, v.File, v.FieldCount, v.MethodCount)))
+// writeOrphanPackagesWithColor writes the packages nothing imports with colors
+func writeOrphanPackagesWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if len(report.Metrics.Packages.Orphans) == 0 {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "ORPHAN PACKAGES [INFO]", ColorCyan)
+
+	for i, pkg := range report.Metrics.Packages.Orphans {
+		sb.WriteString(formatter.Info(fmt.Sprintf("[%d] %s is not imported by any analyzed package", i+1, pkg)) + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+// writeCouplingWithColor writes the most depended-upon packages with colors
+func writeCouplingWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if len(report.Metrics.Packages.Coupling) == 0 {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "COUPLING", ColorCyan)
+
+	for i, c := range report.Metrics.Packages.Coupling {
+		sb.WriteString(formatter.Info(formatCouplingLine(i+1, c)) + "\n")
 	}
 	sb.WriteString("\n")
 }
 
 // writeScoreBreakdownWithColor writes the score breakdown with colors
-func writeScoreBreakdownWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter) {
+func writeScoreBreakdownWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
 	if !report.HasViolations {
 		sb.WriteString(formatter.Success("✨ No structural violations detected! Your architecture is clean.") + "\n\n")
 		return
 	}
 
-	sb.WriteString(formatter.Color("┌───────────────────────────────────────────────────────────┐", ColorCyan))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("│  SCORE BREAKDOWN                                          │", ColorCyan))
-	sb.WriteString("\n")
-	sb.WriteString(formatter.Color("└───────────────────────────────────────────────────────────┘", ColorCyan))
-	sb.WriteString("\n")
+	writeSectionBoxWithColor(sb, formatter, layout, "SCORE BREAKDOWN", ColorCyan)
+	if !isWeightedScore(report.Score) {
+		sb.WriteString(formatRubricBreakdown(report.Score))
+		sb.WriteString(formatter.Color("─────────────────────────────────────────────────", ColorCyan) + "\n")
+		sb.WriteString(fmt.Sprintf("Final Score:          %s\n\n", formatter.Bold(fmt.Sprintf("%.1f / %.1f", report.Score.TotalScore, report.Score.MaxScore))))
+		return
+	}
 	
-	sb.WriteString(fmt.Sprintf("Base Score:           100.0\n"))
-	sb.WriteString(fmt.Sprintf("Circular Penalty:     %s\n", formatter.Error(fmt.Sprintf("-%.1f (%d violations x 10.0)", report.Score.CircularPenalty, report.Score.CircularCount))))
-	sb.WriteString(fmt.Sprintf("Layer Penalty:        %s\n", formatter.Warn(fmt.Sprintf("-%.1f (%d violations x 5.0)", report.Score.LayerPenalty, report.Score.LayerCount))))
-	sb.WriteString(fmt.Sprintf("Size Penalty:         %s\n", formatter.Info(fmt.Sprintf("-%.1f (%d violations x 3.0)", report.Score.SizePenalty, report.Score.SizeCount))))
-	sb.WriteString(fmt.Sprintf("God Object Penalty:   %s\n", formatter.Info(fmt.Sprintf("-%.1f (%d violations x 5.0)", report.Score.GodObjectPenalty, report.Score.GodObjectCount))))
+	explanation := newScoreExplanation(report.Score)
+	sb.WriteString(fmt.Sprintf("Base Score:           %.1f\n", explanation.BaseScore))
+	sb.WriteString(fmt.Sprintf("Circular Penalty:     %s\n", formatter.Error(fmt.Sprintf("-%.1f (%s)", explanation.Circular.Penalty, explanation.Circular.basis()))))
+	sb.WriteString(fmt.Sprintf("Layer Penalty:        %s\n", formatter.Warn(fmt.Sprintf("-%.1f (%s)", explanation.Layer.Penalty, explanation.Layer.basis()))))
+	if explanation.FeatureIsolation != nil {
+		sb.WriteString(fmt.Sprintf("Isolation Penalty:    %s\n", formatter.Warn(fmt.Sprintf("-%.1f (%s)", explanation.FeatureIsolation.Penalty, explanation.FeatureIsolation.basis()))))
+	}
+	if explanation.DependencyCap != nil {
+		sb.WriteString(fmt.Sprintf("Dependency Penalty:   %s\n", formatter.Warn(fmt.Sprintf("-%.1f (%s)", explanation.DependencyCap.Penalty, explanation.DependencyCap.basis()))))
+	}
+	sb.WriteString(fmt.Sprintf("Size Penalty:         %s\n", formatter.Info(fmt.Sprintf("-%.1f (%s)", explanation.Size.Penalty, explanation.Size.basis()))))
+	sb.WriteString(fmt.Sprintf("God Object Penalty:   %s\n", formatter.Info(fmt.Sprintf("-%.1f (%s)", explanation.GodObject.Penalty, explanation.GodObject.basis()))))
 	sb.WriteString(formatter.Color("─────────────────────────────────────────────────", ColorCyan) + "\n")
 	sb.WriteString(fmt.Sprintf("Final Score:          %s\n\n", formatter.Bold(fmt.Sprintf("%.1f", report.Score.TotalScore))))
 }
+
+// writeSectionBoxWithColor writes a width-aware section title box
+func writeSectionBoxWithColor(sb *strings.Builder, formatter *ColorFormatter, layout *textLayout, title, color string) {
+	sb.WriteString(formatter.Color(layout.boxTop(), color))
+	sb.WriteString("\n")
+	sb.WriteString(formatter.Color(layout.boxTitle(title), color))
+	sb.WriteString("\n")
+	sb.WriteString(formatter.Color(layout.boxBottom(), color))
+	sb.WriteString("\n")
+}
@@ -1,22 +1,45 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"RepoDoctor/internal/model"
+
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the root configuration structure
 type Config struct {
-	Size              *SizeConfig              `yaml:"size,omitempty"`
-	GodObject         *GodObjectConfig         `yaml:"god_object,omitempty"`
-	Rules             *RulesConfig             `yaml:"rules,omitempty"`
-	Weights           *WeightsConfig           `yaml:"weights,omitempty"`
-	LanguageDetection *LanguageDetectionConfig `yaml:"language_detection,omitempty"`
+	Size               *SizeConfig              `yaml:"size,omitempty"`
+	GodObject          *GodObjectConfig         `yaml:"god_object,omitempty"`
+	Rules              *RulesConfig             `yaml:"rules,omitempty"`
+	Weights            *WeightsConfig           `yaml:"weights,omitempty"`
+	LanguageDetection  *LanguageDetectionConfig `yaml:"language_detection,omitempty"`
+	History            *HistoryConfig           `yaml:"history,omitempty"`
+	Layers             *LayersConfig            `yaml:"layers,omitempty"`
+	Graph              *GraphConfig             `yaml:"graph,omitempty"`
+	Penalties          *PenaltiesConfig         `yaml:"penalties,omitempty"`
+	ThirdParty         *ThirdPartyConfig        `yaml:"third_party,omitempty"`
+	Scoring            *ScoringConfig           `yaml:"scoring,omitempty"`
+	Output             *OutputConfig            `yaml:"output,omitempty"`
+	Orphans            *OrphansConfig           `yaml:"orphans,omitempty"`
+	RuleSectionsConfig `yaml:",inline"`
+	// PersistLatest writes .repodoctor/latest.json after every analysis
+	PersistLatest *bool `yaml:"persist_latest,omitempty"`
+	// PersistBadge writes .repodoctor/badge.json after every analysis
+	PersistBadge bool `yaml:"persist_badge,omitempty"`
+	// PersistParseCache writes .repodoctor/cache/parse.json after every
+	// analysis, as cache warm does
+	PersistParseCache bool `yaml:"persist_parse_cache,omitempty"`
+	// Exclude holds glob patterns of files, relative to the analyzed
+	// directory, that the per-file rules and import extraction skip
+	Exclude []string `yaml:"exclude,omitempty"`
 }
 
 type LanguageDetectionConfig struct {
@@ -27,19 +50,19 @@ type LanguageDetectionConfig struct {
 
 // SizeConfig holds size rule configuration
 type SizeConfig struct {
-	MaxFileLines     int    `yaml:"max_file_lines,omitempty"`
-	MaxFunctionLines int    `yaml:"max_function_lines,omitempty"`
-	Enabled          *bool  `yaml:"enabled,omitempty"`
-	Severity         string `yaml:"severity,omitempty"`
+	MaxFileLines     int            `yaml:"max_file_lines,omitempty"`
+	MaxFunctionLines int            `yaml:"max_function_lines,omitempty"`
+	Enabled          *bool          `yaml:"enabled,omitempty"`
+	Severity         model.Severity `yaml:"severity,omitempty"`
 }
 
 // GodObjectConfig holds god object rule configuration
 type GodObjectConfig struct {
-	MaxFields  int      `yaml:"max_fields,omitempty"`
-	MaxMethods int      `yaml:"max_methods,omitempty"`
-	Enabled    *bool    `yaml:"enabled,omitempty"`
-	Severity   string   `yaml:"severity,omitempty"`
-	Exclude    []string `yaml:"exclude,omitempty"`
+	MaxFields  int            `yaml:"max_fields,omitempty"`
+	MaxMethods int            `yaml:"max_methods,omitempty"`
+	Enabled    *bool          `yaml:"enabled,omitempty"`
+	Severity   model.Severity `yaml:"severity,omitempty"`
+	Exclude    []string       `yaml:"exclude,omitempty"`
 }
 
 // RulesConfig holds rule enable/disable states
@@ -48,6 +71,18 @@ type RulesConfig struct {
 	EnableGodObjectRule *bool `yaml:"enable_god_object_rule,omitempty"`
 	EnableCircularRule  *bool `yaml:"enable_circular_rule,omitempty"`
 	EnableLayerRule     *bool `yaml:"enable_layer_rule,omitempty"`
+	// Profiles enable or disable rules per directory subtree
+	Profiles []RuleProfile `yaml:"profiles,omitempty"`
+	// Timeouts bound the run time of rules, as durations (e.g. "30s") keyed
+	// by rule name; rules without one run to the end
+	Timeouts map[string]string `yaml:"timeouts,omitempty"`
+}
+
+// EntrypointOnlyConfig holds configuration for the heuristic rule that flags
+// packages imported only by entrypoints (cmd/) and tests
+type EntrypointOnlyConfig struct {
+	Enabled   *bool    `yaml:"enabled,omitempty"`
+	Allowlist []string `yaml:"allowlist,omitempty"`
 }
 
 // WeightsConfig holds penalty weights for scoring
@@ -56,6 +91,10 @@ type WeightsConfig struct {
 	Layer     float64 `yaml:"layer,omitempty"`
 	Size      float64 `yaml:"size,omitempty"`
 	GodObject float64 `yaml:"god_object,omitempty"`
+	// FeatureIsolation weighs the opt-in feature isolation rule
+	FeatureIsolation float64 `yaml:"feature_isolation,omitempty"`
+	// DependencyCap weighs an exceeded dependencies.max_external cap
+	DependencyCap float64 `yaml:"dependency_cap,omitempty"`
 }
 
 // ConfigLoader handles loading and validating configuration
@@ -114,24 +153,14 @@ func (l *ConfigLoader) GetConfig() *Config {
 
 // validate validates the configuration and returns an error if invalid
 func (l *ConfigLoader) validate(cfg *Config) error {
-	// Validate severity values if provided
-	validSeverities := map[string]bool{
-		"info":     true,
-		"warning":  true,
-		"error":    true,
-		"critical": true,
-	}
-
-	if cfg.Size != nil && cfg.Size.Severity != "" {
-		if !validSeverities[cfg.Size.Severity] {
-			return fmt.Errorf("invalid severity '%s' for size rule (must be: info, warning, error, critical)", cfg.Size.Severity)
-		}
+	// Severity names are rejected while decoding; this catches values set
+	// in code
+	if cfg.Size != nil && cfg.Size.Severity != 0 && !cfg.Size.Severity.Valid() {
+		return fmt.Errorf("invalid severity %d for size rule (must be: info, warning, error, critical)", cfg.Size.Severity)
 	}
 
-	if cfg.GodObject != nil && cfg.GodObject.Severity != "" {
-		if !validSeverities[cfg.GodObject.Severity] {
-			return fmt.Errorf("invalid severity '%s' for god object rule (must be: info, warning, error, critical)", cfg.GodObject.Severity)
-		}
+	if cfg.GodObject != nil && cfg.GodObject.Severity != 0 && !cfg.GodObject.Severity.Valid() {
+		return fmt.Errorf("invalid severity %d for god object rule (must be: info, warning, error, critical)", cfg.GodObject.Severity)
 	}
 
 	// Validate weights are non-negative
@@ -148,6 +177,12 @@ func (l *ConfigLoader) validate(cfg *Config) error {
 		if cfg.Weights.GodObject < 0 {
 			return fmt.Errorf("god object weight must be non-negative, got: %.2f", cfg.Weights.GodObject)
 		}
+		if cfg.Weights.FeatureIsolation < 0 {
+			return fmt.Errorf("feature isolation weight must be non-negative, got: %.2f", cfg.Weights.FeatureIsolation)
+		}
+		if cfg.Weights.DependencyCap < 0 {
+			return fmt.Errorf("dependency cap weight must be non-negative, got: %.2f", cfg.Weights.DependencyCap)
+		}
 	}
 
 	if cfg.LanguageDetection != nil {
@@ -174,7 +209,7 @@ func (l *ConfigLoader) validate(cfg *Config) error {
 		}
 	}
 
-	return nil
+	return validateSectionConfigs(cfg)
 }
 
 // getDefaultConfig returns the default configuration
@@ -183,19 +218,24 @@ func (l *ConfigLoader) getDefaultConfig() *Config {
 	enableGodObject := true
 	enableCircular := true
 	enableLayer := true
+	enableEntrypointOnly := false
+	persistLatest := true
+	enableFeatureIsolation := false
+	enableCohesion := false
+	enableSingleImpl := false
 
 	return &Config{
 		Size: &SizeConfig{
 			MaxFileLines:     500,
 			MaxFunctionLines: 80,
 			Enabled:          &enableSize,
-			Severity:         "warning",
+			Severity:         model.SeverityWarning,
 		},
 		GodObject: &GodObjectConfig{
 			MaxFields:  15,
 			MaxMethods: 10,
 			Enabled:    &enableGodObject,
-			Severity:   "warning",
+			Severity:   model.SeverityWarning,
 			// Exclude internal implementation files from strict checks
 			Exclude: []string{"internal/"},
 		},
@@ -206,10 +246,12 @@ func (l *ConfigLoader) getDefaultConfig() *Config {
 			EnableLayerRule:     &enableLayer,
 		},
 		Weights: &WeightsConfig{
-			Circular:  10.0,
-			Layer:     5.0,
-			Size:      3.0,
-			GodObject: 5.0,
+			Circular:         10.0,
+			Layer:            5.0,
+			Size:             3.0,
+			GodObject:        5.0,
+			FeatureIsolation: 5.0,
+			DependencyCap:    5.0,
 		},
 		LanguageDetection: &LanguageDetectionConfig{
 			Weights: map[string]float64{
@@ -227,6 +269,28 @@ func (l *ConfigLoader) getDefaultConfig() *Config {
 				"scripts": 0.2,
 			},
 		},
+		History: defaultHistoryConfig(),
+		Graph: &GraphConfig{
+			TestEdges: string(model.TestEdgesExclude),
+		},
+		PersistLatest: &persistLatest,
+		RuleSectionsConfig: RuleSectionsConfig{
+			EntrypointOnly: &EntrypointOnlyConfig{
+				Enabled:   &enableEntrypointOnly,
+				Allowlist: []string{"tools/", "scripts/"},
+			},
+			FeatureIsolation: &FeatureIsolationConfig{
+				Enabled:      &enableFeatureIsolation,
+				SharedRoots:  []string{"common", "lib", "pkg/shared"},
+				FeatureRoots: []string{"features", "apps"},
+			},
+			Cohesion: &CohesionConfig{
+				Enabled: &enableCohesion,
+			},
+			SingleImplInterface: &SingleImplInterfaceConfig{
+				Enabled: &enableSingleImpl,
+			},
+		},
 	}
 }
 
@@ -239,6 +303,15 @@ func (l *ConfigLoader) mergeWithDefaults(cfg *Config) *Config {
 	mergeRulesConfig(cfg, defaults)
 	mergeWeightsConfig(cfg, defaults)
 	mergeLanguageDetectionConfig(cfg, defaults)
+	mergeEntrypointOnlyConfig(cfg, defaults)
+	mergeHistoryConfig(cfg, defaults)
+	mergeGraphConfig(cfg, defaults)
+	mergeFeatureIsolationConfig(cfg, defaults)
+	mergeCohesionConfig(cfg, defaults)
+	mergeSingleImplInterfaceConfig(cfg, defaults)
+	if cfg.PersistLatest == nil {
+		cfg.PersistLatest = defaults.PersistLatest
+	}
 
 	return cfg
 }
@@ -257,7 +330,7 @@ func mergeSizeConfig(cfg, defaults *Config) {
 	if cfg.Size.Enabled == nil {
 		cfg.Size.Enabled = defaults.Size.Enabled
 	}
-	if cfg.Size.Severity == "" {
+	if cfg.Size.Severity == 0 {
 		cfg.Size.Severity = defaults.Size.Severity
 	}
 }
@@ -276,7 +349,7 @@ func mergeGodObjectConfig(cfg, defaults *Config) {
 	if cfg.GodObject.Enabled == nil {
 		cfg.GodObject.Enabled = defaults.GodObject.Enabled
 	}
-	if cfg.GodObject.Severity == "" {
+	if cfg.GodObject.Severity == 0 {
 		cfg.GodObject.Severity = defaults.GodObject.Severity
 	}
 }
@@ -317,6 +390,12 @@ func mergeWeightsConfig(cfg, defaults *Config) {
 	if cfg.Weights.GodObject == 0 {
 		cfg.Weights.GodObject = defaults.Weights.GodObject
 	}
+	if cfg.Weights.FeatureIsolation == 0 {
+		cfg.Weights.FeatureIsolation = defaults.Weights.FeatureIsolation
+	}
+	if cfg.Weights.DependencyCap == 0 {
+		cfg.Weights.DependencyCap = defaults.Weights.DependencyCap
+	}
 }
 
 func mergeLanguageDetectionConfig(cfg, defaults *Config) {
@@ -335,6 +414,33 @@ func mergeLanguageDetectionConfig(cfg, defaults *Config) {
 	}
 }
 
+func mergeEntrypointOnlyConfig(cfg, defaults *Config) {
+	if cfg.EntrypointOnly == nil {
+		cfg.EntrypointOnly = defaults.EntrypointOnly
+		return
+	}
+	if cfg.EntrypointOnly.Enabled == nil {
+		cfg.EntrypointOnly.Enabled = defaults.EntrypointOnly.Enabled
+	}
+	if cfg.EntrypointOnly.Allowlist == nil {
+		cfg.EntrypointOnly.Allowlist = defaults.EntrypointOnly.Allowlist
+	}
+}
+
+// configHash returns a short stable hash of the effective configuration so
+// history entries produced under different settings can be told apart
+func configHash(cfg *Config) string {
+	if cfg == nil {
+		return ""
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
 func rejectUnknownConfigKeys(data []byte) error {
 	var raw map[string]interface{}
 	if err := yaml.Unmarshal(data, &raw); err != nil {
@@ -342,7 +448,10 @@ func rejectUnknownConfigKeys(data []byte) error {
 	}
 
 	allowed := map[string]bool{
-		"size": true, "god_object": true, "rules": true, "weights": true, "language_detection": true,
+		"size": true, "god_object": true, "rules": true, "weights": true, "language_detection": true, "entrypoint_only": true,
+		"history": true, "layers": true, "graph": true, "persist_latest": true, "persist_badge": true, "persist_parse_cache": true,
+		"feature_isolation": true, "penalties": true, "cohesion": true,
+		"single_impl_interface": true, "import_diversity": true, "ignored_error": true, "dependencies": true, "circular": true, "third_party": true, "scoring": true, "output": true, "orphans": true, "exclude": true,
 	}
 	for key := range raw {
 		if !allowed[key] {
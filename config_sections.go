@@ -0,0 +1,508 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"RepoDoctor/internal/model"
+)
+
+// RuleSectionsConfig groups the config sections of individual rules. It is
+// inlined into Config, so each section is still a top-level YAML key.
+type RuleSectionsConfig struct {
+	EntrypointOnly      *EntrypointOnlyConfig      `yaml:"entrypoint_only,omitempty"`
+	FeatureIsolation    *FeatureIsolationConfig    `yaml:"feature_isolation,omitempty"`
+	Cohesion            *CohesionConfig            `yaml:"cohesion,omitempty"`
+	SingleImplInterface *SingleImplInterfaceConfig `yaml:"single_impl_interface,omitempty"`
+	ImportDiversity     *ImportDiversityConfig     `yaml:"import_diversity,omitempty"`
+	IgnoredError        *IgnoredErrorConfig        `yaml:"ignored_error,omitempty"`
+	Dependencies        *DependenciesConfig        `yaml:"dependencies,omitempty"`
+	Circular            *CircularConfig            `yaml:"circular,omitempty"`
+}
+
+// CircularConfig holds circular dependency rule configuration
+type CircularConfig struct {
+	// MinLength is the smallest number of participating nodes a cycle needs
+	// to be reported; 0 reports every cycle
+	MinLength int `yaml:"min_length,omitempty"`
+	// MaxAllowed is how many Baseline cycles may remain without failing the
+	// run; a cycle outside Baseline always fails it
+	MaxAllowed int `yaml:"max_allowed,omitempty"`
+	// Baseline holds the fingerprints of known legacy cycles
+	Baseline []string `yaml:"baseline,omitempty"`
+}
+
+// FeatureIsolationConfig holds configuration for the rule that flags shared
+// packages depending on feature packages. Roots are slash-separated path
+// globs matched against package directories and their ancestors.
+type FeatureIsolationConfig struct {
+	Enabled      *bool    `yaml:"enabled,omitempty"`
+	SharedRoots  []string `yaml:"shared_roots,omitempty"`
+	FeatureRoots []string `yaml:"feature_roots,omitempty"`
+}
+
+// LayersConfig defines a custom layer hierarchy, ordered from the highest
+// layer to the lowest. When omitted the built-in handler -> service -> repo
+// hierarchy applies.
+type LayersConfig struct {
+	Levels []LayerLevelConfig `yaml:"levels,omitempty"`
+	// Exempt lists path keywords that are never checked
+	Exempt []string `yaml:"exempt,omitempty"`
+	// Default names the level for paths matching no keyword; empty leaves them unchecked
+	Default string `yaml:"default,omitempty"`
+}
+
+// LayerLevelConfig is a single named level and the path keywords that select it
+type LayerLevelConfig struct {
+	Name     string   `yaml:"name"`
+	Keywords []string `yaml:"keywords"`
+}
+
+// HistoryConfig holds score history configuration
+type HistoryConfig struct {
+	// DedupeWindow is a duration (e.g. "10m"); "0" disables deduplication
+	DedupeWindow string `yaml:"dedupe_window,omitempty"`
+	// MaxEntries caps the number of history entries; the oldest are dropped
+	// first. 0 keeps every entry.
+	MaxEntries *int `yaml:"max_entries,omitempty"`
+	// RegressionWindow is how many previous runs the regression check
+	// averages
+	RegressionWindow *int `yaml:"regression_window,omitempty"`
+	// RegressionThreshold is how many points below that average a score
+	// must fall to be a regression
+	RegressionThreshold *float64 `yaml:"regression_threshold,omitempty"`
+}
+
+// GraphConfig holds dependency graph construction settings
+type GraphConfig struct {
+	// TestEdges is include, exclude or separate; see model.TestEdgeMode
+	TestEdges string `yaml:"test_edges,omitempty"`
+}
+
+// ScoringConfig selects the score model: "weighted" (default) or
+// "category-rubric"
+type ScoringConfig struct {
+	Model string `yaml:"model,omitempty"`
+	// DensityWeights weighs violations by severity in the violation density
+	DensityWeights *DensityWeightsConfig `yaml:"density_weights,omitempty"`
+}
+
+// DensityWeightsConfig holds the density weight of each severity tier;
+// unset tiers keep their default (critical 10, high 5, medium 3, low 1)
+type DensityWeightsConfig struct {
+	Critical float64 `yaml:"critical,omitempty"`
+	High     float64 `yaml:"high,omitempty"`
+	Medium   float64 `yaml:"medium,omitempty"`
+	Low      float64 `yaml:"low,omitempty"`
+}
+
+// ThirdPartyConfig controls how copied third-party code is treated. Detected
+// directories are always reported; Exclude, true by default, keeps their
+// files out of the per-file rules.
+type ThirdPartyConfig struct {
+	Exclude *bool `yaml:"exclude,omitempty"`
+}
+
+// thirdPartyExcluded reports whether detected third-party code is left out
+// of the per-file rules
+func thirdPartyExcluded(cfg *Config) bool {
+	return cfg == nil || cfg.ThirdParty == nil || cfg.ThirdParty.Exclude == nil || *cfg.ThirdParty.Exclude
+}
+
+// PenaltiesConfig holds optional penalty curve expressions per category,
+// e.g. "min(15, count * 2)". A set curve replaces the flat weight x count
+// penalty of its category; see ParsePenaltyExpr for the grammar.
+type PenaltiesConfig struct {
+	Circular  string `yaml:"circular,omitempty"`
+	Layer     string `yaml:"layer,omitempty"`
+	Size      string `yaml:"size,omitempty"`
+	GodObject string `yaml:"god_object,omitempty"`
+}
+
+// curves returns the config keys and expressions of the configured curves
+func (c *PenaltiesConfig) curves() map[string]string {
+	return map[string]string{"circular": c.Circular, "layer": c.Layer, "size": c.Size, "god_object": c.GodObject}
+}
+
+// CohesionConfig controls the struct cohesion metric. When enabled, cohesion
+// is reported per struct; structs below MinCohesion are additionally
+// flagged as advisories (0 disables flagging).
+type CohesionConfig struct {
+	Enabled     *bool   `yaml:"enabled,omitempty"`
+	MinCohesion float64 `yaml:"min_cohesion,omitempty"`
+}
+
+// SingleImplInterfaceConfig enables the rule that reports interfaces with
+// exactly one implementation in the module
+type SingleImplInterfaceConfig struct {
+	Enabled *bool `yaml:"enabled,omitempty"`
+}
+
+// defaultImportDiversityMaxDomains is how many external modules a package
+// may import unless import_diversity.max_domains says otherwise
+const defaultImportDiversityMaxDomains = 5
+
+// ImportDiversityConfig enables the rule that reports packages importing
+// more than MaxDomains distinct external modules as low-cohesion
+// candidates. Standard library and own-module imports are not counted.
+type ImportDiversityConfig struct {
+	Enabled    *bool `yaml:"enabled,omitempty"`
+	MaxDomains int   `yaml:"max_domains,omitempty"`
+}
+
+// IgnoredErrorConfig enables the rule that reports error returns discarded
+// to the blank identifier
+type IgnoredErrorConfig struct {
+	Enabled *bool `yaml:"enabled,omitempty"`
+}
+
+func validateImportDiversityConfig(diversity *ImportDiversityConfig) error {
+	if diversity != nil && diversity.MaxDomains < 0 {
+		return fmt.Errorf("import_diversity.max_domains must be non-negative, got: %d", diversity.MaxDomains)
+	}
+	return nil
+}
+
+func mergeCohesionConfig(cfg, defaults *Config) {
+	if cfg.Cohesion == nil {
+		cfg.Cohesion = defaults.Cohesion
+		return
+	}
+	if cfg.Cohesion.Enabled == nil {
+		cfg.Cohesion.Enabled = defaults.Cohesion.Enabled
+	}
+}
+
+// DependenciesConfig caps the number of distinct external modules. The cap
+// is unset by default, which disables the dependency-cap rule.
+type DependenciesConfig struct {
+	MaxExternal *int `yaml:"max_external,omitempty"`
+}
+
+func validateDependenciesConfig(dependencies *DependenciesConfig) error {
+	if dependencies != nil && dependencies.MaxExternal != nil && *dependencies.MaxExternal < 0 {
+		return fmt.Errorf("dependencies.max_external must be non-negative, got: %d", *dependencies.MaxExternal)
+	}
+	return nil
+}
+
+func validateRuleProfiles(profiles []RuleProfile) error {
+	for i, profile := range profiles {
+		root := profileRoot(profile.Path)
+		if strings.TrimSpace(profile.Path) == "" {
+			return fmt.Errorf("rules.profiles[%d].path must not be empty", i)
+		}
+		if _, err := path.Match(root, ""); err != nil {
+			return fmt.Errorf("rules.profiles[%d].path %q is not a valid glob: %w", i, profile.Path, err)
+		}
+
+		enabled, err := resolveRuleNames(strings.Join(profile.Enable, ","))
+		if err != nil {
+			return fmt.Errorf("rules.profiles[%d].enable: %w", i, err)
+		}
+		disabled, err := resolveRuleNames(strings.Join(profile.Disable, ","))
+		if err != nil {
+			return fmt.Errorf("rules.profiles[%d].disable: %w", i, err)
+		}
+		for _, id := range enabled {
+			if slices.Contains(disabled, id) {
+				return fmt.Errorf("rules.profiles[%d] both enables and disables %s", i, ruleShortName(id))
+			}
+		}
+	}
+	return nil
+}
+
+func mergeSingleImplInterfaceConfig(cfg, defaults *Config) {
+	if cfg.SingleImplInterface == nil {
+		cfg.SingleImplInterface = defaults.SingleImplInterface
+		return
+	}
+	if cfg.SingleImplInterface.Enabled == nil {
+		cfg.SingleImplInterface.Enabled = defaults.SingleImplInterface.Enabled
+	}
+}
+
+func validateCohesionConfig(cohesion *CohesionConfig) error {
+	if cohesion != nil && (cohesion.MinCohesion < 0 || cohesion.MinCohesion > 1) {
+		return fmt.Errorf("cohesion.min_cohesion must be between 0 and 1, got: %.2f", cohesion.MinCohesion)
+	}
+	return nil
+}
+
+func mergeFeatureIsolationConfig(cfg, defaults *Config) {
+	if cfg.FeatureIsolation == nil {
+		cfg.FeatureIsolation = defaults.FeatureIsolation
+		return
+	}
+	if cfg.FeatureIsolation.Enabled == nil {
+		cfg.FeatureIsolation.Enabled = defaults.FeatureIsolation.Enabled
+	}
+	if cfg.FeatureIsolation.SharedRoots == nil {
+		cfg.FeatureIsolation.SharedRoots = defaults.FeatureIsolation.SharedRoots
+	}
+	if cfg.FeatureIsolation.FeatureRoots == nil {
+		cfg.FeatureIsolation.FeatureRoots = defaults.FeatureIsolation.FeatureRoots
+	}
+}
+
+func validateFeatureIsolationConfig(cfg *FeatureIsolationConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	fields := []struct {
+		name  string
+		roots []string
+	}{{"shared_roots", cfg.SharedRoots}, {"feature_roots", cfg.FeatureRoots}}
+	for _, field := range fields {
+		for _, root := range field.roots {
+			if strings.TrimSpace(root) == "" {
+				return fmt.Errorf("feature_isolation.%s cannot include empty values", field.name)
+			}
+			if _, err := path.Match(root, ""); err != nil {
+				return fmt.Errorf("invalid feature_isolation.%s glob '%s': %w", field.name, root, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateLayersConfig(layers *LayersConfig) error {
+	if layers == nil {
+		return nil
+	}
+	if len(layers.Levels) == 0 {
+		return fmt.Errorf("layers.levels must define at least one level")
+	}
+
+	names := make(map[string]bool, len(layers.Levels))
+	for i, level := range layers.Levels {
+		if strings.TrimSpace(level.Name) == "" {
+			return fmt.Errorf("layers.levels[%d] has an empty name", i)
+		}
+		if names[level.Name] {
+			return fmt.Errorf("layers.levels contains duplicate level '%s'", level.Name)
+		}
+		names[level.Name] = true
+		if len(level.Keywords) == 0 {
+			return fmt.Errorf("layer level '%s' must have at least one keyword", level.Name)
+		}
+	}
+	if layers.Default != "" && !names[layers.Default] {
+		return fmt.Errorf("layers.default '%s' is not a defined level", layers.Default)
+	}
+	return nil
+}
+
+// defaultHistoryConfig deduplicates runs within 10 minutes, keeps the
+// newest defaultHistoryMaxEntries entries and checks scores for regressions
+// against the default window and threshold
+func defaultHistoryConfig() *HistoryConfig {
+	maxEntries, window, threshold := defaultHistoryMaxEntries, defaultRegressionWindow, defaultRegressionThreshold
+	return &HistoryConfig{DedupeWindow: "10m", MaxEntries: &maxEntries, RegressionWindow: &window, RegressionThreshold: &threshold}
+}
+
+func mergeHistoryConfig(cfg, defaults *Config) {
+	if cfg.History == nil {
+		cfg.History = defaults.History
+		return
+	}
+	if cfg.History.DedupeWindow == "" {
+		cfg.History.DedupeWindow = defaults.History.DedupeWindow
+	}
+	if cfg.History.MaxEntries == nil {
+		cfg.History.MaxEntries = defaults.History.MaxEntries
+	}
+	if cfg.History.RegressionWindow == nil {
+		cfg.History.RegressionWindow = defaults.History.RegressionWindow
+	}
+	if cfg.History.RegressionThreshold == nil {
+		cfg.History.RegressionThreshold = defaults.History.RegressionThreshold
+	}
+}
+
+func mergeGraphConfig(cfg, defaults *Config) {
+	if cfg.Graph == nil {
+		cfg.Graph = defaults.Graph
+		return
+	}
+	if cfg.Graph.TestEdges == "" {
+		cfg.Graph.TestEdges = defaults.Graph.TestEdges
+	}
+}
+
+// graphTestEdgeMode returns the configured test edge mode, defaulting to exclude
+func graphTestEdgeMode(cfg *Config) model.TestEdgeMode {
+	if cfg == nil || cfg.Graph == nil {
+		return model.TestEdgesExclude
+	}
+	mode, err := model.ParseTestEdgeMode(cfg.Graph.TestEdges)
+	if err != nil {
+		return model.TestEdgesExclude
+	}
+	return mode
+}
+
+// testEdgeModeFor returns the configured test edge mode, or include when
+// -include-test-edges overrides it
+func testEdgeModeFor(cfg *Config, includeTestEdges bool) model.TestEdgeMode {
+	if includeTestEdges {
+		return model.TestEdgesInclude
+	}
+	return graphTestEdgeMode(cfg)
+}
+
+// historyDedupeWindow returns the configured history deduplication window
+func historyDedupeWindow(cfg *Config) time.Duration {
+	if cfg == nil || cfg.History == nil || cfg.History.DedupeWindow == "" {
+		return 0
+	}
+	window, err := time.ParseDuration(cfg.History.DedupeWindow)
+	if err != nil || window < 0 {
+		return 0
+	}
+	return window
+}
+
+// historyMaxEntries returns the configured history length cap, or the
+// default cap without one
+func historyMaxEntries(cfg *Config) int {
+	if cfg == nil || cfg.History == nil || cfg.History.MaxEntries == nil {
+		return defaultHistoryMaxEntries
+	}
+	return *cfg.History.MaxEntries
+}
+
+// historyRegressionWindow returns the configured regression window, or the
+// default window without one
+func historyRegressionWindow(cfg *Config) int {
+	if cfg == nil || cfg.History == nil || cfg.History.RegressionWindow == nil {
+		return defaultRegressionWindow
+	}
+	return *cfg.History.RegressionWindow
+}
+
+// historyRegressionThreshold returns the configured regression threshold,
+// or the default threshold without one
+func historyRegressionThreshold(cfg *Config) float64 {
+	if cfg == nil || cfg.History == nil || cfg.History.RegressionThreshold == nil {
+		return defaultRegressionThreshold
+	}
+	return *cfg.History.RegressionThreshold
+}
+
+func validateGraphConfig(graph *GraphConfig) error {
+	if graph == nil {
+		return nil
+	}
+	if _, err := model.ParseTestEdgeMode(graph.TestEdges); err != nil {
+		return fmt.Errorf("graph.test_edges: %w", err)
+	}
+	return nil
+}
+
+func validateHistoryConfig(history *HistoryConfig) error {
+	if history == nil {
+		return nil
+	}
+	if history.MaxEntries != nil && *history.MaxEntries < 0 {
+		return fmt.Errorf("history.max_entries must be non-negative, got: %d", *history.MaxEntries)
+	}
+	if history.RegressionWindow != nil && *history.RegressionWindow < 1 {
+		return fmt.Errorf("history.regression_window must be at least 1, got: %d", *history.RegressionWindow)
+	}
+	if history.RegressionThreshold != nil && *history.RegressionThreshold < 0 {
+		return fmt.Errorf("history.regression_threshold must be non-negative, got: %g", *history.RegressionThreshold)
+	}
+	if history.DedupeWindow == "" {
+		return nil
+	}
+	window, err := time.ParseDuration(history.DedupeWindow)
+	if err != nil {
+		return fmt.Errorf("invalid history.dedupe_window '%s': %w", history.DedupeWindow, err)
+	}
+	if window < 0 {
+		return fmt.Errorf("history.dedupe_window must be non-negative, got: %s", history.DedupeWindow)
+	}
+	return nil
+}
+
+func validatePenaltiesConfig(penalties *PenaltiesConfig) error {
+	if penalties == nil {
+		return nil
+	}
+	for key, source := range penalties.curves() {
+		if source == "" {
+			continue
+		}
+		if _, err := ParsePenaltyExpr(source); err != nil {
+			return fmt.Errorf("invalid penalties.%s expression '%s': %w", key, source, err)
+		}
+	}
+	return nil
+}
+
+// validateSectionConfigs validates the optional config sections
+func validateSectionConfigs(cfg *Config) error {
+	if err := validateLayersConfig(cfg.Layers); err != nil {
+		return err
+	}
+	if err := validateFeatureIsolationConfig(cfg.FeatureIsolation); err != nil {
+		return err
+	}
+	if err := validateGraphConfig(cfg.Graph); err != nil {
+		return err
+	}
+	if err := validateHistoryConfig(cfg.History); err != nil {
+		return err
+	}
+	if err := validateCohesionConfig(cfg.Cohesion); err != nil {
+		return err
+	}
+	if err := validateImportDiversityConfig(cfg.ImportDiversity); err != nil {
+		return err
+	}
+	if err := validateDependenciesConfig(cfg.Dependencies); err != nil {
+		return err
+	}
+	if err := validateCircularConfig(cfg.Circular); err != nil {
+		return err
+	}
+	if cfg.Rules != nil {
+		if err := validateRuleProfiles(cfg.Rules.Profiles); err != nil {
+			return err
+		}
+		if err := validateRuleTimeouts(cfg.Rules.Timeouts); err != nil {
+			return err
+		}
+	}
+	if err := validateScoringConfig(cfg.Scoring); err != nil {
+		return err
+	}
+	if err := validateOutputConfig(cfg.Output); err != nil {
+		return err
+	}
+	if err := validateOrphansConfig(cfg.Orphans); err != nil {
+		return err
+	}
+	if err := validateExcludeGlobs(cfg.Exclude); err != nil {
+		return err
+	}
+	return validatePenaltiesConfig(cfg.Penalties)
+}
+
+func validateCircularConfig(circular *CircularConfig) error {
+	if circular == nil {
+		return nil
+	}
+	if circular.MinLength < 0 {
+		return fmt.Errorf("circular.min_length must be non-negative, got: %d", circular.MinLength)
+	}
+	if circular.MaxAllowed < 0 {
+		return fmt.Errorf("circular.max_allowed must be non-negative, got: %d", circular.MaxAllowed)
+	}
+	return nil
+}
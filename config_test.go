@@ -1,10 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"RepoDoctor/internal/model"
 )
 
 func TestConfigLoader_DefaultConfig(t *testing.T) {
@@ -38,6 +42,10 @@ func TestConfigLoader_DefaultConfig(t *testing.T) {
 	if config.Rules.EnableGodObjectRule == nil || !*config.Rules.EnableGodObjectRule {
 		t.Error("Expected EnableGodObjectRule to be true by default")
 	}
+
+	if config.EntrypointOnly == nil || config.EntrypointOnly.Enabled == nil || *config.EntrypointOnly.Enabled {
+		t.Error("Expected EntrypointOnly rule to be disabled by default")
+	}
 }
 
 func TestConfigLoader_NonExistentFile(t *testing.T) {
@@ -398,3 +406,313 @@ language_detection:
 		t.Fatalf("expected deterministic nested unknown-key error, got: %v", err)
 	}
 }
+
+func TestConfigLoader_LayersHierarchy(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+layers:
+  levels:
+    - name: transport
+      keywords: [transport, http]
+    - name: application
+      keywords: [app]
+    - name: domain
+      keywords: [domain]
+    - name: infrastructure
+      keywords: [infra]
+    - name: platform
+      keywords: [platform]
+  exempt: [testutil]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Layers == nil || len(cfg.Layers.Levels) != 5 {
+		t.Fatalf("expected 5 layer levels, got %+v", cfg.Layers)
+	}
+
+	hierarchy := layerHierarchyFromConfig(cfg.Layers)
+	if hierarchy.Levels[4].Name != "platform" || len(hierarchy.Levels[0].Keywords) != 2 || hierarchy.Exempt[0] != "testutil" {
+		t.Fatalf("unexpected hierarchy conversion: %+v", hierarchy)
+	}
+}
+
+func TestConfigLoader_LayersValidation(t *testing.T) {
+	cases := map[string]string{
+		"empty levels":      "layers:\n  levels: []\n",
+		"duplicate level":   "layers:\n  levels:\n    - {name: a, keywords: [a]}\n    - {name: a, keywords: [b]}\n",
+		"missing keywords":  "layers:\n  levels:\n    - {name: a}\n",
+		"undefined default": "layers:\n  levels:\n    - {name: a, keywords: [a]}\n  default: b\n",
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			if _, err := NewConfigLoader(configPath).Load(); err == nil {
+				t.Fatal("expected layers validation error")
+			}
+		})
+	}
+}
+
+func TestConfigLoader_GraphTestEdges(t *testing.T) {
+	loader := NewConfigLoader("")
+	if got := graphTestEdgeMode(loader.mergeWithDefaults(&Config{})); got != "exclude" {
+		t.Fatalf("expected default test edge mode exclude, got %q", got)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("graph:\n  test_edges: separate\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := graphTestEdgeMode(cfg); got != "separate" {
+		t.Fatalf("expected test edge mode separate, got %q", got)
+	}
+
+	if err := os.WriteFile(configPath, []byte("graph:\n  test_edges: overlay\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil {
+		t.Fatal("expected validation error for unknown graph.test_edges value")
+	}
+}
+
+func TestConfigLoader_FeatureIsolationRoots(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("feature_isolation:\n  shared_roots: [\"platform/*\"]\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.FeatureIsolation.SharedRoots, []string{"platform/*"}) {
+		t.Fatalf("expected custom shared roots, got %v", cfg.FeatureIsolation.SharedRoots)
+	}
+	if !reflect.DeepEqual(cfg.FeatureIsolation.FeatureRoots, []string{"features", "apps"}) {
+		t.Fatalf("expected default feature roots, got %v", cfg.FeatureIsolation.FeatureRoots)
+	}
+	// Roots alone do not enable the rule
+	if ruleEnabledByConfig("rule.feature-isolation", cfg) || ruleEnabledByConfig("rule.feature-isolation", nil) {
+		t.Fatal("expected feature isolation to be opt-in")
+	}
+
+	if err := os.WriteFile(configPath, []byte("feature_isolation:\n  feature_roots: [\"features/[\"]\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil {
+		t.Fatal("expected validation error for malformed feature root glob")
+	}
+}
+
+func TestConfigLoader_PenaltyCurves(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("penalties:\n  size: \"min(15, count * 2)\"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sizeViolations := make([]model.Violation, 10)
+	for i := range sizeViolations {
+		sizeViolations[i] = model.Violation{RuleID: "rule.size", File: fmt.Sprintf("f%d.go", i), Message: "File f.go has 600 lines (threshold: 500)"}
+	}
+	report := buildReportFromRuleViolations("/repo", "test", cfg, sizeViolations)
+	if report.Score.SizePenalty != 15 || report.Score.TotalScore != 85 {
+		t.Fatalf("expected capped size penalty 15 and score 85, got %.1f and %.1f", report.Score.SizePenalty, report.Score.TotalScore)
+	}
+	if err := verifyReportInvariants(report, scoringWeightsFromConfig(cfg)); err != nil {
+		t.Fatalf("expected curve penalties to pass self-check, got %v", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte("penalties:\n  layer: \"count ** 2\"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil || !strings.Contains(err.Error(), "penalties.layer") {
+		t.Fatalf("expected validation error naming penalties.layer, got %v", err)
+	}
+}
+
+func TestConfigLoader_CohesionConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("cohesion:\n  min_cohesion: 0.5\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *cfg.Cohesion.Enabled || ruleEnabledByConfig("rule.struct-cohesion", cfg) {
+		t.Fatal("expected the cohesion metric to stay disabled by default")
+	}
+
+	if err := os.WriteFile(configPath, []byte("cohesion:\n  enabled: true\n  min_cohesion: 1.5\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil {
+		t.Fatal("expected validation error for min_cohesion above 1")
+	}
+}
+
+func TestConfigLoader_SingleImplInterfaceConfig(t *testing.T) {
+	if ruleEnabledByConfig("rule.single-impl-interface", nil) {
+		t.Fatal("expected the single-impl-interface rule to be opt-in")
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("single_impl_interface:\n  enabled: true\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ruleEnabledByConfig("rule.single-impl-interface", cfg) {
+		t.Fatal("expected the single-impl-interface rule to be enabled")
+	}
+}
+
+func TestConfigLoader_DependenciesMaxExternal(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("dependencies:\n  max_external: 0\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Dependencies.MaxExternal == nil || *cfg.Dependencies.MaxExternal != 0 {
+		t.Fatalf("expected an explicit zero cap, got %+v", cfg.Dependencies)
+	}
+
+	if err := os.WriteFile(configPath, []byte("dependencies:\n  max_external: -1\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil {
+		t.Fatal("expected validation error for negative max_external")
+	}
+}
+
+func TestConfigLoader_RuleProfiles(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	valid := "rules:\n  profiles:\n    - path: cmd/**\n      disable: [god-object]\n    - path: cmd/tool\n      enable: [rule.god-object]\n"
+	if err := os.WriteFile(configPath, []byte(valid), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Rules.Profiles) != 2 || cfg.Rules.Profiles[0].Path != "cmd/**" {
+		t.Fatalf("expected two rule profiles, got %+v", cfg.Rules.Profiles)
+	}
+
+	invalid := map[string]string{
+		"unknown rule":     "rules:\n  profiles:\n    - path: cmd\n      disable: [god-objekt]\n",
+		"empty path":       "rules:\n  profiles:\n    - path: \"\"\n      disable: [size]\n",
+		"bad glob":         "rules:\n  profiles:\n    - path: \"cmd/[\"\n      disable: [size]\n",
+		"enable & disable": "rules:\n  profiles:\n    - path: cmd\n      enable: [size]\n      disable: [size]\n",
+	}
+	for name, content := range invalid {
+		if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := NewConfigLoader(configPath).Load(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestConfigLoader_CircularMinLength(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("circular:\n  min_length: 3\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Circular.MinLength != 3 {
+		t.Fatalf("expected min_length 3, got %d", cfg.Circular.MinLength)
+	}
+
+	if err := os.WriteFile(configPath, []byte("circular:\n  min_length: -2\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil {
+		t.Fatal("expected validation error for negative min_length")
+	}
+
+	if err := os.WriteFile(configPath, []byte("circular:\n  max_allowed: -1\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil {
+		t.Fatal("expected validation error for negative max_allowed")
+	}
+}
+
+func TestConfigLoader_Severity(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("size:\n  severity: Critical\ngod_object:\n  max_fields: 20\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Size.Severity != model.SeverityCritical || cfg.GodObject.Severity != model.SeverityWarning {
+		t.Fatalf("expected size=critical and the god object default warning, got %s and %s", cfg.Size.Severity, cfg.GodObject.Severity)
+	}
+
+	if err := os.WriteFile(configPath, []byte("size:\n  severity: high\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil || !strings.Contains(err.Error(), "invalid severity 'high'") {
+		t.Fatalf("expected an invalid severity error, got %v", err)
+	}
+}
+
+func TestConfigLoader_CustomLayerWeightReachesScore(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("weights:\n  layer: 8.0\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := NewConfigLoader(configPath).Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	defaults := (&ConfigLoader{}).getDefaultConfig()
+	if cfg.Weights.Layer != 8.0 || cfg.Weights.Circular != defaults.Weights.Circular || cfg.Weights.Size != defaults.Weights.Size || cfg.Weights.GodObject != defaults.Weights.GodObject {
+		t.Fatalf("expected only the layer weight to change, got %+v", *cfg.Weights)
+	}
+
+	graph := NewDependencyGraph()
+	graph.AddEdge("project/repo/user_repo.go", "project/service/user_service.go")
+	score := NewStructuralScorer(graph, cfg, "").CalculateScore()
+	if score.LayerCount != 1 || score.LayerPenalty != 8.0 {
+		t.Fatalf("expected one layer violation costing 8, got %d costing %.1f", score.LayerCount, score.LayerPenalty)
+	}
+
+	report := &StructuralReport{Layer: []LayerViolation{{From: "a.go", To: "b.go"}, {From: "c.go", To: "d.go"}}}
+	if got := calculateScoreFromViolations(cfg, report).LayerPenalty; got != 16.0 {
+		t.Fatalf("expected the rule engine score to use the configured weight, got %.1f", got)
+	}
+}
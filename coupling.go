@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// couplingTopN is the number of packages listed in the COUPLING section
+const couplingTopN = 5
+
+// PackageStructure holds the findings a run derives from the package graph
+type PackageStructure struct {
+	// Coupling lists the most depended-upon packages
+	Coupling []PackageCoupling
+	// Orphans lists the packages no other analyzed package imports
+	Orphans []string
+	// Diagram is the package graph of -format mermaid
+	Diagram *PackageDiagram
+}
+
+// PackageCoupling is the afferent and efferent coupling of one package:
+// how many analyzed packages import it and how many it imports
+type PackageCoupling struct {
+	Package   string `json:"package"`
+	InDegree  int    `json:"inDegree"`
+	OutDegree int    `json:"outDegree"`
+}
+
+// computePackageCoupling returns the topN most depended-upon packages of
+// the package graph, sorted by in-degree descending and then by package.
+// Packages nothing imports are left out.
+func computePackageCoupling(graph Graph, absPath string, files []string, topN int) []PackageCoupling {
+	packages := buildPackageGraph(graph, absPath, files)
+	coupling := make([]PackageCoupling, 0, packages.GetNodeCount())
+	for _, pkg := range packages.GetAllNodes() {
+		if in := packages.GetInDegree(pkg); in > 0 {
+			coupling = append(coupling, PackageCoupling{Package: pkg, InDegree: in, OutDegree: packages.GetOutDegree(pkg)})
+		}
+	}
+	sort.Slice(coupling, func(i, j int) bool {
+		if coupling[i].InDegree != coupling[j].InDegree {
+			return coupling[i].InDegree > coupling[j].InDegree
+		}
+		return coupling[i].Package < coupling[j].Package
+	})
+	if topN >= 0 && len(coupling) > topN {
+		coupling = coupling[:topN]
+	}
+	return coupling
+}
+
+// buildPackageGraph collapses the file-level graph into a graph of the
+// analyzed packages. A package is the directory of its files relative to
+// absPath; Go imports of the own module map to the same directories.
+// External imports and imports within a package are left out.
+func buildPackageGraph(graph Graph, absPath string, files []string) *DependencyGraph {
+	modulePath, _ := readGoModule(filepath.Join(absPath, "go.mod"))
+	analyzed := make(map[string]bool, len(files))
+	for _, file := range files {
+		analyzed[file] = true
+	}
+	packageOf := func(node string) (string, bool) {
+		if modulePath != "" && isModuleImportPath(node, modulePath) {
+			if rel := strings.TrimPrefix(strings.TrimPrefix(node, modulePath), "/"); rel != "" {
+				return rel, true
+			}
+			return ".", true
+		}
+		// Import paths are graph nodes too, but only files have an
+		// absolute path
+		if analyzed[node] && filepath.IsAbs(node) {
+			rel, err := filepath.Rel(absPath, filepath.Dir(node))
+			return filepath.ToSlash(rel), err == nil
+		}
+		return "", false
+	}
+
+	packages := NewDependencyGraph()
+	for _, node := range graph.GetAllNodes() {
+		from, ok := packageOf(node)
+		if !ok {
+			continue
+		}
+		packages.AddNode(from)
+		for _, dep := range graph.GetDependencies(node) {
+			if to, ok := packageOf(dep); ok && to != from {
+				packages.AddEdge(from, to)
+			}
+		}
+	}
+	return packages
+}
+
+// formatCouplingLine renders one row of the COUPLING section
+func formatCouplingLine(index int, c PackageCoupling) string {
+	return fmt.Sprintf("[%d] %s (in: %d, out: %d)", index, c.Package, c.InDegree, c.OutDegree)
+}
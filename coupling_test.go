@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDependencyGraph_InAndOutDegree(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddEdge("a", "c")
+	graph.AddEdge("b", "c")
+	graph.AddEdge("b", "c")
+	graph.AddEdge("c", "d")
+	graph.AddNode("e")
+	graph.AddTestEdge("e", "c")
+
+	degrees := map[string][2]int{"a": {0, 1}, "b": {0, 1}, "c": {2, 1}, "d": {1, 0}, "e": {0, 0}, "missing": {0, 0}}
+	for node, want := range degrees {
+		if in, out := graph.GetInDegree(node), graph.GetOutDegree(node); in != want[0] || out != want[1] {
+			t.Errorf("%s: expected in/out %d/%d, got %d/%d", node, want[0], want[1], in, out)
+		}
+	}
+}
+
+// couplingFixtureGraph is a small module graph: api and cli import core,
+// api and core import store, and every package also imports fmt. Like the
+// analyzed files of a run, the returned files include the import nodes.
+func couplingFixtureGraph(root string) (Graph, []string) {
+	file := func(rel string) string { return filepath.Join(root, filepath.FromSlash(rel)) }
+	graph := NewDependencyGraph()
+	graph.AddEdge(file("api/api.go"), "example.com/demo/core")
+	graph.AddEdge(file("api/api.go"), "example.com/demo/store")
+	graph.AddEdge(file("api/routes.go"), "example.com/demo/core")
+	graph.AddEdge(file("cli/main.go"), "example.com/demo/core")
+	graph.AddEdge(file("core/core.go"), "example.com/demo/store")
+	graph.AddEdge(file("core/core_helpers.go"), "example.com/demo/core")
+	for _, node := range graph.GetAllNodes() {
+		if strings.HasSuffix(node, ".go") {
+			graph.AddEdge(node, "fmt")
+		}
+	}
+	graph.AddNode(file("store/store.go"))
+	return graph, []string{"fmt", "example.com/demo/core", "example.com/demo/store", file("api/api.go"), file("api/routes.go"), file("cli/main.go"), file("core/core.go"), file("core/core_helpers.go"), file("store/store.go")}
+}
+
+func TestComputePackageCoupling_RanksPackagesByInDegree(t *testing.T) {
+	root := t.TempDir()
+	writeServiceFixture(t, root, map[string]string{"go.mod": "module example.com/demo\n"})
+	graph, files := couplingFixtureGraph(root)
+
+	want := []PackageCoupling{
+		{Package: "core", InDegree: 2, OutDegree: 1},
+		{Package: "store", InDegree: 2, OutDegree: 0},
+	}
+	if got := computePackageCoupling(graph, root, files, couplingTopN); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if got := computePackageCoupling(graph, root, files, 1); !reflect.DeepEqual(got, want[:1]) {
+		t.Fatalf("expected the top package only, got %+v", got)
+	}
+}
+
+func TestCoupling_ShownInTextAndJSONReports(t *testing.T) {
+	report := &StructuralReport{
+		Path:    "demo",
+		Score:   &StructuralScore{TotalScore: 100, MaxScore: 100},
+		Metrics: ReportMetrics{Packages: PackageStructure{Coupling: []PackageCoupling{{Package: "core", InDegree: 2, OutDegree: 1}, {Package: "store", InDegree: 2}}}},
+	}
+
+	for _, text := range []string{NewReporter(FormatText).Format(report), NewColoredReporter(FormatText, false).FormatColoredText(report)} {
+		if !strings.Contains(text, "COUPLING") || !strings.Contains(text, "[1] core (in: 2, out: 1)") || !strings.Contains(text, "[2] store (in: 2, out: 0)") {
+			t.Errorf("expected a COUPLING section listing core and store, got:\n%s", text)
+		}
+	}
+
+	var legacy struct {
+		Metrics struct {
+			Coupling []PackageCoupling `json:"coupling"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONLegacy).Format(report)), &legacy); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if !reflect.DeepEqual(legacy.Metrics.Coupling, report.Metrics.Packages.Coupling) {
+		t.Errorf("expected metrics.coupling to match, got %+v", legacy.Metrics.Coupling)
+	}
+
+	var v1 struct {
+		Coupling []PackageCoupling `json:"coupling"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &v1); err != nil {
+		t.Fatalf("invalid json-v1: %v", err)
+	}
+	if !reflect.DeepEqual(v1.Coupling, report.Metrics.Packages.Coupling) {
+		t.Errorf("expected coupling in json-v1, got %+v", v1.Coupling)
+	}
+
+	if text := NewReporter(FormatText).Format(&StructuralReport{Score: report.Score}); strings.Contains(text, "COUPLING") {
+		t.Errorf("expected no COUPLING section without coupling data, got:\n%s", text)
+	}
+}
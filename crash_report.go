@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimedebug "runtime/debug"
+	"strings"
+	"time"
+)
+
+// crashReportDir is where crash reports are written, relative to the
+// working directory
+var crashReportDir = ".repodoctor"
+
+// crashError is returned by runRecovered when the command panicked
+type crashError struct {
+	value interface{}
+	// path is the crash report, empty when it could not be written
+	path string
+}
+
+func (e *crashError) Error() string {
+	if e.path == "" {
+		return fmt.Sprintf("RepoDoctor crashed: %v (the crash report could not be written)", e.value)
+	}
+	return fmt.Sprintf("RepoDoctor crashed: %v; crash report: %s", e.value, e.path)
+}
+
+// splitDebugFlag removes -debug from the command arguments. Every command
+// accepts it, so it is taken out before the command parses its own flags.
+func splitDebugFlag(args []string) (bool, []string) {
+	debug := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "-debug" || arg == "--debug" {
+			debug = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return debug, rest
+}
+
+// runCLI runs a command through execute and returns the process exit code:
+// 0 on success, the exitCodeForError code after printing the command's
+// error, and exitCodeInternalError after a crash, with a one-line pointer
+// to the crash report on stderr
+func runCLI(cmd string, args []string, execute func(cmd string, args []string) error) int {
+	debug, args := splitDebugFlag(args)
+	err := runRecovered(cmd, args, crashReportDir, debug, func() error { return execute(cmd, args) })
+	if crash, ok := err.(*crashError); ok {
+		fmt.Fprintln(os.Stderr, ColorError(crash.Error()))
+		return exitCodeInternalError
+	}
+	if err != nil {
+		PrintError(err)
+		return exitCodeForError(err)
+	}
+	return exitCodeClean
+}
+
+// runRecovered runs a command and turns a panic on its goroutine into a
+// crash report in dir, returned as a *crashError. With debug set the panic
+// is not recovered, so Go prints the stack trace and exit code as usual.
+func runRecovered(cmd string, args []string, dir string, debug bool, run func() error) (err error) {
+	if !debug {
+		defer func() {
+			if value := recover(); value != nil {
+				path, writeErr := writeCrashReport(dir, cmd, args, value, runtimedebug.Stack())
+				if writeErr != nil {
+					// The stack trace is all the report would have added
+					fmt.Fprintf(os.Stderr, "%s", runtimedebug.Stack())
+				}
+				err = &crashError{value: value, path: path}
+			}
+		}()
+	}
+	return run()
+}
+
+// writeCrashReport writes the crash report of a panic to
+// dir/crash-<timestamp>.log and returns its path. Arguments naming paths
+// are redacted, so the report can be attached to a public issue.
+func writeCrashReport(dir, cmd string, args []string, value interface{}, stack []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crash report directory: %w", err)
+	}
+	now := time.Now().UTC()
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%09d.log", now.Format("20060102T150405Z"), now.Nanosecond()))
+
+	var sb strings.Builder
+	sb.WriteString("RepoDoctor crash report\n")
+	sb.WriteString(fmt.Sprintf("Time:    %s\n", now.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Version: %s\n", version))
+	sb.WriteString(fmt.Sprintf("Go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH))
+	sb.WriteString(fmt.Sprintf("Command: %s\n", cmd))
+	sb.WriteString(fmt.Sprintf("Args:    %s\n", strings.Join(redactCrashArgs(args), " ")))
+	sb.WriteString(fmt.Sprintf("Panic:   %v\n\n", value))
+	sb.Write(stack)
+
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// redactCrashArgs replaces the arguments and flag values that look like
+// paths with <path>; flag names and other values are kept
+func redactCrashArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if name, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(name, "-") {
+			if looksLikePath(value) {
+				arg = name + "=<path>"
+			}
+		} else if !strings.HasPrefix(arg, "-") && looksLikePath(arg) {
+			arg = "<path>"
+		}
+		redacted[i] = arg
+	}
+	return redacted
+}
+
+// looksLikePath reports whether an argument names a file or directory: it
+// holds a path separator, is . or .., or exists
+func looksLikePath(arg string) bool {
+	if arg == "" {
+		return false
+	}
+	if arg == "." || arg == ".." || strings.ContainsAny(arg, `/\`) {
+		return true
+	}
+	_, err := os.Stat(arg)
+	return err == nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
+)
+
+// panickingRule panics when evaluated, like a rule with a bug
+type panickingRule struct{}
+
+func (panickingRule) ID() string               { return "rule.panicking" }
+func (panickingRule) Category() string         { return "testing" }
+func (panickingRule) Severity() model.Severity { return model.SeverityWarning }
+func (panickingRule) Evaluate(rules.AnalysisContext) []model.Violation {
+	panic("rule exploded")
+}
+
+// runPanickingRule evaluates every registered rule outside the engine,
+// which would recover the panic itself
+func runPanickingRule(string, []string) error {
+	registry := rules.NewRuleRegistry()
+	registry.MustRegister(panickingRule{})
+	for _, rule := range registry.GetAll() {
+		rule.Evaluate(rules.AnalysisContext{})
+	}
+	return nil
+}
+
+func useCrashReportDir(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), ".repodoctor")
+	previous := crashReportDir
+	crashReportDir = dir
+	t.Cleanup(func() { crashReportDir = previous })
+	return dir
+}
+
+func TestRunCLI_PanicWritesCrashReport(t *testing.T) {
+	dir := useCrashReportDir(t)
+	secret := filepath.Join(t.TempDir(), "customer-repo")
+
+	if code := runCLI("analyze", []string{"-path", secret, "-format=json", "-base-path=" + secret}, runPanickingRule); code != exitCodeInternalError {
+		t.Fatalf("expected exit code %d, got %d", exitCodeInternalError, code)
+	}
+
+	reports, err := filepath.Glob(filepath.Join(dir, "crash-*.log"))
+	if err != nil || len(reports) != 1 {
+		t.Fatalf("expected one crash report in %s, got %v (%v)", dir, reports, err)
+	}
+	data, err := os.ReadFile(reports[0])
+	if err != nil {
+		t.Fatalf("failed to read crash report: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{"Version: " + version, "Command: analyze", "Args:    -path <path> -format=json -base-path=<path>", "Panic:   rule exploded", "panickingRule.Evaluate"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected crash report to contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, secret) {
+		t.Errorf("expected paths to be redacted, got:\n%s", content)
+	}
+}
+
+func TestRunCLI_ExitCodes(t *testing.T) {
+	useCrashReportDir(t)
+	if code := runCLI("version", nil, func(string, []string) error { return nil }); code != 0 {
+		t.Errorf("expected 0 on success, got %d", code)
+	}
+	if code := runCLI("analyze", nil, func(string, []string) error { return HandleCLIUsageError("bad flag", nil) }); code != exitCodeFailure {
+		t.Errorf("expected %d on a command error, got %d", exitCodeFailure, code)
+	}
+}
+
+func TestRunCLI_DebugDisablesRecovery(t *testing.T) {
+	dir := useCrashReportDir(t)
+	var seen []string
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected -debug to let the panic through")
+		}
+		if len(seen) != 1 || seen[0] != "-verbose" {
+			t.Errorf("expected -debug to be removed from the command's args, got %v", seen)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("expected no crash report with -debug, got %v", err)
+		}
+	}()
+
+	runCLI("analyze", []string{"-debug", "-verbose"}, func(cmd string, args []string) error {
+		seen = args
+		return runPanickingRule(cmd, args)
+	})
+}
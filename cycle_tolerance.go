@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleFingerprint identifies a cycle by its member set, so the same cycle
+// keeps its fingerprint whatever node the walk starts at
+type CycleFingerprint struct {
+	Fingerprint string   `json:"fingerprint"`
+	Path        []string `json:"path"`
+}
+
+// CycleTolerance records how the cycles of a run matched the fingerprints
+// recorded in circular.baseline
+type CycleTolerance struct {
+	MaxAllowed int                `json:"maxAllowed"`
+	Baselined  []CycleFingerprint `json:"baselined"`
+	New        []CycleFingerprint `json:"new"`
+}
+
+// Tolerated reports whether the cycles may pass the run: none is new and
+// no more than MaxAllowed are baselined. Tolerated cycles are still
+// reported and penalized.
+func (t *CycleTolerance) Tolerated() bool {
+	return t != nil && len(t.New) == 0 && len(t.Baselined) <= t.MaxAllowed
+}
+
+// cycleFingerprint returns the fingerprint diff also uses for a cycle
+func cycleFingerprint(path []string) string {
+	return violationFingerprint(compareKindCircular, cycleIdentity(path))
+}
+
+// cycleIdentity joins the sorted members of a cycle
+func cycleIdentity(path []string) string {
+	members := append([]string(nil), path...)
+	sort.Strings(members)
+	return strings.Join(members, ",")
+}
+
+// evaluateCycleTolerance matches every cycle of the report against the
+// configured baseline. Fingerprints are taken over paths relative to
+// absPath, so they do not depend on where the repository is checked out.
+// It returns nil when there are no cycles.
+func evaluateCycleTolerance(cycles []CycleViolation, absPath string, cfg *Config) *CycleTolerance {
+	if len(cycles) == 0 {
+		return nil
+	}
+	tolerance := &CycleTolerance{Baselined: []CycleFingerprint{}, New: []CycleFingerprint{}}
+	baseline := make(map[string]bool)
+	if cfg != nil && cfg.Circular != nil {
+		tolerance.MaxAllowed = cfg.Circular.MaxAllowed
+		for _, fingerprint := range cfg.Circular.Baseline {
+			baseline[fingerprint] = true
+		}
+	}
+	for _, v := range cycles {
+		entry := CycleFingerprint{Path: make([]string, len(v.Path))}
+		for i, node := range v.Path {
+			entry.Path[i] = relativeToBase(node, absPath)
+		}
+		entry.Fingerprint = cycleFingerprint(entry.Path)
+		if baseline[entry.Fingerprint] {
+			tolerance.Baselined = append(tolerance.Baselined, entry)
+		} else {
+			tolerance.New = append(tolerance.New, entry)
+		}
+	}
+	return tolerance
+}
+
+// formatCycleTolerance renders the baseline match for verbose output, with
+// the fingerprints to record for new cycles
+func formatCycleTolerance(tolerance *CycleTolerance) string {
+	if tolerance == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(ColorInfo("Cycle baseline: ") + fmt.Sprintf("%d baselined (max allowed %d), %d new\n", len(tolerance.Baselined), tolerance.MaxAllowed, len(tolerance.New)))
+	for _, cycle := range tolerance.New {
+		sb.WriteString(fmt.Sprintf("  new %s  %s\n", cycle.Fingerprint, formatCyclePath(cycle.Path)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// cycleToleranceReport analyzes a module with three independent two-file
+// cycles, a.go-b.go, c.go-d.go and e.go-f.go
+func cycleToleranceReport(t *testing.T, circular *CircularConfig) *StructuralReport {
+	t.Helper()
+	dir := t.TempDir()
+	graph := NewDependencyGraph()
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("package p\n"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		graph.AddNode(filepath.Join(dir, name))
+	}
+	for _, pair := range [][2]string{{"a.go", "b.go"}, {"c.go", "d.go"}, {"e.go", "f.go"}} {
+		graph.AddEdge(filepath.Join(dir, pair[0]), filepath.Join(dir, pair[1]))
+		graph.AddEdge(filepath.Join(dir, pair[1]), filepath.Join(dir, pair[0]))
+	}
+
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	cfg.Circular = circular
+	summary := runInternalRulePipeline(dir, graph, cfg, nil, nil)
+	report := buildReportFromRuleViolations(dir, version, cfg, summary.result.Violations)
+	report.Metrics.Cycles = evaluateCycleTolerance(report.Circular, dir, cfg)
+	if len(report.Circular) != 3 {
+		t.Fatalf("expected 3 cycles, got %d", len(report.Circular))
+	}
+	return report
+}
+
+func TestCycleTolerance_BaselinedCyclesPassWithinMaxAllowed(t *testing.T) {
+	baseline := []string{cycleFingerprint([]string{"a.go", "b.go"}), cycleFingerprint([]string{"d.go", "c.go"}), cycleFingerprint([]string{"e.go", "f.go"})}
+	report := cycleToleranceReport(t, &CircularConfig{MaxAllowed: 3, Baseline: baseline})
+
+	if code := determineExitCode(report); code != 0 {
+		t.Fatalf("expected baselined cycles to pass, got exit code %d", code)
+	}
+	if report.Score.CircularCount != 3 || report.Score.CircularPenalty == 0 {
+		t.Fatalf("tolerated cycles must still be reported and penalized, got %+v", report.Score)
+	}
+}
+
+func TestCycleTolerance_NewCycleFailsRegardlessOfCount(t *testing.T) {
+	baseline := []string{cycleFingerprint([]string{"a.go", "b.go"}), cycleFingerprint([]string{"c.go", "d.go"})}
+	report := cycleToleranceReport(t, &CircularConfig{MaxAllowed: 10, Baseline: baseline})
+
+	if code := determineExitCode(report); code != exitCodeViolations {
+		t.Fatalf("expected a cycle outside the baseline to fail the run, got exit code %d", code)
+	}
+	tolerance := report.Metrics.Cycles
+	if len(tolerance.Baselined) != 2 || len(tolerance.New) != 1 {
+		t.Fatalf("expected 2 baselined and 1 new cycle, got %+v", tolerance)
+	}
+	if got := tolerance.New[0]; got.Fingerprint != cycleFingerprint([]string{"e.go", "f.go"}) {
+		t.Fatalf("expected e.go-f.go to be the new cycle, got %+v", got)
+	}
+}
+
+func TestCycleTolerance_MoreBaselinedCyclesThanAllowedFail(t *testing.T) {
+	baseline := []string{cycleFingerprint([]string{"a.go", "b.go"}), cycleFingerprint([]string{"c.go", "d.go"}), cycleFingerprint([]string{"e.go", "f.go"})}
+	report := cycleToleranceReport(t, &CircularConfig{MaxAllowed: 2, Baseline: baseline})
+
+	if code := determineExitCode(report); code != exitCodeViolations {
+		t.Fatalf("expected 3 baselined cycles over max_allowed 2 to fail, got exit code %d", code)
+	}
+}
+
+func TestCycleTolerance_ZeroToleranceByDefault(t *testing.T) {
+	for name, circular := range map[string]*CircularConfig{
+		"no config":         nil,
+		"baseline only":     {Baseline: []string{cycleFingerprint([]string{"a.go", "b.go"})}},
+		"max allowed alone": {MaxAllowed: 5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if code := determineExitCode(cycleToleranceReport(t, circular)); code != exitCodeViolations {
+				t.Fatalf("expected cycles to fail the run, got exit code %d", code)
+			}
+		})
+	}
+}
+
+func TestCycleFingerprint_MatchesDiffFingerprint(t *testing.T) {
+	report := &StructuralReport{Circular: []CycleViolation{{Path: []string{"b.go", "a.go"}}}}
+	for fingerprint := range indexReportViolations(report) {
+		if fingerprint != cycleFingerprint([]string{"a.go", "b.go"}) {
+			t.Fatalf("expected diff and baseline to share the cycle fingerprint, got %s", fingerprint)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// ViolationDensity is the severity-weighted number of violations per
+// thousand lines of code, so repositories of different sizes compare
+type ViolationDensity struct {
+	Lines              int     `json:"lines"`
+	WeightedViolations float64 `json:"weightedViolations"`
+	PerKLOC            float64 `json:"perKloc"`
+}
+
+// defaultDensityWeights weigh the severity tiers of the report sections:
+// cycles are critical, layer violations high, god objects medium and size
+// violations low
+var defaultDensityWeights = DensityWeightsConfig{Critical: 10, High: 5, Medium: 3, Low: 1}
+
+// densityWeightsFromConfig returns the default density weights overridden
+// by scoring.density_weights
+func densityWeightsFromConfig(cfg *Config) DensityWeightsConfig {
+	weights := defaultDensityWeights
+	if cfg == nil || cfg.Scoring == nil || cfg.Scoring.DensityWeights == nil {
+		return weights
+	}
+	configured := cfg.Scoring.DensityWeights
+	if configured.Critical != 0 {
+		weights.Critical = configured.Critical
+	}
+	if configured.High != 0 {
+		weights.High = configured.High
+	}
+	if configured.Medium != 0 {
+		weights.Medium = configured.Medium
+	}
+	if configured.Low != 0 {
+		weights.Low = configured.Low
+	}
+	return weights
+}
+
+func validateDensityWeights(weights *DensityWeightsConfig) error {
+	if weights == nil {
+		return nil
+	}
+	if weights.Critical < 0 || weights.High < 0 || weights.Medium < 0 || weights.Low < 0 {
+		return fmt.Errorf("scoring.density_weights must be non-negative, got: %+v", *weights)
+	}
+	return nil
+}
+
+// computeViolationDensity weighs the structural violations of report by
+// severity and divides them by lines/1000. A report over no lines has a
+// density of 0.
+func computeViolationDensity(report *StructuralReport, lines int, weights DensityWeightsConfig) *ViolationDensity {
+	density := &ViolationDensity{
+		Lines: lines,
+		WeightedViolations: float64(len(report.Circular))*weights.Critical +
+			float64(len(report.Layer)+len(report.OptIn.FeatureIsolation)+len(report.OptIn.DependencyCap))*weights.High +
+			float64(len(report.GodObject))*weights.Medium +
+			float64(len(report.Size))*weights.Low,
+	}
+	if lines > 0 {
+		density.PerKLOC = density.WeightedViolations / (float64(lines) / 1000)
+	}
+	return density
+}
+
+// countRepositoryLines returns the number of lines of files
+func countRepositoryLines(files []rules.RepositoryFile) int {
+	lines := 0
+	for _, file := range files {
+		if file.Content == "" {
+			continue
+		}
+		lines += strings.Count(file.Content, "\n")
+		if !strings.HasSuffix(file.Content, "\n") {
+			lines++
+		}
+	}
+	return lines
+}
+
+// formatDensityLine renders the density shown below the score
+func formatDensityLine(density *ViolationDensity) string {
+	return fmt.Sprintf("Density: %.2f weighted violations / KLOC (%d lines)\n", density.PerKLOC, density.Lines)
+}
+
+// formatTargetRollup ranks the reports of a multi-target run twice: by
+// violation density, lowest first, and by score, highest first. Paths are
+// relative to basePath when set.
+func formatTargetRollup(reports []*StructuralReport, basePath string) string {
+	if len(reports) == 0 {
+		return ""
+	}
+	byScore := append([]*StructuralReport(nil), reports...)
+	sort.SliceStable(byScore, func(i, j int) bool {
+		return reportTotalScore(byScore[i]) > reportTotalScore(byScore[j])
+	})
+	scoreRank := make(map[*StructuralReport]int, len(byScore))
+	for i, report := range byScore {
+		scoreRank[report] = i + 1
+	}
+	byDensity := append([]*StructuralReport(nil), reports...)
+	sort.SliceStable(byDensity, func(i, j int) bool {
+		return reportDensity(byDensity[i]) < reportDensity(byDensity[j])
+	})
+
+	var sb strings.Builder
+	sb.WriteString("\nAggregate ranking (by density, lowest first)\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-10s %-8s %-10s %s\n", "#", "Density", "Score", "Score #", "Path"))
+	for i, report := range byDensity {
+		path := report.Path
+		if basePath != "" {
+			path = relativeToBase(path, basePath)
+		}
+		sb.WriteString(fmt.Sprintf("%-4d %-10.2f %-8.1f %-10d %s\n", i+1, reportDensity(report), reportTotalScore(report), scoreRank[report], path))
+	}
+	return sb.String()
+}
+
+func reportDensity(report *StructuralReport) float64 {
+	if report.Metrics.Density == nil {
+		return 0
+	}
+	return report.Metrics.Density.PerKLOC
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComputeViolationDensity_NormalizesByRepositorySize(t *testing.T) {
+	violations := &StructuralReport{
+		Circular:  []CycleViolation{{Path: []string{"a.go", "b.go"}}},
+		Layer:     []LayerViolation{{From: "a.go"}},
+		Size:      []SizeViolation{{File: "a.go"}, {File: "b.go"}},
+		GodObject: []GodObjectViolation{{File: "b.go"}},
+	}
+	weights := densityWeightsFromConfig(nil)
+
+	small := computeViolationDensity(violations, 2000, weights)
+	large := computeViolationDensity(violations, 200000, weights)
+	if small.WeightedViolations != 20 || large.WeightedViolations != 20 {
+		t.Fatalf("expected 10+5+3+2*1 weighted violations, got %.1f and %.1f", small.WeightedViolations, large.WeightedViolations)
+	}
+	if small.PerKLOC != 10 || large.PerKLOC != 0.1 {
+		t.Fatalf("expected the same violations to weigh 100x less in a 100x larger repo, got %.2f and %.2f", small.PerKLOC, large.PerKLOC)
+	}
+	if empty := computeViolationDensity(violations, 0, weights); empty.PerKLOC != 0 {
+		t.Fatalf("expected no density without lines, got %.2f", empty.PerKLOC)
+	}
+}
+
+func TestDensityWeightsFromConfig_OverridesConfiguredTiers(t *testing.T) {
+	cfg := &Config{Scoring: &ScoringConfig{DensityWeights: &DensityWeightsConfig{Low: 2}}}
+	if got := densityWeightsFromConfig(cfg); got != (DensityWeightsConfig{Critical: 10, High: 5, Medium: 3, Low: 2}) {
+		t.Fatalf("expected only the low tier to change, got %+v", got)
+	}
+	if err := validateScoringConfig(&ScoringConfig{DensityWeights: &DensityWeightsConfig{High: -1}}); err == nil {
+		t.Fatal("expected a negative density weight to be rejected")
+	}
+}
+
+func TestAnalyze_ReportsDensityOfFixturesOfDifferentSizes(t *testing.T) {
+	bigFile := "package big\n" + strings.Repeat("// filler\n", 599)
+	small := map[string]string{"big.go": bigFile}
+	large := map[string]string{"big.go": bigFile, "other.go": "package big\n" + strings.Repeat("// filler\n", 399)}
+
+	densities := make([]*ViolationDensity, 0, 2)
+	for _, files := range []map[string]string{small, large} {
+		report, _ := analyzeFixture(t, files, AnalyzeOptions{NoLargest: true})
+		if report.Metrics.Density == nil {
+			t.Fatalf("expected a report with a density for %d files", len(files))
+		}
+		densities = append(densities, report.Metrics.Density)
+	}
+	if densities[0].Lines != 600 || densities[0].PerKLOC != 1/0.6 {
+		t.Fatalf("expected one low violation over 600 lines, got %+v", densities[0])
+	}
+	if densities[1].Lines != 1000 || densities[1].PerKLOC != 1 {
+		t.Fatalf("expected one low violation over 1000 lines, got %+v", densities[1])
+	}
+}
+
+func TestFormatTargetRollup_RanksByDensityAndScore(t *testing.T) {
+	reports := []*StructuralReport{
+		{Path: "/repos/huge", Score: &StructuralScore{TotalScore: 60}, Metrics: ReportMetrics{Density: &ViolationDensity{PerKLOC: 0.4}}},
+		{Path: "/repos/tiny", Score: &StructuralScore{TotalScore: 90}, Metrics: ReportMetrics{Density: &ViolationDensity{PerKLOC: 5}}},
+		{Path: "/repos/mid", Score: &StructuralScore{TotalScore: 80}, Metrics: ReportMetrics{Density: &ViolationDensity{PerKLOC: 1.5}}},
+	}
+
+	lines := strings.Split(strings.TrimSpace(formatTargetRollup(reports, "/repos")), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected a title, a header and three rows, got:\n%s", strings.Join(lines, "\n"))
+	}
+	want := [][]string{{"1", "0.40", "60.0", "3", "huge"}, {"2", "1.50", "80.0", "2", "mid"}, {"3", "5.00", "90.0", "1", "tiny"}}
+	for i, row := range lines[2:] {
+		if got := strings.Fields(row); strings.Join(got, " ") != strings.Join(want[i], " ") {
+			t.Fatalf("row %d: expected %v, got %v", i+1, want[i], got)
+		}
+	}
+}
@@ -1,6 +1,14 @@
 package main
 
-// Graph defines the interface for a directed dependency graph
+import (
+	"sort"
+
+	"RepoDoctor/internal/model"
+)
+
+// Graph defines the interface for a directed dependency graph.
+// GetDependencies and GetAllNodes return names sorted, so what is built from
+// them does not depend on the order nodes and edges were added in.
 type Graph interface {
 	AddNode(name string)
 	AddEdge(from, to string)
@@ -9,19 +17,33 @@ type Graph interface {
 	GetAllNodes() []string
 	GetNodeCount() int
 	GetEdgeCount() int
+	GetInDegree(name string) int
+	GetOutDegree(name string) int
+}
+
+// TestEdgeGraph is an optional Graph extension for graphs that keep the
+// edges declared by test files apart from production edges. Test edges are
+// not returned by GetDependencies, counted by GetEdgeCount or followed by
+// DetectCycles.
+type TestEdgeGraph interface {
+	AddTestEdge(from, to string)
+	GetTestDependencies(name string) []string
 }
 
 // DependencyGraph implements Graph using adjacency list
 type DependencyGraph struct {
 	nodes     map[string]bool
 	adjacency map[string]map[string]bool
+	// testAdjacency holds the edges only test files declare
+	testAdjacency map[string]map[string]bool
 }
 
 // NewDependencyGraph creates a new empty dependency graph
 func NewDependencyGraph() *DependencyGraph {
 	return &DependencyGraph{
-		nodes:     make(map[string]bool),
-		adjacency: make(map[string]map[string]bool),
+		nodes:         make(map[string]bool),
+		adjacency:     make(map[string]map[string]bool),
+		testAdjacency: make(map[string]map[string]bool),
 	}
 }
 
@@ -47,28 +69,62 @@ func (g *DependencyGraph) AddEdge(from, to string) {
 	}
 }
 
-// GetDependencies returns all dependencies (outgoing edges) for a node
-func (g *DependencyGraph) GetDependencies(name string) []string {
-	neighbors := g.adjacency[name]
-	if neighbors == nil {
-		return []string{}
+// AddTestEdge adds a directed edge declared by a test file. An edge that is
+// also a production edge stays a production edge.
+func (g *DependencyGraph) AddTestEdge(from, to string) {
+	g.AddNode(from)
+	g.AddNode(to)
+	if g.testAdjacency[from] == nil {
+		g.testAdjacency[from] = make(map[string]bool)
 	}
+	g.testAdjacency[from][to] = true
+}
 
-	deps := make([]string, 0, len(neighbors))
-	for dep := range neighbors {
-		deps = append(deps, dep)
+// GetTestDependencies returns the dependencies of a node that only test
+// files declare, sorted
+func (g *DependencyGraph) GetTestDependencies(name string) []string {
+	deps := make([]string, 0, len(g.testAdjacency[name]))
+	for _, dep := range sortedKeys(g.testAdjacency[name]) {
+		if !g.adjacency[name][dep] {
+			deps = append(deps, dep)
+		}
 	}
-
 	return deps
 }
 
-// GetAllNodes returns all nodes in the graph
-func (g *DependencyGraph) GetAllNodes() []string {
-	nodes := make([]string, 0, len(g.nodes))
-	for node := range g.nodes {
-		nodes = append(nodes, node)
+// withTestEdgesAsProduction returns a copy of graph in which the test edges
+// are ordinary edges, so every rule follows them
+func withTestEdgesAsProduction(graph Graph) Graph {
+	merged := NewDependencyGraph()
+	testGraph, _ := graph.(TestEdgeGraph)
+	for _, node := range graph.GetAllNodes() {
+		merged.AddNode(node)
+		for _, dep := range graph.GetDependencies(node) {
+			merged.AddEdge(node, dep)
+		}
+		if testGraph == nil {
+			continue
+		}
+		for _, dep := range testGraph.GetTestDependencies(node) {
+			merged.AddEdge(node, dep)
+		}
+	}
+	return merged
+}
+
+// GetDependencies returns all dependencies (outgoing edges) for a node,
+// sorted
+func (g *DependencyGraph) GetDependencies(name string) []string {
+	neighbors := g.adjacency[name]
+	if len(neighbors) == 0 {
+		return []string{}
 	}
-	return nodes
+	return sortedKeys(neighbors)
+}
+
+// GetAllNodes returns all nodes in the graph, sorted
+func (g *DependencyGraph) GetAllNodes() []string {
+	return sortedKeys(g.nodes)
 }
 
 // GetNodeCount returns the number of nodes in the graph
@@ -85,8 +141,47 @@ func (g *DependencyGraph) GetEdgeCount() int {
 	return count
 }
 
+// GetInDegree returns the number of nodes that depend on a node
+func (g *DependencyGraph) GetInDegree(name string) int {
+	count := 0
+	for _, neighbors := range g.adjacency {
+		if neighbors[name] {
+			count++
+		}
+	}
+	return count
+}
+
+// GetOutDegree returns the number of dependencies of a node
+func (g *DependencyGraph) GetOutDegree(name string) int {
+	return len(g.adjacency[name])
+}
+
+// GetOrphanNodes returns the nodes no other node depends on, sorted
+func (g *DependencyGraph) GetOrphanNodes() []string {
+	imported := make(map[string]bool, len(g.nodes))
+	for from, neighbors := range g.adjacency {
+		for to := range neighbors {
+			if to != from {
+				imported[to] = true
+			}
+		}
+	}
+	orphans := make([]string, 0)
+	for node := range g.nodes {
+		if !imported[node] {
+			orphans = append(orphans, node)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
 // DetectCycles finds all cycles in the graph using DFS
-// Returns a slice of cycles, where each cycle is a slice of node names
+// Returns a slice of cycles, where each cycle is a slice of node names.
+// Only closed walks are returned: the last node depends on the first.
+// Start nodes and dependencies are visited in sorted order, so the cycles
+// and their order are the same on every run.
 func (g *DependencyGraph) DetectCycles() [][]string {
 	cycles := [][]string{}
 	visited := make(map[string]bool)
@@ -124,11 +219,13 @@ func (g *DependencyGraph) DetectCycles() [][]string {
 	}
 
 	// Run DFS from each unvisited node
-	for node := range g.nodes {
+	for _, node := range g.GetAllNodes() {
 		if !visited[node] {
 			dfs(node)
 		}
 	}
 
-	return cycles
+	return model.WellFormedCycles(cycles, func(from, to string) bool {
+		return g.adjacency[from][to]
+	})
 }
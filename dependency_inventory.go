@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// DependencyInventory lists the distinct external modules the analyzed Go
+// files import. Newest holds the modules not recorded by the previous
+// history entry; with no recorded baseline every module is new.
+type DependencyInventory struct {
+	Modules     []string `json:"externalModules"`
+	Count       int      `json:"count"`
+	MaxExternal *int     `json:"maxExternal,omitempty"`
+	Newest      []string `json:"newest"`
+	// manifest is the go.mod path cap violations are reported against
+	manifest string
+	// modulePath and requires resolve import paths to modules
+	modulePath string
+	requires   []string
+}
+
+// majorVersionRe matches a module major version suffix such as "v2"
+var majorVersionRe = regexp.MustCompile(`^v[0-9]+$`)
+
+// buildDependencyInventory collects the external module roots imported by
+// the Go files in graph. Imports are resolved against the go.mod require
+// list; imports of unlisted modules fall back to a path heuristic.
+// Standard library and own-module imports are not external.
+func buildDependencyInventory(absPath string, graph Graph, cfg *Config, previous *HistoryEntry) *DependencyInventory {
+	manifest := filepath.Join(absPath, "go.mod")
+	modulePath, requires := readGoModule(manifest)
+
+	seen := make(map[string]bool)
+	for _, node := range graph.GetAllNodes() {
+		if !strings.HasSuffix(node, ".go") {
+			continue
+		}
+		for _, imp := range graph.GetDependencies(node) {
+			if isExternalGoImport(imp, modulePath) {
+				seen[externalModuleRoot(imp, requires)] = true
+			}
+		}
+	}
+
+	inventory := &DependencyInventory{Modules: make([]string, 0, len(seen)), Newest: []string{}, manifest: manifest, modulePath: modulePath, requires: requires}
+	for module := range seen {
+		inventory.Modules = append(inventory.Modules, module)
+	}
+	sort.Strings(inventory.Modules)
+	inventory.Count = len(inventory.Modules)
+	if cfg != nil && cfg.Dependencies != nil {
+		inventory.MaxExternal = cfg.Dependencies.MaxExternal
+	}
+
+	recorded := make(map[string]bool)
+	if previous != nil {
+		for _, module := range previous.ExternalModules {
+			recorded[module] = true
+		}
+	}
+	for _, module := range inventory.Modules {
+		if !recorded[module] {
+			inventory.Newest = append(inventory.Newest, module)
+		}
+	}
+	return inventory
+}
+
+// moduleDomain returns the external module an import path belongs to, or ""
+// for standard library and own-module imports
+func (i *DependencyInventory) moduleDomain(importPath string) string {
+	if !isExternalGoImport(importPath, i.modulePath) {
+		return ""
+	}
+	return externalModuleRoot(importPath, i.requires)
+}
+
+// isExternalGoImport reports whether an import path belongs to another
+// module. Standard library paths have no dot in their first element.
+func isExternalGoImport(importPath, modulePath string) bool {
+	if modulePath != "" && (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")) {
+		return false
+	}
+	first, _, _ := strings.Cut(importPath, "/")
+	return strings.Contains(first, ".")
+}
+
+// externalModuleRoot maps an import path to its module: the longest required
+// module prefix, or else host/owner/repo (plus a major version suffix) for
+// hosts with that layout and host/name for others
+func externalModuleRoot(importPath string, requires []string) string {
+	best := ""
+	for _, module := range requires {
+		if (importPath == module || strings.HasPrefix(importPath, module+"/")) && len(module) > len(best) {
+			best = module
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	parts := strings.Split(importPath, "/")
+	depth := 2
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org", "golang.org":
+		depth = 3
+	}
+	if len(parts) > depth && majorVersionRe.MatchString(parts[depth]) {
+		depth++
+	}
+	if len(parts) < depth {
+		depth = len(parts)
+	}
+	return strings.Join(parts[:depth], "/")
+}
+
+// readGoModule returns the module path and required modules of a go.mod
+// file. A missing or unreadable file yields empty results.
+func readGoModule(path string) (modulePath string, requires []string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil
+	}
+	defer file.Close()
+
+	inRequireBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequireBlock:
+			inRequireBlock = fields[0] != ")"
+			if inRequireBlock {
+				requires = append(requires, fields[0])
+			}
+		case fields[0] == "module" && len(fields) > 1:
+			modulePath = strings.Trim(fields[1], `"`)
+		case fields[0] == "require" && len(fields) > 1:
+			if fields[1] == "(" {
+				inRequireBlock = true
+			} else {
+				requires = append(requires, fields[1])
+			}
+		}
+	}
+	return modulePath, requires
+}
+
+// previousHistoryEntry returns the newest recorded history entry, if any
+func previousHistoryEntry(absPath string) *HistoryEntry {
+	trendAnalyzer := NewTrendAnalyzer(absPath)
+	if err := trendAnalyzer.LoadHistory(); err != nil {
+		return nil
+	}
+	last, ok := trendAnalyzer.GetLastEntry()
+	if !ok {
+		return nil
+	}
+	return last
+}
+
+// newDependencyCapRule builds the dependency-cap rule from the configured
+// cap and the collected inventory; without a cap it never reports
+func newDependencyCapRule(cfg *Config, inventory *DependencyInventory) *rules.DependencyCapRule {
+	maxExternal := -1
+	if cfg != nil && cfg.Dependencies != nil && cfg.Dependencies.MaxExternal != nil {
+		maxExternal = *cfg.Dependencies.MaxExternal
+	}
+	if inventory == nil {
+		return rules.NewDependencyCapRule(maxExternal, nil, nil, "")
+	}
+	return rules.NewDependencyCapRule(maxExternal, inventory.Modules, inventory.Newest, inventory.manifest)
+}
+
+// newImportDiversityRule creates the import diversity rule from the
+// import_diversity config, resolving imports against the module inventory.
+// Without an inventory, when rules are only listed, it reports nothing.
+func newImportDiversityRule(cfg *Config, inventory *DependencyInventory) *rules.ImportDiversityRule {
+	maxDomains := defaultImportDiversityMaxDomains
+	if cfg != nil && cfg.ImportDiversity != nil && cfg.ImportDiversity.MaxDomains > 0 {
+		maxDomains = cfg.ImportDiversity.MaxDomains
+	}
+	if inventory == nil {
+		return rules.NewImportDiversityRule(maxDomains, nil)
+	}
+	return rules.NewImportDiversityRule(maxDomains, inventory.moduleDomain)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/rules"
+)
+
+const inventoryGoMod = `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+	go.uber.org/zap v1.27.0
+)
+`
+
+// inventoryFixture returns a repository with a go.mod and a graph whose Go
+// files import stdlib, own-module and four external modules
+func inventoryFixture(t *testing.T) (string, Graph) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(inventoryGoMod), 0o644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	graph := NewDependencyGraph()
+	mainFile, serverFile := filepath.Join(dir, "main.go"), filepath.Join(dir, "server", "server.go")
+	graph.AddNode(mainFile)
+	graph.AddNode(serverFile)
+	for _, imp := range []string{"fmt", "example.com/app/server", "github.com/spf13/cobra", "go.uber.org/zap/zapcore"} {
+		graph.AddEdge(mainFile, imp)
+	}
+	for _, imp := range []string{"golang.org/x/text/language", "github.com/gorilla/mux/v2/route", "go.uber.org/zap"} {
+		graph.AddEdge(serverFile, imp)
+	}
+	return dir, graph
+}
+
+func dependencyCapViolations(cfg *Config, inventory *DependencyInventory) []string {
+	var messages []string
+	for _, v := range newDependencyCapRule(cfg, inventory).Evaluate(rules.AnalysisContext{}) {
+		messages = append(messages, v.Message)
+	}
+	return messages
+}
+
+func TestBuildDependencyInventory_ResolvesModuleRoots(t *testing.T) {
+	dir, graph := inventoryFixture(t)
+
+	inventory := buildDependencyInventory(dir, graph, nil, nil)
+	want := []string{"github.com/gorilla/mux/v2", "github.com/spf13/cobra", "go.uber.org/zap", "golang.org/x/text"}
+	if !reflect.DeepEqual(inventory.Modules, want) || inventory.Count != 4 {
+		t.Fatalf("unexpected inventory: %+v", inventory)
+	}
+	// Without a recorded baseline every module is new
+	if !reflect.DeepEqual(inventory.Newest, want) {
+		t.Fatalf("expected every module to be new, got %v", inventory.Newest)
+	}
+}
+
+func TestDependencyCap_ExceededAndMetExactly(t *testing.T) {
+	dir, graph := inventoryFixture(t)
+
+	limit := 3
+	cfg := &Config{RuleSectionsConfig: RuleSectionsConfig{Dependencies: &DependenciesConfig{MaxExternal: &limit}}}
+	inventory := buildDependencyInventory(dir, graph, cfg, nil)
+	if inventory.MaxExternal == nil || *inventory.MaxExternal != 3 {
+		t.Fatalf("expected the cap in the inventory metadata, got %+v", inventory.MaxExternal)
+	}
+	messages := dependencyCapViolations(cfg, inventory)
+	if len(messages) != 1 || !strings.HasPrefix(messages[0], "4 external modules exceed the cap of 3") {
+		t.Fatalf("expected one cap violation, got %v", messages)
+	}
+
+	limit = 4
+	if messages := dependencyCapViolations(cfg, inventory); len(messages) != 0 {
+		t.Fatalf("expected no violation at the cap, got %v", messages)
+	}
+	if ruleEnabledByConfig("rule.dependency-cap", &Config{}) || !ruleEnabledByConfig("rule.dependency-cap", cfg) {
+		t.Fatal("expected the rule to run only when max_external is set")
+	}
+}
+
+func TestDependencyCap_NewestOffendersAgainstHistory(t *testing.T) {
+	dir, graph := inventoryFixture(t)
+
+	analyzer := NewTrendAnalyzer(dir)
+	seeded := HistoryEntry{Score: 90, ExternalModules: []string{"github.com/spf13/cobra", "go.uber.org/zap"}}
+	if _, err := analyzer.RecordEntry(seeded, true); err != nil {
+		t.Fatalf("failed to seed history: %v", err)
+	}
+
+	limit := 2
+	cfg := &Config{RuleSectionsConfig: RuleSectionsConfig{Dependencies: &DependenciesConfig{MaxExternal: &limit}}}
+	inventory := buildDependencyInventory(dir, graph, cfg, previousHistoryEntry(dir))
+	if want := []string{"github.com/gorilla/mux/v2", "golang.org/x/text"}; !reflect.DeepEqual(inventory.Newest, want) {
+		t.Fatalf("expected newest %v, got %v", want, inventory.Newest)
+	}
+
+	messages := dependencyCapViolations(cfg, inventory)
+	want := "4 external modules exceed the cap of 2 (newest: github.com/gorilla/mux/v2, golang.org/x/text)"
+	if len(messages) != 1 || messages[0] != want {
+		t.Fatalf("unexpected violation: %v", messages)
+	}
+}
+
+func TestDependencyCap_ReportedAsItsOwnCategory(t *testing.T) {
+	dir, graph := inventoryFixture(t)
+
+	limit := 3
+	cfg := &Config{RuleSectionsConfig: RuleSectionsConfig{Dependencies: &DependenciesConfig{MaxExternal: &limit}}}
+	violations := newDependencyCapRule(cfg, buildDependencyInventory(dir, graph, cfg, nil)).Evaluate(rules.AnalysisContext{})
+	report := buildReportFromRuleViolations(dir, "test", cfg, violations)
+
+	if len(report.Layer) != 0 || len(report.OptIn.DependencyCap) != 1 || report.Summary.DependencyCap != 1 {
+		t.Fatalf("expected one dependency cap violation outside the layer category, got layer %+v and cap %+v", report.Layer, report.OptIn.DependencyCap)
+	}
+	if got := report.OptIn.DependencyCap[0].Manifest; got != filepath.Join(dir, "go.mod") {
+		t.Fatalf("expected the violation to name go.mod, got %q", got)
+	}
+	if report.Score.OptIn.DependencyCapPenalty != 5 || report.Score.TotalScore != 95 {
+		t.Fatalf("expected the dependency cap weight to cost 5 points, got %.1f and score %.1f", report.Score.OptIn.DependencyCapPenalty, report.Score.TotalScore)
+	}
+	if err := verifyReportInvariants(report, scoringWeightsFromConfig(cfg)); err != nil {
+		t.Fatalf("expected the report to pass self-check, got %v", err)
+	}
+	if !(ExitPolicy{}).failsOnViolations(report) {
+		t.Fatal("expected an exceeded cap to fail the run")
+	}
+}
+
+func TestImportDiversity_CountsModulesNotStandardLibrary(t *testing.T) {
+	dir, graph := inventoryFixture(t)
+	inventory := buildDependencyInventory(dir, graph, nil, nil)
+	files := buildRulesAnalysisContext(dir, graph, nil).RepositoryFiles
+
+	// main.go imports fmt, its own module and two external modules
+	cfg := &Config{RuleSectionsConfig: RuleSectionsConfig{ImportDiversity: &ImportDiversityConfig{MaxDomains: 1}}}
+	got := newImportDiversityRule(cfg, inventory).Analyze(files)
+	if len(got) != 2 || got[0].Package != filepath.ToSlash(dir) || !reflect.DeepEqual(got[0].Domains, []string{"github.com/spf13/cobra", "go.uber.org/zap"}) {
+		t.Fatalf("expected both packages flagged by module root, got %+v", got)
+	}
+	if got := newImportDiversityRule(nil, inventory).Analyze(files); len(got) != 0 {
+		t.Fatalf("expected no finding under the default of %d, got %+v", defaultImportDiversityMaxDomains, got)
+	}
+
+	enabled := true
+	if ruleEnabledByConfig("rule.import-diversity", &Config{}) || !ruleEnabledByConfig("rule.import-diversity", &Config{RuleSectionsConfig: RuleSectionsConfig{ImportDiversity: &ImportDiversityConfig{Enabled: &enabled}}}) {
+		t.Fatal("expected the rule to run only when import_diversity.enabled is set")
+	}
+}
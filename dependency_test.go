@@ -1,7 +1,12 @@
 package main
 
 import (
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"RepoDoctor/internal/model"
 )
 
 // TestDependencyGraphAcyclic tests graph with no cycles
@@ -111,6 +116,35 @@ func TestDependencyGraphMultipleCycles(t *testing.T) {
 	}
 }
 
+func TestDependencyGraphDetectCyclesIsDeterministic(t *testing.T) {
+	graph := NewDependencyGraph()
+	// Three independent cycles and one shared node with several dependencies
+	edges := [][2]string{
+		{"svc/a", "svc/b"}, {"svc/b", "svc/c"}, {"svc/c", "svc/a"},
+		{"repo/x", "repo/y"}, {"repo/y", "repo/x"},
+		{"api/h", "api/m"}, {"api/m", "api/h"}, {"api/h", "svc/a"}, {"api/h", "repo/x"},
+	}
+	for _, edge := range edges {
+		graph.AddNode(edge[0])
+		graph.AddNode(edge[1])
+		graph.AddEdge(edge[0], edge[1])
+	}
+
+	first := graph.DetectCycles()
+	if len(first) != 3 {
+		t.Fatalf("expected 3 cycles, got %v", first)
+	}
+	for i := 0; i < 10; i++ {
+		if got := graph.DetectCycles(); !reflect.DeepEqual(got, first) {
+			t.Fatalf("run %d detected %v, first run detected %v", i, got, first)
+		}
+	}
+	want := [][]string{{"api/h", "api/m"}, {"repo/x", "repo/y"}, {"svc/a", "svc/b", "svc/c"}}
+	if !reflect.DeepEqual(first, want) {
+		t.Fatalf("expected cycles in sorted visiting order %v, got %v", want, first)
+	}
+}
+
 // TestLayerValidationRuleUpwardImport tests layer violation detection
 func TestLayerValidationRuleUpwardImport(t *testing.T) {
 	graph := NewDependencyGraph()
@@ -129,7 +163,7 @@ func TestLayerValidationRuleUpwardImport(t *testing.T) {
 	// Valid: service -> repo (downward)
 	graph.AddEdge(servicePath, repoPath)
 
-	rule := NewLayerValidationRule(graph)
+	rule := NewLayerValidationRule(graph, nil)
 	hasViolations := rule.Check()
 
 	// Should have no violations for valid downward imports
@@ -150,7 +184,7 @@ func TestLayerValidationRuleRepoToService(t *testing.T) {
 	// Invalid: repo -> service (upward)
 	graph.AddEdge(repoPath, servicePath)
 
-	rule := NewLayerValidationRule(graph)
+	rule := NewLayerValidationRule(graph, nil)
 	hasViolations := rule.Check()
 
 	if !hasViolations {
@@ -186,7 +220,7 @@ func TestLayerValidationRuleServiceToHandler(t *testing.T) {
 	// Invalid: service -> handler (upward)
 	graph.AddEdge(servicePath, handlerPath)
 
-	rule := NewLayerValidationRule(graph)
+	rule := NewLayerValidationRule(graph, nil)
 	hasViolations := rule.Check()
 
 	if !hasViolations {
@@ -194,6 +228,85 @@ func TestLayerValidationRuleServiceToHandler(t *testing.T) {
 	}
 }
 
+// TestLayerValidationRuleCustomLayers tests a configured four-layer hierarchy
+func TestLayerValidationRuleCustomLayers(t *testing.T) {
+	layers := &LayersConfig{
+		Levels: []LayerLevelConfig{
+			{Name: "controller", Keywords: []string{"controller"}},
+			{Name: "usecase", Keywords: []string{"usecase"}},
+			{Name: "domain", Keywords: []string{"domain"}},
+			{Name: "gateway", Keywords: []string{"gateway", "infra"}},
+		},
+		Exempt: []string{"testutil"},
+	}
+
+	graph := NewDependencyGraph()
+	// Valid: downward imports, skipping layers included
+	graph.AddEdge("app/controller/user.go", "app/usecase/user.go")
+	graph.AddEdge("app/usecase/user.go", "app/infra/db.go")
+	// Invalid: gateway -> domain and domain -> controller (upward)
+	graph.AddEdge("app/gateway/user.go", "app/domain/user.go")
+	graph.AddEdge("app/domain/user.go", "app/controller/user.go")
+	// Unchecked: no default layer, and exempt paths
+	graph.AddEdge("app/util/strings.go", "app/controller/user.go")
+	graph.AddEdge("app/testutil/fake.go", "app/controller/user.go")
+	// The built-in handler layer means nothing here
+	graph.AddEdge("app/repo/user.go", "app/handler/user.go")
+
+	rule := NewLayerValidationRule(graph, layers)
+	rule.Check()
+
+	violations := rule.Violations()
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", violations)
+	}
+	got := map[string]string{}
+	for _, v := range violations {
+		got[v.From] = v.Message
+	}
+	if msg := got["app/gateway/user.go"]; msg != "app/gateway/user.go (gateway) -> app/domain/user.go (domain): upward import not allowed" {
+		t.Errorf("Unexpected gateway -> domain message: %q", msg)
+	}
+	if _, ok := got["app/domain/user.go"]; !ok {
+		t.Errorf("Expected a domain -> controller violation, got %v", violations)
+	}
+}
+
+// TestLayerValidationRuleDefaultLayers tests that an unconfigured rule keeps
+// the handler -> service -> repo hierarchy with service as the fallback
+func TestLayerValidationRuleDefaultLayers(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddEdge("app/util/strings.go", "app/handler/user.go")
+
+	rule := NewLayerValidationRule(graph, &LayersConfig{})
+	if !rule.Check() {
+		t.Error("Expected an unmatched path to count as service and violate service -> handler")
+	}
+}
+
+// TestMatchLayerPathSegments tests that layer keywords only match whole
+// path segments
+func TestMatchLayerPathSegments(t *testing.T) {
+	tests := []struct {
+		path  string
+		layer LayerConvention
+		ok    bool
+	}{
+		{path: "internal/reporting/service.go", layer: LayerService, ok: true},
+		{path: "internal/repo", layer: LayerRepo, ok: true},
+		{path: `project\handler\user.go`, layer: LayerHandler, ok: true},
+		{path: "pkg/reposcan/x.go", ok: false},
+		{path: "service_impl/x.go", ok: false},
+		{path: "myrepo/service_helpers/x.go", ok: false},
+	}
+	for _, tc := range tests {
+		layer, ok := defaultLayerHierarchy.matchLayer(tc.path)
+		if layer != tc.layer || ok != tc.ok {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tc.path, tc.layer, tc.ok, layer, ok)
+		}
+	}
+}
+
 // TestStructuralScoringCircularPenalty tests scoring with circular dependencies
 func TestStructuralScoringCircularPenalty(t *testing.T) {
 	graph := NewDependencyGraph()
@@ -292,7 +405,7 @@ func TestCircularDependencyRuleCriticalSeverity(t *testing.T) {
 
 	rule := NewCircularDependencyRule(graph)
 
-	if rule.Severity() != "critical" {
+	if rule.Severity() != model.SeverityCritical {
 		t.Errorf("Expected circular dependency severity to be 'critical', got '%s'", rule.Severity())
 	}
 }
@@ -336,3 +449,115 @@ func TestGraphInterface(t *testing.T) {
 		t.Errorf("Expected dependency to be 'node2', got '%s'", deps[0])
 	}
 }
+
+// TestDependencyGraphCyclesAreClosedWalks builds cycles sharing sub-paths,
+// plus a self-import, and checks every reported cycle closes on itself
+func TestDependencyGraphCyclesAreClosedWalks(t *testing.T) {
+	graph := NewDependencyGraph()
+	edges := [][2]string{
+		{"A", "B"}, {"B", "C"}, {"C", "D"}, {"D", "B"}, // B -> C -> D -> B
+		{"C", "A"},             // A -> B -> C -> A shares B -> C
+		{"D", "E"}, {"E", "C"}, // C -> D -> E -> C shares C -> D
+		{"E", "E"}, // self-import
+		{"F", "D"}, {"F", "A"},
+	}
+	for _, edge := range edges {
+		graph.AddEdge(edge[0], edge[1])
+	}
+
+	for run := 0; run < 20; run++ {
+		cycles := graph.DetectCycles()
+		if len(cycles) == 0 {
+			t.Fatal("expected cycles")
+		}
+		for _, cycle := range cycles {
+			for i, node := range cycle {
+				next := cycle[(i+1)%len(cycle)]
+				if !graph.adjacency[node][next] {
+					t.Fatalf("cycle %v is not a closed walk: %s does not depend on %s", cycle, node, next)
+				}
+			}
+		}
+	}
+}
+
+// testOnlyCycleGraph builds a model graph where a.go and b.go only form a
+// cycle through the imports of b_test.go
+func testOnlyCycleGraph(dir string) *model.DependencyGraph {
+	a, b, bTest := filepath.Join(dir, "a.go"), filepath.Join(dir, "b.go"), filepath.Join(dir, "b_test.go")
+	languageGraph := model.NewDependencyGraph()
+	languageGraph.AddEdge(a, b)
+	languageGraph.AddEdge(b, bTest)
+	testNode := languageGraph.AddNode(bTest, bTest, "fixture")
+	testNode.Metadata[model.ProvenanceMetadataKey] = string(model.EdgeFromTest)
+	languageGraph.AddEdge(bTest, a)
+	return languageGraph
+}
+
+func TestTestOnlyCycle_ExcludedByDefault(t *testing.T) {
+	dir := t.TempDir()
+	graph := buildDependencyGraphFromModel(testOnlyCycleGraph(dir), false)
+	if graph.GetEdgeCount() != 2 || len(graph.DetectCycles()) != 0 {
+		t.Fatalf("expected 2 production edges and no cycle, got %d edges and %v", graph.GetEdgeCount(), graph.DetectCycles())
+	}
+
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	summary := runInternalRulePipeline(dir, graph, cfg, nil, nil)
+	report := buildReportFromRuleViolations(dir, version, cfg, summary.result.Violations)
+	if len(report.Circular) != 0 {
+		t.Fatalf("expected the test-only cycle to be excluded, got %+v", report.Circular)
+	}
+	if len(report.Advisory) != 1 || report.Advisory[0].RuleID != "rule.test-only-cycle" {
+		t.Fatalf("expected one test-only cycle advisory, got %+v", report.Advisory)
+	}
+	if report.HasViolations {
+		t.Fatal("expected the test-only cycle not to count as a violation")
+	}
+}
+
+func TestTestOnlyCycle_IncludedWithFlag(t *testing.T) {
+	req, err := parseAnalyzeFlags([]string{"-include-test-edges", "."})
+	if err != nil || !req.Options.IncludeTestEdges {
+		t.Fatalf("expected -include-test-edges to be parsed, got %v", err)
+	}
+
+	dir := t.TempDir()
+	graph := withTestEdgesAsProduction(buildDependencyGraphFromModel(testOnlyCycleGraph(dir), false))
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	summary := runInternalRulePipeline(dir, graph, cfg, nil, nil)
+	report := buildReportFromRuleViolations(dir, version, cfg, summary.result.Violations)
+	if len(report.Circular) != 1 {
+		t.Fatalf("expected the cycle to be reported with test edges included, got %+v", report.Circular)
+	}
+	if len(report.Advisory) != 0 {
+		t.Fatalf("expected no test-only advisory once test edges are production edges, got %+v", report.Advisory)
+	}
+}
+
+// TestStructuralScoringRespectsRuleToggles tests that disabled rules neither
+// count violations nor cost points
+func TestStructuralScoringRespectsRuleToggles(t *testing.T) {
+	dir := t.TempDir()
+	writeServiceFixture(t, dir, map[string]string{"big.go": "package big\n" + strings.Repeat("// filler\n", 600)})
+
+	graph := NewDependencyGraph()
+	graph.AddEdge("A", "B")
+	graph.AddEdge("B", "A")
+
+	config := (&ConfigLoader{}).getDefaultConfig()
+	if score := NewStructuralScorer(graph, config, dir).CalculateScore(); score.SizeCount != 1 || score.CircularCount != 1 {
+		t.Fatalf("expected the enabled rules to find the 600-line file and the cycle, got %+v", score)
+	}
+
+	disabled := false
+	config.Rules.EnableSizeRule = &disabled
+	config.Rules.EnableCircularRule = &disabled
+	scorer := NewStructuralScorer(graph, config, dir)
+	score := scorer.CalculateScore()
+	if score.SizeCount != 0 || score.SizePenalty != 0 || score.CircularCount != 0 || score.CircularPenalty != 0 {
+		t.Fatalf("expected disabled rules to count nothing, got %+v", score)
+	}
+	if score.TotalScore != score.MaxScore || scorer.HasCriticalViolations() {
+		t.Fatalf("expected a perfect score without critical violations, got %.1f", score.TotalScore)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+)
+
+// deterministicTime is the time -deterministic runs stamp on history
+// entries and latest.json
+var deterministicTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// runClock returns the clock a run stamps its outputs with: the fixed
+// deterministicTime with -deterministic, the wall clock otherwise
+func runClock(deterministic bool) func() time.Time {
+	if deterministic {
+		return func() time.Time { return deterministicTime }
+	}
+	return time.Now
+}
+
+// sortedKeys returns the keys of m in ascending order. Code that builds
+// output from a map iterates it through sortedKeys, since map iteration
+// order changes between runs.
+func sortedKeys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// canonicalizeReport puts a -deterministic report into a form that only
+// depends on the analyzed files: every violation list is sorted with the
+// ordering helpers and rule durations are zeroed. Rules that ran keep
+// their duration entry.
+func canonicalizeReport(report *StructuralReport) {
+	*report = *sortedReport(report)
+	for _, id := range sortedKeys(report.Metrics.RuleDurations) {
+		report.Metrics.RuleDurations[id] = 0
+	}
+}
+
+// sortedReport returns a copy of report with every violation list in the
+// shared stable order the ordering helpers define. Reporters list
+// violations from it, so their order never depends on how the rules
+// produced them.
+func sortedReport(report *StructuralReport) *StructuralReport {
+	sorted := *report
+	findings := newReportFindings(report).sorted()
+	sorted.Circular, sorted.Layer, sorted.Size, sorted.GodObject = findings.Circular, findings.Layer, findings.Size, findings.GodObject
+	sorted.OptIn.FeatureIsolation, sorted.OptIn.DependencyCap = findings.FeatureIsolation, findings.DependencyCap
+	sorted.Advisory = sortedAdvisory(report.Advisory)
+	sorted.OptIn.SingleImpl = sortedSingleImpl(report.OptIn.SingleImpl)
+	return &sorted
+}
+
+func sortedAdvisory(in []AdvisoryViolation) []AdvisoryViolation {
+	result := append([]AdvisoryViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].RuleID != result[j].RuleID {
+			return result[i].RuleID < result[j].RuleID
+		}
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Message < result[j].Message
+	})
+	return result
+}
+
+func sortedSingleImpl(in []SingleImplInterfaceViolation) []SingleImplInterfaceViolation {
+	result := append([]SingleImplInterfaceViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Interface != result[j].Interface {
+			return result[i].Interface < result[j].Interface
+		}
+		return result[i].Impl < result[j].Impl
+	})
+	return result
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// determinismFormats are the machine-readable formats the determinism suite
+// checks. A new format is only done once it is listed here.
+var determinismFormats = []OutputFormat{FormatJSON, FormatJSONV1, FormatJSONLegacy, FormatEnv, FormatFixPlan, FormatSARIF, FormatJUnit, FormatHTML, FormatTree, FormatTreeJSON, FormatMermaid, FormatCheckstyle, FormatMarkdown, FormatJSONL, FormatNDJSON, FormatTAP, FormatBadge}
+
+// determinismFixture is a mixed-language repository with violations of
+// every structural category and the opt-in rules enabled
+func determinismFixture() map[string]string {
+	fields := func(prefix string, n int) string {
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			sb.WriteString("\t" + prefix + string(rune('A'+i)) + " int\n")
+		}
+		return sb.String()
+	}
+	methods := func(receiver string, n int) string {
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			sb.WriteString("func (s *" + receiver + ") M" + string(rune('A'+i)) + "() {}\n")
+		}
+		return sb.String()
+	}
+	return map[string]string{
+		"go.mod":                      "module example.com/fixture\n\ngo 1.24\n",
+		".repodoctor/config.yaml":     "entrypoint_only:\n  enabled: true\nfeature_isolation:\n  enabled: true\ncohesion:\n  enabled: true\nsingle_impl_interface:\n  enabled: true\nimport_diversity:\n  enabled: true\nignored_error:\n  enabled: true\ndependencies:\n  max_external: 5\n",
+		"cmd/tool/main.go":            "package main\n\nimport \"example.com/fixture/internal/handler\"\n\nfunc main() { handler.Serve() }\n",
+		"internal/handler/handler.go": "package handler\n\nimport \"example.com/fixture/internal/service\"\n\nfunc Serve() { service.Run() }\n",
+		"internal/service/service.go": "package service\n\nimport \"example.com/fixture/internal/repository\"\n\ntype Store interface{ Load() }\n\ntype sqlStore struct{}\n\nfunc (sqlStore) Load() {}\n\nfunc Run() { repository.Find() }\n",
+		"internal/repository/repo.go": "package repository\n\nimport \"example.com/fixture/internal/handler\"\n\nfunc Find() { handler.Serve() }\n",
+		"internal/big/big.go":         "package big\n\nfunc Long() {\n" + strings.Repeat("\t_ = 1\n", 90) + "}\n" + strings.Repeat("// filler\n", 420),
+		"internal/big/ledger.go":      "package big\n\ntype Ledger struct {\n" + fields("L", 17) + "}\n\n" + methods("Ledger", 11),
+		"internal/big/account.go":     "package big\n\ntype Account struct {\n" + fields("A", 16) + "}\n",
+		"scripts/a.py":                "import b\n\ndef run():\n    b.run()\n",
+		"scripts/b.py":                "import a\n\ndef run():\n    a.run()\n",
+		"web/app.ts":                  "import { view } from './view';\n\nexport const app = () => view();\n",
+		"web/view.ts":                 "import { app } from './app';\n\nexport const view = () => app;\n",
+	}
+}
+
+// writeFixtureInOrder writes files in the given order, so the directory is
+// populated differently while holding the same content
+func writeFixtureInOrder(t *testing.T, root string, files map[string]string, order []string) {
+	t.Helper()
+	for _, name := range order {
+		writeServiceFixture(t, root, map[string]string{name: files[name]})
+	}
+}
+
+// runDeterministic analyzes root with -deterministic in format and returns
+// stdout followed by latest.json
+func runDeterministic(t *testing.T, root string, format OutputFormat) string {
+	t.Helper()
+	out := captureStdout(t, func() {
+		NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(format), Options: AnalyzeOptions{Deterministic: true}})
+	})
+	latest, err := os.ReadFile(latestReportPath(root))
+	if err != nil {
+		t.Fatalf("expected latest.json: %v", err)
+	}
+	return out + "\n--- latest.json ---\n" + string(latest)
+}
+
+// runAllFormats returns the deterministic output of root in every checked
+// format
+func runAllFormats(t *testing.T, root string) map[OutputFormat]string {
+	t.Helper()
+	outputs := make(map[OutputFormat]string, len(determinismFormats))
+	for _, format := range determinismFormats {
+		outputs[format] = runDeterministic(t, root, format)
+	}
+	return outputs
+}
+
+func assertSameOutputs(t *testing.T, label string, want, got map[OutputFormat]string) {
+	t.Helper()
+	for _, format := range determinismFormats {
+		if want[format] != got[format] {
+			t.Errorf("%s: -format %s output changed\nwant:\n%s\ngot:\n%s", label, format, want[format], got[format])
+		}
+	}
+}
+
+func TestDeterminism_SuiteCoversEveryFormatAndRule(t *testing.T) {
+	for _, format := range analyzeFormats {
+		if format != FormatText && !slices.Contains(determinismFormats, format) {
+			t.Errorf("format %s is missing from the determinism suite", format)
+		}
+	}
+
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, determinismFixture())
+	report, _ := NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatJSON), Quiet: true, Options: AnalyzeOptions{Deterministic: true}})
+	if report == nil {
+		t.Fatal("expected a report for the fixture")
+	}
+	if !slices.Equal(report.RuleSet, runtimeRuleIDs()) {
+		t.Errorf("expected the fixture to run every rule, got %v want %v", report.RuleSet, runtimeRuleIDs())
+	}
+	if len(report.Layer) == 0 || len(report.Size) < 2 || len(report.GodObject) < 2 {
+		t.Errorf("expected layer, size and several god object violations, got %+v", report.Summary)
+	}
+}
+
+func TestDeterminism_RepeatedRunsAreByteIdentical(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, determinismFixture())
+
+	first := runAllFormats(t, root)
+	for run := 2; run <= 5; run++ {
+		assertSameOutputs(t, "run "+string(rune('0'+run)), first, runAllFormats(t, root))
+	}
+}
+
+func TestDeterminism_ShuffledFixtureIsByteIdentical(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	files := determinismFixture()
+	names := sortedKeys(files)
+	writeFixtureInOrder(t, root, files, names)
+	want := runAllFormats(t, root)
+
+	for _, seed := range []int64{1, 2} {
+		if err := os.RemoveAll(root); err != nil {
+			t.Fatalf("failed to remove fixture: %v", err)
+		}
+		shuffled := slices.Clone(names)
+		rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		writeFixtureInOrder(t, root, files, shuffled)
+		assertSameOutputs(t, "shuffled copy", want, runAllFormats(t, root))
+	}
+}
+
+func TestDeterminism_ParallelParsingIsByteIdentical(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, determinismFixture())
+
+	previous := runtime.GOMAXPROCS(1)
+	t.Cleanup(func() { runtime.GOMAXPROCS(previous) })
+	serial := runAllFormats(t, root)
+
+	runtime.GOMAXPROCS(max(8, previous))
+	assertSameOutputs(t, "parallel parse", serial, runAllFormats(t, root))
+}
+
+// rootedReport returns a copy of report as a checkout at root would
+// produce it, with sep as the path separator
+func rootedReport(report *StructuralReport, analyzed, root, sep string) *StructuralReport {
+	return mapReportPaths(report, func(path string) string {
+		rest, ok := strings.CutPrefix(path, analyzed)
+		if !ok {
+			return path
+		}
+		return root + strings.ReplaceAll(rest, "/", sep)
+	})
+}
+
+func TestDeterminism_WindowsPathsAreByteIdentical(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, determinismFixture())
+	report, _ := NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatJSON), Quiet: true, Options: AnalyzeOptions{Deterministic: true}})
+	if report == nil {
+		t.Fatal("expected a report for the fixture")
+	}
+
+	linux := rootedReport(report, root, "/work/repo", "/")
+	windows := rootedReport(report, root, `C:\work\repo`, `\`)
+	render := func(report *StructuralReport, format OutputFormat) string {
+		if format == FormatFixPlan {
+			return formatFixPlan(BuildFixPlan(relativizeReport(report, reportBase(report, "", false)), scoringWeightsFromConfig(nil)))
+		}
+		return NewReporter(format).Format(report)
+	}
+	for _, format := range append(slices.Clone(determinismFormats), FormatText) {
+		want, got := render(linux, format), render(windows, format)
+		if want != got {
+			t.Errorf("-format %s differs between path separators\nlinux:\n%s\nwindows:\n%s", format, want, got)
+		}
+		if strings.Contains(got, "work") {
+			t.Errorf("-format %s leaks the absolute root:\n%s", format, got)
+		}
+	}
+}
+
+func TestDeterminism_ShuffledGraphInsertionIsByteIdentical(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	writeServiceFixture(t, root, map[string]string{
+		"a.go":   "package a\n",
+		"b.go":   "package a\n",
+		"c.go":   "package a\n",
+		"d.go":   "package a\n",
+		"big.go": "package a\n" + strings.Repeat("// filler\n", 600),
+	})
+	node := func(name string) string { return filepath.Join(root, name) }
+	edges := [][2]string{
+		{node("a.go"), node("b.go")}, {node("b.go"), node("c.go")}, {node("c.go"), node("a.go")},
+		{node("c.go"), node("d.go")}, {node("d.go"), node("c.go")}, {node("big.go"), node("a.go")},
+		{node("big.go"), node("d.go")},
+	}
+	cfg := loadConfiguration(root, false)
+	render := func(order [][2]string) string {
+		graph := NewDependencyGraph()
+		for _, edge := range order {
+			graph.AddEdge(edge[0], edge[1])
+		}
+		summary := runInternalRulePipelineWithSources(root, graph, cfg, nil, nil, nil)
+		report, err := generateRuleEngineReport(root, AnalyzeRequest{Format: string(FormatJSON), Quiet: true}, cfg, summary)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Circular) < 2 {
+			t.Fatalf("expected several cycles, got %+v", report.Circular)
+		}
+		var adjacency strings.Builder
+		for _, from := range graph.GetAllNodes() {
+			adjacency.WriteString(from + " -> " + strings.Join(graph.GetDependencies(from), ", ") + "\n")
+		}
+		return adjacency.String() + NewReporter(FormatText).Format(report) + NewReporter(FormatJSON).Format(report)
+	}
+
+	want := render(edges)
+	for seed := int64(1); seed <= 20; seed++ {
+		shuffled := slices.Clone(edges)
+		rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		if got := render(shuffled); got != want {
+			t.Fatalf("seed %d: report changed with the insertion order\nwant:\n%s\ngot:\n%s", seed, want, got)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DiffSummary is the machine-readable result of the diff command. Added and
+// Removed are ordered by kind, description and fingerprint, so identical
+// inputs always serialize identically. Pass is false when head adds
+// violations or lowers the score.
+type DiffSummary struct {
+	Added      []ComparedViolation `json:"added"`
+	Removed    []ComparedViolation `json:"removed"`
+	ScoreDelta float64             `json:"scoreDelta"`
+	Pass       bool                `json:"pass"`
+}
+
+// reportDocument accepts both the json and json-v1 report shapes, as well
+// as latest.json, which wraps a json-v1 report under "report"
+type reportDocument struct {
+	Report json.RawMessage `json:"report"`
+	Score  *struct {
+		Total float64 `json:"total"`
+	} `json:"score"`
+	CircularViolations         []CycleViolation            `json:"circularViolations"`
+	LayerViolations            []LayerViolation            `json:"layerViolations"`
+	FeatureIsolationViolations []FeatureIsolationViolation `json:"featureIsolationViolations"`
+	DependencyCapViolations    []DependencyCapViolation    `json:"dependencyCapViolations"`
+	SizeViolations             []SizeViolation             `json:"sizeViolations"`
+	GodObjectViolations        []godObjectDocument         `json:"godObjectViolations"`
+}
+
+// godObjectDocument reads god object entries under their json field names
+// as well as the json-v1 names (struct, fields, methods)
+type godObjectDocument struct {
+	StructName  string
+	Struct      string `json:"struct"`
+	File        string
+	FieldCount  int
+	Fields      int `json:"fields"`
+	MethodCount int
+	Methods     int `json:"methods"`
+}
+
+// summarizeDiff reduces a report comparison to the diff summary
+func summarizeDiff(cmp *ReportComparison) *DiffSummary {
+	return &DiffSummary{
+		Added:      cmp.Added,
+		Removed:    cmp.Fixed,
+		ScoreDelta: cmp.ScoreDelta,
+		Pass:       len(cmp.Added) == 0 && cmp.ScoreDelta >= 0,
+	}
+}
+
+// loadReportFile reads a report written by analyze -format json or json-v1,
+// or a latest.json file
+func loadReportFile(path string) (*StructuralReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, WrapError(err, ErrorFileNotFound, fmt.Sprintf("Error reading report file: %s", path), GetSuggestion(err.Error()))
+	}
+
+	var doc reportDocument
+	err = json.Unmarshal(data, &doc)
+	if err == nil && len(doc.Report) > 0 {
+		wrapped := doc.Report
+		doc = reportDocument{}
+		err = json.Unmarshal(wrapped, &doc)
+	}
+	if err != nil {
+		return nil, WrapError(err, ErrorInvalidArgument, fmt.Sprintf("Invalid report file: %s", path), "Pass a report written by 'repodoctor analyze -format json'")
+	}
+
+	report := &StructuralReport{
+		Circular: doc.CircularViolations,
+		Layer:    doc.LayerViolations,
+		OptIn:    OptInViolations{FeatureIsolation: doc.FeatureIsolationViolations, DependencyCap: doc.DependencyCapViolations},
+		Size:     doc.SizeViolations,
+	}
+	if doc.Score != nil {
+		report.Score = &StructuralScore{TotalScore: doc.Score.Total}
+	}
+	for _, g := range doc.GodObjectViolations {
+		report.GodObject = append(report.GodObject, GodObjectViolation{
+			StructName:  firstNonEmpty(g.StructName, g.Struct),
+			File:        g.File,
+			FieldCount:  max(g.FieldCount, g.Fields),
+			MethodCount: max(g.MethodCount, g.Methods),
+		})
+	}
+	return report, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// formatDiffText renders the diff summary for people
+func formatDiffText(summary *DiffSummary) string {
+	var sb strings.Builder
+
+	status := ColorSuccess("PASS")
+	if !summary.Pass {
+		status = ColorError("FAIL")
+	}
+	sb.WriteString(fmt.Sprintf("Diff: %s (score %s)\n", status, formatScoreDeltaArrow(summary.ScoreDelta)))
+
+	writeDiffViolations(&sb, "Added", "+", summary.Added)
+	writeDiffViolations(&sb, "Removed", "-", summary.Removed)
+	if len(summary.Added) == 0 && len(summary.Removed) == 0 {
+		sb.WriteString("No violation changes.\n")
+	}
+	return sb.String()
+}
+
+func writeDiffViolations(sb *strings.Builder, title, marker string, violations []ComparedViolation) {
+	if len(violations) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("%s (%d):\n", title, len(violations)))
+	for _, v := range violations {
+		sb.WriteString(fmt.Sprintf("  %s [%s] %s\n", marker, v.Kind, v.Description))
+	}
+}
+
+// formatDiffJSON renders the diff summary as an indented JSON object
+func formatDiffJSON(summary *DiffSummary) string {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "{}\n"
+	}
+	return string(data) + "\n"
+}
+
+// handleDiffCommand compares a base and a head report. The exit code is 1
+// when the diff does not pass, so CI can gate on it directly.
+func handleDiffCommand(args []string) error {
+	diffCmd := flag.NewFlagSet("diff", flag.ExitOnError)
+	basePath := diffCmd.String("base", "", "Report of the base revision")
+	headPath := diffCmd.String("head", "", "Report of the head revision")
+	format := diffCmd.String("format", "text", "Output format (text, json, markdown)")
+	diffCmd.Parse(args)
+
+	if *basePath == "" || *headPath == "" {
+		return NewCLIError(ErrorCLIUsage, "diff requires -base and -head", "Example: repodoctor diff -base base.json -head head.json", nil)
+	}
+	if *format != "text" && *format != "json" && *format != "markdown" {
+		return NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid format: %s", *format), "Valid formats: text, json, markdown", nil)
+	}
+
+	base, err := loadReportFile(*basePath)
+	if err != nil {
+		return err
+	}
+	head, err := loadReportFile(*headPath)
+	if err != nil {
+		return err
+	}
+
+	cmp := CompareReports(base, head)
+	summary := summarizeDiff(cmp)
+	switch *format {
+	case "json":
+		fmt.Print(formatDiffJSON(summary))
+	case "markdown":
+		fmt.Print(formatComparisonMarkdown(cmp))
+	default:
+		fmt.Print(formatDiffText(summary))
+	}
+
+	if !summary.Pass {
+		os.Exit(1)
+	}
+	return nil
+}
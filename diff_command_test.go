@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeDiffReport writes a report to dir in the given format and returns its path
+func writeDiffReport(t *testing.T, dir, name string, format OutputFormat, report *StructuralReport) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(NewReporter(format).Format(report)), 0o644); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	return path
+}
+
+func TestDiffSummary_JSONFieldsForCraftedReports(t *testing.T) {
+	dir := t.TempDir()
+	base := compareFixtureBase()
+	head := compareFixtureBase()
+	head.Score = &StructuralScore{TotalScore: 74.0, MaxScore: 100.0}
+	head.Circular = nil
+	head.Size = append(head.Size,
+		SizeViolation{File: "internal/z/z.go", Lines: 900, Threshold: 500},
+		SizeViolation{File: "internal/a/a.go", Function: "Run", Lines: 120, Threshold: 80},
+	)
+
+	// The base is read back from json output and the head from json-v1
+	basePath := writeDiffReport(t, dir, "base.json", FormatJSON, base)
+	headPath := writeDiffReport(t, dir, "head.json", FormatJSONV1, head)
+	baseReport, err := loadReportFile(basePath)
+	if err != nil {
+		t.Fatalf("failed to load base: %v", err)
+	}
+	headReport, err := loadReportFile(headPath)
+	if err != nil {
+		t.Fatalf("failed to load head: %v", err)
+	}
+
+	var got struct {
+		Added      []ComparedViolation `json:"added"`
+		Removed    []ComparedViolation `json:"removed"`
+		ScoreDelta float64             `json:"scoreDelta"`
+		Pass       bool                `json:"pass"`
+	}
+	output := formatDiffJSON(summarizeDiff(CompareReports(baseReport, headReport)))
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("diff summary is not valid JSON: %v\n%s", err, output)
+	}
+
+	wantAdded := []string{
+		"File internal/z/z.go: 900 lines (threshold: 500)",
+		"Function 'Run' in internal/a/a.go: 120 lines (threshold: 80)",
+	}
+	var added []string
+	for _, v := range got.Added {
+		added = append(added, v.Description)
+	}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Fatalf("unexpected added order: %v", added)
+	}
+	if len(got.Removed) != 1 || got.Removed[0].Kind != compareKindCircular {
+		t.Fatalf("expected the cycle to be removed, got %+v", got.Removed)
+	}
+	if got.ScoreDelta != -8 || got.Pass {
+		t.Fatalf("expected scoreDelta -8 and pass=false, got %v and %v", got.ScoreDelta, got.Pass)
+	}
+
+	// Fixing the cycle without adding violations passes
+	fixed := compareFixtureBase()
+	fixed.Score = &StructuralScore{TotalScore: 92.0, MaxScore: 100.0}
+	fixed.Circular = nil
+	if summary := summarizeDiff(CompareReports(baseReport, fixed)); !summary.Pass || len(summary.Added) != 0 || len(summary.Removed) != 1 {
+		t.Fatalf("expected a passing diff, got %+v", summary)
+	}
+	if again := formatDiffJSON(summarizeDiff(CompareReports(baseReport, headReport))); again != output {
+		t.Fatal("expected identical output for identical inputs")
+	}
+}
+
+func TestLoadReportFile_LatestReportWrapper(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeLatestReport(dir, compareFixtureBase(), nil, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("failed to write latest report: %v", err)
+	}
+
+	report, err := loadReportFile(latestReportPath(dir))
+	if err != nil {
+		t.Fatalf("failed to load latest report: %v", err)
+	}
+	if report.Score == nil || report.Score.TotalScore != 82 || len(report.Circular) != 1 || len(report.GodObject) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if got := report.GodObject[0]; got.StructName != "Manager" || got.FieldCount != 18 || got.MethodCount != 9 {
+		t.Fatalf("unexpected god object: %+v", got)
+	}
+}
+
+func TestDiff_OneCycleAddedOneRemoved(t *testing.T) {
+	dir := t.TempDir()
+	base := compareFixtureBase()
+	head := compareFixtureBase()
+	head.Circular = []CycleViolation{{Path: []string{"internal/c/c.go", "internal/d/d.go"}}}
+	baseReport, err := loadReportFile(writeDiffReport(t, dir, "base.json", FormatJSON, base))
+	if err != nil {
+		t.Fatalf("failed to load base: %v", err)
+	}
+	headReport, err := loadReportFile(writeDiffReport(t, dir, "head.json", FormatJSON, head))
+	if err != nil {
+		t.Fatalf("failed to load head: %v", err)
+	}
+
+	summary := summarizeDiff(CompareReports(baseReport, headReport))
+	if len(summary.Added) != 1 || summary.Added[0].Kind != compareKindCircular || len(summary.Removed) != 1 || summary.Removed[0].Kind != compareKindCircular {
+		t.Fatalf("expected one added and one removed cycle, got %+v", summary)
+	}
+	if summary.Pass || summary.ScoreDelta != 0 {
+		t.Fatalf("expected a failing diff with no score change, got %+v", summary)
+	}
+
+	text := formatDiffText(summary)
+	for _, want := range []string{
+		"+ [circular] " + summary.Added[0].Description,
+		"- [circular] " + summary.Removed[0].Description,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected text output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if !strings.Contains(summary.Added[0].Description, "internal/c/c.go") || !strings.Contains(summary.Removed[0].Description, "internal/a/a.go") {
+		t.Fatalf("expected the new cycle added and the old one removed, got %+v", summary)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envKey describes one line of -format env output. Keys are part of the
+// scripting contract: never rename or reorder them, only append.
+type envKey struct {
+	Name        string
+	Description string
+	value       func(report *StructuralReport) string
+}
+
+// envReportKeys lists the keys printed by -format env, in output order
+var envReportKeys = []envKey{
+	{"REPODOCTOR_SCORE", "Total structural health score, one decimal", func(r *StructuralReport) string { return fmt.Sprintf("%.1f", r.Score.TotalScore) }},
+	{"REPODOCTOR_MAX_SCORE", "Maximum attainable score, one decimal", func(r *StructuralReport) string { return fmt.Sprintf("%.1f", r.Score.MaxScore) }},
+	{"REPODOCTOR_GRADE", "Letter grade for the score (A-F)", func(r *StructuralReport) string { return scoreGrade(r.Score.TotalScore) }},
+	{"REPODOCTOR_VIOLATIONS", "Total structural violations", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.TotalViolations) }},
+	{"REPODOCTOR_CIRCULAR", "Circular dependency violations", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.Circular) }},
+	{"REPODOCTOR_LAYER", "Layer violations", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.Layer) }},
+	{"REPODOCTOR_SIZE", "File and function size violations", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.Size) }},
+	{"REPODOCTOR_GOD_OBJECT", "God object violations", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.GodObject) }},
+	{"REPODOCTOR_CRITICAL", "Violations that fail the run (circular + layer + feature isolation + dependency cap)", func(r *StructuralReport) string {
+		return strconv.Itoa(r.Summary.Circular + r.Summary.Layer + r.Summary.FeatureIsolation + r.Summary.DependencyCap)
+	}},
+	{"REPODOCTOR_WARNINGS", "Non-failing violations (size + god object)", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.Size + r.Summary.GodObject) }},
+	{"REPODOCTOR_ADVISORIES", "Informational findings without score impact", func(r *StructuralReport) string { return strconv.Itoa(len(r.Advisory)) }},
+	{"REPODOCTOR_EXIT_CODE", "Exit code of the run", func(r *StructuralReport) string { return strconv.Itoa(determineExitCode(r)) }},
+	{"REPODOCTOR_FEATURE_ISOLATION", "Feature isolation violations", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.FeatureIsolation) }},
+	{"REPODOCTOR_DEPENDENCY_CAP", "Dependency cap violations", func(r *StructuralReport) string { return strconv.Itoa(r.Summary.DependencyCap) }},
+}
+
+// envErrorKey is printed instead of the report keys when analysis fails
+const envErrorKey = "REPODOCTOR_ERROR_CODE"
+
+// scoreGrade maps a score on the 0-100 scale to a letter grade
+func scoreGrade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// formatEnv renders the report as shell-evaluable KEY=value lines. Every
+// value is a number or a single token, so no quoting is needed.
+func formatEnv(report *StructuralReport) string {
+	var sb strings.Builder
+	for _, key := range envReportKeys {
+		sb.WriteString(key.Name + "=" + key.value(report) + "\n")
+	}
+	return sb.String()
+}
+
+// envErrorCode turns an error into a shell-safe token, e.g. a CLIError in
+// the "Analysis Error" category becomes ANALYSIS_ERROR
+func envErrorCode(err error) string {
+	code := string(ErrorRuntime)
+	if cliErr, ok := err.(*CLIError); ok && cliErr.Code != "" {
+		code = cliErr.Code
+	}
+
+	token := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		default:
+			return '_'
+		}
+	}, code)
+	return strings.Trim(token, "_")
+}
+
+// emitEnvError keeps -format env output evaluable when analysis fails: the
+// error code goes to stdout while the human-readable error stays on stderr
+func emitEnvError(format string, err error) {
+	if OutputFormat(format) == FormatEnv {
+		fmt.Fprintf(os.Stdout, "%s=%s\n", envErrorKey, envErrorCode(err))
+	}
+}
+
+// envFormatRequested reports whether raw analyze arguments ask for env
+// output, for errors raised before flags are fully parsed
+func envFormatRequested(rawArgs []string) bool {
+	for i, arg := range rawArgs {
+		name := strings.TrimLeft(arg, "-")
+		if name == "format="+string(FormatEnv) {
+			return true
+		}
+		if name == "format" && arg != name && i+1 < len(rawArgs) && rawArgs[i+1] == string(FormatEnv) {
+			return true
+		}
+	}
+	return false
+}
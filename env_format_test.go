@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// evalEnvInSubshell evaluates env output in sh and echoes the named variables
+func evalEnvInSubshell(t *testing.T, output string, names ...string) string {
+	t.Helper()
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	refs := make([]string, len(names))
+	for i, name := range names {
+		refs[i] = "${" + name + "-unset}"
+	}
+	script := `set -eu; eval "$1"; echo "` + strings.Join(refs, " ") + `"`
+	out, err := exec.Command(sh, "-c", script, "sh", output).CombinedOutput()
+	if err != nil {
+		t.Fatalf("eval failed: %v\n%s", err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func runEnvAnalysis(t *testing.T, path string) (string, int) {
+	t.Helper()
+
+	exitCode := 0
+	out := captureStdout(t, func() {
+		exitCode = NewAnalysisService().Run(AnalyzeRequest{
+			Path:    path,
+			Format:  string(FormatEnv),
+			Verbose: true,
+		})
+	})
+	return out, exitCode
+}
+
+func TestAnalysisService_EnvFormatEvaluatesInSubshell(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+	writeServiceFixture(t, root, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n",
+		"repo/store.go": "package repo\n\nimport _ \"example.com/app/handler\"\n",
+		"handler/h.go":  "package handler\n",
+	})
+
+	out, exitCode := runEnvAnalysis(t, root)
+	if exitCode != 1 {
+		t.Fatalf("expected exit code 1, got %d", exitCode)
+	}
+
+	line := regexp.MustCompile(`^(REPODOCTOR_[A-Z_]+)=[A-Za-z0-9.]+$`)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != len(envReportKeys) {
+		t.Fatalf("expected %d lines and nothing else on stdout, got:\n%s", len(envReportKeys), out)
+	}
+	for i, l := range lines {
+		m := line.FindStringSubmatch(l)
+		if m == nil || m[1] != envReportKeys[i].Name {
+			t.Fatalf("line %d: expected shell-safe %s=..., got %q", i, envReportKeys[i].Name, l)
+		}
+	}
+
+	got := evalEnvInSubshell(t, out, "REPODOCTOR_SCORE", "REPODOCTOR_GRADE", "REPODOCTOR_VIOLATIONS",
+		"REPODOCTOR_LAYER", "REPODOCTOR_CRITICAL", "REPODOCTOR_EXIT_CODE", envErrorKey)
+	if want := "95.0 A 1 1 1 1 unset"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestAnalysisService_EnvFormatErrorSetsErrorCode(t *testing.T) {
+	out, exitCode := runEnvAnalysis(t, filepath.Join(t.TempDir(), "missing"))
+	if exitCode != exitCodePathError {
+		t.Fatalf("expected exit code %d, got %d", exitCodePathError, exitCode)
+	}
+
+	got := evalEnvInSubshell(t, out, envErrorKey, "REPODOCTOR_SCORE")
+	if want := "FILE_NOT_FOUND unset"; got != want {
+		t.Fatalf("expected %q, got %q (stdout %q)", want, got, out)
+	}
+}
+
+func TestEnvFormatRequested(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"-format", "env"}, true},
+		{[]string{"--format=env", "-bogus"}, true},
+		{[]string{"-format", "json"}, false},
+		{[]string{"format", "env"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := envFormatRequested(tt.args); got != tt.want {
+			t.Fatalf("envFormatRequested(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestScoreGrade(t *testing.T) {
+	for score, want := range map[float64]string{100: "A", 90: "A", 89.9: "B", 72.5: "C", 60: "D", 0: "F"} {
+		if got := scoreGrade(score); got != want {
+			t.Fatalf("scoreGrade(%.1f) = %s, want %s", score, got, want)
+		}
+	}
+}
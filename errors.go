@@ -164,10 +164,10 @@ func PrintError(err error) {
 	}
 }
 
-// ExitWithError prints an error and exits with code 1
+// ExitWithError prints an error and exits with its exitCodeForError code
 func ExitWithError(err error) {
 	PrintError(err)
-	os.Exit(1)
+	os.Exit(exitCodeForError(err))
 }
 
 // WrapError wraps an existing error with additional context
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// excludedPath reports whether file matches one of the exclude globs of the
+// config, against its slash path relative to root. A pattern without a
+// slash, such as *_test.go, matches file names at any depth; any other
+// pattern matches the whole relative path, with ** matching any number of
+// directories, as in **/testdata/** or mocks/**.
+func excludedPath(root, file string, globs []string) bool {
+	if len(globs) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, glob := range globs {
+		if !strings.Contains(glob, "/") {
+			if ok, _ := path.Match(glob, path.Base(rel)); ok {
+				return true
+			}
+			continue
+		}
+		if matchGlobSegments(strings.Split(glob, "/"), strings.Split(rel, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlobSegments matches path segments against pattern segments, where a
+// ** segment matches zero or more path segments
+func matchGlobSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(segments); skip++ {
+			if matchGlobSegments(pattern[1:], segments[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], segments[1:])
+}
+
+// withoutExcludedFiles returns the files that match none of the globs
+func withoutExcludedFiles(absPath string, files []rules.RepositoryFile, globs []string) []rules.RepositoryFile {
+	if len(globs) == 0 {
+		return files
+	}
+	kept := make([]rules.RepositoryFile, 0, len(files))
+	for _, file := range files {
+		if !excludedPath(absPath, file.Path, globs) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// configExcludes returns the exclude globs of cfg, if any
+func configExcludes(cfg *Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	return cfg.Exclude
+}
+
+func validateExcludeGlobs(globs []string) error {
+	for i, glob := range globs {
+		if strings.TrimSpace(glob) == "" {
+			return fmt.Errorf("exclude[%d] must not be empty", i)
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("exclude[%d] %q is not a valid glob: %w", i, glob, err)
+		}
+	}
+	return nil
+}
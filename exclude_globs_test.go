@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExcludedPath_Globs(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	globs := []string{"**/testdata/**", "*_test.go", "*_gen.go", "mocks/**"}
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"testdata/big.go", true},
+		{"internal/parser/testdata/fixture.go", true},
+		{"service_test.go", true},
+		{"internal/service/service_test.go", true},
+		{"api/types_gen.go", true},
+		{"mocks/store.go", true},
+		{"mocks/sub/store.go", true},
+		{"internal/mocks/store.go", false},
+		{"internal/service/service.go", false},
+		{"testdata.go", false},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(root, filepath.FromSlash(tt.file))
+		if got := excludedPath(root, file, globs); got != tt.want {
+			t.Errorf("excludedPath(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+	if excludedPath(root, filepath.Join(root, "service_test.go"), nil) {
+		t.Error("expected nothing to be excluded without globs")
+	}
+}
+
+func TestRuleChecks_SkipExcludedFiles(t *testing.T) {
+	root := t.TempDir()
+	fields := ""
+	for i := 0; i < 16; i++ {
+		fields += "\tF" + string(rune('A'+i)) + " int\n"
+	}
+	bigStruct := "package p\n\ntype Big struct {\n" + fields + "}\n\nfunc Long() {\n" + strings.Repeat("\t_ = 1\n", 85) + "}\n"
+	writeServiceFixture(t, root, map[string]string{
+		"service.go":              bigStruct,
+		"service_test.go":         bigStruct,
+		"pkg/testdata/fixture.go": bigStruct,
+	})
+
+	size, god := NewSizeRule(), NewGodObjectRule()
+	size.Excludes = []string{"**/testdata/**", "*_test.go"}
+	god.Excludes = size.Excludes
+	if err := size.Check(root); err != nil {
+		t.Fatalf("size Check failed: %v", err)
+	}
+	if err := god.Check(root); err != nil {
+		t.Fatalf("god object Check failed: %v", err)
+	}
+	want := filepath.Join(root, "service.go")
+	if v := size.Violations(); len(v) != 1 || v[0].File != want {
+		t.Errorf("expected only the size violation of service.go, got %+v", v)
+	}
+	if v := god.Violations(); len(v) != 1 || v[0].File != want {
+		t.Errorf("expected only the god object of service.go, got %+v", v)
+	}
+}
+
+func TestAnalysisService_ConfigExcludeSkipsPerFileRules(t *testing.T) {
+	root := t.TempDir()
+	writeServiceFixture(t, root, map[string]string{
+		"go.mod":                  "module example.com/app\n\ngo 1.21\n",
+		".repodoctor/config.yaml": "exclude:\n  - \"**/testdata/**\"\n  - \"*_test.go\"\n",
+		"app/app.go":              "package app\n",
+		"app/app_test.go":         "package app\n" + strings.Repeat("// filler\n", 600),
+		"app/testdata/big.go":     "package testdata\n" + strings.Repeat("// filler\n", 600),
+	})
+
+	report, _ := NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatJSON)})
+	if report == nil || len(report.Size) != 0 {
+		t.Fatalf("expected the excluded files to have no size violations, got %+v", report)
+	}
+}
+
+func TestImportExtractor_SkipsExcludedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeServiceFixture(t, root, map[string]string{
+		"app/app.go":      "package app\n\nimport \"fmt\"\n",
+		"app/app_test.go": "package app\n\nimport \"testing\"\n",
+	})
+
+	extractor := NewImportExtractor("example.com/app")
+	extractor.Excludes = []string{"*_test.go"}
+	imports, err := extractor.ExtractFromDir(root)
+	if err != nil {
+		t.Fatalf("ExtractFromDir failed: %v", err)
+	}
+	if _, ok := imports[filepath.Join(root, "app", "app_test.go")]; ok || len(imports) != 1 {
+		t.Errorf("expected only app.go to be extracted, got %v", imports)
+	}
+}
+
+func TestConfigLoader_ValidatesExcludeGlobs(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("exclude:\n  - \"[\"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil || !strings.Contains(err.Error(), "exclude[0]") {
+		t.Fatalf("expected an invalid glob error, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+)
+
+// Exit codes of the CLI. Each failure class has its own code, so a script
+// can tell a run that could not analyze from one that found violations or
+// scored below its floor.
+const (
+	// exitCodeClean is the exit code of a run that passed
+	exitCodeClean = 0
+	// exitCodeViolations is the exit code of a run that found violations at
+	// the -fail-on level
+	exitCodeViolations = 1
+	// exitCodeScoreBelowFloor is the exit code of a run that scored below
+	// -fail-under
+	exitCodeScoreBelowFloor = 2
+	// exitCodePathError is the exit code of a run whose path does not exist
+	// or cannot be read
+	exitCodePathError = 3
+	// exitCodeConfigError is the exit code of a run whose config file could
+	// not be read, parsed or validated
+	exitCodeConfigError = 4
+	// exitCodeFailure is the exit code of a run that failed for any other
+	// reason: a usage error, a failed analysis or, with -fail-on-timeout, a
+	// rule that timed out
+	exitCodeFailure = 5
+	// exitCodeInternalError is the exit code of a run that crashed
+	exitCodeInternalError = 6
+	// exitCodeScoreRegression is the exit code of a run that, with
+	// -fail-on-regression, scored below the average of the previous runs
+	exitCodeScoreRegression = 7
+)
+
+// exitCodeForError returns the exit code of a command that failed with err,
+// by its error category
+func exitCodeForError(err error) int {
+	var crash *crashError
+	if errors.As(err, &crash) {
+		return exitCodeInternalError
+	}
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) {
+		return exitCodeFailure
+	}
+	switch cliErr.Category {
+	case ErrorFileNotFound:
+		return exitCodePathError
+	case ErrorConfiguration:
+		return exitCodeConfigError
+	default:
+		return exitCodeFailure
+	}
+}
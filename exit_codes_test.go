@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExitCodeForError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "plain error", err: errors.New("boom"), want: exitCodeFailure},
+		{name: "usage error", err: HandleCLIUsageError("bad flag", nil), want: exitCodeFailure},
+		{name: "analysis error", err: NewCLIError(ErrorAnalysis, "pipeline failed", "", nil), want: exitCodeFailure},
+		{name: "missing path", err: HandleFileNotFoundError("/missing", nil), want: exitCodePathError},
+		{name: "config error", err: NewCLIError(ErrorConfiguration, "bad config", "", nil), want: exitCodeConfigError},
+		{name: "wrapped config error", err: fmt.Errorf("loading: %w", NewCLIError(ErrorConfiguration, "bad config", "", nil)), want: exitCodeConfigError},
+		{name: "crash", err: &crashError{value: "boom"}, want: exitCodeInternalError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := exitCodeForError(tc.err); got != tc.want {
+				t.Fatalf("expected exit code %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDetermineExitCode_FailureClasses(t *testing.T) {
+	timedOut := []RuleDescriptor{{Name: "rule.size", TimedOut: true}}
+	layer := []LayerViolation{{From: "store/s.go", To: "api/h.go"}}
+	tests := []struct {
+		name   string
+		report *StructuralReport
+		want   int
+	}{
+		{name: "clean", report: &StructuralReport{}, want: exitCodeClean},
+		{name: "violations", report: &StructuralReport{Layer: layer, HasViolations: true}, want: exitCodeViolations},
+		{name: "score below floor", report: &StructuralReport{Score: &StructuralScore{TotalScore: 70, MaxScore: 100}, Metrics: ReportMetrics{Exit: ExitPolicy{FailUnder: 80}}}, want: exitCodeScoreBelowFloor},
+		{name: "timeout", report: &StructuralReport{Layer: layer, HasViolations: true, Metrics: ReportMetrics{Rules: timedOut, Exit: ExitPolicy{FailOnTimeout: true}}}, want: exitCodeFailure},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := determineExitCode(tc.report); got != tc.want {
+				t.Fatalf("expected exit code %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalysisService_FailureExitCodes(t *testing.T) {
+	clean := t.TempDir()
+	writeServiceFixture(t, clean, map[string]string{"a.go": "package a\n"})
+	badConfig := t.TempDir()
+	writeServiceFixture(t, badConfig, map[string]string{"a.go": "package a\n", ".repodoctor/config.yaml": "size: [\n"})
+	file := filepath.Join(clean, "a.go")
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{name: "clean", path: clean, want: exitCodeClean},
+		{name: "missing path", path: filepath.Join(clean, "missing"), want: exitCodePathError},
+		{name: "file path", path: file, want: exitCodePathError},
+		{name: "invalid config", path: badConfig, want: exitCodeConfigError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stderr := os.Stderr
+			os.Stderr, _ = os.Open(os.DevNull)
+			defer func() { os.Stderr = stderr }()
+
+			report, code := NewAnalysisService().analyze(AnalyzeRequest{Path: tc.path, Format: string(FormatJSON), Quiet: true})
+			if code != tc.want {
+				t.Fatalf("expected exit code %d, got %d", tc.want, code)
+			}
+			if (report == nil) != (tc.want != exitCodeClean) {
+				t.Fatalf("expected a report only for a clean run, got %v", report)
+			}
+		})
+	}
+}
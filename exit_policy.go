@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// -fail-on levels. Unset keeps the default, which fails like high.
+const (
+	failOnNone     = "none"
+	failOnCritical = "critical"
+	failOnHigh     = "high"
+	failOnAny      = "any"
+)
+
+// failOnScorePrefix starts a -fail-on score floor, as in score<80
+const failOnScorePrefix = "score<"
+
+// ExitPolicy holds the flags that decide the exit code of a run
+type ExitPolicy struct {
+	// FailOn is the -fail-on level: none, critical, high or any; empty
+	// fails on critical violations, like high
+	FailOn string
+	// FailUnder is the score floor of -fail-under or -fail-on score<N; 0
+	// when the run fails on violations instead
+	FailUnder float64
+	// FailOnTimeout fails the run when a rule timed out
+	FailOnTimeout bool
+	// FailOnRegression fails the run when the regression check detects a
+	// drop below the average of the previous runs
+	FailOnRegression bool
+}
+
+// exitPolicyFlags holds the analyze flags that fill ExitPolicy
+type exitPolicyFlags struct {
+	failOn           *string
+	failUnder        *float64
+	failOnTimeout    *bool
+	failOnRegression *bool
+}
+
+// bindExitPolicyFlags registers the ExitPolicy flags on fs
+func bindExitPolicyFlags(fs *flag.FlagSet) *exitPolicyFlags {
+	return &exitPolicyFlags{
+		failOn:           fs.String("fail-on", "", "Exit-code policy: none, critical, high, any or score<N (default: high)"),
+		failUnder:        fs.Float64("fail-under", 0, "Fail only when the score is below this floor (0: fail on critical violations)"),
+		failOnTimeout:    fs.Bool("fail-on-timeout", false, "Exit with 5 when a rule hits its rules.timeouts entry"),
+		failOnRegression: fs.Bool("fail-on-regression", false, "Exit with 7 when the score regressed below the average of recent runs"),
+	}
+}
+
+// policy validates the parsed flags and returns the ExitPolicy they select.
+// -fail-on score<N is the same floor as -fail-under N, so the two flags
+// cannot be combined.
+func (f *exitPolicyFlags) policy() (ExitPolicy, error) {
+	policy := ExitPolicy{FailUnder: *f.failUnder, FailOnTimeout: *f.failOnTimeout, FailOnRegression: *f.failOnRegression}
+	if policy.FailUnder < 0 {
+		return ExitPolicy{}, NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid -fail-under: %g", policy.FailUnder), "Use a score floor of 0 or more", nil)
+	}
+	if *f.failOn == "" {
+		return policy, nil
+	}
+	if policy.FailUnder > 0 {
+		return ExitPolicy{}, NewCLIError(ErrorInvalidArgument, "-fail-on cannot be combined with -fail-under", "Use -fail-on score<N for a score floor", nil)
+	}
+
+	switch value := strings.TrimSpace(*f.failOn); value {
+	case failOnNone, failOnCritical, failOnHigh, failOnAny:
+		policy.FailOn = value
+		return policy, nil
+	default:
+		floor, err := strconv.ParseFloat(strings.TrimPrefix(value, failOnScorePrefix), 64)
+		if !strings.HasPrefix(value, failOnScorePrefix) || err != nil || floor <= 0 {
+			return ExitPolicy{}, NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid -fail-on: %s", *f.failOn), "Use none, critical, high, any or score<N with a positive N, e.g. score<80", nil)
+		}
+		policy.FailUnder = floor
+		return policy, nil
+	}
+}
+
+// failsOnViolations reports whether the report's violations fail the run
+// under the -fail-on level. Cycles that circular.baseline tolerates never
+// fail it.
+func (p ExitPolicy) failsOnViolations(report *StructuralReport) bool {
+	cycles := len(report.Circular) > 0 && !report.Metrics.Cycles.Tolerated()
+	switch p.FailOn {
+	case failOnNone:
+		return false
+	case failOnCritical:
+		return cycles
+	case failOnAny:
+		return cycles || len(report.Layer) > 0 || len(report.OptIn.FeatureIsolation) > 0 || len(report.OptIn.DependencyCap) > 0 || len(report.Size) > 0 || len(report.GodObject) > 0
+	default:
+		return cycles || len(report.Layer) > 0 || len(report.OptIn.FeatureIsolation) > 0 || len(report.OptIn.DependencyCap) > 0
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetermineExitCode_FailOnPolicies(t *testing.T) {
+	cycle := []CycleViolation{{Path: []string{"a/a.go", "b/b.go", "a/a.go"}}}
+	layer := []LayerViolation{{From: "store/s.go", To: "api/h.go"}}
+	size := []SizeViolation{{File: "big.go"}}
+	godObject := []GodObjectViolation{{File: "god.go"}}
+	tests := []struct {
+		name      string
+		failOn    string
+		failUnder float64
+		circular  []CycleViolation
+		layer     []LayerViolation
+		size      []SizeViolation
+		godObject []GodObjectViolation
+		want      int
+	}{
+		{name: "none passes on cycles", failOn: failOnNone, circular: cycle, layer: layer, want: 0},
+		{name: "critical fails on cycles", failOn: failOnCritical, circular: cycle, want: 1},
+		{name: "critical passes on layer violations", failOn: failOnCritical, layer: layer, want: 0},
+		{name: "high fails on cycles", failOn: failOnHigh, circular: cycle, want: 1},
+		{name: "high fails on layer violations", failOn: failOnHigh, layer: layer, want: 1},
+		{name: "high passes on size violations", failOn: failOnHigh, size: size, godObject: godObject, want: 0},
+		{name: "any fails on size violations", failOn: failOnAny, size: size, want: 1},
+		{name: "any fails on god objects", failOn: failOnAny, godObject: godObject, want: 1},
+		{name: "unset fails like high", layer: layer, want: 1},
+		{name: "unset passes on size violations", size: size, want: 0},
+		{name: "score below floor fails", failUnder: 90, size: size, want: 2},
+		{name: "score at floor passes", failUnder: 80, layer: layer, want: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := &StructuralReport{
+				Score:         &StructuralScore{TotalScore: 80, MaxScore: 100},
+				Circular:      tc.circular,
+				Layer:         tc.layer,
+				Size:          tc.size,
+				GodObject:     tc.godObject,
+				HasViolations: true,
+				Metrics:       ReportMetrics{Exit: ExitPolicy{FailOn: tc.failOn, FailUnder: tc.failUnder}},
+			}
+			if got := determineExitCode(report); got != tc.want {
+				t.Fatalf("expected exit code %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDetermineExitCode_FailOnKeepsToleratedCycles(t *testing.T) {
+	report := &StructuralReport{
+		Circular:      []CycleViolation{{Path: []string{"a/a.go", "b/b.go", "a/a.go"}}},
+		HasViolations: true,
+		Metrics:       ReportMetrics{Exit: ExitPolicy{FailOn: failOnAny}, Cycles: &CycleTolerance{MaxAllowed: 1, Baselined: []CycleFingerprint{{Fingerprint: "a"}}}},
+	}
+	if !report.Metrics.Cycles.Tolerated() {
+		t.Fatal("expected the cycle to be tolerated by the baseline")
+	}
+	if got := determineExitCode(report); got != 0 {
+		t.Fatalf("expected tolerated cycles to pass -fail-on any, got exit code %d", got)
+	}
+}
+
+func TestComposeAnalyzeRequest_FailOn(t *testing.T) {
+	for _, value := range []string{failOnNone, failOnCritical, failOnHigh, failOnAny} {
+		req, err := parseAnalyzeFlags([]string{"-fail-on", value, "."})
+		if err != nil || req.Options.Exit.FailOn != value {
+			t.Fatalf("expected -fail-on %s, got %+v, %v", value, req, err)
+		}
+	}
+
+	req, err := parseAnalyzeFlags([]string{"-fail-on", "score<82.5", "."})
+	if err != nil || req.Options.Exit.FailOn != "" || req.Options.Exit.FailUnder != 82.5 {
+		t.Fatalf("expected score<82.5 to set the score floor, got %+v, %v", req, err)
+	}
+
+	for _, args := range [][]string{
+		{"-fail-on", "severe", "."},
+		{"-fail-on", "score<", "."},
+		{"-fail-on", "score<0", "."},
+		{"-fail-on", "score<abc", "."},
+		{"-fail-on", "score>80", "."},
+		{"-fail-on", "any", "-fail-under", "80", "."},
+	} {
+		if _, err := parseAnalyzeFlags(args); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestAnalysisService_FailOnAnyFailsOnSizeViolations(t *testing.T) {
+	files := map[string]string{"big.go": "package big\n" + strings.Repeat("// filler\n", 600)}
+
+	for _, tc := range []struct {
+		failOn string
+		want   int
+	}{{"", 0}, {failOnHigh, 0}, {failOnAny, 1}, {"score<98", 2}} {
+		flags := &exitPolicyFlags{failOn: &tc.failOn, failUnder: new(float64), failOnTimeout: new(bool), failOnRegression: new(bool)}
+		exit, err := flags.policy()
+		if err != nil {
+			t.Fatalf("-fail-on %q: %v", tc.failOn, err)
+		}
+		_, code := analyzeFixture(t, files, AnalyzeOptions{NoLargest: true, Exit: exit})
+		if code != tc.want {
+			t.Fatalf("-fail-on %q: expected exit code %d, got %d", tc.failOn, tc.want, code)
+		}
+	}
+}
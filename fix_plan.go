@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FormatFixPlan prints the remediation plan instead of the report
+const FormatFixPlan OutputFormat = "fixplan"
+
+// FixAction is one suggested remediation step. Impact is the estimated
+// number of score points recovered by fixing only this violation, given the
+// configured weights and penalty curves and the current violation counts.
+type FixAction struct {
+	Action string  `json:"action"`
+	Target string  `json:"target"`
+	File   string  `json:"file,omitempty"`
+	Detail string  `json:"detail"`
+	Impact float64 `json:"impact"`
+}
+
+// BuildFixPlan lists one action per scored violation, ordered by impact.
+// Equal impacts are ordered by action, target and detail, so identical
+// reports always produce the same plan.
+func BuildFixPlan(report *StructuralReport, weights *ScoringWeights) []FixAction {
+	counts := [6]int{len(report.Circular), len(report.Layer), len(report.Size), len(report.GodObject), len(report.OptIn.FeatureIsolation), len(report.OptIn.DependencyCap)}
+	score := fixPlanScore(weights, counts)
+	impact := func(category int) float64 {
+		reduced := counts
+		reduced[category]--
+		return fixPlanScore(weights, reduced) - score
+	}
+
+	plan := make([]FixAction, 0, counts[0]+counts[1]+counts[2]+counts[3]+counts[4]+counts[5])
+	for _, v := range report.Circular {
+		plan = append(plan, FixAction{Action: "break-cycle", Target: strings.Join(v.Path, " → "), File: firstOf(v.Path), Detail: "Remove one import of the cycle", Impact: impact(0)})
+	}
+	for _, v := range report.Layer {
+		plan = append(plan, FixAction{Action: "fix-dependency", Target: v.From, File: v.From, Detail: v.Message, Impact: impact(1)})
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		plan = append(plan, FixAction{Action: "isolate-feature", Target: v.From, File: v.From, Detail: v.Message, Impact: impact(4)})
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		plan = append(plan, FixAction{Action: "drop-dependency", Target: v.Manifest, File: v.Manifest, Detail: v.Message, Impact: impact(5)})
+	}
+	for _, v := range report.Size {
+		action := FixAction{Action: "shrink-file", Target: v.File, File: v.File, Impact: impact(2)}
+		if v.Function != "" {
+			action.Action, action.Target = "shrink-function", v.Function
+		}
+		action.Detail = fmt.Sprintf("Reduce %d lines to at most %d", v.Lines, v.Threshold)
+		plan = append(plan, action)
+	}
+	for _, v := range report.GodObject {
+		detail := fmt.Sprintf("Split %d fields and %d methods into smaller types", v.FieldCount, v.MethodCount)
+		plan = append(plan, FixAction{Action: "split-struct", Target: v.StructName, File: v.File, Detail: detail, Impact: impact(3)})
+	}
+
+	sort.SliceStable(plan, func(i, j int) bool {
+		if plan[i].Impact != plan[j].Impact {
+			return plan[i].Impact > plan[j].Impact
+		}
+		if plan[i].Action != plan[j].Action {
+			return plan[i].Action < plan[j].Action
+		}
+		if plan[i].Target != plan[j].Target {
+			return plan[i].Target < plan[j].Target
+		}
+		return plan[i].Detail < plan[j].Detail
+	})
+	return plan
+}
+
+// fixPlanScore scores violation counts (circular, layer, size, god object,
+// feature isolation, dependency cap) the way calculateScoreFromViolations
+// does
+func fixPlanScore(weights *ScoringWeights, counts [6]int) float64 {
+	penalty := categoryPenalty(weights.CircularDependencyPenalty, weights.CircularCurve, counts[0]) +
+		categoryPenalty(weights.LayerViolationPenalty, weights.LayerCurve, counts[1]) +
+		categoryPenalty(weights.SizeViolationPenalty, weights.SizeCurve, counts[2]) +
+		categoryPenalty(weights.GodObjectPenalty, weights.GodObjectCurve, counts[3]) +
+		categoryPenalty(weights.FeatureIsolationPenalty, nil, counts[4]) +
+		categoryPenalty(weights.DependencyCapPenalty, nil, counts[5])
+	return max(0, 100.0-penalty)
+}
+
+func firstOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// formatFixPlan renders the plan as an indented JSON array
+func formatFixPlan(plan []FixAction) string {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return "[]\n"
+	}
+	return string(data) + "\n"
+}
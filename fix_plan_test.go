@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func fixPlanReport() *StructuralReport {
+	return &StructuralReport{
+		Circular: []CycleViolation{{Path: []string{"internal/a/a.go", "internal/b/b.go"}, Severity: model.SeverityError}},
+		Size: []SizeViolation{
+			{File: "big.go", Lines: 700, Threshold: 500},
+			{File: "big.go", Function: "handle", Lines: 120, Threshold: 80},
+		},
+		GodObject: []GodObjectViolation{{StructName: "Server", File: "server.go", FieldCount: 20, MethodCount: 4}},
+	}
+}
+
+func TestBuildFixPlan_BreakingTheCycleRanksFirst(t *testing.T) {
+	plan := BuildFixPlan(fixPlanReport(), DefaultScoringWeights())
+	if len(plan) != 4 {
+		t.Fatalf("expected one action per violation, got %+v", plan)
+	}
+
+	if plan[0].Action != "break-cycle" || plan[0].Impact != 10 || plan[0].Target != "internal/a/a.go → internal/b/b.go" {
+		t.Fatalf("expected breaking the cycle (10 points) first, got %+v", plan[0])
+	}
+	want := []string{"break-cycle", "split-struct", "shrink-file", "shrink-function"}
+	for i, action := range plan {
+		if action.Action != want[i] {
+			t.Fatalf("expected order %v, got %+v", want, plan)
+		}
+	}
+	if plan[1].Impact != 5 || plan[2].Impact != 3 {
+		t.Fatalf("expected impacts from the default weights, got %+v", plan)
+	}
+}
+
+func TestBuildFixPlan_ImpactFollowsPenaltyCurves(t *testing.T) {
+	weights := DefaultScoringWeights()
+	// Capped curve: 2 size violations cost 4 points instead of 6, so fixing
+	// one of them recovers only 1 point
+	curve, err := ParsePenaltyExpr("min(4, count * 3)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	weights.SizeCurve = curve
+
+	plan := BuildFixPlan(fixPlanReport(), weights)
+	last := plan[len(plan)-1]
+	if last.Action != "shrink-file" && last.Action != "shrink-function" {
+		t.Fatalf("expected size actions last, got %+v", plan)
+	}
+	if last.Impact != 1 {
+		t.Fatalf("expected 1 point recovered (penalty 4 -> 3), got %v", last.Impact)
+	}
+
+	data := formatFixPlan(plan)
+	var decoded []FixAction
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil || len(decoded) != len(plan) {
+		t.Fatalf("expected a JSON array of actions, got %q (%v)", data, err)
+	}
+}
@@ -131,8 +131,8 @@ func (r *{{.TypeName}}Rule) Category() string {
 }
 
 // Severity returns the default severity.
-func (r *{{.TypeName}}Rule) Severity() string {
-	return "warning"
+func (r *{{.TypeName}}Rule) Severity() model.Severity {
+	return model.SeverityWarning
 }
 
 // Evaluate checks for {{.RuleName}} violations in the analysis context.
@@ -184,8 +184,8 @@ func (r *%sRule) Category() string {
 }
 
 // Severity returns the default severity.
-func (r *%sRule) Severity() string {
-	return "warning"
+func (r *%sRule) Severity() model.Severity {
+	return model.SeverityWarning
 }
 
 // Evaluate checks for %s violations in the analysis context.
@@ -280,8 +280,8 @@ func Test%sRule_Category(t *testing.T) {
 func Test%sRule_Severity(t *testing.T) {
 	rule := New%sRule()
 	
-	if rule.Severity() == "" {
-		t.Error("Expected non-empty severity")
+	if !rule.Severity().Valid() {
+		t.Error("Expected a valid severity")
 	}
 }
 
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// gitRepository runs git plumbing commands against one repository
+type gitRepository struct {
+	dir string
+}
+
+// run executes git in the repository and returns its trimmed stdout. The
+// error carries git's stderr, which names the ref or path it rejected.
+func (g gitRepository) run(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", g.dir}, args...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), firstNonEmpty(strings.TrimSpace(stderr.String()), err.Error()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// resolveCommit returns the full hash of the commit ref points to
+func (g gitRepository) resolveCommit(ref string) (string, error) {
+	return g.run("rev-parse", "--verify", ref+"^{commit}")
+}
+
+// firstParentRange lists the commits after good up to and including bad,
+// oldest first, following first parents so merged branches count as the
+// merge commit that brought them in
+func (g gitRepository) firstParentRange(good, bad string) ([]string, error) {
+	out, err := g.run("rev-list", "--reverse", "--first-parent", "--ancestry-path", good+".."+bad)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// subject returns the first line of a commit message
+func (g gitRepository) subject(commit string) string {
+	out, err := g.run("log", "-1", "--format=%s", commit)
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// addWorktree checks out commit in a detached temporary worktree. The
+// returned cleanup removes the worktree and its directory.
+func (g gitRepository) addWorktree(commit string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "repodoctor-worktree-")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := g.run("worktree", "add", "--detach", dir, commit); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	cleanup := func() {
+		_, _ = g.run("worktree", "remove", "--force", dir)
+		os.RemoveAll(dir)
+	}
+	return dir, cleanup, nil
+}
@@ -7,6 +7,6 @@ require gopkg.in/yaml.v3 v3.0.1
 require github.com/fsnotify/fsnotify v1.9.0 // indirect
 
 require (
-	golang.org/x/sys v0.41.0 // indirect
+	golang.org/x/sys v0.41.0
 	gopkg.in/fsnotify.v1 v1.4.7
 )
@@ -2,11 +2,14 @@ package main
 
 import (
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
 )
 
 // GodObjectViolation represents a god object detection violation
@@ -15,6 +18,14 @@ type GodObjectViolation struct {
 	File        string
 	FieldCount  int
 	MethodCount int
+	// Line is where the struct is declared, when known
+	Line int `json:",omitempty"`
+	// StartLine and EndLine span the struct's type declaration, when known
+	StartLine int `json:",omitempty"`
+	EndLine   int `json:",omitempty"`
+	// Severity is the severity the rule reported; it is not serialized so
+	// the report schema stays unchanged
+	Severity model.Severity `json:"-"`
 }
 
 // GodObjectRule detects structs that violate single responsibility principle
@@ -22,8 +33,15 @@ type GodObjectRule struct {
 	MaxFields  int
 	MaxMethods int
 	Exclude    []string
+	// Excludes are globs of files, relative to the checked directory, that
+	// are not checked
+	Excludes []string
+	// Tests selects whether Go test files are checked; by default they are
+	// not
+	Tests      TestFileMode
 	violations []GodObjectViolation
-	fset       *token.FileSet
+	// sources is shared with the size rule when set by the scorer
+	sources *astCache
 }
 
 // NewGodObjectRule creates a new god object detection rule
@@ -33,7 +51,6 @@ func NewGodObjectRule() *GodObjectRule {
 		MaxMethods: 10,
 		Exclude:    []string{"internal/"},
 		violations: make([]GodObjectViolation, 0),
-		fset:       token.NewFileSet(),
 	}
 }
 
@@ -47,39 +64,23 @@ func (r *GodObjectRule) shouldExclude(filePath string) bool {
 	return false
 }
 
-// Check analyzes the given directory for god object violations
+// Check analyzes the given directory for god object violations. Files are
+// parsed once, concurrently; violations are sorted by file, line and name.
 func (r *GodObjectRule) Check(dirPath string) error {
 	r.violations = make([]GodObjectViolation, 0)
 
-	// Map to track methods per struct (struct name -> method count)
-	structMethods := make(map[string]*structInfo)
-
-	// First pass: collect all struct definitions and their fields
-	err := r.walkDir(dirPath, func(filePath string) error {
-		// Skip excluded files
-		if r.shouldExclude(filePath) {
-			return nil
-		}
-		return r.collectStructs(filePath, structMethods)
-	})
+	files, err := collectRuleFiles(dirPath, r.Excludes, r.Tests)
 	if err != nil {
 		return err
 	}
 
-	// Second pass: collect all method declarations
-	err = r.walkDir(dirPath, func(filePath string) error {
-		// Skip excluded files
-		if r.shouldExclude(filePath) {
-			return nil
-		}
-		return r.collectMethods(filePath, structMethods)
-	})
+	structMethods, err := r.collectDeclarations(files)
 	if err != nil {
 		return err
 	}
 
 	// Check for violations
-	for structName, info := range structMethods {
+	for _, info := range structMethods {
 		isViolation := false
 		fieldCount := info.FieldCount
 		methodCount := info.MethodCount
@@ -96,71 +97,103 @@ func (r *GodObjectRule) Check(dirPath string) error {
 
 		if isViolation {
 			r.violations = append(r.violations, GodObjectViolation{
-				StructName:  structName,
+				StructName:  info.Name,
 				File:        info.File,
+				Line:        info.Line,
+				StartLine:   info.Line,
+				EndLine:     info.EndLine,
 				FieldCount:  fieldCount,
 				MethodCount: methodCount,
 			})
 		}
 	}
 
+	sort.Slice(r.violations, func(i, j int) bool {
+		a, b := r.violations[i], r.violations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.StructName < b.StructName
+	})
 	return nil
 }
 
+// collectDeclarations parses every file once, concurrently, and returns the
+// structs found with their field and method counts
+func (r *GodObjectRule) collectDeclarations(files []string) (map[string]*structInfo, error) {
+	// Map to track methods per struct (package-qualified key -> info), so
+	// same-named structs in different packages are counted separately
+	structMethods := make(map[string]*structInfo)
+	packages := make(map[string]*rules.PackageTypes)
+	var receivers []string
+
+	// Collect all struct definitions and the receivers of all methods
+	sources := r.sources.orNew()
+	var mu sync.Mutex
+	err := forEachFile(files, func(filePath string) error {
+		// Skip excluded files
+		if r.shouldExclude(filePath) {
+			return nil
+		}
+		source := sources.parse(filePath)
+		if source.node == nil {
+			return source.err
+		}
+		keys := methodReceiverKeys(filePath, source.node)
+
+		mu.Lock()
+		defer mu.Unlock()
+		collectStructs(filePath, source.node, source.fset, structMethods, packages)
+		receivers = append(receivers, keys...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Count the fields of embedded structs and the methods of embedded
+	// interfaces, within each struct's package, then the declared methods
+	for _, info := range structMethods {
+		types := rules.PackageTypesOf(packages, info.File)
+		info.FieldCount = types.FieldCount(info.Name)
+		info.MethodCount = types.EmbeddedMethodCount(info.Name)
+	}
+	for _, key := range receivers {
+		if info, exists := structMethods[key]; exists {
+			info.MethodCount++
+		}
+	}
+	return structMethods, nil
+}
+
 // structInfo holds information about a struct
 type structInfo struct {
+	Name        string // bare struct name for display
 	File        string
+	Line        int
+	EndLine     int
 	FieldCount  int
 	MethodCount int
 }
 
+// structKey returns a package-qualified key for a struct. Go requires
+// methods to reside in the same package (directory) as their receiver
+// type, so Dir+Name is unique.
+func structKey(filePath, structName string) string {
+	return filepath.Dir(filePath) + "#" + structName
+}
+
 // Violations returns all detected god object violations
 func (r *GodObjectRule) Violations() []GodObjectViolation {
 	return r.violations
 }
 
-// walkDir walks through a directory and calls the callback for each Go file
-func (r *GodObjectRule) walkDir(root string, callback func(string) error) error {
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip files with errors
-		}
-
-		// Skip directories
-		if info.IsDir() {
-			// Skip hidden directories
-			if strings.HasPrefix(info.Name(), ".") {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Skip non-Go files
-		if !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-
-		// Skip hidden files
-		if strings.HasPrefix(info.Name(), ".") {
-			return nil
-		}
-
-		return callback(path)
-	})
-}
-
-// collectStructs collects all struct definitions and their field counts
-func (r *GodObjectRule) collectStructs(filePath string, structMethods map[string]*structInfo) error {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
-	}
-
-	node, err := parser.ParseFile(r.fset, filePath, content, 0)
-	if err != nil {
-		return nil // Skip malformed files
-	}
-
+// collectStructs collects all struct definitions, and the struct and
+// interface declarations that embedding is resolved against
+func collectStructs(filePath string, node *ast.File, fset *token.FileSet, structMethods map[string]*structInfo, packages map[string]*rules.PackageTypes) {
 	// Walk through all declarations
 	ast.Inspect(node, func(n ast.Node) bool {
 		typeSpec, ok := n.(*ast.TypeSpec)
@@ -168,41 +201,36 @@ func (r *GodObjectRule) collectStructs(filePath string, structMethods map[string
 			return true
 		}
 
-		structType, ok := typeSpec.Type.(*ast.StructType)
-		if !ok {
+		types := rules.PackageTypesOf(packages, filePath)
+		if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+			types.AddInterface(rules.NewInterfaceMethods(typeSpec.Name.Name, iface))
 			return true
 		}
 
-		// Count fields
-		fieldCount := 0
-		if structType.Fields != nil {
-			fieldCount = structType.Fields.NumFields()
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
 		}
 
+		// Fields are counted once every struct of the package is known
 		structName := typeSpec.Name.Name
-		structMethods[structName] = &structInfo{
-			File:        filePath,
-			FieldCount:  fieldCount,
-			MethodCount: 0,
+		line := fset.Position(typeSpec.Pos()).Line
+		types.AddStruct(rules.NewStructFields(structName, structType, line))
+		structMethods[structKey(filePath, structName)] = &structInfo{
+			Name:    structName,
+			File:    filePath,
+			Line:    line,
+			EndLine: fset.Position(typeSpec.End()).Line,
 		}
 
 		return true
 	})
-
-	return nil
 }
 
-// collectMethods collects all method declarations for each struct
-func (r *GodObjectRule) collectMethods(filePath string, structMethods map[string]*structInfo) error {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
-	}
-
-	node, err := parser.ParseFile(r.fset, filePath, content, 0)
-	if err != nil {
-		return nil // Skip malformed files
-	}
+// methodReceiverKeys returns the struct key of the receiver of every
+// method declared in a file
+func methodReceiverKeys(filePath string, node *ast.File) []string {
+	var keys []string
 
 	// Walk through all declarations
 	ast.Inspect(node, func(n ast.Node) bool {
@@ -227,17 +255,14 @@ func (r *GodObjectRule) collectMethods(filePath string, structMethods map[string
 
 			// Get the type name
 			if ident, ok := recvType.(*ast.Ident); ok {
-				structName := ident.Name
-				if info, exists := structMethods[structName]; exists {
-					info.MethodCount++
-				}
+				keys = append(keys, structKey(filePath, ident.Name))
 			}
 		}
 
 		return true
 	})
 
-	return nil
+	return keys
 }
 
 // HasCriticalViolations returns true if any god object violations found
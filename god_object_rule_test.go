@@ -93,6 +93,9 @@ type TestStruct struct {}
 	for _, v := range violations {
 		if v.StructName == "TestStruct" && v.MethodCount > 10 {
 			foundViolation = true
+			if v.Line != 3 {
+				t.Errorf("Expected TestStruct to be declared on line 3, got %d", v.Line)
+			}
 		}
 	}
 
@@ -203,3 +206,71 @@ type GodStruct struct {
 		t.Errorf("Expected no violations for hidden file, got %d", len(violations))
 	}
 }
+
+func TestGodObjectRule_SameNameInDifferentPackages(t *testing.T) {
+	// Create two packages that each define a Service with 6 methods; merged
+	// by name they would exceed the 10 method threshold
+	tmpDir := t.TempDir()
+	for _, pkg := range []string{"billing", "shipping"} {
+		content := "package " + pkg + "\n\ntype Service struct{}\n\n"
+		for i := 0; i < 6; i++ {
+			content += "func (s *Service) Method" + string(rune('A'+i)) + "() {}\n"
+		}
+		if err := os.MkdirAll(filepath.Join(tmpDir, pkg), 0755); err != nil {
+			t.Fatalf("Failed to create package dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, pkg, "service.go"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	rule := NewGodObjectRule()
+	if err := rule.Check(tmpDir); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	if violations := rule.Violations(); len(violations) != 0 {
+		t.Errorf("Expected no violations for same-named structs in different packages, got: %+v", violations)
+	}
+}
+
+func TestGodObjectRule_CountsEmbeddedFieldsAndInterfaceMethods(t *testing.T) {
+	// Base has 12 fields and lives in another file of the package; Wide
+	// embeds it next to 5 fields of its own, 17 in all
+	tmpDir := t.TempDir()
+	base := "package store\n\ntype Base struct {\n"
+	for i := 0; i < 12; i++ {
+		base += "\tBase" + string(rune('A'+i)) + " int\n"
+	}
+	base += "}\n\ntype Reader interface {\n\tGet()\n\tList()\n}\n\ntype ReadWriter interface {\n\tReader\n\tPut()\n\tDelete()\n}\n"
+	wide := "package store\n\ntype Wide struct {\n\t*Base\n\tA, B int\n\tC    string\n\tD    bool\n\tE    error\n}\n"
+	// Facade embeds 4 interface methods and declares 7, 11 in all
+	facade := "package store\n\ntype Facade struct {\n\tReadWriter\n}\n\n"
+	for i := 0; i < 7; i++ {
+		facade += "func (f *Facade) Method" + string(rune('A'+i)) + "() {}\n"
+	}
+	for name, content := range map[string]string{"base.go": base, "wide.go": wide, "facade.go": facade} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	rule := NewGodObjectRule()
+	if err := rule.Check(tmpDir); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	found := make(map[string]GodObjectViolation)
+	for _, v := range rule.Violations() {
+		found[v.StructName] = v
+	}
+	if v, ok := found["Wide"]; !ok || v.FieldCount != 17 {
+		t.Errorf("Expected Wide to be reported with 17 fields, got %+v", found)
+	}
+	if v, ok := found["Facade"]; !ok || v.MethodCount != 11 {
+		t.Errorf("Expected Facade to be reported with 11 methods, got %+v", found)
+	}
+	if _, ok := found["Base"]; ok || len(found) != 2 {
+		t.Errorf("Expected only Wide and Facade to be reported, got %+v", found)
+	}
+}
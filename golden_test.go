@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite golden files under testdata/golden")
+
+// assertGolden compares got against testdata/golden/<name>, rewriting the
+// file instead when the test binary runs with -update-golden
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name)
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s (run with -update-golden to create it): %v", path, err)
+	}
+
+	if diff := goldenDiff(string(want), got); diff != "" {
+		t.Fatalf("output does not match %s (run with -update-golden to accept):\n%s", path, diff)
+	}
+}
+
+// goldenDiff returns a line-oriented diff of want and got, or "" when they
+// are identical. Differing lines are shown as -want/+got with line numbers.
+func goldenDiff(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	total := len(wantLines)
+	if len(gotLines) > total {
+		total = len(gotLines)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < total; i++ {
+		var w, g string
+		wOK, gOK := i < len(wantLines), i < len(gotLines)
+		if wOK {
+			w = wantLines[i]
+		}
+		if gOK {
+			g = gotLines[i]
+		}
+		if wOK && gOK && w == g {
+			continue
+		}
+		if wOK {
+			sb.WriteString(fmt.Sprintf("%4d - %s\n", i+1, w))
+		}
+		if gOK {
+			sb.WriteString(fmt.Sprintf("%4d + %s\n", i+1, g))
+		}
+	}
+	return sb.String()
+}
+
+func goldenFixtureReport() *StructuralReport {
+	return &StructuralReport{
+		Version:       "0.5.0-dev",
+		SchemaVersion: "v2",
+		Path:          "demo/repo",
+		Score: &StructuralScore{
+			TotalScore:       72,
+			MaxScore:         100,
+			CircularPenalty:  10,
+			LayerPenalty:     5,
+			SizePenalty:      6,
+			GodObjectPenalty: 5,
+			ViolationCount:   5,
+			CircularCount:    1,
+			LayerCount:       1,
+			SizeCount:        2,
+			GodObjectCount:   1,
+		},
+		Circular: []CycleViolation{{Path: []string{"demo/repo/service/a.go", "demo/repo/service/b.go"}, Severity: model.SeverityCritical}},
+		Layer: []LayerViolation{{
+			From:    "demo/repo/repo/store.go",
+			To:      "demo/repo/handler/http.go",
+			Message: "demo/repo/repo/store.go (repo) -> demo/repo/handler/http.go (handler): upward import not allowed",
+		}},
+		Size: []SizeViolation{
+			{File: "demo/repo/service/big.go", Function: "Process", Lines: 120, Threshold: 80},
+			{File: "demo/repo/service/big.go", Lines: 640, Threshold: 500},
+		},
+		GodObject:     []GodObjectViolation{{StructName: "Manager", File: "demo/repo/service/manager.go", FieldCount: 18, MethodCount: 12}},
+		Summary:       ReportSummary{TotalViolations: 5, Circular: 1, Layer: 1, Size: 2, GodObject: 1},
+		Language:      LanguageEvidenceSummary{DetectedLanguage: "Go", Confidence: 0.97},
+		HasViolations: true,
+	}
+}
+
+func TestReporter_GoldenOutputs(t *testing.T) {
+	tests := []struct {
+		golden string
+		format OutputFormat
+	}{
+		{golden: "report.txt", format: FormatText},
+		{golden: "report.json", format: FormatJSON},
+		{golden: "report.v1.json", format: FormatJSONV1},
+		{golden: "report.md", format: FormatMarkdown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.golden, func(t *testing.T) {
+			reporter := NewReporter(tc.format)
+			reporter.width = 100
+			assertGolden(t, tc.golden, reporter.Format(goldenFixtureReport()))
+		})
+	}
+}
+
+func TestGoldenDiff_ReportsChangedLines(t *testing.T) {
+	if diff := goldenDiff("a\nb\n", "a\nb\n"); diff != "" {
+		t.Fatalf("expected empty diff for identical input, got %q", diff)
+	}
+
+	diff := goldenDiff("a\nb\nc", "a\nB\nc\nd")
+	if !strings.Contains(diff, "2 - b") || !strings.Contains(diff, "2 + B") || !strings.Contains(diff, "4 + d") {
+		t.Fatalf("unexpected diff output:\n%s", diff)
+	}
+	if strings.Contains(diff, "- a") {
+		t.Fatalf("unchanged lines must not appear in diff:\n%s", diff)
+	}
+}
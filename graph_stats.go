@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeDegree pairs a graph node with an edge count
+type NodeDegree struct {
+	Node  string `json:"node"`
+	Count int    `json:"count"`
+}
+
+// GraphStats summarises the structure of a dependency graph
+type GraphStats struct {
+	Nodes                int          `json:"nodes"`
+	Edges                int          `json:"edges"`
+	TestEdges            int          `json:"testEdges"`
+	SCCCount             int          `json:"sccCount"`
+	MaxDepth             int          `json:"maxDepth"`
+	TopFanIn             []NodeDegree `json:"topFanIn"`
+	TopFanOut            []NodeDegree `json:"topFanOut"`
+	ExternalDependencies int          `json:"externalDependencies"`
+}
+
+// ComputeGraphStats derives structural statistics from a graph. SCCCount
+// counts strongly connected components that contain a cycle, MaxDepth is the
+// longest dependency chain (in nodes) after collapsing those components, and
+// the external dependencies are the distinct third-party modules of the
+// inventory, so standard library and own-module imports do not count.
+// Edges only test files declare are counted apart and left out of the other
+// statistics.
+func ComputeGraphStats(graph Graph, inventory *DependencyInventory, topN int) *GraphStats {
+	nodes := graph.GetAllNodes()
+
+	adjacency := make(map[string][]string, len(nodes))
+	fanIn := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		deps := graph.GetDependencies(node)
+		adjacency[node] = deps
+		for _, dep := range deps {
+			fanIn[dep]++
+		}
+	}
+
+	stats := &GraphStats{
+		Nodes:                graph.GetNodeCount(),
+		Edges:                graph.GetEdgeCount(),
+		ExternalDependencies: inventory.Count,
+	}
+	if testGraph, ok := graph.(TestEdgeGraph); ok {
+		for _, node := range nodes {
+			stats.TestEdges += len(testGraph.GetTestDependencies(node))
+		}
+	}
+
+	fanOut := make([]NodeDegree, 0, len(nodes))
+	incoming := make([]NodeDegree, 0, len(nodes))
+	for _, node := range nodes {
+		if len(adjacency[node]) > 0 {
+			fanOut = append(fanOut, NodeDegree{Node: node, Count: len(adjacency[node])})
+		}
+		if fanIn[node] > 0 {
+			incoming = append(incoming, NodeDegree{Node: node, Count: fanIn[node]})
+		}
+	}
+	stats.TopFanIn = topDegrees(incoming, topN)
+	stats.TopFanOut = topDegrees(fanOut, topN)
+
+	components := stronglyConnectedComponents(nodes, adjacency)
+	for _, component := range components {
+		if len(component) > 1 || hasSelfLoop(component[0], adjacency) {
+			stats.SCCCount++
+		}
+	}
+	stats.MaxDepth = condensedDepth(components, adjacency)
+
+	return stats
+}
+
+// relativeTo makes the fan-in and fan-out node paths relative to base, as
+// reported file paths are
+func (s *GraphStats) relativeTo(base string) {
+	for _, degrees := range [][]NodeDegree{s.TopFanIn, s.TopFanOut} {
+		for i := range degrees {
+			degrees[i].Node = relativeToBase(degrees[i].Node, base)
+		}
+	}
+}
+
+// topDegrees returns the n highest counts, breaking ties by node name
+func topDegrees(degrees []NodeDegree, n int) []NodeDegree {
+	sort.SliceStable(degrees, func(i, j int) bool {
+		if degrees[i].Count != degrees[j].Count {
+			return degrees[i].Count > degrees[j].Count
+		}
+		return degrees[i].Node < degrees[j].Node
+	})
+	if n >= 0 && len(degrees) > n {
+		degrees = degrees[:n]
+	}
+	return degrees
+}
+
+func hasSelfLoop(node string, adjacency map[string][]string) bool {
+	for _, dep := range adjacency[node] {
+		if dep == node {
+			return true
+		}
+	}
+	return false
+}
+
+// stronglyConnectedComponents runs Tarjan's algorithm over sorted nodes so
+// the component order is deterministic
+func stronglyConnectedComponents(nodes []string, adjacency map[string][]string) [][]string {
+	index := 0
+	indices := make(map[string]int, len(nodes))
+	lowLinks := make(map[string]int, len(nodes))
+	onStack := make(map[string]bool, len(nodes))
+	stack := make([]string, 0)
+	components := make([][]string, 0)
+
+	var connect func(node string)
+	connect = func(node string) {
+		indices[node] = index
+		lowLinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, dep := range adjacency[node] {
+			if _, seen := indices[dep]; !seen {
+				connect(dep)
+				if lowLinks[dep] < lowLinks[node] {
+					lowLinks[node] = lowLinks[dep]
+				}
+			} else if onStack[dep] && indices[dep] < lowLinks[node] {
+				lowLinks[node] = indices[dep]
+			}
+		}
+
+		if lowLinks[node] == indices[node] {
+			component := make([]string, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, seen := indices[node]; !seen {
+			connect(node)
+		}
+	}
+
+	return components
+}
+
+// condensedDepth returns the longest chain of components in the condensation
+// of the graph, counting every component as one step
+func condensedDepth(components [][]string, adjacency map[string][]string) int {
+	componentOf := make(map[string]int)
+	for i, component := range components {
+		for _, node := range component {
+			componentOf[node] = i
+		}
+	}
+
+	// Tarjan emits components in reverse topological order, so every
+	// dependency's depth is known before its dependents are visited.
+	depths := make([]int, len(components))
+	maxDepth := 0
+	for i, component := range components {
+		depth := 1
+		for _, node := range component {
+			for _, dep := range adjacency[node] {
+				target := componentOf[dep]
+				if target != i && depths[target]+1 > depth {
+					depth = depths[target] + 1
+				}
+			}
+		}
+		depths[i] = depth
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+
+	return maxDepth
+}
+
+// formatGraphStats renders graph statistics as text or JSON
+func formatGraphStats(stats *GraphStats, format string) string {
+	if format == string(FormatJSON) || format == string(FormatJSONV1) {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return "{}\n"
+		}
+		return string(data) + "\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📊 Dependency Graph Statistics\n")
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	sb.WriteString(fmt.Sprintf("Nodes:                 %d\n", stats.Nodes))
+	sb.WriteString(fmt.Sprintf("Edges:                 %d\n", stats.Edges))
+	if stats.TestEdges > 0 {
+		sb.WriteString(fmt.Sprintf("Test-only edges:       %d\n", stats.TestEdges))
+	}
+	sb.WriteString(fmt.Sprintf("Cyclic components:     %d\n", stats.SCCCount))
+	sb.WriteString(fmt.Sprintf("Max depth:             %d\n", stats.MaxDepth))
+	sb.WriteString(fmt.Sprintf("External dependencies: %d\n", stats.ExternalDependencies))
+	writeNodeDegrees(&sb, "Top fan-in", stats.TopFanIn)
+	writeNodeDegrees(&sb, "Top fan-out", stats.TopFanOut)
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	return sb.String()
+}
+
+func writeNodeDegrees(sb *strings.Builder, title string, degrees []NodeDegree) {
+	sb.WriteString(fmt.Sprintf("\n%s:\n", title))
+	if len(degrees) == 0 {
+		sb.WriteString("   (none)\n")
+		return
+	}
+	for _, d := range degrees {
+		sb.WriteString(fmt.Sprintf("   • %s (%d)\n", d.Node, d.Count))
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComputeGraphStats_CountsComponentsDepthAndDegrees(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddEdge("a.go", "b.go")
+	graph.AddEdge("b.go", "a.go")
+	graph.AddEdge("b.go", "c.go")
+	graph.AddEdge("c.go", "ext/lib")
+	graph.AddEdge("d.go", "ext/lib")
+	graph.AddEdge("d.go", "c.go")
+
+	stats := ComputeGraphStats(graph, &DependencyInventory{}, 2)
+
+	if stats.Nodes != 5 || stats.Edges != 6 {
+		t.Fatalf("expected 5 nodes and 6 edges, got %d and %d", stats.Nodes, stats.Edges)
+	}
+	if stats.SCCCount != 1 {
+		t.Errorf("expected 1 cyclic component, got %d", stats.SCCCount)
+	}
+	if stats.MaxDepth != 3 {
+		t.Errorf("expected max depth 3 ({a,b} -> c -> ext/lib), got %d", stats.MaxDepth)
+	}
+
+	wantFanIn := []NodeDegree{{Node: "c.go", Count: 2}, {Node: "ext/lib", Count: 2}}
+	if !reflect.DeepEqual(stats.TopFanIn, wantFanIn) {
+		t.Errorf("unexpected top fan-in: %+v", stats.TopFanIn)
+	}
+	wantFanOut := []NodeDegree{{Node: "b.go", Count: 2}, {Node: "d.go", Count: 2}}
+	if !reflect.DeepEqual(stats.TopFanOut, wantFanOut) {
+		t.Errorf("unexpected top fan-out: %+v", stats.TopFanOut)
+	}
+}
+
+func TestComputeGraphStats_GraphOnlyMatchesFullRun(t *testing.T) {
+	repo := t.TempDir()
+	files := map[string]string{
+		"main.go":            "package main\n\nimport (\n\t\"fmt\"\n\t\"example.com/demo/service\"\n)\n\nfunc main() { fmt.Println(service.Name()) }\n",
+		"service/service.go": "package service\n\nimport \"example.com/demo/repo\"\n\nfunc Name() string { return repo.Name() }\n",
+		"repo/repo.go":       "package repo\n\nimport \"strings\"\n\nfunc Name() string { return strings.ToUpper(\"x\") }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	orchestrator := newAnalysisOrchestrator(repo)
+	full, err := orchestrator.Analyze(repo)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	graphOnly, err := orchestrator.AnalyzeGraph(repo)
+	if err != nil {
+		t.Fatalf("AnalyzeGraph failed: %v", err)
+	}
+	if graphOnly.Metrics != nil {
+		t.Fatal("graph-only run must not collect metrics")
+	}
+
+	fullGraph := buildDependencyGraphFromModel(full.Graph, false)
+	onlyGraph := buildDependencyGraphFromModel(graphOnly.Graph, false)
+	fullStats := ComputeGraphStats(fullGraph, buildDependencyInventory(repo, fullGraph, nil, nil), graphStatsTopN)
+	onlyStats := ComputeGraphStats(onlyGraph, buildDependencyInventory(repo, onlyGraph, nil, nil), graphStatsTopN)
+	if !reflect.DeepEqual(fullStats, onlyStats) {
+		t.Fatalf("graph-only stats differ from full run\nfull: %+v\nonly: %+v", fullStats, onlyStats)
+	}
+	if fullStats.Nodes == 0 {
+		t.Fatalf("expected non-empty graph stats, got %+v", fullStats)
+	}
+}
+
+func TestFormatGraphStats_TextAndJSON(t *testing.T) {
+	stats := &GraphStats{Nodes: 2, Edges: 1, MaxDepth: 2, TopFanIn: []NodeDegree{{Node: "b", Count: 1}}}
+
+	text := formatGraphStats(stats, "text")
+	if !strings.Contains(text, "Nodes:                 2") || !strings.Contains(text, "• b (1)") {
+		t.Fatalf("unexpected text output: %s", text)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(formatGraphStats(stats, "json")), &payload); err != nil {
+		t.Fatalf("json output must be valid: %v", err)
+	}
+	if payload["maxDepth"] != float64(2) {
+		t.Fatalf("expected maxDepth in json output, got %v", payload["maxDepth"])
+	}
+}
+
+func TestComputeGraphStats_CountsTestEdgesApart(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddEdge("a.go", "b.go")
+	graph.AddTestEdge("a_test.go", "a.go")
+	graph.AddTestEdge("a.go", "b.go")
+
+	stats := ComputeGraphStats(graph, &DependencyInventory{}, 5)
+	if stats.Edges != 1 || stats.TestEdges != 1 {
+		t.Fatalf("expected 1 production and 1 test-only edge, got %d and %d", stats.Edges, stats.TestEdges)
+	}
+	if !strings.Contains(formatGraphStats(stats, "text"), "Test-only edges:       1") {
+		t.Fatalf("expected the text output to show test-only edges:\n%s", formatGraphStats(stats, "text"))
+	}
+}
+
+func TestRunGraphOnly_CountsOnlyThirdPartyModules(t *testing.T) {
+	repo := t.TempDir()
+	writeServiceFixture(t, repo, map[string]string{
+		"go.mod":             "module example.com/demo\n\ngo 1.21\n",
+		"main.go":            "package main\n\nimport (\n\t\"fmt\"\n\t\"example.com/demo/service\"\n)\n\nfunc main() { fmt.Println(service.Name()) }\n",
+		"service/service.go": "package service\n\nimport \"example.com/demo/repo\"\n\nfunc Name() string { return repo.Name() }\n",
+		"repo/repo.go":       "package repo\n\nimport \"strings\"\n\nfunc Name() string { return strings.ToUpper(\"x\") }\n",
+	})
+
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = NewAnalysisService().RunGraphOnly(AnalyzeRequest{Path: repo, Format: "json"})
+	})
+	if runErr != nil {
+		t.Fatalf("graph-only run failed: %v", runErr)
+	}
+	var stats GraphStats
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("invalid json output: %v\n%s", err, out)
+	}
+	if stats.ExternalDependencies != 0 {
+		t.Errorf("expected standard library and own-module imports not to count, got %d", stats.ExternalDependencies)
+	}
+	for _, degree := range append(stats.TopFanIn, stats.TopFanOut...) {
+		if filepath.IsAbs(degree.Node) {
+			t.Errorf("expected node paths relative to the analyzed root, got %s", degree.Node)
+		}
+	}
+}
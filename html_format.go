@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// FormatHTML prints the report as a self-contained HTML page, for
+// publishing as a CI build artifact
+const FormatHTML OutputFormat = "html"
+
+// htmlReport is the view the HTML template renders. Paths are relative to
+// the analyzed directory.
+type htmlReport struct {
+	Version          string
+	Path             string
+	Score            float64
+	MaxScore         float64
+	Tier             string
+	Summary          htmlSummary
+	Cycles           []htmlCycle
+	Layer            []LayerViolation
+	FeatureIsolation []FeatureIsolationViolation
+	DependencyCap    []DependencyCapViolation
+	Size             []htmlSizeRow
+	GodObjects       []GodObjectViolation
+	Trend            *htmlTrend
+}
+
+type htmlSummary struct {
+	Total, Circular, Layer, FeatureIsolation, DependencyCap, Size, GodObject int
+}
+
+type htmlCycle struct {
+	Number int
+	Path   string
+	Note   string
+}
+
+type htmlSizeRow struct {
+	File    string
+	Message string
+}
+
+// htmlTrend is the score sparkline. Points are the polyline coordinates in
+// a Width x Height box.
+type htmlTrend struct {
+	Points        string
+	Width, Height int
+	Runs          int
+	First, Last   float64
+}
+
+const (
+	htmlSparklineWidth  = 200
+	htmlSparklineHeight = 40
+)
+
+// htmlScoreTrend returns the scores the HTML sparkline draws: the recorded
+// history entries of the report's score model, oldest first, followed by
+// the report's own score. A history that cannot be read draws nothing.
+func htmlScoreTrend(absPath string, report *StructuralReport) []float64 {
+	if report.Score == nil {
+		return nil
+	}
+	analyzer := NewTrendAnalyzer(absPath)
+	if err := analyzer.LoadHistory(); err != nil {
+		return nil
+	}
+	return append(modelScores(analyzer.GetAllHistory(), report.Score.Model), report.Score.TotalScore)
+}
+
+// newHTMLTrend scales scores onto the sparkline box, with 0 at the bottom
+// and maxScore at the top. It returns nil for fewer than two scores, since
+// one run shows no trend.
+func newHTMLTrend(scores []float64, maxScore float64) *htmlTrend {
+	if len(scores) < 2 || maxScore <= 0 {
+		return nil
+	}
+	points := make([]string, len(scores))
+	step := float64(htmlSparklineWidth) / float64(len(scores)-1)
+	for i, score := range scores {
+		clamped := max(0, score)
+		if clamped > maxScore {
+			clamped = maxScore
+		}
+		y := htmlSparklineHeight * (1 - clamped/maxScore)
+		points[i] = fmt.Sprintf("%.1f,%.1f", float64(i)*step, y)
+	}
+	return &htmlTrend{Points: strings.Join(points, " "), Width: htmlSparklineWidth, Height: htmlSparklineHeight, Runs: len(scores), First: scores[0], Last: scores[len(scores)-1]}
+}
+
+// htmlScoreTier styles a score like the text report's indicator: "ok" from
+// 70% of the maximum, "warning" from 50%, "critical" below
+func htmlScoreTier(score, max float64) string {
+	percent := score / max * 100
+	switch {
+	case percent < 50:
+		return "critical"
+	case percent < 70:
+		return "warning"
+	default:
+		return "ok"
+	}
+}
+
+// formatHTML renders the score, a sparkline of the score history when there
+// is one, the violations summary and one collapsible table per circular,
+// layer, feature isolation, dependency cap, size and god object category. The page embeds its styles and
+// needs no external assets.
+func formatHTML(report *StructuralReport) string {
+	relative := func(file string) string {
+		return relativeToBase(file, report.Path)
+	}
+	view := htmlReport{Version: report.Version, Path: report.Path, MaxScore: 100, Tier: "ok"}
+	if score := report.Score; score != nil {
+		view.Score, view.MaxScore = score.TotalScore, scoreScale(score)
+		view.Tier = htmlScoreTier(view.Score, view.MaxScore)
+		view.Summary = htmlSummary{score.ViolationCount, score.CircularCount, score.LayerCount, score.OptIn.FeatureIsolationCount, score.OptIn.DependencyCapCount, score.SizeCount, score.GodObjectCount}
+	}
+	view.Trend = newHTMLTrend(report.Metrics.Trend.Scores, view.MaxScore)
+	for _, v := range report.Circular {
+		cycle := make([]string, len(v.Path))
+		for i, file := range v.Path {
+			cycle[i] = relative(file)
+		}
+		view.Cycles = append(view.Cycles, htmlCycle{Number: len(view.Cycles) + 1, Path: formatCyclePath(cycle), Note: cycleBlankImportNote(v, relative)})
+	}
+	for _, v := range report.Layer {
+		view.Layer = append(view.Layer, LayerViolation{From: relative(v.From), To: relative(v.To), Message: v.Message})
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		view.FeatureIsolation = append(view.FeatureIsolation, FeatureIsolationViolation{From: relative(v.From), Message: v.Message})
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		view.DependencyCap = append(view.DependencyCap, DependencyCapViolation{Manifest: relative(v.Manifest), Message: v.Message})
+	}
+	for _, v := range report.Size {
+		view.Size = append(view.Size, htmlSizeRow{File: relative(v.File), Message: sizeViolationMessage(v)})
+	}
+	for _, v := range report.GodObject {
+		v.File = relative(v.File)
+		view.GodObjects = append(view.GodObjects, v)
+	}
+
+	var sb strings.Builder
+	if err := htmlReportTemplate.Execute(&sb, view); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>RepoDoctor report: {{.Path}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { margin-bottom: 0; }
+.meta { color: #656d76; }
+.score { display: inline-block; padding: .5rem 1rem; border-radius: 6px; font-size: 1.5rem; font-weight: bold; }
+.ok { background: #dafbe1; color: #1a7f37; }
+.warning { background: #fff8c5; color: #9a6700; }
+.critical { background: #ffebe9; color: #cf222e; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: .3rem .6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.note { color: #9a6700; font-size: .9em; }
+summary { cursor: pointer; }
+summary h2 { display: inline-block; }
+.sparkline { color: #0969da; }
+</style>
+</head>
+<body>
+<h1>RepoDoctor</h1>
+<p class="meta">Version {{.Version}} &middot; {{.Path}}</p>
+<h2>Structural health score</h2>
+<p class="score {{.Tier}}">{{printf "%.1f" .Score}} / {{printf "%.1f" .MaxScore}}</p>
+{{- with .Trend}}
+<h2>Score trend</h2>
+<svg class="sparkline" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="Score trend over {{.Runs}} runs"><polyline fill="none" stroke="currentColor" stroke-width="2" points="{{.Points}}"/></svg>
+<p class="meta">{{.Runs}} runs: {{printf "%.1f" .First}} &rarr; {{printf "%.1f" .Last}}</p>
+{{- end}}
+<h2>Violations summary</h2>
+<table>
+<tr><th>Total violations</th><td>{{.Summary.Total}}</td></tr>
+<tr><th>Circular dependencies</th><td>{{.Summary.Circular}}</td></tr>
+<tr><th>Layer violations</th><td>{{.Summary.Layer}}</td></tr>
+{{- if .Summary.FeatureIsolation}}
+<tr><th>Feature isolation violations</th><td>{{.Summary.FeatureIsolation}}</td></tr>
+{{- end}}
+{{- if .Summary.DependencyCap}}
+<tr><th>Dependency cap violations</th><td>{{.Summary.DependencyCap}}</td></tr>
+{{- end}}
+<tr><th>Size violations</th><td>{{.Summary.Size}}</td></tr>
+<tr><th>God objects</th><td>{{.Summary.GodObject}}</td></tr>
+</table>
+{{- if .Cycles}}
+<details open>
+<summary><h2>Circular dependencies</h2></summary>
+<table>
+<tr><th>#</th><th>Cycle</th></tr>
+{{- range .Cycles}}
+<tr><td>{{.Number}}</td><td>{{.Path}}{{if .Note}}<br><span class="note">{{.Note}}</span>{{end}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- if .Layer}}
+<details open>
+<summary><h2>Layer violations</h2></summary>
+<table>
+<tr><th>From</th><th>To</th><th>Message</th></tr>
+{{- range .Layer}}
+<tr><td>{{.From}}</td><td>{{.To}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- if .FeatureIsolation}}
+<details open>
+<summary><h2>Feature isolation violations</h2></summary>
+<table>
+<tr><th>File</th><th>Message</th></tr>
+{{- range .FeatureIsolation}}
+<tr><td>{{.From}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- if .DependencyCap}}
+<details open>
+<summary><h2>Dependency cap violations</h2></summary>
+<table>
+<tr><th>Manifest</th><th>Message</th></tr>
+{{- range .DependencyCap}}
+<tr><td>{{.Manifest}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- if .Size}}
+<details open>
+<summary><h2>Size violations</h2></summary>
+<table>
+<tr><th>File</th><th>Violation</th></tr>
+{{- range .Size}}
+<tr><td>{{.File}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- if .GodObjects}}
+<details open>
+<summary><h2>God objects</h2></summary>
+<table>
+<tr><th>Struct</th><th>File</th><th>Fields</th><th>Methods</th></tr>
+{{- range .GodObjects}}
+<tr><td>{{.StructName}}</td><td>{{.File}}</td><td>{{.FieldCount}}</td><td>{{.MethodCount}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+</body>
+</html>
+`))
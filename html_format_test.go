@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFormatHTML_EmptyReport(t *testing.T) {
+	out := NewReporter(FormatHTML).Format(&StructuralReport{Version: "1.0.0", Path: "/repo"})
+	if !strings.HasPrefix(out, "<!DOCTYPE html>") || !strings.HasSuffix(out, "</html>\n") {
+		t.Fatalf("expected a complete HTML page, got:\n%s", out)
+	}
+	if !strings.Contains(out, `<p class="score ok">0.0 / 100.0</p>`) {
+		t.Fatalf("expected an empty score, got:\n%s", out)
+	}
+	if strings.Contains(out, "<h2>Circular dependencies</h2>") || strings.Contains(out, "<h2>God objects</h2>") {
+		t.Fatalf("expected no violation tables, got:\n%s", out)
+	}
+	if strings.Contains(out, "<link") || strings.Contains(out, "<script") || strings.Contains(out, "http") {
+		t.Fatalf("expected a page without external assets, got:\n%s", out)
+	}
+}
+
+func TestFormatHTML_SmallReport(t *testing.T) {
+	report := &StructuralReport{
+		Version: "1.0.0",
+		Path:    "/repo",
+		Score:   &StructuralScore{TotalScore: 65, MaxScore: 100, ViolationCount: 2, LayerCount: 1, SizeCount: 1},
+		Layer:   []LayerViolation{{From: "/repo/store/s.go", To: "/repo/api/h.go", Message: "store imports <api>"}},
+		Size:    []SizeViolation{{File: "/repo/big.go", Function: "Run", Lines: 90, Threshold: 80}},
+	}
+
+	out := NewReporter(FormatHTML).Format(report)
+	for _, want := range []string{
+		`<p class="score warning">65.0 / 100.0</p>`,
+		"<tr><th>Layer violations</th><td>1</td></tr>",
+		"<tr><td>store/s.go</td><td>api/h.go</td><td>store imports &lt;api&gt;</td></tr>",
+		"<tr><td>big.go</td><td>Function &#39;Run&#39; has 90 lines (threshold: 80)</td></tr>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<h2>Circular dependencies</h2>") {
+		t.Fatalf("expected no cycle table, got:\n%s", out)
+	}
+}
+
+func TestFormatHTML_ViolationHeavyReport(t *testing.T) {
+	report := &StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 12.5, MaxScore: 100}}
+	for i := 0; i < 50; i++ {
+		report.Circular = append(report.Circular, CycleViolation{Path: []string{fmt.Sprintf("/repo/a%d.go", i), fmt.Sprintf("/repo/b%d.go", i)}})
+		report.GodObject = append(report.GodObject, GodObjectViolation{StructName: fmt.Sprintf("Hub%d", i), File: "/repo/hub.go", FieldCount: 30, MethodCount: 40})
+	}
+	report.Circular[49].BlankImportsFrom = []string{"/repo/a49.go"}
+
+	out := NewReporter(FormatHTML).Format(report)
+	if !strings.Contains(out, `<p class="score critical">12.5 / 100.0</p>`) {
+		t.Fatalf("expected critical styling, got:\n%s", out)
+	}
+	if got := strings.Count(out, "<tr><td>Hub"); got != 50 {
+		t.Fatalf("expected 50 god object rows, got %d", got)
+	}
+	if !strings.Contains(out, "<tr><td>50</td><td>a49.go → b49.go → a49.go<br><span class=\"note\">via blank import a49.go → b49.go") {
+		t.Fatalf("expected the last cycle with its blank import note, got:\n%s", out)
+	}
+}
+
+func TestFormatHTML_ScoreAndEscapedUTF8(t *testing.T) {
+	report := &StructuralReport{
+		Path:  "/repo",
+		Score: &StructuralScore{TotalScore: 91.5, MaxScore: 100, ViolationCount: 1, SizeCount: 1},
+		Size:  []SizeViolation{{File: "/repo/çekirdek/<script>.go", Lines: 900, Threshold: 500}},
+	}
+
+	out := NewReporter(FormatHTML).Format(report)
+	if !utf8.ValidString(out) {
+		t.Fatal("expected the page to be valid UTF-8")
+	}
+	if !strings.Contains(out, `<p class="score ok">91.5 / 100.0</p>`) {
+		t.Fatalf("expected the score badge, got:\n%s", out)
+	}
+	if strings.Contains(out, "<script>") || !strings.Contains(out, "çekirdek/&lt;script&gt;.go") {
+		t.Fatalf("expected the path to be escaped and kept in UTF-8, got:\n%s", out)
+	}
+	if !strings.Contains(out, "<details open>\n<summary><h2>Size violations</h2></summary>") {
+		t.Fatalf("expected a collapsible size section, got:\n%s", out)
+	}
+}
+
+func TestFormatHTML_TrendSparkline(t *testing.T) {
+	report := &StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 100, MaxScore: 100}}
+	if out := NewReporter(FormatHTML).Format(report); strings.Contains(out, "<svg") {
+		t.Fatalf("expected no sparkline without history, got:\n%s", out)
+	}
+
+	report.Metrics.Trend.Scores = []float64{50, 75, 100}
+	out := NewReporter(FormatHTML).Format(report)
+	for _, want := range []string{`points="0.0,20.0 100.0,10.0 200.0,0.0"`, "3 runs: 50.0 &rarr; 100.0"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTMLScoreTrend_ReadsHistoryOfTheScoreModel(t *testing.T) {
+	root := t.TempDir()
+	writeServiceFixture(t, root, map[string]string{
+		filepath.Join(".repodoctor", "history.json"): `[{"timestamp": "2026-01-01T00:00:00Z", "score": 70}, {"timestamp": "2026-01-02T00:00:00Z", "score": 4, "scoreModel": "category-rubric"}, {"timestamp": "2026-01-03T00:00:00Z", "score": 80, "scoreModel": "weighted"}]`,
+	})
+	report := &StructuralReport{Score: &StructuralScore{TotalScore: 90, MaxScore: 100}}
+
+	if got, want := htmlScoreTrend(root, report), []float64{70, 80, 90}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected trend %v, got %v", want, got)
+	}
+}
@@ -13,12 +13,17 @@ import (
 type ImportMetadata struct {
 	Package string
 	Imports []string
+	// BlankImports lists the Imports that are blank imports (import _)
+	BlankImports []string
 }
 
 // ImportExtractor extracts import metadata from Go source files
 type ImportExtractor struct {
 	modulePath    string
 	stdlibPrefixs map[string]bool
+	// Excludes are globs of files, relative to the extracted directory, that
+	// are skipped
+	Excludes []string
 }
 
 // NewImportExtractor creates a new ImportExtractor
@@ -51,8 +56,8 @@ func (e *ImportExtractor) ExtractFromDir(rootPath string) (map[string]*ImportMet
 			return nil
 		}
 
-		// Only process .go files
-		if !strings.HasSuffix(info.Name(), ".go") {
+		// Only process .go files that are not excluded
+		if !strings.HasSuffix(info.Name(), ".go") || excludedPath(rootPath, path, e.Excludes) {
 			return nil
 		}
 
@@ -116,13 +121,7 @@ func (e *ImportExtractor) extractImports(file *ast.File) []string {
 		}
 	}
 
-	// Convert map to slice
-	imports := make([]string, 0, len(importMap))
-	for imp := range importMap {
-		imports = append(imports, imp)
-	}
-
-	return imports
+	return sortedKeys(importMap)
 }
 
 // isStdlibImport checks if an import path is from the standard library
@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"RepoDoctor/internal/languages"
@@ -11,6 +12,8 @@ import (
 // Orchestrator coordinates language detection and adapter-driven analysis steps.
 type Orchestrator struct {
 	detector languages.LanguageDetector
+	// skip holds files the caller analyzes itself
+	skip map[string]bool
 }
 
 // Result contains adapter-driven analysis output.
@@ -19,6 +22,9 @@ type Result struct {
 	Files       []string
 	Metrics     *model.RepositoryMetrics
 	Graph       *model.DependencyGraph
+	// TestOverlay holds test-file import edges when the adapter builds them
+	// separately; nil otherwise
+	TestOverlay *model.DependencyGraph
 }
 
 // NewOrchestrator creates a new analysis orchestrator.
@@ -26,39 +32,34 @@ func NewOrchestrator(detector languages.LanguageDetector) *Orchestrator {
 	return &Orchestrator{detector: detector}
 }
 
-// Analyze executes the runtime pipeline: detect adapter -> detect files -> metrics -> graph.
-func (o *Orchestrator) Analyze(repoPath string) (*Result, error) {
-	if o.detector == nil {
-		return nil, fmt.Errorf("language detector is required")
+// SkipFiles leaves the given files out of metrics and graph building, for
+// callers that already extracted them
+func (o *Orchestrator) SkipFiles(files []string) {
+	o.skip = make(map[string]bool, len(files))
+	for _, file := range files {
+		o.skip[file] = true
 	}
+}
 
-	adapter, err := o.detector.DetectLanguage(repoPath)
+// Analyze executes the runtime pipeline: detect adapter -> detect files -> metrics -> graph.
+func (o *Orchestrator) Analyze(repoPath string) (*Result, error) {
+	adapter, files, err := o.detectAdapterFiles(repoPath)
 	if err != nil {
-		return nil, fmt.Errorf("language detection failed: %w", err)
+		return nil, err
 	}
 
-	capabilities := adapter.Capabilities()
-	if !capabilities.SupportsDependencyGraph {
-		return nil, fmt.Errorf("adapter %s does not support dependency graph capability", adapter.Name())
-	}
-	if !capabilities.SupportsMetrics {
+	if !adapter.Capabilities().SupportsMetrics {
 		return nil, fmt.Errorf("adapter %s does not support metrics capability", adapter.Name())
 	}
 
-	files, err := adapter.DetectFiles(repoPath)
-	if err != nil {
-		return nil, fmt.Errorf("file detection failed for %s: %w", adapter.Name(), err)
-	}
-	sort.Strings(files)
-
 	metrics, err := adapter.CollectMetrics(files)
 	if err != nil {
 		return nil, fmt.Errorf("metrics collection failed for %s: %w", adapter.Name(), err)
 	}
 
-	graph, err := adapter.BuildDependencyGraph(files)
+	graph, overlay, err := buildGraphs(adapter, files)
 	if err != nil {
-		return nil, fmt.Errorf("dependency graph build failed for %s: %w", adapter.Name(), err)
+		return nil, err
 	}
 
 	return &Result{
@@ -66,5 +67,76 @@ func (o *Orchestrator) Analyze(repoPath string) (*Result, error) {
 		Files:       files,
 		Metrics:     metrics,
 		Graph:       graph,
+		TestOverlay: overlay,
+	}, nil
+}
+
+// AnalyzeGraph executes only the graph half of the pipeline: detect adapter ->
+// detect files -> graph. Metrics collection is skipped entirely, so the
+// returned Result has a nil Metrics field.
+func (o *Orchestrator) AnalyzeGraph(repoPath string) (*Result, error) {
+	adapter, files, err := o.detectAdapterFiles(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, overlay, err := buildGraphs(adapter, files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		AdapterName: adapter.Name(),
+		Files:       files,
+		Graph:       graph,
+		TestOverlay: overlay,
 	}, nil
 }
+
+// buildGraphs builds the dependency graph and, for adapters that support it,
+// the separate test-file overlay
+func buildGraphs(adapter languages.LanguageAdapter, files []string) (*model.DependencyGraph, *model.DependencyGraph, error) {
+	graph, err := adapter.BuildDependencyGraph(files)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dependency graph build failed for %s: %w", adapter.Name(), err)
+	}
+
+	builder, ok := adapter.(languages.TestOverlayBuilder)
+	if !ok {
+		return graph, nil, nil
+	}
+	overlay, err := builder.BuildTestOverlay(files)
+	if err != nil {
+		return nil, nil, fmt.Errorf("test overlay build failed for %s: %w", adapter.Name(), err)
+	}
+	return graph, overlay, nil
+}
+
+// detectAdapterFiles selects the adapter for the repository and returns its
+// sorted source files, without skipped ones. Adapters without graph support
+// are rejected.
+func (o *Orchestrator) detectAdapterFiles(repoPath string) (languages.LanguageAdapter, []string, error) {
+	if o.detector == nil {
+		return nil, nil, fmt.Errorf("language detector is required")
+	}
+
+	adapter, err := o.detector.DetectLanguage(repoPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("language detection failed: %w", err)
+	}
+
+	if !adapter.Capabilities().SupportsDependencyGraph {
+		return nil, nil, fmt.Errorf("adapter %s does not support dependency graph capability", adapter.Name())
+	}
+
+	files, err := adapter.DetectFiles(repoPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("file detection failed for %s: %w", adapter.Name(), err)
+	}
+	if len(o.skip) > 0 {
+		files = slices.DeleteFunc(files, func(file string) bool { return o.skip[file] })
+	}
+	sort.Strings(files)
+
+	return adapter, files, nil
+}
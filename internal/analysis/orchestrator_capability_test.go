@@ -44,3 +44,53 @@ func TestOrchestrator_Analyze_RejectsMissingCapabilities(t *testing.T) {
 		t.Fatal("expected orchestrator to reject missing capabilities")
 	}
 }
+
+type countingAdapter struct {
+	fakeAdapter
+	metricsCalls *int
+}
+
+func (a countingAdapter) DetectFiles(repoPath string) ([]string, error) {
+	return []string{"b.fake", "a.fake"}, nil
+}
+
+func (a countingAdapter) CollectMetrics(files []string) (*model.RepositoryMetrics, error) {
+	*a.metricsCalls++
+	return model.NewRepositoryMetrics(), nil
+}
+
+func (a countingAdapter) BuildDependencyGraph(files []string) (*model.DependencyGraph, error) {
+	graph := model.NewDependencyGraph()
+	graph.AddEdge(files[0], files[1])
+	return graph, nil
+}
+
+func TestOrchestrator_AnalyzeGraph_SkipsMetricsCollection(t *testing.T) {
+	calls := 0
+	adapter := countingAdapter{
+		fakeAdapter:  fakeAdapter{caps: languages.AdapterCapabilities{SupportsDependencyGraph: true, SupportsMetrics: true}},
+		metricsCalls: &calls,
+	}
+	orchestrator := NewOrchestrator(fakeDetector{adapter: adapter})
+
+	result, err := orchestrator.AnalyzeGraph(t.TempDir())
+	if err != nil {
+		t.Fatalf("AnalyzeGraph returned error: %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected metrics collection to be skipped, got %d calls", calls)
+	}
+	if result.Metrics != nil {
+		t.Fatal("expected nil metrics in graph-only result")
+	}
+	if result.Graph.EdgeCount() != 1 || result.Files[0] != "a.fake" {
+		t.Fatalf("expected sorted files and built graph, got files=%v edges=%d", result.Files, result.Graph.EdgeCount())
+	}
+
+	if _, err := orchestrator.Analyze(t.TempDir()); err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected full analysis to collect metrics once, got %d calls", calls)
+	}
+}
@@ -42,3 +42,31 @@ func TestOrchestrator_Analyze_SelectsAdapterAndBuildsPipeline(t *testing.T) {
 		t.Fatal("expected dependency graph result")
 	}
 }
+
+func TestOrchestrator_SkipFilesLeavesThemToTheCaller(t *testing.T) {
+	repo := t.TempDir()
+	for name, content := range map[string]string{
+		"main.go":     "package main\nimport \"example.com/app/store\"\nfunc main(){store.Open()}\n",
+		"store.go":    "package main\nimport \"fmt\"\nfunc open(){fmt.Println()}\n",
+		"provided.go": "package main\nimport \"example.com/app/provided\"\n",
+	} {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	strategy := domain.NewDefaultIgnoreStrategy(domain.DefaultIgnoredDirs)
+	detector := languages.NewRepositoryLanguageDetector(strategy)
+	detector.RegisterAdapter(languages.NewGoAdapter())
+
+	orchestrator := NewOrchestrator(detector)
+	orchestrator.SkipFiles([]string{filepath.Join(repo, "provided.go")})
+	result, err := orchestrator.Analyze(repo)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	if len(result.Files) != 2 || result.Graph.GetNode(filepath.Join(repo, "provided.go")) != nil || result.Graph.GetNode("example.com/app/provided") != nil {
+		t.Fatalf("expected provided.go to be neither listed nor parsed, got files %v", result.Files)
+	}
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"RepoDoctor/internal/model"
 	"RepoDoctor/internal/rules"
+	stdcontext "context"
 	"sort"
 	"time"
 )
@@ -11,6 +12,8 @@ import (
 // It guarantees deterministic order and rule isolation during execution.
 type RuleExecutor struct {
 	registry *rules.RuleRegistry
+	// timeouts bound the run time of individual rules, by rule ID
+	timeouts map[string]time.Duration
 }
 
 // NewRuleExecutor creates a new rule executor with the given registry
@@ -27,6 +30,21 @@ type ExecutionResult struct {
 	// RulesExecuted is the number of rules that were executed
 	RulesExecuted int
 	TimedOut      bool
+	// Coverage holds examined/skipped file counts for coverage-aware rules,
+	// in execution order
+	Coverage []rules.RuleCoverage
+	// Durations is the wall time each executed rule took, by rule ID
+	Durations map[string]time.Duration
+	// TimedOutRules lists, in execution order, the rules stopped by their
+	// timeout; they contribute no violations
+	TimedOutRules []string
+}
+
+// SetRuleTimeouts bounds the run time of the rules in timeouts, keyed by
+// rule ID. A rule still running at its deadline is abandoned and reported
+// in ExecutionResult.TimedOutRules; rules without a timeout run to the end.
+func (e *RuleExecutor) SetRuleTimeouts(timeouts map[string]time.Duration) {
+	e.timeouts = timeouts
 }
 
 const defaultExecutionBudget = 2 * time.Second
@@ -37,20 +55,35 @@ const defaultExecutionBudget = 2 * time.Second
 func (e *RuleExecutor) Execute(context rules.AnalysisContext) *ExecutionResult {
 	allRules := e.selectEligibleRules(context)
 	allViolations := make([]model.Violation, 0)
+	var coverage []rules.RuleCoverage
+	durations := make(map[string]time.Duration, len(allRules))
+	var timedOut []string
 	start := time.Now()
 
 	for _, rule := range allRules {
 		if time.Since(start) > defaultExecutionBudget {
-			return &ExecutionResult{Violations: allViolations, RulesExecuted: len(allRules), TimedOut: true}
+			return &ExecutionResult{Violations: allViolations, RulesExecuted: len(allRules), TimedOut: true, Coverage: coverage, Durations: durations, TimedOutRules: timedOut}
+		}
+		ruleStart := time.Now()
+		violations, expired := e.executeRuleWithin(rule, context, e.timeouts[rule.ID()])
+		durations[rule.ID()] = time.Since(ruleStart)
+		if expired {
+			timedOut = append(timedOut, rule.ID())
+			continue
 		}
-		violations := e.executeRule(rule, context)
 		allViolations = append(allViolations, violations...)
+		if aware, ok := rule.(rules.CoverageAwareRule); ok {
+			coverage = append(coverage, e.ruleCoverage(aware, context))
+		}
 	}
 
 	return &ExecutionResult{
 		Violations:    allViolations,
 		RulesExecuted: len(allRules),
 		TimedOut:      false,
+		Coverage:      coverage,
+		Durations:     durations,
+		TimedOutRules: timedOut,
 	}
 }
 
@@ -138,3 +171,41 @@ func (e *RuleExecutor) executeRule(rule rules.Rule, context rules.AnalysisContex
 	// Execute the rule
 	return rule.Evaluate(context)
 }
+
+// executeRuleWithin executes a rule under a context deadline of timeout; 0
+// means no deadline. The rule runs on its own goroutine and hands back its
+// violations over a channel, so a rule that misses the deadline is left to
+// finish in the background and its late violations are dropped without
+// touching the results of other rules.
+func (e *RuleExecutor) executeRuleWithin(rule rules.Rule, context rules.AnalysisContext, timeout time.Duration) ([]model.Violation, bool) {
+	if timeout <= 0 {
+		return e.executeRule(rule, context), false
+	}
+
+	deadline, cancel := stdcontext.WithTimeout(stdcontext.Background(), timeout)
+	defer cancel()
+	done := make(chan []model.Violation, 1)
+	go func() {
+		done <- e.executeRule(rule, context)
+	}()
+
+	select {
+	case violations := <-done:
+		return violations, false
+	case <-deadline.Done():
+		return nil, true
+	}
+}
+
+// ruleCoverage collects a rule's file coverage, recovering from panics the
+// same way executeRule does. A panicking rule reports no examined files.
+func (e *RuleExecutor) ruleCoverage(rule rules.CoverageAwareRule, context rules.AnalysisContext) (coverage rules.RuleCoverage) {
+	coverage = rules.RuleCoverage{RuleID: rule.ID()}
+	defer func() {
+		if r := recover(); r != nil {
+			coverage = rules.RuleCoverage{RuleID: rule.ID()}
+		}
+	}()
+
+	return rule.Coverage(context)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"RepoDoctor/internal/model"
 	"RepoDoctor/internal/rules"
@@ -101,7 +102,7 @@ func (r *executorCategorizedRule) Category() string { return r.category }
 
 func (r *stubRule) ID() string                           { return r.id }
 func (r *stubRule) Category() string                     { return "testing" }
-func (r *stubRule) Severity() string                     { return "info" }
+func (r *stubRule) Severity() model.Severity             { return model.SeverityInfo }
 func (r *stubRule) Capabilities() rules.RuleCapabilities { return r.caps }
 func (r *stubRule) Evaluate(context rules.AnalysisContext) []model.Violation {
 	*r.hits = *r.hits + 1
@@ -132,3 +133,75 @@ func TestRuleExecutor_SelectEligibleRules_ByLanguageContext(t *testing.T) {
 		t.Fatalf("expected shared rule to execute once, got %d", sharedHits)
 	}
 }
+
+type coverageStubRule struct {
+	stubRule
+	coverage rules.RuleCoverage
+}
+
+func (r *coverageStubRule) Coverage(context rules.AnalysisContext) rules.RuleCoverage {
+	return r.coverage
+}
+
+func TestRuleExecutor_Execute_CollectsCoverageFromAwareRules(t *testing.T) {
+	registry := rules.NewRuleRegistry()
+	hits := 0
+
+	registry.MustRegister(&stubRule{id: "rule.plain", hits: &hits})
+	registry.MustRegister(&coverageStubRule{
+		stubRule: stubRule{id: "rule.aware", hits: &hits},
+		coverage: rules.RuleCoverage{RuleID: "rule.aware", Examined: 3, Skipped: 1},
+	})
+
+	result := NewRuleExecutor(registry).Execute(rules.AnalysisContext{})
+
+	if len(result.Coverage) != 1 {
+		t.Fatalf("expected coverage from the aware rule only, got %+v", result.Coverage)
+	}
+	if got := result.Coverage[0]; got.RuleID != "rule.aware" || got.Examined != 3 || got.Skipped != 1 {
+		t.Fatalf("unexpected coverage: %+v", got)
+	}
+}
+
+// slowStubRule reports one violation after sleeping for delay
+type slowStubRule struct {
+	stubRule
+	delay time.Duration
+}
+
+func (r *slowStubRule) Evaluate(context rules.AnalysisContext) []model.Violation {
+	time.Sleep(r.delay)
+	return []model.Violation{{RuleID: r.id, File: r.id + ".go"}}
+}
+
+func TestRuleExecutor_Execute_StopsRulesAtTheirTimeout(t *testing.T) {
+	hits := 0
+	registry := rules.NewRuleRegistry()
+	registry.MustRegister(&slowStubRule{stubRule: stubRule{id: "rule.fast", hits: &hits}})
+	registry.MustRegister(&slowStubRule{stubRule: stubRule{id: "rule.slow", hits: &hits}, delay: 300 * time.Millisecond})
+
+	executor := NewRuleExecutor(registry)
+	executor.SetRuleTimeouts(map[string]time.Duration{"rule.slow": 20 * time.Millisecond, "rule.fast": time.Second})
+	result := executor.Execute(rules.AnalysisContext{})
+
+	if len(result.TimedOutRules) != 1 || result.TimedOutRules[0] != "rule.slow" {
+		t.Fatalf("expected only rule.slow to time out, got %v", result.TimedOutRules)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].RuleID != "rule.fast" {
+		t.Fatalf("expected the fast rule's violation only, got %+v", result.Violations)
+	}
+	if result.RulesExecuted != 2 || result.Durations["rule.slow"] >= 300*time.Millisecond {
+		t.Fatalf("expected the slow rule to be abandoned at its deadline, got %+v", result)
+	}
+
+	// The abandoned rule finishing later must not change the result
+	time.Sleep(350 * time.Millisecond)
+	if len(result.Violations) != 1 {
+		t.Fatalf("expected the late violation to be dropped, got %+v", result.Violations)
+	}
+
+	executor.SetRuleTimeouts(nil)
+	if full := executor.Execute(rules.AnalysisContext{}); len(full.Violations) != 2 || len(full.TimedOutRules) != 0 {
+		t.Fatalf("expected every rule to finish without timeouts, got %+v", full)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"RepoDoctor/internal/model"
@@ -13,13 +14,21 @@ import (
 
 // GoAdapter implements LanguageAdapter for Go programming language
 type GoAdapter struct {
-	fset *token.FileSet
+	fset      *token.FileSet
+	testEdges model.TestEdgeMode
 }
 
-// NewGoAdapter creates a new Go language adapter
+// NewGoAdapter creates a new Go language adapter that ignores test files
 func NewGoAdapter() *GoAdapter {
+	return NewGoAdapterWithTestEdges(model.TestEdgesExclude)
+}
+
+// NewGoAdapterWithTestEdges creates a Go adapter whose dependency graph
+// treats the imports of _test.go files according to mode
+func NewGoAdapterWithTestEdges(mode model.TestEdgeMode) *GoAdapter {
 	return &GoAdapter{
-		fset: token.NewFileSet(),
+		fset:      token.NewFileSet(),
+		testEdges: mode,
 	}
 }
 
@@ -180,9 +189,61 @@ func (a *GoAdapter) BuildDependencyGraph(files []string) (*model.DependencyGraph
 		}
 	}
 
+	if a.testEdges == model.TestEdgesInclude {
+		goAddTestEdges(a.fset, files, graph)
+	}
+
 	return graph, nil
 }
 
+// BuildTestOverlay returns a graph of the test-file imports next to the given
+// source files when the adapter keeps test edges separate, and nil otherwise
+func (a *GoAdapter) BuildTestOverlay(files []string) (*model.DependencyGraph, error) {
+	if a.testEdges != model.TestEdgesSeparate {
+		return nil, nil
+	}
+
+	overlay := model.NewDependencyGraph()
+	goAddTestEdges(a.fset, files, overlay)
+	return overlay, nil
+}
+
+// goAddTestEdges adds the imports of the test files that sit next to the given
+// source files, marking their nodes as test provenance.
+// Package-level helper to keep GoAdapter method count within SRP bounds.
+func goAddTestEdges(fset *token.FileSet, files []string, graph *model.DependencyGraph) {
+	for _, file := range goSiblingTestFiles(files) {
+		node, err := goParseFileAndAddToGraph(fset, file, graph)
+		if err != nil || node == nil {
+			continue
+		}
+		node.Metadata[model.ProvenanceMetadataKey] = string(model.EdgeFromTest)
+		for _, imp := range node.Imports {
+			graph.AddEdge(node.ID, imp)
+		}
+	}
+}
+
+// goSiblingTestFiles returns the sorted _test.go files in the directories of
+// the given source files. DetectFiles skips test files, so they are found here.
+func goSiblingTestFiles(files []string) []string {
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		dirs[filepath.Dir(file)] = true
+	}
+
+	var testFiles []string
+	for dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+		if err != nil {
+			continue
+		}
+		testFiles = append(testFiles, matches...)
+	}
+	sort.Strings(testFiles)
+	return testFiles
+}
+
 // goParseFileAndAddToGraph parses a Go file and adds it to the dependency graph.
 // Package-level helper to keep GoAdapter method count within SRP bounds.
 func goParseFileAndAddToGraph(fset *token.FileSet, path string, graph *model.DependencyGraph) (*model.Node, error) {
@@ -201,10 +262,18 @@ func goParseFileAndAddToGraph(fset *token.FileSet, path string, graph *model.Dep
 
 	graphNode := graph.AddNode(nodeID, path, pkgName)
 
-	// Extract imports
+	// Extract imports; blank imports are edges like any other, recorded so
+	// violations can explain them
+	var blank []string
 	for _, imp := range node.Imports {
 		importPath := strings.Trim(imp.Path.Value, "\"")
 		graphNode.Imports = append(graphNode.Imports, importPath)
+		if imp.Name != nil && imp.Name.Name == "_" {
+			blank = append(blank, importPath)
+		}
+	}
+	if len(blank) > 0 {
+		graphNode.Metadata[model.BlankImportsMetadataKey] = strings.Join(blank, "\n")
 	}
 
 	return graphNode, nil
@@ -0,0 +1,142 @@
+package languages
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func writeTestEdgeFixture(t *testing.T) (string, []string) {
+	t.Helper()
+	repo := t.TempDir()
+	files := map[string]string{
+		"foo/foo.go":      "package foo\n\nimport \"example.com/app/bar\"\n",
+		"foo/foo_test.go": "package foo_test\n\nimport (\n\t\"example.com/app/foo\"\n\t\"example.com/app/testkit\"\n)\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(repo, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed creating dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed writing %s: %v", rel, err)
+		}
+	}
+
+	sources, err := NewGoAdapter().DetectFiles(repo)
+	if err != nil {
+		t.Fatalf("DetectFiles failed: %v", err)
+	}
+	return repo, sources
+}
+
+// edgeSet lists every edge as "from -> to [provenance]" with paths relative to repo
+func edgeSet(repo string, graph *model.DependencyGraph) []string {
+	edges := make([]string, 0)
+	for _, node := range graph.GetNodes() {
+		from, err := filepath.Rel(repo, node.ID)
+		if err != nil || !filepath.IsAbs(node.ID) {
+			from = node.ID
+		}
+		for _, dep := range graph.GetDependencies(node.ID) {
+			edges = append(edges, filepath.ToSlash(from)+" -> "+dep+" ["+string(model.NodeProvenance(node))+"]")
+		}
+	}
+	sort.Strings(edges)
+	return edges
+}
+
+func TestGoAdapter_TestEdgesExcludeIgnoresTestImports(t *testing.T) {
+	repo, sources := writeTestEdgeFixture(t)
+
+	graph, err := NewGoAdapterWithTestEdges(model.TestEdgesExclude).BuildDependencyGraph(sources)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+
+	want := []string{"foo/foo.go -> example.com/app/bar [source]"}
+	if got := edgeSet(repo, graph); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected edges %v, got %v", want, got)
+	}
+	if overlay, err := NewGoAdapterWithTestEdges(model.TestEdgesExclude).BuildTestOverlay(sources); err != nil || overlay != nil {
+		t.Fatalf("expected no test overlay in exclude mode, got %v (%v)", overlay, err)
+	}
+}
+
+func TestGoAdapter_TestEdgesIncludeAddsTestProvenanceEdges(t *testing.T) {
+	repo, sources := writeTestEdgeFixture(t)
+
+	graph, err := NewGoAdapterWithTestEdges(model.TestEdgesInclude).BuildDependencyGraph(sources)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+
+	want := []string{
+		"foo/foo.go -> example.com/app/bar [source]",
+		"foo/foo_test.go -> example.com/app/foo [test]",
+		"foo/foo_test.go -> example.com/app/testkit [test]",
+	}
+	if got := edgeSet(repo, graph); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected edges %v, got %v", want, got)
+	}
+}
+
+func TestGoAdapter_TestEdgesSeparateBuildsOverlay(t *testing.T) {
+	repo, sources := writeTestEdgeFixture(t)
+
+	adapter := NewGoAdapterWithTestEdges(model.TestEdgesSeparate)
+	graph, err := adapter.BuildDependencyGraph(sources)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+
+	wantMain := []string{"foo/foo.go -> example.com/app/bar [source]"}
+	if got := edgeSet(repo, graph); !reflect.DeepEqual(got, wantMain) {
+		t.Fatalf("expected main graph edges %v, got %v", wantMain, got)
+	}
+
+	overlay, err := adapter.BuildTestOverlay(sources)
+	if err != nil || overlay == nil {
+		t.Fatalf("expected a test overlay in separate mode, got %v", err)
+	}
+	wantOverlay := []string{
+		"foo/foo_test.go -> example.com/app/foo [test]",
+		"foo/foo_test.go -> example.com/app/testkit [test]",
+	}
+	if got := edgeSet(repo, overlay); !reflect.DeepEqual(got, wantOverlay) {
+		t.Fatalf("expected overlay edges %v, got %v", wantOverlay, got)
+	}
+}
+
+func TestParseTestEdgeMode(t *testing.T) {
+	if mode, err := model.ParseTestEdgeMode(""); err != nil || mode != model.TestEdgesExclude {
+		t.Fatalf("expected empty mode to default to exclude, got %q (%v)", mode, err)
+	}
+	if _, err := model.ParseTestEdgeMode("overlay"); err == nil {
+		t.Fatal("expected error for unknown test edge mode")
+	}
+}
+
+func TestGoAdapter_RecordsBlankImports(t *testing.T) {
+	repo := t.TempDir()
+	source := "package app\n\nimport (\n\t\"example.com/app/store\"\n\t_ \"example.com/app/migrations\"\n)\n"
+	if err := os.WriteFile(filepath.Join(repo, "app.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("failed writing app.go: %v", err)
+	}
+
+	graph, err := NewGoAdapter().BuildDependencyGraph([]string{filepath.Join(repo, "app.go")})
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+	want := []string{"app.go -> example.com/app/migrations [source]", "app.go -> example.com/app/store [source]"}
+	if got := edgeSet(repo, graph); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected blank imports to stay graph edges, got %v", got)
+	}
+	if got := model.NodeBlankImports(graph.GetNode(filepath.Join(repo, "app.go"))); !reflect.DeepEqual(got, []string{"example.com/app/migrations"}) {
+		t.Fatalf("expected the blank import to be recorded, got %v", got)
+	}
+}
@@ -43,6 +43,13 @@ type LanguageAdapter interface {
 	NormalizeImport(importPath string) string
 }
 
+// TestOverlayBuilder is an optional adapter extension that builds the imports
+// of test files into a graph kept apart from the main dependency graph.
+// Implementations return a nil graph when no overlay is configured.
+type TestOverlayBuilder interface {
+	BuildTestOverlay(files []string) (*model.DependencyGraph, error)
+}
+
 // EvidenceSignal is a language-neutral detection signal produced by adapters.
 // Domain scoring must depend on this structure instead of parser internals.
 type EvidenceSignal struct {
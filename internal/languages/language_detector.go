@@ -2,6 +2,7 @@ package languages
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -12,6 +13,10 @@ import (
 	"RepoDoctor/internal/domain"
 )
 
+// ErrNoSupportedLanguage is returned by DetectLanguage when the repository
+// holds no file any registered adapter supports
+var ErrNoSupportedLanguage = errors.New("no supported language files found in repository")
+
 // RepositoryLanguageDetector detects the primary language of a repository
 // based on deterministic extension, layout and adapter evidence.
 type RepositoryLanguageDetector struct {
@@ -386,7 +391,7 @@ func normalizePathWithinRoot(root, candidate string) (string, bool) {
 // findDominantLanguage returns the adapter for the strongest language.
 func (d *RepositoryLanguageDetector) findDominantLanguage(stats map[string]*LanguageStat) (LanguageAdapter, error) {
 	if len(stats) == 0 {
-		return nil, fmt.Errorf("no supported language files found in repository")
+		return nil, ErrNoSupportedLanguage
 	}
 
 	candidates := make([]LanguageStat, 0, len(stats))
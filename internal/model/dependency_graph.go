@@ -1,6 +1,10 @@
 package model
 
-import "sync"
+import (
+	"slices"
+	"strings"
+	"sync"
+)
 
 // DependencyGraph represents a language-agnostic dependency graph.
 // Nodes represent files or modules, edges represent import/dependency relationships.
@@ -98,7 +102,7 @@ func (g *DependencyGraph) GetNode(id string) *Node {
 	return g.nodes[id]
 }
 
-// GetNodes returns all nodes in the graph
+// GetNodes returns all nodes in the graph, sorted by ID
 func (g *DependencyGraph) GetNodes() []*Node {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
@@ -107,14 +111,19 @@ func (g *DependencyGraph) GetNodes() []*Node {
 	for _, node := range g.nodes {
 		nodes = append(nodes, node)
 	}
+	slices.SortFunc(nodes, func(a, b *Node) int { return strings.Compare(a.ID, b.ID) })
 	return nodes
 }
 
-// GetDependencies returns the dependencies of a node
+// GetDependencies returns the dependencies of a node, sorted. The result is
+// a copy, so the order edges were added in does not show.
 func (g *DependencyGraph) GetDependencies(id string) []string {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	return g.edges[id]
+	if g.edges[id] == nil {
+		return nil
+	}
+	return slices.Sorted(slices.Values(g.edges[id]))
 }
 
 // GetDependents returns nodes that depend on the given node
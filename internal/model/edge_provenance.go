@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EdgeProvenance identifies the kind of file an import edge was created from
+type EdgeProvenance string
+
+const (
+	EdgeFromSource EdgeProvenance = "source"
+	EdgeFromTest   EdgeProvenance = "test"
+)
+
+// ProvenanceMetadataKey is the Node.Metadata key recording which kind of file
+// a node was parsed from. Nodes without it are production source.
+const ProvenanceMetadataKey = "provenance"
+
+// NodeProvenance returns the provenance of a node's outgoing edges. Every
+// edge is created from an import declared by its source node, so the edge
+// inherits that node's provenance.
+func NodeProvenance(node *Node) EdgeProvenance {
+	if node == nil || node.Metadata[ProvenanceMetadataKey] == "" {
+		return EdgeFromSource
+	}
+	return EdgeProvenance(node.Metadata[ProvenanceMetadataKey])
+}
+
+// BlankImportsMetadataKey is the Node.Metadata key listing, one per line,
+// the imports a node declares only for their side effects (import _ "pkg")
+const BlankImportsMetadataKey = "blankImports"
+
+// NodeBlankImports returns the imports a node declares only for their side
+// effects. Their edges are ordinary edges; the list only explains them.
+func NodeBlankImports(node *Node) []string {
+	if node == nil || node.Metadata[BlankImportsMetadataKey] == "" {
+		return nil
+	}
+	return strings.Split(node.Metadata[BlankImportsMetadataKey], "\n")
+}
+
+// TestEdgeMode controls how imports of test files enter the dependency graph
+type TestEdgeMode string
+
+const (
+	// TestEdgesInclude adds test-file imports to the main graph
+	TestEdgesInclude TestEdgeMode = "include"
+	// TestEdgesExclude ignores test files entirely
+	TestEdgesExclude TestEdgeMode = "exclude"
+	// TestEdgesSeparate builds test-file imports into an overlay graph
+	TestEdgesSeparate TestEdgeMode = "separate"
+)
+
+// ParseTestEdgeMode validates a test edge mode; empty selects TestEdgesExclude
+func ParseTestEdgeMode(value string) (TestEdgeMode, error) {
+	switch mode := TestEdgeMode(value); mode {
+	case "":
+		return TestEdgesExclude, nil
+	case TestEdgesInclude, TestEdgesExclude, TestEdgesSeparate:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid test edge mode '%s' (expected include, exclude or separate)", value)
+	}
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"slices"
+	"strings"
+)
+
 // GraphCycleDetector performs cycle detection on a DependencyGraph.
 // Extracted from DependencyGraph to satisfy SRP — the graph stores
 // structure, the detector runs analysis algorithms over it.
@@ -17,6 +22,7 @@ func NewGraphCycleDetector(graph *DependencyGraph) *GraphCycleDetector {
 }
 
 // DetectCycles performs DFS-based cycle detection and returns all cycles found.
+// Every returned cycle is a closed walk; see IsClosedWalk.
 func (d *GraphCycleDetector) DetectCycles() [][]string {
 	d.cycles = make([][]string, 0)
 
@@ -63,6 +69,9 @@ func (d *GraphCycleDetector) DetectCycles() [][]string {
 		}
 	}
 
+	d.cycles = WellFormedCycles(d.cycles, func(from, to string) bool {
+		return slices.Contains(d.graph.GetDependencies(from), to)
+	})
 	return d.cycles
 }
 
@@ -78,3 +87,54 @@ func (d *GraphCycleDetector) HasCycles() bool {
 	}
 	return len(d.cycles) > 0
 }
+
+// IsClosedWalk reports whether cycle is a well-formed cycle: every node
+// depends on the next one and the last node depends on the first. A single
+// node is a self-import and needs an edge to itself.
+func IsClosedWalk(cycle []string, hasEdge func(from, to string) bool) bool {
+	if len(cycle) == 0 {
+		return false
+	}
+	for i, node := range cycle {
+		if !hasEdge(node, cycle[(i+1)%len(cycle)]) {
+			return false
+		}
+	}
+	return true
+}
+
+// WellFormedCycles returns the distinct cycles that are closed walks,
+// dropping any malformed extraction. Each cycle is rotated by
+// CanonicalCycle, so rotations of the same cycle are reported once.
+func WellFormedCycles(cycles [][]string, hasEdge func(from, to string) bool) [][]string {
+	valid := make([][]string, 0, len(cycles))
+	seen := make(map[string]bool, len(cycles))
+	for _, cycle := range cycles {
+		if !IsClosedWalk(cycle, hasEdge) {
+			continue
+		}
+		cycle = CanonicalCycle(cycle)
+		key := strings.Join(cycle, "\x00")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		valid = append(valid, cycle)
+	}
+	return valid
+}
+
+// CanonicalCycle returns a copy of cycle rotated to start at its
+// lexicographically smallest node, keeping the direction of the walk
+func CanonicalCycle(cycle []string) []string {
+	if len(cycle) == 0 {
+		return cycle
+	}
+	start := 0
+	for i, node := range cycle {
+		if node < cycle[start] {
+			start = i
+		}
+	}
+	return append(append([]string(nil), cycle[start:]...), cycle[:start]...)
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsClosedWalk(t *testing.T) {
+	edges := map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a", "b"}, "d": {"d"}}
+	hasEdge := func(from, to string) bool {
+		for _, dep := range edges[from] {
+			if dep == to {
+				return true
+			}
+		}
+		return false
+	}
+
+	cases := []struct {
+		cycle []string
+		want  bool
+	}{
+		{[]string{"a", "b", "c"}, true},
+		{[]string{"b", "c", "a"}, true},
+		{[]string{"b", "c"}, true},
+		{[]string{"a", "b"}, false}, // b does not depend on a
+		{[]string{"a", "c", "b"}, false},
+		{[]string{"d"}, true},
+		{[]string{"a"}, false},
+		{nil, false},
+	}
+	for _, tc := range cases {
+		if got := IsClosedWalk(tc.cycle, hasEdge); got != tc.want {
+			t.Errorf("IsClosedWalk(%v) = %v, want %v", tc.cycle, got, tc.want)
+		}
+	}
+}
+
+func TestWellFormedCycles_DropsRotations(t *testing.T) {
+	hasEdge := func(from, to string) bool {
+		return (from == "a" && to == "b") || (from == "b" && to == "c") || (from == "c" && to == "a")
+	}
+	cycles := WellFormedCycles([][]string{{"b", "c", "a"}, {"a", "b", "c"}, {"c", "a", "b"}}, hasEdge)
+	if len(cycles) != 1 || !slices.Equal(cycles[0], []string{"a", "b", "c"}) {
+		t.Fatalf("expected one cycle starting at its smallest node, got %v", cycles)
+	}
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity level of a rule violation. Levels are ordered
+// from info to critical, so severities compare with < and >=. The zero
+// value means no severity was set.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota + 1
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = [...]string{
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// Severities lists every valid severity from lowest to highest
+func Severities() []Severity {
+	return []Severity{SeverityInfo, SeverityWarning, SeverityError, SeverityCritical}
+}
+
+// ParseSeverity parses a severity name. Names are case-insensitive; an
+// unknown or empty name is an error.
+func ParseSeverity(name string) (Severity, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	for _, severity := range Severities() {
+		if severityNames[severity] == normalized {
+			return severity, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid severity '%s' (must be: info, warning, error, critical)", name)
+}
+
+// Valid reports whether s is one of the defined severities
+func (s Severity) Valid() bool {
+	return s >= SeverityInfo && s <= SeverityCritical
+}
+
+// String returns the lowercase severity name, or "" when s is not valid
+func (s Severity) String() string {
+	if !s.Valid() {
+		return ""
+	}
+	return severityNames[s]
+}
+
+// MarshalJSON encodes the severity as its lowercase name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a severity name. An empty string leaves the
+// severity unset.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	return s.UnmarshalText([]byte(name))
+}
+
+// MarshalText encodes the severity as its lowercase name, for YAML
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name, for YAML. An empty string leaves
+// the severity unset.
+func (s *Severity) UnmarshalText(text []byte) error {
+	if strings.TrimSpace(string(text)) == "" {
+		*s = 0
+		return nil
+	}
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSeverity_Ordering(t *testing.T) {
+	severities := Severities()
+	for i := 1; i < len(severities); i++ {
+		if severities[i-1] >= severities[i] {
+			t.Fatalf("expected %s < %s", severities[i-1], severities[i])
+		}
+	}
+	if !(SeverityCritical > SeverityError && SeverityWarning >= SeverityInfo) {
+		t.Fatal("expected critical > error and warning >= info")
+	}
+	if Severity(0).Valid() || Severity(5).Valid() {
+		t.Fatal("expected unset and out-of-range severities to be invalid")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	cases := map[string]Severity{
+		"info":       SeverityInfo,
+		"warning":    SeverityWarning,
+		"error":      SeverityError,
+		"critical":   SeverityCritical,
+		" Critical ": SeverityCritical,
+	}
+	for name, want := range cases {
+		got, err := ParseSeverity(name)
+		if err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"", "high", "fatal"} {
+		if _, err := ParseSeverity(name); err == nil {
+			t.Errorf("expected ParseSeverity(%q) to fail", name)
+		}
+	}
+	for _, severity := range Severities() {
+		if parsed, err := ParseSeverity(severity.String()); err != nil || parsed != severity {
+			t.Errorf("expected %s to parse back, got %v, %v", severity, parsed, err)
+		}
+	}
+}
+
+func TestSeverity_JSONRoundTrip(t *testing.T) {
+	type doc struct {
+		Severity Severity `json:"severity"`
+	}
+	for _, severity := range Severities() {
+		data, err := json.Marshal(doc{Severity: severity})
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		if want := `{"severity":"` + severity.String() + `"}`; string(data) != want {
+			t.Fatalf("expected %s, got %s", want, data)
+		}
+		var decoded doc
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded.Severity != severity {
+			t.Fatalf("expected %s to round-trip, got %v, %v", severity, decoded.Severity, err)
+		}
+	}
+
+	var decoded doc
+	if err := json.Unmarshal([]byte(`{"severity":"high"}`), &decoded); err == nil {
+		t.Fatal("expected an unknown severity to be rejected")
+	}
+	if err := json.Unmarshal([]byte(`{"severity":""}`), &decoded); err != nil || decoded.Severity != 0 {
+		t.Fatalf("expected an empty severity to stay unset, got %v, %v", decoded.Severity, err)
+	}
+}
@@ -1,15 +1,5 @@
 package model
 
-// Severity represents the severity level of a rule violation
-type Severity string
-
-const (
-	SeverityInfo     Severity = "info"
-	SeverityWarning  Severity = "warning"
-	SeverityError    Severity = "error"
-	SeverityCritical Severity = "critical"
-)
-
 // Violation represents a standardized rule violation
 type Violation struct {
 	// RuleID is the unique identifier of the rule that detected the violation
@@ -22,6 +12,8 @@ type Violation struct {
 	File string
 	// Line is the line number where the violation occurred (0 if not applicable)
 	Line int
+	// EndLine is the last line of the offending declaration (0 if not applicable)
+	EndLine int `json:",omitempty"`
 	// ScoreImpact is the impact on the structural health score
 	ScoreImpact float64
 }
@@ -0,0 +1,16 @@
+package rules
+
+import "strings"
+
+// BlankImportNote explains violations that involve blank imports (import _
+// "pkg"). Such an import references no symbol, so the dependency is easy to
+// miss in review: the importer only relies on the package's init-time side
+// effects. edges are "from → to" descriptions of the blank imports.
+func BlankImportNote(edges ...string) string {
+	noun := "blank import"
+	if len(edges) > 1 {
+		noun = "blank imports"
+	}
+	return "via " + noun + " " + strings.Join(edges, ", ") +
+		": the dependency exists only for init-time side effects; move the import to the entrypoint or replace it with an explicit registration call"
+}
@@ -1,12 +1,18 @@
 package rules
 
 import (
+	"path"
+	"slices"
+	"strings"
+
 	"RepoDoctor/internal/model"
 )
 
 // CircularDependencyRule detects circular dependencies in a graph
 type CircularDependencyRule struct {
 	graph DependencyGraph
+	// minLength is the smallest number of nodes a reported cycle spans
+	minLength int
 }
 
 // NewCircularDependencyRule creates a new circular dependency rule checker
@@ -16,6 +22,15 @@ func NewCircularDependencyRule(graph DependencyGraph) *CircularDependencyRule {
 	}
 }
 
+// NewCircularDependencyRuleWithMinLength creates a circular dependency rule
+// that ignores cycles spanning fewer than minLength nodes
+func NewCircularDependencyRuleWithMinLength(graph DependencyGraph, minLength int) *CircularDependencyRule {
+	return &CircularDependencyRule{
+		graph:     graph,
+		minLength: minLength,
+	}
+}
+
 // ID returns the unique identifier for this rule
 func (r *CircularDependencyRule) ID() string {
 	return "rule.circular-dependency"
@@ -27,38 +42,95 @@ func (r *CircularDependencyRule) Category() string {
 }
 
 // Severity returns the severity level for this rule
-func (r *CircularDependencyRule) Severity() string {
-	return string(model.SeverityCritical)
+func (r *CircularDependencyRule) Severity() model.Severity {
+	return model.SeverityCritical
+}
+
+// Description explains what this rule reports
+func (r *CircularDependencyRule) Description() string {
+	return "Reports import cycles between files; cycles through the root package are flagged and weigh double"
+}
+
+// Thresholds returns the limits this rule checks against
+func (r *CircularDependencyRule) Thresholds() map[string]float64 {
+	return map[string]float64{"min_length": float64(r.minLength)}
 }
 
 func (r *CircularDependencyRule) Capabilities() RuleCapabilities {
 	return RuleCapabilities{SupportedLanguages: []string{"Go", "Python", "JavaScript", "TypeScript"}, SupportsMultipleLanguages: true}
 }
 
-// Evaluate executes the rule logic against the provided context
+// Evaluate executes the rule logic against the provided context.
+// Every cycle is critical; cycles through the root package are escalated
+// with a message naming the root and a double score impact, since they
+// usually indicate misplaced wiring.
+// Cycles shorter than the minimum length are dropped after detection, as
+// are extracted cycles that are not closed walks. A file importing itself
+// is reported as a self-import.
 func (r *CircularDependencyRule) Evaluate(context AnalysisContext) []model.Violation {
 	var violations []model.Violation
 
 	// Use the dependency graph from context or build one from repository files
 	graph := r.buildDependencyGraph(context)
-	cycles := r.detectCycles(graph)
+	cycles := model.WellFormedCycles(r.detectCycles(graph), func(from, to string) bool {
+		return slices.Contains(graph.Edges[from], to)
+	})
+	root, _ := context.Configuration["repositoryPath"].(string)
 
 	for _, cycle := range cycles {
-		if len(cycle) > 0 {
-			violations = append(violations, model.Violation{
-				RuleID:      r.ID(),
-				Severity:    model.SeverityCritical,
-				Message:     formatCycle(cycle),
-				File:        cycle[0],
-				Line:        0,
-				ScoreImpact: -10.0,
-			})
+		if len(cycle) == 0 || len(cycle) < r.minLength {
+			continue
+		}
+
+		violation := model.Violation{
+			RuleID:      r.ID(),
+			Severity:    model.SeverityCritical,
+			Message:     formatCycle(cycle),
+			File:        cycle[0],
+			Line:        0,
+			ScoreImpact: -10.0,
+		}
+		if len(cycle) == 1 {
+			violation.Message = "Self-import: " + violation.Message
 		}
+		if rootNode := findRootNode(cycle, root); rootNode != "" {
+			violation.Message = "Cycle with root package " + rootNode + ": " + violation.Message
+			violation.ScoreImpact = -20.0
+		}
+		violations = append(violations, violation)
 	}
 
 	return violations
 }
 
+// findRootNode returns the first node of a multi-node cycle that belongs to
+// the root package, or "" when the cycle does not involve the root
+func findRootNode(cycle []string, root string) string {
+	if len(cycle) < 2 {
+		return ""
+	}
+	for _, node := range cycle {
+		if isRootPackageNode(node, root) {
+			return node
+		}
+	}
+	return ""
+}
+
+// isRootPackageNode reports whether a graph node is the root package: "."
+// or a source file directly inside the repository root
+func isRootPackageNode(node, root string) bool {
+	if node == "." {
+		return true
+	}
+	if root == "" {
+		return false
+	}
+
+	rel := relativeSlashPath(root, node)
+	return path.Dir(rel) == "." && !strings.HasPrefix(rel, "/") && path.Ext(rel) != ""
+}
+
 // buildDependencyGraph builds a dependency graph from the context
 func (r *CircularDependencyRule) buildDependencyGraph(context AnalysisContext) DependencyGraph {
 	// If context has a dependency graph, use it
@@ -119,14 +191,15 @@ func (r *CircularDependencyRule) detectCycles(graph DependencyGraph) [][]string
 	return cycles
 }
 
-// extractCycle extracts the cycle from the current path
+// extractCycle extracts the cycle from the current path. The result is a
+// copy, since the DFS path is reused and overwritten as traversal continues.
 func extractCycle(path []string, start string) []string {
 	for i, node := range path {
 		if node == start {
-			return path[i:]
+			return append([]string(nil), path[i:]...)
 		}
 	}
-	return path
+	return append([]string(nil), path...)
 }
 
 // formatCycle formats a cycle path for display
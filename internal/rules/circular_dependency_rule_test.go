@@ -0,0 +1,124 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func evaluateCycles(edges map[string][]string) []model.Violation {
+	nodes := make([]string, 0, len(edges))
+	for node := range edges {
+		nodes = append(nodes, node)
+	}
+	graph := DependencyGraph{Nodes: nodes, Edges: edges}
+
+	rule := NewCircularDependencyRule(graph)
+	return rule.Evaluate(AnalysisContext{
+		DependencyGraph: graph,
+		Configuration:   Configuration{"repositoryPath": "/repo"},
+	})
+}
+
+func TestCircularDependencyRule_EscalatesCycleWithRootPackage(t *testing.T) {
+	violations := evaluateCycles(map[string][]string{
+		"/repo/main.go":                   {"/repo/internal/wiring/wiring.go"},
+		"/repo/internal/wiring/wiring.go": {"/repo/main.go"},
+	})
+
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 cycle, got %d", len(violations))
+	}
+	if violations[0].Severity != model.SeverityCritical || violations[0].ScoreImpact != -20 {
+		t.Fatalf("expected root cycle to be critical with double impact, got %s and %.1f", violations[0].Severity, violations[0].ScoreImpact)
+	}
+	if !strings.HasPrefix(violations[0].Message, "Cycle with root package /repo/main.go") {
+		t.Fatalf("expected message to name the root package, got %q", violations[0].Message)
+	}
+}
+
+func TestCircularDependencyRule_DoesNotEscalateNonRootCycle(t *testing.T) {
+	violations := evaluateCycles(map[string][]string{
+		"/repo/main.go":         {"/repo/internal/a/a.go"},
+		"/repo/internal/a/a.go": {"/repo/internal/b/b.go"},
+		"/repo/internal/b/b.go": {"/repo/internal/a/a.go"},
+	})
+
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 cycle, got %d", len(violations))
+	}
+	if violations[0].Severity != model.SeverityCritical || violations[0].ScoreImpact != -10 {
+		t.Fatalf("expected non-root cycle to stay critical with the plain impact, got %s and %.1f", violations[0].Severity, violations[0].ScoreImpact)
+	}
+	if strings.Contains(violations[0].Message, "root package") {
+		t.Fatalf("expected plain cycle message, got %q", violations[0].Message)
+	}
+}
+
+func TestCircularDependencyRule_MinLengthIgnoresShortCycles(t *testing.T) {
+	edges := map[string][]string{
+		"/repo/pkg/a/a.go": {"/repo/pkg/b/b.go"},
+		"/repo/pkg/b/b.go": {"/repo/pkg/a/a.go"},
+		"/repo/pkg/c/c.go": {"/repo/pkg/d/d.go"},
+		"/repo/pkg/d/d.go": {"/repo/pkg/e/e.go"},
+		"/repo/pkg/e/e.go": {"/repo/pkg/f/f.go"},
+		"/repo/pkg/f/f.go": {"/repo/pkg/c/c.go"},
+	}
+	nodes := make([]string, 0, len(edges))
+	for node := range edges {
+		nodes = append(nodes, node)
+	}
+	graph := DependencyGraph{Nodes: nodes, Edges: edges}
+
+	if violations := NewCircularDependencyRule(graph).Evaluate(AnalysisContext{DependencyGraph: graph}); len(violations) != 2 {
+		t.Fatalf("expected both cycles without a minimum length, got %d", len(violations))
+	}
+
+	violations := NewCircularDependencyRuleWithMinLength(graph, 3).Evaluate(AnalysisContext{DependencyGraph: graph})
+	if len(violations) != 1 {
+		t.Fatalf("expected only the 4-node cycle, got %d violations", len(violations))
+	}
+	if got := strings.Count(violations[0].Message, "→"); got != 4 {
+		t.Fatalf("expected the 4-node cycle to be reported, got %q", violations[0].Message)
+	}
+}
+
+func TestCircularDependencyRule_ReportsSelfImportDistinctly(t *testing.T) {
+	violations := evaluateCycles(map[string][]string{
+		"/repo/pkg/a/a.go": {"/repo/pkg/a/a.go", "/repo/pkg/b/b.go"},
+		"/repo/pkg/b/b.go": {"/repo/pkg/a/a.go"},
+	})
+
+	var selfImports, mutual int
+	for _, v := range violations {
+		if strings.HasPrefix(v.Message, "Self-import: ") {
+			selfImports++
+		} else {
+			mutual++
+		}
+	}
+	if selfImports != 1 || mutual != 1 {
+		t.Fatalf("expected one self-import and one mutual cycle, got %+v", violations)
+	}
+}
+
+func TestCircularDependencyRule_ReportsTriangleOnce(t *testing.T) {
+	triangle := [][2]string{{"/repo/a.go", "/repo/b.go"}, {"/repo/b.go", "/repo/c.go"}, {"/repo/c.go", "/repo/a.go"}}
+	for _, order := range [][]int{{0, 1, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}} {
+		graph := DependencyGraph{Edges: map[string][]string{}}
+		for _, i := range order {
+			from, to := triangle[i][0], triangle[i][1]
+			graph.Nodes = append(graph.Nodes, from)
+			graph.Edges[from] = append(graph.Edges[from], to)
+		}
+
+		violations := NewCircularDependencyRule(graph).Evaluate(AnalysisContext{DependencyGraph: graph, Configuration: Configuration{}})
+		if len(violations) != 1 {
+			t.Fatalf("edge order %v: expected the triangle once, got %d cycles", order, len(violations))
+		}
+		if violations[0].File != "/repo/a.go" || violations[0].Message != formatCycle([]string{"/repo/a.go", "/repo/b.go", "/repo/c.go"}) {
+			t.Fatalf("edge order %v: expected the cycle to start at its smallest node, got %+v", order, violations[0])
+		}
+	}
+}
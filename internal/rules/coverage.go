@@ -0,0 +1,57 @@
+package rules
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// RuleCoverage counts the repository files a rule examined and the files it
+// had to skip, so silent skips such as malformed sources are quantified
+type RuleCoverage struct {
+	RuleID   string `json:"ruleId"`
+	Examined int    `json:"examined"`
+	Skipped  int    `json:"skipped"`
+}
+
+// Total returns the number of files the rule considered
+func (c RuleCoverage) Total() int {
+	return c.Examined + c.Skipped
+}
+
+// CoverageAwareRule is an optional extension for rules that only examine the
+// files they can parse. Coverage must be derived from the same context passed
+// to Evaluate and, like Evaluate, must not mutate the rule.
+type CoverageAwareRule interface {
+	Rule
+	Coverage(context AnalysisContext) RuleCoverage
+}
+
+// goFileCoverage counts the Go source files a parser-based rule can examine.
+// Files that fail to parse with the given mode or that disable the rule with
+// a file directive are counted as skipped; test files are only considered
+// when includeTests is set.
+func goFileCoverage(ruleID string, files []RepositoryFile, mode parser.Mode, includeTests bool) RuleCoverage {
+	coverage := RuleCoverage{RuleID: ruleID}
+	fset := token.NewFileSet()
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, ".go") {
+			continue
+		}
+		if !includeTests && strings.HasSuffix(file.Path, "_test.go") {
+			continue
+		}
+		if fileDisablesRule(file.Content, ruleID) {
+			coverage.Skipped++
+			continue
+		}
+		if _, err := parser.ParseFile(fset, file.Path, file.Content, mode); err != nil {
+			coverage.Skipped++
+			continue
+		}
+		coverage.Examined++
+	}
+
+	return coverage
+}
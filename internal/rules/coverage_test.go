@@ -0,0 +1,42 @@
+package rules
+
+import "testing"
+
+func TestGoFileCoverage_MalformedFileCountedAsSkipped(t *testing.T) {
+	context := AnalysisContext{
+		RepositoryFiles: []RepositoryFile{
+			{Path: "/repo/ok.go", Content: "package repo\n\nfunc ok() {}\n"},
+			{Path: "/repo/broken.go", Content: "package repo\n\nfunc broken( {\n"},
+			{Path: "/repo/ok_test.go", Content: "package repo\n"},
+			{Path: "fmt"},
+		},
+	}
+
+	for _, rule := range []CoverageAwareRule{NewSizeRule(), NewGodObjectRule()} {
+		coverage := rule.Coverage(context)
+		if coverage.RuleID != rule.ID() {
+			t.Fatalf("expected coverage for %s, got %q", rule.ID(), coverage.RuleID)
+		}
+		if coverage.Examined != 2 || coverage.Skipped != 1 {
+			t.Fatalf("%s: expected 2 examined and 1 skipped, got %+v", rule.ID(), coverage)
+		}
+		if coverage.Total() != 3 {
+			t.Fatalf("%s: expected 3 Go files considered, got %d", rule.ID(), coverage.Total())
+		}
+	}
+}
+
+func TestEntrypointOnlyRule_CoverageIgnoresTestFiles(t *testing.T) {
+	context := AnalysisContext{
+		RepositoryFiles: []RepositoryFile{
+			{Path: "/repo/cmd/app/main.go", Content: "package main\n"},
+			{Path: "/repo/pkg/broken.go", Content: "func nope() {}\n"},
+			{Path: "/repo/pkg/pkg_test.go", Content: "package pkg\n"},
+		},
+	}
+
+	coverage := NewEntrypointOnlyRule(nil).Coverage(context)
+	if coverage.Examined != 1 || coverage.Skipped != 1 {
+		t.Fatalf("expected 1 examined and 1 skipped, got %+v", coverage)
+	}
+}
@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// DependencyCapRule flags repositories that depend on more distinct external
+// modules than allowed. The module inventory is collected by the caller, as
+// it needs the module manifest and the previous run's recorded modules.
+type DependencyCapRule struct {
+	// MaxExternal is the highest accepted number of external modules
+	MaxExternal int
+	// Modules are the distinct external module roots in use
+	Modules []string
+	// Newest are the modules not recorded by the previous run
+	Newest []string
+	// Manifest is the file the violation is reported against (go.mod)
+	Manifest string
+}
+
+// NewDependencyCapRule creates an external dependency cap rule
+func NewDependencyCapRule(maxExternal int, modules, newest []string, manifest string) *DependencyCapRule {
+	return &DependencyCapRule{MaxExternal: maxExternal, Modules: modules, Newest: newest, Manifest: manifest}
+}
+
+// ID returns the unique identifier for this rule
+func (r *DependencyCapRule) ID() string {
+	return "rule.dependency-cap"
+}
+
+// Category returns the category for this rule
+func (r *DependencyCapRule) Category() string {
+	return string(CategoryArchitecture)
+}
+
+// Severity returns the severity level for this rule
+func (r *DependencyCapRule) Severity() model.Severity {
+	return model.SeverityError
+}
+
+// Description explains what this rule reports
+func (r *DependencyCapRule) Description() string {
+	return "Reports repositories that depend on more external modules than allowed"
+}
+
+// Thresholds returns the limits this rule checks against
+func (r *DependencyCapRule) Thresholds() map[string]float64 {
+	return map[string]float64{"max_external": float64(r.MaxExternal)}
+}
+
+func (r *DependencyCapRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
+}
+
+// Evaluate reports a single violation when the module count exceeds the cap,
+// naming the newest modules as the likely offenders
+func (r *DependencyCapRule) Evaluate(context AnalysisContext) []model.Violation {
+	var violations []model.Violation
+	if r.MaxExternal < 0 || len(r.Modules) <= r.MaxExternal {
+		return violations
+	}
+
+	newest := "none since the previous run"
+	if len(r.Newest) > 0 {
+		newest = strings.Join(r.Newest, ", ")
+	}
+	violations = append(violations, model.Violation{
+		RuleID:      r.ID(),
+		Severity:    model.SeverityError,
+		Message:     fmt.Sprintf("%d external modules exceed the cap of %d (newest: %s)", len(r.Modules), r.MaxExternal, newest),
+		File:        r.Manifest,
+		Line:        0,
+		ScoreImpact: -5.0,
+	})
+	return violations
+}
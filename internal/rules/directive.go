@@ -0,0 +1,54 @@
+package rules
+
+import "strings"
+
+// disableDirective turns rules off for a whole file when it appears in the
+// file header, e.g. "//repodoctor:disable size,god-object"
+const disableDirective = "//repodoctor:disable"
+
+// fileDisablesRule reports whether the file header carries a disable
+// directive naming the rule, either by short name ("size") or by full ID
+// ("rule.size"). The header ends at the first import or declaration.
+func fileDisablesRule(content, ruleID string) bool {
+	shortName := strings.TrimPrefix(ruleID, "rule.")
+	inBlockComment := false
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlockComment:
+			inBlockComment = !strings.Contains(line, "*/")
+			continue
+		case line == "" || strings.HasPrefix(line, "package "):
+			continue
+		case strings.HasPrefix(line, "/*"):
+			inBlockComment = !strings.Contains(line, "*/")
+			continue
+		case !strings.HasPrefix(line, "//"):
+			return false
+		}
+
+		names, ok := strings.CutPrefix(line, disableDirective+" ")
+		if !ok {
+			continue
+		}
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if name == shortName || name == ruleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filesWithRuleEnabled returns the files whose header does not disable ruleID
+func filesWithRuleEnabled(files []RepositoryFile, ruleID string) []RepositoryFile {
+	enabled := make([]RepositoryFile, 0, len(files))
+	for _, file := range files {
+		if !fileDisablesRule(file.Content, ruleID) {
+			enabled = append(enabled, file)
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,56 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFileDisablesRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		ruleID  string
+		want    bool
+	}{
+		{"short names", "//repodoctor:disable size,god-object\npackage a\n", "rule.god-object", true},
+		{"full id with spaces", "package a\n\n//repodoctor:disable rule.size, god-object\n", "rule.size", true},
+		{"after block comment", "/*\nLicense text\n*/\n//repodoctor:disable size\npackage a\n", "rule.size", true},
+		{"other rule", "//repodoctor:disable god-object\npackage a\n", "rule.size", false},
+		{"below first declaration", "package a\n\nimport \"fmt\"\n\n//repodoctor:disable size\n", "rule.size", false},
+		{"prefix name only", "//repodoctor:disable size-ish\npackage a\n", "rule.size", false},
+		{"spaced comment is not a directive", "// repodoctor:disable size\npackage a\n", "rule.size", false},
+	}
+
+	for _, tt := range tests {
+		if got := fileDisablesRule(tt.content, tt.ruleID); got != tt.want {
+			t.Fatalf("%s: fileDisablesRule(%s) = %v, want %v", tt.name, tt.ruleID, got, tt.want)
+		}
+	}
+}
+
+func TestDisableDirective_SkipsSizeRuleOnly(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("//repodoctor:disable size\npackage big\n\ntype Big struct {\n")
+	for i := 0; i < 20; i++ {
+		content.WriteString("\tField" + string(rune('A'+i)) + " int\n")
+	}
+	content.WriteString("}\n")
+	for i := 0; i < 600; i++ {
+		content.WriteString("var _ = 1\n")
+	}
+
+	context := AnalysisContext{RepositoryFiles: []RepositoryFile{{Path: "/repo/big/big.go", Content: content.String()}}}
+
+	sizeRule := NewSizeRule()
+	if violations := sizeRule.Evaluate(context); len(violations) != 0 {
+		t.Fatalf("expected no size violations for a disabled file, got %v", violations)
+	}
+	if coverage := sizeRule.Coverage(context); coverage.Examined != 0 || coverage.Skipped != 1 {
+		t.Fatalf("expected the disabled file to count as skipped, got %+v", coverage)
+	}
+
+	violations := NewGodObjectRule().Evaluate(context)
+	if len(violations) != 1 || !strings.Contains(violations[0].Message, "Big has 20 fields") {
+		t.Fatalf("expected the god object rule to still flag Big, got %v", violations)
+	}
+}
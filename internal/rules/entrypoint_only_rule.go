@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// EntrypointOnlyRule flags packages whose only importers are entrypoints
+// (cmd/**) and test files. Such packages are frequently dead features or
+// wiring code that belongs next to the entrypoint.
+type EntrypointOnlyRule struct {
+	// Allowlist contains package directory prefixes (e.g. "tools/") that
+	// are never flagged
+	Allowlist []string
+	fset      *token.FileSet
+}
+
+// NewEntrypointOnlyRule creates a new entrypoint-only import rule
+func NewEntrypointOnlyRule(allowlist []string) *EntrypointOnlyRule {
+	return &EntrypointOnlyRule{
+		Allowlist: allowlist,
+		fset:      token.NewFileSet(),
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *EntrypointOnlyRule) ID() string {
+	return "rule.entrypoint-only"
+}
+
+// Category returns the category for this rule
+func (r *EntrypointOnlyRule) Category() string {
+	return string(CategoryArchitecture)
+}
+
+// Severity returns the severity level for this rule
+func (r *EntrypointOnlyRule) Severity() model.Severity {
+	return model.SeverityInfo
+}
+
+// Description explains what this rule reports
+func (r *EntrypointOnlyRule) Description() string {
+	return "Reports packages imported only by entrypoints and test files"
+}
+
+func (r *EntrypointOnlyRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
+}
+
+// Coverage reports the non-test Go files whose package clause could be read;
+// files that fail to parse count as skipped
+func (r *EntrypointOnlyRule) Coverage(context AnalysisContext) RuleCoverage {
+	return goFileCoverage(r.ID(), context.RepositoryFiles, parser.PackageClauseOnly, false)
+}
+
+// Evaluate executes the rule logic against the provided context
+func (r *EntrypointOnlyRule) Evaluate(context AnalysisContext) []model.Violation {
+	var violations []model.Violation
+
+	root, _ := context.Configuration["repositoryPath"].(string)
+	packages := r.collectPackages(context.RepositoryFiles, root)
+	importers := buildReverseImportIndex(context.RepositoryFiles, root, packages)
+
+	dirs := make([]string, 0, len(packages))
+	for dir := range packages {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		if packages[dir] || r.isAllowlisted(dir) {
+			continue
+		}
+
+		pkgImporters := importers[dir]
+		if len(pkgImporters) == 0 || !onlyEntrypointsAndTests(pkgImporters) {
+			continue
+		}
+
+		violations = append(violations, model.Violation{
+			RuleID:      r.ID(),
+			Severity:    model.SeverityInfo,
+			Message:     "Package " + dir + " is only imported by entrypoints and tests: " + strings.Join(pkgImporters, ", "),
+			File:        filepath.Join(root, filepath.FromSlash(dir)),
+			Line:        0,
+			ScoreImpact: 0,
+		})
+	}
+
+	return violations
+}
+
+// collectPackages returns every package directory (slash-separated and
+// relative to root) mapped to whether it is a main package. Files disabling
+// the rule by directive are left out, so a package whose files all do so is
+// never flagged.
+func (r *EntrypointOnlyRule) collectPackages(files []RepositoryFile, root string) map[string]bool {
+	packages := make(map[string]bool)
+	for _, file := range files {
+		rel := relativeSlashPath(root, file.Path)
+		if !strings.HasSuffix(rel, ".go") || strings.HasSuffix(rel, "_test.go") || fileDisablesRule(file.Content, r.ID()) {
+			continue
+		}
+
+		dir := path.Dir(rel)
+		isMain := packages[dir]
+		if node, err := parser.ParseFile(r.fset, file.Path, file.Content, parser.PackageClauseOnly); err == nil {
+			isMain = isMain || node.Name.Name == "main"
+		}
+		packages[dir] = isMain
+	}
+	return packages
+}
+
+func (r *EntrypointOnlyRule) isAllowlisted(dir string) bool {
+	for _, prefix := range r.Allowlist {
+		if strings.HasPrefix(dir+"/", prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildReverseImportIndex maps each package directory to the sorted list of
+// files importing it. Imports are resolved by matching the trailing path
+// segments of the import against known package directories, and a
+// package's own files are not counted as importers.
+func buildReverseImportIndex(files []RepositoryFile, root string, packages map[string]bool) map[string][]string {
+	dirs := sortedPackageDirs(packages)
+
+	index := make(map[string][]string)
+	for _, file := range files {
+		rel := relativeSlashPath(root, file.Path)
+		for _, imp := range file.Imports {
+			target := resolveImportDir(imp, dirs)
+			if target == "" || target == path.Dir(rel) {
+				continue
+			}
+			index[target] = append(index[target], rel)
+		}
+	}
+
+	for dir := range index {
+		sort.Strings(index[dir])
+	}
+	return index
+}
+
+// sortedPackageDirs lists the package directories other than the root,
+// longest first so resolveImportDir prefers "a/b/c" over "b/c".
+func sortedPackageDirs(packages map[string]bool) []string {
+	dirs := make([]string, 0, len(packages))
+	for dir := range packages {
+		if dir != "." {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if len(dirs[i]) != len(dirs[j]) {
+			return len(dirs[i]) > len(dirs[j])
+		}
+		return dirs[i] < dirs[j]
+	})
+	return dirs
+}
+
+func resolveImportDir(importPath string, dirs []string) string {
+	importPath = strings.TrimPrefix(importPath, "./")
+	for _, dir := range dirs {
+		if importPath == dir || strings.HasSuffix(importPath, "/"+dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
+func onlyEntrypointsAndTests(importers []string) bool {
+	for _, importer := range importers {
+		isEntrypoint := strings.HasPrefix(importer, "cmd/")
+		isTest := strings.HasSuffix(importer, "_test.go")
+		if !isEntrypoint && !isTest {
+			return false
+		}
+	}
+	return true
+}
+
+// relativeSlashPath returns filePath relative to root with forward slashes,
+// or the cleaned slash path when it cannot be made relative
+func relativeSlashPath(root, filePath string) string {
+	if root != "" {
+		if rel, err := filepath.Rel(root, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(filePath))
+}
@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const entrypointOnlyRoot = "/repo"
+
+func entrypointFile(rel, pkg string, imports ...string) RepositoryFile {
+	return RepositoryFile{
+		Path:    filepath.Join(entrypointOnlyRoot, filepath.FromSlash(rel)),
+		Content: "package " + pkg + "\n",
+		Imports: imports,
+	}
+}
+
+func evaluateEntrypointOnly(rule *EntrypointOnlyRule, files ...RepositoryFile) []string {
+	context := AnalysisContext{
+		RepositoryFiles: files,
+		Configuration:   Configuration{"repositoryPath": entrypointOnlyRoot},
+	}
+
+	messages := make([]string, 0)
+	for _, v := range rule.Evaluate(context) {
+		messages = append(messages, v.Message)
+	}
+	return messages
+}
+
+func TestEntrypointOnlyRule_FlagsPackageImportedOnlyByCmdAndTests(t *testing.T) {
+	rule := NewEntrypointOnlyRule(nil)
+	messages := evaluateEntrypointOnly(rule,
+		entrypointFile("cmd/app/main.go", "main", "example.com/app/internal/wiring"),
+		entrypointFile("internal/wiring/wiring.go", "wiring", "fmt"),
+		entrypointFile("internal/other/other_test.go", "other", "example.com/app/internal/wiring"),
+		entrypointFile("internal/other/other.go", "other"),
+		entrypointFile("fmt", ""),
+	)
+
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 violation, got %d: %v", len(messages), messages)
+	}
+	if !strings.Contains(messages[0], "internal/wiring") {
+		t.Fatalf("expected violation for internal/wiring, got %q", messages[0])
+	}
+	if !strings.Contains(messages[0], "cmd/app/main.go, internal/other/other_test.go") {
+		t.Fatalf("expected importers to be listed, got %q", messages[0])
+	}
+}
+
+func TestEntrypointOnlyRule_IgnoresPackageWithLibraryImporter(t *testing.T) {
+	rule := NewEntrypointOnlyRule(nil)
+	messages := evaluateEntrypointOnly(rule,
+		entrypointFile("cmd/app/main.go", "main", "example.com/app/internal/store"),
+		entrypointFile("internal/store/store.go", "store"),
+		entrypointFile("internal/service/service.go", "service", "example.com/app/internal/store"),
+	)
+
+	if len(messages) != 0 {
+		t.Fatalf("expected no violations, got %v", messages)
+	}
+}
+
+func TestEntrypointOnlyRule_SkipsAllowlistedAndMainPackages(t *testing.T) {
+	rule := NewEntrypointOnlyRule([]string{"tools/", "scripts/"})
+	messages := evaluateEntrypointOnly(rule,
+		entrypointFile("cmd/app/main.go", "main", "example.com/app/tools/gen", "example.com/app/cmd/shared"),
+		entrypointFile("tools/gen/gen.go", "gen"),
+		entrypointFile("cmd/shared/shared.go", "main"),
+	)
+
+	if len(messages) != 0 {
+		t.Fatalf("expected allowlisted and main packages to be skipped, got %v", messages)
+	}
+}
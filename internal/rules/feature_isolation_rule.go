@@ -0,0 +1,196 @@
+package rules
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// DefaultSharedRoots are the package roots treated as shared libraries
+var DefaultSharedRoots = []string{"common", "lib", "pkg/shared"}
+
+// DefaultFeatureRoots are the package roots treated as isolated features
+var DefaultFeatureRoots = []string{"features", "apps"}
+
+// FeatureIsolationRule flags shared library packages that depend on feature
+// packages. Such imports couple every consumer of the shared code to one
+// feature and break feature isolation in plugin-style repositories.
+type FeatureIsolationRule struct {
+	// SharedRoots and FeatureRoots are slash-separated path globs matched
+	// against package directories and their ancestors
+	SharedRoots  []string
+	FeatureRoots []string
+}
+
+// NewFeatureIsolationRule creates a feature isolation rule; nil root lists
+// fall back to the defaults
+func NewFeatureIsolationRule(sharedRoots, featureRoots []string) *FeatureIsolationRule {
+	if sharedRoots == nil {
+		sharedRoots = DefaultSharedRoots
+	}
+	if featureRoots == nil {
+		featureRoots = DefaultFeatureRoots
+	}
+	return &FeatureIsolationRule{SharedRoots: sharedRoots, FeatureRoots: featureRoots}
+}
+
+// ID returns the unique identifier for this rule
+func (r *FeatureIsolationRule) ID() string {
+	return "rule.feature-isolation"
+}
+
+// Category returns the category for this rule
+func (r *FeatureIsolationRule) Category() string {
+	return string(CategoryArchitecture)
+}
+
+// Severity returns the severity level for this rule
+func (r *FeatureIsolationRule) Severity() model.Severity {
+	return model.SeverityError
+}
+
+// Description explains what this rule reports
+func (r *FeatureIsolationRule) Description() string {
+	return "Reports shared packages that depend on feature packages, directly or transitively"
+}
+
+func (r *FeatureIsolationRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go", "Python", "JavaScript", "TypeScript"}, SupportsMultipleLanguages: true}
+}
+
+// Evaluate reports, for every shared package, the shortest import chain to
+// each feature package it reaches. Chains through other shared packages are
+// left to those packages so each crossing is reported once.
+func (r *FeatureIsolationRule) Evaluate(context AnalysisContext) []model.Violation {
+	var violations []model.Violation
+
+	root, _ := context.Configuration["repositoryPath"].(string)
+	adjacency := buildPackageImportGraph(context.RepositoryFiles, root)
+
+	packages := make([]string, 0, len(adjacency))
+	for pkg := range adjacency {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	for _, pkg := range packages {
+		if !matchesRoot(pkg, r.SharedRoots) {
+			continue
+		}
+
+		chains := shortestPathsTo(adjacency, pkg,
+			func(dir string) bool { return matchesRoot(dir, r.FeatureRoots) },
+			func(dir string) bool { return !matchesRoot(dir, r.SharedRoots) })
+		for _, chain := range chains {
+			violations = append(violations, model.Violation{
+				RuleID:      r.ID(),
+				Severity:    model.SeverityError,
+				Message:     "Shared package " + pkg + " depends on feature package " + chain[len(chain)-1] + ": " + strings.Join(chain, " → "),
+				File:        filepath.Join(root, filepath.FromSlash(pkg)),
+				Line:        0,
+				ScoreImpact: -5.0,
+			})
+		}
+	}
+
+	return violations
+}
+
+// buildPackageImportGraph maps each package directory (slash-separated and
+// relative to root) to the sorted package directories it imports
+func buildPackageImportGraph(files []RepositoryFile, root string) map[string][]string {
+	known := make(map[string]bool)
+	for _, file := range files {
+		rel := relativeSlashPath(root, file.Path)
+		if path.Ext(rel) == "" || strings.HasPrefix(rel, "/") || strings.HasSuffix(rel, "_test.go") {
+			continue
+		}
+		known[path.Dir(rel)] = true
+	}
+
+	dirs := sortedPackageDirs(known)
+
+	edges := make(map[string]map[string]bool, len(known))
+	for dir := range known {
+		edges[dir] = make(map[string]bool)
+	}
+	for _, file := range files {
+		rel := relativeSlashPath(root, file.Path)
+		from := path.Dir(rel)
+		if _, ok := edges[from]; !ok || strings.HasSuffix(rel, "_test.go") {
+			continue
+		}
+		for _, imp := range file.Imports {
+			if target := resolveImportDir(imp, dirs); target != "" && target != from {
+				edges[from][target] = true
+			}
+		}
+	}
+
+	adjacency := make(map[string][]string, len(edges))
+	for dir, targets := range edges {
+		list := make([]string, 0, len(targets))
+		for target := range targets {
+			list = append(list, target)
+		}
+		sort.Strings(list)
+		adjacency[dir] = list
+	}
+	return adjacency
+}
+
+// shortestPathsTo runs a breadth-first search from start and returns the
+// shortest chain to every reachable node accepted by isTarget. The search
+// only continues through intermediate nodes accepted by traverse; targets
+// are not expanded. Chains are ordered by their target.
+func shortestPathsTo(adjacency map[string][]string, start string, isTarget, traverse func(string) bool) [][]string {
+	parent := map[string]string{start: ""}
+	queue := []string{start}
+	var targets []string
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[node] {
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			parent[next] = node
+			if isTarget(next) {
+				targets = append(targets, next)
+				continue
+			}
+			if traverse(next) {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	sort.Strings(targets)
+	chains := make([][]string, 0, len(targets))
+	for _, target := range targets {
+		chain := []string{target}
+		for node := parent[target]; node != ""; node = parent[node] {
+			chain = append([]string{node}, chain...)
+		}
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// matchesRoot reports whether dir or one of its ancestors matches any of the
+// root globs
+func matchesRoot(dir string, roots []string) bool {
+	for _, pattern := range roots {
+		pattern = strings.Trim(pattern, "/")
+		for candidate := dir; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const featureIsolationRoot = "/repo"
+
+func featureFile(rel string, imports ...string) RepositoryFile {
+	return RepositoryFile{
+		Path:    filepath.Join(featureIsolationRoot, filepath.FromSlash(rel)),
+		Imports: imports,
+	}
+}
+
+func evaluateFeatureIsolation(rule *FeatureIsolationRule, files ...RepositoryFile) []string {
+	context := AnalysisContext{
+		RepositoryFiles: files,
+		Configuration:   Configuration{"repositoryPath": featureIsolationRoot},
+	}
+
+	messages := make([]string, 0)
+	for _, v := range rule.Evaluate(context) {
+		messages = append(messages, v.Message)
+	}
+	return messages
+}
+
+func TestFeatureIsolationRule_CleanLayoutHasNoViolations(t *testing.T) {
+	rule := NewFeatureIsolationRule(nil, nil)
+	messages := evaluateFeatureIsolation(rule,
+		featureFile("features/billing/invoice.go", "example.com/app/lib/money", "example.com/app/common/log"),
+		featureFile("apps/web/main.go", "example.com/app/features/billing"),
+		featureFile("lib/money/money.go", "example.com/app/common/log"),
+		featureFile("common/log/log.go"),
+	)
+
+	if len(messages) != 0 {
+		t.Fatalf("expected no violations for a clean layout, got %v", messages)
+	}
+}
+
+func TestFeatureIsolationRule_FlagsSharedToFeatureEdgeWithChain(t *testing.T) {
+	rule := NewFeatureIsolationRule(nil, nil)
+	messages := evaluateFeatureIsolation(rule,
+		featureFile("features/billing/invoice.go"),
+		featureFile("lib/money/money.go", "example.com/app/features/billing"),
+		featureFile("pkg/shared/audit/audit.go", "example.com/app/internal/glue"),
+		featureFile("internal/glue/glue.go", "example.com/app/apps/web"),
+		featureFile("apps/web/main.go"),
+		// Reaches a feature only through another shared package, which is
+		// reported on its own
+		featureFile("common/log/log.go", "example.com/app/lib/money"),
+	)
+
+	want := []string{
+		"Shared package lib/money depends on feature package features/billing: lib/money → features/billing",
+		"Shared package pkg/shared/audit depends on feature package apps/web: pkg/shared/audit → internal/glue → apps/web",
+	}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected violations\nwant: %v\ngot:  %v", want, messages)
+	}
+}
+
+func TestFeatureIsolationRule_CustomRoots(t *testing.T) {
+	rule := NewFeatureIsolationRule([]string{"platform/*"}, []string{"modules"})
+	messages := evaluateFeatureIsolation(rule,
+		featureFile("platform/db/db.go", "example.com/app/modules/orders"),
+		featureFile("modules/orders/orders.go"),
+		// Default roots no longer apply
+		featureFile("lib/util/util.go", "example.com/app/features/search"),
+		featureFile("features/search/search.go"),
+	)
+
+	if len(messages) != 1 || !strings.HasPrefix(messages[0], "Shared package platform/db depends on feature package modules/orders") {
+		t.Fatalf("expected only the custom-root violation, got %v", messages)
+	}
+}
@@ -1,9 +1,6 @@
 package rules
 
 import (
-	"go/ast"
-	"go/parser"
-	"go/token"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -16,7 +13,7 @@ import (
 type GodObjectRule struct {
 	MaxFields  int
 	MaxMethods int
-	fset       *token.FileSet
+	cache      *ParseCache
 }
 
 // NewGodObjectRule creates a new god object detection rule
@@ -24,7 +21,7 @@ func NewGodObjectRule() *GodObjectRule {
 	return &GodObjectRule{
 		MaxFields:  15,
 		MaxMethods: 10,
-		fset:       token.NewFileSet(),
+		cache:      sharedParseCache,
 	}
 }
 
@@ -39,14 +36,30 @@ func (r *GodObjectRule) Category() string {
 }
 
 // Severity returns the severity level for this rule
-func (r *GodObjectRule) Severity() string {
-	return string(model.SeverityWarning)
+func (r *GodObjectRule) Severity() model.Severity {
+	return model.SeverityWarning
+}
+
+// Description explains what this rule reports
+func (r *GodObjectRule) Description() string {
+	return "Reports structs with more fields or methods than the configured limits"
+}
+
+// Thresholds returns the limits this rule checks against
+func (r *GodObjectRule) Thresholds() map[string]float64 {
+	return map[string]float64{"max_fields": float64(r.MaxFields), "max_methods": float64(r.MaxMethods)}
 }
 
 func (r *GodObjectRule) Capabilities() RuleCapabilities {
 	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
 }
 
+// Coverage reports the Go files scanned for structs and methods; files that
+// fail to parse or disable the rule by directive count as skipped
+func (r *GodObjectRule) Coverage(context AnalysisContext) RuleCoverage {
+	return goFileCoverage(r.ID(), context.RepositoryFiles, 0, true)
+}
+
 // Evaluate executes the rule logic against the provided context
 func (r *GodObjectRule) Evaluate(context AnalysisContext) []model.Violation {
 	var violations []model.Violation
@@ -55,15 +68,24 @@ func (r *GodObjectRule) Evaluate(context AnalysisContext) []model.Violation {
 	// Keys are Dir(filePath)+"#"+structName to prevent cross-package
 	// name collisions (e.g. main.DependencyGraph vs model.DependencyGraph).
 	structMethods := make(map[string]*structInfo)
-
-	// First pass: collect all struct definitions and their fields
-	for _, file := range context.RepositoryFiles {
-		r.collectStructs(file, structMethods)
+	files := filesWithRuleEnabled(context.RepositoryFiles, r.ID())
+	parsed := r.cache.ParseAll(files)
+
+	// First pass: collect all struct definitions and their fields, then
+	// count what they get through embedding
+	packages := make(map[string]*PackageTypes)
+	for i, file := range files {
+		if parsed[i] != nil {
+			collectStructs(file.Path, parsed[i], structMethods, packages)
+		}
 	}
+	resolveEmbedding(structMethods, packages)
 
 	// Second pass: collect all method declarations
-	for _, file := range context.RepositoryFiles {
-		r.collectMethods(file, structMethods)
+	for i, file := range files {
+		if parsed[i] != nil {
+			collectMethods(file.Path, parsed[i], structMethods)
+		}
 	}
 
 	// Check for violations
@@ -78,7 +100,8 @@ func (r *GodObjectRule) Evaluate(context AnalysisContext) []model.Violation {
 				Severity:    model.SeverityWarning,
 				Message:     info.Name + " has " + strconv.Itoa(fieldCount) + " fields (threshold: " + strconv.Itoa(r.MaxFields) + ")",
 				File:        info.File,
-				Line:        0,
+				Line:        info.Line,
+				EndLine:     info.EndLine,
 				ScoreImpact: -5.0,
 			})
 		}
@@ -90,7 +113,8 @@ func (r *GodObjectRule) Evaluate(context AnalysisContext) []model.Violation {
 				Severity:    model.SeverityWarning,
 				Message:     info.Name + " has " + strconv.Itoa(methodCount) + " methods (threshold: " + strconv.Itoa(r.MaxMethods) + ")",
 				File:        info.File,
-				Line:        0,
+				Line:        info.Line,
+				EndLine:     info.EndLine,
 				ScoreImpact: -5.0,
 			})
 		}
@@ -103,6 +127,8 @@ func (r *GodObjectRule) Evaluate(context AnalysisContext) []model.Violation {
 type structInfo struct {
 	Name        string // bare struct name for display
 	File        string
+	Line        int
+	EndLine     int
 	FieldCount  int
 	MethodCount int
 }
@@ -114,83 +140,39 @@ func structKey(filePath, structName string) string {
 	return filepath.Dir(filePath) + "#" + structName
 }
 
-// collectStructs collects all struct definitions and their field counts
-func (r *GodObjectRule) collectStructs(file RepositoryFile, structMethods map[string]*structInfo) {
-	node, err := parser.ParseFile(r.fset, file.Path, file.Content, 0)
-	if err != nil {
-		return // Skip malformed files
-	}
-
-	// Walk through all declarations
-	ast.Inspect(node, func(n ast.Node) bool {
-		typeSpec, ok := n.(*ast.TypeSpec)
-		if !ok {
-			return true
-		}
-
-		structType, ok := typeSpec.Type.(*ast.StructType)
-		if !ok {
-			return true
-		}
-
-		// Count fields
-		fieldCount := 0
-		if structType.Fields != nil {
-			fieldCount = structType.Fields.NumFields()
-		}
-
-		structName := typeSpec.Name.Name
-		key := structKey(file.Path, structName)
-		structMethods[key] = &structInfo{
-			Name:        structName,
-			File:        file.Path,
-			FieldCount:  fieldCount,
+// collectStructs collects all struct definitions and records the file's
+// declarations with the types of its package
+func collectStructs(path string, parsed *ParsedGoFile, structMethods map[string]*structInfo, packages map[string]*PackageTypes) {
+	PackageTypesOf(packages, path).AddFile(parsed)
+	for _, st := range parsed.Structs {
+		structMethods[structKey(path, st.Name)] = &structInfo{
+			Name:        st.Name,
+			File:        path,
+			Line:        st.Line,
+			EndLine:     st.EndLine,
+			FieldCount:  st.Fields,
 			MethodCount: 0,
 		}
-
-		return true
-	})
+	}
 }
 
-// collectMethods collects all method declarations for each struct
-func (r *GodObjectRule) collectMethods(file RepositoryFile, structMethods map[string]*structInfo) {
-	node, err := parser.ParseFile(r.fset, file.Path, file.Content, 0)
-	if err != nil {
-		return // Skip malformed files
+// resolveEmbedding replaces each struct's field count with the one that
+// includes embedded structs and adds the methods of embedded interfaces
+func resolveEmbedding(structMethods map[string]*structInfo, packages map[string]*PackageTypes) {
+	for _, info := range structMethods {
+		types := PackageTypesOf(packages, info.File)
+		info.FieldCount = types.FieldCount(info.Name)
+		info.MethodCount += types.EmbeddedMethodCount(info.Name)
 	}
+}
 
-	// Walk through all declarations
-	ast.Inspect(node, func(n ast.Node) bool {
-		funcDecl, ok := n.(*ast.FuncDecl)
-		if !ok {
-			return true
-		}
-
-		// Check if this is a method (has receiver)
-		if funcDecl.Recv == nil {
-			return true
-		}
-
-		// Get receiver type
-		for _, field := range funcDecl.Recv.List {
-			recvType := field.Type
-
-			// Handle pointer receivers (*T)
-			if starExpr, ok := recvType.(*ast.StarExpr); ok {
-				recvType = starExpr.X
-			}
-
-			// Get the type name and look up with package-qualified key
-			if ident, ok := recvType.(*ast.Ident); ok {
-				key := structKey(file.Path, ident.Name)
-				if info, exists := structMethods[key]; exists {
-					info.MethodCount++
-				}
-			}
+// collectMethods counts the method declarations of each collected struct
+func collectMethods(path string, parsed *ParsedGoFile, structMethods map[string]*structInfo) {
+	for _, recv := range parsed.MethodReceivers {
+		if info, exists := structMethods[structKey(path, recv)]; exists {
+			info.MethodCount++
 		}
-
-		return true
-	})
+	}
 }
 
 // LoadFromDir loads Go files from a directory and returns them as RepositoryFile slices
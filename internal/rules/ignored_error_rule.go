@@ -0,0 +1,151 @@
+package rules
+
+import (
+	"fmt"
+	"go/parser"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// IgnoredErrorViolation is an error return discarded to the blank identifier
+type IgnoredErrorViolation struct {
+	File     string
+	Function string
+	Line     int
+}
+
+// knownErrorFuncs maps standard library functions, by import path and name,
+// to their result count when the last result is an error
+var knownErrorFuncs = map[string]int{
+	"os.Chdir": 1, "os.Chmod": 1, "os.Mkdir": 1, "os.MkdirAll": 1, "os.Remove": 1,
+	"os.RemoveAll": 1, "os.Rename": 1, "os.Setenv": 1, "os.Unsetenv": 1, "os.WriteFile": 1,
+	"os.Create": 2, "os.Open": 2, "os.OpenFile": 2, "os.ReadFile": 2, "os.ReadDir": 2, "os.Stat": 2,
+	"io.Copy": 2, "io.ReadAll": 2, "io.WriteString": 2,
+	"encoding/json.Unmarshal": 1, "encoding/json.Marshal": 2, "encoding/json.MarshalIndent": 2,
+	"strconv.Atoi": 2, "strconv.ParseBool": 2, "strconv.ParseFloat": 2, "strconv.ParseInt": 2, "strconv.ParseUint": 2,
+	"path/filepath.Abs": 2, "path/filepath.Rel": 2, "path/filepath.Walk": 1, "path/filepath.WalkDir": 1,
+}
+
+// knownErrorMethods maps method names that conventionally return an error
+// as their last result, such as io.Closer's Close, to their result count
+var knownErrorMethods = map[string]int{
+	"Close": 1, "Flush": 1, "Sync": 1, "Shutdown": 1,
+	"Read": 2, "Write": 2, "WriteString": 2,
+}
+
+// IgnoredErrorRule detects assignments that discard an error return to `_`.
+// Without type checking it relies on names: a call discards an error when
+// it calls a standard library function or conventional method known to
+// return one, or a function or method of the same name declared in the
+// analyzed files with error as its last result.
+type IgnoredErrorRule struct {
+	cache *ParseCache
+}
+
+// NewIgnoredErrorRule creates an ignored error rule
+func NewIgnoredErrorRule() *IgnoredErrorRule {
+	return &IgnoredErrorRule{cache: sharedParseCache}
+}
+
+// ID returns the unique identifier for this rule
+func (r *IgnoredErrorRule) ID() string {
+	return "rule.ignored-error"
+}
+
+// Category returns the category for this rule
+func (r *IgnoredErrorRule) Category() string {
+	return string(CategoryMaintainability)
+}
+
+// Severity returns the severity level for this rule
+func (r *IgnoredErrorRule) Severity() model.Severity {
+	return model.SeverityInfo
+}
+
+// Description explains what this rule reports
+func (r *IgnoredErrorRule) Description() string {
+	return "Reports error returns discarded to the blank identifier"
+}
+
+// Thresholds returns the limits this rule checks against
+func (r *IgnoredErrorRule) Thresholds() map[string]float64 {
+	return map[string]float64{}
+}
+
+func (r *IgnoredErrorRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
+}
+
+// Coverage reports the non-test Go files scanned for discarded errors
+func (r *IgnoredErrorRule) Coverage(context AnalysisContext) RuleCoverage {
+	return goFileCoverage(r.ID(), context.RepositoryFiles, parser.SkipObjectResolution, false)
+}
+
+// Evaluate reports every discarded error return
+func (r *IgnoredErrorRule) Evaluate(context AnalysisContext) []model.Violation {
+	var violations []model.Violation
+	for _, v := range r.Analyze(filesWithRuleEnabled(context.RepositoryFiles, r.ID())) {
+		violations = append(violations, model.Violation{
+			RuleID:      r.ID(),
+			Severity:    model.SeverityInfo,
+			Message:     fmt.Sprintf("Function '%s' discards an error return at line %d", v.Function, v.Line),
+			File:        v.File,
+			Line:        v.Line,
+			ScoreImpact: 0,
+		})
+	}
+	return violations
+}
+
+// Analyze returns the discarded error returns in the non-test Go files, in
+// file order. Test files are skipped since they commonly discard errors on
+// purpose.
+func (r *IgnoredErrorRule) Analyze(files []RepositoryFile) []IgnoredErrorViolation {
+	var goFiles []RepositoryFile
+	for _, file := range files {
+		if strings.HasSuffix(file.Path, ".go") && !strings.HasSuffix(file.Path, "_test.go") {
+			goFiles = append(goFiles, file)
+		}
+	}
+	parsed := r.cache.ParseAll(goFiles)
+
+	declared := make(map[string]int)
+	for _, p := range parsed {
+		if p == nil {
+			continue
+		}
+		for _, fn := range p.ErrorFuncs {
+			declared[fn.Name] = fn.Results
+		}
+	}
+
+	violations := make([]IgnoredErrorViolation, 0)
+	for i, p := range parsed {
+		if p == nil {
+			continue
+		}
+		for _, call := range p.BlankCalls {
+			if discardsError(call, declared) {
+				violations = append(violations, IgnoredErrorViolation{File: goFiles[i].Path, Function: call.Function, Line: call.Line})
+			}
+		}
+	}
+	return violations
+}
+
+// discardsError reports whether a blank assignment drops an error: the call
+// must be known to return one and the assignment must take every result
+func discardsError(call BlankCall, declared map[string]int) bool {
+	var results int
+	var known bool
+	switch {
+	case call.Package != "":
+		results, known = knownErrorFuncs[call.Package+"."+call.Callee]
+	default:
+		if results, known = declared[call.Callee]; !known {
+			results, known = knownErrorMethods[call.Callee]
+		}
+	}
+	return known && results == call.Results
+}
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func ignoredErrors(t *testing.T, content string) []IgnoredErrorViolation {
+	t.Helper()
+	rule := &IgnoredErrorRule{cache: NewParseCache()}
+	return rule.Analyze([]RepositoryFile{{Path: "/repo/sample/sample.go", Content: content}})
+}
+
+func TestIgnoredErrorRule_DetectsDiscardedError(t *testing.T) {
+	got := ignoredErrors(t, `package sample
+
+type store struct{}
+
+func (s *store) Load(key string) (string, error) { return key, nil }
+
+func save() error { return nil }
+
+func run(s *store) {
+	_ = save()
+	value, _ := s.Load("key")
+	_ = value
+}
+`)
+	want := []IgnoredErrorViolation{
+		{File: "/repo/sample/sample.go", Function: "run", Line: 10},
+		{File: "/repo/sample/sample.go", Function: "run", Line: 11},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestIgnoredErrorRule_DetectsStandardLibraryAndMethodCalls(t *testing.T) {
+	got := ignoredErrors(t, `package sample
+
+import (
+	"os"
+	fp "path/filepath"
+)
+
+func cleanup(p string, f *os.File) {
+	_ = os.Remove(p)
+	_ = f.Close()
+	abs, _ := fp.Abs(p)
+	_ = abs
+	_, _ = os.Stat(p)
+}
+`)
+	var lines []int
+	for _, v := range got {
+		lines = append(lines, v.Line)
+	}
+	if want := []int{9, 10, 11, 13}; !reflect.DeepEqual(lines, want) {
+		t.Fatalf("expected violations at lines %v, got %+v", want, got)
+	}
+}
+
+func TestIgnoredErrorRule_IgnoresHandledError(t *testing.T) {
+	got := ignoredErrors(t, `package sample
+
+import "os"
+
+func save() error { return nil }
+
+func count() (int, bool) { return 0, false }
+
+func run(os2 string) error {
+	if err := save(); err != nil {
+		return err
+	}
+	n, _ := count()
+	_ = n
+	_ = os.Getenv("HOME")
+	return os.Remove(os2)
+}
+`)
+	if len(got) != 0 {
+		t.Fatalf("expected no violations, got %+v", got)
+	}
+}
@@ -0,0 +1,130 @@
+package rules
+
+import (
+	"fmt"
+	"go/parser"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// CohesionViolation is a package whose imports span too many unrelated
+// external domains to be considered cohesive
+type CohesionViolation struct {
+	Package     string
+	DomainCount int
+	Domains     []string
+}
+
+// ImportDiversityRule flags low-cohesion candidates by measuring the import
+// domain diversity of each package: the number of distinct external modules
+// imported across its files. The caller resolves import paths to modules,
+// as that needs the module manifest.
+type ImportDiversityRule struct {
+	// MaxDomains is the highest accepted number of external domains
+	MaxDomains int
+	// Domain maps an import path to its external module root, or "" for
+	// standard library and own-module imports. A nil Domain reports nothing.
+	Domain func(importPath string) string
+}
+
+// NewImportDiversityRule creates an import domain diversity rule
+func NewImportDiversityRule(maxDomains int, domain func(string) string) *ImportDiversityRule {
+	return &ImportDiversityRule{MaxDomains: maxDomains, Domain: domain}
+}
+
+// ID returns the unique identifier for this rule
+func (r *ImportDiversityRule) ID() string {
+	return "rule.import-diversity"
+}
+
+// Category returns the category for this rule
+func (r *ImportDiversityRule) Category() string {
+	return string(CategoryMaintainability)
+}
+
+// Severity returns the severity level for this rule
+func (r *ImportDiversityRule) Severity() model.Severity {
+	return model.SeverityInfo
+}
+
+// Description explains what this rule reports
+func (r *ImportDiversityRule) Description() string {
+	return "Reports packages importing more distinct external modules than allowed"
+}
+
+// Thresholds returns the limits this rule checks against
+func (r *ImportDiversityRule) Thresholds() map[string]float64 {
+	return map[string]float64{"max_domains": float64(r.MaxDomains)}
+}
+
+func (r *ImportDiversityRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
+}
+
+// Coverage reports the non-test Go files whose imports are grouped
+func (r *ImportDiversityRule) Coverage(context AnalysisContext) RuleCoverage {
+	return goFileCoverage(r.ID(), context.RepositoryFiles, parser.ImportsOnly, false)
+}
+
+// Evaluate reports every package whose domain count exceeds MaxDomains
+func (r *ImportDiversityRule) Evaluate(context AnalysisContext) []model.Violation {
+	var violations []model.Violation
+	for _, cv := range r.Analyze(filesWithRuleEnabled(context.RepositoryFiles, r.ID())) {
+		violations = append(violations, model.Violation{
+			RuleID:   r.ID(),
+			Severity: model.SeverityInfo,
+			Message: fmt.Sprintf("Package %s imports %d external domains (max: %d): %s",
+				cv.Package, cv.DomainCount, r.MaxDomains, strings.Join(cv.Domains, ", ")),
+			File:        cv.Package,
+			Line:        0,
+			ScoreImpact: 0,
+		})
+	}
+	return violations
+}
+
+// Analyze groups the imports of the non-test Go files by package directory
+// and returns the packages whose domain count exceeds MaxDomains, sorted by
+// package
+func (r *ImportDiversityRule) Analyze(files []RepositoryFile) []CohesionViolation {
+	violations := make([]CohesionViolation, 0)
+	if r.Domain == nil {
+		return violations
+	}
+
+	packageDomains := make(map[string]map[string]bool)
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, ".go") || strings.HasSuffix(file.Path, "_test.go") {
+			continue
+		}
+		pkg := filepath.ToSlash(filepath.Dir(file.Path))
+		if packageDomains[pkg] == nil {
+			packageDomains[pkg] = make(map[string]bool)
+		}
+		for _, imp := range file.Imports {
+			if domain := r.Domain(imp); domain != "" {
+				packageDomains[pkg][domain] = true
+			}
+		}
+	}
+
+	for pkg, domains := range packageDomains {
+		if len(domains) <= r.MaxDomains {
+			continue
+		}
+		names := make([]string, 0, len(domains))
+		for domain := range domains {
+			names = append(names, domain)
+		}
+		sort.Strings(names)
+		violations = append(violations, CohesionViolation{Package: pkg, DomainCount: len(domains), Domains: names})
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Package < violations[j].Package
+	})
+	return violations
+}
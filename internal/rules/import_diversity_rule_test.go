@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// hostedDomain treats host/owner/repo as the module and paths without a
+// dot in their first element as standard library
+func hostedDomain(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	if !strings.Contains(parts[0], ".") {
+		return ""
+	}
+	return strings.Join(parts[:min(len(parts), 3)], "/")
+}
+
+func TestImportDiversityRule_FlagsGrabBagPackage(t *testing.T) {
+	files := []RepositoryFile{
+		{Path: "/repo/grabbag/db.go", Imports: []string{"database/sql", "github.com/lib/pq", "github.com/jmoiron/sqlx"}},
+		{Path: "/repo/grabbag/http.go", Imports: []string{"net/http", "github.com/gorilla/mux", "github.com/gin-gonic/gin/render"}},
+		{Path: "/repo/grabbag/misc.go", Imports: []string{"golang.org/x/crypto/bcrypt", "github.com/flosch/pongo2", "html/template"}},
+		{Path: "/repo/grabbag/misc_test.go", Imports: []string{"github.com/stretchr/testify/assert"}},
+		{Path: "/repo/focused/store.go", Imports: []string{"fmt", "os", "strings", "errors", "github.com/lib/pq", "github.com/lib/pq/oid"}},
+		{Path: "/repo/focused/query.go", Imports: []string{"context", "github.com/jmoiron/sqlx"}},
+	}
+
+	got := NewImportDiversityRule(5, hostedDomain).Analyze(files)
+	want := []CohesionViolation{{
+		Package:     "/repo/grabbag",
+		DomainCount: 6,
+		Domains:     []string{"github.com/flosch/pongo2", "github.com/gin-gonic/gin", "github.com/gorilla/mux", "github.com/jmoiron/sqlx", "github.com/lib/pq", "golang.org/x/crypto"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected only the grab-bag package, got %+v", got)
+	}
+
+	violations := NewImportDiversityRule(5, hostedDomain).Evaluate(AnalysisContext{RepositoryFiles: files})
+	if len(violations) != 1 || violations[0].File != "/repo/grabbag" || !strings.HasPrefix(violations[0].Message, "Package /repo/grabbag imports 6 external domains (max: 5)") {
+		t.Fatalf("unexpected violations: %+v", violations)
+	}
+}
+
+func TestImportDiversityRule_StandardLibraryIsNotADomain(t *testing.T) {
+	files := []RepositoryFile{{Path: "/repo/util/util.go", Imports: []string{"fmt", "os", "strings", "errors", "io", "sort", "time"}}}
+
+	if got := NewImportDiversityRule(1, hostedDomain).Analyze(files); len(got) != 0 {
+		t.Fatalf("expected standard library imports to be ignored, got %+v", got)
+	}
+	if got := NewImportDiversityRule(0, nil).Analyze(files); len(got) != 0 {
+		t.Fatalf("expected no findings without a domain resolver, got %+v", got)
+	}
+}
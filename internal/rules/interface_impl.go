@@ -0,0 +1,211 @@
+package rules
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InterfaceImplementations lists the concrete types of the analyzed files
+// whose method sets satisfy one interface declared in them. Names are
+// qualified with their package name, e.g. "rules.Rule".
+type InterfaceImplementations struct {
+	Interface       string
+	File            string
+	Implementations []string
+}
+
+// typeDecl indexes one named type: its methods (by name, mapped to an
+// arity signature) and, for interfaces, the interfaces it embeds
+type typeDecl struct {
+	name     string // package-qualified display name
+	file     string
+	isIface  bool
+	complete bool // false when the method set cannot be determined
+	methods  map[string]string
+	embedded []string // index keys of embedded types in the same package
+}
+
+// FindInterfaceImplementations matches every interface declared in the Go
+// files against every concrete type declared in them. Matching is syntactic:
+// a type implements an interface when it has methods of the same names and
+// parameter/result counts, including methods promoted from embedded types of
+// the same package. Interfaces whose method set cannot be resolved (embedded
+// external interfaces, type constraints) are left out.
+func FindInterfaceImplementations(files []RepositoryFile) []InterfaceImplementations {
+	index := indexTypeDecls(files)
+
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var results []InterfaceImplementations
+	for _, key := range keys {
+		iface := index[key]
+		if !iface.isIface {
+			continue
+		}
+		required, ok := resolveMethodSet(index, key, map[string]bool{})
+		if !ok || len(required) == 0 {
+			continue
+		}
+
+		impls := []string{}
+		for _, candidateKey := range keys {
+			candidate := index[candidateKey]
+			if candidate.isIface {
+				continue
+			}
+			if provided, _ := resolveMethodSet(index, candidateKey, map[string]bool{}); satisfies(provided, required) {
+				impls = append(impls, candidate.name)
+			}
+		}
+		results = append(results, InterfaceImplementations{Interface: iface.name, File: iface.file, Implementations: impls})
+	}
+	return results
+}
+
+func satisfies(provided, required map[string]string) bool {
+	for name, signature := range required {
+		if provided[name] != signature {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveMethodSet returns the full method set of a type, following embedded
+// types within the same package. ok is false when part of it is unknown.
+func resolveMethodSet(index map[string]*typeDecl, key string, visiting map[string]bool) (map[string]string, bool) {
+	decl := index[key]
+	if decl == nil || visiting[key] {
+		return nil, false
+	}
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	methods := make(map[string]string, len(decl.methods))
+	complete := decl.complete
+	for _, embedded := range decl.embedded {
+		promoted, ok := resolveMethodSet(index, embedded, visiting)
+		complete = complete && ok
+		for name, signature := range promoted {
+			if _, own := methods[name]; !own {
+				methods[name] = signature
+			}
+		}
+	}
+	for name, signature := range decl.methods {
+		methods[name] = signature
+	}
+	return methods, complete
+}
+
+// indexTypeDecls parses the Go files and indexes their named types and
+// methods, keyed by package directory and type name
+func indexTypeDecls(files []RepositoryFile) map[string]*typeDecl {
+	fset := token.NewFileSet()
+	index := make(map[string]*typeDecl)
+	var parsed []*ast.File
+	var paths []string
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, ".go") {
+			continue
+		}
+		node, err := parser.ParseFile(fset, file.Path, file.Content, 0)
+		if err != nil {
+			continue
+		}
+		indexTypeSpecs(node, file.Path, index)
+		parsed, paths = append(parsed, node), append(paths, file.Path)
+	}
+
+	for i, node := range parsed {
+		for _, decl := range node.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+				continue
+			}
+			if owner := index[structKey(paths[i], receiverTypeName(fn.Recv.List[0].Type))]; owner != nil && !owner.isIface {
+				owner.methods[fn.Name.Name] = funcArity(fn.Type)
+			}
+		}
+	}
+	return index
+}
+
+// indexTypeSpecs records every top-level named type declared in a file
+func indexTypeSpecs(node *ast.File, path string, index map[string]*typeDecl) {
+	dir := filepath.Dir(path)
+	for _, d := range node.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range gen.Specs {
+			spec := s.(*ast.TypeSpec)
+			decl := &typeDecl{
+				name:     node.Name.Name + "." + spec.Name.Name,
+				file:     path,
+				complete: spec.TypeParams == nil,
+				methods:  make(map[string]string),
+			}
+			switch t := spec.Type.(type) {
+			case *ast.InterfaceType:
+				decl.isIface = true
+				indexInterfaceMethods(t, dir, decl)
+			case *ast.StructType:
+				for _, field := range t.Fields.List {
+					if len(field.Names) == 0 {
+						decl.embedded = append(decl.embedded, dir+"#"+receiverTypeName(field.Type))
+					}
+				}
+			}
+			index[structKey(path, spec.Name.Name)] = decl
+		}
+	}
+}
+
+// indexInterfaceMethods records an interface's declared methods and the
+// interfaces it embeds. Embedded types from other packages and type
+// constraint elements make the method set incomplete.
+func indexInterfaceMethods(iface *ast.InterfaceType, dir string, decl *typeDecl) {
+	for _, field := range iface.Methods.List {
+		if fn, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+			decl.methods[field.Names[0].Name] = funcArity(fn)
+			continue
+		}
+		if ident, ok := field.Type.(*ast.Ident); ok {
+			decl.embedded = append(decl.embedded, dir+"#"+ident.Name)
+			continue
+		}
+		decl.complete = false
+	}
+}
+
+// funcArity summarises a function type as "params/results", with a trailing
+// "..." for variadic functions
+func funcArity(fn *ast.FuncType) string {
+	params, results := fieldCount(fn.Params), fieldCount(fn.Results)
+	signature := strconv.Itoa(params) + "/" + strconv.Itoa(results)
+	if fn.Params != nil && len(fn.Params.List) > 0 {
+		if _, variadic := fn.Params.List[len(fn.Params.List)-1].Type.(*ast.Ellipsis); variadic {
+			signature += "..."
+		}
+	}
+	return signature
+}
+
+func fieldCount(list *ast.FieldList) int {
+	if list == nil {
+		return 0
+	}
+	return list.NumFields()
+}
@@ -0,0 +1,77 @@
+package rules
+
+import "sort"
+
+// FileSize is a file and its non-empty line count, as the size rule counts it
+type FileSize struct {
+	File  string `json:"file"`
+	Lines int    `json:"lines"`
+}
+
+// FunctionSize is a function, its line span and its cyclomatic complexity
+type FunctionSize struct {
+	Function   string `json:"function"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Lines      int    `json:"lines"`
+	Complexity int    `json:"complexity"`
+}
+
+// LargestArtifacts lists the largest files and functions regardless of the
+// size thresholds, so growth is visible before it becomes a violation
+type LargestArtifacts struct {
+	Files                 []FileSize     `json:"files"`
+	FunctionsByLines      []FunctionSize `json:"functionsByLines"`
+	FunctionsByComplexity []FunctionSize `json:"functionsByComplexity"`
+}
+
+// FindLargestArtifacts returns the topN largest files and functions. Files
+// are parsed through cache, so after the size and god object rules ran this
+// only reads cached results. Ties are ordered by file, function name and
+// line.
+func FindLargestArtifacts(files []RepositoryFile, cache *ParseCache, topN int) *LargestArtifacts {
+	largest := &LargestArtifacts{Files: []FileSize{}}
+	var functions []FunctionSize
+	for i, parsed := range cache.ParseAll(files) {
+		if files[i].Content == "" {
+			continue
+		}
+		largest.Files = append(largest.Files, FileSize{File: files[i].Path, Lines: countNonEmptyLines(files[i].Content)})
+		if parsed == nil {
+			continue
+		}
+		for _, fn := range parsed.Functions {
+			functions = append(functions, FunctionSize{Function: fn.Name, File: files[i].Path, Line: fn.StartLine, Lines: fn.Lines(), Complexity: fn.Complexity})
+		}
+	}
+
+	sort.Slice(largest.Files, func(i, j int) bool {
+		if largest.Files[i].Lines != largest.Files[j].Lines {
+			return largest.Files[i].Lines > largest.Files[j].Lines
+		}
+		return largest.Files[i].File < largest.Files[j].File
+	})
+	largest.Files = largest.Files[:min(topN, len(largest.Files))]
+	largest.FunctionsByLines = topFunctions(functions, topN, func(f FunctionSize) int { return f.Lines })
+	largest.FunctionsByComplexity = topFunctions(functions, topN, func(f FunctionSize) int { return f.Complexity })
+	return largest
+}
+
+// topFunctions returns the topN functions with the highest measure
+func topFunctions(functions []FunctionSize, topN int, measure func(FunctionSize) int) []FunctionSize {
+	sorted := append([]FunctionSize{}, functions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if measure(a) != measure(b) {
+			return measure(a) > measure(b)
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Function != b.Function {
+			return a.Function < b.Function
+		}
+		return a.Line < b.Line
+	})
+	return sorted[:min(topN, len(sorted))]
+}
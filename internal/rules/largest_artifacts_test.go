@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// largestFixture builds twelve files of 17 non-empty lines each. File i
+// holds LongNN with i+1 statements and BranchyNN with 11-i if statements,
+// so every file and function stays below the size thresholds.
+func largestFixture() []RepositoryFile {
+	files := make([]RepositoryFile, 12)
+	for i := range files {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("package p\n\nfunc Long%02d() {\n", i))
+		sb.WriteString(strings.Repeat("\t_ = 1\n", i+1))
+		sb.WriteString(fmt.Sprintf("}\n\nfunc Branchy%02d(x int) {\n", i))
+		sb.WriteString(strings.Repeat("\tif x > 0 { x-- }\n", 11-i))
+		sb.WriteString("}\n")
+		files[i] = RepositoryFile{Path: fmt.Sprintf("/repo/f%02d.go", i), Content: sb.String()}
+	}
+	return files
+}
+
+func TestFindLargestArtifacts_ListsItemsBelowThresholds(t *testing.T) {
+	files := largestFixture()
+	if violations := NewSizeRule().Evaluate(AnalysisContext{RepositoryFiles: files}); len(violations) != 0 {
+		t.Fatalf("expected the fixture to stay below the size thresholds, got %v", violations)
+	}
+
+	largest := FindLargestArtifacts(files, NewParseCache(), 10)
+
+	var fileNames []string
+	for _, f := range largest.Files {
+		if f.Lines != 17 {
+			t.Fatalf("expected 17 lines in %s, got %d", f.File, f.Lines)
+		}
+		fileNames = append(fileNames, strings.TrimPrefix(f.File, "/repo/"))
+	}
+	// Equal sizes fall back to path order
+	if want := []string{"f00.go", "f01.go", "f02.go", "f03.go", "f04.go", "f05.go", "f06.go", "f07.go", "f08.go", "f09.go"}; !reflect.DeepEqual(fileNames, want) {
+		t.Fatalf("expected files %v, got %v", want, fileNames)
+	}
+
+	names := func(functions []FunctionSize) []string {
+		var out []string
+		for _, f := range functions {
+			out = append(out, f.Function)
+		}
+		return out
+	}
+	wantByLines := []string{"Long11", "Branchy00", "Long10", "Branchy01", "Long09", "Branchy02", "Long08", "Branchy03", "Long07", "Branchy04"}
+	if got := names(largest.FunctionsByLines); !reflect.DeepEqual(got, wantByLines) {
+		t.Fatalf("expected functions by lines %v, got %v", wantByLines, got)
+	}
+	if top := largest.FunctionsByLines[0]; top.Lines != 14 || top.File != "/repo/f11.go" || top.Line != 3 {
+		t.Fatalf("unexpected longest function: %+v", top)
+	}
+
+	wantByComplexity := []string{"Branchy00", "Branchy01", "Branchy02", "Branchy03", "Branchy04", "Branchy05", "Branchy06", "Branchy07", "Branchy08", "Branchy09"}
+	if got := names(largest.FunctionsByComplexity); !reflect.DeepEqual(got, wantByComplexity) {
+		t.Fatalf("expected functions by complexity %v, got %v", wantByComplexity, got)
+	}
+	if top := largest.FunctionsByComplexity[0]; top.Complexity != 12 {
+		t.Fatalf("expected complexity 12 for Branchy00, got %d", top.Complexity)
+	}
+}
+
+func TestCyclomaticComplexity_CountsDecisionPoints(t *testing.T) {
+	source := `package p
+
+func F(x int, ch chan int) {
+	if x > 0 && x < 10 || x == 20 {
+	}
+	for i := 0; i < x; i++ {
+	}
+	for range ch {
+	}
+	switch x {
+	case 1, 2:
+	case 3:
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+}
+`
+	parsed := ParseGoFile(RepositoryFile{Path: "f.go", Content: source})
+	// 1 + if + && + || + for + range + 2 cases + 1 comm case
+	if got := parsed.Functions[0].Complexity; got != 9 {
+		t.Fatalf("expected complexity 9, got %d", got)
+	}
+}
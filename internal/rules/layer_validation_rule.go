@@ -1,6 +1,13 @@
 package rules
 
-import "RepoDoctor/internal/model"
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
 
 // LayerConvention represents the allowed dependency direction
 type LayerConvention string
@@ -11,19 +18,51 @@ const (
 	LayerRepo    LayerConvention = "repo"
 )
 
-// layerOrder defines the hierarchy (lower index = higher layer)
-var layerOrder = map[LayerConvention]int{
-	LayerHandler: 0,
-	LayerService: 1,
-	LayerRepo:    2,
+// LayerLevel is one level of a layer hierarchy. A path belongs to the level
+// when any of its keywords appears as a whole path segment.
+type LayerLevel struct {
+	Name     string
+	Keywords []string
+}
+
+// LayerHierarchy orders layers from highest (index 0) to lowest. Imports may
+// only point downwards.
+type LayerHierarchy struct {
+	Levels []LayerLevel
+	// Exempt lists keywords whose members are never checked, as importer or import
+	Exempt []string
+	// Fallback names the level of paths matching no keyword; when empty such
+	// paths are not checked
+	Fallback string
+}
+
+// DefaultLayerHierarchy returns the built-in handler -> service -> repo
+// hierarchy, where unmatched paths count as the service layer
+func DefaultLayerHierarchy() LayerHierarchy {
+	return LayerHierarchy{
+		Levels: []LayerLevel{
+			{Name: string(LayerHandler), Keywords: []string{string(LayerHandler)}},
+			{Name: string(LayerService), Keywords: []string{string(LayerService)}},
+			{Name: string(LayerRepo), Keywords: []string{string(LayerRepo)}},
+		},
+		Fallback: string(LayerService),
+	}
 }
 
 // LayerValidationRule enforces architectural layering constraints
-type LayerValidationRule struct{}
+type LayerValidationRule struct {
+	hierarchy LayerHierarchy
+}
 
 // NewLayerValidationRule creates a new layer validation rule checker
 func NewLayerValidationRule() *LayerValidationRule {
-	return &LayerValidationRule{}
+	return NewLayerValidationRuleWithHierarchy(DefaultLayerHierarchy())
+}
+
+// NewLayerValidationRuleWithHierarchy creates a layer validation rule for a
+// custom, arbitrarily deep hierarchy
+func NewLayerValidationRuleWithHierarchy(hierarchy LayerHierarchy) *LayerValidationRule {
+	return &LayerValidationRule{hierarchy: hierarchy}
 }
 
 // ID returns the unique identifier for this rule
@@ -37,8 +76,13 @@ func (r *LayerValidationRule) Category() string {
 }
 
 // Severity returns the severity level for this rule
-func (r *LayerValidationRule) Severity() string {
-	return string(model.SeverityError)
+func (r *LayerValidationRule) Severity() model.Severity {
+	return model.SeverityError
+}
+
+// Description explains what this rule reports
+func (r *LayerValidationRule) Description() string {
+	return "Reports imports from a lower architectural layer into a higher one"
 }
 
 func (r *LayerValidationRule) Capabilities() RuleCapabilities {
@@ -51,17 +95,24 @@ func (r *LayerValidationRule) Evaluate(context AnalysisContext) []model.Violatio
 
 	// Check all files and their imports
 	for _, file := range context.RepositoryFiles {
-		fromLayer := detectLayer(file.Path)
+		fromLevel := r.hierarchy.detectLevel(file.Path)
+		if fromLevel < 0 {
+			continue
+		}
 
 		for _, imp := range file.Imports {
-			toLayer := detectLayer(imp)
+			toLevel := r.hierarchy.detectLevel(imp)
 
 			// Check if this is an upward import (forbidden)
-			if isUpwardImport(fromLayer, toLayer) {
+			if isUpwardImport(fromLevel, toLevel) {
+				message := r.hierarchy.formatViolation(file.Path, imp, fromLevel, toLevel)
+				if slices.Contains(file.BlankImports, imp) {
+					message += " (" + BlankImportNote(file.Path+" → "+imp) + ")"
+				}
 				violations = append(violations, model.Violation{
 					RuleID:      r.ID(),
 					Severity:    model.SeverityError,
-					Message:     formatLayerViolation(file.Path, imp, fromLayer, toLayer),
+					Message:     message,
 					File:        file.Path,
 					Line:        0,
 					ScoreImpact: -5.0,
@@ -73,58 +124,59 @@ func (r *LayerValidationRule) Evaluate(context AnalysisContext) []model.Violatio
 	return violations
 }
 
-// detectLayer detects the layer of a package based on its path
-func detectLayer(pkgPath string) LayerConvention {
-	// Check for layer keywords in the path
-	if containsLayerKeyword(pkgPath, "handler") {
-		return LayerHandler
+// detectLevel returns the index of the level a path belongs to, or -1 when
+// the path is exempt or matches no level and there is no fallback
+func (h LayerHierarchy) detectLevel(pkgPath string) int {
+	segments := LayerPathSegments(pkgPath)
+	for _, keyword := range h.Exempt {
+		if slices.Contains(segments, keyword) {
+			return -1
+		}
 	}
-	if containsLayerKeyword(pkgPath, "service") {
-		return LayerService
+
+	// Levels are matched in order, so a higher layer wins on ambiguous paths
+	for i, level := range h.Levels {
+		for _, keyword := range level.Keywords {
+			if slices.Contains(segments, keyword) {
+				return i
+			}
+		}
 	}
-	if containsLayerKeyword(pkgPath, "repo") {
-		return LayerRepo
+
+	for i, level := range h.Levels {
+		if h.Fallback != "" && level.Name == h.Fallback {
+			return i
+		}
 	}
+	return -1
+}
 
-	// Default to service layer if no specific layer detected
-	return LayerService
-}
-
-// containsLayerKeyword checks if a path contains a layer keyword
-func containsLayerKeyword(path, keyword string) bool {
-	// Simple check: look for /keyword/ or /keyword at end
-	if len(path) >= len(keyword) {
-		for i := 0; i <= len(path)-len(keyword); i++ {
-			if i+len(keyword) <= len(path) {
-				substr := path[i : i+len(keyword)]
-				if substr == keyword {
-					// Check if it's a word boundary
-					beforeOK := i == 0 || path[i-1] == '/' || path[i-1] == '\\'
-					afterOK := i+len(keyword) == len(path) || path[i+len(keyword)] == '/' || path[i+len(keyword)] == '\\'
-					if beforeOK && afterOK {
-						return true
-					}
-				}
-			}
+// LayerPathSegments splits a path into the segments a layer keyword must
+// equal, so service_impl and myrepo match no keyword. The file name also
+// counts without its extension, which puts service.go in the service layer.
+func LayerPathSegments(path string) []string {
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' })
+	if n := len(segments); n > 0 {
+		if ext := filepath.Ext(segments[n-1]); ext != "" && ext != segments[n-1] {
+			segments = append(segments, strings.TrimSuffix(segments[n-1], ext))
 		}
 	}
-	return false
+	return segments
 }
 
 // isUpwardImport checks if an import goes upward in the layer hierarchy
-func isUpwardImport(from, to LayerConvention) bool {
-	fromLevel, fromExists := layerOrder[from]
-	toLevel, toExists := layerOrder[to]
-
-	if !fromExists || !toExists {
+func isUpwardImport(fromLevel, toLevel int) bool {
+	if fromLevel < 0 || toLevel < 0 {
 		return false
 	}
 
-	// Upward import: from lower layer (higher number) to higher layer (lower number)
+	// Upward import: from lower layer (higher index) to higher layer (lower index)
 	return toLevel < fromLevel
 }
 
-// formatLayerViolation formats a layer violation message
-func formatLayerViolation(from, to string, fromLayer, toLayer LayerConvention) string {
-	return from + " (" + string(fromLayer) + ") -> " + to + " (" + string(toLayer) + "): upward import not allowed"
+// formatViolation formats a layer violation message naming both levels and
+// their 1-based positions in the hierarchy
+func (h LayerHierarchy) formatViolation(from, to string, fromLevel, toLevel int) string {
+	return fmt.Sprintf("%s (%s, level %d) -> %s (%s, level %d): upward import not allowed",
+		from, h.Levels[fromLevel].Name, fromLevel+1, to, h.Levels[toLevel].Name, toLevel+1)
 }
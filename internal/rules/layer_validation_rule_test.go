@@ -0,0 +1,118 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func fiveLevelHierarchy() LayerHierarchy {
+	return LayerHierarchy{
+		Levels: []LayerLevel{
+			{Name: "transport", Keywords: []string{"transport", "http", "grpc"}},
+			{Name: "application", Keywords: []string{"app", "usecase"}},
+			{Name: "domain", Keywords: []string{"domain"}},
+			{Name: "infrastructure", Keywords: []string{"infra", "postgres"}},
+			{Name: "platform", Keywords: []string{"platform"}},
+		},
+		Exempt: []string{"testutil", "mocks"},
+	}
+}
+
+func evaluateLayers(rule *LayerValidationRule, files ...RepositoryFile) []string {
+	messages := make([]string, 0)
+	for _, v := range rule.Evaluate(AnalysisContext{RepositoryFiles: files}) {
+		messages = append(messages, v.Message)
+	}
+	return messages
+}
+
+func TestLayerValidationRule_CustomHierarchyAllowsDownwardImports(t *testing.T) {
+	rule := NewLayerValidationRuleWithHierarchy(fiveLevelHierarchy())
+	messages := evaluateLayers(rule,
+		RepositoryFile{Path: "svc/http/server.go", Imports: []string{"svc/usecase/orders", "svc/platform/log"}},
+		RepositoryFile{Path: "svc/usecase/orders.go", Imports: []string{"svc/domain/order"}},
+		RepositoryFile{Path: "svc/postgres/orders.go", Imports: []string{"svc/platform/db"}},
+	)
+
+	if len(messages) != 0 {
+		t.Fatalf("expected no violations for downward imports, got %v", messages)
+	}
+}
+
+func TestLayerValidationRule_CustomHierarchyFlagsNonAdjacentUpwardImport(t *testing.T) {
+	rule := NewLayerValidationRuleWithHierarchy(fiveLevelHierarchy())
+	messages := evaluateLayers(rule,
+		RepositoryFile{Path: "svc/platform/db/conn.go", Imports: []string{"svc/grpc/api"}},
+		RepositoryFile{Path: "svc/infra/cache.go", Imports: []string{"svc/app/config"}},
+	)
+
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 violations, got %d: %v", len(messages), messages)
+	}
+	want := "svc/platform/db/conn.go (platform, level 5) -> svc/grpc/api (transport, level 1)"
+	if !strings.HasPrefix(messages[0], want) {
+		t.Fatalf("expected message to name both levels and positions, got %q", messages[0])
+	}
+	if !strings.Contains(messages[1], "(infrastructure, level 4)") || !strings.Contains(messages[1], "(application, level 2)") {
+		t.Fatalf("expected infrastructure -> application violation, got %q", messages[1])
+	}
+}
+
+func TestLayerValidationRule_CustomHierarchySkipsExemptAndUnmatchedPaths(t *testing.T) {
+	rule := NewLayerValidationRuleWithHierarchy(fiveLevelHierarchy())
+	messages := evaluateLayers(rule,
+		RepositoryFile{Path: "svc/testutil/fixtures.go", Imports: []string{"svc/http/server"}},
+		RepositoryFile{Path: "svc/platform/mocks/api.go", Imports: []string{"svc/transport/api"}},
+		RepositoryFile{Path: "svc/domain/order.go", Imports: []string{"svc/mocks/clock"}},
+		RepositoryFile{Path: "svc/tools/gen.go", Imports: []string{"svc/http/server"}},
+	)
+
+	if len(messages) != 0 {
+		t.Fatalf("expected exempt and unmatched paths to be skipped, got %v", messages)
+	}
+}
+
+func TestLayerValidationRule_DefaultHierarchyKeepsServiceFallback(t *testing.T) {
+	rule := NewLayerValidationRule()
+	messages := evaluateLayers(rule,
+		RepositoryFile{Path: "project/repo/user_repo.go", Imports: []string{"project/util/strings"}},
+	)
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "(service, level 2)") {
+		t.Fatalf("expected unmatched import to fall back to service layer, got %v", messages)
+	}
+}
+
+func TestLayerValidationRule_CallsOutBlankImports(t *testing.T) {
+	rule := NewLayerValidationRuleWithHierarchy(fiveLevelHierarchy())
+	messages := evaluateLayers(rule,
+		RepositoryFile{Path: "svc/platform/db/conn.go", Imports: []string{"svc/grpc/api", "svc/app/migrations"}, BlankImports: []string{"svc/app/migrations"}},
+	)
+
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 violations, got %v", messages)
+	}
+	if strings.Contains(messages[0], "blank import") {
+		t.Fatalf("expected a named import to carry no blank import note, got %q", messages[0])
+	}
+	if !strings.HasSuffix(messages[1], "("+BlankImportNote("svc/platform/db/conn.go → svc/app/migrations")+")") || !strings.Contains(messages[1], "init-time side effects") {
+		t.Fatalf("expected the blank import to be called out, got %q", messages[1])
+	}
+}
+
+func TestLayerHierarchy_MatchesWholePathSegments(t *testing.T) {
+	hierarchy := DefaultLayerHierarchy()
+	hierarchy.Fallback = ""
+
+	tests := map[string]int{
+		"internal/reporting/service.go": 1,
+		"pkg/reposcan/x.go":             -1,
+		"service_impl/x.go":             -1,
+		"app/myrepo/handlers.go":        -1,
+	}
+	for path, want := range tests {
+		if got := hierarchy.detectLevel(path); got != want {
+			t.Errorf("%s: expected level %d, got %d", path, want, got)
+		}
+	}
+}
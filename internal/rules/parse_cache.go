@@ -0,0 +1,341 @@
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ParsedGoFile is what the size and god object rules need from one Go file.
+// Positions are resolved to line numbers at parse time, so no token.FileSet
+// outlives the parse and results can be shared between goroutines.
+type ParsedGoFile struct {
+	Functions []FunctionSpan
+	Structs   []StructFields
+	// Interfaces holds the interface type declarations, which structs can
+	// embed
+	Interfaces []InterfaceMethods `json:",omitempty"`
+	// MethodReceivers holds the receiver type name of every method whose
+	// receiver is a plain or pointer identifier, in source order
+	MethodReceivers []string
+	// ErrorFuncs holds the functions and methods whose last result is error
+	ErrorFuncs []ErrorFunc `json:",omitempty"`
+	// BlankCalls holds the assignments of a call whose last result goes to _
+	BlankCalls []BlankCall `json:",omitempty"`
+}
+
+// ErrorFunc is a declared function or method whose last result is error
+type ErrorFunc struct {
+	Name    string
+	Results int
+}
+
+// BlankCall is an assignment such as `_ = f()` or `v, _ := x.F()` inside
+// Function. Package is the import path when the call is qualified by an
+// imported package, and empty for local functions and method calls.
+type BlankCall struct {
+	Function string
+	Package  string `json:",omitempty"`
+	Callee   string
+	Results  int
+	Line     int
+}
+
+// FunctionSpan is a function declaration, the lines it occupies and its
+// cyclomatic complexity
+type FunctionSpan struct {
+	Name       string
+	StartLine  int
+	EndLine    int
+	Complexity int
+}
+
+// Lines returns the number of lines the function spans, inclusive
+func (f FunctionSpan) Lines() int {
+	return f.EndLine - f.StartLine + 1
+}
+
+// StructFields is a struct type declaration, its field count, the line of
+// its name and the line it ends on. Fields leaves out the types of the same package listed in
+// Embedded, which PackageTypes resolves.
+type StructFields struct {
+	Name     string
+	Fields   int
+	Line     int
+	EndLine  int
+	Embedded []string `json:",omitempty"`
+}
+
+// ParseCache parses Go files concurrently and keeps the results by path.
+// Each file is parsed with its own token.FileSet: concurrent ParseFile calls
+// on a shared FileSet race and corrupt position information. A cached entry
+// is reused only while the file content is unchanged.
+type ParseCache struct {
+	mu      sync.Mutex
+	entries map[string]CachedParse
+	hits    int
+	misses  int
+}
+
+// CachedParse is one cache entry. Hash is the hex SHA-256 of the content the
+// entry was parsed from, so entries stay valid across processes.
+type CachedParse struct {
+	Path   string        `json:"path"`
+	Hash   string        `json:"hash"`
+	Parsed *ParsedGoFile `json:"parsed"` // nil when the file failed to parse
+}
+
+// ParseCacheStats counts cache entries and the lookups served since the
+// cache was created or reset. Misses are the files actually parsed.
+type ParseCacheStats struct {
+	Entries int
+	Hits    int
+	Misses  int
+}
+
+// sharedParseCache lets rules evaluated in the same run reuse each other's
+// parse results
+var sharedParseCache = NewParseCache()
+
+// SharedParseCache returns the cache the size and god object rules use
+func SharedParseCache() *ParseCache {
+	return sharedParseCache
+}
+
+// NewParseCache creates an empty parse cache
+func NewParseCache() *ParseCache {
+	return &ParseCache{entries: make(map[string]CachedParse)}
+}
+
+// Stats returns the current entry count and lookup counters
+func (c *ParseCache) Stats() ParseCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ParseCacheStats{Entries: len(c.entries), Hits: c.hits, Misses: c.misses}
+}
+
+// Reset drops every entry and zeroes the lookup counters
+func (c *ParseCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]CachedParse)
+	c.hits, c.misses = 0, 0
+}
+
+// Snapshot returns the entries whose path keep accepts, sorted by path
+func (c *ParseCache) Snapshot(keep func(path string) bool) []CachedParse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]CachedParse, 0, len(c.entries))
+	for path, entry := range c.entries {
+		if keep == nil || keep(path) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+// Restore adds previously snapshotted entries. Restored entries are still
+// checked against the file content on lookup.
+func (c *ParseCache) Restore(entries []CachedParse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range entries {
+		c.entries[entry.Path] = entry
+	}
+}
+
+// ParseAll returns the parse result of every file, in input order, using one
+// worker per CPU. Entries are nil for files that fail to parse.
+func (c *ParseCache) ParseAll(files []RepositoryFile) []*ParsedGoFile {
+	results := make([]*ParsedGoFile, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = c.parse(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// parse returns the cached result for a file, parsing it on a miss. Empty
+// content, such as an import path node without a file, never parses.
+func (c *ParseCache) parse(file RepositoryFile) *ParsedGoFile {
+	if file.Content == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(file.Content))
+	hash := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	entry, ok := c.entries[file.Path]
+	if ok && entry.Hash == hash {
+		c.hits++
+		c.mu.Unlock()
+		return entry.Parsed
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	parsed := ParseGoFile(file)
+	c.mu.Lock()
+	c.entries[file.Path] = CachedParse{Path: file.Path, Hash: hash, Parsed: parsed}
+	c.mu.Unlock()
+	return parsed
+}
+
+// ParseGoFile parses one file with a FileSet of its own and resolves every
+// position it records. It returns nil when the file fails to parse.
+func ParseGoFile(file RepositoryFile) *ParsedGoFile {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, file.Path, file.Content, 0)
+	if err != nil {
+		return nil
+	}
+
+	parsed := &ParsedGoFile{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch decl := n.(type) {
+		case *ast.FuncDecl:
+			parsed.Functions = append(parsed.Functions, FunctionSpan{
+				Name:       decl.Name.Name,
+				StartLine:  fset.Position(decl.Pos()).Line,
+				EndLine:    fset.Position(decl.End()).Line,
+				Complexity: cyclomaticComplexity(decl.Body),
+			})
+			if decl.Recv != nil {
+				parsed.MethodReceivers = append(parsed.MethodReceivers, methodReceivers(decl.Recv)...)
+			}
+			if results := decl.Type.Results; results != nil {
+				if last, ok := results.List[len(results.List)-1].Type.(*ast.Ident); ok && last.Name == "error" {
+					parsed.ErrorFuncs = append(parsed.ErrorFuncs, ErrorFunc{Name: decl.Name.Name, Results: results.NumFields()})
+				}
+			}
+		case *ast.TypeSpec:
+			switch typ := decl.Type.(type) {
+			case *ast.StructType:
+				st := NewStructFields(decl.Name.Name, typ, fset.Position(decl.Name.Pos()).Line)
+				st.EndLine = fset.Position(decl.End()).Line
+				parsed.Structs = append(parsed.Structs, st)
+			case *ast.InterfaceType:
+				parsed.Interfaces = append(parsed.Interfaces, NewInterfaceMethods(decl.Name.Name, typ))
+			}
+		}
+		return true
+	})
+	parsed.BlankCalls = blankCalls(fset, node)
+	return parsed
+}
+
+// cyclomaticComplexity is one plus the number of decision points in a
+// function body: conditions, loops, non-default cases and && or ||
+// operators. Function literals count toward the enclosing function.
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	if body == nil {
+		return complexity
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if node.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// methodReceivers returns the receiver type names of T and *T receivers
+func methodReceivers(recv *ast.FieldList) []string {
+	var names []string
+	for _, field := range recv.List {
+		recvType := field.Type
+		if starExpr, ok := recvType.(*ast.StarExpr); ok {
+			recvType = starExpr.X
+		}
+		if ident, ok := recvType.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	}
+	return names
+}
+
+// blankCalls returns the assignments in the file's function bodies whose
+// single right-hand side is a call and whose last operand is _
+func blankCalls(fset *token.FileSet, node *ast.File) []BlankCall {
+	packages := make(map[string]string)
+	for _, spec := range node.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		packages[name] = importPath
+	}
+
+	var calls []BlankCall
+	for _, decl := range node.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Rhs) != 1 {
+				return true
+			}
+			call, ok := assign.Rhs[0].(*ast.CallExpr)
+			if blank, isIdent := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident); !ok || !isIdent || blank.Name != "_" {
+				return true
+			}
+			bc := BlankCall{Function: funcDecl.Name.Name, Results: len(assign.Lhs), Line: fset.Position(assign.Pos()).Line}
+			switch fn := call.Fun.(type) {
+			case *ast.Ident:
+				bc.Callee = fn.Name
+			case *ast.SelectorExpr:
+				bc.Callee = fn.Sel.Name
+				if x, ok := fn.X.(*ast.Ident); ok && x.Obj == nil {
+					bc.Package = packages[x.Name]
+				}
+			default:
+				return true
+			}
+			calls = append(calls, bc)
+			return true
+		})
+	}
+	return calls
+}
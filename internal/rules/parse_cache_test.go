@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// parseCacheFixture generates files whose functions have distinct lengths,
+// so position corruption between files shows up as wrong line counts
+func parseCacheFixture(n int) []RepositoryFile {
+	files := make([]RepositoryFile, n)
+	for i := range files {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("package p%d\n\ntype S%d struct {\n\tA, B int\n}\n\n", i%7, i))
+		sb.WriteString(fmt.Sprintf("func (s *S%d) Method() {}\n\nfunc F%d() {\n", i, i))
+		for line := 0; line < i%120; line++ {
+			sb.WriteString("\t_ = 1\n")
+		}
+		sb.WriteString("}\n")
+		files[i] = RepositoryFile{Path: fmt.Sprintf("/repo/p%d/f%d.go", i%7, i), Content: sb.String()}
+	}
+	return files
+}
+
+// TestParseCache_ConcurrentParseMatchesSequential is meant to run under
+// go test -race: several goroutines parse 500 files through one cache
+func TestParseCache_ConcurrentParseMatchesSequential(t *testing.T) {
+	files := parseCacheFixture(500)
+	sequential := make([]*ParsedGoFile, len(files))
+	for i, file := range files {
+		sequential[i] = ParseGoFile(file)
+	}
+
+	cache := NewParseCache()
+	results := make([][]*ParsedGoFile, 4)
+	var wg sync.WaitGroup
+	for g := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[g] = cache.ParseAll(files)
+		}()
+	}
+	wg.Wait()
+
+	for _, concurrent := range results {
+		for i := range files {
+			if !reflect.DeepEqual(concurrent[i], sequential[i]) {
+				t.Fatalf("file %s: concurrent %+v, sequential %+v", files[i].Path, concurrent[i], sequential[i])
+			}
+		}
+	}
+}
+
+func TestSizeRule_FunctionLineCountsMatchSequentialParse(t *testing.T) {
+	files := parseCacheFixture(500)
+	rule := NewSizeRule()
+	rule.MaxFunctionLines = 60
+
+	want := make(map[string]int)
+	for _, file := range files {
+		for _, fn := range ParseGoFile(file).Functions {
+			if fn.Lines() > rule.MaxFunctionLines {
+				want[fmt.Sprintf("%s:%d %s", file.Path, fn.StartLine, fn.Name)] = fn.Lines()
+			}
+		}
+	}
+
+	got := make(map[string]int)
+	for _, v := range rule.Evaluate(AnalysisContext{RepositoryFiles: files}) {
+		var name string
+		var lines int
+		if _, err := fmt.Sscanf(v.Message, "Function %s has %d lines", &name, &lines); err != nil {
+			t.Fatalf("unexpected message %q: %v", v.Message, err)
+		}
+		got[fmt.Sprintf("%s:%d %s", v.File, v.Line, strings.Trim(name, "'"))] = lines
+	}
+	if len(want) == 0 || !reflect.DeepEqual(got, want) {
+		t.Fatalf("line counts differ from the sequential parse:\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestParseCache_ReparsesChangedContent(t *testing.T) {
+	cache := NewParseCache()
+	file := RepositoryFile{Path: "/repo/a.go", Content: "package a\n\nfunc A() {}\n"}
+
+	first := cache.ParseAll([]RepositoryFile{file})[0]
+	if again := cache.ParseAll([]RepositoryFile{file})[0]; again != first {
+		t.Fatal("expected unchanged content to hit the cache")
+	}
+
+	file.Content = "package a\n\nfunc A() {\n}\n"
+	changed := cache.ParseAll([]RepositoryFile{file})[0]
+	if changed == first || changed.Functions[0].Lines() != 2 {
+		t.Fatalf("expected changed content to be reparsed, got %+v", changed)
+	}
+
+	file.Content = "package a\n\nfunc A( {"
+	if malformed := cache.ParseAll([]RepositoryFile{file})[0]; malformed != nil {
+		t.Fatalf("expected nil for a malformed file, got %+v", malformed)
+	}
+}
+
+func TestParseCache_RestoredEntriesAreCheckedAgainstContent(t *testing.T) {
+	files := parseCacheFixture(3)
+	warm := NewParseCache()
+	warm.ParseAll(files)
+
+	cache := NewParseCache()
+	cache.Restore(warm.Snapshot(nil))
+	files[1].Content += "\nfunc Added() {}\n"
+	cache.ParseAll(files)
+
+	if got := cache.Stats(); got.Hits != 2 || got.Misses != 1 || got.Entries != 3 {
+		t.Fatalf("expected the changed file to be parsed again, got %+v", got)
+	}
+}
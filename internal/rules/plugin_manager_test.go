@@ -28,12 +28,12 @@ func (m *MockPlugin) RegisterRules(registry *RuleRegistry) {
 type MockRule struct {
 	id       string
 	category string
-	severity string
+	severity model.Severity
 }
 
-func (m *MockRule) ID() string       { return m.id }
-func (m *MockRule) Category() string { return m.category }
-func (m *MockRule) Severity() string { return m.severity }
+func (m *MockRule) ID() string               { return m.id }
+func (m *MockRule) Category() string         { return m.category }
+func (m *MockRule) Severity() model.Severity { return m.severity }
 func (m *MockRule) Evaluate(ctx AnalysisContext) []model.Violation {
 	return []model.Violation{}
 }
@@ -62,7 +62,7 @@ func TestPluginManager_RegisterPlugin(t *testing.T) {
 	mockRule := &MockRule{
 		id:       "mock.rule",
 		category: "testing",
-		severity: "info",
+		severity: model.SeverityInfo,
 	}
 
 	mockPlugin := &MockPlugin{
@@ -167,7 +167,7 @@ func TestPluginManager_MultiplePlugins(t *testing.T) {
 		version:     "1.0.0",
 		description: "First plugin",
 		rules: []Rule{
-			&MockRule{id: "rule1", category: "testing", severity: "info"},
+			&MockRule{id: "rule1", category: "testing", severity: model.SeverityInfo},
 		},
 	}
 
@@ -176,8 +176,8 @@ func TestPluginManager_MultiplePlugins(t *testing.T) {
 		version:     "2.0.0",
 		description: "Second plugin",
 		rules: []Rule{
-			&MockRule{id: "rule2", category: "testing", severity: "warning"},
-			&MockRule{id: "rule3", category: "testing", severity: "error"},
+			&MockRule{id: "rule2", category: "testing", severity: model.SeverityWarning},
+			&MockRule{id: "rule3", category: "testing", severity: model.SeverityError},
 		},
 	}
 
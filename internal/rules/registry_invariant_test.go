@@ -13,9 +13,9 @@ type registryStubRule struct {
 	category string
 }
 
-func (r *registryStubRule) ID() string       { return r.id }
-func (r *registryStubRule) Category() string { return r.category }
-func (r *registryStubRule) Severity() string { return "info" }
+func (r *registryStubRule) ID() string               { return r.id }
+func (r *registryStubRule) Category() string         { return r.category }
+func (r *registryStubRule) Severity() model.Severity { return model.SeverityInfo }
 func (r *registryStubRule) Evaluate(AnalysisContext) []model.Violation {
 	return nil
 }
@@ -1,6 +1,10 @@
 package rules
 
-import "RepoDoctor/internal/model"
+import (
+	"slices"
+
+	"RepoDoctor/internal/model"
+)
 
 // AnalysisContext provides read-only access to repository data for rules.
 // It encapsulates all information needed for rule evaluation without
@@ -30,6 +34,21 @@ type LanguageAwareRule interface {
 	Capabilities() RuleCapabilities
 }
 
+// DescribedRule is an optional extension for rules that explain themselves
+// to report consumers. Every built-in rule implements it.
+type DescribedRule interface {
+	Rule
+	// Description is one plain sentence saying what the rule reports
+	Description() string
+}
+
+// ThresholdedRule is an optional extension for rules with tunable limits.
+// Thresholds returns the limits in effect, keyed by their config names.
+type ThresholdedRule interface {
+	Rule
+	Thresholds() map[string]float64
+}
+
 // RepositoryFile represents a Go file in the repository
 type RepositoryFile struct {
 	// Path is the file path relative to repository root
@@ -38,6 +57,9 @@ type RepositoryFile struct {
 	Content string
 	// Imports contains the list of import paths
 	Imports []string
+	// BlankImports lists the imports declared only for their side effects
+	// (import _ "pkg"); each is also in Imports
+	BlankImports []string
 }
 
 // RepositoryMetrics contains computed metrics for analysis
@@ -56,6 +78,26 @@ type DependencyGraph struct {
 	Nodes []string
 	// Edges contains dependency relationships
 	Edges map[string][]string
+	// TestEdges contains the dependency relationships only test files
+	// declare. Graph rules follow Edges alone unless test edges are merged
+	// in.
+	TestEdges map[string][]string
+}
+
+// WithTestEdges returns the graph with its test edges merged into Edges
+func (g DependencyGraph) WithTestEdges() DependencyGraph {
+	edges := make(map[string][]string, len(g.Edges))
+	for node, deps := range g.Edges {
+		edges[node] = append([]string(nil), deps...)
+	}
+	for node, deps := range g.TestEdges {
+		for _, dep := range deps {
+			if !slices.Contains(edges[node], dep) {
+				edges[node] = append(edges[node], dep)
+			}
+		}
+	}
+	return DependencyGraph{Nodes: g.Nodes, Edges: edges}
 }
 
 // Configuration contains rule-specific configuration
@@ -74,8 +116,8 @@ type Rule interface {
 	Category() string
 
 	// Severity returns the severity level of the rule.
-	// Expected values: "info", "warning", "error", "critical".
-	Severity() string
+	// Rules report the highest severity their violations can have.
+	Severity() model.Severity
 
 	// Evaluate executes the rule logic against the provided context.
 	// It must handle missing data safely and never panic.
@@ -98,8 +140,8 @@ func (r *ExampleRule) Category() string {
 }
 
 // Severity returns the severity level for this rule
-func (r *ExampleRule) Severity() string {
-	return "info"
+func (r *ExampleRule) Severity() model.Severity {
+	return model.SeverityInfo
 }
 
 // Evaluate executes the rule logic (placeholder implementation)
@@ -0,0 +1,72 @@
+package rules
+
+import (
+	"RepoDoctor/internal/model"
+)
+
+// SingleImplInterfaceRule flags interfaces implemented by exactly one type
+// of the module. Such interfaces are often premature abstractions and are
+// reported as candidates for removal.
+type SingleImplInterfaceRule struct{}
+
+// NewSingleImplInterfaceRule creates a single-implementation interface rule
+func NewSingleImplInterfaceRule() *SingleImplInterfaceRule {
+	return &SingleImplInterfaceRule{}
+}
+
+// ID returns the unique identifier for this rule
+func (r *SingleImplInterfaceRule) ID() string {
+	return "rule.single-impl-interface"
+}
+
+// Category returns the category for this rule
+func (r *SingleImplInterfaceRule) Category() string {
+	return string(CategoryMaintainability)
+}
+
+// Severity returns the severity level for this rule
+func (r *SingleImplInterfaceRule) Severity() model.Severity {
+	return model.SeverityInfo
+}
+
+// Description explains what this rule reports
+func (r *SingleImplInterfaceRule) Description() string {
+	return "Reports interfaces that have exactly one implementation"
+}
+
+func (r *SingleImplInterfaceRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
+}
+
+// Coverage reports the Go files scanned for interfaces and their implementations
+func (r *SingleImplInterfaceRule) Coverage(context AnalysisContext) RuleCoverage {
+	return goFileCoverage(r.ID(), context.RepositoryFiles, 0, true)
+}
+
+// Evaluate reports every interface with exactly one implementation. Files
+// disabling the rule by directive still provide implementations; only their
+// own interfaces are not reported.
+func (r *SingleImplInterfaceRule) Evaluate(context AnalysisContext) []model.Violation {
+	var violations []model.Violation
+
+	disabled := make(map[string]bool)
+	for _, file := range context.RepositoryFiles {
+		disabled[file.Path] = fileDisablesRule(file.Content, r.ID())
+	}
+
+	for _, found := range FindInterfaceImplementations(context.RepositoryFiles) {
+		if len(found.Implementations) != 1 || disabled[found.File] {
+			continue
+		}
+		violations = append(violations, model.Violation{
+			RuleID:      r.ID(),
+			Severity:    model.SeverityInfo,
+			Message:     "Interface " + found.Interface + " has a single implementation: " + found.Implementations[0],
+			File:        found.File,
+			Line:        0,
+			ScoreImpact: 0,
+		})
+	}
+
+	return violations
+}
@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+const singleImplFixture = `package store
+
+import "io"
+
+type Loader interface {
+	Load(key string) ([]byte, error)
+}
+
+type Saver interface {
+	Save(key string, data []byte) error
+}
+
+type ReadCloser interface {
+	io.Reader
+	Close() error
+}
+
+type diskStore struct{ root string }
+
+func (d *diskStore) Load(key string) ([]byte, error) { return nil, nil }
+func (d *diskStore) Save(key string, data []byte) error { return nil }
+
+type base struct{}
+
+func (base) Save(key string, data []byte) error { return nil }
+
+type memStore struct {
+	base
+	data map[string][]byte
+}
+`
+
+func TestFindInterfaceImplementations_EmbeddingAndIncompleteSets(t *testing.T) {
+	files := []RepositoryFile{{Path: "/repo/store/store.go", Content: singleImplFixture}}
+
+	got := FindInterfaceImplementations(files)
+	want := []InterfaceImplementations{
+		{Interface: "store.Loader", File: "/repo/store/store.go", Implementations: []string{"store.diskStore"}},
+		{Interface: "store.Saver", File: "/repo/store/store.go", Implementations: []string{"store.base", "store.diskStore", "store.memStore"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected implementations:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestSingleImplInterfaceRule_FlagsOnlySingleImplementations(t *testing.T) {
+	rule := NewSingleImplInterfaceRule()
+	files := []RepositoryFile{{Path: "/repo/store/store.go", Content: singleImplFixture}}
+
+	violations := rule.Evaluate(AnalysisContext{RepositoryFiles: files})
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %d: %+v", len(violations), violations)
+	}
+	if got, want := violations[0].Message, "Interface store.Loader has a single implementation: store.diskStore"; got != want {
+		t.Fatalf("unexpected message: %q", got)
+	}
+	if violations[0].ScoreImpact != 0 {
+		t.Fatalf("expected no score impact, got %v", violations[0].ScoreImpact)
+	}
+
+	// A directive in the interface's file silences it
+	files[0].Content = "//repodoctor:disable single-impl-interface\n" + singleImplFixture
+	if violations := rule.Evaluate(AnalysisContext{RepositoryFiles: files}); len(violations) != 0 {
+		t.Fatalf("expected directive to disable the rule, got %+v", violations)
+	}
+}
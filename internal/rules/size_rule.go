@@ -1,9 +1,6 @@
 package rules
 
 import (
-	"go/ast"
-	"go/parser"
-	"go/token"
 	"strconv"
 	"strings"
 
@@ -14,7 +11,7 @@ import (
 type SizeRule struct {
 	MaxFileLines     int
 	MaxFunctionLines int
-	fset             *token.FileSet
+	cache            *ParseCache
 }
 
 // NewSizeRule creates a new size rule checker with default thresholds
@@ -22,7 +19,7 @@ func NewSizeRule() *SizeRule {
 	return &SizeRule{
 		MaxFileLines:     500,
 		MaxFunctionLines: 80,
-		fset:             token.NewFileSet(),
+		cache:            sharedParseCache,
 	}
 }
 
@@ -37,46 +34,74 @@ func (r *SizeRule) Category() string {
 }
 
 // Severity returns the severity level for this rule
-func (r *SizeRule) Severity() string {
-	return string(model.SeverityWarning)
+func (r *SizeRule) Severity() model.Severity {
+	return model.SeverityWarning
+}
+
+// Description explains what this rule reports
+func (r *SizeRule) Description() string {
+	return "Reports files and functions longer than the configured line limits"
+}
+
+// Thresholds returns the limits this rule checks against
+func (r *SizeRule) Thresholds() map[string]float64 {
+	return map[string]float64{"max_file_lines": float64(r.MaxFileLines), "max_function_lines": float64(r.MaxFunctionLines)}
 }
 
 func (r *SizeRule) Capabilities() RuleCapabilities {
 	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
 }
 
+// Coverage reports the Go files whose functions could be measured. Files
+// that fail to parse only get the file-level line check and count as
+// skipped, as do files disabling the rule by directive.
+func (r *SizeRule) Coverage(context AnalysisContext) RuleCoverage {
+	return goFileCoverage(r.ID(), context.RepositoryFiles, 0, true)
+}
+
 // Evaluate executes the rule logic against the provided context
 func (r *SizeRule) Evaluate(context AnalysisContext) []model.Violation {
 	var violations []model.Violation
 
-	for _, file := range context.RepositoryFiles {
-		r.checkFile(file, &violations)
+	files := filesWithRuleEnabled(context.RepositoryFiles, r.ID())
+	for i, parsed := range r.cache.ParseAll(files) {
+		r.checkFile(files[i], parsed, &violations)
 	}
 
 	return violations
 }
 
 // checkFile checks a single file for size violations
-func (r *SizeRule) checkFile(file RepositoryFile, violations *[]model.Violation) {
+func (r *SizeRule) checkFile(file RepositoryFile, parsed *ParsedGoFile, violations *[]model.Violation) {
 	// Check file LOC
-	fileLines := r.countNonEmptyLines(file.Content)
+	fileLines := countNonEmptyLines(file.Content)
 	if fileLines > r.MaxFileLines {
 		*violations = append(*violations, model.Violation{
 			RuleID:      r.ID(),
 			Severity:    model.SeverityWarning,
 			Message:     "File " + file.Path + " has " + strconv.Itoa(fileLines) + " lines (threshold: " + strconv.Itoa(r.MaxFileLines) + ")",
 			File:        file.Path,
-			Line:        0,
+			Line:        1,
+			EndLine:     countLines(file.Content),
 			ScoreImpact: -3.0,
 		})
 	}
 
 	// Check function LOC
-	r.checkFunctions(file, violations)
+	r.checkFunctions(file, parsed, violations)
+}
+
+// countLines returns the number of lines in a file, the last line of a
+// file-level violation
+func countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
 }
 
 // countNonEmptyLines counts non-empty lines in a file
-func (r *SizeRule) countNonEmptyLines(content string) int {
+func countNonEmptyLines(content string) int {
 	lines := strings.Split(content, "\n")
 	count := 0
 	for _, line := range lines {
@@ -88,37 +113,23 @@ func (r *SizeRule) countNonEmptyLines(content string) int {
 	return count
 }
 
-// checkFunctions checks function sizes in a file
-func (r *SizeRule) checkFunctions(file RepositoryFile, violations *[]model.Violation) {
-	// Parse AST
-	node, err := parser.ParseFile(r.fset, file.Path, file.Content, 0)
-	if err != nil {
+// checkFunctions checks function sizes in a parsed file
+func (r *SizeRule) checkFunctions(file RepositoryFile, parsed *ParsedGoFile, violations *[]model.Violation) {
+	if parsed == nil {
 		return // Skip malformed files
 	}
 
-	// Walk through all declarations
-	ast.Inspect(node, func(n ast.Node) bool {
-		funcDecl, ok := n.(*ast.FuncDecl)
-		if !ok {
-			return true
-		}
-
-		// Calculate function lines
-		startLine := r.fset.Position(funcDecl.Pos()).Line
-		endLine := r.fset.Position(funcDecl.End()).Line
-		funcLines := endLine - startLine + 1
-
-		if funcLines > r.MaxFunctionLines {
+	for _, fn := range parsed.Functions {
+		if funcLines := fn.Lines(); funcLines > r.MaxFunctionLines {
 			*violations = append(*violations, model.Violation{
 				RuleID:      r.ID(),
 				Severity:    model.SeverityWarning,
-				Message:     "Function '" + funcDecl.Name.Name + "' has " + strconv.Itoa(funcLines) + " lines (threshold: " + strconv.Itoa(r.MaxFunctionLines) + ")",
+				Message:     "Function '" + fn.Name + "' has " + strconv.Itoa(funcLines) + " lines (threshold: " + strconv.Itoa(r.MaxFunctionLines) + ")",
 				File:        file.Path,
-				Line:        startLine,
+				Line:        fn.StartLine,
+				EndLine:     fn.EndLine,
 				ScoreImpact: -3.0,
 			})
 		}
-
-		return true
-	})
+	}
 }
@@ -0,0 +1,304 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// StructCohesion describes how the methods of one struct share its fields.
+// Methods are connected when they access a common receiver field or call
+// each other through the receiver; each connected group is a cluster
+// (LCOM4). Cohesion is 1 / len(Clusters), so a fully connected struct
+// scores 1.0 and a struct split into two disjoint halves scores 0.5.
+type StructCohesion struct {
+	Struct   string            `json:"struct"`
+	File     string            `json:"file"`
+	Methods  int               `json:"methods"`
+	Cohesion float64           `json:"cohesion"`
+	Clusters []CohesionCluster `json:"clusters"`
+}
+
+// CohesionCluster is one group of connected methods and the fields they use
+type CohesionCluster struct {
+	Methods []string `json:"methods"`
+	Fields  []string `json:"fields"`
+}
+
+// StructCohesionRule flags structs whose methods fall into disjoint
+// clusters. Such structs may stay under the god object thresholds while
+// still bundling unrelated responsibilities.
+type StructCohesionRule struct {
+	// MinCohesion is the lowest accepted cohesion; 0 disables flagging
+	MinCohesion float64
+}
+
+// NewStructCohesionRule creates a struct cohesion rule
+func NewStructCohesionRule(minCohesion float64) *StructCohesionRule {
+	return &StructCohesionRule{MinCohesion: minCohesion}
+}
+
+// ID returns the unique identifier for this rule
+func (r *StructCohesionRule) ID() string {
+	return "rule.struct-cohesion"
+}
+
+// Category returns the category for this rule
+func (r *StructCohesionRule) Category() string {
+	return string(CategoryMaintainability)
+}
+
+// Severity returns the severity level for this rule
+func (r *StructCohesionRule) Severity() model.Severity {
+	return model.SeverityInfo
+}
+
+// Description explains what this rule reports
+func (r *StructCohesionRule) Description() string {
+	return "Reports structs whose methods fall into disjoint clusters"
+}
+
+// Thresholds returns the limits this rule checks against
+func (r *StructCohesionRule) Thresholds() map[string]float64 {
+	return map[string]float64{"min_cohesion": r.MinCohesion}
+}
+
+func (r *StructCohesionRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go"}, SupportsMultipleLanguages: false}
+}
+
+// Coverage reports the non-test Go files scanned for structs and methods
+func (r *StructCohesionRule) Coverage(context AnalysisContext) RuleCoverage {
+	return goFileCoverage(r.ID(), context.RepositoryFiles, 0, false)
+}
+
+// Evaluate reports every struct whose cohesion is below MinCohesion
+func (r *StructCohesionRule) Evaluate(context AnalysisContext) []model.Violation {
+	var violations []model.Violation
+	if r.MinCohesion <= 0 {
+		return violations
+	}
+
+	for _, sc := range AnalyzeStructCohesion(filesWithRuleEnabled(context.RepositoryFiles, r.ID())) {
+		if sc.Cohesion >= r.MinCohesion {
+			continue
+		}
+		violations = append(violations, model.Violation{
+			RuleID:   r.ID(),
+			Severity: model.SeverityInfo,
+			Message: fmt.Sprintf("%s has cohesion %.2f (min: %.2f) with %d disjoint clusters: %s",
+				sc.Struct, sc.Cohesion, r.MinCohesion, len(sc.Clusters), formatCohesionClusters(sc.Clusters)),
+			File:        sc.File,
+			Line:        0,
+			ScoreImpact: 0,
+		})
+	}
+	return violations
+}
+
+// formatCohesionClusters renders clusters as "[m1, m2 | f1, f2]; [m3 | f3]"
+func formatCohesionClusters(clusters []CohesionCluster) string {
+	parts := make([]string, len(clusters))
+	for i, c := range clusters {
+		parts[i] = "[" + strings.Join(c.Methods, ", ") + " | " + strings.Join(c.Fields, ", ") + "]"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// cohesionStruct indexes one struct's fields and its methods' receiver usage
+type cohesionStruct struct {
+	name    string
+	file    string
+	fields  map[string]bool
+	methods map[string]map[string]bool // method -> receiver selectors used
+}
+
+// AnalyzeStructCohesion computes the cohesion of every struct in the non-test
+// Go files that has at least two methods touching its fields. Methods that
+// neither access a field nor call a sibling (constants, ID accessors) are
+// left out. Results are sorted by file and struct name.
+func AnalyzeStructCohesion(files []RepositoryFile) []StructCohesion {
+	fset := token.NewFileSet()
+	index := make(map[string]*cohesionStruct)
+	var parsed []*ast.File
+	var paths []string
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, ".go") || strings.HasSuffix(file.Path, "_test.go") {
+			continue
+		}
+		node, err := parser.ParseFile(fset, file.Path, file.Content, 0)
+		if err != nil {
+			continue
+		}
+		indexStructFields(node, file.Path, index)
+		parsed, paths = append(parsed, node), append(paths, file.Path)
+	}
+	for i, node := range parsed {
+		indexMethodSelectors(node, paths[i], index)
+	}
+
+	var results []StructCohesion
+	for _, st := range index {
+		clusters, methods := cohesionClusters(st)
+		if methods < 2 {
+			continue
+		}
+		results = append(results, StructCohesion{
+			Struct:   st.name,
+			File:     st.file,
+			Methods:  methods,
+			Cohesion: 1 / float64(len(clusters)),
+			Clusters: clusters,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].File != results[j].File {
+			return results[i].File < results[j].File
+		}
+		return results[i].Struct < results[j].Struct
+	})
+	return results
+}
+
+// indexStructFields records the field names of every struct type in a file.
+// Embedded fields are named after their type.
+func indexStructFields(node *ast.File, path string, index map[string]*cohesionStruct) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+
+		st := &cohesionStruct{name: typeSpec.Name.Name, file: path, fields: make(map[string]bool), methods: make(map[string]map[string]bool)}
+		for _, field := range structType.Fields.List {
+			for _, name := range field.Names {
+				st.fields[name.Name] = true
+			}
+			if len(field.Names) == 0 {
+				if name := receiverTypeName(field.Type); name != "" {
+					st.fields[name] = true
+				}
+			}
+		}
+		index[structKey(path, st.name)] = st
+		return true
+	})
+}
+
+// indexMethodSelectors records, for every method of an indexed struct, the
+// names selected on its receiver (fields and sibling methods alike)
+func indexMethodSelectors(node *ast.File, path string, index map[string]*cohesionStruct) {
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 || fn.Body == nil {
+			continue
+		}
+		recv := fn.Recv.List[0]
+		st := index[structKey(path, receiverTypeName(recv.Type))]
+		if st == nil {
+			continue
+		}
+
+		selectors := make(map[string]bool)
+		st.methods[fn.Name.Name] = selectors
+		if len(recv.Names) == 0 || recv.Names[0].Name == "_" {
+			continue
+		}
+		recvName := recv.Names[0].Name
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == recvName {
+					selectors[sel.Sel.Name] = true
+				}
+			}
+			return true
+		})
+	}
+}
+
+// receiverTypeName returns the base type name of a receiver or embedded
+// field type, unwrapping pointers, generics and package qualifiers
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// cohesionClusters groups a struct's methods into connected clusters and
+// returns them with the number of methods taking part
+func cohesionClusters(st *cohesionStruct) ([]CohesionCluster, int) {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(x string) string {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	union := func(a, b string) {
+		if _, ok := parent[a]; !ok {
+			parent[a] = a
+		}
+		if _, ok := parent[b]; !ok {
+			parent[b] = b
+		}
+		parent[find(a)] = find(b)
+	}
+
+	// Methods are prefixed "m:" and fields "f:" so a field and a method
+	// sharing a name stay distinct nodes
+	for method, selectors := range st.methods {
+		for name := range selectors {
+			if _, isMethod := st.methods[name]; isMethod && name != method {
+				union("m:"+method, "m:"+name)
+			} else if st.fields[name] {
+				union("m:"+method, "f:"+name)
+			}
+		}
+	}
+
+	groups := make(map[string]*CohesionCluster)
+	methods := 0
+	for node := range parent {
+		root := find(node)
+		if groups[root] == nil {
+			groups[root] = &CohesionCluster{Methods: []string{}, Fields: []string{}}
+		}
+		if name, ok := strings.CutPrefix(node, "m:"); ok {
+			groups[root].Methods = append(groups[root].Methods, name)
+			methods++
+		} else {
+			groups[root].Fields = append(groups[root].Fields, strings.TrimPrefix(node, "f:"))
+		}
+	}
+
+	clusters := make([]CohesionCluster, 0, len(groups))
+	for _, c := range groups {
+		sort.Strings(c.Methods)
+		sort.Strings(c.Fields)
+		clusters = append(clusters, *c)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Methods[0] < clusters[j].Methods[0] })
+	return clusters, methods
+}
@@ -0,0 +1,84 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+const cohesionFixture = `package store
+
+type Store struct {
+	path  string
+	data  []byte
+	tmpl  string
+	cache map[string]string
+}
+
+func (s *Store) Load() { s.data = readFile(s.path) }
+func (s *Store) Save() { writeFile(s.path, s.data) }
+func (s *Store) Render() string { return s.tmpl + s.lookup() }
+func (s *Store) lookup() string { return s.cache[s.tmpl] }
+func (s *Store) ID() string { return "store" }
+
+type Counter struct {
+	hits  int
+	limit int
+}
+
+func (c *Counter) Inc() { c.hits++ }
+func (c *Counter) Full() bool { return c.hits >= c.limit }
+func (c Counter) Reset() Counter { c.hits = 0; return c }
+
+func readFile(string) []byte { return nil }
+func writeFile(string, []byte) {}
+`
+
+func TestAnalyzeStructCohesion_TwoClustersAndCohesive(t *testing.T) {
+	files := []RepositoryFile{{Path: "/repo/store/store.go", Content: cohesionFixture}}
+
+	got := AnalyzeStructCohesion(files)
+	want := []StructCohesion{
+		{
+			Struct:   "Counter",
+			File:     "/repo/store/store.go",
+			Methods:  3,
+			Cohesion: 1,
+			Clusters: []CohesionCluster{{Methods: []string{"Full", "Inc", "Reset"}, Fields: []string{"hits", "limit"}}},
+		},
+		{
+			Struct:   "Store",
+			File:     "/repo/store/store.go",
+			Methods:  4,
+			Cohesion: 0.5,
+			Clusters: []CohesionCluster{
+				{Methods: []string{"Load", "Save"}, Fields: []string{"data", "path"}},
+				{Methods: []string{"Render", "lookup"}, Fields: []string{"cache", "tmpl"}},
+			},
+		},
+	}
+
+	// Repeat to catch map-order dependent output
+	for i := 0; i < 10; i++ {
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected cohesion:\n got %+v\nwant %+v", got, want)
+		}
+		got = AnalyzeStructCohesion(files)
+	}
+}
+
+func TestStructCohesionRule_FlagsBelowMinimum(t *testing.T) {
+	context := AnalysisContext{RepositoryFiles: []RepositoryFile{{Path: "/repo/store/store.go", Content: cohesionFixture}}}
+
+	if violations := NewStructCohesionRule(0).Evaluate(context); len(violations) != 0 {
+		t.Fatalf("expected no violations with flagging disabled, got %v", violations)
+	}
+
+	violations := NewStructCohesionRule(0.6).Evaluate(context)
+	if len(violations) != 1 {
+		t.Fatalf("expected only Store to be flagged, got %v", violations)
+	}
+	want := "Store has cohesion 0.50 (min: 0.60) with 2 disjoint clusters: [Load, Save | data, path]; [Render, lookup | cache, tmpl]"
+	if violations[0].Message != want || violations[0].ScoreImpact != 0 {
+		t.Fatalf("unexpected violation: %+v", violations[0])
+	}
+}
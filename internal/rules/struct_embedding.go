@@ -0,0 +1,166 @@
+package rules
+
+import (
+	"go/ast"
+	"path/filepath"
+)
+
+// InterfaceMethods is an interface type declaration, the number of methods
+// it declares and the interfaces of the same package it embeds
+type InterfaceMethods struct {
+	Name     string
+	Methods  int
+	Embedded []string `json:",omitempty"`
+}
+
+// NewStructFields describes a struct declaration. Fields embedded by the
+// name of a type of the same package, T or *T, are listed in Embedded
+// instead of being counted, so PackageTypes can resolve them; every other
+// field counts once.
+func NewStructFields(name string, structType *ast.StructType, line int) StructFields {
+	st := StructFields{Name: name, Line: line}
+	if structType.Fields == nil {
+		return st
+	}
+	for _, field := range structType.Fields.List {
+		if embedded, ok := localEmbeddedName(field); ok {
+			st.Embedded = append(st.Embedded, embedded)
+			continue
+		}
+		st.Fields += max(len(field.Names), 1)
+	}
+	return st
+}
+
+// NewInterfaceMethods describes an interface declaration. Embedded
+// interfaces of the same package are listed in Embedded; other embedded
+// elements, such as io.Reader or type constraints, are not counted.
+func NewInterfaceMethods(name string, iface *ast.InterfaceType) InterfaceMethods {
+	im := InterfaceMethods{Name: name}
+	if iface.Methods == nil {
+		return im
+	}
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			im.Methods += len(field.Names)
+		} else if embedded, ok := localEmbeddedName(field); ok {
+			im.Embedded = append(im.Embedded, embedded)
+		}
+	}
+	return im
+}
+
+// localEmbeddedName returns the type name of an embedded field whose type is
+// declared in the same package: T or *T
+func localEmbeddedName(field *ast.Field) (string, bool) {
+	if len(field.Names) > 0 {
+		return "", false
+	}
+	fieldType := field.Type
+	if star, ok := fieldType.(*ast.StarExpr); ok {
+		fieldType = star.X
+	}
+	ident, ok := fieldType.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	return ident.Name, true
+}
+
+// PackageTypes holds the struct and interface declarations of one package,
+// so the god object rules can count what a struct gets through embedding
+type PackageTypes struct {
+	structs    map[string]StructFields
+	interfaces map[string]InterfaceMethods
+}
+
+// NewPackageTypes creates an empty set of declarations
+func NewPackageTypes() *PackageTypes {
+	return &PackageTypes{structs: make(map[string]StructFields), interfaces: make(map[string]InterfaceMethods)}
+}
+
+// PackageTypesOf returns the declarations of the package (directory) of
+// filePath, creating them on first use
+func PackageTypesOf(packages map[string]*PackageTypes, filePath string) *PackageTypes {
+	dir := filepath.Dir(filePath)
+	types, ok := packages[dir]
+	if !ok {
+		types = NewPackageTypes()
+		packages[dir] = types
+	}
+	return types
+}
+
+// AddFile records the struct and interface declarations of a parsed file
+func (p *PackageTypes) AddFile(parsed *ParsedGoFile) {
+	for _, st := range parsed.Structs {
+		p.AddStruct(st)
+	}
+	for _, im := range parsed.Interfaces {
+		p.AddInterface(im)
+	}
+}
+
+// AddStruct records a struct declaration
+func (p *PackageTypes) AddStruct(st StructFields) {
+	p.structs[st.Name] = st
+}
+
+// AddInterface records an interface declaration
+func (p *PackageTypes) AddInterface(im InterfaceMethods) {
+	p.interfaces[im.Name] = im
+}
+
+// FieldCount returns the fields of a struct with each embedded struct of the
+// package counted as its own fields, recursively. Any other embedded type,
+// including an interface, counts as one field.
+func (p *PackageTypes) FieldCount(name string) int {
+	return p.fieldCount(name, map[string]bool{})
+}
+
+func (p *PackageTypes) fieldCount(name string, seen map[string]bool) int {
+	st, ok := p.structs[name]
+	if !ok || seen[name] {
+		return 1
+	}
+	seen[name] = true
+	defer delete(seen, name)
+
+	count := st.Fields
+	for _, embedded := range st.Embedded {
+		if _, isStruct := p.structs[embedded]; isStruct {
+			count += p.fieldCount(embedded, seen)
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
+// EmbeddedMethodCount returns the methods a struct gets from the interfaces
+// of the package it embeds, directly or through embedded structs
+func (p *PackageTypes) EmbeddedMethodCount(name string) int {
+	return p.embeddedMethodCount(name, map[string]bool{})
+}
+
+func (p *PackageTypes) embeddedMethodCount(name string, seen map[string]bool) int {
+	if seen[name] {
+		return 0
+	}
+	seen[name] = true
+	defer delete(seen, name)
+
+	if im, ok := p.interfaces[name]; ok {
+		count := im.Methods
+		for _, embedded := range im.Embedded {
+			count += p.embeddedMethodCount(embedded, seen)
+		}
+		return count
+	}
+
+	count := 0
+	for _, embedded := range p.structs[name].Embedded {
+		count += p.embeddedMethodCount(embedded, seen)
+	}
+	return count
+}
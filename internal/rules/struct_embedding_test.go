@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGodObjectRule_CountsEmbeddedFieldsAndInterfaceMethods(t *testing.T) {
+	var base, facade strings.Builder
+	base.WriteString("package store\n\ntype Base struct {\n")
+	for i := 0; i < 12; i++ {
+		base.WriteString("\tBase" + string(rune('A'+i)) + " int\n")
+	}
+	base.WriteString("}\n\ntype Reader interface {\n\tGet()\n\tList()\n}\n\ntype ReadWriter interface {\n\tReader\n\tPut()\n\tDelete()\n}\n")
+	facade.WriteString("package store\n\ntype Facade struct {\n\tReadWriter\n\tio.Closer\n}\n\n")
+	for i := 0; i < 7; i++ {
+		facade.WriteString("func (f *Facade) Method" + string(rune('A'+i)) + "() {}\n")
+	}
+
+	context := AnalysisContext{RepositoryFiles: []RepositoryFile{
+		{Path: "/repo/store/base.go", Content: base.String()},
+		{Path: "/repo/store/wide.go", Content: "package store\n\ntype Wide struct {\n\tBase\n\tA, B, C, D, E int\n}\n"},
+		{Path: "/repo/store/facade.go", Content: facade.String()},
+		{Path: "/repo/other/wide.go", Content: "package other\n\ntype Narrow struct {\n\tBase\n\tA, B, C, D, E int\n}\n"},
+	}}
+
+	var messages []string
+	for _, v := range NewGodObjectRule().Evaluate(context) {
+		messages = append(messages, v.Message)
+	}
+	got := strings.Join(messages, "\n")
+	for _, want := range []string{"Wide has 17 fields", "Facade has 11 methods"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q, got:\n%s", want, got)
+		}
+	}
+	if len(messages) != 2 {
+		t.Errorf("expected only Wide and Facade to be flagged; Base of another package must not count, got:\n%s", got)
+	}
+}
+
+func TestPackageTypes_SelfEmbeddingTerminates(t *testing.T) {
+	types := NewPackageTypes()
+	types.AddStruct(StructFields{Name: "Node", Fields: 2, Embedded: []string{"Node"}})
+	types.AddInterface(InterfaceMethods{Name: "Loop", Methods: 1, Embedded: []string{"Loop"}})
+	types.AddStruct(StructFields{Name: "Holder", Embedded: []string{"Loop"}})
+
+	if got := types.FieldCount("Node"); got != 3 {
+		t.Errorf("expected a self-embedding pointer to count as one field, got %d", got)
+	}
+	if got := types.EmbeddedMethodCount("Holder"); got != 1 {
+		t.Errorf("expected 1 interface method, got %d", got)
+	}
+}
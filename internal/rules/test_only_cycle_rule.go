@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"slices"
+
+	"RepoDoctor/internal/model"
+)
+
+// TestOnlyCycleRule reports the cycles that only close through an edge a
+// test file declares. Such cycles do not exist in the production build, so
+// they are informational and do not affect the score.
+type TestOnlyCycleRule struct{}
+
+// NewTestOnlyCycleRule creates a new test-only cycle rule
+func NewTestOnlyCycleRule() *TestOnlyCycleRule {
+	return &TestOnlyCycleRule{}
+}
+
+// ID returns the unique identifier for this rule
+func (r *TestOnlyCycleRule) ID() string {
+	return "rule.test-only-cycle"
+}
+
+// Category returns the category for this rule
+func (r *TestOnlyCycleRule) Category() string {
+	return string(CategoryArchitecture)
+}
+
+// Severity returns the severity level for this rule
+func (r *TestOnlyCycleRule) Severity() model.Severity {
+	return model.SeverityInfo
+}
+
+// Description explains what this rule reports
+func (r *TestOnlyCycleRule) Description() string {
+	return "Reports cycles that only close through an import declared by a test file"
+}
+
+func (r *TestOnlyCycleRule) Capabilities() RuleCapabilities {
+	return RuleCapabilities{SupportedLanguages: []string{"Go", "Python", "JavaScript", "TypeScript"}, SupportsMultipleLanguages: true}
+}
+
+// Evaluate detects cycles in the graph with test edges merged in and keeps
+// those that follow at least one test edge. When test edges were already
+// merged into the production edges the circular dependency rule reports
+// these cycles and this rule finds none.
+func (r *TestOnlyCycleRule) Evaluate(context AnalysisContext) []model.Violation {
+	production := context.DependencyGraph
+	if len(production.TestEdges) == 0 {
+		return nil
+	}
+
+	merged := production.WithTestEdges()
+	isProduction := func(from, to string) bool {
+		return slices.Contains(production.Edges[from], to)
+	}
+	cycles := model.WellFormedCycles((&CircularDependencyRule{}).detectCycles(merged), func(from, to string) bool {
+		return slices.Contains(merged.Edges[from], to)
+	})
+
+	var violations []model.Violation
+	for _, cycle := range cycles {
+		if len(cycle) == 0 || !followsTestEdge(cycle, isProduction) {
+			continue
+		}
+		violations = append(violations, model.Violation{
+			RuleID:   r.ID(),
+			Severity: model.SeverityInfo,
+			Message:  "Cycle through test imports: " + formatCycle(cycle),
+			File:     cycle[0],
+		})
+	}
+	return violations
+}
+
+// followsTestEdge reports whether a closed walk uses an edge that is not a
+// production edge
+func followsTestEdge(cycle []string, isProduction func(from, to string) bool) bool {
+	for i, node := range cycle {
+		if !isProduction(node, cycle[(i+1)%len(cycle)]) {
+			return true
+		}
+	}
+	return false
+}
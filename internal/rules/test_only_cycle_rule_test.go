@@ -0,0 +1,36 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTestOnlyCycleRule_ReportsCyclesClosedByTestEdges(t *testing.T) {
+	graph := DependencyGraph{
+		Nodes:     []string{"a.go", "b.go", "c.go", "d.go"},
+		Edges:     map[string][]string{"a.go": {"b.go"}, "c.go": {"d.go"}, "d.go": {"c.go"}},
+		TestEdges: map[string][]string{"b.go": {"a.go"}},
+	}
+	context := AnalysisContext{DependencyGraph: graph}
+
+	cycles := NewCircularDependencyRule(graph).Evaluate(context)
+	if len(cycles) != 1 || !strings.Contains(cycles[0].Message, "c.go") {
+		t.Fatalf("expected the circular rule to see only the production cycle, got %+v", cycles)
+	}
+
+	violations := NewTestOnlyCycleRule().Evaluate(context)
+	if len(violations) != 1 || !strings.Contains(violations[0].Message, "a.go → b.go") {
+		t.Fatalf("expected one test-only cycle, got %+v", violations)
+	}
+	if violations[0].ScoreImpact != 0 {
+		t.Fatalf("expected an informational violation, got score impact %.1f", violations[0].ScoreImpact)
+	}
+
+	merged := AnalysisContext{DependencyGraph: graph.WithTestEdges()}
+	if got := NewCircularDependencyRule(merged.DependencyGraph).Evaluate(merged); len(got) != 2 {
+		t.Fatalf("expected both cycles with test edges merged, got %+v", got)
+	}
+	if got := NewTestOnlyCycleRule().Evaluate(merged); len(got) != 0 {
+		t.Fatalf("expected no test-only cycles once merged, got %+v", got)
+	}
+}
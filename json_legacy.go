@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// FormatJSONLegacy names the legacy json format explicitly, so it stays
+// available while output.default_json switches plain json to json-v1. It is
+// deprecated like the format it names and is kept for one release cycle.
+const FormatJSONLegacy OutputFormat = "json-legacy"
+
+// Values of output.default_json
+const (
+	defaultJSONLegacy = "legacy"
+	defaultJSONV1     = "v1"
+)
+
+// OutputConfig holds report output settings
+type OutputConfig struct {
+	// DefaultJSON is what -format json prints: legacy (default) or v1. It
+	// lets a repository move to json-v1 before plain json changes meaning.
+	DefaultJSON string `yaml:"default_json,omitempty"`
+	// MermaidMaxNodes caps the packages -format mermaid draws
+	MermaidMaxNodes int `yaml:"mermaid_max_nodes,omitempty"`
+}
+
+func validateOutputConfig(output *OutputConfig) error {
+	if output == nil {
+		return nil
+	}
+	if output.MermaidMaxNodes < 0 {
+		return fmt.Errorf("output.mermaid_max_nodes must be positive, got: %d", output.MermaidMaxNodes)
+	}
+	switch output.DefaultJSON {
+	case "", defaultJSONLegacy, defaultJSONV1:
+		return nil
+	}
+	return fmt.Errorf("output.default_json must be %s or %s, got: %s", defaultJSONV1, defaultJSONLegacy, output.DefaultJSON)
+}
+
+// resolveJSONFormat maps plain json onto the writer output.default_json
+// selects; every other format is returned unchanged
+func resolveJSONFormat(format OutputFormat, cfg *Config) OutputFormat {
+	if format != FormatJSON {
+		return format
+	}
+	if cfg != nil && cfg.Output != nil && cfg.Output.DefaultJSON == defaultJSONV1 {
+		return FormatJSONV1
+	}
+	return FormatJSONLegacy
+}
+
+// legacyJSONNotice tells users of the legacy json format how to migrate
+const legacyJSONNotice = "Warning: the legacy json format is deprecated. Use -format json-v1, or set output.default_json: v1 to make -format json print it; -format json-legacy keeps the legacy format for one more release.\n"
+
+// writeLegacyJSONNotice writes the deprecation notice to w when format
+// resolves to the legacy json writer, unless quiet is set. The notice never
+// goes to stdout, which holds the report.
+func writeLegacyJSONNotice(w io.Writer, format OutputFormat, quiet bool) {
+	if format == FormatJSONLegacy && !quiet {
+		fmt.Fprint(w, ColorWarn(legacyJSONNotice))
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func TestJSONLegacy_AliasPrintsDeprecatedLegacyReport(t *testing.T) {
+	report := &StructuralReport{Version: "0.5.0-dev", SchemaVersion: "v2", Path: "demo", Score: &StructuralScore{TotalScore: 97, MaxScore: 100}}
+
+	legacy := NewReporter(FormatJSONLegacy).Format(report)
+	if legacy != NewReporter(FormatJSON).Format(report) {
+		t.Fatalf("expected json-legacy to print the legacy json report:\n%s", legacy)
+	}
+	var payload struct {
+		Deprecated bool `json:"deprecated"`
+	}
+	if err := json.Unmarshal([]byte(legacy), &payload); err != nil || !payload.Deprecated {
+		t.Fatalf("expected deprecated: true, got %s (%v)", legacy, err)
+	}
+	if strings.Contains(NewReporter(FormatJSONV1).Format(report), "deprecated") {
+		t.Fatal("json-v1 must not be marked deprecated")
+	}
+	if err := validateAnalyzeFormat(string(FormatJSONLegacy)); err != nil {
+		t.Fatalf("expected analyze to accept json-legacy: %v", err)
+	}
+}
+
+func TestResolveJSONFormat_FollowsDefaultJSONConfig(t *testing.T) {
+	v1 := &Config{Output: &OutputConfig{DefaultJSON: defaultJSONV1}}
+	legacy := &Config{Output: &OutputConfig{DefaultJSON: defaultJSONLegacy}}
+	tests := []struct {
+		format OutputFormat
+		cfg    *Config
+		want   OutputFormat
+	}{
+		{FormatJSON, nil, FormatJSONLegacy},
+		{FormatJSON, &Config{}, FormatJSONLegacy},
+		{FormatJSON, legacy, FormatJSONLegacy},
+		{FormatJSON, v1, FormatJSONV1},
+		{FormatJSONLegacy, v1, FormatJSONLegacy},
+		{FormatJSONV1, legacy, FormatJSONV1},
+		{FormatText, v1, FormatText},
+	}
+	for _, tc := range tests {
+		if got := resolveJSONFormat(tc.format, tc.cfg); got != tc.want {
+			t.Errorf("resolveJSONFormat(%s, %+v) = %s, want %s", tc.format, tc.cfg, got, tc.want)
+		}
+	}
+	if err := validateOutputConfig(&OutputConfig{DefaultJSON: "v2"}); err == nil {
+		t.Fatal("expected an unknown output.default_json to be rejected")
+	}
+}
+
+func TestAnalyze_DefaultJSONConfigSwitchesPlainJSONToV1(t *testing.T) {
+	dir := t.TempDir()
+	writeServiceFixture(t, dir, map[string]string{
+		"main.go":                 "package main\n\nfunc main() {}\n",
+		".repodoctor/config.yaml": "output:\n  default_json: v1\n",
+	})
+
+	out := captureStdout(t, func() {
+		NewAnalysisService().analyze(AnalyzeRequest{Path: dir, Format: string(FormatJSON), Options: AnalyzeOptions{NoLargest: true}})
+	})
+	if !strings.Contains(out, `"schemaVersion": 1,`) || strings.Contains(out, `"deprecated"`) {
+		t.Fatalf("expected output.default_json: v1 to print json-v1 for -format json:\n%s", out)
+	}
+}
+
+func TestWriteLegacyJSONNotice_SuppressedByQuiet(t *testing.T) {
+	tests := []struct {
+		format OutputFormat
+		quiet  bool
+		want   bool
+	}{
+		{FormatJSONLegacy, false, true},
+		{FormatJSONLegacy, true, false},
+		{FormatJSONV1, false, false},
+		{FormatText, false, false},
+	}
+	for _, tc := range tests {
+		var stderr bytes.Buffer
+		writeLegacyJSONNotice(&stderr, tc.format, tc.quiet)
+		if got := strings.Contains(stderr.String(), "deprecated"); got != tc.want {
+			t.Errorf("format %s, quiet %v: expected notice %v, got %q", tc.format, tc.quiet, tc.want, stderr.String())
+		}
+	}
+
+	req, err := parseAnalyzeFlags([]string{"-quiet", "-format", "json", "."})
+	if err != nil || !req.Options.NoNotices {
+		t.Fatalf("expected -quiet to suppress notices, got %+v, %v", req, err)
+	}
+}
+
+func TestJSONWriters_ShareFindings(t *testing.T) {
+	report := &StructuralReport{
+		Version:   "0.5.0-dev",
+		Path:      "demo",
+		Score:     &StructuralScore{TotalScore: 70, MaxScore: 100},
+		Circular:  []CycleViolation{{Path: []string{"b.go", "a.go"}, Severity: model.SeverityCritical}, {Path: []string{"a.go", "c.go"}, Severity: model.SeverityCritical}},
+		Layer:     []LayerViolation{{From: "store/s.go", To: "api/h.go", Message: "store imports api"}},
+		Size:      []SizeViolation{{File: "z.go", Function: "Run", Lines: 90, Threshold: 80}, {File: "z.go", Lines: 600, Threshold: 500}},
+		GodObject: []GodObjectViolation{{StructName: "Ledger", File: "ledger.go", FieldCount: 30, MethodCount: 4}},
+	}
+
+	var legacy struct {
+		Circular  []CycleViolation     `json:"circularViolations"`
+		Layer     []LayerViolation     `json:"layerViolations"`
+		Size      []SizeViolation      `json:"sizeViolations"`
+		GodObject []GodObjectViolation `json:"godObjectViolations"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONLegacy).Format(report)), &legacy); err != nil {
+		t.Fatalf("invalid legacy json: %v", err)
+	}
+	var v1 jsonV1Document
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &v1); err != nil {
+		t.Fatalf("invalid json-v1: %v", err)
+	}
+
+	var fromLegacy, fromV1 []string
+	for _, v := range legacy.Circular {
+		fromLegacy = append(fromLegacy, fmt.Sprint("circular ", v.Path, v.Severity))
+	}
+	for _, v := range v1.CircularViolations {
+		fromV1 = append(fromV1, fmt.Sprint("circular ", v.Path, v.Severity))
+	}
+	for _, v := range legacy.Layer {
+		fromLegacy = append(fromLegacy, fmt.Sprint("layer ", v.From, v.To, v.Message))
+	}
+	for _, v := range v1.LayerViolations {
+		fromV1 = append(fromV1, fmt.Sprint("layer ", v.From, v.To, v.Message))
+	}
+	for _, v := range legacy.Size {
+		fromLegacy = append(fromLegacy, fmt.Sprint("size ", v.File, v.Function, v.Lines, v.Threshold))
+	}
+	for _, v := range v1.SizeViolations {
+		fromV1 = append(fromV1, fmt.Sprint("size ", v.File, v.Function, v.Lines, v.Threshold))
+	}
+	for _, v := range legacy.GodObject {
+		fromLegacy = append(fromLegacy, fmt.Sprint("god ", v.StructName, v.File, v.FieldCount, v.MethodCount))
+	}
+	for _, v := range v1.GodObjectViolations {
+		fromV1 = append(fromV1, fmt.Sprint("god ", v.Struct, v.File, v.Fields, v.Methods))
+	}
+	sort.Strings(fromLegacy)
+	sort.Strings(fromV1)
+	if len(fromLegacy) != 6 || strings.Join(fromLegacy, "\n") != strings.Join(fromV1, "\n") {
+		t.Fatalf("expected both writers to print the same findings\nlegacy:\n%s\njson-v1:\n%s", strings.Join(fromLegacy, "\n"), strings.Join(fromV1, "\n"))
+	}
+}
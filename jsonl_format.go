@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// FormatJSONL prints one JSON object per violation, for tools that process
+// the findings of large repositories line by line
+const FormatJSONL OutputFormat = "jsonl"
+
+// FormatNDJSON prints a header record with the score and the violation
+// counts, then one JSON object per violation. Reporter.Stream writes it
+// record by record, so the report is never held in memory as one string.
+const FormatNDJSON OutputFormat = "ndjson"
+
+// ndjsonHeader is the first record of an ndjson report
+type ndjsonHeader struct {
+	Type          string       `json:"type"`
+	SchemaVersion int          `json:"schemaVersion"`
+	Version       string       `json:"version"`
+	Path          string       `json:"path"`
+	Score         jsonV1Score  `json:"score"`
+	Violations    jsonV1Counts `json:"violations"`
+}
+
+// formatJSONL renders every violation as a json-v1 violation object on a
+// line of its own, with a type field of circular, layer, size or
+// godObject. Cycles come first, then layer, size and god object
+// violations, each in the legacy json's stable order. A clean report prints
+// nothing.
+func formatJSONL(report *StructuralReport) string {
+	var sb strings.Builder
+	writeViolationRecords(&sb, report)
+	return sb.String()
+}
+
+// formatNDJSON renders an ndjson report into a string, for callers that
+// need the whole document
+func formatNDJSON(report *StructuralReport) string {
+	var sb strings.Builder
+	streamNDJSON(&sb, report)
+	return sb.String()
+}
+
+// streamNDJSON writes the header record of report, then its violation
+// records as in jsonl output
+func streamNDJSON(w io.Writer, report *StructuralReport) error {
+	score := report.Score
+	if score == nil {
+		score = &StructuralScore{}
+	}
+	header := ndjsonHeader{
+		Type:          "header",
+		SchemaVersion: jsonV1SchemaVersion,
+		Version:       report.Version,
+		Path:          report.Path,
+		Score:         newJSONV1Score(score),
+		Violations:    newJSONV1Counts(report, score),
+	}
+	if err := json.NewEncoder(w).Encode(header); err != nil {
+		return err
+	}
+	return writeViolationRecords(w, report)
+}
+
+// writeViolationRecords writes every violation of report to w as one line
+// of JSON, converting and encoding one violation at a time. The violation
+// types only hold strings and numbers, so only writing to w can fail.
+func writeViolationRecords(w io.Writer, report *StructuralReport) error {
+	findings := newReportFindings(report).sorted()
+	enc := json.NewEncoder(w)
+	var err error
+	encode := func(record any) {
+		if err == nil {
+			err = enc.Encode(record)
+		}
+	}
+	for _, v := range findings.Circular {
+		encode(struct {
+			Type string `json:"type"`
+			jsonV1CycleViolation
+		}{"circular", newJSONV1CycleViolation(v)})
+	}
+	for _, v := range findings.Layer {
+		encode(struct {
+			Type string `json:"type"`
+			jsonV1LayerViolation
+		}{"layer", newJSONV1LayerViolation(v)})
+	}
+	for _, v := range findings.FeatureIsolation {
+		encode(struct {
+			Type string `json:"type"`
+			FeatureIsolationViolation
+		}{"featureIsolation", v})
+	}
+	for _, v := range findings.DependencyCap {
+		encode(struct {
+			Type string `json:"type"`
+			DependencyCapViolation
+		}{"dependencyCap", v})
+	}
+	for _, v := range findings.Size {
+		encode(struct {
+			Type string `json:"type"`
+			jsonV1SizeViolation
+		}{"size", newJSONV1SizeViolation(v)})
+	}
+	for _, v := range findings.GodObject {
+		encode(struct {
+			Type string `json:"type"`
+			jsonV1GodObjectViolation
+		}{"godObject", newJSONV1GodObjectViolation(v)})
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func TestFormatJSONL_OneViolationPerLineInCategoryOrder(t *testing.T) {
+	report := &StructuralReport{
+		Path:      "/repo",
+		Score:     &StructuralScore{TotalScore: 70, MaxScore: 100},
+		GodObject: []GodObjectViolation{{StructName: "Server", File: "/repo/server.go", FieldCount: 20, Line: 3}},
+		Size:      []SizeViolation{{File: "/repo/z.go", Lines: 600, Threshold: 500}, {File: "/repo/a \"quoted\".go", Function: "Run", Lines: 90, Threshold: 80}},
+		Layer:     []LayerViolation{{From: "/repo/repo/s.go", To: "/repo/handler/h.go", Message: "upward\nimport"}},
+		Circular:  []CycleViolation{{Path: []string{"/repo/b.go", "/repo/a.go"}, Severity: model.SeverityCritical}},
+	}
+
+	out := NewReporter(FormatJSONL).Format(report)
+	if !strings.HasSuffix(out, "\n") {
+		t.Fatalf("expected the last line to end with a newline:\n%s", out)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	var types, files []string
+	for _, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("expected every line to be valid JSON, %q: %v", line, err)
+		}
+		types = append(types, record["type"].(string))
+		if file, ok := record["file"].(string); ok {
+			files = append(files, file)
+		}
+	}
+	if got := strings.Join(types, ","); got != "circular,layer,size,size,godObject" {
+		t.Errorf("expected one line per violation in category order, got %s", got)
+	}
+	if got := strings.Join(files, ","); got != `a "quoted".go,z.go,server.go` {
+		t.Errorf("expected sorted, relative size and god object files, got %s", got)
+	}
+
+	if out := NewReporter(FormatJSONL).Format(&StructuralReport{Path: "/repo"}); out != "" {
+		t.Errorf("expected a clean report to print nothing, got %q", out)
+	}
+}
+
+func TestAnalysisService_JSONLKeepsStdoutClean(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+	writeServiceFixture(t, root, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n",
+		"repo/store.go": "package repo\n\nimport _ \"example.com/app/handler\"\n",
+		"handler/h.go":  "package handler\n",
+	})
+
+	out := captureStdout(t, func() {
+		NewAnalysisService().Run(AnalyzeRequest{Path: root, Format: string(FormatJSONL)})
+	})
+	var record struct {
+		Type string `json:"type"`
+		From string `json:"from"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &record); err != nil || record.Type != "layer" || record.From != "repo/store.go" {
+		t.Fatalf("expected a single layer violation line, got %q (%v)", out, err)
+	}
+}
+
+func TestFormatNDJSON_HeaderThenViolations(t *testing.T) {
+	report := &StructuralReport{
+		Path:     "/repo",
+		Version:  "1.2.3",
+		Score:    &StructuralScore{TotalScore: 87, MaxScore: 100, CircularCount: 1, SizeCount: 1, CircularPenalty: 10, SizePenalty: 3},
+		Size:     []SizeViolation{{File: "/repo/z.go", Lines: 600, Threshold: 500}},
+		Circular: []CycleViolation{{Path: []string{"/repo/b.go", "/repo/a.go"}, Severity: model.SeverityCritical}},
+	}
+
+	var streamed strings.Builder
+	if err := NewReporter(FormatNDJSON).Stream(&streamed, report); err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	out := streamed.String()
+	if formatted := NewReporter(FormatNDJSON).Format(report); formatted != out {
+		t.Fatalf("expected Format to match the streamed output, got:\n%s\nwant:\n%s", formatted, out)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	var header struct {
+		Type       string       `json:"type"`
+		Version    string       `json:"version"`
+		Score      jsonV1Score  `json:"score"`
+		Violations jsonV1Counts `json:"violations"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("expected a JSON header line, %q: %v", lines[0], err)
+	}
+	if header.Type != "header" || header.Version != "1.2.3" || header.Score.Total != 87 || header.Violations.Circular != 1 || header.Violations.Size != 1 {
+		t.Fatalf("unexpected header %+v", header)
+	}
+	if got := strings.Join(lines[1:], "\n") + "\n"; got != NewReporter(FormatJSONL).Format(report) {
+		t.Fatalf("expected the violation records of jsonl output, got:\n%s", got)
+	}
+
+	clean := NewReporter(FormatNDJSON).Format(&StructuralReport{Path: "/repo"})
+	if strings.Count(clean, "\n") != 1 || !strings.Contains(clean, `"type":"header"`) {
+		t.Fatalf("expected a clean report to print only the header, got %q", clean)
+	}
+}
+
+// largeStreamReport holds n size violations, like a monorepo report
+func largeStreamReport(n int) *StructuralReport {
+	report := &StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 0, MaxScore: 100, SizeCount: n}}
+	for i := 0; i < n; i++ {
+		report.Size = append(report.Size, SizeViolation{File: fmt.Sprintf("/repo/pkg%d/file%d.go", i%100, i), Function: "Run", Lines: 120, Threshold: 80})
+	}
+	return report
+}
+
+// BenchmarkReporter_StreamNDJSON writes a large report record by record;
+// compare its B/op with BenchmarkReporter_FormatJSONL, which builds the same
+// records into one string before writing it
+func BenchmarkReporter_StreamNDJSON(b *testing.B) {
+	report := largeStreamReport(20000)
+	reporter := NewReporter(FormatNDJSON)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := reporter.Stream(io.Discard, report); err != nil {
+			b.Fatalf("stream failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkReporter_FormatJSONL(b *testing.B) {
+	report := largeStreamReport(20000)
+	reporter := NewReporter(FormatJSONL)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := io.WriteString(io.Discard, reporter.Format(report)); err != nil {
+			b.Fatalf("write failed: %v", err)
+		}
+	}
+}
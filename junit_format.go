@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FormatJUnit prints the report as JUnit XML, for CI test dashboards
+const FormatJUnit OutputFormat = "junit"
+
+// junitCoreRules always get a test suite, passing when the rule found
+// nothing, in this order
+var junitCoreRules = sarifCoreRules
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// formatJUnit renders one test suite per rule and one failed test case per
+// circular, layer, size and god object violation. The core rules always
+// get a suite; a suite without violations holds a single passing case.
+// Suite times are the rule's execution time; file paths are relative to
+// the analyzed directory.
+func formatJUnit(report *StructuralReport) string {
+	suites := newJUnitSuites()
+	relative := func(file string) string {
+		return relativeToBase(file, report.Path)
+	}
+	for _, v := range report.Circular {
+		cycle := make([]string, len(v.Path))
+		for i, file := range v.Path {
+			cycle[i] = relative(file)
+		}
+		suites.fail("rule.circular-dependency", formatCyclePath(cycle), "Circular dependency: "+formatCyclePath(cycle))
+	}
+	for _, v := range report.Layer {
+		ruleID := v.RuleID
+		if ruleID == "" {
+			ruleID = "rule.layer-validation"
+		}
+		suites.fail(ruleID, relative(v.From)+" -> "+relative(v.To), v.Message)
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		suites.fail("rule.feature-isolation", relative(v.From), v.Message)
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		suites.fail("rule.dependency-cap", relative(v.Manifest), v.Message)
+	}
+	for _, v := range report.Size {
+		name := relative(v.File)
+		if v.Function != "" {
+			name += ":" + v.Function
+		}
+		suites.fail("rule.size", name, sizeViolationMessage(v))
+	}
+	for _, v := range report.GodObject {
+		suites.fail("rule.god-object", relative(v.File)+":"+v.StructName, godObjectViolationMessage(v))
+	}
+
+	doc := suites.document(report.Metrics.RuleDurations)
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return xml.Header
+	}
+	return xml.Header + string(data) + "\n"
+}
+
+// junitSuites collects failed test cases per rule, keeping the order in
+// which rules first appear
+type junitSuites struct {
+	order    []string
+	failures map[string][]junitTestCase
+}
+
+func newJUnitSuites() *junitSuites {
+	suites := &junitSuites{failures: make(map[string][]junitTestCase)}
+	for _, id := range junitCoreRules {
+		suites.suite(id)
+	}
+	return suites
+}
+
+func (s *junitSuites) suite(ruleID string) {
+	if _, ok := s.failures[ruleID]; !ok {
+		s.order = append(s.order, ruleID)
+		s.failures[ruleID] = nil
+	}
+}
+
+func (s *junitSuites) fail(ruleID, name, message string) {
+	s.suite(ruleID)
+	s.failures[ruleID] = append(s.failures[ruleID], junitTestCase{
+		Name:      name,
+		ClassName: junitClassName(ruleID),
+		Time:      junitSeconds(0),
+		Failure:   &junitFailure{Message: message, Type: ruleID, Body: message},
+	})
+}
+
+// document builds the testsuites element, adding the passing case of every
+// suite without failures
+func (s *junitSuites) document(durations map[string]time.Duration) junitTestSuites {
+	doc := junitTestSuites{Name: "RepoDoctor"}
+	var total time.Duration
+	for _, ruleID := range s.order {
+		name := ruleShortName(ruleID)
+		cases := s.failures[ruleID]
+		failures := len(cases)
+		if failures == 0 {
+			cases = []junitTestCase{{Name: "no " + strings.ReplaceAll(name, "-", " ") + " violations", ClassName: junitClassName(ruleID), Time: junitSeconds(durations[ruleID])}}
+		}
+		doc.Suites = append(doc.Suites, junitTestSuite{Name: name, Tests: len(cases), Failures: failures, Time: junitSeconds(durations[ruleID]), Cases: cases})
+		doc.Tests += len(cases)
+		doc.Failures += failures
+		total += durations[ruleID]
+	}
+	doc.Time = junitSeconds(total)
+	return doc
+}
+
+// junitClassName groups a rule's test cases under repodoctor.<rule>
+func junitClassName(ruleID string) string {
+	return "repodoctor." + ruleShortName(ruleID)
+}
+
+// junitSeconds formats a duration the way JUnit time attributes expect
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
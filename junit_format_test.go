@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"RepoDoctor/internal/model"
+)
+
+func TestFormatJUnit_OneFailedCasePerViolation(t *testing.T) {
+	report := &StructuralReport{
+		Path:     "/repo",
+		Circular: []CycleViolation{{Path: []string{"/repo/a/a.go", "/repo/b/b.go"}, Severity: model.SeverityCritical}},
+		Layer:    []LayerViolation{{From: "/repo/store/s.go", To: "/repo/api/h.go", Message: "store imports api"}},
+		Size: []SizeViolation{
+			{File: `/repo/gen/<weird> & "odd".go`, Lines: 600, Threshold: 500},
+			{File: "/repo/big.go", Function: "Run", Lines: 90, Threshold: 80},
+		},
+		Metrics: ReportMetrics{RuleDurations: map[string]time.Duration{"rule.size": 1500 * time.Millisecond}},
+	}
+
+	out := NewReporter(FormatJUnit).Format(report)
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Fatalf("expected an XML declaration, got %q", out)
+	}
+	if !strings.Contains(out, `name="gen/&lt;weird&gt; &amp; &#34;odd&#34;.go"`) {
+		t.Fatalf("expected special characters in file paths to be escaped:\n%s", out)
+	}
+
+	var doc junitTestSuites
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JUnit XML: %v", err)
+	}
+	if doc.Tests != 5 || doc.Failures != 4 || doc.Time != "1.500" {
+		t.Fatalf("unexpected totals: tests=%d failures=%d time=%s", doc.Tests, doc.Failures, doc.Time)
+	}
+	names := make([]string, len(doc.Suites))
+	for i, suite := range doc.Suites {
+		names[i] = suite.Name
+	}
+	if strings.Join(names, ",") != "circular-dependency,layer-validation,size,god-object" {
+		t.Fatalf("expected one suite per core rule, got %v", names)
+	}
+
+	cycle := doc.Suites[0].Cases[0]
+	if cycle.Failure == nil || cycle.Failure.Body != "Circular dependency: a/a.go → b/b.go → a/a.go" || cycle.Failure.Type != "rule.circular-dependency" {
+		t.Fatalf("unexpected cycle case: %+v", cycle)
+	}
+	size := doc.Suites[2]
+	if size.Tests != 2 || size.Failures != 2 || size.Time != "1.500" || size.Cases[1].Name != "big.go:Run" {
+		t.Fatalf("unexpected size suite: %+v", size)
+	}
+	if god := doc.Suites[3]; god.Failures != 0 || len(god.Cases) != 1 || god.Cases[0].Failure != nil {
+		t.Fatalf("expected a passing god object suite, got %+v", god)
+	}
+}
+
+func TestFormatJUnit_CleanRepoPasses(t *testing.T) {
+	var doc junitTestSuites
+	if err := xml.Unmarshal([]byte(NewReporter(FormatJUnit).Format(&StructuralReport{Path: "/repo"})), &doc); err != nil {
+		t.Fatalf("invalid JUnit XML: %v", err)
+	}
+	if doc.Failures != 0 || doc.Tests != len(junitCoreRules) {
+		t.Fatalf("expected one passing case per core rule, got tests=%d failures=%d", doc.Tests, doc.Failures)
+	}
+	for _, suite := range doc.Suites {
+		if suite.Failures != 0 || suite.Cases[0].Failure != nil {
+			t.Fatalf("expected suite %s to pass", suite.Name)
+		}
+	}
+}
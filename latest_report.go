@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LatestReport is the document persisted to .repodoctor/latest.json after
+// every analysis so external tooling can read the newest result without
+// re-running RepoDoctor
+type LatestReport struct {
+	GeneratedAt string          `json:"generatedAt"`
+	Version     string          `json:"version"`
+	ConfigHash  string          `json:"configHash,omitempty"`
+	Format      string          `json:"format"`
+	Report      json.RawMessage `json:"report"`
+}
+
+// latestReportPath returns the location of latest.json for a repository
+func latestReportPath(baseDir string) string {
+	return filepath.Join(baseDir, ".repodoctor", "latest.json")
+}
+
+// persistLatestEnabled reports whether latest.json should be written
+func persistLatestEnabled(cfg *Config) bool {
+	return cfg == nil || cfg.PersistLatest == nil || *cfg.PersistLatest
+}
+
+// writeLatestReport writes the json-v1 report and run metadata to
+// latest.json, atomically
+func writeLatestReport(baseDir string, report *StructuralReport, cfg *Config, now time.Time) error {
+	body := NewReporter(FormatJSONV1).Format(report)
+	if !json.Valid([]byte(body)) {
+		return fmt.Errorf("json-v1 report is not valid JSON")
+	}
+
+	data, err := json.MarshalIndent(LatestReport{
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Version:     report.Version,
+		ConfigHash:  configHash(cfg),
+		Format:      string(FormatJSONV1),
+		Report:      json.RawMessage(body),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal latest report: %w", err)
+	}
+
+	return writeStateFile(latestReportPath(baseDir), append(data, '\n'), "latest report")
+}
+
+// writeStateFile writes data to a file of the .repodoctor state directory,
+// naming it what in errors. The file is written to a temporary sibling and
+// renamed into place so readers never observe a partial document.
+func writeStateFile(path string, data []byte, what string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	pattern := "." + strings.TrimSuffix(filepath.Base(path), ".json") + "-*.json"
+	tmp, err := os.CreateTemp(filepath.Dir(path), pattern)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary %s: %w", what, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", what, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", what, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", what, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", what, err)
+	}
+	return nil
+}
+
+// persistLatestReport writes latest.json when enabled, stamped by the
+// request's clock. Failures, such as a read-only checkout, only produce a
+// warning and never fail the analysis.
+func persistLatestReport(absPath string, report *StructuralReport, cfg *Config, request AnalyzeRequest) {
+	if !persistLatestEnabled(cfg) {
+		return
+	}
+	if err := writeLatestReport(absPath, report, cfg, runClock(request.Options.Deterministic)()); err != nil && request.Verbose {
+		fmt.Printf("%s", ColorWarn(fmt.Sprintf("Warning: could not save latest report: %v\n", err)))
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readLatestReport(t *testing.T, baseDir string) LatestReport {
+	t.Helper()
+	data, err := os.ReadFile(latestReportPath(baseDir))
+	if err != nil {
+		t.Fatalf("expected latest.json to exist: %v", err)
+	}
+	var latest LatestReport
+	if err := json.Unmarshal(data, &latest); err != nil {
+		t.Fatalf("latest.json is not valid JSON: %v", err)
+	}
+	return latest
+}
+
+func TestWriteLatestReport_WritesJSONV1WithMetadata(t *testing.T) {
+	baseDir := t.TempDir()
+	report := goldenFixtureReport()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := writeLatestReport(baseDir, report, nil, now); err != nil {
+		t.Fatalf("writeLatestReport failed: %v", err)
+	}
+
+	latest := readLatestReport(t, baseDir)
+	if latest.Format != "json-v1" || latest.Version != report.Version || latest.GeneratedAt != "2026-01-02T03:04:05Z" {
+		t.Fatalf("unexpected metadata: %+v", latest)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(latest.Report, &body); err != nil {
+		t.Fatalf("embedded report is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"version", "path", "score", "violations"} {
+		if _, ok := body[key]; !ok {
+			t.Fatalf("embedded json-v1 report missing %q: %v", key, body)
+		}
+	}
+}
+
+func TestWriteLatestReport_ReplacesAtomically(t *testing.T) {
+	baseDir := t.TempDir()
+	first := goldenFixtureReport()
+	second := goldenFixtureReport()
+	second.Version = "9.9.9"
+
+	if err := writeLatestReport(baseDir, first, nil, time.Now()); err != nil {
+		t.Fatalf("first write failed: %v", err)
+	}
+	if err := writeLatestReport(baseDir, second, nil, time.Now()); err != nil {
+		t.Fatalf("second write failed: %v", err)
+	}
+
+	if latest := readLatestReport(t, baseDir); latest.Version != "9.9.9" {
+		t.Fatalf("expected latest.json to hold the newest report, got version %q", latest.Version)
+	}
+	entries, err := os.ReadDir(filepath.Join(baseDir, ".repodoctor"))
+	if err != nil {
+		t.Fatalf("failed to read state dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "latest.json" {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Fatalf("expected only latest.json in state dir, got %v", names)
+	}
+}
+
+func TestPersistLatestReport_SkippedWhenDisabledOrUnwritable(t *testing.T) {
+	baseDir := t.TempDir()
+	disabled := false
+	persistLatestReport(baseDir, goldenFixtureReport(), &Config{PersistLatest: &disabled}, AnalyzeRequest{})
+	if _, err := os.Stat(latestReportPath(baseDir)); !os.IsNotExist(err) {
+		t.Fatalf("expected no latest.json when persist_latest is false, got %v", err)
+	}
+
+	// A file where the state directory should be makes the target unwritable
+	blocked := t.TempDir()
+	if err := os.WriteFile(filepath.Join(blocked, ".repodoctor"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to create blocking file: %v", err)
+	}
+	if err := writeLatestReport(blocked, goldenFixtureReport(), nil, time.Now()); err == nil {
+		t.Fatal("expected an error when the state directory cannot be created")
+	}
+	persistLatestReport(blocked, goldenFixtureReport(), nil, AnalyzeRequest{})
+}
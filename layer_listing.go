@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// layerUnknown groups packages whose path names no layer. detectLayer treats
+// them as service, but listing them apart shows where the heuristic guesses.
+const layerUnknown = "unknown"
+
+// LayerGroup lists the packages assigned to one layer
+type LayerGroup struct {
+	Layer    string   `json:"layer"`
+	Packages []string `json:"packages"`
+}
+
+// GroupPackagesByLayer assigns every analyzed package in the graph to its
+// layer. Packages are the directories of analyzed file nodes, reported
+// relative to root; groups follow the layer hierarchy with unknown last, and
+// packages within a group are sorted.
+func GroupPackagesByLayer(graph Graph, files []string, root string) []LayerGroup {
+	analyzed := make(map[string]bool, len(files))
+	for _, file := range files {
+		analyzed[file] = true
+	}
+
+	packages := make(map[string]bool)
+	for _, node := range graph.GetAllNodes() {
+		if !analyzed[node] {
+			continue
+		}
+		pkg := filepath.Dir(node)
+		if rel, err := filepath.Rel(root, pkg); err == nil {
+			pkg = rel
+		}
+		packages[filepath.ToSlash(pkg)] = true
+	}
+
+	order := append(defaultLayerHierarchy.layerNames(), layerUnknown)
+	members := make(map[string][]string, len(order))
+	for pkg := range packages {
+		// Paths are matched relative to root so directories above the
+		// repository cannot select a layer
+		layer := layerUnknown
+		if matched, ok := defaultLayerHierarchy.matchLayer(pkg); ok {
+			layer = string(matched)
+		}
+		members[layer] = append(members[layer], pkg)
+	}
+
+	groups := make([]LayerGroup, 0, len(order))
+	for _, layer := range order {
+		pkgs := members[layer]
+		if pkgs == nil {
+			pkgs = []string{}
+		}
+		sort.Strings(pkgs)
+		groups = append(groups, LayerGroup{Layer: layer, Packages: pkgs})
+	}
+	return groups
+}
+
+func formatLayerGroups(groups []LayerGroup, format string) string {
+	if format == string(FormatJSON) || format == string(FormatJSONV1) {
+		data, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return "[]\n"
+		}
+		return string(data) + "\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🧱 Detected Layers\n")
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	for _, group := range groups {
+		sb.WriteString(fmt.Sprintf("\n%s (%d):\n", group.Layer, len(group.Packages)))
+		if len(group.Packages) == 0 {
+			sb.WriteString("   (none)\n")
+			continue
+		}
+		for _, pkg := range group.Packages {
+			sb.WriteString(fmt.Sprintf("   • %s\n", pkg))
+		}
+	}
+	sb.WriteString(strings.Repeat("─", 60) + "\n")
+	return sb.String()
+}
+
+// runLayers prints the packages of a repository grouped by detected layer
+func runLayers(path, format string) error {
+	absPath := validatePath(path)
+
+	result, err := newAnalysisOrchestrator(absPath).AnalyzeGraph(absPath)
+	if err != nil {
+		return WrapError(err, ErrorAnalysis, "Dependency graph extraction failed", GetSuggestion(err.Error()))
+	}
+
+	graph := buildDependencyGraphFromModel(result.Graph, false)
+	fmt.Print(formatLayerGroups(GroupPackagesByLayer(graph, result.Files, absPath), format))
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGroupPackagesByLayer_FixtureMembership(t *testing.T) {
+	repo := t.TempDir()
+	files := map[string]string{
+		"main.go":                  "package main\n\nimport \"example.com/demo/api/handler\"\n",
+		"api/handler/user.go":      "package handler\n\nimport \"example.com/demo/service\"\n",
+		"api/handler/order.go":     "package handler\n",
+		"service/user.go":          "package service\n\nimport \"example.com/demo/internal/repo\"\n",
+		"internal/repo/user.go":    "package repo\n",
+		"internal/util/strings.go": "package util\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	result, err := newAnalysisOrchestrator(repo).AnalyzeGraph(repo)
+	if err != nil {
+		t.Fatalf("AnalyzeGraph failed: %v", err)
+	}
+	groups := GroupPackagesByLayer(buildDependencyGraphFromModel(result.Graph, false), result.Files, repo)
+
+	want := []LayerGroup{
+		{Layer: "handler", Packages: []string{"api/handler"}},
+		{Layer: "service", Packages: []string{"service"}},
+		{Layer: "repo", Packages: []string{"internal/repo"}},
+		{Layer: "unknown", Packages: []string{".", "internal/util"}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("unexpected layer grouping\nwant: %+v\ngot:  %+v", want, groups)
+	}
+
+	var decoded []LayerGroup
+	if err := json.Unmarshal([]byte(formatLayerGroups(groups, "json")), &decoded); err != nil {
+		t.Fatalf("expected valid JSON output: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("JSON output does not round-trip: %+v", decoded)
+	}
+}
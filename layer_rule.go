@@ -1,10 +1,22 @@
 package main
 
+import (
+	"slices"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
+)
+
 // LayerViolation represents a layer constraint violation
 type LayerViolation struct {
 	From    string
 	To      string
 	Message string
+	// RuleID is the runtime rule that produced the violation; it is not
+	// serialized so the report schema stays unchanged
+	RuleID string `json:"-"`
+	// Severity is the severity the rule reported; it is not serialized either
+	Severity model.Severity `json:"-"`
 }
 
 // LayerConvention represents the allowed dependency direction
@@ -16,23 +28,62 @@ const (
 	LayerRepo    LayerConvention = "repo"
 )
 
-// layerOrder defines the hierarchy (lower index = higher layer)
-var layerOrder = map[LayerConvention]int{
-	LayerHandler: 0,
-	LayerService: 1,
-	LayerRepo:    2,
+// layerHierarchy orders the layers, lower index = higher layer. Imports may
+// only point downwards.
+type layerHierarchy struct {
+	levels []LayerLevelConfig
+	// layerOrder maps a layer name to its index in levels
+	layerOrder map[LayerConvention]int
+	// exempt lists keywords whose paths are never checked
+	exempt []string
+	// fallback is the layer of paths matching no keyword; empty leaves them
+	// unchecked
+	fallback LayerConvention
+}
+
+// defaultLayerHierarchy is the built-in handler -> service -> repo
+// hierarchy, where unmatched paths count as the service layer
+var defaultLayerHierarchy = newLayerHierarchy(nil)
+
+// newLayerHierarchy builds the hierarchy of the layers config section,
+// falling back to the built-in three layers when it is not configured
+func newLayerHierarchy(layers *LayersConfig) *layerHierarchy {
+	if layers == nil || len(layers.Levels) == 0 {
+		layers = &LayersConfig{
+			Levels: []LayerLevelConfig{
+				{Name: string(LayerHandler), Keywords: []string{string(LayerHandler)}},
+				{Name: string(LayerService), Keywords: []string{string(LayerService)}},
+				{Name: string(LayerRepo), Keywords: []string{string(LayerRepo)}},
+			},
+			Default: string(LayerService),
+		}
+	}
+
+	h := &layerHierarchy{
+		levels:     layers.Levels,
+		layerOrder: make(map[LayerConvention]int, len(layers.Levels)),
+		exempt:     layers.Exempt,
+		fallback:   LayerConvention(layers.Default),
+	}
+	for i, level := range layers.Levels {
+		h.layerOrder[LayerConvention(level.Name)] = i
+	}
+	return h
 }
 
 // LayerValidationRule enforces architectural layering constraints
 type LayerValidationRule struct {
 	graph      Graph
+	hierarchy  *layerHierarchy
 	violations []LayerViolation
 }
 
-// NewLayerValidationRule creates a new layer validation rule checker
-func NewLayerValidationRule(graph Graph) *LayerValidationRule {
+// NewLayerValidationRule creates a new layer validation rule checker for
+// the layers config section; nil selects the built-in three layers
+func NewLayerValidationRule(graph Graph, layers *LayersConfig) *LayerValidationRule {
 	return &LayerValidationRule{
 		graph:      graph,
+		hierarchy:  newLayerHierarchy(layers),
 		violations: []LayerViolation{},
 	}
 }
@@ -43,8 +94,8 @@ func (r *LayerValidationRule) Name() string {
 }
 
 // Severity returns the severity level of this rule
-func (r *LayerValidationRule) Severity() string {
-	return "high"
+func (r *LayerValidationRule) Severity() model.Severity {
+	return model.SeverityError
 }
 
 // Check runs the rule and returns true if violations are found
@@ -55,13 +106,13 @@ func (r *LayerValidationRule) Check() bool {
 	nodes := r.graph.GetAllNodes()
 	for _, node := range nodes {
 		deps := r.graph.GetDependencies(node)
-		fromLayer := detectLayer(node)
+		fromLayer := r.hierarchy.detectLayer(node)
 
 		for _, dep := range deps {
-			toLayer := detectLayer(dep)
+			toLayer := r.hierarchy.detectLayer(dep)
 
 			// Check if this is an upward import (forbidden)
-			if isUpwardImport(fromLayer, toLayer) {
+			if r.hierarchy.isUpwardImport(fromLayer, toLayer) {
 				r.violations = append(r.violations, LayerViolation{
 					From:    node,
 					To:      dep,
@@ -93,48 +144,56 @@ func (r *LayerValidationRule) Message() string {
 	return msg
 }
 
-// detectLayer detects the layer of a package based on its path
-func detectLayer(pkgPath string) LayerConvention {
-	// Check for layer keywords in the path
-	if containsLayerKeyword(pkgPath, "handler") {
-		return LayerHandler
-	}
-	if containsLayerKeyword(pkgPath, "service") {
-		return LayerService
+// detectLayer detects the layer of a package based on its path. Exempt
+// paths, and paths matching no keyword without a fallback, have no layer.
+func (h *layerHierarchy) detectLayer(pkgPath string) LayerConvention {
+	segments := rules.LayerPathSegments(pkgPath)
+	for _, keyword := range h.exempt {
+		if slices.Contains(segments, keyword) {
+			return ""
+		}
 	}
-	if containsLayerKeyword(pkgPath, "repo") {
-		return LayerRepo
+
+	if layer, ok := h.matchSegments(segments); ok {
+		return layer
 	}
 
-	// Default to service layer if no specific layer detected
-	return LayerService
-}
-
-// containsLayerKeyword checks if a path contains a layer keyword
-func containsLayerKeyword(path, keyword string) bool {
-	// Simple check: look for /keyword/ or /keyword at end
-	if len(path) >= len(keyword) {
-		for i := 0; i <= len(path)-len(keyword); i++ {
-			if i+len(keyword) <= len(path) {
-				substr := path[i : i+len(keyword)]
-				if substr == keyword {
-					// Check if it's a word boundary
-					beforeOK := i == 0 || path[i-1] == '/' || path[i-1] == '\\'
-					afterOK := i+len(keyword) == len(path) || path[i+len(keyword)] == '/' || path[i+len(keyword)] == '\\'
-					if beforeOK && afterOK {
-						return true
-					}
-				}
+	// Default to the fallback layer if no specific layer detected
+	return h.fallback
+}
+
+// matchLayer returns the layer with a keyword among the path's segments,
+// reporting false when there is none
+func (h *layerHierarchy) matchLayer(pkgPath string) (LayerConvention, bool) {
+	return h.matchSegments(rules.LayerPathSegments(pkgPath))
+}
+
+// matchSegments returns the layer with a keyword among segments. Layers are
+// matched in order, so a higher layer wins on ambiguous paths.
+func (h *layerHierarchy) matchSegments(segments []string) (LayerConvention, bool) {
+	for _, level := range h.levels {
+		for _, keyword := range level.Keywords {
+			if slices.Contains(segments, keyword) {
+				return LayerConvention(level.Name), true
 			}
 		}
 	}
-	return false
+	return "", false
+}
+
+// layerNames returns the layer names from highest to lowest
+func (h *layerHierarchy) layerNames() []string {
+	names := make([]string, len(h.levels))
+	for i, level := range h.levels {
+		names[i] = level.Name
+	}
+	return names
 }
 
 // isUpwardImport checks if an import goes upward in the layer hierarchy
-func isUpwardImport(from, to LayerConvention) bool {
-	fromLevel, fromExists := layerOrder[from]
-	toLevel, toExists := layerOrder[to]
+func (h *layerHierarchy) isUpwardImport(from, to LayerConvention) bool {
+	fromLevel, fromExists := h.layerOrder[from]
+	toLevel, toExists := h.layerOrder[to]
 
 	if !fromExists || !toExists {
 		return false
@@ -5,6 +5,7 @@ import (
 	"RepoDoctor/internal/domain"
 	"RepoDoctor/internal/languages"
 	"RepoDoctor/internal/model"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -17,11 +18,11 @@ const version = "0.5.0-dev"
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
-		os.Exit(1)
+		os.Exit(exitCodeFailure)
 	}
 
-	if err := executeCommand(os.Args[1], os.Args[2:]); err != nil {
-		ExitWithError(err)
+	if code := runCLI(os.Args[1], os.Args[2:], executeCommand); code != 0 {
+		os.Exit(code)
 	}
 }
 
@@ -39,6 +40,21 @@ func executeCommand(cmd string, args []string) error {
 	case "history":
 		return handleHistoryCommand(args)
 
+	case "diff":
+		return handleDiffCommand(args)
+
+	case "bisect":
+		return handleBisectCommand(args)
+
+	case "layers":
+		return handleLayersCommand(args)
+
+	case "rules":
+		return handleRulesCommand(args)
+
+	case "cache":
+		return handleCacheCommand(args)
+
 	case "interactive":
 		return handleInteractiveCommand()
 
@@ -64,148 +80,39 @@ func executeCommand(cmd string, args []string) error {
 }
 
 func handleAnalyzeCommand(args []string) error {
-	req, err := composeAnalyzeRequest(args)
+	req, err := parseAnalyzeFlags(args)
 	if err != nil {
+		if envFormatRequested(args) {
+			emitEnvError(string(FormatEnv), err)
+		}
 		return err
 	}
 
-	if req.watch {
-		runWatch(req.path)
+	switch req.Mode {
+	case analyzeWatch:
+		runWatch(req.Path)
 		return nil
+	case analyzeGraphOnly:
+		return NewAnalysisService().RunGraphOnly(*req)
+	case analyzeStdinTargets:
+		return runAnalyzeTargets(os.Stdin, req)
 	}
 
-	runAnalyze(req.path, req.format, req.verbose, req.colorEnabled, true)
+	req.ExitOnViolation = true
+	NewAnalysisService().Run(*req)
 	return nil
 }
 
-type analyzeCommandRequest struct {
-	path         string
-	format       string
-	verbose      bool
-	colorEnabled bool
-	watch        bool
-}
-
-func composeAnalyzeRequest(args []string) (*analyzeCommandRequest, error) {
-	parsed, err := parseAnalyzeFlags(args)
-	if err != nil {
-		return nil, err
-	}
-
-	resolvedPath := resolveAnalyzePathArg(args, parsed.pathFlag, parsed.positional)
-	normalizedPath, normalizeErr := normalizeAnalyzePathInput(resolvedPath)
-	if normalizeErr != nil {
-		return nil, normalizeErr
-	}
-
-	return &analyzeCommandRequest{
-		path:         normalizedPath,
-		format:       parsed.outputFormat,
-		verbose:      parsed.verbose,
-		colorEnabled: !parsed.noColor,
-		watch:        parsed.watch,
-	}, nil
-}
-
-type analyzeFlagInput struct {
-	pathFlag     string
-	outputFormat string
-	verbose      bool
-	watch        bool
-	noColor      bool
-	positional   []string
-}
-
-func parseAnalyzeFlags(args []string) (*analyzeFlagInput, error) {
-	analyzeCmd := flag.NewFlagSet("analyze", flag.ContinueOnError)
-	analyzeCmd.SetOutput(os.Stderr)
-
-	path := analyzeCmd.String("path", ".", "Path to analyze")
-	format := analyzeCmd.String("format", "text", "Output format (text, json, json-v1)")
-	verbose := analyzeCmd.Bool("verbose", false, "Enable verbose output")
-	jsonOut := analyzeCmd.Bool("json", false, "Output in JSON format")
-	watch := analyzeCmd.Bool("watch", false, "Enable watch mode for continuous analysis")
-	noColor := analyzeCmd.Bool("no-color", false, "Disable colored output")
-
-	if err := analyzeCmd.Parse(args); err != nil {
-		return nil, NewCLIError(
-			ErrorCLIUsage,
-			fmt.Sprintf("Invalid analyze arguments: %v", err),
-			"Run 'repodoctor help' to review analyze command usage",
-			err,
-		)
-	}
-
-	outputFormat := *format
-	if *jsonOut {
-		outputFormat = "json"
-	}
-
-	return &analyzeFlagInput{
-		pathFlag:     *path,
-		outputFormat: outputFormat,
-		verbose:      *verbose,
-		watch:        *watch,
-		noColor:      *noColor,
-		positional:   analyzeCmd.Args(),
-	}, nil
-}
-
-func normalizeAnalyzePathInput(pathArg string) (string, error) {
-	if strings.TrimSpace(pathArg) == "" {
-		return "", NewCLIError(
-			ErrorInvalidArgument,
-			"Analyze path cannot be empty",
-			"Provide a valid repository path with -path or positional argument",
-			nil,
-		)
-	}
-
-	cleaned := filepath.Clean(pathArg)
-	absPath, err := filepath.Abs(cleaned)
-	if err != nil {
-		return "", HandleInvalidPathError(pathArg, err)
-	}
-	absPath = filepath.Clean(absPath)
-
-	if resolvedPath, err := filepath.EvalSymlinks(absPath); err == nil {
-		return filepath.Clean(resolvedPath), nil
-	}
-
-	return absPath, nil
-}
-
-func resolveAnalyzePathArg(rawArgs []string, pathFlag string, positional []string) string {
-	if hasExplicitPathFlag(rawArgs) {
-		return pathFlag
-	}
-
-	if len(positional) > 0 {
-		return positional[0]
-	}
-
-	return pathFlag
-}
-
-func hasExplicitPathFlag(rawArgs []string) bool {
-	for _, arg := range rawArgs {
-		if arg == "-path" || arg == "--path" || strings.HasPrefix(arg, "-path=") || strings.HasPrefix(arg, "--path=") {
-			return true
-		}
-	}
-
-	return false
-}
-
 func handleExtractCommand(args []string) error {
 	extractCmd := flag.NewFlagSet("extract", flag.ExitOnError)
 	path := extractCmd.String("path", ".", "Path to extract imports from")
 	module := extractCmd.String("module", "RepoDoctor", "Module path for normalization")
 	verbose := extractCmd.Bool("verbose", false, "Enable verbose output")
 	jsonOut := extractCmd.Bool("json", false, "Output in JSON format")
+	ascii := extractCmd.Bool("ascii", false, "Print plain ASCII, without box drawing and emoji")
 	extractCmd.Parse(args)
 
-	return runExtract(*path, *module, *verbose, *jsonOut)
+	return runExtract(*path, *module, *verbose, *jsonOut, asciiOutput(*ascii))
 }
 
 func handleReportCommand(args []string) error {
@@ -231,6 +138,25 @@ func handleHistoryCommand(args []string) error {
 	return runHistory(*path)
 }
 
+func handleLayersCommand(args []string) error {
+	layersCmd := flag.NewFlagSet("layers", flag.ExitOnError)
+	path := layersCmd.String("path", ".", "Path to repository")
+	format := layersCmd.String("format", "text", "Output format (text, json)")
+	layersCmd.Parse(args)
+
+	return runLayers(*path, *format)
+}
+
+func handleRulesCommand(args []string) error {
+	rulesCmd := flag.NewFlagSet("rules", flag.ExitOnError)
+	path := rulesCmd.String("path", ".", "Path to repository")
+	format := rulesCmd.String("format", "text", "Output format (text, json)")
+	effective := rulesCmd.Bool("effective", false, "Print the per-directory rule matrix after applying rule profiles")
+	rulesCmd.Parse(args)
+
+	return runRules(*path, *format, *effective)
+}
+
 func handleInteractiveCommand() error {
 	runInteractive()
 	return nil
@@ -254,7 +180,7 @@ func handleHelpCommand() error {
 }
 
 func getCommandSuggestion(cmd string) string {
-	commands := []string{"analyze", "extract", "report", "history", "interactive", "generate", "version", "help"}
+	commands := []string{"analyze", "extract", "report", "history", "diff", "bisect", "layers", "rules", "cache", "interactive", "generate", "version", "help"}
 	closest := ""
 	for _, candidate := range commands {
 		if strings.HasPrefix(candidate, strings.ToLower(cmd[:min(1, len(cmd))])) || strings.Contains(candidate, strings.ToLower(cmd)) {
@@ -275,8 +201,8 @@ func min(a, b int) int {
 	return b
 }
 
-func printUsage() {
-	fmt.Println(`RepoDoctor - Static Architecture Intelligence for Go Repositories
+// usageText is the help printed by "repodoctor help"
+const usageText = `RepoDoctor - Static Architecture Intelligence for Go Repositories
 
 Usage:
   repodoctor <command> [options]
@@ -286,23 +212,81 @@ Commands:
   extract      Extract Go package imports from source files
   report       Display existing analysis report
   history      Show score trend history
+  diff         Compare a base and a head report
+  bisect       Find the commit that regressed the score between two revisions
+  layers       List detected packages grouped by layer
+  rules        List rules, or the per-directory rule matrix with -effective
+  cache warm   Parse the repository into .repodoctor/cache without analyzing
   interactive  Start interactive mode for guided analysis
   generate     Generate rule templates and other files
   version      Show version information
   help         Show this help message
 
+Global options:
+  -debug       Let a crash print Go's stack trace instead of writing a crash report
+
 Arguments:
   analyze [options]
-    -path      Directory path to analyze (default: current directory)
-    -format    Output format: text, json, json-v1 (default: text)
+    -path      Directory path to analyze (default: current directory); "-" reads
+               one directory per line from stdin and analyzes each in turn
+    -format    Output format: text, json, json-v1, json-legacy, env, fixplan, sarif, junit, html, tree, tree-json, mermaid, checkstyle, markdown, jsonl, ndjson, tap, badge (default: text)
+               env prints shell-evaluable REPODOCTOR_* lines for eval in CI scripts
+               json prints the deprecated legacy format unless output.default_json is v1;
+               json-legacy always prints it and is removed one release after json;
+               several comma-separated formats (e.g. text,json) need -output
+    -output    Write each format but text to <output>.<ext> (e.g. report.json, report.sarif)
+               from the same run; text is still printed, and history records one entry
     -verbose   Enable verbose output
     -watch     Enable watch mode for continuous analysis
     -no-color  Disable colored output (default: enabled)
+    -graph-only  Print dependency graph statistics only, skipping rules and history
+    -width     Force the text report width (default: terminal width, fallback 100)
+    -base-path Report file paths relative to this directory (default: the analyzed directory)
+    -print-score  Print only the numeric total score; the exit code still reflects violations
+    -top       List at most N violations per category in the text report, worst first;
+               totals and the score still cover every violation (default: 0, all)
+    -group-by  Add violation counts per dir (top-level directory) or package to the text report
+    -explain   Explain the score: each category's weight and penalty, as a SCORE EXPLANATION
+               section in text output and an explanation object in json and json-v1
+    -ascii     Print text and tree output in plain ASCII (->, -, [OK]) instead of box drawing,
+               arrows and emoji; on by default when LC_ALL, LC_CTYPE or LANG is not UTF-8
+    -only      Run only these rules, comma-separated (e.g. size,god-object); overrides config
+    -skip      Skip these rules, comma-separated; cannot be combined with -only
+    -self-check  Verify report counts and penalties are consistent before printing
+    -out-rule  Write one rule's violations to a file as <rule>:<format>:<path> (repeatable)
+    -force-history-entry  Always append a history entry, even if identical to a recent one
+    -no-largest  Omit the largest files and functions (JSON, and text with -verbose)
+    -include-test-edges  Let cycle and layer rules follow imports of Go test files
+    -include-tests  Let the size and god object rules check Go test files, which
+               they skip by default
+    -tests-only  Let the size and god object rules check Go test files only
+    -sample    Run per-file rules on a deterministic fraction of files (e.g. 0.2); not recorded in history
+    -seed      Seed for -sample (default: 0)
+    -depth     Directory levels shown by -format tree (default: 0, the whole tree)
+    -fail-under  Exit with 2 only when the score is below this floor, whatever the
+               violations (default: 0, exit with 1 on critical violations)
+    -fail-on   Violations that exit with 1: none (never), critical (cycles), high
+               (cycles and layer violations, the default), any (every violation,
+               size and god objects included) or score<N (same as -fail-under N)
+    -quiet     Suppress notices on stderr, such as the legacy json deprecation
+    -fail-on-timeout  Exit with 5 when a rule hits its rules.timeouts entry; by default
+               the rule reports no violations and the run continues
+    -fail-on-regression  Exit with 7 when the score is more than history.regression_threshold
+               below the average of the last history.regression_window runs
+    -deterministic  Fix timestamps, zero rule durations and sort every violation list,
+               so repeated runs on the same files print byte-identical output
+    -min-severity  Report only violations of this severity or higher: info, warning
+               (size, god objects), error (layer) or critical (cycles), or of this
+               section level or higher: low (size), medium (god objects) or high
+               (layer). The score and exit code still count every violation
+    -abs-paths Report absolute file paths instead of paths relative to the analyzed
+               directory
 
   extract [options]
     -path      Directory path to extract imports from (default: current directory)
     -module    Module path for import normalization (default: RepoDoctor)
     -verbose   Enable verbose output
+    -ascii     Print plain ASCII instead of box drawing and emoji (default when the locale is not UTF-8)
 
   report [options]
     -path      Path to JSON report file (default: repodoctor-report.json)
@@ -311,15 +295,53 @@ Arguments:
   history [options]
     -path      Path to repository (default: current directory)
 
+  diff [options]
+    -base      Report of the base revision (json, json-v1 or latest.json)
+    -head      Report of the head revision
+    -format    Output format: text, json, markdown (default: text)
+               json prints {added, removed, scoreDelta, pass}; exits 1 unless pass
+
+  bisect [options]
+    -good      Revision known to be good
+    -bad       Revision known to be bad
+    -threshold Score below which a commit is bad (default: the good commit's score)
+    -path      Path inside the git repository to analyze (default: current directory)
+    -format    Output format: text, json (default: text)
+               A commit is also bad when it adds a critical violation
+
+  layers [options]
+    -path      Path to repository (default: current directory)
+    -format    Output format: text, json (default: text)
+
+  rules [options]
+    -path      Path to repository (default: current directory)
+    -effective Print which rules are active in each package directory
+    -format    Output format for -effective: text, json (default: text)
+
+  cache warm [options]
+    -path      Path to repository (default: current directory)
+               Prints cache entries, bytes and the hit rate of an existing cache
+
 Examples:
   repodoctor analyze .
   repodoctor analyze -path ./myproject -format json
   repodoctor analyze -path . --json
+  repodoctor analyze -graph-only -format json .
+  SCORE=$(repodoctor analyze -print-score .)
+  find repos -maxdepth 1 -mindepth 1 -type d | repodoctor analyze -format json -
+  eval "$(repodoctor analyze -format env .)"
   repodoctor extract .
   repodoctor extract -path ./src -module github.com/myorg/myrepo
   repodoctor report -path ./report.json
   repodoctor history -path .
-  repodoctor version`)
+  repodoctor diff -base base.json -head head.json -format json
+  repodoctor layers -path . -format json
+  repodoctor rules -effective -path .
+  repodoctor cache warm -path .
+  repodoctor version`
+
+func printUsage() {
+	fmt.Println(usageText)
 }
 
 func runAnalyze(path, format string, verbose bool, colorEnabled bool, exitOnViolation bool) int {
@@ -328,52 +350,74 @@ func runAnalyze(path, format string, verbose bool, colorEnabled bool, exitOnViol
 		Path:            path,
 		Format:          format,
 		Verbose:         verbose,
-		ColorEnabled:    colorEnabled,
 		ExitOnViolation: exitOnViolation,
+		Report:          ReportOptions{ColorEnabled: colorEnabled},
 	})
 }
 
 // determineExitCode returns the appropriate exit code based on report
 // 0 = success (no violations)
-// 2 = critical violations (circular dependencies or layer violations)
+// 1 = violations at the -fail-on level, by default critical ones (circular
+// dependencies or layer violations)
+// 2 = with -fail-under, a score below the floor, whatever the violations
+// 5 = with -fail-on-timeout, a rule timed out
+// 7 = with -fail-on-regression, a score below the average of recent runs
 func determineExitCode(report *StructuralReport) int {
+	// A rule that timed out leaves the analysis incomplete
+	if report.Metrics.Exit.FailOnTimeout && len(timedOutRules(report.Metrics.Rules)) > 0 {
+		return exitCodeFailure
+	}
+	if report.Metrics.Exit.FailOnRegression && regressionDetected(report) {
+		return exitCodeScoreRegression
+	}
+	if floor := report.Metrics.Exit.FailUnder; floor > 0 {
+		if reportTotalScore(report) < floor {
+			return exitCodeScoreBelowFloor
+		}
+		return exitCodeClean
+	}
 	if !report.HasViolations {
-		return 0
+		return exitCodeClean
 	}
 
-	// Critical violations: circular dependencies or layer violations
-	if len(report.Circular) > 0 || len(report.Layer) > 0 {
-		return 2
+	// By default critical violations fail: layer violations, and circular
+	// dependencies unless circular.baseline tolerates them. Non-critical
+	// warnings (size/god-object) only fail with -fail-on any.
+	if report.Metrics.Exit.failsOnViolations(report) {
+		return exitCodeViolations
 	}
-
-	// Non-critical warnings (size/god-object) should not fail CI pipelines.
-	return 0
+	return exitCodeClean
 }
 
 func validatePath(path string) string {
+	canonicalPath, cliErr := resolveDirectoryPath(path)
+	if cliErr != nil {
+		cliErr.Display()
+		os.Exit(exitCodePathError)
+	}
+	return canonicalPath
+}
+
+// resolveDirectoryPath returns the canonical absolute path of an existing
+// directory, or the error validatePath would display
+func resolveDirectoryPath(path string) (string, *CLIError) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		cliErr := HandleInvalidPathError(path, err)
-		cliErr.Display()
-		os.Exit(1)
+		return "", HandleInvalidPathError(path, err)
 	}
 
 	info, err := os.Stat(absPath)
 	if err != nil {
-		cliErr := HandleFileNotFoundError(absPath, err)
-		cliErr.Display()
-		os.Exit(1)
+		return "", HandleFileNotFoundError(absPath, err)
 	}
 
 	if !info.IsDir() {
-		cliErr := NewCLIError(
+		return "", NewCLIError(
 			ErrorInvalidArgument,
 			fmt.Sprintf("Path is not a directory: %s", absPath),
 			"Provide a directory path instead of a file",
 			nil,
 		)
-		cliErr.Display()
-		os.Exit(1)
 	}
 
 	canonicalPath := absPath
@@ -381,12 +425,13 @@ func validatePath(path string) string {
 		canonicalPath = resolvedPath
 	}
 
-	return canonicalPath
+	return canonicalPath, nil
 }
 
 func extractImports(absPath string, verbose bool) map[string]*ImportMetadata {
 	moduleName := "RepoDoctor"
 	extractor := NewImportExtractor(moduleName)
+	extractor.Excludes = configExcludes(loadConfiguration(absPath, false))
 	imports, err := extractor.ExtractFromDir(absPath)
 	if err != nil && verbose {
 		fmt.Fprintf(os.Stderr, "%s", ColorWarn(fmt.Sprintf("Warning: error extracting imports: %v\n", err)))
@@ -395,6 +440,17 @@ func extractImports(absPath string, verbose bool) map[string]*ImportMetadata {
 }
 
 func runAdapterPipeline(absPath string) (*analysis.Result, error) {
+	return newAnalysisOrchestrator(absPath).Analyze(absPath)
+}
+
+func newAnalysisOrchestrator(absPath string) *analysis.Orchestrator {
+	return newAnalysisOrchestratorWithTestEdges(absPath, false)
+}
+
+// newAnalysisOrchestratorWithTestEdges creates the orchestrator; with
+// includeTestEdges the Go adapter adds test-file imports to the graph
+// whatever graph.test_edges says
+func newAnalysisOrchestratorWithTestEdges(absPath string, includeTestEdges bool) *analysis.Orchestrator {
 	ignoreStrategy := domain.NewDefaultIgnoreStrategy(domain.DefaultIgnoredDirs)
 	config := loadConfiguration(absPath, false)
 	policy := languages.DetectionPolicy{}
@@ -404,13 +460,12 @@ func runAdapterPipeline(absPath string) (*analysis.Result, error) {
 		policy.SegmentWeights = config.LanguageDetection.SegmentWeights
 	}
 	detector := languages.NewRepositoryLanguageDetectorWithPolicy(ignoreStrategy, policy)
-	detector.RegisterAdapter(languages.NewGoAdapter())
+	detector.RegisterAdapter(languages.NewGoAdapterWithTestEdges(testEdgeModeFor(config, includeTestEdges)))
 	detector.RegisterAdapter(languages.NewPythonAdapter())
 	detector.RegisterAdapter(languages.NewJavaScriptAdapter())
 	detector.RegisterAdapter(languages.NewTypeScriptAdapter())
 
-	orchestrator := analysis.NewOrchestrator(detector)
-	return orchestrator.Analyze(absPath)
+	return analysis.NewOrchestrator(detector)
 }
 
 func buildDependencyGraphFromModel(languageGraph *model.DependencyGraph, verbose bool) Graph {
@@ -419,10 +474,15 @@ func buildDependencyGraphFromModel(languageGraph *model.DependencyGraph, verbose
 		return graph
 	}
 
+	// Edges inherit the provenance of the file that declares them
 	for _, node := range languageGraph.GetNodes() {
 		graph.AddNode(node.ID)
 		for _, dep := range languageGraph.GetDependencies(node.ID) {
-			graph.AddEdge(node.ID, dep)
+			if model.NodeProvenance(node) == model.EdgeFromTest {
+				graph.AddTestEdge(node.ID, dep)
+			} else {
+				graph.AddEdge(node.ID, dep)
+			}
 		}
 	}
 
@@ -431,7 +491,7 @@ func buildDependencyGraphFromModel(languageGraph *model.DependencyGraph, verbose
 			graph.GetNodeCount(), graph.GetEdgeCount())))
 	}
 
-	return graph
+	return withBlankImports(graph, languageGraph)
 }
 
 func buildDependencyGraph(imports map[string]*ImportMetadata, verbose bool) Graph {
@@ -475,51 +535,104 @@ func generateReport(scorer *StructuralScorer, absPath, format string, verbose bo
 		fmt.Println(reporter.Format(report))
 	} else {
 		// Use colored output for text format
-		var sb strings.Builder
-		writeHeaderWithColor(&sb, reporter.formatter)
-		writeScoreSectionWithColor(&sb, report, reporter.formatter)
-		writeViolationsSummaryWithColor(&sb, report, reporter.formatter)
-		writeCircularViolationsWithColor(&sb, report, reporter.formatter)
-		writeLayerViolationsWithColor(&sb, report, reporter.formatter)
-		writeSizeViolationsWithColor(&sb, report, reporter.formatter)
-		writeGodObjectViolationsWithColor(&sb, report, reporter.formatter)
-		writeScoreBreakdownWithColor(&sb, report, reporter.formatter)
-		fmt.Println(sb.String())
+		fmt.Println(reporter.FormatColoredText(report))
 	}
 	return report
 }
 
-func generateRuleEngineReport(absPath, format string, verbose bool, colorEnabled bool, cfg *Config, summary *runtimeRuleSummary) *StructuralReport {
+func generateRuleEngineReport(absPath string, request AnalyzeRequest, cfg *Config, summary *runtimeRuleSummary) (*StructuralReport, error) {
+	format, verbose := resolveJSONFormat(OutputFormat(request.Format), cfg), request.Verbose
 	report := buildReportFromRuleViolations(absPath, version, cfg, summary.result.Violations)
+	report.RuleSet = summary.ruleIDs
+	report.Metrics = ReportMetrics{Coverage: summary.result.Coverage, Cohesion: summary.cohesion, Dependencies: summary.dependencies, Sample: request.Options.Sample, Rules: summary.descriptors, ThirdParty: summary.thirdParty, RuleDurations: summary.result.Durations, Stats: summary.stats}
+	if !request.Options.NoLargest {
+		report.Metrics.Largest = summary.largest
+	}
+	report.Metrics.Cycles = evaluateCycleTolerance(report.Circular, absPath, cfg)
+	report.Metrics.Packages = PackageStructure{Coupling: computePackageCoupling(summary.graph, absPath, summary.files, couplingTopN), Orphans: findOrphanPackages(summary.graph, absPath, summary.files, orphanIgnoreFromConfig(cfg))}
+	report.Metrics.Density = computeViolationDensity(report, summary.stats.Lines, densityWeightsFromConfig(cfg))
+	report.Metrics.Exit = request.Options.Exit
+	report.Metrics.Trend.Regression = detectScoreRegression(absPath, report, cfg)
+	warnTimedOutRules(report, cfg)
+	annotateBlankImportCycles(report.Circular, summary.graph)
+	if request.Options.Deterministic {
+		canonicalizeReport(report)
+	}
+	for _, outputFormat := range reportOutputFormats(format, request.Report.Outputs) {
+		addFormatMetrics(report, outputFormat, absPath, cfg, request, summary)
+	}
+
+	if request.Options.SelfCheck || reportSelfCheck {
+		if err := verifyReportInvariants(report, scoringWeightsFromConfig(cfg)); err != nil {
+			return nil, err
+		}
+	}
 
 	if verbose {
 		fmt.Printf(ColorInfo("Rules in registry: ")+"%d\n", summary.rulesInScope)
 		fmt.Printf(ColorInfo("Rules executed: ")+"%d\n", summary.result.RulesExecuted)
+		fmt.Print(formatRuleCoverage(report.Metrics.Coverage))
+		fmt.Print(formatStructCohesion(report.Metrics.Cohesion))
+		fmt.Print(formatDependencyInventory(report.Metrics.Dependencies))
+		fmt.Print(formatLargestArtifacts(report.Metrics.Largest))
+		fmt.Print(formatThirdPartyCode(report.Metrics.ThirdParty, thirdPartyExcluded(cfg)))
+		fmt.Print(formatCycleTolerance(report.Metrics.Cycles))
 	}
 
-	reporter := NewColoredReporter(OutputFormat(format), colorEnabled)
-	if format == "json" {
-		fmt.Println(reporter.Format(report))
-	} else {
-		var sb strings.Builder
-		writeHeaderWithColor(&sb, reporter.formatter)
-		writeScoreSectionWithColor(&sb, report, reporter.formatter)
-		writeViolationsSummaryWithColor(&sb, report, reporter.formatter)
-		writeCircularViolationsWithColor(&sb, report, reporter.formatter)
-		writeLayerViolationsWithColor(&sb, report, reporter.formatter)
-		writeSizeViolationsWithColor(&sb, report, reporter.formatter)
-		writeGodObjectViolationsWithColor(&sb, report, reporter.formatter)
-		writeScoreBreakdownWithColor(&sb, report, reporter.formatter)
-		fmt.Println(sb.String())
+	if request.Report.PrintScore {
+		fmt.Println(formatScoreOnly(report))
+		return report, writeRequestOutputs(report, cfg, request)
 	}
 
-	return report
+	if request.Quiet {
+		return report, writeRequestOutputs(report, cfg, request)
+	}
+
+	writeLegacyJSONNotice(os.Stderr, format, request.Options.NoNotices)
+	if err := writeReport(os.Stdout, report, format, cfg, request); err != nil {
+		return nil, NewCLIError(ErrorRuntime, "Error writing report", "", err)
+	}
+
+	if err := writeRequestOutputs(report, cfg, request); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// newRequestReporter creates the reporter of a request's report, with the
+// request's width, path, listing and explanation settings
+func newRequestReporter(format OutputFormat, request AnalyzeRequest) *ColoredReporter {
+	reporter := NewColoredReporter(format, request.Report.ColorEnabled)
+	reporter.width = request.Report.Width
+	reporter.basePath = request.Report.BasePath
+	reporter.absPaths = request.Options.AbsPaths
+	reporter.minSeverity = request.Options.MinSeverity
+	reporter.listing = request.Report.Listing
+	reporter.explain = request.Report.Explain
+	reporter.ascii = request.Report.ASCII
+	return reporter
 }
 
-func handleTrendAnalysis(absPath string, report *StructuralReport, verbose bool) {
+// formatScoreOnly renders just the total score for scripting
+func formatScoreOnly(report *StructuralReport) string {
+	return fmt.Sprintf("%.1f", report.Score.TotalScore)
+}
+
+func handleTrendAnalysis(absPath string, report *StructuralReport, cfg *Config, request AnalyzeRequest) {
+	verbose := request.Verbose
 	trendAnalyzer := NewTrendAnalyzer(absPath)
-	if err := trendAnalyzer.LoadHistory(); err != nil && verbose {
-		fmt.Printf("%s", ColorWarn(fmt.Sprintf("Warning: could not load history: %v\n", err)))
+	trendAnalyzer.dedupeWindow = historyDedupeWindow(cfg)
+	trendAnalyzer.maxEntries = historyMaxEntries(cfg)
+	trendAnalyzer.scoreModel = report.Score.Model
+	trendAnalyzer.counts = &report.Summary
+	trendAnalyzer.now = runClock(request.Options.Deterministic)
+	if err := trendAnalyzer.LoadHistory(); err != nil {
+		fmt.Fprint(os.Stderr, ColorWarn(fmt.Sprintf("Warning: could not load history: %v\n", err)))
+		if !errors.Is(err, ErrCorruptHistory) {
+			// Saving now would overwrite the file that could not be read
+			return
+		}
 	}
 
 	if verbose {
@@ -527,7 +640,19 @@ func handleTrendAnalysis(absPath string, report *StructuralReport, verbose bool)
 		fmt.Println(ColorInfo(trendAnalyzer.GetTrendSummary(report.Score.TotalScore)))
 	}
 
-	if err := trendAnalyzer.AppendScore(report.Score.TotalScore); err != nil && verbose {
+	entry := newHistoryEntry(report)
+	entry.ConfigHash = configHash(cfg)
+	if report.Metrics.Dependencies != nil {
+		entry.ExternalModules = report.Metrics.Dependencies.Modules
+	}
+	if report.Metrics.Density != nil {
+		entry.Density = report.Metrics.Density.PerKLOC
+	}
+	deduped, err := trendAnalyzer.RecordEntry(entry, request.Options.ForceHistoryEntry)
+	if err != nil && verbose {
 		fmt.Printf("%s", ColorWarn(fmt.Sprintf("Warning: could not save to history: %v\n", err)))
 	}
+	if deduped && verbose {
+		fmt.Println(ColorInfo("History: identical recent entry refreshed instead of appended"))
+	}
 }
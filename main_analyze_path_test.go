@@ -55,24 +55,24 @@ func TestComposeAnalyzeRequest_PathParityVariants(t *testing.T) {
 		{name: "absolute", args: []string{"-path", abs}},
 	}
 
-	var baseline *analyzeCommandRequest
+	var baseline *AnalyzeRequest
 	for i, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			req, composeErr := composeAnalyzeRequest(tc.args)
+			req, composeErr := parseAnalyzeFlags(tc.args)
 			if composeErr != nil {
-				t.Fatalf("composeAnalyzeRequest failed: %v", composeErr)
+				t.Fatalf("parseAnalyzeFlags failed: %v", composeErr)
 			}
-			if req.format != "text" {
-				t.Fatalf("expected default format text, got %s", req.format)
+			if req.Format != "text" {
+				t.Fatalf("expected default format text, got %s", req.Format)
 			}
 			if i == 0 {
 				baseline = req
 				return
 			}
-			if req.path != baseline.path {
-				t.Fatalf("expected path parity, baseline=%q got=%q", baseline.path, req.path)
+			if req.Path != baseline.Path {
+				t.Fatalf("expected path parity, baseline=%q got=%q", baseline.Path, req.Path)
 			}
-			if req.format != baseline.format || req.verbose != baseline.verbose || req.colorEnabled != baseline.colorEnabled || req.watch != baseline.watch {
+			if req.Format != baseline.Format || req.Verbose != baseline.Verbose || req.Report.ColorEnabled != baseline.Report.ColorEnabled || req.Mode != baseline.Mode {
 				t.Fatalf("expected request parity across path forms")
 			}
 		})
@@ -80,21 +80,21 @@ func TestComposeAnalyzeRequest_PathParityVariants(t *testing.T) {
 }
 
 func TestComposeAnalyzeRequest_AllowsParentPathWhenExists(t *testing.T) {
-	req, err := composeAnalyzeRequest([]string{"-path", ".."})
+	req, err := parseAnalyzeFlags([]string{"-path", ".."})
 	if err != nil {
 		t.Fatalf("expected parent path to be allowed, got error: %v", err)
 	}
-	if req.path == "" {
+	if req.Path == "" {
 		t.Fatal("expected normalized path to be non-empty")
 	}
 }
 
 func TestComposeAnalyzeRequest_JSONFlagOverridesFormat(t *testing.T) {
-	req, err := composeAnalyzeRequest([]string{"-format", "text", "-json"})
+	req, err := parseAnalyzeFlags([]string{"-format", "text", "-json"})
 	if err != nil {
-		t.Fatalf("composeAnalyzeRequest failed: %v", err)
+		t.Fatalf("parseAnalyzeFlags failed: %v", err)
 	}
-	if req.format != "json" {
-		t.Fatalf("expected json flag to override format, got %s", req.format)
+	if req.Format != "json" {
+		t.Fatalf("expected json flag to override format, got %s", req.Format)
 	}
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatMarkdown prints the report as GitHub-flavored Markdown, for posting
+// as a pull request comment
+const FormatMarkdown OutputFormat = "markdown"
+
+// formatMarkdown renders an H2 header with the score, a table of violation
+// counts per category and one fenced list per category with violations.
+// Categories without violations are left out; a clean report says so in
+// one line instead. Paths are relative to the analyzed directory.
+func formatMarkdown(report *StructuralReport) string {
+	relative := func(file string) string {
+		return relativeToBase(file, report.Path)
+	}
+
+	var cycles, layer, featureIsolation, dependencyCap, size, godObjects []string
+	for _, v := range report.Circular {
+		cycle := make([]string, len(v.Path))
+		for i, file := range v.Path {
+			cycle[i] = relative(file)
+		}
+		line := formatCyclePath(cycle)
+		if note := cycleBlankImportNote(v, relative); note != "" {
+			line += " (" + note + ")"
+		}
+		cycles = append(cycles, line)
+	}
+	for _, v := range report.Layer {
+		layer = append(layer, fmt.Sprintf("%s → %s: %s", relative(v.From), relative(v.To), v.Message))
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		featureIsolation = append(featureIsolation, relative(v.From)+": "+v.Message)
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		dependencyCap = append(dependencyCap, relative(v.Manifest)+": "+v.Message)
+	}
+	for _, v := range report.Size {
+		size = append(size, relative(v.File)+": "+sizeViolationMessage(v))
+	}
+	for _, v := range report.GodObject {
+		godObjects = append(godObjects, relative(v.File)+": "+godObjectViolationMessage(v))
+	}
+
+	total := len(cycles) + len(layer) + len(featureIsolation) + len(dependencyCap) + len(size) + len(godObjects)
+	var sb strings.Builder
+	if score := report.Score; score != nil {
+		sb.WriteString(fmt.Sprintf("## RepoDoctor Score: %.1f / %.1f\n\n", score.TotalScore, scoreScale(score)))
+	} else {
+		sb.WriteString("## RepoDoctor Report\n\n")
+	}
+
+	sb.WriteString("| Category | Violations |\n")
+	sb.WriteString("|---|---:|\n")
+	sb.WriteString(fmt.Sprintf("| Circular dependencies | %d |\n", len(cycles)))
+	sb.WriteString(fmt.Sprintf("| Layer violations | %d |\n", len(layer)))
+	if len(featureIsolation) > 0 {
+		sb.WriteString(fmt.Sprintf("| Feature isolation violations | %d |\n", len(featureIsolation)))
+	}
+	if len(dependencyCap) > 0 {
+		sb.WriteString(fmt.Sprintf("| Dependency cap violations | %d |\n", len(dependencyCap)))
+	}
+	sb.WriteString(fmt.Sprintf("| Size violations | %d |\n", len(size)))
+	sb.WriteString(fmt.Sprintf("| God objects | %d |\n", len(godObjects)))
+	sb.WriteString(fmt.Sprintf("| **Total** | **%d** |\n\n", total))
+
+	if total == 0 {
+		sb.WriteString("No structural violations found.\n")
+		return sb.String()
+	}
+
+	writeMarkdownViolations(&sb, "Circular dependencies", cycles)
+	writeMarkdownViolations(&sb, "Layer violations", layer)
+	writeMarkdownViolations(&sb, "Feature isolation violations", featureIsolation)
+	writeMarkdownViolations(&sb, "Dependency cap violations", dependencyCap)
+	writeMarkdownViolations(&sb, "Size violations", size)
+	writeMarkdownViolations(&sb, "God objects", godObjects)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// writeMarkdownViolations writes a titled, numbered list in a fenced block.
+// Backticks are replaced so a path cannot close the fence.
+func writeMarkdownViolations(sb *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("### %s (%d)\n\n", title, len(lines)))
+	sb.WriteString("```\n")
+	for i, line := range lines {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, strings.ReplaceAll(line, "`", "'")))
+	}
+	sb.WriteString("```\n\n")
+}
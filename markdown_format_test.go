@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatMarkdown_TableAndViolationLists(t *testing.T) {
+	report := &StructuralReport{
+		Path:     "/repo",
+		Score:    &StructuralScore{TotalScore: 82.5, MaxScore: 100, CircularCount: 1, SizeCount: 1, ViolationCount: 2},
+		Circular: []CycleViolation{{Path: []string{"/repo/a/a.go", "/repo/b/b.go"}}},
+		Size:     []SizeViolation{{File: "/repo/gen/`odd`.go", Lines: 600, Threshold: 500}},
+	}
+
+	out := NewReporter(FormatMarkdown).Format(report)
+	for _, want := range []string{
+		"## RepoDoctor Score: 82.5 / 100.0\n",
+		"| Category | Violations |\n|---|---:|\n",
+		"| Circular dependencies | 1 |\n",
+		"| **Total** | **2** |\n",
+		"### Circular dependencies (1)\n\n```\n1. a/a.go → b/b.go → a/a.go\n```\n",
+		"### Size violations (1)\n\n```\n1. gen/'odd'.go: ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in markdown report:\n%s", want, out)
+		}
+	}
+	for _, empty := range []string{"### Layer violations", "### God objects", "No structural violations"} {
+		if strings.Contains(out, empty) {
+			t.Errorf("expected %q to be left out:\n%s", empty, out)
+		}
+	}
+	if strings.Count(out, "```") != 4 {
+		t.Errorf("expected backticks in paths not to break the fences:\n%s", out)
+	}
+}
+
+func TestFormatMarkdown_CleanRepo(t *testing.T) {
+	out := NewReporter(FormatMarkdown).Format(&StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 100, MaxScore: 100}})
+	if !strings.Contains(out, "| Category | Violations |\n") || !strings.Contains(out, "| **Total** | **0** |\n") {
+		t.Fatalf("expected the counts table, got:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "\nNo structural violations found.\n") || strings.Contains(out, "###") {
+		t.Fatalf("expected only a no-violations line after the table, got:\n%s", out)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FormatMermaid prints the package dependency graph as a Mermaid diagram
+const FormatMermaid OutputFormat = "mermaid"
+
+// defaultMermaidMaxNodes is the number of packages a diagram shows unless
+// output.mermaid_max_nodes sets another cap
+const defaultMermaidMaxNodes = 50
+
+// mermaidCycleLinkStyle is the link style of edges within a dependency cycle
+const mermaidCycleLinkStyle = "stroke:#d62728,stroke-width:2px"
+
+// PackageDiagram is the package graph the mermaid format draws. Packages
+// and edges are sorted; Total counts the packages before the node cap.
+type PackageDiagram struct {
+	Packages []string
+	Edges    []PackageEdge
+	Total    int
+}
+
+// PackageEdge is an import between two packages. Cycle is set when both
+// packages lie on the same dependency cycle.
+type PackageEdge struct {
+	From  string
+	To    string
+	Cycle bool
+}
+
+// mermaidMaxNodes returns output.mermaid_max_nodes, or the default cap
+func mermaidMaxNodes(cfg *Config) int {
+	if cfg != nil && cfg.Output != nil && cfg.Output.MermaidMaxNodes > 0 {
+		return cfg.Output.MermaidMaxNodes
+	}
+	return defaultMermaidMaxNodes
+}
+
+// buildPackageDiagram collapses the graph to packages like the COUPLING
+// section and keeps the maxNodes packages with the highest degree (in plus
+// out), breaking ties by package. Edges between kept packages are drawn;
+// an edge is a cycle edge when its packages share a strongly connected
+// component of the full package graph.
+func buildPackageDiagram(graph Graph, absPath string, files []string, maxNodes int) *PackageDiagram {
+	packages := buildPackageGraph(graph, absPath, files)
+	nodes := packages.GetAllNodes()
+	adjacency := make(map[string][]string, len(nodes))
+	for _, node := range nodes {
+		adjacency[node] = packages.GetDependencies(node)
+	}
+	componentOf := make(map[string]int, len(nodes))
+	for i, component := range stronglyConnectedComponents(nodes, adjacency) {
+		for _, node := range component {
+			componentOf[node] = i
+		}
+	}
+
+	kept := append([]string(nil), nodes...)
+	if len(kept) > maxNodes {
+		degree := func(node string) int { return packages.GetInDegree(node) + packages.GetOutDegree(node) }
+		sort.SliceStable(kept, func(i, j int) bool { return degree(kept[i]) > degree(kept[j]) })
+		kept = kept[:maxNodes]
+		sort.Strings(kept)
+	}
+	shown := make(map[string]bool, len(kept))
+	for _, node := range kept {
+		shown[node] = true
+	}
+
+	diagram := &PackageDiagram{Packages: kept, Total: len(nodes)}
+	for _, from := range kept {
+		for _, to := range adjacency[from] {
+			if shown[to] {
+				diagram.Edges = append(diagram.Edges, PackageEdge{From: from, To: to, Cycle: componentOf[from] == componentOf[to]})
+			}
+		}
+	}
+	return diagram
+}
+
+// formatMermaidDiagram renders the diagram as a Mermaid graph TD block.
+// Node IDs follow the sorted package order, so the output is stable, and
+// cycle edges are labeled and drawn with mermaidCycleLinkStyle.
+func formatMermaidDiagram(diagram *PackageDiagram) string {
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	if diagram == nil {
+		return sb.String()
+	}
+	if len(diagram.Packages) < diagram.Total {
+		sb.WriteString(fmt.Sprintf("    %%%% %d of %d packages with the highest degree\n", len(diagram.Packages), diagram.Total))
+	}
+
+	ids := make(map[string]string, len(diagram.Packages))
+	for i, pkg := range diagram.Packages {
+		ids[pkg] = fmt.Sprintf("p%d", i)
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", ids[pkg], strings.ReplaceAll(pkg, `"`, "#quot;")))
+	}
+
+	var cycleLinks []string
+	for i, edge := range diagram.Edges {
+		if edge.Cycle {
+			sb.WriteString(fmt.Sprintf("    %s -->|cycle| %s\n", ids[edge.From], ids[edge.To]))
+			cycleLinks = append(cycleLinks, fmt.Sprint(i))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("    %s --> %s\n", ids[edge.From], ids[edge.To]))
+	}
+	if len(cycleLinks) > 0 {
+		sb.WriteString(fmt.Sprintf("    linkStyle %s %s\n", strings.Join(cycleLinks, ","), mermaidCycleLinkStyle))
+	}
+	return sb.String()
+}
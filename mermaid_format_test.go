@@ -0,0 +1,73 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mermaidFixture is a module with a layered chain cmd/tool -> handler ->
+// service -> repository and a cycle between billing and ledger, which
+// service also imports
+func mermaidFixture(t *testing.T) (string, Graph, []string) {
+	t.Helper()
+	root := t.TempDir()
+	writeServiceFixture(t, root, map[string]string{"go.mod": "module example.com/shop\n"})
+	file := func(rel string) string { return filepath.Join(root, filepath.FromSlash(rel)) }
+	imports := map[string][]string{
+		"cmd/tool/main.go":                {"example.com/shop/internal/handler", "fmt"},
+		"internal/handler/handler.go":     {"example.com/shop/internal/service"},
+		"internal/service/service.go":     {"example.com/shop/internal/repository", "example.com/shop/internal/billing"},
+		"internal/repository/repo.go":     {"database/sql"},
+		"internal/billing/invoice.go":     {"example.com/shop/internal/ledger"},
+		"internal/ledger/ledger.go":       {"example.com/shop/internal/billing"},
+		"internal/ledger/ledger_entry.go": {"example.com/shop/internal/ledger"},
+	}
+	graph := NewDependencyGraph()
+	var files []string
+	for _, rel := range sortedKeys(imports) {
+		graph.AddNode(file(rel))
+		files = append(files, file(rel))
+		for _, imp := range imports[rel] {
+			graph.AddEdge(file(rel), imp)
+		}
+	}
+	return root, graph, files
+}
+
+func TestMermaidFormat_Golden(t *testing.T) {
+	root, graph, files := mermaidFixture(t)
+
+	assertGolden(t, "mermaid.mmd", formatMermaidDiagram(buildPackageDiagram(graph, root, files, defaultMermaidMaxNodes)))
+	assertGolden(t, "mermaid.max3.mmd", formatMermaidDiagram(buildPackageDiagram(graph, root, files, 3)))
+}
+
+func TestMermaidFormat_MarksOnlyCycleEdges(t *testing.T) {
+	root, graph, files := mermaidFixture(t)
+	diagram := buildPackageDiagram(graph, root, files, defaultMermaidMaxNodes)
+
+	if len(diagram.Packages) != 6 || diagram.Total != 6 {
+		t.Fatalf("expected the 6 analyzed packages, got %v of %d", diagram.Packages, diagram.Total)
+	}
+	for _, edge := range diagram.Edges {
+		inCycle := strings.HasSuffix(edge.From, "billing") && strings.HasSuffix(edge.To, "ledger") || strings.HasSuffix(edge.From, "ledger") && strings.HasSuffix(edge.To, "billing")
+		if edge.Cycle != inCycle {
+			t.Errorf("edge %s -> %s: expected cycle=%v", edge.From, edge.To, inCycle)
+		}
+	}
+	if out := formatMermaidDiagram(nil); out != "graph TD\n" {
+		t.Errorf("expected an empty diagram without data, got %q", out)
+	}
+}
+
+func TestMermaidMaxNodesConfig(t *testing.T) {
+	if got := mermaidMaxNodes(&Config{}); got != defaultMermaidMaxNodes {
+		t.Errorf("expected the default cap %d, got %d", defaultMermaidMaxNodes, got)
+	}
+	if got := mermaidMaxNodes(&Config{Output: &OutputConfig{MermaidMaxNodes: 12}}); got != 12 {
+		t.Errorf("expected output.mermaid_max_nodes to set the cap, got %d", got)
+	}
+	if err := validateOutputConfig(&OutputConfig{MermaidMaxNodes: -1}); err == nil {
+		t.Error("expected a negative output.mermaid_max_nodes to be rejected")
+	}
+}
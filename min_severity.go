@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// sectionLevel ranks the text report's violation sections by their label:
+// size violations are low, god objects medium, layer, feature isolation and
+// dependency cap violations high and cycles critical
+type sectionLevel int
+
+const (
+	sectionLow sectionLevel = iota + 1
+	sectionMedium
+	sectionHigh
+	sectionCritical
+)
+
+// sectionLevelNames are the -min-severity values that filter by section
+// label. critical is parsed as the rule severity, which cycles report.
+var sectionLevelNames = map[string]sectionLevel{
+	"low":    sectionLow,
+	"medium": sectionMedium,
+	"high":   sectionHigh,
+}
+
+// severityThreshold is a parsed -min-severity value. A rule severity keeps
+// the violations whose rule reported that severity or higher; a section
+// level keeps the sections labelled that level or higher. The zero value
+// keeps every violation.
+type severityThreshold struct {
+	severity model.Severity
+	section  sectionLevel
+}
+
+// parseMinSeverity parses the -min-severity value: a rule severity (info,
+// warning, error or critical) or a section level (low, medium or high).
+// Empty keeps every violation.
+func parseMinSeverity(value string) (severityThreshold, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return severityThreshold{}, nil
+	}
+	if section, ok := sectionLevelNames[normalized]; ok {
+		return severityThreshold{section: section}, nil
+	}
+	severity, err := model.ParseSeverity(normalized)
+	if err != nil {
+		return severityThreshold{}, NewCLIError(
+			ErrorCLIUsage,
+			fmt.Sprintf("Invalid -min-severity value %q", value),
+			"Use a severity (info, warning, error or critical) or a section level (low, medium or high)",
+			err,
+		)
+	}
+	return severityThreshold{severity: severity}, nil
+}
+
+// severityOr returns severity, or fallback when the violation carries none,
+// as in reports built by hand rather than from rule output
+func severityOr(severity, fallback model.Severity) model.Severity {
+	if severity == 0 {
+		return fallback
+	}
+	return severity
+}
+
+// atLeast returns the violations of a section at level that meet threshold:
+// none when the section is below its level, otherwise those whose severity
+// is threshold or higher
+func atLeast[T any](violations []T, threshold severityThreshold, level sectionLevel, severity func(T) model.Severity) []T {
+	if violations == nil || level < threshold.section {
+		return nil
+	}
+	kept := make([]T, 0, len(violations))
+	for _, v := range violations {
+		if severity(v) >= threshold.severity {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// filterReportBySeverity returns a copy of the report without the violations
+// below threshold. Each violation is judged by the severity its rule
+// reported, falling back to the rule's default, and by the level of its
+// section. Summary counts the violations left and how many were filtered
+// out. Score, HasViolations and the advisories are left alone, so the score
+// and exit code still account for everything.
+func filterReportBySeverity(report *StructuralReport, threshold severityThreshold) *StructuralReport {
+	if report == nil || threshold == (severityThreshold{}) {
+		return report
+	}
+
+	out := *report
+	out.Circular = atLeast(report.Circular, threshold, sectionCritical, func(v CycleViolation) model.Severity {
+		return severityOr(v.Severity, model.SeverityCritical)
+	})
+	out.Layer = atLeast(report.Layer, threshold, sectionHigh, func(v LayerViolation) model.Severity {
+		return severityOr(v.Severity, model.SeverityError)
+	})
+	out.OptIn.FeatureIsolation = atLeast(report.OptIn.FeatureIsolation, threshold, sectionHigh, func(v FeatureIsolationViolation) model.Severity {
+		return severityOr(v.Severity, model.SeverityError)
+	})
+	out.OptIn.DependencyCap = atLeast(report.OptIn.DependencyCap, threshold, sectionHigh, func(v DependencyCapViolation) model.Severity {
+		return severityOr(v.Severity, model.SeverityError)
+	})
+	out.Size = atLeast(report.Size, threshold, sectionLow, func(v SizeViolation) model.Severity {
+		return severityOr(v.Severity, model.SeverityWarning)
+	})
+	out.GodObject = atLeast(report.GodObject, threshold, sectionMedium, func(v GodObjectViolation) model.Severity {
+		return severityOr(v.Severity, model.SeverityWarning)
+	})
+
+	shown := len(out.Circular) + len(out.Layer) + len(out.OptIn.FeatureIsolation) + len(out.OptIn.DependencyCap) + len(out.Size) + len(out.GodObject)
+	all := len(report.Circular) + len(report.Layer) + len(report.OptIn.FeatureIsolation) + len(report.OptIn.DependencyCap) + len(report.Size) + len(report.GodObject)
+	out.Summary = ReportSummary{
+		TotalViolations:  shown,
+		Circular:         len(out.Circular),
+		Layer:            len(out.Layer),
+		FeatureIsolation: len(out.OptIn.FeatureIsolation),
+		DependencyCap:    len(out.OptIn.DependencyCap),
+		Size:             len(out.Size),
+		GodObject:        len(out.GodObject),
+		Filtered:         report.Summary.Filtered + all - shown,
+		Omitted:          report.Summary.Omitted,
+	}
+	return &out
+}
+
+// formatFilteredNote tells the text report's reader that violations were
+// left out by -min-severity
+func formatFilteredNote(filtered int) string {
+	return fmt.Sprintf("  (%d below -min-severity not listed)", filtered)
+}
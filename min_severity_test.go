@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func minSeverityFixtureReport() *StructuralReport {
+	return &StructuralReport{
+		Path:          "/repo",
+		Score:         &StructuralScore{TotalScore: 70, MaxScore: 100, ViolationCount: 5, CircularCount: 1, LayerCount: 1, SizeCount: 2, GodObjectCount: 1},
+		Circular:      []CycleViolation{{Path: []string{"/repo/a.go", "/repo/b.go"}}},
+		Layer:         []LayerViolation{{From: "/repo/repo/s.go", To: "/repo/handler/h.go", Message: "upward"}},
+		Size:          []SizeViolation{{File: "/repo/big.go", Lines: 600, Threshold: 500}, {File: "/repo/huge.go", Lines: 900, Threshold: 500}},
+		GodObject:     []GodObjectViolation{{StructName: "Server", File: "/repo/server.go", MethodCount: 20}},
+		Summary:       ReportSummary{TotalViolations: 5, Circular: 1, Layer: 1, Size: 2, GodObject: 1},
+		HasViolations: true,
+	}
+}
+
+func TestFilterReportBySeverity_Thresholds(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ReportSummary
+	}{
+		{value: "", want: ReportSummary{TotalViolations: 5, Circular: 1, Layer: 1, Size: 2, GodObject: 1}},
+		{value: "info", want: ReportSummary{TotalViolations: 5, Circular: 1, Layer: 1, Size: 2, GodObject: 1}},
+		{value: "low", want: ReportSummary{TotalViolations: 5, Circular: 1, Layer: 1, Size: 2, GodObject: 1}},
+		{value: "warning", want: ReportSummary{TotalViolations: 5, Circular: 1, Layer: 1, Size: 2, GodObject: 1}},
+		{value: "medium", want: ReportSummary{TotalViolations: 3, Circular: 1, Layer: 1, GodObject: 1, Filtered: 2}},
+		{value: "error", want: ReportSummary{TotalViolations: 2, Circular: 1, Layer: 1, Filtered: 3}},
+		{value: "high", want: ReportSummary{TotalViolations: 2, Circular: 1, Layer: 1, Filtered: 3}},
+		{value: "Critical", want: ReportSummary{TotalViolations: 1, Circular: 1, Filtered: 4}},
+	}
+	for _, tc := range tests {
+		threshold, err := parseMinSeverity(tc.value)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.value, err)
+		}
+		report := minSeverityFixtureReport()
+		filtered := filterReportBySeverity(report, threshold)
+		if filtered.Summary != tc.want {
+			t.Errorf("%q: expected summary %+v, got %+v", tc.value, tc.want, filtered.Summary)
+		}
+		if len(filtered.Size) != tc.want.Size || len(filtered.GodObject) != tc.want.GodObject || len(filtered.Layer) != tc.want.Layer {
+			t.Errorf("%q: expected the lists to match the summary, got %+v", tc.value, filtered)
+		}
+		if !filtered.HasViolations || filtered.Score.ViolationCount != 5 || len(report.Size) != 2 {
+			t.Errorf("%q: expected the score, HasViolations and the original report to stay untouched", tc.value)
+		}
+	}
+
+	if _, err := parseMinSeverity("severe"); err == nil {
+		t.Error("expected an unknown -min-severity value to be rejected")
+	}
+}
+
+func TestFilterReportBySeverity_MediumDropsSizeButKeepsGodObjects(t *testing.T) {
+	threshold, err := parseMinSeverity("medium")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report := minSeverityFixtureReport()
+	// Both default to warning, so only the section level tells them apart
+	report.Size[0].Severity = model.SeverityWarning
+	report.GodObject[0].Severity = model.SeverityWarning
+
+	filtered := filterReportBySeverity(report, threshold)
+	if len(filtered.Size) != 0 || len(filtered.GodObject) != 1 {
+		t.Errorf("expected medium to drop size violations and keep god objects, got %d size and %d god objects", len(filtered.Size), len(filtered.GodObject))
+	}
+}
+
+func TestFilterReportBySeverity_PerViolation(t *testing.T) {
+	report := minSeverityFixtureReport()
+	report.Circular = append(report.Circular, CycleViolation{Path: []string{"/repo/c.go", "/repo/d.go"}, Severity: model.SeverityWarning})
+	report.Layer = append(report.Layer, LayerViolation{From: "/repo/x.go", Message: "critical", Severity: model.SeverityCritical})
+	report.Size[0].Severity = model.SeverityError
+	report.Summary.Omitted = ListingOmissions{Size: 3}
+
+	filtered := filterReportBySeverity(report, severityThreshold{severity: model.SeverityError})
+	want := ReportSummary{TotalViolations: 4, Circular: 1, Layer: 2, Size: 1, Filtered: 3, Omitted: ListingOmissions{Size: 3}}
+	if filtered.Summary != want {
+		t.Errorf("expected summary %+v, got %+v", want, filtered.Summary)
+	}
+	if len(filtered.Size) != 1 || filtered.Size[0].File != "/repo/big.go" {
+		t.Errorf("expected only the size violation raised to error to stay, got %+v", filtered.Size)
+	}
+	if len(filtered.Circular) != 1 || filtered.Circular[0].Path[0] != "/repo/a.go" {
+		t.Errorf("expected the cycle reported as a warning to be filtered, got %+v", filtered.Circular)
+	}
+
+	critical := filterReportBySeverity(report, severityThreshold{severity: model.SeverityCritical})
+	if len(critical.Layer) != 1 || critical.Layer[0].Message != "critical" {
+		t.Errorf("expected the critical layer violation to stay, got %+v", critical.Layer)
+	}
+}
+
+func TestReporter_MinSeverityOutput(t *testing.T) {
+	reporter := NewReporter(FormatJSONV1)
+	reporter.minSeverity = severityThreshold{severity: model.SeverityError}
+	var doc struct {
+		Violations struct {
+			Size     int `json:"size"`
+			Filtered int `json:"filtered"`
+		} `json:"violations"`
+		SizeViolations []json.RawMessage `json:"sizeViolations"`
+	}
+	if err := json.Unmarshal([]byte(reporter.Format(minSeverityFixtureReport())), &doc); err != nil {
+		t.Fatalf("invalid json-v1: %v", err)
+	}
+	if doc.Violations.Filtered != 3 || doc.Violations.Size != 2 || len(doc.SizeViolations) != 0 {
+		t.Errorf("expected 3 filtered violations and no size list, got %+v", doc)
+	}
+
+	text := &Reporter{format: FormatText, width: 80, minSeverity: severityThreshold{severity: model.SeverityError}}
+	out := text.Format(minSeverityFixtureReport())
+	if !strings.Contains(out, "(3 below -min-severity not listed)") || strings.Contains(out, "SIZE VIOLATIONS") {
+		t.Errorf("expected the text report to note the filtered violations, got:\n%s", out)
+	}
+	if out := NewReporter(FormatText).Format(minSeverityFixtureReport()); strings.Contains(out, "-min-severity") {
+		t.Errorf("expected no note without -min-severity, got:\n%s", out)
+	}
+}
+
+func TestAnalysisService_MinSeverityKeepsExitCode(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+	writeServiceFixture(t, root, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n",
+		"repo/store.go": "package repo\n\nimport _ \"example.com/app/handler\"\n",
+		"handler/h.go":  "package handler\n",
+	})
+
+	exitCode := 0
+	out := captureStdout(t, func() {
+		exitCode = NewAnalysisService().Run(AnalyzeRequest{Path: root, Format: string(FormatJSON), Options: AnalyzeOptions{MinSeverity: severityThreshold{severity: model.SeverityCritical}}})
+	})
+	if exitCode != 1 {
+		t.Fatalf("expected the hidden layer violation to still exit with 1, got %d", exitCode)
+	}
+	start := strings.Index(out, "{")
+	var doc struct {
+		Summary ReportSummary `json:"summary"`
+		Score   struct {
+			Total float64 `json:"total"`
+		} `json:"score"`
+	}
+	if start < 0 || json.NewDecoder(strings.NewReader(out[start:])).Decode(&doc) != nil {
+		t.Fatalf("expected a json report, got:\n%s", out)
+	}
+	if doc.Summary.Layer != 0 || doc.Summary.Filtered != 1 || doc.Score.Total != 95 {
+		t.Errorf("expected the layer violation filtered but still scored, got %+v", doc)
+	}
+}
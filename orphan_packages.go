@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// OrphansConfig configures the ORPHAN PACKAGES section
+type OrphansConfig struct {
+	// Ignore holds package directory globs, such as cmd/*, that are never
+	// reported. A glob matching a directory also covers the packages below.
+	Ignore []string `yaml:"ignore,omitempty"`
+}
+
+func validateOrphansConfig(orphans *OrphansConfig) error {
+	if orphans == nil {
+		return nil
+	}
+	for i, pattern := range orphans.Ignore {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("orphans.ignore[%d] %q is not a valid glob: %w", i, pattern, err)
+		}
+	}
+	return nil
+}
+
+// orphanIgnoreFromConfig returns orphans.ignore
+func orphanIgnoreFromConfig(cfg *Config) []string {
+	if cfg == nil || cfg.Orphans == nil {
+		return nil
+	}
+	return cfg.Orphans.Ignore
+}
+
+// findOrphanPackages returns the packages of the package graph that no
+// other analyzed package imports, as potential dead code. Main packages are
+// entry points and never reported, and neither are directories holding
+// only test files or packages matching an ignore glob.
+func findOrphanPackages(graph Graph, absPath string, files []string, ignore []string) []string {
+	sources := sourcePackages(absPath, files)
+	var orphans []string
+	for _, pkg := range buildPackageGraph(graph, absPath, files).GetOrphanNodes() {
+		isMain, ok := sources[pkg]
+		if ok && !isMain && !matchesPackageGlob(pkg, ignore) {
+			orphans = append(orphans, pkg)
+		}
+	}
+	return orphans
+}
+
+// sourcePackages maps the directory of every analyzed non-test Go file,
+// relative to absPath, to whether it holds a main package
+func sourcePackages(absPath string, files []string) map[string]bool {
+	fset := token.NewFileSet()
+	packages := make(map[string]bool)
+	for _, file := range files {
+		if !filepath.IsAbs(file) || !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		rel, err := filepath.Rel(absPath, filepath.Dir(file))
+		if err != nil {
+			continue
+		}
+		dir := filepath.ToSlash(rel)
+		isMain := packages[dir]
+		if node, err := parser.ParseFile(fset, file, nil, parser.PackageClauseOnly); err == nil {
+			isMain = isMain || node.Name.Name == "main"
+		}
+		packages[dir] = isMain
+	}
+	return packages
+}
+
+// matchesPackageGlob reports whether a glob matches pkg or one of its
+// parent directories
+func matchesPackageGlob(pkg string, globs []string) bool {
+	for candidate := pkg; ; candidate = path.Dir(candidate) {
+		for _, glob := range globs {
+			if ok, _ := path.Match(glob, candidate); ok {
+				return true
+			}
+		}
+		if !strings.Contains(candidate, "/") {
+			return false
+		}
+	}
+}
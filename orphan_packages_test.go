@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDependencyGraph_GetOrphanNodes(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddEdge("app", "api")
+	graph.AddEdge("api", "store")
+	graph.AddEdge("legacy", "legacy")
+	graph.AddNode("unused")
+	graph.AddTestEdge("e2e", "unused")
+
+	want := []string{"app", "e2e", "legacy", "unused"}
+	if got := graph.GetOrphanNodes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected orphans %v, got %v", want, got)
+	}
+	if got := NewDependencyGraph().GetOrphanNodes(); len(got) != 0 {
+		t.Fatalf("expected no orphans in an empty graph, got %v", got)
+	}
+}
+
+// orphanFixture has one leaf package, internal/legacy, that nothing
+// imports, next to entry points, a test-only directory and an SDK package
+// that are not dead code
+func orphanFixture() map[string]string {
+	return map[string]string{
+		"go.mod":                    "module example.com/app\n\ngo 1.24\n",
+		"cmd/app/main.go":           "package main\n\nimport \"example.com/app/internal/api\"\n\nfunc main() { api.Serve() }\n",
+		"tools/gen/gen.go":          "package main\n\nfunc main() {}\n",
+		"internal/api/api.go":       "package api\n\nimport \"example.com/app/internal/store\"\n\nfunc Serve() { store.Open() }\n",
+		"internal/store/store.go":   "package store\n\nfunc Open() {}\n",
+		"internal/legacy/legacy.go": "package legacy\n\nfunc Old() {}\n",
+		"e2e/app_test.go":           "package e2e_test\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n",
+		"pkg/sdk/sdk.go":            "package sdk\n\nfunc Client() {}\n",
+	}
+}
+
+func TestAnalysisService_ReportsOrphanPackages(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	files := orphanFixture()
+	writeServiceFixture(t, root, files)
+
+	report, _ := NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatJSONV1), Quiet: true})
+	if report == nil {
+		t.Fatal("expected a report")
+	}
+	if want := []string{"internal/legacy", "pkg/sdk"}; !reflect.DeepEqual(report.Metrics.Packages.Orphans, want) {
+		t.Fatalf("expected orphans %v, got %v", want, report.Metrics.Packages.Orphans)
+	}
+
+	files[".repodoctor/config.yaml"] = "orphans:\n  ignore: [pkg/*]\n"
+	writeServiceFixture(t, root, files)
+	report, _ = NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatJSONV1), Quiet: true})
+	if want := []string{"internal/legacy"}; !reflect.DeepEqual(report.Metrics.Packages.Orphans, want) {
+		t.Fatalf("expected orphans.ignore to drop pkg/sdk, got %v", report.Metrics.Packages.Orphans)
+	}
+}
+
+func TestMatchesPackageGlob(t *testing.T) {
+	tests := []struct {
+		pkg  string
+		want bool
+	}{
+		{pkg: "cmd/tool", want: true},
+		{pkg: "cmd/tool/internal/flags", want: true},
+		{pkg: "main", want: true},
+		{pkg: "internal/cmd", want: false},
+		{pkg: ".", want: false},
+	}
+	for _, tc := range tests {
+		if got := matchesPackageGlob(tc.pkg, []string{"cmd/*", "main"}); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.pkg, tc.want, got)
+		}
+	}
+	if err := validateOrphansConfig(&OrphansConfig{Ignore: []string{"cmd/["}}); err == nil {
+		t.Error("expected an invalid glob to be rejected")
+	}
+}
+
+func TestOrphanPackages_ShownInTextAndJSONReports(t *testing.T) {
+	report := &StructuralReport{
+		Path:    "demo",
+		Score:   &StructuralScore{TotalScore: 100, MaxScore: 100},
+		Metrics: ReportMetrics{Packages: PackageStructure{Orphans: []string{"internal/legacy"}}},
+	}
+
+	for _, text := range []string{NewReporter(FormatText).Format(report), NewColoredReporter(FormatText, false).FormatColoredText(report)} {
+		if !strings.Contains(text, "ORPHAN PACKAGES [INFO]") || !strings.Contains(text, "[1] internal/legacy is not imported by any analyzed package") {
+			t.Errorf("expected an ORPHAN PACKAGES section, got:\n%s", text)
+		}
+	}
+
+	var legacy struct {
+		Metrics struct {
+			OrphanPackages []string `json:"orphanPackages"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONLegacy).Format(report)), &legacy); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	var v1 struct {
+		OrphanPackages []string `json:"orphanPackages"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &v1); err != nil {
+		t.Fatalf("invalid json-v1: %v", err)
+	}
+	if !reflect.DeepEqual(legacy.Metrics.OrphanPackages, []string{"internal/legacy"}) || !reflect.DeepEqual(v1.OrphanPackages, []string{"internal/legacy"}) {
+		t.Errorf("expected orphanPackages in both json formats, got %v and %v", legacy.Metrics.OrphanPackages, v1.OrphanPackages)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"RepoDoctor/internal/domain"
+	"RepoDoctor/internal/rules"
+)
+
+// parseCacheVersion changes whenever ParsedGoFile changes shape; cache files
+// of another version are ignored
+const parseCacheVersion = 6
+
+// parseCacheDocument is the on-disk form of the parse cache. Paths are
+// slash-separated and relative to the repository root, so a cache baked into
+// a container image stays valid wherever the checkout is mounted.
+type parseCacheDocument struct {
+	Version int                 `json:"version"`
+	Entries []rules.CachedParse `json:"entries"`
+}
+
+// CacheWarmStats describes the cache after warming. HitRate is the share of
+// files served from a pre-existing cache, or nil when there was none.
+type CacheWarmStats struct {
+	Entries int
+	Bytes   int64
+	HitRate *float64
+}
+
+// parseCachePath returns the location of the persisted parse cache
+func parseCachePath(baseDir string) string {
+	return filepath.Join(baseDir, ".repodoctor", "cache", "parse.json")
+}
+
+// cacheableFilter accepts repository files that belong in the persisted
+// cache: files inside the repository, outside excluded and hidden
+// directories, and not matched by the root .gitignore
+func cacheableFilter(absPath string) func(file string) bool {
+	ignore := readGitignore(filepath.Join(absPath, ".gitignore"))
+	return func(file string) bool {
+		rel, ok := repositoryRelPath(absPath, file)
+		if !ok {
+			return false
+		}
+		return !inIgnoredDir(rel) && !ignore.matches(rel)
+	}
+}
+
+// inIgnoredDir reports whether the slash-separated relative path rel lies in
+// a hidden directory or one the directory walkers skip by default
+func inIgnoredDir(rel string) bool {
+	for _, segment := range strings.Split(path.Dir(rel), "/") {
+		if (strings.HasPrefix(segment, ".") && segment != ".") || slices.Contains(domain.DefaultIgnoredDirs, segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// repositoryRelPath returns file relative to absPath with slashes, or false
+// when file lies outside the repository
+func repositoryRelPath(absPath, file string) (string, bool) {
+	if !filepath.IsAbs(file) {
+		return "", false
+	}
+	rel, err := filepath.Rel(absPath, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// loadParseCache restores the persisted cache into cache and returns the
+// number of restored entries. A missing, unreadable or outdated cache file
+// restores nothing.
+func loadParseCache(absPath string, cache *rules.ParseCache) int {
+	data, err := os.ReadFile(parseCachePath(absPath))
+	if err != nil {
+		return 0
+	}
+	var doc parseCacheDocument
+	if err := json.Unmarshal(data, &doc); err != nil || doc.Version != parseCacheVersion {
+		return 0
+	}
+
+	for i := range doc.Entries {
+		doc.Entries[i].Path = filepath.Join(absPath, filepath.FromSlash(doc.Entries[i].Path))
+	}
+	cache.Restore(doc.Entries)
+	return len(doc.Entries)
+}
+
+// saveParseCache persists the cacheable entries of cache and returns the
+// size of the written file. The file is replaced atomically.
+func saveParseCache(absPath string, cache *rules.ParseCache) (int64, error) {
+	entries := cache.Snapshot(cacheableFilter(absPath))
+	for i := range entries {
+		rel, _ := repositoryRelPath(absPath, entries[i].Path)
+		entries[i].Path = rel
+	}
+	data, err := json.Marshal(parseCacheDocument{Version: parseCacheVersion, Entries: entries})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal parse cache: %w", err)
+	}
+
+	target := parseCachePath(absPath)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".parse-*.json")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary parse cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to write parse cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write parse cache: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write parse cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return 0, fmt.Errorf("failed to replace parse cache: %w", err)
+	}
+	return int64(len(data)), nil
+}
+
+// persistParseCache saves the parse cache after an analysis when
+// persist_parse_cache is set. The cache only saves parse time, so a write
+// failure, for example in a read-only checkout, is a notice on stderr and
+// never fails the run.
+func persistParseCache(absPath string, cfg *Config, request AnalyzeRequest) {
+	if cfg == nil || !cfg.PersistParseCache {
+		return
+	}
+	if _, err := saveParseCache(absPath, rules.SharedParseCache()); err != nil && !request.Options.NoNotices {
+		fmt.Fprint(os.Stderr, ColorWarn(fmt.Sprintf("Warning: could not save the parse cache: %v\n", err)))
+	}
+}
+
+// warmParseCache runs extraction and parsing only, so the persisted cache
+// holds every file a later analyze run parses. No rules run and no report
+// or history entry is written.
+func warmParseCache(absPath string, cache *rules.ParseCache) (*CacheWarmStats, error) {
+	restored := loadParseCache(absPath, cache)
+	before := cache.Stats()
+
+	result, err := newAnalysisOrchestrator(absPath).AnalyzeGraph(absPath)
+	if err != nil {
+		return nil, WrapError(err, ErrorAnalysis, "Dependency graph extraction failed", GetSuggestion(err.Error()))
+	}
+	graph := buildDependencyGraphFromModel(result.Graph, false)
+
+	keep := cacheableFilter(absPath)
+	var files []rules.RepositoryFile
+	for _, file := range buildRulesAnalysisContext(absPath, graph, nil).RepositoryFiles {
+		if keep(file.Path) {
+			files = append(files, file)
+		}
+	}
+	cache.ParseAll(files)
+
+	bytes, err := saveParseCache(absPath, cache)
+	if err != nil {
+		return nil, WrapError(err, ErrorRuntime, "Failed to write the parse cache", "Check that the .repodoctor directory is writable")
+	}
+
+	stats := &CacheWarmStats{Entries: len(cache.Snapshot(keep)), Bytes: bytes}
+	after := cache.Stats()
+	if lookups := (after.Hits - before.Hits) + (after.Misses - before.Misses); restored > 0 && lookups > 0 {
+		rate := float64(after.Hits-before.Hits) / float64(lookups)
+		stats.HitRate = &rate
+	}
+	return stats, nil
+}
+
+// handleCacheCommand runs cache subcommands; warm is the only one
+func handleCacheCommand(args []string) error {
+	if len(args) == 0 || args[0] != "warm" {
+		return NewCLIError(ErrorCLIUsage, "Unknown cache subcommand", "Usage: repodoctor cache warm -path .", nil)
+	}
+	warmCmd := flag.NewFlagSet("cache warm", flag.ExitOnError)
+	path := warmCmd.String("path", ".", "Path to repository")
+	warmCmd.Parse(args[1:])
+
+	stats, err := warmParseCache(validatePath(*path), rules.SharedParseCache())
+	if err != nil {
+		return err
+	}
+	fmt.Print(formatCacheWarmStats(stats))
+	return nil
+}
+
+// formatCacheWarmStats renders the cache statistics printed by cache warm
+func formatCacheWarmStats(stats *CacheWarmStats) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Entries:  %d\n", stats.Entries))
+	sb.WriteString(fmt.Sprintf("Bytes:    %d\n", stats.Bytes))
+	if stats.HitRate != nil {
+		sb.WriteString(fmt.Sprintf("Hit rate: %.1f%%\n", *stats.HitRate*100))
+	}
+	return sb.String()
+}
+
+// gitignorePatterns holds the patterns of a .gitignore file. Negated
+// patterns are not supported and are skipped.
+type gitignorePatterns []string
+
+// readGitignore reads a .gitignore file; a missing file ignores nothing
+func readGitignore(file string) gitignorePatterns {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var patterns gitignorePatterns
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// matches reports whether a slash-separated repository path is ignored.
+// Patterns containing a slash are anchored at the root; others match any
+// path element. A trailing slash matches directories only.
+func (p gitignorePatterns) matches(rel string) bool {
+	segments := strings.Split(rel, "/")
+	for _, pattern := range p {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+		anchored := strings.Contains(pattern, "/")
+		pattern = strings.TrimPrefix(pattern, "/")
+		for i := range segments {
+			if dirOnly && i == len(segments)-1 {
+				break
+			}
+			candidate := segments[i]
+			if anchored {
+				candidate = strings.Join(segments[:i+1], "/")
+			}
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/rules"
+)
+
+// writeCacheFixture writes a small Go module; files maps slash-separated
+// relative paths to their content
+func writeCacheFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.22\n"
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+	return dir
+}
+
+func TestCacheWarm_AnalyzeParsesNoFilesAfterWarming(t *testing.T) {
+	dir := writeCacheFixture(t, map[string]string{
+		"main.go":         "package main\n\nimport \"example.com/app/store\"\n\nfunc main() { store.Open() }\n",
+		"store/store.go":  "package store\n\ntype Store struct{ path string }\n\nfunc Open() *Store { return &Store{} }\n",
+		"store/reader.go": "package store\n\nfunc (s *Store) Read() string { return s.path }\n",
+	})
+
+	stats, err := warmParseCache(dir, rules.NewParseCache())
+	if err != nil {
+		t.Fatalf("warm failed: %v", err)
+	}
+	if stats.Entries != 3 || stats.Bytes == 0 || stats.HitRate != nil {
+		t.Fatalf("unexpected warm stats: %+v", stats)
+	}
+
+	// A fresh process starts with an empty in-memory cache
+	shared := rules.SharedParseCache()
+	shared.Reset()
+	defer shared.Reset()
+
+	result, err := runAdapterPipeline(dir)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	runInternalRulePipeline(dir, buildDependencyGraphFromModel(result.Graph, false), (&ConfigLoader{}).getDefaultConfig(), nil, nil)
+
+	if got := shared.Stats(); got.Misses != 0 || got.Hits == 0 {
+		t.Fatalf("expected every file to come from the warmed cache, got %+v", got)
+	}
+
+	rewarmed, err := warmParseCache(dir, rules.NewParseCache())
+	if err != nil {
+		t.Fatalf("second warm failed: %v", err)
+	}
+	if rewarmed.HitRate == nil || *rewarmed.HitRate != 1 {
+		t.Fatalf("expected a 100%% hit rate when warming again, got %+v", rewarmed)
+	}
+}
+
+func TestCacheWarm_RespectsExcludesAndGitignore(t *testing.T) {
+	dir := writeCacheFixture(t, map[string]string{
+		".gitignore":           "# generated code\ngen/\n/tmp_*.go\n",
+		"main.go":              "package main\n\nfunc main() {}\n",
+		"tmp_scratch.go":       "package main\n\nfunc scratch() {}\n",
+		"gen/api/api.go":       "package api\n\nfunc Generated() {}\n",
+		"vendor/lib/lib.go":    "package lib\n\nfunc Vendored() {}\n",
+		"internal/gen_util.go": "package internal\n\nfunc Util() {}\n",
+	})
+
+	if _, err := warmParseCache(dir, rules.NewParseCache()); err != nil {
+		t.Fatalf("warm failed: %v", err)
+	}
+
+	data, err := os.ReadFile(parseCachePath(dir))
+	if err != nil {
+		t.Fatalf("expected a cache file: %v", err)
+	}
+	var doc parseCacheDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid cache file: %v", err)
+	}
+	var paths []string
+	for _, entry := range doc.Entries {
+		paths = append(paths, entry.Path)
+	}
+	if want := []string{"internal/gen_util.go", "main.go"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("expected cached files %v, got %v", want, paths)
+	}
+}
+
+func TestPersistParseCache_WrittenOnlyWhenEnabled(t *testing.T) {
+	dir := t.TempDir()
+	persistParseCache(dir, &Config{}, AnalyzeRequest{})
+	if _, err := os.Stat(parseCachePath(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected no parse cache unless persist_parse_cache is set, got %v", err)
+	}
+
+	persistParseCache(dir, &Config{PersistParseCache: true}, AnalyzeRequest{})
+	if _, err := os.Stat(parseCachePath(dir)); err != nil {
+		t.Fatalf("expected a parse cache with persist_parse_cache set: %v", err)
+	}
+}
+
+func TestPersistParseCache_WriteFailureIsANotice(t *testing.T) {
+	dir := t.TempDir()
+	// A file where the cache directory belongs makes the write fail
+	if err := os.WriteFile(filepath.Join(dir, ".repodoctor"), nil, 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	stderr := os.Stderr
+	capture, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatalf("failed to create stderr capture: %v", err)
+	}
+	os.Stderr = capture
+	persistParseCache(dir, &Config{PersistParseCache: true}, AnalyzeRequest{})
+	persistParseCache(dir, &Config{PersistParseCache: true}, AnalyzeRequest{Options: AnalyzeOptions{NoNotices: true}})
+	os.Stderr = stderr
+	capture.Close()
+
+	out, _ := os.ReadFile(capture.Name())
+	if got := strings.Count(string(out), "could not save the parse cache"); got != 1 {
+		t.Fatalf("expected one notice, silenced by NoNotices, got:\n%s", out)
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"unicode"
+)
+
+// PenaltyExpr is a parsed penalty curve such as "min(15, count * 2)". The
+// grammar is deliberately tiny: numbers, the variable count, + - * /,
+// parentheses and the functions min and max. Nothing else is accepted.
+type PenaltyExpr struct {
+	source string
+	root   exprNode
+}
+
+// exprNode is one node of a parsed penalty expression
+type exprNode interface {
+	eval(count float64) (float64, error)
+}
+
+type numberNode float64
+
+type countNode struct{}
+
+type unaryMinusNode struct{ operand exprNode }
+
+type binaryNode struct {
+	op          byte
+	left, right exprNode
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+// penaltyFunctions are the only callable functions in penalty expressions
+var penaltyFunctions = map[string]func(a, b float64) float64{
+	"min": math.Min,
+	"max": math.Max,
+}
+
+// ParsePenaltyExpr parses a penalty curve expression
+func ParsePenaltyExpr(source string) (*PenaltyExpr, error) {
+	p := &exprParser{input: source}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok != "" {
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok, p.pos-len(tok))
+	}
+	return &PenaltyExpr{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression for a violation count
+func (e *PenaltyExpr) Eval(count int) (float64, error) {
+	value, err := e.root.eval(float64(count))
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("expression %q is not finite for count %d", e.source, count)
+	}
+	return value, nil
+}
+
+// String returns the expression source
+func (e *PenaltyExpr) String() string {
+	return e.source
+}
+
+func (n numberNode) eval(float64) (float64, error) { return float64(n), nil }
+
+func (countNode) eval(count float64) (float64, error) { return count, nil }
+
+func (n unaryMinusNode) eval(count float64) (float64, error) {
+	value, err := n.operand.eval(count)
+	return -value, err
+}
+
+func (n binaryNode) eval(count float64) (float64, error) {
+	left, err := n.left.eval(count)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(count)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return left / right, nil
+	}
+}
+
+func (n callNode) eval(count float64) (float64, error) {
+	result, err := n.args[0].eval(count)
+	if err != nil {
+		return 0, err
+	}
+	for _, arg := range n.args[1:] {
+		value, err := arg.eval(count)
+		if err != nil {
+			return 0, err
+		}
+		result = penaltyFunctions[n.name](result, value)
+	}
+	return result, nil
+}
+
+// exprParser is a recursive-descent parser over the expression source
+type exprParser struct {
+	input string
+	pos   int
+}
+
+// next consumes and returns the next token, or "" at the end of input
+func (p *exprParser) next() string {
+	tok := p.peek()
+	p.pos += len(tok)
+	return tok
+}
+
+// peek skips whitespace and returns the next token without consuming it.
+// Tokens are numbers, identifiers and single-character operators.
+func (p *exprParser) peek() string {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		return ""
+	}
+
+	end := p.pos + 1
+	switch c := rune(p.input[p.pos]); {
+	case unicode.IsDigit(c) || c == '.':
+		for end < len(p.input) && (unicode.IsDigit(rune(p.input[end])) || p.input[end] == '.') {
+			end++
+		}
+	case unicode.IsLetter(c) || c == '_':
+		for end < len(p.input) && (unicode.IsLetter(rune(p.input[end])) || unicode.IsDigit(rune(p.input[end])) || p.input[end] == '_') {
+			end++
+		}
+	}
+	return p.input[p.pos:end]
+}
+
+// parseSum parses term (('+' | '-') term)*
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	for err == nil {
+		op := p.peek()
+		if op != "+" && op != "-" {
+			return left, nil
+		}
+		p.next()
+		var right exprNode
+		if right, err = p.parseProduct(); err == nil {
+			left = binaryNode{op: op[0], left: left, right: right}
+		}
+	}
+	return nil, err
+}
+
+// parseProduct parses unary (('*' | '/') unary)*
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	for err == nil {
+		op := p.peek()
+		if op != "*" && op != "/" {
+			return left, nil
+		}
+		p.next()
+		var right exprNode
+		if right, err = p.parseUnary(); err == nil {
+			left = binaryNode{op: op[0], left: left, right: right}
+		}
+	}
+	return nil, err
+}
+
+// parseUnary parses '-' unary | primary
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek() == "-" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryMinusNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a number, count, a function call or a parenthesised sum
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	offset := p.pos - len(tok)
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing != ")" {
+			return nil, fmt.Errorf("expected ')' at offset %d", p.pos-len(closing))
+		}
+		return inner, nil
+	case tok == "count":
+		return countNode{}, nil
+	case penaltyFunctions[tok] != nil:
+		return p.parseCall(tok)
+	}
+
+	// Only digit tokens are numbers; ParseFloat alone would accept "inf"
+	value, err := strconv.ParseFloat(tok, 64)
+	if err != nil || !unicode.IsDigit(rune(tok[0])) && tok[0] != '.' {
+		return nil, fmt.Errorf("invalid token %q at offset %d (allowed: numbers, count, min, max, + - * / and parentheses)", tok, offset)
+	}
+	return numberNode(value), nil
+}
+
+// parseCall parses the argument list of min or max; at least two are required
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	if open := p.next(); open != "(" {
+		return nil, fmt.Errorf("expected '(' after %s", name)
+	}
+
+	var args []exprNode
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		switch sep := p.next(); sep {
+		case ",":
+			continue
+		case ")":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("%s needs at least two arguments", name)
+			}
+			return callNode{name: name, args: args}, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or ')' in %s arguments", name)
+		}
+	}
+}
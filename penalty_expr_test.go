@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPenaltyExpr_Eval(t *testing.T) {
+	tests := []struct {
+		source string
+		count  int
+		want   float64
+	}{
+		{"min(15, count * 2)", 3, 6},
+		{"min(15, count * 2)", 10, 15},
+		{"max(0, count - 2) * 4", 1, 0},
+		{"max(0, count - 2) * 4", 5, 12},
+		{"1 + 2 * count", 3, 7},
+		{"(1 + 2) * count", 3, 9},
+		{"-count + 10 / 4", 1, 1.5},
+		{"min(count, 4, 2.5)", 9, 2.5},
+	}
+
+	for _, tt := range tests {
+		expr, err := ParsePenaltyExpr(tt.source)
+		if err != nil {
+			t.Fatalf("ParsePenaltyExpr(%q): %v", tt.source, err)
+		}
+		got, err := expr.Eval(tt.count)
+		if err != nil {
+			t.Fatalf("%q at count %d: %v", tt.source, tt.count, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q at count %d = %v, want %v", tt.source, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestPenaltyExpr_RejectsInvalidExpressions(t *testing.T) {
+	tests := map[string]string{
+		"":                   "unexpected end",
+		"count ** 2":         "invalid token \"*\"",
+		"os.Exit(1)":         "invalid token \"os\"",
+		"inf":                "invalid token \"inf\"",
+		"min(count)":         "at least two arguments",
+		"max(1, 2":           "expected ',' or ')'",
+		"(count + 1":         "expected ')'",
+		"count 2":            "unexpected \"2\"",
+		"sqrt(count)":        "invalid token \"sqrt\"",
+		"1.2.3 * count":      "invalid token \"1.2.3\"",
+		"count; rm -rf /":    "unexpected \";\"",
+		"min(1, 2) + count$": "unexpected \"$\"",
+	}
+
+	for source, want := range tests {
+		_, err := ParsePenaltyExpr(source)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("ParsePenaltyExpr(%q) error = %v, want it to contain %q", source, err, want)
+		}
+	}
+}
+
+func TestCategoryPenalty_CurveFallbacks(t *testing.T) {
+	divide, err := ParsePenaltyExpr("10 / count")
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if got := categoryPenalty(3, divide, 0); got != 0 {
+		t.Fatalf("expected flat fallback 0 x 3 for division by zero, got %v", got)
+	}
+
+	negative, err := ParsePenaltyExpr("count - 5")
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if got := categoryPenalty(3, negative, 2); got != 0 {
+		t.Fatalf("expected negative curve result to clamp to 0, got %v", got)
+	}
+
+	if got := categoryPenalty(3, nil, 4); got != 12 {
+		t.Fatalf("expected flat weight 4 x 3 = 12 without a curve, got %v", got)
+	}
+}
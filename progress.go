@@ -15,6 +15,8 @@ type ProgressReporter struct {
 	currentStep  int
 	enabled      bool
 	startTime    time.Time
+	// ascii draws the bar with # and . instead of block characters
+	ascii bool
 }
 
 // NewProgressReporter creates a new progress reporter
@@ -73,6 +75,9 @@ func (p *ProgressReporter) printProgress() {
 
 	percentage := float64(p.currentStep) / float64(p.totalSteps) * 100
 	bar := p.renderBar(percentage, 20)
+	if p.ascii {
+		bar = toASCII(bar)
+	}
 
 	fmt.Printf("\r%s [%s] %3.0f%%", p.currentStage, bar, percentage)
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// providedSources holds the files an API caller extracted itself, keyed by
+// absolute path
+type providedSources struct {
+	imports  map[string]*ImportMetadata
+	contents map[string]string
+}
+
+// newProvidedSources resolves the provided files of options, whose paths
+// may be relative to the analyzed directory. It returns nil when nothing
+// was provided.
+func newProvidedSources(absPath string, options AnalyzeOptions) *providedSources {
+	if len(options.ProvidedImports) == 0 && len(options.ProvidedFiles) == 0 {
+		return nil
+	}
+	provided := &providedSources{
+		imports:  make(map[string]*ImportMetadata, len(options.ProvidedImports)),
+		contents: make(map[string]string, len(options.ProvidedFiles)),
+	}
+	for path, metadata := range options.ProvidedImports {
+		provided.imports[providedPath(absPath, path)] = metadata
+	}
+	for path, content := range options.ProvidedFiles {
+		provided.contents[providedPath(absPath, path)] = content
+	}
+	return provided
+}
+
+func providedPath(absPath, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(absPath, filepath.FromSlash(path))
+}
+
+// paths returns every provided file, sorted
+func (p *providedSources) paths() []string {
+	if p == nil {
+		return nil
+	}
+	files := make(map[string]bool, len(p.imports)+len(p.contents))
+	for path := range p.imports {
+		files[path] = true
+	}
+	for path := range p.contents {
+		files[path] = true
+	}
+	return slices.Sorted(maps.Keys(files))
+}
+
+// content returns the provided content of path
+func (p *providedSources) content(path string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	content, ok := p.contents[path]
+	return content, ok
+}
+
+// importsOf returns the provided imports of path, parsing them from its
+// provided content when only that was given. It returns nil when neither
+// gives them, like for a Go file that does not parse.
+func (p *providedSources) importsOf(path string) *ImportMetadata {
+	if metadata, ok := p.imports[path]; ok {
+		return metadata
+	}
+	if !strings.HasSuffix(path, ".go") {
+		return nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), path, p.contents[path], parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	metadata := &ImportMetadata{Package: file.Name.Name}
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		metadata.Imports = append(metadata.Imports, importPath)
+		if imp.Name != nil && imp.Name.Name == "_" {
+			metadata.BlankImports = append(metadata.BlankImports, importPath)
+		}
+	}
+	return metadata
+}
+
+// addToGraph adds the provided files to graph the way the Go adapter adds
+// the files it parses: one node per file with an edge to every import as
+// written. Test files are test-provenance nodes when test edges are
+// included and are left out otherwise.
+func (p *providedSources) addToGraph(graph *model.DependencyGraph, testEdges model.TestEdgeMode) {
+	for _, path := range p.paths() {
+		isTest := strings.HasSuffix(path, "_test.go")
+		if isTest && testEdges != model.TestEdgesInclude {
+			continue
+		}
+		metadata := p.importsOf(path)
+		if metadata == nil {
+			continue
+		}
+		node := graph.AddNode(path, path, metadata.Package)
+		if isTest {
+			node.Metadata[model.ProvenanceMetadataKey] = string(model.EdgeFromTest)
+		}
+		if len(metadata.BlankImports) > 0 {
+			node.Metadata[model.BlankImportsMetadataKey] = strings.Join(metadata.BlankImports, "\n")
+		}
+		for _, imp := range metadata.Imports {
+			graph.AddEdge(path, imp)
+		}
+	}
+}
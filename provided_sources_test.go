@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// providedFixture is a small repository with a size violation, a god
+// object and a blank import
+func providedFixture() map[string]string {
+	var body strings.Builder
+	for i := 0; i < 90; i++ {
+		body.WriteString(fmt.Sprintf("\t_ = %d\n", i))
+	}
+	var fields strings.Builder
+	for i := 0; i < 20; i++ {
+		fields.WriteString(fmt.Sprintf("\tField%d int\n", i))
+	}
+	return map[string]string{
+		"main.go":           "package main\n\nimport (\n\t\"example.com/app/store\"\n\t_ \"example.com/app/plugins\"\n)\n\nfunc main() {\n\tstore.Open()\n}\n",
+		"store/store.go":    "package store\n\nimport \"fmt\"\n\nfunc Open() {\n" + body.String() + "\tfmt.Println()\n}\n",
+		"store/registry.go": "package store\n\ntype Registry struct {\n" + fields.String() + "}\n",
+	}
+}
+
+// providedRunFindings is what must not depend on where the files came from
+type providedRunFindings struct {
+	Circular  []CycleViolation
+	Layer     []LayerViolation
+	Size      []SizeViolation
+	GodObject []GodObjectViolation
+	Advisory  []AdvisoryViolation
+	Score     StructuralScore
+}
+
+// providedFindings returns the findings of a report with paths relative to
+// the analyzed directory
+func providedFindings(report *StructuralReport) providedRunFindings {
+	report = relativizeReport(report, report.Path)
+	return providedRunFindings{report.Circular, report.Layer, report.Size, report.GodObject, report.Advisory, *report.Score}
+}
+
+func TestAnalyze_ProvidedFilesMatchDiskRun(t *testing.T) {
+	disk, _ := analyzeFixture(t, providedFixture(), AnalyzeOptions{NoLargest: true})
+	want := providedFindings(disk)
+	if len(want.Size) == 0 || len(want.GodObject) == 0 {
+		t.Fatalf("expected the fixture to have size and god object violations, got %+v", want)
+	}
+
+	// Nothing on disk: every file comes from the caller
+	provided, _ := analyzeFixture(t, nil, AnalyzeOptions{NoLargest: true, ProvidedFiles: providedFixture()})
+	if got := providedFindings(provided); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected a provided run to match the disk run:\ngot  %+v\nwant %+v", got, want)
+	}
+	if entries, err := os.ReadDir(provided.Path); err != nil || len(entries) > 1 {
+		t.Fatalf("expected no source files to be written, got %v (%v)", entries, err)
+	}
+}
+
+func TestAnalyze_ProvidedImportsMergeWithDiskFiles(t *testing.T) {
+	disk, _ := analyzeFixture(t, providedFixture(), AnalyzeOptions{NoLargest: true})
+	want := providedFindings(disk)
+
+	// Provided imports may be keyed by absolute path, so the disk half of
+	// the fixture is written before the options are built
+	fixture := providedFixture()
+	mixedDir := t.TempDir()
+	writeServiceFixture(t, mixedDir, map[string]string{"store/store.go": fixture["store/store.go"]})
+	mixed, exitCode := NewAnalysisService().analyze(AnalyzeRequest{Path: mixedDir, Format: string(FormatJSON), Quiet: true, Options: AnalyzeOptions{
+		NoLargest: true,
+		ProvidedImports: map[string]*ImportMetadata{
+			"main.go": {Package: "main", Imports: []string{"example.com/app/store", "example.com/app/plugins"}, BlankImports: []string{"example.com/app/plugins"}},
+			filepath.Join(mixedDir, "store/registry.go"): {Package: "store"},
+		},
+		ProvidedFiles: map[string]string{
+			"main.go":           fixture["main.go"],
+			"store/registry.go": fixture["store/registry.go"],
+		},
+	}})
+	if mixed == nil {
+		t.Fatalf("analysis failed with exit code %d", exitCode)
+	}
+	if got := providedFindings(mixed); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected provided and discovered files to merge:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestProvidedSources_ParsesImportsFromContent(t *testing.T) {
+	provided := newProvidedSources("/repo", AnalyzeOptions{ProvidedFiles: providedFixture()})
+	metadata := provided.importsOf(filepath.Join("/repo", "main.go"))
+	if metadata == nil || metadata.Package != "main" {
+		t.Fatalf("expected main.go's imports to be parsed, got %+v", metadata)
+	}
+	if !reflect.DeepEqual(metadata.Imports, []string{"example.com/app/store", "example.com/app/plugins"}) || !reflect.DeepEqual(metadata.BlankImports, []string{"example.com/app/plugins"}) {
+		t.Fatalf("unexpected imports %+v", metadata)
+	}
+	if newProvidedSources("/repo", AnalyzeOptions{}) != nil {
+		t.Fatal("expected no provided sources without provided files")
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Violation kinds used for comparison identity and per-rule deltas
+const (
+	compareKindCircular         = "circular"
+	compareKindLayer            = "layer"
+	compareKindFeatureIsolation = "feature-isolation"
+	compareKindDependencyCap    = "dependency-cap"
+	compareKindSize             = "size"
+	compareKindGodObject        = "god-object"
+)
+
+// compareKinds lists the violation kinds in report order
+var compareKinds = []string{compareKindCircular, compareKindLayer, compareKindFeatureIsolation, compareKindDependencyCap, compareKindSize, compareKindGodObject}
+
+// optionalCompareKinds are the kinds of opt-in rules, which only get a
+// delta when either report has violations of the kind
+var optionalCompareKinds = map[string]bool{compareKindFeatureIsolation: true, compareKindDependencyCap: true}
+
+// ComparedViolation is a violation identified across two reports
+type ComparedViolation struct {
+	Kind        string `json:"kind"`
+	Fingerprint string `json:"fingerprint"`
+	Description string `json:"description"`
+}
+
+// RuleDelta holds the violation count change for one kind
+type RuleDelta struct {
+	Kind  string `json:"kind"`
+	Base  int    `json:"base"`
+	Head  int    `json:"head"`
+	Delta int    `json:"delta"`
+}
+
+// ReportComparison is the difference between a base and a head report
+type ReportComparison struct {
+	BaseScore  float64             `json:"baseScore"`
+	HeadScore  float64             `json:"headScore"`
+	ScoreDelta float64             `json:"scoreDelta"`
+	Added      []ComparedViolation `json:"added"`
+	Fixed      []ComparedViolation `json:"fixed"`
+	RuleDeltas []RuleDelta         `json:"ruleDeltas"`
+}
+
+// CompareReports matches violations by identity and returns what was added
+// in head, what was fixed since base, and the score and per-rule deltas.
+// Identity is the cycle member set, the layer from/to pair, file+function for
+// size and file+struct for god objects.
+func CompareReports(base, head *StructuralReport) *ReportComparison {
+	baseViolations := indexReportViolations(base)
+	headViolations := indexReportViolations(head)
+
+	cmp := &ReportComparison{
+		BaseScore:  reportTotalScore(base),
+		HeadScore:  reportTotalScore(head),
+		Added:      make([]ComparedViolation, 0),
+		Fixed:      make([]ComparedViolation, 0),
+		RuleDeltas: make([]RuleDelta, 0, len(compareKinds)),
+	}
+	cmp.ScoreDelta = cmp.HeadScore - cmp.BaseScore
+
+	for fingerprint, v := range headViolations {
+		if _, ok := baseViolations[fingerprint]; !ok {
+			cmp.Added = append(cmp.Added, v)
+		}
+	}
+	for fingerprint, v := range baseViolations {
+		if _, ok := headViolations[fingerprint]; !ok {
+			cmp.Fixed = append(cmp.Fixed, v)
+		}
+	}
+	sortComparedViolations(cmp.Added)
+	sortComparedViolations(cmp.Fixed)
+
+	baseCounts := countByKind(baseViolations)
+	headCounts := countByKind(headViolations)
+	for _, kind := range compareKinds {
+		if optionalCompareKinds[kind] && baseCounts[kind] == 0 && headCounts[kind] == 0 {
+			continue
+		}
+		cmp.RuleDeltas = append(cmp.RuleDeltas, RuleDelta{
+			Kind:  kind,
+			Base:  baseCounts[kind],
+			Head:  headCounts[kind],
+			Delta: headCounts[kind] - baseCounts[kind],
+		})
+	}
+
+	return cmp
+}
+
+// indexReportViolations keys every violation in the report by fingerprint
+func indexReportViolations(report *StructuralReport) map[string]ComparedViolation {
+	index := make(map[string]ComparedViolation)
+	if report == nil {
+		return index
+	}
+
+	add := func(kind, identity, description string) {
+		fingerprint := violationFingerprint(kind, identity)
+		index[fingerprint] = ComparedViolation{Kind: kind, Fingerprint: fingerprint, Description: description}
+	}
+
+	for _, v := range report.Circular {
+		add(compareKindCircular, cycleIdentity(v.Path), formatCyclePath(v.Path))
+	}
+	for _, v := range report.Layer {
+		add(compareKindLayer, v.From+"->"+v.To, v.Message)
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		add(compareKindFeatureIsolation, v.From+"#"+v.Message, v.Message)
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		// The message names the newest modules, which change between runs
+		add(compareKindDependencyCap, v.Manifest, v.Message)
+	}
+	for _, v := range report.Size {
+		description := fmt.Sprintf("File %s: %d lines (threshold: %d)", v.File, v.Lines, v.Threshold)
+		if v.Function != "" {
+			description = fmt.Sprintf("Function '%s' in %s: %d lines (threshold: %d)", v.Function, v.File, v.Lines, v.Threshold)
+		}
+		add(compareKindSize, v.File+"#"+v.Function, description)
+	}
+	for _, v := range report.GodObject {
+		description := fmt.Sprintf("Struct '%s' in %s: %d fields, %d methods", v.StructName, v.File, v.FieldCount, v.MethodCount)
+		add(compareKindGodObject, v.File+"#"+v.StructName, description)
+	}
+
+	return index
+}
+
+// violationFingerprint returns a short stable hash of a violation identity
+func violationFingerprint(kind, identity string) string {
+	sum := sha256.Sum256([]byte(kind + "|" + identity))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func sortComparedViolations(violations []ComparedViolation) {
+	order := make(map[string]int, len(compareKinds))
+	for i, kind := range compareKinds {
+		order[kind] = i
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Kind != violations[j].Kind {
+			return order[violations[i].Kind] < order[violations[j].Kind]
+		}
+		if violations[i].Description != violations[j].Description {
+			return violations[i].Description < violations[j].Description
+		}
+		return violations[i].Fingerprint < violations[j].Fingerprint
+	})
+}
+
+func countByKind(index map[string]ComparedViolation) map[string]int {
+	counts := make(map[string]int, len(compareKinds))
+	for _, v := range index {
+		counts[v.Kind]++
+	}
+	return counts
+}
+
+func reportTotalScore(report *StructuralReport) float64 {
+	if report == nil || report.Score == nil {
+		return 0
+	}
+	return report.Score.TotalScore
+}
+
+// formatComparisonMarkdown renders a comparison as GitHub-flavored Markdown
+// suitable for posting as a pull request comment. Each violation row carries
+// its fingerprint in a hidden HTML comment so bots can deduplicate.
+func formatComparisonMarkdown(cmp *ReportComparison) string {
+	var sb strings.Builder
+
+	sb.WriteString("## RepoDoctor Comparison\n\n")
+	sb.WriteString(fmt.Sprintf("**Score:** %.1f → %.1f (%s)\n\n", cmp.BaseScore, cmp.HeadScore, formatScoreDeltaArrow(cmp.ScoreDelta)))
+
+	sb.WriteString("| Rule | Base | Head | Δ |\n")
+	sb.WriteString("|---|---:|---:|---:|\n")
+	for _, d := range cmp.RuleDeltas {
+		delta := "0"
+		if d.Delta != 0 {
+			delta = fmt.Sprintf("%+d", d.Delta)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %s |\n", d.Kind, d.Base, d.Head, delta))
+	}
+	sb.WriteString("\n")
+
+	if len(cmp.Added) == 0 && len(cmp.Fixed) == 0 {
+		sb.WriteString("_No violation changes._\n")
+		return sb.String()
+	}
+
+	writeComparedViolations(&sb, "New violations", "➕", cmp.Added)
+	writeComparedViolations(&sb, "Fixed violations", "✔", cmp.Fixed)
+
+	return sb.String()
+}
+
+func writeComparedViolations(sb *strings.Builder, title, marker string, violations []ComparedViolation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("### %s (%d)\n\n", title, len(violations)))
+	for _, v := range violations {
+		description := strings.ReplaceAll(v.Description, "`", "'")
+		sb.WriteString(fmt.Sprintf("- %s **%s** `%s` <!-- fingerprint:%s -->\n", marker, v.Kind, description, v.Fingerprint))
+	}
+	sb.WriteString("\n")
+}
+
+// formatScoreDeltaArrow renders a score change with a direction arrow
+func formatScoreDeltaArrow(delta float64) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("▲ +%.1f", delta)
+	case delta < 0:
+		return fmt.Sprintf("▼ %.1f", delta)
+	default:
+		return "= 0.0"
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func compareFixtureBase() *StructuralReport {
+	return &StructuralReport{
+		Score: &StructuralScore{TotalScore: 82.0, MaxScore: 100.0},
+		Circular: []CycleViolation{
+			{Path: []string{"internal/a/a.go", "internal/b/b.go"}, Severity: model.SeverityCritical},
+		},
+		Size: []SizeViolation{
+			{File: "internal/big/big.go", Lines: 640, Threshold: 500},
+		},
+		GodObject: []GodObjectViolation{
+			{StructName: "Manager", File: "internal/manager/manager.go", FieldCount: 18, MethodCount: 9},
+		},
+	}
+}
+
+func TestCompareReports_MatchesCycleByMemberSet(t *testing.T) {
+	base := compareFixtureBase()
+	head := compareFixtureBase()
+	head.Circular[0].Path = []string{"internal/b/b.go", "internal/a/a.go"}
+
+	cmp := CompareReports(base, head)
+	if len(cmp.Added) != 0 || len(cmp.Fixed) != 0 {
+		t.Fatalf("expected rotated cycle to match, got added=%v fixed=%v", cmp.Added, cmp.Fixed)
+	}
+}
+
+func TestCompareReports_MarkdownGoldenAdditions(t *testing.T) {
+	base := compareFixtureBase()
+	head := compareFixtureBase()
+	head.Score = &StructuralScore{TotalScore: 69.0, MaxScore: 100.0}
+	head.Layer = []LayerViolation{
+		{From: "internal/domain/user.go", To: "internal/infra/db.go", Message: "domain must not depend on infra: internal/domain/user.go -> internal/infra/db.go"},
+	}
+	head.Size = append(head.Size, SizeViolation{File: "internal/api/handler.go", Function: "ServeHTTP", Lines: 120, Threshold: 80})
+
+	assertGolden(t, "compare_additions.md", formatComparisonMarkdown(CompareReports(base, head)))
+}
+
+func TestCompareReports_MarkdownGoldenFixes(t *testing.T) {
+	base := compareFixtureBase()
+	head := compareFixtureBase()
+	head.Score = &StructuralScore{TotalScore: 97.0, MaxScore: 100.0}
+	head.Circular = nil
+	head.GodObject = nil
+
+	got := formatComparisonMarkdown(CompareReports(base, head))
+	if strings.Contains(got, "➕") {
+		t.Fatalf("expected no additions, got:\n%s", got)
+	}
+	assertGolden(t, "compare_fixes.md", got)
+}
+
+func TestCompareReports_MarkdownGoldenNoChange(t *testing.T) {
+	got := formatComparisonMarkdown(CompareReports(compareFixtureBase(), compareFixtureBase()))
+	if !strings.Contains(got, "_No violation changes._") {
+		t.Fatalf("expected no-change line, got:\n%s", got)
+	}
+	assertGolden(t, "compare_unchanged.md", got)
+}
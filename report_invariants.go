@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// reportSelfCheck forces invariant verification for every generated report.
+// Tests enable it; in production it is opt-in via analyze -self-check.
+var reportSelfCheck = false
+
+// penaltyTolerance absorbs floating point error when comparing penalties
+const penaltyTolerance = 1e-9
+
+// verifyReportInvariants checks that a report is internally consistent:
+// per-category counts match the detailed lists, the violation count is their
+// sum, penalties equal weight x count (or the category's penalty curve), the total follows from the penalties
+// and HasViolations agrees with the counts. Penalties and total are only
+// checked for the weighted score model. All inconsistencies are reported
+// together in a single runtime error.
+func verifyReportInvariants(report *StructuralReport, weights *ScoringWeights) error {
+	if report == nil || report.Score == nil {
+		return HandleRuntimeError("Report self-check failed", errors.New("report has no score"))
+	}
+
+	score := report.Score
+	problems := make([]string, 0)
+	checkCount := func(name string, got, want int) {
+		if got != want {
+			problems = append(problems, fmt.Sprintf("%s is %d but %d violations are listed", name, got, want))
+		}
+	}
+	checkPenalty := func(name string, got, weight float64, curve *PenaltyExpr, count int) {
+		want := categoryPenalty(weight, curve, count)
+		if math.Abs(got-want) <= penaltyTolerance {
+			return
+		}
+		if curve != nil {
+			problems = append(problems, fmt.Sprintf("%s is %.2f but %s at count %d = %.2f", name, got, curve, count, want))
+			return
+		}
+		problems = append(problems, fmt.Sprintf("%s is %.2f but %d x %.2f = %.2f", name, got, count, weight, want))
+	}
+
+	checkCount("Score.CircularCount", score.CircularCount, len(report.Circular))
+	checkCount("Score.LayerCount", score.LayerCount, len(report.Layer))
+	checkCount("Score.FeatureIsolationCount", score.OptIn.FeatureIsolationCount, len(report.OptIn.FeatureIsolation))
+	checkCount("Score.DependencyCapCount", score.OptIn.DependencyCapCount, len(report.OptIn.DependencyCap))
+	checkCount("Score.SizeCount", score.SizeCount, len(report.Size))
+	checkCount("Score.GodObjectCount", score.GodObjectCount, len(report.GodObject))
+	checkCount("Summary.Circular", report.Summary.Circular, len(report.Circular))
+	checkCount("Summary.Layer", report.Summary.Layer, len(report.Layer))
+	checkCount("Summary.FeatureIsolation", report.Summary.FeatureIsolation, len(report.OptIn.FeatureIsolation))
+	checkCount("Summary.DependencyCap", report.Summary.DependencyCap, len(report.OptIn.DependencyCap))
+	checkCount("Summary.Size", report.Summary.Size, len(report.Size))
+	checkCount("Summary.GodObject", report.Summary.GodObject, len(report.GodObject))
+
+	total := score.CircularCount + score.LayerCount + score.OptIn.FeatureIsolationCount + score.OptIn.DependencyCapCount + score.SizeCount + score.GodObjectCount
+	if score.ViolationCount != total {
+		problems = append(problems, fmt.Sprintf("Score.ViolationCount is %d but category counts sum to %d", score.ViolationCount, total))
+	}
+	if report.Summary.TotalViolations != total {
+		problems = append(problems, fmt.Sprintf("Summary.TotalViolations is %d but category counts sum to %d", report.Summary.TotalViolations, total))
+	}
+
+	weighted := isWeightedScore(score)
+	if weights != nil && weighted {
+		checkPenalty("Score.CircularPenalty", score.CircularPenalty, weights.CircularDependencyPenalty, weights.CircularCurve, score.CircularCount)
+		checkPenalty("Score.LayerPenalty", score.LayerPenalty, weights.LayerViolationPenalty, weights.LayerCurve, score.LayerCount)
+		checkPenalty("Score.FeatureIsolationPenalty", score.OptIn.FeatureIsolationPenalty, weights.FeatureIsolationPenalty, nil, score.OptIn.FeatureIsolationCount)
+		checkPenalty("Score.DependencyCapPenalty", score.OptIn.DependencyCapPenalty, weights.DependencyCapPenalty, nil, score.OptIn.DependencyCapCount)
+		checkPenalty("Score.SizePenalty", score.SizePenalty, weights.SizeViolationPenalty, weights.SizeCurve, score.SizeCount)
+		checkPenalty("Score.GodObjectPenalty", score.GodObjectPenalty, weights.GodObjectPenalty, weights.GodObjectCurve, score.GodObjectCount)
+	}
+
+	expectedTotal := math.Max(0, score.MaxScore-(score.CircularPenalty+score.LayerPenalty+score.OptIn.FeatureIsolationPenalty+score.OptIn.DependencyCapPenalty+score.SizePenalty+score.GodObjectPenalty))
+	if weighted && math.Abs(score.TotalScore-expectedTotal) > penaltyTolerance {
+		problems = append(problems, fmt.Sprintf("Score.TotalScore is %.2f but max minus penalties is %.2f", score.TotalScore, expectedTotal))
+	}
+
+	if report.HasViolations != (total > 0) {
+		problems = append(problems, fmt.Sprintf("HasViolations is %t but %d violations are counted", report.HasViolations, total))
+	}
+
+	if len(problems) > 0 {
+		return HandleRuntimeError("Report self-check failed", errors.New(strings.Join(problems, "; ")))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+// TestMain enables report self-checks for every test in the package so any
+// analysis run under test fails loudly on an inconsistent report.
+func TestMain(m *testing.M) {
+	reportSelfCheck = true
+	os.Exit(m.Run())
+}
+
+func consistentInvariantReport() *StructuralReport {
+	return buildReportFromRuleViolations("/repo", "test", nil, []model.Violation{
+		{RuleID: "rule.circular-dependency", File: "a.go", Severity: model.SeverityCritical},
+		{RuleID: "rule.size", File: "big.go", Message: "File big.go has 900 lines (threshold: 500)"},
+		{RuleID: "rule.size", File: "huge.go", Message: "File huge.go has 700 lines (threshold: 500)"},
+		{RuleID: "rule.god-object", File: "m.go", Message: "Manager has 20 fields (threshold: 15)"},
+	})
+}
+
+func TestVerifyReportInvariants_AcceptsConsistentReport(t *testing.T) {
+	if err := verifyReportInvariants(consistentInvariantReport(), DefaultScoringWeights()); err != nil {
+		t.Fatalf("expected consistent report to pass, got %v", err)
+	}
+
+	clean := buildReportFromRuleViolations("/repo", "test", nil, nil)
+	if err := verifyReportInvariants(clean, DefaultScoringWeights()); err != nil {
+		t.Fatalf("expected clean report to pass, got %v", err)
+	}
+}
+
+func TestVerifyReportInvariants_DetectsCorruption(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(r *StructuralReport)
+		want    string
+	}{
+		{"score count", func(r *StructuralReport) { r.Score.SizeCount = 1 }, "Score.SizeCount is 1 but 2"},
+		{"summary count", func(r *StructuralReport) { r.Summary.Circular = 0 }, "Summary.Circular is 0 but 1"},
+		{"list length", func(r *StructuralReport) { r.Layer = append(r.Layer, LayerViolation{From: "x"}) }, "Score.LayerCount is 0 but 1"},
+		{"violation count", func(r *StructuralReport) { r.Score.ViolationCount = 7 }, "Score.ViolationCount is 7 but category counts sum to 4"},
+		{"penalty", func(r *StructuralReport) { r.Score.GodObjectPenalty = 9 }, "Score.GodObjectPenalty is 9.00 but 1 x 5.00 = 5.00"},
+		{"total score", func(r *StructuralReport) { r.Score.TotalScore = 100 }, "Score.TotalScore is 100.00"},
+		{"has violations", func(r *StructuralReport) { r.HasViolations = false }, "HasViolations is false but 4 violations are counted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := consistentInvariantReport()
+			tt.corrupt(report)
+
+			err := verifyReportInvariants(report, DefaultScoringWeights())
+			if err == nil {
+				t.Fatal("expected invariant violation to be detected")
+			}
+			cliErr, ok := err.(*CLIError)
+			if !ok || cliErr.Category != ErrorRuntime {
+				t.Fatalf("expected runtime CLIError, got %T: %v", err, err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error to contain %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReportOutput writes the whole report to a file in one format, so a single
+// run can feed a pipeline and the console at once
+type ReportOutput struct {
+	Format OutputFormat
+	Path   string
+}
+
+// reportOutputExtensions are the extensions -output appends per format.
+// They are unique, so no two formats of a run write the same file.
+var reportOutputExtensions = map[OutputFormat]string{
+	FormatText:       "txt",
+	FormatJSON:       "json",
+	FormatJSONV1:     "v1.json",
+	FormatJSONLegacy: "legacy.json",
+	FormatEnv:        "env",
+	FormatFixPlan:    "fixplan.txt",
+	FormatSARIF:      "sarif",
+	FormatJUnit:      "junit.xml",
+	FormatHTML:       "html",
+	FormatTree:       "tree.txt",
+	FormatTreeJSON:   "tree.json",
+	FormatMermaid:    "mmd",
+	FormatCheckstyle: "checkstyle.xml",
+	FormatMarkdown:   "md",
+	FormatJSONL:      "jsonl",
+	FormatNDJSON:     "ndjson",
+	FormatTAP:        "tap",
+	FormatBadge:      "badge.json",
+}
+
+// planReportOutputs splits a comma-separated -format value and decides where
+// each format goes. Without -output the single format is printed. With
+// -output, text is printed and every other format is written to
+// <output>.<ext>. It returns the printed format, empty when nothing is
+// printed, and the file outputs. Unknown and repeated formats are rejected
+// before anything runs.
+func planReportOutputs(value, output string) (OutputFormat, []ReportOutput, error) {
+	var formats []OutputFormat
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if err := validateAnalyzeFormat(name); err != nil {
+			return "", nil, err
+		}
+		for _, seen := range formats {
+			if seen == OutputFormat(name) {
+				return "", nil, NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Format %s is listed twice", name), "List each format once, e.g. -format text,json", nil)
+			}
+		}
+		formats = append(formats, OutputFormat(name))
+	}
+
+	if output == "" {
+		if len(formats) > 1 {
+			return "", nil, NewCLIError(ErrorCLIUsage, "Several formats need -output", "Add -output <path>: text is printed and each other format is written to <path>.<ext>", nil)
+		}
+		return formats[0], nil, nil
+	}
+
+	var printed OutputFormat
+	var outputs []ReportOutput
+	for _, format := range formats {
+		if format == FormatText {
+			printed = format
+			continue
+		}
+		outputs = append(outputs, ReportOutput{Format: format, Path: output + "." + reportOutputExtensions[format]})
+	}
+	return printed, outputs, nil
+}
+
+// reportOutputFormats returns the printed format followed by the formats
+// written to files
+func reportOutputFormats(printed OutputFormat, outputs []ReportOutput) []OutputFormat {
+	formats := []OutputFormat{printed}
+	for _, output := range outputs {
+		formats = append(formats, output.Format)
+	}
+	return formats
+}
+
+// writeReport writes report to w in format, exactly as analyze prints it
+func writeReport(w io.Writer, report *StructuralReport, format OutputFormat, cfg *Config, request AnalyzeRequest) error {
+	reporter := newRequestReporter(format, request)
+	var err error
+	switch format {
+	case FormatJSONLegacy, FormatJSONV1, FormatSARIF, FormatTree, FormatTreeJSON:
+		_, err = fmt.Fprintln(w, reporter.Format(report))
+	case FormatEnv, FormatJUnit, FormatHTML, FormatMermaid, FormatCheckstyle, FormatMarkdown, FormatJSONL, FormatNDJSON, FormatTAP, FormatBadge:
+		err = reporter.Stream(w, report)
+	case FormatFixPlan:
+		_, err = fmt.Fprint(w, formatFixPlan(BuildFixPlan(relativizeReport(report, reportBase(report, request.Report.BasePath, request.Options.AbsPaths)), scoringWeightsFromConfig(cfg))))
+	default:
+		_, err = fmt.Fprintln(w, reporter.FormatColoredText(report))
+	}
+	return err
+}
+
+// writeReportOutputs writes report to the file of each output, without
+// colors. Every file is rendered from the same report, so a run with
+// several formats analyzes and records history once.
+func writeReportOutputs(report *StructuralReport, outputs []ReportOutput, cfg *Config, request AnalyzeRequest) error {
+	request.Report.ColorEnabled = false
+	for _, output := range outputs {
+		if err := writeReportFile(output.Path, report, output.Format, cfg, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeReportFile writes report to path in format through writeReport,
+// creating the parent directory as needed
+func writeReportFile(path string, report *StructuralReport, format OutputFormat, cfg *Config, request AnalyzeRequest) error {
+	format = resolveJSONFormat(format, cfg)
+	writeLegacyJSONNotice(os.Stderr, format, request.Options.NoNotices)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return WrapError(err, ErrorRuntime, fmt.Sprintf("Could not create directory for %s", path), "Check that the output path is writable")
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return WrapError(err, ErrorRuntime, fmt.Sprintf("Could not write report output %s", path), "Check that the output path is writable")
+	}
+	err = writeReport(file, report, format, cfg, request)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return WrapError(err, ErrorRuntime, fmt.Sprintf("Could not write report output %s", path), "Check that the output path is writable")
+	}
+	return nil
+}
+
+// writeRequestOutputs writes the -out-rule and -output files of request
+func writeRequestOutputs(report *StructuralReport, cfg *Config, request AnalyzeRequest) error {
+	if err := writeRuleOutputs(report, request.Report.RuleOutputs, cfg, request); err != nil {
+		return err
+	}
+	return writeReportOutputs(report, request.Report.Outputs, cfg, request)
+}
+
+// addFormatMetrics adds the metrics only format prints: the directory tree,
+// the HTML score trend and the Mermaid package diagram
+func addFormatMetrics(report *StructuralReport, format OutputFormat, absPath string, cfg *Config, request AnalyzeRequest, summary *runtimeRuleSummary) {
+	switch {
+	case isTreeFormat(format):
+		report.Metrics.Tree = buildDirectoryTree(absPath, summary.files, report, cfg, request.Options.TreeDepth)
+	case format == FormatHTML:
+		report.Metrics.Trend.Scores = htmlScoreTrend(absPath, report)
+	case format == FormatMermaid:
+		report.Metrics.Packages.Diagram = buildPackageDiagram(summary.graph, absPath, summary.files, mermaidMaxNodes(cfg))
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanReportOutputs(t *testing.T) {
+	printed, outputs, err := planReportOutputs("text, json,sarif", "out/report")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []ReportOutput{{Format: FormatJSON, Path: "out/report.json"}, {Format: FormatSARIF, Path: "out/report.sarif"}}
+	if printed != FormatText || len(outputs) != 2 || outputs[0] != want[0] || outputs[1] != want[1] {
+		t.Fatalf("expected text printed and %v, got %q and %v", want, printed, outputs)
+	}
+
+	if printed, outputs, err := planReportOutputs("json", "report"); err != nil || printed != "" || len(outputs) != 1 {
+		t.Fatalf("expected json in a file and nothing printed, got %q, %v, %v", printed, outputs, err)
+	}
+	if printed, outputs, err := planReportOutputs("json", ""); err != nil || printed != FormatJSON || outputs != nil {
+		t.Fatalf("expected a single format to be printed without -output, got %q, %v, %v", printed, outputs, err)
+	}
+}
+
+func TestPlanReportOutputs_RejectsInvalidLists(t *testing.T) {
+	for _, tc := range []struct {
+		value, output, want string
+	}{
+		{value: "text,yaml", output: "report", want: "Invalid format: yaml"},
+		{value: "json,json", output: "report", want: "Format json is listed twice"},
+		{value: "text,json", output: "", want: "Several formats need -output"},
+	} {
+		_, _, err := planReportOutputs(tc.value, tc.output)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("-format %s -output %q: expected error %q, got %v", tc.value, tc.output, tc.want, err)
+		}
+	}
+}
+
+func TestAnalyze_SeveralFormatsFromOneRun(t *testing.T) {
+	dir := t.TempDir()
+	writeServiceFixture(t, dir, map[string]string{
+		"big.go": "package big\n" + strings.Repeat("// filler\n", 600),
+	})
+	base := filepath.Join(t.TempDir(), "reports", "repodoctor")
+
+	req, err := parseAnalyzeFlags([]string{"-format", "text,json-v1,sarif", "-output", base, "-no-color", dir})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var report *StructuralReport
+	stdout := captureStdout(t, func() {
+		report, _ = NewAnalysisService().analyze(*req)
+	})
+	if report == nil {
+		t.Fatal("expected a report")
+	}
+	if !strings.Contains(stdout, "SIZE VIOLATIONS") || strings.Contains(stdout, `"schemaVersion"`) {
+		t.Fatalf("expected only the text report on stdout, got:\n%s", stdout)
+	}
+
+	var payload struct {
+		Score struct {
+			Total float64 `json:"total"`
+		} `json:"score"`
+	}
+	data, err := os.ReadFile(base + ".v1.json")
+	if err != nil || json.Unmarshal(data, &payload) != nil || payload.Score.Total != report.Score.TotalScore {
+		t.Fatalf("expected the json-v1 report with score %.1f, got %s, %v", report.Score.TotalScore, data, err)
+	}
+	if data, err := os.ReadFile(base + ".sarif"); err != nil || !strings.Contains(string(data), `"runs"`) {
+		t.Fatalf("expected the SARIF report, got %s, %v", data, err)
+	}
+
+	history := NewTrendAnalyzer(dir)
+	if err := history.LoadHistory(); err != nil || history.GetHistoryLength() != 1 {
+		t.Fatalf("expected exactly one history entry, got %d, %v", history.GetHistoryLength(), err)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// relativizeReport returns a copy of the report whose file paths are
+// relative to basePath. Paths outside basePath are left untouched.
+func relativizeReport(report *StructuralReport, basePath string) *StructuralReport {
+	if report == nil || basePath == "" {
+		return report
+	}
+	return mapReportPaths(report, func(path string) string {
+		return relativeToBase(path, basePath)
+	})
+}
+
+// mapReportPaths returns a copy of the report with rel applied to the
+// analyzed path and every file path, including those in layer messages
+func mapReportPaths(report *StructuralReport, rel func(string) string) *StructuralReport {
+	out := *report
+	out.Path = rel(report.Path)
+
+	out.Circular = make([]CycleViolation, len(report.Circular))
+	for i, v := range report.Circular {
+		path := make([]string, len(v.Path))
+		for j, node := range v.Path {
+			path[j] = rel(node)
+		}
+		out.Circular[i] = CycleViolation{Path: path, Severity: v.Severity}
+		for _, node := range v.BlankImportsFrom {
+			out.Circular[i].BlankImportsFrom = append(out.Circular[i].BlankImportsFrom, rel(node))
+		}
+	}
+
+	out.Layer = make([]LayerViolation, len(report.Layer))
+	for i, v := range report.Layer {
+		message := v.Message
+		if v.From != "" {
+			message = strings.ReplaceAll(message, v.From, rel(v.From))
+		}
+		if v.To != "" {
+			message = strings.ReplaceAll(message, v.To, rel(v.To))
+		}
+		v.From, v.To, v.Message = rel(v.From), rel(v.To), message
+		out.Layer[i] = v
+	}
+
+	out.OptIn = mapOptInPaths(report.OptIn, rel)
+
+	out.Size = make([]SizeViolation, len(report.Size))
+	for i, v := range report.Size {
+		v.File = rel(v.File)
+		out.Size[i] = v
+	}
+
+	out.GodObject = make([]GodObjectViolation, len(report.GodObject))
+	for i, v := range report.GodObject {
+		v.File = rel(v.File)
+		out.GodObject[i] = v
+	}
+
+	out.Advisory = make([]AdvisoryViolation, len(report.Advisory))
+	for i, v := range report.Advisory {
+		v.File = rel(v.File)
+		out.Advisory[i] = v
+	}
+
+	if report.Metrics.Cohesion != nil {
+		out.Metrics.Cohesion = make([]rules.StructCohesion, len(report.Metrics.Cohesion))
+		for i, c := range report.Metrics.Cohesion {
+			c.File = rel(c.File)
+			out.Metrics.Cohesion[i] = c
+		}
+	}
+
+	if report.Metrics.Largest != nil {
+		largest := *report.Metrics.Largest
+		largest.Files = make([]rules.FileSize, len(report.Metrics.Largest.Files))
+		for i, f := range report.Metrics.Largest.Files {
+			f.File = rel(f.File)
+			largest.Files[i] = f
+		}
+		largest.FunctionsByLines = relativizeFunctionSizes(report.Metrics.Largest.FunctionsByLines, rel)
+		largest.FunctionsByComplexity = relativizeFunctionSizes(report.Metrics.Largest.FunctionsByComplexity, rel)
+		out.Metrics.Largest = &largest
+	}
+
+	return &out
+}
+
+// mapOptInPaths returns a copy of the opt-in rule violations with rel
+// applied to their file paths
+func mapOptInPaths(optIn OptInViolations, rel func(string) string) OptInViolations {
+	out := optIn
+	out.FeatureIsolation = make([]FeatureIsolationViolation, len(optIn.FeatureIsolation))
+	for i, v := range optIn.FeatureIsolation {
+		message := v.Message
+		if v.From != "" {
+			message = strings.ReplaceAll(message, v.From, rel(v.From))
+		}
+		v.From, v.Message = rel(v.From), message
+		out.FeatureIsolation[i] = v
+	}
+
+	out.DependencyCap = make([]DependencyCapViolation, len(optIn.DependencyCap))
+	for i, v := range optIn.DependencyCap {
+		v.Manifest = rel(v.Manifest)
+		out.DependencyCap[i] = v
+	}
+	return out
+}
+
+func relativizeFunctionSizes(functions []rules.FunctionSize, rel func(string) string) []rules.FunctionSize {
+	out := make([]rules.FunctionSize, len(functions))
+	for i, f := range functions {
+		f.File = rel(f.File)
+		out[i] = f
+	}
+	return out
+}
+
+// reportBase returns the directory a report's paths are made relative to:
+// basePath when set, otherwise the analyzed directory itself. absPaths
+// keeps the paths as the analysis produced them.
+func reportBase(report *StructuralReport, basePath string, absPaths bool) string {
+	if basePath != "" || absPaths || report == nil {
+		return basePath
+	}
+	return report.Path
+}
+
+// relativeToBase makes an absolute path relative to basePath using forward
+// slashes. Relative paths, and paths outside basePath, are returned as-is.
+// Both separators are accepted whatever the platform, so a report produced
+// on Windows relativizes the same way everywhere.
+func relativeToBase(file, basePath string) string {
+	if file == "" || !isAbsolutePath(file) {
+		return file
+	}
+	target, base := slashPath(file), slashPath(basePath)
+	fold := isDrivePath(target) || runtime.GOOS == "windows"
+	if target == base || (fold && strings.EqualFold(target, base)) {
+		return "."
+	}
+	prefix := strings.TrimSuffix(base, "/") + "/"
+	if len(target) <= len(prefix) {
+		return file
+	}
+	if head := target[:len(prefix)]; head == prefix || (fold && strings.EqualFold(head, prefix)) {
+		return target[len(prefix):]
+	}
+	return file
+}
+
+// isAbsolutePath reports whether file is absolute on this platform or is a
+// Windows drive path
+func isAbsolutePath(file string) bool {
+	return filepath.IsAbs(file) || isDrivePath(strings.ReplaceAll(file, `\`, "/"))
+}
+
+// isDrivePath reports whether a slash-separated path starts with a Windows
+// drive letter, such as C:/
+func isDrivePath(file string) bool {
+	if len(file) < 3 || file[1] != ':' || file[2] != '/' {
+		return false
+	}
+	letter := file[0] | 0x20
+	return 'a' <= letter && letter <= 'z'
+}
+
+// slashPath cleans file after turning backslashes into forward slashes
+func slashPath(file string) string {
+	return path.Clean(strings.ReplaceAll(file, `\`, "/"))
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func TestReporter_BasePathRendersRelativePaths(t *testing.T) {
+	base := t.TempDir()
+	repo := filepath.Join(base, "services", "api")
+	file := filepath.Join(repo, "handler", "server.go")
+	outside := filepath.Join(filepath.Dir(base), "elsewhere", "lib.go")
+
+	report := &StructuralReport{
+		Version:       "0.5.0-dev",
+		SchemaVersion: "v2",
+		Path:          repo,
+		Score:         &StructuralScore{TotalScore: 90, MaxScore: 100},
+		Circular:      []CycleViolation{{Path: []string{file, outside}, Severity: model.SeverityCritical}},
+		Layer:         []LayerViolation{{From: file, Message: file + " (handler) -> x (service): upward import not allowed"}},
+		Size:          []SizeViolation{{File: file, Lines: 600, Threshold: 500}},
+		GodObject:     []GodObjectViolation{{StructName: "Server", File: file, FieldCount: 20}},
+		HasViolations: true,
+	}
+
+	reporter := NewReporter(FormatJSON)
+	reporter.basePath = base
+	var payload struct {
+		Path     string `json:"path"`
+		Circular []struct {
+			Path []string
+		} `json:"circularViolations"`
+		Size []struct {
+			File string
+		} `json:"sizeViolations"`
+		GodObject []struct {
+			File string
+		} `json:"godObjectViolations"`
+	}
+	if err := json.Unmarshal([]byte(reporter.Format(report)), &payload); err != nil {
+		t.Fatalf("output must be valid JSON: %v", err)
+	}
+
+	want := "services/api/handler/server.go"
+	if payload.Path != "services/api" {
+		t.Errorf("expected report path relative to base, got %q", payload.Path)
+	}
+	if payload.Size[0].File != want || payload.GodObject[0].File != want || payload.Circular[0].Path[0] != want {
+		t.Errorf("expected violation files relative to base, got %+v", payload)
+	}
+	if payload.Circular[0].Path[1] != outside {
+		t.Errorf("expected path outside base to stay absolute, got %q", payload.Circular[0].Path[1])
+	}
+
+	text := NewReporter(FormatText)
+	text.basePath = base
+	text.width = 160
+	out := text.Format(report)
+	if strings.Contains(out, file) {
+		t.Errorf("text output leaked absolute path: %s", out)
+	}
+	if !strings.Contains(out, want+" (handler)") {
+		t.Errorf("expected layer message relative to base: %s", out)
+	}
+	if report.Size[0].File != file {
+		t.Error("relativizing must not mutate the original report")
+	}
+}
+
+func TestReporter_PathsRelativeToAnalyzedDirectoryByDefault(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	file := filepath.Join(repo, "handler", "server.go")
+	report := &StructuralReport{
+		Path:          repo,
+		Score:         &StructuralScore{TotalScore: 95, MaxScore: 100},
+		Size:          []SizeViolation{{File: file, Lines: 600, Threshold: 500}},
+		HasViolations: true,
+	}
+
+	var payload struct {
+		Path string `json:"path"`
+		Size []struct {
+			File string `json:"file"`
+		} `json:"sizeViolations"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &payload); err != nil {
+		t.Fatalf("output must be valid JSON: %v", err)
+	}
+	if payload.Path != "." || payload.Size[0].File != "handler/server.go" {
+		t.Errorf("expected paths relative to the analyzed directory, got %+v", payload)
+	}
+
+	reporter := NewReporter(FormatJSONV1)
+	reporter.absPaths = true
+	if err := json.Unmarshal([]byte(reporter.Format(report)), &payload); err != nil {
+		t.Fatalf("output must be valid JSON: %v", err)
+	}
+	if payload.Size[0].File != file {
+		t.Errorf("expected -abs-paths to keep %q, got %q", file, payload.Size[0].File)
+	}
+}
+
+func TestRelativeToBase_AcceptsEitherSeparator(t *testing.T) {
+	tests := []struct {
+		file, base, want string
+	}{
+		{file: `C:\work\repo\a\b.go`, base: `C:\work\repo`, want: "a/b.go"},
+		{file: `c:\Work\Repo\a\b.go`, base: `C:\work\repo\`, want: "a/b.go"},
+		{file: `C:\work\repo`, base: `C:\work\repo`, want: "."},
+		{file: `C:\work\repository\a.go`, base: `C:\work\repo`, want: `C:\work\repository\a.go`},
+		{file: "/work/repo/a/b.go", base: "/work/repo/", want: "a/b.go"},
+		{file: "/work/other/a.go", base: "/work/repo", want: "/work/other/a.go"},
+		{file: "a/b.go", base: "/work/repo", want: "a/b.go"},
+	}
+	for _, tc := range tests {
+		if got := relativeToBase(tc.file, tc.base); got != tc.want {
+			t.Errorf("relativeToBase(%q, %q): expected %q, got %q", tc.file, tc.base, tc.want, got)
+		}
+	}
+}
@@ -1,12 +1,12 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
-	"path/filepath"
-	"sort"
+	"io"
 	"strings"
+	"time"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
 )
 
 // OutputFormat defines the output format type
@@ -16,6 +16,7 @@ const (
 	FormatText   OutputFormat = "text"
 	FormatJSON   OutputFormat = "json"
 	FormatJSONV1 OutputFormat = "json-v1"
+	FormatEnv    OutputFormat = "env"
 )
 
 // ColoredReporter extends Reporter with colored output support
@@ -40,19 +41,115 @@ type StructuralReport struct {
 	Score         *StructuralScore
 	Circular      []CycleViolation
 	Layer         []LayerViolation
+	// OptIn holds the violations of opt-in rules that are listed as
+	// categories of their own
+	OptIn         OptInViolations
 	Size          []SizeViolation
 	GodObject     []GodObjectViolation
+	Advisory      []AdvisoryViolation
 	Summary       ReportSummary
 	Language      LanguageEvidenceSummary
+	RuleSet       []string
+	Metrics       ReportMetrics
 	HasViolations bool
 }
 
+// OptInViolations holds the violation categories of opt-in rules
+type OptInViolations struct {
+	// FeatureIsolation holds shared packages that depend on feature
+	// packages; they are scored with their own weight
+	FeatureIsolation []FeatureIsolationViolation
+	// DependencyCap holds the external module count exceeding the
+	// dependencies.max_external cap; it is scored with its own weight
+	DependencyCap []DependencyCapViolation
+	// SingleImpl holds interfaces with a single implementation; they are
+	// informational and not scored
+	SingleImpl []SingleImplInterfaceViolation
+}
+
+// ReportMetrics holds per-rule measurements that are reported alongside,
+// but are not part of, the violations
+type ReportMetrics struct {
+	Coverage     []rules.RuleCoverage
+	Cohesion     []rules.StructCohesion
+	Dependencies *DependencyInventory
+	// Sample is set when per-file rules ran on a sample of the files
+	Sample *SampleSpec
+	// Density is the severity-weighted violation density of the run
+	Density *ViolationDensity
+	// Exit is the policy that decides the exit code
+	Exit ExitPolicy
+	// Largest lists the largest files and functions, violating or not
+	Largest *rules.LargestArtifacts
+	// Rules describes the executed rules, for json-v1 consumers
+	Rules []RuleDescriptor
+	// ThirdParty lists the directories detected as copied third-party code
+	ThirdParty []ThirdPartyDir
+	// Cycles matches the cycles against circular.baseline
+	Cycles *CycleTolerance
+	// Packages holds the findings on the package graph
+	Packages PackageStructure
+	// Tree is the annotated directory tree, built for -format tree
+	Tree *DirectoryHealth
+	// Trend compares the score with the history: the -format html
+	// sparkline and the regression check
+	Trend ScoreTrend
+	// RuleDurations is the wall time each executed rule took, by rule ID
+	RuleDurations map[string]time.Duration
+	// Stats sizes the analyzed files and their dependency graph
+	Stats *RepositoryStats
+}
+
+// FeatureIsolationViolation is a shared package that depends on a feature
+// package. Message shows the shortest import chain between them.
+type FeatureIsolationViolation struct {
+	From    string `json:"from"`
+	Message string `json:"message"`
+	// Severity is the severity the rule reported; it is not serialized so
+	// the report schema stays unchanged
+	Severity model.Severity `json:"-"`
+}
+
+// DependencyCapViolation is an external module count above the
+// dependencies.max_external cap. Manifest is the go.mod the modules are
+// required in; Message names the modules new since the previous run.
+type DependencyCapViolation struct {
+	Manifest string `json:"manifest"`
+	Message  string `json:"message"`
+	// Severity is the severity the rule reported; it is not serialized so
+	// the report schema stays unchanged
+	Severity model.Severity `json:"-"`
+}
+
+// AdvisoryViolation is an informational finding from a heuristic rule. It is
+// reported alongside structural violations but carries no score penalty.
+type AdvisoryViolation struct {
+	RuleID  string `json:"ruleId"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// SingleImplInterfaceViolation is an interface implemented by exactly one
+// type of the module, reported as a removal candidate without score penalty
+type SingleImplInterfaceViolation struct {
+	Interface string `json:"interface"`
+	Impl      string `json:"impl"`
+}
+
 type ReportSummary struct {
 	TotalViolations int `json:"totalViolations"`
 	Circular        int `json:"circular"`
 	Layer           int `json:"layer"`
-	Size            int `json:"size"`
-	GodObject       int `json:"godObject"`
+	// FeatureIsolation and DependencyCap are omitted while their opt-in
+	// rules report nothing, so the summary of other reports is unchanged
+	FeatureIsolation int `json:"featureIsolation,omitempty"`
+	DependencyCap    int `json:"dependencyCap,omitempty"`
+	Size             int `json:"size"`
+	GodObject        int `json:"godObject"`
+	// Filtered counts the violations -min-severity left out of the report
+	Filtered int `json:"filtered,omitempty"`
+	// Omitted counts the violations -top left out of the text report
+	Omitted ListingOmissions `json:"-"`
 }
 
 type LanguageEvidenceSummary struct {
@@ -63,6 +160,21 @@ type LanguageEvidenceSummary struct {
 // Reporter handles formatting and displaying structural analysis results
 type Reporter struct {
 	format OutputFormat
+	// width forces the text report width; zero detects the terminal width
+	width int
+	// basePath, when set, makes every reported file path relative to it;
+	// otherwise paths are relative to the analyzed directory
+	basePath string
+	// absPaths keeps file paths absolute when no basePath is set
+	absPaths bool
+	// minSeverity leaves the violation categories below it out of the report
+	minSeverity severityThreshold
+	// listing shortens and groups the violation listings of the text report
+	listing ViolationListing
+	// explain adds the score explanation to text and JSON output
+	explain bool
+	// ascii prints text and tree output without box drawing, arrows and emoji
+	ascii bool
 }
 
 // NewReporter creates a new reporter with the specified format
@@ -79,7 +191,7 @@ func (r *Reporter) GenerateReport(scorer *StructuralScorer, path, version string
 	return &StructuralReport{
 		Version:       version,
 		Path:          path,
-		SchemaVersion: "v2",
+		SchemaVersion: jsonSchemaVersion,
 		Score:         scorer.CalculateScore(),
 		Circular:      violations.Circular,
 		Layer:         violations.Layer,
@@ -99,30 +211,125 @@ func (r *Reporter) GenerateReport(scorer *StructuralScorer, path, version string
 
 // Format formats the report according to the output format
 func (r *Reporter) Format(report *StructuralReport) string {
+	report = filterReportBySeverity(relativizeReport(report, reportBase(report, r.basePath, r.absPaths)), r.minSeverity)
+
 	switch r.format {
-	case FormatJSON:
+	case FormatJSON, FormatJSONLegacy:
 		return r.formatJSON(report)
 	case FormatJSONV1:
 		return r.formatJSONV1(report)
+	case FormatEnv:
+		return formatEnv(report)
+	case FormatSARIF:
+		return formatSARIF(report)
+	case FormatJUnit:
+		return formatJUnit(report)
+	case FormatHTML:
+		return formatHTML(report)
+	case FormatTree:
+		return r.plain(formatDirectoryTree(report.Metrics.Tree))
+	case FormatTreeJSON:
+		return formatDirectoryTreeJSON(report.Metrics.Tree)
+	case FormatMermaid:
+		return formatMermaidDiagram(report.Metrics.Packages.Diagram)
+	case FormatCheckstyle:
+		return formatCheckstyle(report)
+	case FormatMarkdown:
+		return formatMarkdown(report)
+	case FormatJSONL:
+		return formatJSONL(report)
+	case FormatNDJSON:
+		return formatNDJSON(report)
+	case FormatTAP:
+		return formatTAP(report)
+	case FormatBadge:
+		return formatBadge(report)
 	default:
 		return r.formatText(report)
 	}
 }
 
+// Stream writes the report to w. ndjson reports are encoded one record at
+// a time; every other format is formatted first and written at once.
+func (r *Reporter) Stream(w io.Writer, report *StructuralReport) error {
+	if r.format != FormatNDJSON {
+		_, err := io.WriteString(w, r.Format(report))
+		return err
+	}
+	return streamNDJSON(w, filterReportBySeverity(relativizeReport(report, reportBase(report, r.basePath, r.absPaths)), r.minSeverity))
+}
+
 // formatText formats the report as human-readable text
 func (r *Reporter) formatText(report *StructuralReport) string {
 	var sb strings.Builder
+	layout := newTextLayout(r.width)
+	report = sortedReport(report)
+	groups := groupViolations(report, r.listing.GroupBy)
+	report = limitViolations(report, r.listing.Top)
+
+	writeHeader(&sb, layout)
+	writeScoreSection(&sb, report, layout)
+	writeScoreRegression(&sb, report, layout)
+	writeViolationsSummary(&sb, report, layout)
+	writeViolationGroups(&sb, groups, r.listing.GroupBy, layout)
+	writeCircularViolations(&sb, report, layout)
+	writeLayerViolations(&sb, report, layout)
+	writeFeatureIsolationViolations(&sb, report, layout)
+	writeDependencyCapViolations(&sb, report, layout)
+	writeSizeViolations(&sb, report, layout)
+	writeGodObjectViolations(&sb, report, layout)
+	writeAdvisoryViolations(&sb, report, layout)
+	writeSingleImplViolations(&sb, report, layout)
+	writeOrphanPackages(&sb, report, layout)
+	writeCoupling(&sb, report, layout)
+	writeRepositoryStats(&sb, report, layout)
+	writeScoreBreakdown(&sb, report, layout)
+	if r.explain {
+		writeScoreExplanation(&sb, report, layout)
+	}
+
+	return r.plain(sb.String())
+}
+
+// plain returns text as printed: in plain ASCII when the reporter is set to
+func (r *Reporter) plain(text string) string {
+	if r.ascii {
+		return toASCII(text)
+	}
+	return text
+}
 
-	writeHeader(&sb)
-	writeScoreSection(&sb, report)
-	writeViolationsSummary(&sb, report)
-	writeCircularViolations(&sb, report)
-	writeLayerViolations(&sb, report)
-	writeSizeViolations(&sb, report)
-	writeGodObjectViolations(&sb, report)
-	writeScoreBreakdown(&sb, report)
+// FormatColoredText formats the report as width-aware text using the
+// reporter's color formatter
+func (r *ColoredReporter) FormatColoredText(report *StructuralReport) string {
+	report = sortedReport(filterReportBySeverity(relativizeReport(report, reportBase(report, r.basePath, r.absPaths)), r.minSeverity))
+	var sb strings.Builder
+	layout := newTextLayout(r.width)
+	groups := groupViolations(report, r.listing.GroupBy)
+	report = limitViolations(report, r.listing.Top)
+
+	writeHeaderWithColor(&sb, r.formatter, layout)
+	writeScoreSectionWithColor(&sb, report, r.formatter, layout)
+	writeScoreRegressionWithColor(&sb, report, r.formatter, layout)
+	writeViolationsSummaryWithColor(&sb, report, r.formatter, layout)
+	writeViolationGroupsWithColor(&sb, groups, r.listing.GroupBy, r.formatter, layout)
+	writeCircularViolationsWithColor(&sb, report, r.formatter, layout)
+	writeLayerViolationsWithColor(&sb, report, r.formatter, layout)
+	writeFeatureIsolationViolationsWithColor(&sb, report, r.formatter, layout)
+	writeDependencyCapViolationsWithColor(&sb, report, r.formatter, layout)
+	writeSizeViolationsWithColor(&sb, report, r.formatter, layout)
+	writeGodObjectViolationsWithColor(&sb, report, r.formatter, layout)
+	writeAdvisoryViolationsWithColor(&sb, report, r.formatter, layout)
+	writeSingleImplViolationsWithColor(&sb, report, r.formatter, layout)
+	writeOrphanPackagesWithColor(&sb, report, r.formatter, layout)
+	writeCouplingWithColor(&sb, report, r.formatter, layout)
+	writeRepositoryStatsWithColor(&sb, report, r.formatter, layout)
+	writeScoreBreakdownWithColor(&sb, report, r.formatter, layout)
+	if r.explain {
+		writeScoreExplanationWithColor(&sb, report, r.formatter, layout)
+	}
 
-	return sb.String()
+	return r.plain(sb.String())
 }
 
 // formatCyclePath formats a cycle path for display
@@ -143,227 +350,3 @@ func formatCyclePath(path []string) string {
 
 	return result
 }
-
-// formatJSON formats the report as JSON
-func (r *Reporter) formatJSON(report *StructuralReport) string {
-	relPath := normalizeReportPath(report.Path)
-	payload := map[string]interface{}{
-		"version":       report.Version,
-		"schemaVersion": report.SchemaVersion,
-		"path":          relPath,
-		"score": map[string]interface{}{
-			"total":            report.Score.TotalScore,
-			"max":              report.Score.MaxScore,
-			"circularPenalty":  report.Score.CircularPenalty,
-			"layerPenalty":     report.Score.LayerPenalty,
-			"sizePenalty":      report.Score.SizePenalty,
-			"godObjectPenalty": report.Score.GodObjectPenalty,
-		},
-		"summary": map[string]interface{}{
-			"totalViolations": report.Summary.TotalViolations,
-			"circular":        report.Summary.Circular,
-			"layer":           report.Summary.Layer,
-			"size":            report.Summary.Size,
-			"godObject":       report.Summary.GodObject,
-		},
-		"language": map[string]interface{}{
-			"detectedLanguage": report.Language.DetectedLanguage,
-			"confidence":       report.Language.Confidence,
-		},
-		"circularViolations":  sortedCircular(report.Circular),
-		"layerViolations":     sortedLayer(report.Layer),
-		"sizeViolations":      sortedSize(report.Size),
-		"godObjectViolations": sortedGodObject(report.GodObject),
-	}
-	data, err := json.MarshalIndent(payload, "", "  ")
-	if err != nil {
-		return "{}\n"
-	}
-	return string(data) + "\n"
-}
-
-func (r *Reporter) formatJSONV1(report *StructuralReport) string {
-	var sb strings.Builder
-
-	sb.WriteString("{\n")
-	sb.WriteString(fmt.Sprintf("  \"version\": \"%s\",\n", report.Version))
-	sb.WriteString(fmt.Sprintf("  \"path\": \"%s\",\n", report.Path))
-
-	r.formatScoreSection(&sb, report)
-	formatViolationsSection(&sb, report)
-	r.formatCircularViolations(&sb, report)
-	r.formatLayerViolations(&sb, report)
-	r.formatSizeViolations(&sb, report)
-	r.formatGodObjectViolations(&sb, report)
-
-	sb.WriteString("}\n")
-
-	return sb.String()
-}
-
-func normalizeReportPath(path string) string {
-	cleaned := filepath.ToSlash(filepath.Clean(path))
-	if wd, err := os.Getwd(); err == nil {
-		if rel, relErr := filepath.Rel(wd, cleaned); relErr == nil && !strings.HasPrefix(rel, "..") {
-			return filepath.ToSlash(rel)
-		}
-	}
-	return cleaned
-}
-
-func sortedCircular(in []CycleViolation) []CycleViolation {
-	result := append([]CycleViolation(nil), in...)
-	sort.SliceStable(result, func(i, j int) bool {
-		left := strings.Join(result[i].Path, "/")
-		right := strings.Join(result[j].Path, "/")
-		return left < right
-	})
-	return result
-}
-
-func sortedLayer(in []LayerViolation) []LayerViolation {
-	result := append([]LayerViolation(nil), in...)
-	sort.SliceStable(result, func(i, j int) bool {
-		if result[i].From != result[j].From {
-			return result[i].From < result[j].From
-		}
-		if result[i].To != result[j].To {
-			return result[i].To < result[j].To
-		}
-		return result[i].Message < result[j].Message
-	})
-	return result
-}
-
-func sortedSize(in []SizeViolation) []SizeViolation {
-	result := append([]SizeViolation(nil), in...)
-	sort.SliceStable(result, func(i, j int) bool {
-		if result[i].File != result[j].File {
-			return result[i].File < result[j].File
-		}
-		if result[i].Function != result[j].Function {
-			return result[i].Function < result[j].Function
-		}
-		return result[i].Lines < result[j].Lines
-	})
-	return result
-}
-
-func sortedGodObject(in []GodObjectViolation) []GodObjectViolation {
-	result := append([]GodObjectViolation(nil), in...)
-	sort.SliceStable(result, func(i, j int) bool {
-		if result[i].File != result[j].File {
-			return result[i].File < result[j].File
-		}
-		return result[i].StructName < result[j].StructName
-	})
-	return result
-}
-
-// formatScoreSection formats the score section of JSON output
-func (r *Reporter) formatScoreSection(sb *strings.Builder, report *StructuralReport) {
-	sb.WriteString("  \"score\": {\n")
-	sb.WriteString(fmt.Sprintf("    \"total\": %.2f,\n", report.Score.TotalScore))
-	sb.WriteString(fmt.Sprintf("    \"max\": %.2f,\n", report.Score.MaxScore))
-	sb.WriteString(fmt.Sprintf("    \"circularPenalty\": %.2f,\n", report.Score.CircularPenalty))
-	sb.WriteString(fmt.Sprintf("    \"layerPenalty\": %.2f,\n", report.Score.LayerPenalty))
-	sb.WriteString(fmt.Sprintf("    \"sizePenalty\": %.2f,\n", report.Score.SizePenalty))
-	sb.WriteString(fmt.Sprintf("    \"godObjectPenalty\": %.2f\n", report.Score.GodObjectPenalty))
-	sb.WriteString("  },\n")
-}
-
-// formatViolationsSection formats the violations summary section
-func formatViolationsSection(sb *strings.Builder, report *StructuralReport) {
-	sb.WriteString("  \"violations\": {\n")
-	sb.WriteString(fmt.Sprintf("    \"circular\": %d,\n", report.Score.CircularCount))
-	sb.WriteString(fmt.Sprintf("    \"layer\": %d,\n", report.Score.LayerCount))
-	sb.WriteString(fmt.Sprintf("    \"size\": %d,\n", report.Score.SizeCount))
-	sb.WriteString(fmt.Sprintf("    \"godObject\": %d\n", report.Score.GodObjectCount))
-	sb.WriteString("  },\n")
-}
-
-// formatCircularViolations formats circular dependency violations
-func (r *Reporter) formatCircularViolations(sb *strings.Builder, report *StructuralReport) {
-	sb.WriteString("  \"circularViolations\": [\n")
-	for i, v := range report.Circular {
-		sb.WriteString("    {\n")
-		sb.WriteString(fmt.Sprintf("      \"path\": %s,\n", formatStringArray(v.Path)))
-		sb.WriteString(fmt.Sprintf("      \"severity\": \"%s\"\n", v.Severity))
-		sb.WriteString("    }")
-		if i < len(report.Circular)-1 {
-			sb.WriteString(",")
-		}
-		sb.WriteString("\n")
-	}
-	sb.WriteString("  ],\n")
-}
-
-// formatLayerViolations formats layer violations
-func (r *Reporter) formatLayerViolations(sb *strings.Builder, report *StructuralReport) {
-	sb.WriteString("  \"layerViolations\": [\n")
-	for i, v := range report.Layer {
-		sb.WriteString("    {\n")
-		sb.WriteString(fmt.Sprintf("      \"from\": \"%s\",\n", v.From))
-		sb.WriteString(fmt.Sprintf("      \"to\": \"%s\",\n", v.To))
-		sb.WriteString(fmt.Sprintf("      \"message\": \"%s\"\n", v.Message))
-		sb.WriteString("    }")
-		if i < len(report.Layer)-1 {
-			sb.WriteString(",")
-		}
-		sb.WriteString("\n")
-	}
-	sb.WriteString("  ],\n")
-}
-
-// formatSizeViolations formats size violations
-func (r *Reporter) formatSizeViolations(sb *strings.Builder, report *StructuralReport) {
-	sb.WriteString("  \"sizeViolations\": [\n")
-	for i, v := range report.Size {
-		sb.WriteString("    {\n")
-		sb.WriteString(fmt.Sprintf("      \"file\": \"%s\",\n", v.File))
-		sb.WriteString(fmt.Sprintf("      \"function\": \"%s\",\n", v.Function))
-		sb.WriteString(fmt.Sprintf("      \"lines\": %d,\n", v.Lines))
-		sb.WriteString(fmt.Sprintf("      \"threshold\": %d\n", v.Threshold))
-		sb.WriteString("    }")
-		if i < len(report.Size)-1 {
-			sb.WriteString(",")
-		}
-		sb.WriteString("\n")
-	}
-	sb.WriteString("  ],\n")
-}
-
-// formatGodObjectViolations formats god object violations
-func (r *Reporter) formatGodObjectViolations(sb *strings.Builder, report *StructuralReport) {
-	sb.WriteString("  \"godObjectViolations\": [\n")
-	for i, v := range report.GodObject {
-		sb.WriteString("    {\n")
-		sb.WriteString(fmt.Sprintf("      \"struct\": \"%s\",\n", v.StructName))
-		sb.WriteString(fmt.Sprintf("      \"file\": \"%s\",\n", v.File))
-		sb.WriteString(fmt.Sprintf("      \"fields\": %d,\n", v.FieldCount))
-		sb.WriteString(fmt.Sprintf("      \"methods\": %d,\n", v.MethodCount))
-		sb.WriteString("    }")
-		if i < len(report.GodObject)-1 {
-			sb.WriteString(",")
-		}
-		sb.WriteString("\n")
-	}
-	sb.WriteString("  ]\n")
-}
-
-// formatStringArray formats a string array as JSON
-func formatStringArray(arr []string) string {
-	if len(arr) == 0 {
-		return "[]"
-	}
-
-	result := "["
-	for i, s := range arr {
-		result += fmt.Sprintf("\"%s\"", s)
-		if i < len(arr)-1 {
-			result += ", "
-		}
-	}
-	result += "]"
-	return result
-}
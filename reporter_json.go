@@ -0,0 +1,423 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
+)
+
+// jsonReport is the json report. Unlike json-v1 it keeps evolving: new
+// sections are added as optional fields, existing field names stay. It is
+// deprecated in favor of json-v1 and always says so.
+type jsonReport struct {
+	Version       string                  `json:"version"`
+	SchemaVersion string                  `json:"schemaVersion"`
+	Deprecated    bool                    `json:"deprecated"`
+	Path          string                  `json:"path"`
+	Score         jsonScore               `json:"score"`
+	Summary       ReportSummary           `json:"summary"`
+	Language      LanguageEvidenceSummary `json:"language"`
+	RuleSet       []string                `json:"ruleSet,omitempty"`
+	Sample        *SampleSpec             `json:"sample,omitempty"`
+	RuleCoverage  []rules.RuleCoverage    `json:"ruleCoverage,omitempty"`
+	jsonViolationLists
+	Metrics *jsonMetrics `json:"metrics,omitempty"`
+	// Explanation is set with -explain
+	Explanation *ScoreExplanation `json:"explanation,omitempty"`
+}
+
+// jsonViolationLists holds the violation sections of the json report.
+// The structural ones are always present; the others only when non-empty.
+type jsonViolationLists struct {
+	CircularViolations            []CycleViolation               `json:"circularViolations"`
+	LayerViolations               []LayerViolation               `json:"layerViolations"`
+	SizeViolations                []SizeViolation                `json:"sizeViolations"`
+	GodObjectViolations           []GodObjectViolation           `json:"godObjectViolations"`
+	FeatureIsolationViolations    []FeatureIsolationViolation    `json:"featureIsolationViolations,omitempty"`
+	DependencyCapViolations       []DependencyCapViolation       `json:"dependencyCapViolations,omitempty"`
+	AdvisoryViolations            []AdvisoryViolation            `json:"advisoryViolations,omitempty"`
+	SingleImplInterfaceViolations []SingleImplInterfaceViolation `json:"singleImplInterfaceViolations,omitempty"`
+}
+
+// jsonScore is the json score object. A score that records its model also
+// carries the model name, and the breakdown when the model is not the
+// weighted one.
+type jsonScore struct {
+	Total            float64 `json:"total"`
+	Max              float64 `json:"max"`
+	CircularPenalty  float64 `json:"circularPenalty"`
+	LayerPenalty     float64 `json:"layerPenalty"`
+	SizePenalty      float64 `json:"sizePenalty"`
+	GodObjectPenalty float64 `json:"godObjectPenalty"`
+	// FeatureIsolationPenalty and DependencyCapPenalty are omitted while
+	// their opt-in rules report nothing
+	FeatureIsolationPenalty float64          `json:"featureIsolationPenalty,omitempty"`
+	DependencyCapPenalty    float64          `json:"dependencyCapPenalty,omitempty"`
+	Model                   string           `json:"model,omitempty"`
+	Breakdown               []ScoreComponent `json:"breakdown,omitempty"`
+}
+
+// jsonMetrics holds the optional metrics of the json report; it is omitted
+// when none is set
+type jsonMetrics struct {
+	StructCohesion []rules.StructCohesion  `json:"structCohesion,omitempty"`
+	Dependencies   *DependencyInventory    `json:"dependencies,omitempty"`
+	Largest        *rules.LargestArtifacts `json:"largest,omitempty"`
+	ThirdPartyCode []ThirdPartyDir         `json:"thirdPartyCode,omitempty"`
+	CycleBaseline  *CycleTolerance         `json:"cycleBaseline,omitempty"`
+	Density        *ViolationDensity       `json:"density,omitempty"`
+	TimedOutRules  []string                `json:"timedOutRules,omitempty"`
+	Coupling       []PackageCoupling       `json:"coupling,omitempty"`
+	OrphanPackages []string                `json:"orphanPackages,omitempty"`
+	Stats          *RepositoryStats        `json:"stats,omitempty"`
+	Regression     *ScoreRegression        `json:"regression,omitempty"`
+}
+
+// reportFindings is the canonical findings model both json writers print,
+// so the legacy json and json-v1 reports of a run hold the same structural
+// violations. json-v1 keeps the pipeline's order; the legacy json sorts.
+type reportFindings struct {
+	Circular         []CycleViolation
+	Layer            []LayerViolation
+	FeatureIsolation []FeatureIsolationViolation
+	DependencyCap    []DependencyCapViolation
+	Size             []SizeViolation
+	GodObject        []GodObjectViolation
+}
+
+func newReportFindings(report *StructuralReport) reportFindings {
+	return reportFindings{Circular: report.Circular, Layer: report.Layer, FeatureIsolation: report.OptIn.FeatureIsolation, DependencyCap: report.OptIn.DependencyCap, Size: report.Size, GodObject: report.GodObject}
+}
+
+// sorted returns the findings in the legacy json's stable order
+func (f reportFindings) sorted() reportFindings {
+	return reportFindings{
+		Circular:         sortedCircular(f.Circular),
+		Layer:            sortedLayer(f.Layer),
+		FeatureIsolation: sortedFeatureIsolation(f.FeatureIsolation),
+		DependencyCap:    sortedDependencyCap(f.DependencyCap),
+		Size:             sortedSize(f.Size),
+		GodObject:        sortedGodObject(f.GodObject),
+	}
+}
+
+// formatJSON formats the report as JSON
+func (r *Reporter) formatJSON(report *StructuralReport) string {
+	findings := newReportFindings(report).sorted()
+	payload := jsonReport{
+		Version:       report.Version,
+		SchemaVersion: report.SchemaVersion,
+		Deprecated:    true,
+		Path:          normalizeReportPath(report.Path),
+		Score:         newJSONScore(report.Score),
+		Summary:       report.Summary,
+		Language:      report.Language,
+		RuleSet:       report.RuleSet,
+		Sample:        report.Metrics.Sample,
+		RuleCoverage:  report.Metrics.Coverage,
+		jsonViolationLists: jsonViolationLists{
+			CircularViolations:            findings.Circular,
+			LayerViolations:               findings.Layer,
+			SizeViolations:                findings.Size,
+			GodObjectViolations:           findings.GodObject,
+			FeatureIsolationViolations:    findings.FeatureIsolation,
+			DependencyCapViolations:       findings.DependencyCap,
+			AdvisoryViolations:            report.Advisory,
+			SingleImplInterfaceViolations: report.OptIn.SingleImpl,
+		},
+		Metrics: newJSONMetrics(report.Metrics),
+	}
+	if r.explain {
+		payload.Explanation = newScoreExplanation(report.Score)
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "{}\n"
+	}
+	return string(data) + "\n"
+}
+
+func newJSONScore(score *StructuralScore) jsonScore {
+	if score == nil {
+		return jsonScore{}
+	}
+	return jsonScore{
+		Total:                   score.TotalScore,
+		Max:                     score.MaxScore,
+		CircularPenalty:         score.CircularPenalty,
+		LayerPenalty:            score.LayerPenalty,
+		SizePenalty:             score.SizePenalty,
+		GodObjectPenalty:        score.GodObjectPenalty,
+		FeatureIsolationPenalty: score.OptIn.FeatureIsolationPenalty,
+		DependencyCapPenalty:    score.OptIn.DependencyCapPenalty,
+		Model:                   score.Model,
+		Breakdown:               score.Breakdown,
+	}
+}
+
+// newJSONMetrics returns the json metrics section, or nil when the report
+// has no metric to show
+func newJSONMetrics(metrics ReportMetrics) *jsonMetrics {
+	out := &jsonMetrics{
+		StructCohesion: metrics.Cohesion,
+		Dependencies:   metrics.Dependencies,
+		Largest:        metrics.Largest,
+		ThirdPartyCode: metrics.ThirdParty,
+		CycleBaseline:  metrics.Cycles,
+		Density:        metrics.Density,
+		TimedOutRules:  timedOutRules(metrics.Rules),
+		Coupling:       metrics.Packages.Coupling,
+		OrphanPackages: metrics.Packages.Orphans,
+		Stats:          metrics.Stats,
+		Regression:     metrics.Trend.Regression,
+	}
+	if len(out.StructCohesion) == 0 && out.Dependencies == nil && out.Largest == nil && len(out.ThirdPartyCode) == 0 && out.CycleBaseline == nil && out.Density == nil && len(out.TimedOutRules) == 0 && len(out.Coupling) == 0 && len(out.OrphanPackages) == 0 && out.Stats == nil && out.Regression == nil {
+		return nil
+	}
+	return out
+}
+
+// formatJSONV1 formats the report in the json-v1 schema. Violations keep
+// the report's order, which the pipeline already sorts.
+func (r *Reporter) formatJSONV1(report *StructuralReport) string {
+	doc := newJSONV1Document(report)
+	if r.explain {
+		doc.Explanation = newScoreExplanation(report.Score)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "{}\n"
+	}
+	return string(data) + "\n"
+}
+
+func normalizeReportPath(path string) string {
+	cleaned := filepath.ToSlash(filepath.Clean(path))
+	if wd, err := os.Getwd(); err == nil {
+		if rel, relErr := filepath.Rel(wd, cleaned); relErr == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return cleaned
+}
+
+func sortedCircular(in []CycleViolation) []CycleViolation {
+	result := append([]CycleViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		left := strings.Join(result[i].Path, "/")
+		right := strings.Join(result[j].Path, "/")
+		return left < right
+	})
+	return result
+}
+
+func sortedLayer(in []LayerViolation) []LayerViolation {
+	result := append([]LayerViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].From != result[j].From {
+			return result[i].From < result[j].From
+		}
+		if result[i].To != result[j].To {
+			return result[i].To < result[j].To
+		}
+		return result[i].Message < result[j].Message
+	})
+	return result
+}
+
+func sortedFeatureIsolation(in []FeatureIsolationViolation) []FeatureIsolationViolation {
+	result := append([]FeatureIsolationViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].From != result[j].From {
+			return result[i].From < result[j].From
+		}
+		return result[i].Message < result[j].Message
+	})
+	return result
+}
+
+func sortedDependencyCap(in []DependencyCapViolation) []DependencyCapViolation {
+	result := append([]DependencyCapViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Manifest != result[j].Manifest {
+			return result[i].Manifest < result[j].Manifest
+		}
+		return result[i].Message < result[j].Message
+	})
+	return result
+}
+
+func sortedSize(in []SizeViolation) []SizeViolation {
+	result := append([]SizeViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		if result[i].Function != result[j].Function {
+			return result[i].Function < result[j].Function
+		}
+		return result[i].Lines < result[j].Lines
+	})
+	return result
+}
+
+func sortedGodObject(in []GodObjectViolation) []GodObjectViolation {
+	result := append([]GodObjectViolation(nil), in...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].StructName < result[j].StructName
+	})
+	return result
+}
+
+// jsonSchemaVersion is the schemaVersion every -format json report carries
+const jsonSchemaVersion = "v2"
+
+// jsonV1SchemaVersion is the schemaVersion every json-v1 report carries
+const jsonV1SchemaVersion = 1
+
+// jsonV1Document is the json-v1 report. The schema is frozen: fields are
+// never renamed or removed, and findings of rule categories added later
+// only appear in the json format.
+type jsonV1Document struct {
+	SchemaVersion       int                        `json:"schemaVersion"`
+	Version             string                     `json:"version"`
+	Path                string                     `json:"path"`
+	Sample              *SampleSpec                `json:"sample,omitempty"`
+	Rules               []RuleDescriptor           `json:"rules,omitempty"`
+	Coupling            []PackageCoupling          `json:"coupling,omitempty"`
+	OrphanPackages      []string                   `json:"orphanPackages,omitempty"`
+	Score               jsonV1Score                `json:"score"`
+	Violations          jsonV1Counts               `json:"violations"`
+	CircularViolations  []jsonV1CycleViolation     `json:"circularViolations"`
+	LayerViolations     []jsonV1LayerViolation     `json:"layerViolations"`
+	SizeViolations      []jsonV1SizeViolation      `json:"sizeViolations"`
+	GodObjectViolations []jsonV1GodObjectViolation `json:"godObjectViolations"`
+	// Explanation is set with -explain
+	Explanation *ScoreExplanation `json:"explanation,omitempty"`
+}
+
+type jsonV1Score struct {
+	Total            float64 `json:"total"`
+	Max              float64 `json:"max"`
+	CircularPenalty  float64 `json:"circularPenalty"`
+	LayerPenalty     float64 `json:"layerPenalty"`
+	SizePenalty      float64 `json:"sizePenalty"`
+	GodObjectPenalty float64 `json:"godObjectPenalty"`
+}
+
+type jsonV1Counts struct {
+	Circular  int `json:"circular"`
+	Layer     int `json:"layer"`
+	Size      int `json:"size"`
+	GodObject int `json:"godObject"`
+	// Filtered counts the violations -min-severity left out of the lists
+	Filtered int `json:"filtered,omitempty"`
+}
+
+type jsonV1CycleViolation struct {
+	Path     []string       `json:"path"`
+	Severity model.Severity `json:"severity"`
+}
+
+type jsonV1LayerViolation struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+type jsonV1SizeViolation struct {
+	File      string `json:"file"`
+	Line      int    `json:"line,omitempty"`
+	StartLine int    `json:"startLine,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	Function  string `json:"function"`
+	Lines     int    `json:"lines"`
+	Threshold int    `json:"threshold"`
+}
+
+type jsonV1GodObjectViolation struct {
+	Struct    string `json:"struct"`
+	File      string `json:"file"`
+	Line      int    `json:"line,omitempty"`
+	StartLine int    `json:"startLine,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	Fields    int    `json:"fields"`
+	Methods   int    `json:"methods"`
+}
+
+// newJSONV1Document maps a report's findings onto the json-v1 schema.
+// Violation lists are never nil, so empty sections still print as [].
+func newJSONV1Document(report *StructuralReport) jsonV1Document {
+	findings := newReportFindings(report)
+	score := report.Score
+	if score == nil {
+		score = &StructuralScore{}
+	}
+	doc := jsonV1Document{
+		SchemaVersion:       jsonV1SchemaVersion,
+		Version:             report.Version,
+		Path:                report.Path,
+		Sample:              report.Metrics.Sample,
+		Rules:               report.Metrics.Rules,
+		Coupling:            report.Metrics.Packages.Coupling,
+		OrphanPackages:      report.Metrics.Packages.Orphans,
+		Score:               newJSONV1Score(score),
+		Violations:          newJSONV1Counts(report, score),
+		CircularViolations:  make([]jsonV1CycleViolation, 0, len(report.Circular)),
+		LayerViolations:     make([]jsonV1LayerViolation, 0, len(report.Layer)),
+		SizeViolations:      make([]jsonV1SizeViolation, 0, len(report.Size)),
+		GodObjectViolations: make([]jsonV1GodObjectViolation, 0, len(report.GodObject)),
+	}
+	for _, v := range findings.Circular {
+		doc.CircularViolations = append(doc.CircularViolations, newJSONV1CycleViolation(v))
+	}
+	for _, v := range findings.Layer {
+		doc.LayerViolations = append(doc.LayerViolations, newJSONV1LayerViolation(v))
+	}
+	for _, v := range findings.Size {
+		doc.SizeViolations = append(doc.SizeViolations, newJSONV1SizeViolation(v))
+	}
+	for _, v := range findings.GodObject {
+		doc.GodObjectViolations = append(doc.GodObjectViolations, newJSONV1GodObjectViolation(v))
+	}
+	return doc
+}
+
+func newJSONV1Score(score *StructuralScore) jsonV1Score {
+	return jsonV1Score{
+		Total:            score.TotalScore,
+		Max:              score.MaxScore,
+		CircularPenalty:  score.CircularPenalty,
+		LayerPenalty:     score.LayerPenalty,
+		SizePenalty:      score.SizePenalty,
+		GodObjectPenalty: score.GodObjectPenalty,
+	}
+}
+
+func newJSONV1Counts(report *StructuralReport, score *StructuralScore) jsonV1Counts {
+	return jsonV1Counts{Circular: score.CircularCount, Layer: score.LayerCount, Size: score.SizeCount, GodObject: score.GodObjectCount, Filtered: report.Summary.Filtered}
+}
+
+func newJSONV1CycleViolation(v CycleViolation) jsonV1CycleViolation {
+	return jsonV1CycleViolation{Path: v.Path, Severity: v.Severity}
+}
+
+func newJSONV1LayerViolation(v LayerViolation) jsonV1LayerViolation {
+	return jsonV1LayerViolation{From: v.From, To: v.To, Message: v.Message}
+}
+
+func newJSONV1SizeViolation(v SizeViolation) jsonV1SizeViolation {
+	return jsonV1SizeViolation{File: v.File, Line: v.Line, StartLine: v.StartLine, EndLine: v.EndLine, Function: v.Function, Lines: v.Lines, Threshold: v.Threshold}
+}
+
+func newJSONV1GodObjectViolation(v GodObjectViolation) jsonV1GodObjectViolation {
+	return jsonV1GodObjectViolation{Struct: v.StructName, File: v.File, Line: v.Line, StartLine: v.StartLine, EndLine: v.EndLine, Fields: v.FieldCount, Methods: v.MethodCount}
+}
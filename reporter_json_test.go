@@ -2,9 +2,13 @@ package main
 
 import (
 	"encoding/json"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
 )
 
 func TestReporter_JSONV2_ContainsSchemaAndSummary(t *testing.T) {
@@ -32,6 +36,27 @@ func TestReporter_JSONV2_ContainsSchemaAndSummary(t *testing.T) {
 	}
 }
 
+func TestAnalysisService_JSONCarriesSchemaVersion(t *testing.T) {
+	root := t.TempDir()
+	writeServiceFixture(t, root, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.21\n",
+		"main.go": "package main\n\nfunc main() {}\n",
+	})
+
+	out := captureStdout(t, func() {
+		NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatJSON)})
+	})
+	var doc struct {
+		SchemaVersion string `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("expected a json report on stdout: %v\n%s", err, out)
+	}
+	if doc.SchemaVersion != jsonSchemaVersion {
+		t.Errorf("expected schemaVersion %q, got %q", jsonSchemaVersion, doc.SchemaVersion)
+	}
+}
+
 func TestReporter_JSONV1_CompatibilitySwitch(t *testing.T) {
 	reporter := NewReporter(FormatJSONV1)
 	report := &StructuralReport{
@@ -41,8 +66,8 @@ func TestReporter_JSONV1_CompatibilitySwitch(t *testing.T) {
 	}
 
 	jsonOut := reporter.Format(report)
-	if strings.Contains(jsonOut, "schemaVersion") {
-		t.Fatalf("v1 output must not include schemaVersion: %s", jsonOut)
+	if !strings.Contains(jsonOut, "\"schemaVersion\": 1,") {
+		t.Fatalf("v1 output must declare schemaVersion 1: %s", jsonOut)
 	}
 	if strings.Contains(jsonOut, "\"summary\"") {
 		t.Fatalf("v1 output must not include summary section: %s", jsonOut)
@@ -70,15 +95,16 @@ func TestReporter_JSONV1_GoldenParity(t *testing.T) {
 
 	got := reporter.Format(report)
 	want := "{\n" +
+		"  \"schemaVersion\": 1,\n" +
 		"  \"version\": \"0.5.0-dev\",\n" +
 		"  \"path\": \"demo/path\",\n" +
 		"  \"score\": {\n" +
-		"    \"total\": 90.00,\n" +
-		"    \"max\": 100.00,\n" +
-		"    \"circularPenalty\": 0.00,\n" +
-		"    \"layerPenalty\": 0.00,\n" +
-		"    \"sizePenalty\": 3.00,\n" +
-		"    \"godObjectPenalty\": 5.00\n" +
+		"    \"total\": 90,\n" +
+		"    \"max\": 100,\n" +
+		"    \"circularPenalty\": 0,\n" +
+		"    \"layerPenalty\": 0,\n" +
+		"    \"sizePenalty\": 3,\n" +
+		"    \"godObjectPenalty\": 5\n" +
 		"  },\n" +
 		"  \"violations\": {\n" +
 		"    \"circular\": 0,\n" +
@@ -86,14 +112,10 @@ func TestReporter_JSONV1_GoldenParity(t *testing.T) {
 		"    \"size\": 1,\n" +
 		"    \"godObject\": 1\n" +
 		"  },\n" +
-		"  \"circularViolations\": [\n" +
-		"  ],\n" +
-		"  \"layerViolations\": [\n" +
-		"  ],\n" +
-		"  \"sizeViolations\": [\n" +
-		"  ],\n" +
-		"  \"godObjectViolations\": [\n" +
-		"  ]\n" +
+		"  \"circularViolations\": [],\n" +
+		"  \"layerViolations\": [],\n" +
+		"  \"sizeViolations\": [],\n" +
+		"  \"godObjectViolations\": []\n" +
 		"}\n"
 
 	if got != want {
@@ -101,6 +123,43 @@ func TestReporter_JSONV1_GoldenParity(t *testing.T) {
 	}
 }
 
+func TestReporter_JSONV1_OmitsCategoriesAddedLater(t *testing.T) {
+	report := &StructuralReport{
+		Version:  "0.5.0-dev",
+		Path:     "demo/path",
+		Score:    &StructuralScore{TotalScore: 100, MaxScore: 100, Model: ScoreModelWeighted},
+		Advisory: []AdvisoryViolation{{File: "a.go", Message: "advice"}},
+		OptIn:    OptInViolations{SingleImpl: []SingleImplInterfaceViolation{{Interface: "Store", Impl: "sqlStore"}}},
+	}
+
+	var v1 map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &v1); err != nil {
+		t.Fatalf("invalid json-v1 output: %v", err)
+	}
+	frozen := []string{"schemaVersion", "version", "path", "score", "violations", "circularViolations", "layerViolations", "sizeViolations", "godObjectViolations"}
+	if len(v1) != len(frozen) {
+		t.Fatalf("expected exactly the frozen json-v1 keys %v, got %d keys", frozen, len(v1))
+	}
+	for _, key := range frozen {
+		if _, ok := v1[key]; !ok {
+			t.Errorf("json-v1 output is missing %q", key)
+		}
+	}
+	if strings.Contains(string(v1["score"]), "model") {
+		t.Fatalf("json-v1 score must keep its frozen fields, got %s", v1["score"])
+	}
+
+	var evolving map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSON).Format(report)), &evolving); err != nil {
+		t.Fatalf("invalid json output: %v", err)
+	}
+	for _, key := range []string{"advisoryViolations", "singleImplInterfaceViolations"} {
+		if _, ok := evolving[key]; !ok {
+			t.Errorf("json output is missing %q", key)
+		}
+	}
+}
+
 func TestReporter_JSONV2_GoldenStableOrderingAndSchema(t *testing.T) {
 	reporter := NewReporter(FormatJSON)
 	report := &StructuralReport{
@@ -117,7 +176,7 @@ func TestReporter_JSONV2_GoldenStableOrderingAndSchema(t *testing.T) {
 		},
 		Summary:  ReportSummary{TotalViolations: 2, Circular: 1, Layer: 0, Size: 1, GodObject: 0},
 		Language: LanguageEvidenceSummary{DetectedLanguage: "Go", Confidence: 0.91},
-		Circular: []CycleViolation{{Path: []string{"b", "a"}, Severity: "critical"}, {Path: []string{"a", "b"}, Severity: "critical"}},
+		Circular: []CycleViolation{{Path: []string{"b", "a"}, Severity: model.SeverityCritical}, {Path: []string{"a", "b"}, Severity: model.SeverityCritical}},
 		Size:     []SizeViolation{{File: "z.go", Function: "f", Lines: 100, Threshold: 80}, {File: "a.go", Function: "f", Lines: 90, Threshold: 80}},
 	}
 
@@ -180,3 +239,160 @@ func TestReporter_JSON_EscapesUntrustedFields(t *testing.T) {
 		t.Fatalf("escaped output must still be valid json: %v", err)
 	}
 }
+
+func TestReporter_JSON_IncludesLargestArtifacts(t *testing.T) {
+	report := &StructuralReport{
+		Version: "0.5.0-dev",
+		Path:    "/repo/demo",
+		Score:   &StructuralScore{TotalScore: 100, MaxScore: 100},
+		Metrics: ReportMetrics{Largest: &rules.LargestArtifacts{
+			Files:            []rules.FileSize{{File: "main.go", Lines: 120}},
+			FunctionsByLines: []rules.FunctionSize{{Function: "main", File: "main.go", Line: 3, Lines: 40, Complexity: 2}},
+		}},
+	}
+
+	var payload struct {
+		Metrics struct {
+			Largest rules.LargestArtifacts `json:"largest"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSON).Format(report)), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(payload.Metrics.Largest.Files) != 1 || payload.Metrics.Largest.FunctionsByLines[0].Lines != 40 {
+		t.Fatalf("expected largest artifacts under metrics, got %+v", payload.Metrics.Largest)
+	}
+
+	req, err := parseAnalyzeFlags([]string{"-no-largest", "."})
+	if err != nil || !req.Options.NoLargest {
+		t.Fatalf("expected -no-largest to be parsed, got %+v, %v", req, err)
+	}
+}
+
+func TestReporter_JSONV1_DescribesEveryViolatedRule(t *testing.T) {
+	dir := t.TempDir()
+	graph := NewDependencyGraph()
+	for _, name := range []string{"a.go", "b.go"} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte("package a\n\n"+strings.Repeat("var _ = 0\n", 600)), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		graph.AddNode(file)
+	}
+	graph.AddEdge(filepath.Join(dir, "a.go"), filepath.Join(dir, "b.go"))
+	graph.AddEdge(filepath.Join(dir, "b.go"), filepath.Join(dir, "a.go"))
+
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	summary := runInternalRulePipeline(dir, graph, cfg, nil, nil)
+	report := buildReportFromRuleViolations(dir, version, cfg, summary.result.Violations)
+	report.Metrics.Rules = summary.descriptors
+
+	var payload struct {
+		Rules []RuleDescriptor `json:"rules"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	described := make(map[string]RuleDescriptor)
+	for _, rule := range payload.Rules {
+		described[rule.Name] = rule
+	}
+	if len(summary.result.Violations) == 0 {
+		t.Fatal("expected the fixture to have violations")
+	}
+	for _, v := range summary.result.Violations {
+		if _, ok := described[v.RuleID]; !ok {
+			t.Errorf("violation rule %s is missing from the rules array", v.RuleID)
+		}
+	}
+
+	size := described["rule.size"]
+	if size.Description == "" || size.Severity != model.SeverityWarning || size.Weight != 3 || size.DocsAnchor != "rule-size" {
+		t.Fatalf("unexpected size rule descriptor: %+v", size)
+	}
+	if size.Thresholds["max_file_lines"] != 500 {
+		t.Fatalf("expected the size threshold in effect, got %v", size.Thresholds)
+	}
+}
+
+func TestReporter_JSONV1_RoundTripsSpecialCharactersInPaths(t *testing.T) {
+	paths := []string{`C:\repo\svc\"quoted".go`, "src/Ünïcode/日本.go", `weird\path\with\tab.go`}
+	report := &StructuralReport{
+		Version:   "0.5.0-dev",
+		Path:      `C:\repo "demo"`,
+		Score:     &StructuralScore{TotalScore: 80, MaxScore: 100, SizeCount: 1, GodObjectCount: 1},
+		Circular:  []CycleViolation{{Path: paths, Severity: model.SeverityCritical}},
+		Layer:     []LayerViolation{{From: paths[0], To: paths[1], Message: paths[0] + " -> " + paths[1]}},
+		Size:      []SizeViolation{{File: paths[2], Function: "Do\"It", Lines: 120, Threshold: 80}},
+		GodObject: []GodObjectViolation{{StructName: "Mgr", File: paths[1], FieldCount: 20, MethodCount: 3}},
+	}
+
+	reporter := NewReporter(FormatJSONV1)
+	reporter.absPaths = true
+	var doc jsonV1Document
+	if err := json.Unmarshal([]byte(reporter.Format(report)), &doc); err != nil {
+		t.Fatalf("json-v1 output must be valid JSON: %v", err)
+	}
+	if doc.Path != report.Path {
+		t.Fatalf("expected path %q, got %q", report.Path, doc.Path)
+	}
+	if len(doc.CircularViolations) != 1 || strings.Join(doc.CircularViolations[0].Path, "|") != strings.Join(paths, "|") {
+		t.Fatalf("cycle path did not round-trip: %+v", doc.CircularViolations)
+	}
+	if doc.LayerViolations[0].Message != report.Layer[0].Message || doc.LayerViolations[0].From != paths[0] {
+		t.Fatalf("layer violation did not round-trip: %+v", doc.LayerViolations[0])
+	}
+	if doc.SizeViolations[0].File != paths[2] || doc.SizeViolations[0].Function != "Do\"It" {
+		t.Fatalf("size violation did not round-trip: %+v", doc.SizeViolations[0])
+	}
+	if doc.GodObjectViolations[0].File != paths[1] || doc.GodObjectViolations[0].Fields != 20 {
+		t.Fatalf("god object violation did not round-trip: %+v", doc.GodObjectViolations[0])
+	}
+}
+
+func TestReporter_JSON_RoundTripsSpecialCharactersInPaths(t *testing.T) {
+	file := `C:\repo\"quoted"\Ünïcode.go`
+	report := &StructuralReport{
+		Version:       "0.5.0-dev",
+		SchemaVersion: "v2",
+		Path:          "demo",
+		Score:         &StructuralScore{TotalScore: 97, MaxScore: 100},
+		Size:          []SizeViolation{{File: file, Lines: 600, Threshold: 500}},
+	}
+
+	var payload struct {
+		SizeViolations []SizeViolation `json:"sizeViolations"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSON).Format(report)), &payload); err != nil {
+		t.Fatalf("json output must be valid JSON: %v", err)
+	}
+	if len(payload.SizeViolations) != 1 || payload.SizeViolations[0].File != file {
+		t.Fatalf("size violation path did not round-trip: %+v", payload.SizeViolations)
+	}
+}
+
+func TestReporter_JSON_ValidWithQuotesInPaths(t *testing.T) {
+	file := `svc/"quoted"\back` + "\nslash.go"
+	report := &StructuralReport{
+		Version:       "0.5.0-dev",
+		SchemaVersion: "v2",
+		Path:          `repo "demo"`,
+		Score:         &StructuralScore{TotalScore: 97, MaxScore: 100},
+		Circular:      []CycleViolation{{Path: []string{file, "b.go"}}},
+		Layer:         []LayerViolation{{From: file, To: "b.go", Message: `"` + file + `" imports b.go`}},
+		Size:          []SizeViolation{{File: file, Lines: 600, Threshold: 500}},
+		Advisory:      []AdvisoryViolation{{File: file, Message: "quote \" and backslash \\"}},
+	}
+
+	out := NewReporter(FormatJSON).Format(report)
+	if !json.Valid([]byte(out)) {
+		t.Fatalf("expected valid JSON, got:\n%s", out)
+	}
+	var payload jsonReport
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("expected the report to round-trip: %v", err)
+	}
+	if payload.SchemaVersion != "v2" || payload.SizeViolations[0].File != file || payload.CircularViolations[0].Path[0] != file {
+		t.Fatalf("expected paths to round-trip unchanged, got %+v", payload)
+	}
+}
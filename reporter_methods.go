@@ -3,131 +3,299 @@ package main
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
-func writeHeader(sb *strings.Builder) {
-	sb.WriteString("╔═══════════════════════════════════════════════════════════╗\n")
-	sb.WriteString("║          RepoDoctor Structural Analysis Report           ║\n")
-	sb.WriteString("╚═══════════════════════════════════════════════════════════╝\n\n")
+func writeHeader(sb *strings.Builder, layout *textLayout) {
+	for _, line := range layout.headerLines() {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("\n")
 }
 
-func writeScoreSection(sb *strings.Builder, report *StructuralReport) {
+func writeScoreSection(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
 	sb.WriteString(fmt.Sprintf("Version: %s\n", report.Version))
-	sb.WriteString(fmt.Sprintf("Path: %s\n\n", report.Path))
+	sb.WriteString(fmt.Sprintf("Path: %s\n", layout.fitPath(report.Path, len("Path: "))))
+	if report.Metrics.Sample != nil {
+		sb.WriteString(formatSampleLabel(report.Metrics.Sample))
+	}
+	sb.WriteString("\n")
 
-	sb.WriteString("┌───────────────────────────────────────────────────────────┐\n")
-	sb.WriteString("│  STRUCTURAL HEALTH SCORE                                  │\n")
-	sb.WriteString("└───────────────────────────────────────────────────────────┘\n")
+	writeSectionBox(sb, layout, "STRUCTURAL HEALTH SCORE")
 
+	scale := scoreScale(report.Score)
+	percent := report.Score.TotalScore / scale * 100
 	scoreIndicator := "✓"
-	if report.Score.TotalScore < 70 {
+	if percent < 70 {
 		scoreIndicator = "⚠"
 	}
-	if report.Score.TotalScore < 50 {
+	if percent < 50 {
 		scoreIndicator = "✗"
 	}
 
-	sb.WriteString(fmt.Sprintf("%s Score: %.1f / 100.0\n\n", scoreIndicator, report.Score.TotalScore))
+	sb.WriteString(fmt.Sprintf("%s Score: %.1f / %.1f\n", scoreIndicator, report.Score.TotalScore, scale))
+	if report.Metrics.Density != nil {
+		sb.WriteString(formatDensityLine(report.Metrics.Density))
+	}
+	sb.WriteString("\n")
 }
 
-func writeViolationsSummary(sb *strings.Builder, report *StructuralReport) {
-	sb.WriteString("┌───────────────────────────────────────────────────────────┐\n")
-	sb.WriteString("│  VIOLATIONS SUMMARY                                       │\n")
-	sb.WriteString("└───────────────────────────────────────────────────────────┘\n")
+func writeViolationsSummary(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	writeSectionBox(sb, layout, "VIOLATIONS SUMMARY")
 	sb.WriteString(fmt.Sprintf("Total Violations: %d\n", report.Score.ViolationCount))
 	sb.WriteString(fmt.Sprintf("  - Circular Dependencies: %d\n", report.Score.CircularCount))
 	sb.WriteString(fmt.Sprintf("  - Layer Violations: %d\n", report.Score.LayerCount))
 	sb.WriteString(fmt.Sprintf("  - Size Violations: %d\n", report.Score.SizeCount))
-	sb.WriteString(fmt.Sprintf("  - God Objects: %d\n\n", report.Score.GodObjectCount))
+	sb.WriteString(fmt.Sprintf("  - God Objects: %d\n", report.Score.GodObjectCount))
+	if report.Score.OptIn.FeatureIsolationCount > 0 {
+		sb.WriteString(fmt.Sprintf("  - Feature Isolation Violations: %d\n", report.Score.OptIn.FeatureIsolationCount))
+	}
+	if report.Score.OptIn.DependencyCapCount > 0 {
+		sb.WriteString(fmt.Sprintf("  - Dependency Cap Violations: %d\n", report.Score.OptIn.DependencyCapCount))
+	}
+	if report.Summary.Filtered > 0 {
+		sb.WriteString(formatFilteredNote(report.Summary.Filtered) + "\n")
+	}
+	sb.WriteString("\n")
 }
 
-func writeCircularViolations(sb *strings.Builder, report *StructuralReport) {
+func writeCircularViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
 	if len(report.Circular) == 0 {
 		return
 	}
 
-	sb.WriteString("┌───────────────────────────────────────────────────────────┐\n")
-	sb.WriteString("│  CIRCULAR DEPENDENCIES [CRITICAL]                         │\n")
-	sb.WriteString("└───────────────────────────────────────────────────────────┘\n")
+	writeSectionBox(sb, layout, "CIRCULAR DEPENDENCIES [CRITICAL]")
 
 	for i, v := range report.Circular {
-		sb.WriteString(fmt.Sprintf("[%d] ", i+1))
-		sb.WriteString(formatCyclePath(v.Path))
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(prefix)
+		sb.WriteString(layout.wrap(layout.fitCycle(v.Path, len(prefix)), len(prefix)))
 		sb.WriteString("\n")
+		if note := cycleBlankImportNote(v, nil); note != "" {
+			sb.WriteString(strings.Repeat(" ", len(prefix)) + note + "\n")
+		}
+	}
+	if omitted := report.Summary.Omitted.Circular; omitted > 0 {
+		sb.WriteString(formatOmittedNote(omitted) + "\n")
 	}
 	sb.WriteString("\n")
 }
 
-func writeLayerViolations(sb *strings.Builder, report *StructuralReport) {
+func writeLayerViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
 	if len(report.Layer) == 0 {
 		return
 	}
 
-	sb.WriteString("┌───────────────────────────────────────────────────────────┐\n")
-	sb.WriteString("│  LAYER VIOLATIONS [HIGH]                                  │\n")
-	sb.WriteString("└───────────────────────────────────────────────────────────┘\n")
+	writeSectionBox(sb, layout, "LAYER VIOLATIONS [HIGH]")
 
 	for i, v := range report.Layer {
-		sb.WriteString(fmt.Sprintf("[%d] %s\n", i+1, v.Message))
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(prefix + layout.fitMessage(v.Message, len(prefix), v.From, v.To) + "\n")
+	}
+	if omitted := report.Summary.Omitted.Layer; omitted > 0 {
+		sb.WriteString(formatOmittedNote(omitted) + "\n")
 	}
 	sb.WriteString("\n")
 }
 
-func writeSizeViolations(sb *strings.Builder, report *StructuralReport) {
+func writeFeatureIsolationViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if len(report.OptIn.FeatureIsolation) == 0 {
+		return
+	}
+
+	writeSectionBox(sb, layout, "FEATURE ISOLATION VIOLATIONS [HIGH]")
+
+	for i, v := range report.OptIn.FeatureIsolation {
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(prefix + layout.fitMessage(v.Message, len(prefix), v.From) + "\n")
+	}
+	if omitted := report.Summary.Omitted.FeatureIsolation; omitted > 0 {
+		sb.WriteString(formatOmittedNote(omitted) + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+func writeDependencyCapViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if len(report.OptIn.DependencyCap) == 0 {
+		return
+	}
+
+	writeSectionBox(sb, layout, "DEPENDENCY CAP VIOLATIONS [HIGH]")
+
+	for i, v := range report.OptIn.DependencyCap {
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(prefix + layout.fitMessage(v.Message, len(prefix)) + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+func writeSizeViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
 	if len(report.Size) == 0 {
 		return
 	}
 
-	sb.WriteString("┌───────────────────────────────────────────────────────────┐\n")
-	sb.WriteString("│  SIZE VIOLATIONS [LOW]                                    │\n")
-	sb.WriteString("└───────────────────────────────────────────────────────────┘\n")
+	writeSectionBox(sb, layout, "SIZE VIOLATIONS [LOW]")
 
 	for i, v := range report.Size {
-		if v.Function != "" {
-			sb.WriteString(fmt.Sprintf("[%d] Function '%s' in %s: %d lines (threshold: %d)\n",
-				i+1, v.Function, v.File, v.Lines, v.Threshold))
-		} else {
-			sb.WriteString(fmt.Sprintf("[%d] File %s: %d lines (threshold: %d)\n",
-				i+1, v.File, v.Lines, v.Threshold))
-		}
+		sb.WriteString(formatSizeViolationLine(i+1, v, layout) + "\n")
+	}
+	if omitted := report.Summary.Omitted.Size; omitted > 0 {
+		sb.WriteString(formatOmittedNote(omitted) + "\n")
 	}
 	sb.WriteString("\n")
 }
 
-func writeGodObjectViolations(sb *strings.Builder, report *StructuralReport) {
+func writeGodObjectViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
 	if len(report.GodObject) == 0 {
 		return
 	}
 
-	sb.WriteString("┌───────────────────────────────────────────────────────────┐\n")
-	sb.WriteString("│  GOD OBJECT VIOLATIONS [MEDIUM]                           │\n")
-	sb.WriteString("└───────────────────────────────────────────────────────────┘\n")
+	writeSectionBox(sb, layout, "GOD OBJECT VIOLATIONS [MEDIUM]")
 
 	for i, v := range report.GodObject {
-		sb.WriteString(fmt.Sprintf("[%d] Struct '%s' in %s: %d fields, %d methods\n",
-			i+1, v.StructName, v.File, v.FieldCount, v.MethodCount))
+		sb.WriteString(formatGodObjectViolationLine(i+1, v, layout) + "\n")
+	}
+	if omitted := report.Summary.Omitted.GodObject; omitted > 0 {
+		sb.WriteString(formatOmittedNote(omitted) + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+func writeAdvisoryViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if len(report.Advisory) == 0 {
+		return
+	}
+
+	writeSectionBox(sb, layout, "ADVISORIES [INFO]")
+
+	for i, v := range report.Advisory {
+		prefix := fmt.Sprintf("[%d] ", i+1)
+		sb.WriteString(prefix + layout.fitMessage(v.Message, len(prefix), v.File) + "\n")
 	}
 	sb.WriteString("\n")
 }
 
-func writeScoreBreakdown(sb *strings.Builder, report *StructuralReport) {
+func writeSingleImplViolations(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if len(report.OptIn.SingleImpl) == 0 {
+		return
+	}
+
+	writeSectionBox(sb, layout, "SINGLE-IMPLEMENTATION INTERFACES [INFO]")
+
+	for i, v := range report.OptIn.SingleImpl {
+		sb.WriteString(fmt.Sprintf("[%d] %s is only implemented by %s\n", i+1, v.Interface, v.Impl))
+	}
+	sb.WriteString("\n")
+}
+
+func writeOrphanPackages(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if len(report.Metrics.Packages.Orphans) == 0 {
+		return
+	}
+
+	writeSectionBox(sb, layout, "ORPHAN PACKAGES [INFO]")
+
+	for i, pkg := range report.Metrics.Packages.Orphans {
+		sb.WriteString(fmt.Sprintf("[%d] %s is not imported by any analyzed package\n", i+1, pkg))
+	}
+	sb.WriteString("\n")
+}
+
+func writeCoupling(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if len(report.Metrics.Packages.Coupling) == 0 {
+		return
+	}
+
+	writeSectionBox(sb, layout, "COUPLING")
+
+	for i, c := range report.Metrics.Packages.Coupling {
+		sb.WriteString(formatCouplingLine(i+1, c) + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+func writeScoreBreakdown(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
 	if !report.HasViolations {
 		sb.WriteString("✨ No structural violations detected! Your architecture is clean.\n\n")
 		return
 	}
 
-	sb.WriteString("┌───────────────────────────────────────────────────────────┐\n")
-	sb.WriteString("│  SCORE BREAKDOWN                                          │\n")
-	sb.WriteString("└───────────────────────────────────────────────────────────┘\n")
-	sb.WriteString(fmt.Sprintf("Base Score:           100.0\n"))
-	sb.WriteString(fmt.Sprintf("Circular Penalty:     -%.1f (%d violations x 10.0)\n",
-		report.Score.CircularPenalty, report.Score.CircularCount))
-	sb.WriteString(fmt.Sprintf("Layer Penalty:        -%.1f (%d violations x 5.0)\n",
-		report.Score.LayerPenalty, report.Score.LayerCount))
-	sb.WriteString(fmt.Sprintf("Size Penalty:         -%.1f (%d violations x 3.0)\n",
-		report.Score.SizePenalty, report.Score.SizeCount))
-	sb.WriteString(fmt.Sprintf("God Object Penalty:   -%.1f (%d violations x 5.0)\n",
-		report.Score.GodObjectPenalty, report.Score.GodObjectCount))
+	writeSectionBox(sb, layout, "SCORE BREAKDOWN")
+	if !isWeightedScore(report.Score) {
+		sb.WriteString(formatRubricBreakdown(report.Score))
+		sb.WriteString("─────────────────────────────────────────────────\n")
+		sb.WriteString(fmt.Sprintf("Final Score:          %.1f / %.1f\n\n", report.Score.TotalScore, report.Score.MaxScore))
+		return
+	}
+	explanation := newScoreExplanation(report.Score)
+	sb.WriteString(fmt.Sprintf("Base Score:           %.1f\n", explanation.BaseScore))
+	sb.WriteString(fmt.Sprintf("Circular Penalty:     -%.1f (%s)\n",
+		explanation.Circular.Penalty, explanation.Circular.basis()))
+	sb.WriteString(fmt.Sprintf("Layer Penalty:        -%.1f (%s)\n",
+		explanation.Layer.Penalty, explanation.Layer.basis()))
+	if explanation.FeatureIsolation != nil {
+		sb.WriteString(fmt.Sprintf("Isolation Penalty:    -%.1f (%s)\n",
+			explanation.FeatureIsolation.Penalty, explanation.FeatureIsolation.basis()))
+	}
+	if explanation.DependencyCap != nil {
+		sb.WriteString(fmt.Sprintf("Dependency Penalty:   -%.1f (%s)\n",
+			explanation.DependencyCap.Penalty, explanation.DependencyCap.basis()))
+	}
+	sb.WriteString(fmt.Sprintf("Size Penalty:         -%.1f (%s)\n",
+		explanation.Size.Penalty, explanation.Size.basis()))
+	sb.WriteString(fmt.Sprintf("God Object Penalty:   -%.1f (%s)\n",
+		explanation.GodObject.Penalty, explanation.GodObject.basis()))
 	sb.WriteString(fmt.Sprintf("─────────────────────────────────────────────────\n"))
 	sb.WriteString(fmt.Sprintf("Final Score:          %.1f\n\n", report.Score.TotalScore))
 }
+
+// writeSectionBox writes a width-aware section title box
+func writeSectionBox(sb *strings.Builder, layout *textLayout, title string) {
+	sb.WriteString(layout.boxTop() + "\n")
+	sb.WriteString(layout.boxTitle(title) + "\n")
+	sb.WriteString(layout.boxBottom() + "\n")
+}
+
+// formatSizeViolationLine renders a size violation with its file path
+// truncated to the remaining line width, wrapped when it still does not fit
+func formatSizeViolationLine(index int, v SizeViolation, layout *textLayout) string {
+	prefix := fmt.Sprintf("[%d] ", index)
+	line := spanSuffix(v.Line, v.StartLine, v.EndLine)
+	if v.Function != "" {
+		rest := fmt.Sprintf("%sFunction '%s' in %s: %d lines (threshold: %d)", prefix, v.Function, line, v.Lines, v.Threshold)
+		return prefix + layout.wrap(fmt.Sprintf("Function '%s' in %s%s: %d lines (threshold: %d)",
+			v.Function, layout.fitPath(v.File, utf8.RuneCountInString(rest)), line, v.Lines, v.Threshold), len(prefix))
+	}
+	rest := fmt.Sprintf("%sFile %s: %d lines (threshold: %d)", prefix, line, v.Lines, v.Threshold)
+	return prefix + layout.wrap(fmt.Sprintf("File %s%s: %d lines (threshold: %d)",
+		layout.fitPath(v.File, utf8.RuneCountInString(rest)), line, v.Lines, v.Threshold), len(prefix))
+}
+
+// formatGodObjectViolationLine renders a god object violation with its file
+// path truncated to the remaining line width, wrapped when it still does not
+// fit
+func formatGodObjectViolationLine(index int, v GodObjectViolation, layout *textLayout) string {
+	prefix := fmt.Sprintf("[%d] ", index)
+	line := spanSuffix(v.Line, v.StartLine, v.EndLine)
+	rest := fmt.Sprintf("%sStruct '%s' in %s: %d fields, %d methods", prefix, v.StructName, line, v.FieldCount, v.MethodCount)
+	return prefix + layout.wrap(fmt.Sprintf("Struct '%s' in %s%s: %d fields, %d methods",
+		v.StructName, layout.fitPath(v.File, utf8.RuneCountInString(rest)), line, v.FieldCount, v.MethodCount), len(prefix))
+}
+
+// lineSuffix renders a violation's line as the :line of a file:line
+// location, or nothing when the line is unknown
+func lineSuffix(line int) string {
+	if line <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(":%d", line)
+}
+
+// spanSuffix renders the lines a violation spans as :start-end, falling
+// back to lineSuffix when the span is unknown or a single line
+func spanSuffix(line, start, end int) string {
+	if start <= 0 || end <= start {
+		return lineSuffix(line)
+	}
+	return fmt.Sprintf(":%d-%d", start, end)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// RepositoryStats sizes the analyzed repository: its own source files, as
+// the rules see them, and the dependency graph built from them
+type RepositoryStats struct {
+	Files         int `json:"files"`
+	GoFiles       int `json:"goFiles"`
+	Lines         int `json:"lines"`
+	NonEmptyLines int `json:"nonEmptyLines"`
+	// Packages counts the directories holding files
+	Packages   int `json:"packages"`
+	GraphNodes int `json:"graphNodes"`
+	GraphEdges int `json:"graphEdges"`
+}
+
+// computeRepositoryStats counts files from the contents the extraction
+// already read, so no second walk of the tree is needed. Graph nodes
+// without content, such as unresolved imports, are not files, and files in
+// directories the walkers skip, such as vendor, are not counted either.
+func computeRepositoryStats(absPath string, files []rules.RepositoryFile, graph Graph) *RepositoryStats {
+	var counted []rules.RepositoryFile
+	for _, file := range files {
+		if rel, ok := repositoryRelPath(absPath, file.Path); file.Content != "" && (!ok || !inIgnoredDir(rel)) {
+			counted = append(counted, file)
+		}
+	}
+
+	stats := &RepositoryStats{}
+	packages := make(map[string]bool)
+	for _, file := range counted {
+		stats.Files++
+		if strings.HasSuffix(file.Path, ".go") {
+			stats.GoFiles++
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(file.Content, "\n"), "\n") {
+			if strings.TrimSpace(line) != "" {
+				stats.NonEmptyLines++
+			}
+		}
+		packages[filepath.Dir(file.Path)] = true
+	}
+	stats.Lines = countRepositoryLines(counted)
+	stats.Packages = len(packages)
+	if graph != nil {
+		stats.GraphNodes = graph.GetNodeCount()
+		stats.GraphEdges = graph.GetEdgeCount()
+	}
+	return stats
+}
+
+// formatRepositoryStats renders the statistics one measure per line
+func formatRepositoryStats(stats *RepositoryStats) []string {
+	return []string{
+		fmt.Sprintf("Files:     %d (%d Go)", stats.Files, stats.GoFiles),
+		fmt.Sprintf("Lines:     %d (%d non-empty)", stats.Lines, stats.NonEmptyLines),
+		fmt.Sprintf("Packages:  %d", stats.Packages),
+		fmt.Sprintf("Graph:     %d nodes, %d edges", stats.GraphNodes, stats.GraphEdges),
+	}
+}
+
+// writeRepositoryStats writes the REPOSITORY STATISTICS section
+func writeRepositoryStats(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if report.Metrics.Stats == nil {
+		return
+	}
+
+	writeSectionBox(sb, layout, "REPOSITORY STATISTICS")
+	for _, line := range formatRepositoryStats(report.Metrics.Stats) {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+// writeRepositoryStatsWithColor writes the REPOSITORY STATISTICS section
+// with colors
+func writeRepositoryStatsWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if report.Metrics.Stats == nil {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "REPOSITORY STATISTICS", ColorCyan)
+	for _, line := range formatRepositoryStats(report.Metrics.Stats) {
+		sb.WriteString(formatter.Info(line) + "\n")
+	}
+	sb.WriteString("\n")
+}
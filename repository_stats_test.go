@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// statsFixture is a module of two packages with one import between them
+func statsFixture() map[string]string {
+	return map[string]string{
+		"go.mod":    "module example.com/app\n\ngo 1.21\n",
+		"README.md": "# app\n",
+		"a/a.go":    "package a\n\nimport \"example.com/app/b\"\n\nvar _ = b.X\n",
+		"b/b.go":    "package b\n\n// X is exported\nvar X = 1\n",
+		"b/b2.go":   "package b\n",
+	}
+}
+
+func TestAnalysisService_RepositoryStats(t *testing.T) {
+	report, _ := analyzeFixture(t, statsFixture(), AnalyzeOptions{})
+	// The graph holds the three files and the imported package
+	want := RepositoryStats{Files: 3, GoFiles: 3, Lines: 10, NonEmptyLines: 7, Packages: 2, GraphNodes: 4, GraphEdges: 1}
+	if got := report.Metrics.Stats; got == nil || *got != want {
+		t.Fatalf("expected stats %+v, got %+v", want, got)
+	}
+}
+
+func TestAnalysisService_RepositoryStatsSkipExcludedFiles(t *testing.T) {
+	files := statsFixture()
+	files[".repodoctor/config.yaml"] = "exclude:\n  - \"*_gen.go\"\n"
+	files["b/b_gen.go"] = "package b\n\nvar Y = 2\n"
+	files["vendor/example.com/dep/dep.go"] = "package dep\n"
+	files[".cache/c.go"] = "package c\n"
+
+	report, _ := analyzeFixture(t, files, AnalyzeOptions{})
+	// Excluded, vendored and hidden files are counted by no walker
+	got := report.Metrics.Stats
+	if got == nil || got.Files != 3 || got.GoFiles != 3 || got.Lines != 10 || got.Packages != 2 {
+		t.Fatalf("expected the fixture's 3 files and 10 lines, got %+v", got)
+	}
+}
+
+func TestReporter_RepositoryStatsSection(t *testing.T) {
+	report := &StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 100, MaxScore: 100}}
+	report.Metrics.Stats = &RepositoryStats{Files: 3, GoFiles: 2, Lines: 10, NonEmptyLines: 7, Packages: 2, GraphNodes: 3, GraphEdges: 1}
+
+	text := NewColoredReporter(FormatText, false).FormatColoredText(report)
+	for _, want := range []string{"REPOSITORY STATISTICS", "Files:     3 (2 Go)", "Lines:     10 (7 non-empty)", "Packages:  2", "Graph:     3 nodes, 1 edges"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected text output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if plain := NewReporter(FormatText).Format(report); !strings.Contains(plain, "Files:     3 (2 Go)") {
+		t.Fatalf("expected the plain text report to list the stats, got:\n%s", plain)
+	}
+
+	var payload struct {
+		Metrics struct {
+			Stats *RepositoryStats `json:"stats"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSON).Format(report)), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if payload.Metrics.Stats == nil || *payload.Metrics.Stats != *report.Metrics.Stats {
+		t.Fatalf("expected metrics.stats in JSON output, got %+v", payload.Metrics.Stats)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// formatRuleCoverage renders how many files each coverage-aware rule
+// examined, e.g. "rule.size: evaluated 312 of 320 Go files; 8 skipped"
+func formatRuleCoverage(coverage []rules.RuleCoverage) string {
+	if len(coverage) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(ColorInfo("Rule coverage:") + "\n")
+	for _, c := range coverage {
+		sb.WriteString(fmt.Sprintf("  %s: evaluated %d of %d Go files", c.RuleID, c.Examined, c.Total()))
+		if c.Skipped > 0 {
+			sb.WriteString(fmt.Sprintf("; %d skipped as malformed or disabled by directive", c.Skipped))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// formatStructCohesion renders the struct cohesion metrics. Fully cohesive
+// structs are summarised in one line; split structs list their clusters.
+func formatStructCohesion(cohesion []rules.StructCohesion) string {
+	if len(cohesion) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	cohesive := 0
+	sb.WriteString(ColorInfo("Struct cohesion:") + "\n")
+	for _, c := range cohesion {
+		if len(c.Clusters) == 1 {
+			cohesive++
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %s (%s): %.2f, %d clusters\n", c.Struct, c.File, c.Cohesion, len(c.Clusters)))
+		for _, cluster := range c.Clusters {
+			sb.WriteString(fmt.Sprintf("    methods: %s; fields: %s\n", strings.Join(cluster.Methods, ", "), strings.Join(cluster.Fields, ", ")))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("  %d of %d structs fully cohesive\n", cohesive, len(cohesion)))
+	return sb.String()
+}
+
+// formatDependencyInventory renders the external module count, the cap when
+// one is configured, and the modules new since the previous run
+func formatDependencyInventory(inventory *DependencyInventory) string {
+	if inventory == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(ColorInfo("External modules: ") + fmt.Sprintf("%d", inventory.Count))
+	if inventory.MaxExternal != nil {
+		sb.WriteString(fmt.Sprintf(" (max: %d)", *inventory.MaxExternal))
+	}
+	sb.WriteString("\n")
+	for _, module := range inventory.Modules {
+		sb.WriteString("  " + module + "\n")
+	}
+	if len(inventory.Newest) > 0 {
+		sb.WriteString(fmt.Sprintf("  new since previous run: %s\n", strings.Join(inventory.Newest, ", ")))
+	}
+	return sb.String()
+}
+
+// formatLargestArtifacts renders the largest files and functions, whether or
+// not they exceed the size thresholds
+func formatLargestArtifacts(largest *rules.LargestArtifacts) string {
+	if largest == nil || len(largest.Files) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(ColorInfo("Largest files:") + "\n")
+	for _, f := range largest.Files {
+		sb.WriteString(fmt.Sprintf("  %5d lines  %s\n", f.Lines, f.File))
+	}
+	if len(largest.FunctionsByLines) > 0 {
+		sb.WriteString(ColorInfo("Longest functions:") + "\n")
+		for _, f := range largest.FunctionsByLines {
+			sb.WriteString(fmt.Sprintf("  %5d lines  %s (%s:%d)\n", f.Lines, f.Function, f.File, f.Line))
+		}
+		sb.WriteString(ColorInfo("Most complex functions:") + "\n")
+		for _, f := range largest.FunctionsByComplexity {
+			sb.WriteString(fmt.Sprintf("  %5d        %s (%s:%d)\n", f.Complexity, f.Function, f.File, f.Line))
+		}
+	}
+	return sb.String()
+}
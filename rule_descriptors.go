@@ -0,0 +1,70 @@
+package main
+
+import (
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
+)
+
+// RuleDescriptor describes one executed rule for report consumers. It is
+// built from the rule registry, so every output format that describes rules
+// shares the same names, descriptions and thresholds.
+type RuleDescriptor struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Severity    model.Severity     `json:"severity"`
+	Weight      float64            `json:"weight"`
+	Thresholds  map[string]float64 `json:"thresholds,omitempty"`
+	DocsAnchor  string             `json:"docsAnchor"`
+	// TimedOut marks a rule stopped by its rules.timeouts entry; it
+	// reported no violations
+	TimedOut bool `json:"timedOut,omitempty"`
+}
+
+// buildRuleDescriptors describes the rules of a registry in registry order.
+// Weight is the score penalty per violation; informational rules weigh 0.
+func buildRuleDescriptors(registry *rules.RuleRegistry, cfg *Config) []RuleDescriptor {
+	weights := scoringWeightsFromConfig(cfg)
+	descriptors := make([]RuleDescriptor, 0, registry.Count())
+	for _, rule := range registry.GetAll() {
+		descriptor := RuleDescriptor{
+			Name:       rule.ID(),
+			Severity:   rule.Severity(),
+			Weight:     ruleWeight(rule.ID(), weights),
+			DocsAnchor: ruleDocsAnchor(rule.ID()),
+		}
+		if described, ok := rule.(rules.DescribedRule); ok {
+			descriptor.Description = described.Description()
+		}
+		if thresholded, ok := rule.(rules.ThresholdedRule); ok {
+			descriptor.Thresholds = thresholded.Thresholds()
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
+
+// ruleWeight returns the penalty weight of the report section a rule's
+// violations are scored in
+func ruleWeight(id string, weights *ScoringWeights) float64 {
+	switch id {
+	case "rule.circular-dependency":
+		return weights.CircularDependencyPenalty
+	case "rule.layer-validation":
+		return weights.LayerViolationPenalty
+	case "rule.feature-isolation":
+		return weights.FeatureIsolationPenalty
+	case "rule.dependency-cap":
+		return weights.DependencyCapPenalty
+	case "rule.size":
+		return weights.SizeViolationPenalty
+	case "rule.god-object":
+		return weights.GodObjectPenalty
+	}
+	return 0
+}
+
+// ruleDocsAnchor returns the stable documentation anchor of a rule, such as
+// "rule-size"
+func ruleDocsAnchor(id string) string {
+	return "rule-" + ruleShortName(id)
+}
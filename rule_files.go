@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// collectRuleFiles walks root once and returns the Go files the size and god
+// object rules check, in lexical order. Hidden files and directories are
+// skipped, as are entries that cannot be read, files matching one of the
+// exclude globs and test files the mode does not check.
+func collectRuleFiles(root string, excludes []string, tests TestFileMode) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip files with errors
+		}
+
+		// Skip hidden directories
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Skip non-Go and hidden files
+		if !strings.HasSuffix(path, ".go") || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		// Skip test files unless the mode checks them
+		if !tests.checks(path) {
+			return nil
+		}
+
+		// Skip files excluded by the config
+		if excludedPath(root, path, excludes) {
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	})
+	return files, err
+}
+
+// forEachFile calls fn for every file on a pool of one worker per CPU.
+// Calls run concurrently, so fn must guard the state it shares. It returns
+// the error of the first file, in input order, whose call failed.
+func forEachFile(files []string, fn func(path string) error) error {
+	errs := make([]error, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = fn(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeRuleFixture writes packages of Go files with oversized functions,
+// large structs and methods spread over several files
+func writeRuleFixture(tb testing.TB, root string, packages int) {
+	tb.Helper()
+	body := strings.Repeat("\t_ = 1\n", 85)
+	for p := 0; p < packages; p++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%02d", p))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			tb.Fatalf("Failed to create package dir: %v", err)
+		}
+		fields := ""
+		for f := 0; f < 16; f++ {
+			fields += fmt.Sprintf("\tF%d int\n", f)
+		}
+		files := map[string]string{
+			"model.go": "package pkg\n\ntype Model struct {\n" + fields + "}\n\nfunc Long() {\n" + body + "}\n",
+		}
+		for f := 0; f < 4; f++ {
+			methods := ""
+			for m := 0; m < 3; m++ {
+				methods += fmt.Sprintf("func (s *Service) M%d_%d() {}\n", f, m)
+			}
+			files[fmt.Sprintf("service%d.go", f)] = "package pkg\n\ntype Service" + strings.Repeat("_", f) + " struct{}\n\n" + methods
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				tb.Fatalf("Failed to create test file: %v", err)
+			}
+		}
+	}
+}
+
+func TestRuleChecks_ConcurrentResultsMatchSerial(t *testing.T) {
+	// Run with -race to check the worker pool for data races
+	root := t.TempDir()
+	writeRuleFixture(t, root, 12)
+
+	check := func() ([]SizeViolation, []GodObjectViolation) {
+		size, god := NewSizeRule(), NewGodObjectRule()
+		if err := size.Check(root); err != nil {
+			t.Fatalf("size Check failed: %v", err)
+		}
+		if err := god.Check(root); err != nil {
+			t.Fatalf("god object Check failed: %v", err)
+		}
+		return size.Violations(), god.Violations()
+	}
+
+	previous := runtime.GOMAXPROCS(1)
+	t.Cleanup(func() { runtime.GOMAXPROCS(previous) })
+	serialSize, serialGod := check()
+	if len(serialSize) != 12 || len(serialGod) != 24 {
+		t.Fatalf("Expected 12 size and 24 god object violations, got %d and %d", len(serialSize), len(serialGod))
+	}
+
+	runtime.GOMAXPROCS(max(8, previous))
+	for run := 0; run < 3; run++ {
+		size, god := check()
+		if !reflect.DeepEqual(size, serialSize) || !reflect.DeepEqual(god, serialGod) {
+			t.Fatalf("Expected concurrent checks to match the serial run\nsize: %+v\ngod objects: %+v", size, god)
+		}
+	}
+	if serialGod[0].File > serialGod[len(serialGod)-1].File {
+		t.Errorf("Expected god object violations sorted by file, got %+v", serialGod)
+	}
+}
+
+// BenchmarkRuleChecks parses every file once per rule; compare its
+// parses/op with BenchmarkRuleChecks_SharedAST
+func BenchmarkRuleChecks(b *testing.B) {
+	root := b.TempDir()
+	writeRuleFixture(b, root, 50)
+
+	b.ResetTimer()
+	parses := 0
+	for i := 0; i < b.N; i++ {
+		size, god := NewSizeRule(), NewGodObjectRule()
+		size.sources, god.sources = newASTCache(), newASTCache()
+		if err := size.Check(root); err != nil {
+			b.Fatalf("size Check failed: %v", err)
+		}
+		if err := god.Check(root); err != nil {
+			b.Fatalf("god object Check failed: %v", err)
+		}
+		parses += size.sources.parseCount() + god.sources.parseCount()
+	}
+	b.ReportMetric(float64(parses)/float64(b.N), "parses/op")
+}
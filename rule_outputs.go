@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleOutput writes a single rule's violations to a file in a chosen format
+type RuleOutput struct {
+	RuleID string
+	Format OutputFormat
+	Path   string
+}
+
+// ruleOutputFlags collects repeated -out-rule values
+type ruleOutputFlags []string
+
+func (f *ruleOutputFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *ruleOutputFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parseRuleOutputs parses -out-rule values of the form <rule>:<format>:<path>.
+// The path is everything after the second colon so Windows drive letters work.
+func parseRuleOutputs(values []string) ([]RuleOutput, error) {
+	outputs := make([]RuleOutput, 0, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 3)
+		if len(parts) != 3 || strings.TrimSpace(parts[2]) == "" {
+			return nil, NewCLIError(
+				ErrorCLIUsage,
+				fmt.Sprintf("Invalid -out-rule value '%s'", value),
+				"Use -out-rule <rule>:<format>:<path>, e.g. -out-rule size:json:size.json",
+				nil,
+			)
+		}
+
+		ids, err := resolveRuleNames(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) != 1 {
+			return nil, NewCLIError(
+				ErrorCLIUsage,
+				fmt.Sprintf("Invalid -out-rule value '%s': expected exactly one rule", value),
+				"Repeat -out-rule to write several rules to separate files",
+				nil,
+			)
+		}
+
+		format := strings.TrimSpace(parts[1])
+		if err := validateAnalyzeFormat(format); err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, RuleOutput{RuleID: ids[0], Format: OutputFormat(format), Path: parts[2]})
+	}
+	return outputs, nil
+}
+
+// filterReportByRule returns a copy of the report that only contains the
+// violations produced by ruleID, with counts and score recomputed
+func filterReportByRule(report *StructuralReport, ruleID string, cfg *Config) *StructuralReport {
+	filtered := *report
+	filtered.Circular = nil
+	filtered.Layer = nil
+	filtered.OptIn.FeatureIsolation = nil
+	filtered.OptIn.DependencyCap = nil
+	filtered.Size = nil
+	filtered.GodObject = nil
+	filtered.Advisory = nil
+	filtered.OptIn.SingleImpl = nil
+	filtered.RuleSet = []string{ruleID}
+
+	switch ruleID {
+	case "rule.circular-dependency":
+		filtered.Circular = report.Circular
+	case "rule.feature-isolation":
+		filtered.OptIn.FeatureIsolation = report.OptIn.FeatureIsolation
+	case "rule.dependency-cap":
+		filtered.OptIn.DependencyCap = report.OptIn.DependencyCap
+	case "rule.layer-validation":
+		for _, v := range report.Layer {
+			if v.RuleID == ruleID {
+				filtered.Layer = append(filtered.Layer, v)
+			}
+		}
+	case "rule.size":
+		filtered.Size = report.Size
+	case "rule.god-object":
+		filtered.GodObject = report.GodObject
+	case "rule.single-impl-interface":
+		filtered.OptIn.SingleImpl = report.OptIn.SingleImpl
+	default:
+		for _, v := range report.Advisory {
+			if v.RuleID == ruleID {
+				filtered.Advisory = append(filtered.Advisory, v)
+			}
+		}
+	}
+
+	filtered.Summary = ReportSummary{
+		TotalViolations:  len(filtered.Circular) + len(filtered.Layer) + len(filtered.OptIn.FeatureIsolation) + len(filtered.OptIn.DependencyCap) + len(filtered.Size) + len(filtered.GodObject),
+		Circular:         len(filtered.Circular),
+		Layer:            len(filtered.Layer),
+		FeatureIsolation: len(filtered.OptIn.FeatureIsolation),
+		DependencyCap:    len(filtered.OptIn.DependencyCap),
+		Size:             len(filtered.Size),
+		GodObject:        len(filtered.GodObject),
+	}
+	filtered.HasViolations = filtered.Summary.TotalViolations > 0
+	filtered.Score = calculateScoreFromViolations(cfg, &filtered)
+	return &filtered
+}
+
+// writeRuleOutputs writes each requested rule's violations to its own file,
+// in any format -output supports
+func writeRuleOutputs(report *StructuralReport, outputs []RuleOutput, cfg *Config, request AnalyzeRequest) error {
+	request.Report.ColorEnabled = false
+	for _, output := range outputs {
+		if err := writeReportFile(output.Path, filterReportByRule(report, output.RuleID, cfg), output.Format, cfg, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
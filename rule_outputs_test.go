@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func TestWriteRuleOutputs_WritesOnlyEachRulesViolations(t *testing.T) {
+	dir := t.TempDir()
+	cyclesPath := filepath.Join(dir, "cycles.json")
+	sizePath := filepath.Join(dir, "out", "size.txt")
+
+	req, err := parseAnalyzeFlags([]string{
+		"-out-rule", "circular-dependency:json:" + cyclesPath,
+		"-out-rule", "rule.size:text:" + sizePath,
+		".",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Report.RuleOutputs) != 2 {
+		t.Fatalf("expected 2 rule outputs, got %d", len(req.Report.RuleOutputs))
+	}
+
+	report := buildReportFromRuleViolations("/repo", "test", nil, []model.Violation{
+		{RuleID: "rule.circular-dependency", File: "a.go", Severity: model.SeverityCritical},
+		{RuleID: "rule.size", File: "big.go", Message: "File big.go has 900 lines (threshold: 500)"},
+		{RuleID: "rule.god-object", File: "m.go", Message: "Manager has 20 fields (threshold: 15)"},
+	})
+	if err := writeRuleOutputs(report, req.Report.RuleOutputs, nil, AnalyzeRequest{Report: ReportOptions{Width: 100}}); err != nil {
+		t.Fatalf("writeRuleOutputs failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cyclesPath)
+	if err != nil {
+		t.Fatalf("failed to read cycles output: %v", err)
+	}
+	var cycles struct {
+		Summary ReportSummary `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &cycles); err != nil {
+		t.Fatalf("cycles output is not valid JSON: %v", err)
+	}
+	if cycles.Summary != (ReportSummary{TotalViolations: 1, Circular: 1}) {
+		t.Fatalf("expected only the circular violation, got %+v", cycles.Summary)
+	}
+
+	text, err := os.ReadFile(sizePath)
+	if err != nil {
+		t.Fatalf("failed to read size output: %v", err)
+	}
+	if !strings.Contains(string(text), "SIZE VIOLATIONS") || !strings.Contains(string(text), "big.go") {
+		t.Fatalf("expected size violation in text output, got:\n%s", text)
+	}
+	for _, unexpected := range []string{"CIRCULAR", "GOD OBJECT", "Manager"} {
+		if strings.Contains(string(text), unexpected) {
+			t.Fatalf("size output should not contain %q:\n%s", unexpected, text)
+		}
+	}
+}
+
+func TestWriteRuleOutputs_SupportsEveryReportFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cycles.sarif")
+	outputs, err := parseRuleOutputs([]string{"circular-dependency:sarif:" + path})
+	if err != nil {
+		t.Fatalf("expected sarif to be accepted: %v", err)
+	}
+
+	report := buildReportFromRuleViolations("/repo", "test", nil, []model.Violation{
+		{RuleID: "rule.circular-dependency", File: "a.go", Message: "a.go → b.go → a.go", Severity: model.SeverityCritical},
+		{RuleID: "rule.size", File: "big.go", Message: "File big.go has 900 lines (threshold: 500)"},
+	})
+	if err := writeRuleOutputs(report, outputs, nil, AnalyzeRequest{}); err != nil {
+		t.Fatalf("writeRuleOutputs failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read sarif output: %v", err)
+	}
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("sarif output is not valid JSON: %v", err)
+	}
+	if len(doc.Runs) != 1 || len(doc.Runs[0].Results) != 1 || doc.Runs[0].Results[0].RuleID != "rule.circular-dependency" {
+		t.Fatalf("expected only the cycle in the sarif output, got %+v", doc)
+	}
+}
+
+func TestParseRuleOutputs_RejectsInvalidValues(t *testing.T) {
+	for _, value := range []string{"size", "size:json:", "nope:json:x.json", "size:xml:x.xml"} {
+		if _, err := parseRuleOutputs([]string{value}); err == nil {
+			t.Fatalf("expected error for -out-rule %q", value)
+		}
+	}
+}
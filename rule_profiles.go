@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/model"
+)
+
+// RuleProfile enables or disables rules for one directory subtree. Path is a
+// slash-separated glob relative to the repository root, e.g. "cmd/**" or
+// "services/*/internal"; a trailing "/**" is optional.
+type RuleProfile struct {
+	Path    string   `yaml:"path"`
+	Enable  []string `yaml:"enable,omitempty"`
+	Disable []string `yaml:"disable,omitempty"`
+}
+
+// ruleProfiles resolves which rules are active in a directory. Profiles are
+// ordered from least to most specific, so the most specific matching profile
+// that mentions a rule decides; equally specific profiles apply in
+// declaration order and the last one wins.
+type ruleProfiles struct {
+	profiles []resolvedRuleProfile
+}
+
+type resolvedRuleProfile struct {
+	root     string // subtree glob; "" matches the whole repository
+	segments int
+	literals int
+	rules    map[string]bool // rule ID -> enabled
+}
+
+// newRuleProfiles resolves the profiles of the rules config section. Rule
+// names were validated when the config was loaded.
+func newRuleProfiles(cfg *Config) *ruleProfiles {
+	set := &ruleProfiles{}
+	if cfg == nil || cfg.Rules == nil {
+		return set
+	}
+
+	for _, profile := range cfg.Rules.Profiles {
+		resolved := resolvedRuleProfile{root: profileRoot(profile.Path), rules: make(map[string]bool)}
+		if resolved.root != "" {
+			for _, segment := range strings.Split(resolved.root, "/") {
+				resolved.segments++
+				if !strings.ContainsAny(segment, "*?[") {
+					resolved.literals++
+				}
+			}
+		}
+		enabled, _ := resolveRuleNames(strings.Join(profile.Enable, ","))
+		disabled, _ := resolveRuleNames(strings.Join(profile.Disable, ","))
+		for _, id := range enabled {
+			resolved.rules[id] = true
+		}
+		for _, id := range disabled {
+			resolved.rules[id] = false
+		}
+		set.profiles = append(set.profiles, resolved)
+	}
+
+	sort.SliceStable(set.profiles, func(i, j int) bool {
+		if set.profiles[i].segments != set.profiles[j].segments {
+			return set.profiles[i].segments < set.profiles[j].segments
+		}
+		return set.profiles[i].literals < set.profiles[j].literals
+	})
+	return set
+}
+
+// profileRoot normalizes a profile path to the glob of its subtree root
+func profileRoot(pattern string) string {
+	root := strings.Trim(filepath.ToSlash(strings.TrimSpace(pattern)), "/")
+	for strings.HasSuffix(root, "/**") {
+		root = strings.TrimSuffix(root, "/**")
+	}
+	if root == "**" || root == "." {
+		return ""
+	}
+	return root
+}
+
+// matches reports whether dir (slash-separated, relative to the repository
+// root) lies in the profile's subtree
+func (p resolvedRuleProfile) matches(dir string) bool {
+	if p.root == "" {
+		return true
+	}
+	for candidate := dir; candidate != "." && candidate != "/" && candidate != ""; candidate = path.Dir(candidate) {
+		if ok, _ := path.Match(p.root, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// active reports whether a rule is active in dir, given its repository-wide
+// enablement
+func (s *ruleProfiles) active(ruleID, dir string, enabled bool) bool {
+	for _, profile := range s.profiles {
+		if value, ok := profile.rules[ruleID]; ok && profile.matches(dir) {
+			enabled = value
+		}
+	}
+	return enabled
+}
+
+// enabledAnywhere reports whether some profile enables the rule, in which
+// case it must run even when disabled repository-wide
+func (s *ruleProfiles) enabledAnywhere(ruleID string) bool {
+	for _, profile := range s.profiles {
+		if profile.rules[ruleID] {
+			return true
+		}
+	}
+	return false
+}
+
+// filterViolations drops violations located where their rule is inactive.
+// Violations without a file inside the repository follow the
+// repository-wide enablement.
+func (s *ruleProfiles) filterViolations(absPath string, violations []model.Violation, cfg *Config) []model.Violation {
+	if len(s.profiles) == 0 {
+		return violations
+	}
+
+	kept := make([]model.Violation, 0, len(violations))
+	for _, v := range violations {
+		enabled := ruleEnabledByConfig(v.RuleID, cfg)
+		if dir, ok := repositoryDir(absPath, v.File); ok {
+			enabled = s.active(v.RuleID, dir, enabled)
+		}
+		if enabled {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// repositoryDir returns the slash-separated directory of file relative to
+// absPath, or false when file is empty or outside the repository
+func repositoryDir(absPath, file string) (string, bool) {
+	if file == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(absPath, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path.Dir(filepath.ToSlash(rel)), true
+}
+
+// RuleMatrixRow lists the rules active in one package directory
+type RuleMatrixRow struct {
+	Directory string          `json:"directory"`
+	Rules     map[string]bool `json:"rules"`
+}
+
+// EffectiveRuleMatrix resolves, for every package directory of the analyzed
+// files, which rules are active. Rules are named by short name.
+func EffectiveRuleMatrix(absPath string, files []string, cfg *Config) []RuleMatrixRow {
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		if dir, ok := repositoryDir(absPath, file); ok {
+			dirs[dir] = true
+		}
+	}
+	sortedDirs := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sortedDirs = append(sortedDirs, dir)
+	}
+	sort.Strings(sortedDirs)
+
+	profiles := newRuleProfiles(cfg)
+	rows := make([]RuleMatrixRow, 0, len(sortedDirs))
+	for _, dir := range sortedDirs {
+		row := RuleMatrixRow{Directory: dir, Rules: make(map[string]bool)}
+		for _, id := range runtimeRuleIDs() {
+			row.Rules[ruleShortName(id)] = profiles.active(id, dir, ruleEnabledByConfig(id, cfg))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func formatRuleMatrix(rows []RuleMatrixRow, format string) string {
+	if format == string(FormatJSON) {
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return "[]\n"
+		}
+		return string(data) + "\n"
+	}
+
+	names := make([]string, 0)
+	for _, id := range runtimeRuleIDs() {
+		names = append(names, ruleShortName(id))
+	}
+	dirWidth := len("DIRECTORY")
+	for _, row := range rows {
+		dirWidth = max(dirWidth, len(row.Directory))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-*s", dirWidth, "DIRECTORY"))
+	for _, name := range names {
+		sb.WriteString("  " + name)
+	}
+	sb.WriteString("\n")
+	for _, row := range rows {
+		line := fmt.Sprintf("%-*s", dirWidth, row.Directory)
+		for _, name := range names {
+			state := "-"
+			if row.Rules[name] {
+				state = "on"
+			}
+			line += fmt.Sprintf("  %-*s", len(name), state)
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return sb.String()
+}
+
+// runRules lists the runtime rules and their repository-wide state, or with
+// effective the per-directory rule matrix after applying rule profiles
+func runRules(repoPath, format string, effective bool) error {
+	absPath := validatePath(repoPath)
+	cfg := loadConfiguration(absPath, false)
+
+	if !effective {
+		profiles := newRuleProfiles(cfg)
+		for _, id := range runtimeRuleIDs() {
+			state := "off"
+			if ruleEnabledByConfig(id, cfg) {
+				state = "on"
+			} else if profiles.enabledAnywhere(id) {
+				state = "off (enabled by profile)"
+			}
+			fmt.Printf("%-24s %s\n", ruleShortName(id), state)
+		}
+		return nil
+	}
+
+	result, err := newAnalysisOrchestrator(absPath).AnalyzeGraph(absPath)
+	if err != nil {
+		return WrapError(err, ErrorAnalysis, "Dependency graph extraction failed", GetSuggestion(err.Error()))
+	}
+	fmt.Print(formatRuleMatrix(EffectiveRuleMatrix(absPath, result.Files, cfg), format))
+	return nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// overlappingProfilesConfig disables size and god object under cmd/, enables
+// size again under cmd/tool/ and enables an opt-in rule under pkg/
+func overlappingProfilesConfig() *Config {
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	cfg.Rules.Profiles = []RuleProfile{
+		{Path: "cmd/tool/**", Enable: []string{"size"}},
+		{Path: "cmd/**", Disable: []string{"god-object", "size"}},
+		{Path: "pkg", Enable: []string{"single-impl-interface"}},
+	}
+	return cfg
+}
+
+func TestRuleProfiles_MostSpecificSubtreeWins(t *testing.T) {
+	cfg := overlappingProfilesConfig()
+	profiles := newRuleProfiles(cfg)
+
+	cases := []struct {
+		rule, dir string
+		want      bool
+	}{
+		{"rule.size", ".", true},
+		{"rule.size", "cmd", false},
+		{"rule.size", "cmd/other", false},
+		{"rule.size", "cmd/tool", true},
+		{"rule.size", "cmd/tool/sub", true},
+		{"rule.god-object", "cmd/tool", false},
+		{"rule.single-impl-interface", "pkg/store", true},
+		{"rule.single-impl-interface", "internal", false},
+	}
+	for _, tc := range cases {
+		got := profiles.active(tc.rule, tc.dir, ruleEnabledByConfig(tc.rule, cfg))
+		if got != tc.want {
+			t.Errorf("%s in %s: expected %v, got %v", tc.rule, tc.dir, tc.want, got)
+		}
+	}
+
+	ids := effectiveRuleIDs(runtimeRuleIDs(), cfg, nil)
+	if !strings.Contains(strings.Join(ids, ","), "rule.single-impl-interface") {
+		t.Fatalf("expected a profile-enabled rule to run, got %v", ids)
+	}
+}
+
+func TestRuleProfiles_EquallySpecificLastDeclaredWins(t *testing.T) {
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	cfg.Rules.Profiles = []RuleProfile{
+		{Path: "services/*", Disable: []string{"size"}},
+		{Path: "services/api", Enable: []string{"size"}},
+		{Path: "*/api", Disable: []string{"size"}},
+	}
+	profiles := newRuleProfiles(cfg)
+
+	// services/api has the most literal segments, so it wins regardless of order
+	if !profiles.active("rule.size", "services/api", true) {
+		t.Fatal("expected the literal path to win over wildcard paths")
+	}
+	// */api and other/* are equally specific for other/api: the later one applies
+	cfg.Rules.Profiles = []RuleProfile{
+		{Path: "*/api", Enable: []string{"size"}},
+		{Path: "other/*", Disable: []string{"size"}},
+	}
+	if newRuleProfiles(cfg).active("rule.size", "other/api", true) {
+		t.Fatal("expected the later of two equally specific profiles to win")
+	}
+}
+
+func TestRunInternalRulePipeline_AppliesRuleProfiles(t *testing.T) {
+	dir := t.TempDir()
+	large := "package p\n\n" + strings.Repeat("var _ = 0\n", 600)
+
+	graph := NewDependencyGraph()
+	for _, rel := range []string{"cmd/tool/a.go", "cmd/other/b.go", "pkg/c.go"} {
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(file, []byte(large), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+		graph.AddNode(file)
+	}
+
+	summary := runInternalRulePipeline(dir, graph, overlappingProfilesConfig(), nil, nil)
+	var flagged []string
+	for _, v := range summary.result.Violations {
+		if v.RuleID == "rule.size" {
+			rel, _ := filepath.Rel(dir, v.File)
+			flagged = append(flagged, filepath.ToSlash(rel))
+		}
+	}
+	if want := []string{"cmd/tool/a.go", "pkg/c.go"}; !reflect.DeepEqual(flagged, want) {
+		t.Fatalf("expected size violations only where the rule is active %v, got %v", want, flagged)
+	}
+
+	// -only bypasses profiles, like it bypasses the config enable flags
+	summary = runInternalRulePipeline(dir, graph, overlappingProfilesConfig(), &RuleSelection{Only: []string{"rule.size"}}, nil)
+	if len(summary.result.Violations) != 3 {
+		t.Fatalf("expected -only to report every file, got %d violations", len(summary.result.Violations))
+	}
+}
+
+func TestEffectiveRuleMatrix_ListsPackageDirectories(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		filepath.Join(dir, "cmd", "tool", "main.go"),
+		filepath.Join(dir, "cmd", "other", "main.go"),
+		filepath.Join(dir, "main.go"),
+	}
+
+	rows := EffectiveRuleMatrix(dir, files, overlappingProfilesConfig())
+	dirs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		dirs = append(dirs, row.Directory)
+	}
+	if want := []string{".", "cmd/other", "cmd/tool"}; !reflect.DeepEqual(dirs, want) {
+		t.Fatalf("expected directories %v, got %v", want, dirs)
+	}
+	if !rows[0].Rules["size"] || rows[1].Rules["size"] || !rows[2].Rules["size"] || rows[2].Rules["god-object"] {
+		t.Fatalf("unexpected matrix: %+v", rows)
+	}
+
+	text := formatRuleMatrix(rows, "text")
+	if !strings.HasPrefix(text, "DIRECTORY") || !strings.Contains(text, "cmd/tool") {
+		t.Fatalf("unexpected text matrix:\n%s", text)
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// ruleIDPrefix is stripped from rule IDs to form their CLI short names
+const ruleIDPrefix = "rule."
+
+// RuleSelection restricts which internal rules run. Only and Skip hold
+// canonical rule IDs and are mutually exclusive; when both are empty the
+// config enable flags decide.
+type RuleSelection struct {
+	Only []string
+	Skip []string
+	// Tests selects whether the per-file rules check Go test files
+	Tests TestFileMode
+}
+
+// testFiles returns the test file mode of the selection, which may be nil
+func (s *RuleSelection) testFiles() TestFileMode {
+	if s == nil {
+		return TestFilesExclude
+	}
+	return s.Tests
+}
+
+// ruleShortName returns the CLI short name of a rule ID (e.g. "size")
+func ruleShortName(id string) string {
+	return strings.TrimPrefix(id, ruleIDPrefix)
+}
+
+// newRuntimeRuleRegistry registers every rule the runtime pipeline can run,
+// regardless of whether it is enabled. inventory may be nil when the rules
+// are only listed.
+func newRuntimeRuleRegistry(graph rules.DependencyGraph, cfg *Config, inventory *DependencyInventory) *rules.RuleRegistry {
+	registry := rules.NewRuleRegistry()
+	for _, rule := range rules.GetDefaultRegistry().GetAll() {
+		if rule.ID() == "rule.layer-validation" && cfg != nil && cfg.Layers != nil {
+			rule = rules.NewLayerValidationRuleWithHierarchy(layerHierarchyFromConfig(cfg.Layers))
+		}
+		registry.MustRegister(rule)
+	}
+	var minCycleLength int
+	if cfg != nil && cfg.Circular != nil {
+		minCycleLength = cfg.Circular.MinLength
+	}
+	registry.MustRegister(rules.NewCircularDependencyRuleWithMinLength(graph, minCycleLength))
+	registry.MustRegister(rules.NewTestOnlyCycleRule())
+
+	var allowlist []string
+	if cfg != nil && cfg.EntrypointOnly != nil {
+		allowlist = cfg.EntrypointOnly.Allowlist
+	}
+	registry.MustRegister(rules.NewEntrypointOnlyRule(allowlist))
+
+	var sharedRoots, featureRoots []string
+	if cfg != nil && cfg.FeatureIsolation != nil {
+		sharedRoots, featureRoots = cfg.FeatureIsolation.SharedRoots, cfg.FeatureIsolation.FeatureRoots
+	}
+	registry.MustRegister(rules.NewFeatureIsolationRule(sharedRoots, featureRoots))
+
+	var minCohesion float64
+	if cfg != nil && cfg.Cohesion != nil {
+		minCohesion = cfg.Cohesion.MinCohesion
+	}
+	registry.MustRegister(rules.NewStructCohesionRule(minCohesion))
+	registry.MustRegister(rules.NewSingleImplInterfaceRule())
+	registry.MustRegister(rules.NewIgnoredErrorRule())
+	registry.MustRegister(newDependencyCapRule(cfg, inventory))
+	registry.MustRegister(newImportDiversityRule(cfg, inventory))
+
+	return registry
+}
+
+// layerHierarchyFromConfig converts the layers config section into the
+// hierarchy used by the layer validation rule
+func layerHierarchyFromConfig(layers *LayersConfig) rules.LayerHierarchy {
+	hierarchy := rules.LayerHierarchy{Exempt: layers.Exempt, Fallback: layers.Default}
+	for _, level := range layers.Levels {
+		hierarchy.Levels = append(hierarchy.Levels, rules.LayerLevel{Name: level.Name, Keywords: level.Keywords})
+	}
+	return hierarchy
+}
+
+// runtimeRuleIDs lists the IDs of every rule the runtime pipeline knows
+func runtimeRuleIDs() []string {
+	return newRuntimeRuleRegistry(rules.DependencyGraph{}, nil, nil).ListIDs()
+}
+
+// parseRuleSelection validates comma-separated -only/-skip values against the
+// runtime registry. Rules may be named by ID or short name.
+func parseRuleSelection(only, skip string) (*RuleSelection, error) {
+	if strings.TrimSpace(only) != "" && strings.TrimSpace(skip) != "" {
+		return nil, NewCLIError(
+			ErrorCLIUsage,
+			"Flags -only and -skip cannot be used together",
+			"Use -only to pick rules or -skip to exclude them, not both",
+			nil,
+		)
+	}
+
+	onlyIDs, err := resolveRuleNames(only)
+	if err != nil {
+		return nil, err
+	}
+	skipIDs, err := resolveRuleNames(skip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RuleSelection{Only: onlyIDs, Skip: skipIDs}, nil
+}
+
+func resolveRuleNames(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]bool)
+	shortNames := make([]string, 0)
+	for _, id := range runtimeRuleIDs() {
+		known[id] = true
+		shortNames = append(shortNames, ruleShortName(id))
+	}
+
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		id := name
+		if !strings.HasPrefix(id, ruleIDPrefix) {
+			id = ruleIDPrefix + id
+		}
+		if !known[id] {
+			return nil, NewCLIError(
+				ErrorCLIUsage,
+				fmt.Sprintf("Unknown rule '%s'", name),
+				"Valid rules: "+strings.Join(shortNames, ", "),
+				nil,
+			)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// optInRules only run when their config section enables them
+var optInRules = map[string]bool{
+	"rule.entrypoint-only":       true,
+	"rule.feature-isolation":     true,
+	"rule.struct-cohesion":       true,
+	"rule.single-impl-interface": true,
+	"rule.dependency-cap":        true,
+	"rule.import-diversity":      true,
+	"rule.ignored-error":         true,
+}
+
+// ruleEnabledByConfig reports whether the config enables a rule. Rules
+// without an enable flag run by default; opt-in rules do not.
+func ruleEnabledByConfig(id string, cfg *Config) bool {
+	if cfg == nil {
+		return !optInRules[id]
+	}
+
+	var flag *bool
+	switch id {
+	case "rule.size":
+		if cfg.Rules != nil {
+			flag = cfg.Rules.EnableSizeRule
+		}
+	case "rule.god-object":
+		if cfg.Rules != nil {
+			flag = cfg.Rules.EnableGodObjectRule
+		}
+	case "rule.circular-dependency":
+		if cfg.Rules != nil {
+			flag = cfg.Rules.EnableCircularRule
+		}
+	case "rule.layer-validation":
+		if cfg.Rules != nil {
+			flag = cfg.Rules.EnableLayerRule
+		}
+	case "rule.entrypoint-only":
+		if cfg.EntrypointOnly != nil {
+			flag = cfg.EntrypointOnly.Enabled
+		}
+	case "rule.feature-isolation":
+		if cfg.FeatureIsolation != nil {
+			flag = cfg.FeatureIsolation.Enabled
+		}
+	case "rule.struct-cohesion":
+		if cfg.Cohesion != nil {
+			flag = cfg.Cohesion.Enabled
+		}
+	case "rule.single-impl-interface":
+		if cfg.SingleImplInterface != nil {
+			flag = cfg.SingleImplInterface.Enabled
+		}
+	case "rule.ignored-error":
+		if cfg.IgnoredError != nil {
+			flag = cfg.IgnoredError.Enabled
+		}
+	case "rule.import-diversity":
+		if cfg.ImportDiversity != nil {
+			flag = cfg.ImportDiversity.Enabled
+		}
+	case "rule.dependency-cap":
+		return cfg.Dependencies != nil && cfg.Dependencies.MaxExternal != nil
+	}
+	if optInRules[id] {
+		return flag != nil && *flag
+	}
+
+	return flag == nil || *flag
+}
+
+// effectiveRuleIDs decides which rules run: -only wins outright, otherwise
+// the config enable flags and rule profiles apply and -skip removes from
+// that set
+func effectiveRuleIDs(candidates []string, cfg *Config, selection *RuleSelection) []string {
+	if selection != nil && len(selection.Only) > 0 {
+		return append([]string(nil), selection.Only...)
+	}
+
+	skipped := make(map[string]bool)
+	if selection != nil {
+		for _, id := range selection.Skip {
+			skipped[id] = true
+		}
+	}
+
+	// Rules enabled only by a directory profile still run; their violations
+	// outside the profile's subtree are filtered afterwards
+	profiles := newRuleProfiles(cfg)
+	ids := make([]string, 0, len(candidates))
+	for _, id := range candidates {
+		if (ruleEnabledByConfig(id, cfg) || profiles.enabledAnywhere(id)) && !skipped[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/rules"
+)
+
+func TestComposeAnalyzeRequest_OnlyRestrictsRules(t *testing.T) {
+	req, err := parseAnalyzeFlags([]string{"-only", "size, rule.god-object", "."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"rule.god-object", "rule.size"}
+	if !reflect.DeepEqual(req.Options.Rules.Only, want) {
+		t.Fatalf("expected only %v, got %v", want, req.Options.Rules.Only)
+	}
+
+	got := effectiveRuleIDs(runtimeRuleIDs(), (&ConfigLoader{}).getDefaultConfig(), req.Options.Rules)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected effective rules %v, got %v", want, got)
+	}
+}
+
+func TestComposeAnalyzeRequest_SkipRemovesFromConfigSet(t *testing.T) {
+	req, err := parseAnalyzeFlags([]string{"-skip", "layer-validation", "."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := effectiveRuleIDs(runtimeRuleIDs(), (&ConfigLoader{}).getDefaultConfig(), req.Options.Rules)
+	want := []string{"rule.circular-dependency", "rule.god-object", "rule.size", "rule.test-only-cycle"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected effective rules %v, got %v", want, got)
+	}
+}
+
+func TestEffectiveRuleIDs_OnlyOverridesConfig(t *testing.T) {
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	disabled := false
+	cfg.Rules.EnableSizeRule = &disabled
+
+	withoutFlags := effectiveRuleIDs(runtimeRuleIDs(), cfg, nil)
+	for _, id := range withoutFlags {
+		if id == "rule.size" || id == "rule.entrypoint-only" {
+			t.Fatalf("expected %s to be disabled by config, got %v", id, withoutFlags)
+		}
+	}
+
+	got := effectiveRuleIDs(runtimeRuleIDs(), cfg, &RuleSelection{Only: []string{"rule.entrypoint-only", "rule.size"}})
+	want := []string{"rule.entrypoint-only", "rule.size"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected -only to win over config, got %v", got)
+	}
+}
+
+func TestComposeAnalyzeRequest_OnlyAndSkipTogetherFails(t *testing.T) {
+	_, err := parseAnalyzeFlags([]string{"-only", "size", "-skip", "layer-validation", "."})
+	if err == nil {
+		t.Fatal("expected error when combining -only and -skip")
+	}
+	if !strings.Contains(err.Error(), "cannot be used together") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestComposeAnalyzeRequest_InvalidRuleNameListsValidNames(t *testing.T) {
+	_, err := parseAnalyzeFlags([]string{"-only", "sise", "."})
+	if err == nil {
+		t.Fatal("expected error for unknown rule name")
+	}
+
+	cliErr, ok := err.(*CLIError)
+	if !ok {
+		t.Fatalf("expected *CLIError, got %T", err)
+	}
+	if !strings.Contains(cliErr.Message, "sise") {
+		t.Fatalf("expected message to name the invalid rule, got %q", cliErr.Message)
+	}
+	for _, name := range []string{"circular-dependency", "god-object", "layer-validation", "size"} {
+		if !strings.Contains(cliErr.Suggestion, name) {
+			t.Fatalf("expected suggestion to list %q, got %q", name, cliErr.Suggestion)
+		}
+	}
+}
+
+func TestRuntimeRules_DeclareValidSeverities(t *testing.T) {
+	all := newRuntimeRuleRegistry(rules.DependencyGraph{}, nil, nil).GetAll()
+	if len(all) != len(runtimeRuleIDs()) {
+		t.Fatalf("expected every runtime rule, got %d", len(all))
+	}
+	for _, rule := range append(all, &rules.ExampleRule{}) {
+		if !rule.Severity().Valid() {
+			t.Errorf("%s declares invalid severity %d", rule.ID(), rule.Severity())
+		}
+	}
+}
+
+func TestRuntimeRules_HaveDescriptions(t *testing.T) {
+	for _, rule := range newRuntimeRuleRegistry(rules.DependencyGraph{}, nil, nil).GetAll() {
+		described, ok := rule.(rules.DescribedRule)
+		if !ok || strings.TrimSpace(described.Description()) == "" {
+			t.Errorf("%s has no description", rule.ID())
+		}
+	}
+}
+
+func TestAnalyze_IgnoredErrorRuleIsOptInAndHonoursExcludes(t *testing.T) {
+	discard := "package files\n\nimport \"os\"\n\nfunc Cleanup(p string) {\n\t_ = os.Remove(p)\n}\n"
+	files := map[string]string{
+		"files/files.go":     discard,
+		"files/files_gen.go": strings.Replace(discard, "Cleanup", "Generated", 1),
+	}
+	advisories := func(config string) []AdvisoryViolation {
+		files[".repodoctor/config.yaml"] = "exclude:\n  - \"*_gen.go\"\n" + config
+		report, _ := analyzeFixture(t, files, AnalyzeOptions{})
+		return report.Advisory
+	}
+
+	if got := advisories(""); len(got) != 0 {
+		t.Fatalf("expected the rule to be off by default, got %+v", got)
+	}
+	got := advisories("ignored_error:\n  enabled: true\n")
+	if len(got) != 1 || got[0].RuleID != "rule.ignored-error" || !strings.HasSuffix(got[0].File, "files.go") || got[0].Message != "Function 'Cleanup' discards an error return at line 6" {
+		t.Fatalf("expected one advisory for files.go only, got %+v", got)
+	}
+}
+
+func TestAnalyze_FeatureIsolationIsItsOwnCategory(t *testing.T) {
+	report, _ := analyzeFixture(t, map[string]string{
+		"go.mod":                      "module example.com/app\n\ngo 1.24\n",
+		"features/billing/invoice.go": "package billing\n",
+		"lib/money/money.go":          "package money\n\nimport _ \"example.com/app/features/billing\"\n",
+		".repodoctor/config.yaml":     "feature_isolation:\n  enabled: true\n",
+	}, AnalyzeOptions{})
+
+	if len(report.Layer) != 0 || report.Summary.Layer != 0 {
+		t.Fatalf("expected no layer violations, got %+v", report.Layer)
+	}
+	if len(report.OptIn.FeatureIsolation) != 1 || report.Summary.FeatureIsolation != 1 || report.Score.OptIn.FeatureIsolationCount != 1 {
+		t.Fatalf("expected one feature isolation violation, got %+v (summary %+v)", report.OptIn.FeatureIsolation, report.Summary)
+	}
+	if report.Score.OptIn.FeatureIsolationPenalty != 5 || report.Score.LayerPenalty != 0 {
+		t.Fatalf("expected the feature isolation weight to score the violation, got penalties %.1f and %.1f", report.Score.OptIn.FeatureIsolationPenalty, report.Score.LayerPenalty)
+	}
+	if err := verifyReportInvariants(report, report.Score.Weights); err != nil {
+		t.Fatalf("expected the report to pass self-check, got %v", err)
+	}
+}
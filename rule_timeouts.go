@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// validateRuleTimeouts checks that rules.timeouts names known rules and
+// gives each a positive duration
+func validateRuleTimeouts(timeouts map[string]string) error {
+	for name, value := range timeouts {
+		if _, err := resolveRuleNames(name); err != nil {
+			return fmt.Errorf("rules.timeouts: %w", err)
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid rules.timeouts.%s '%s': %w", name, value, err)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("rules.timeouts.%s must be positive, got: %s", name, value)
+		}
+	}
+	return nil
+}
+
+// ruleTimeoutsFromConfig returns rules.timeouts keyed by rule ID, or nil
+// when no rule has a timeout. Invalid entries are skipped; Load rejects them.
+func ruleTimeoutsFromConfig(cfg *Config) map[string]time.Duration {
+	if cfg == nil || cfg.Rules == nil || len(cfg.Rules.Timeouts) == 0 {
+		return nil
+	}
+	timeouts := make(map[string]time.Duration, len(cfg.Rules.Timeouts))
+	for name, value := range cfg.Rules.Timeouts {
+		ids, err := resolveRuleNames(name)
+		timeout, parseErr := time.ParseDuration(value)
+		if err != nil || parseErr != nil || len(ids) != 1 || timeout <= 0 {
+			continue
+		}
+		timeouts[ids[0]] = timeout
+	}
+	return timeouts
+}
+
+// markTimedOutRules flags the descriptors of the rules that timed out
+func markTimedOutRules(descriptors []RuleDescriptor, timedOut []string) []RuleDescriptor {
+	for i := range descriptors {
+		descriptors[i].TimedOut = slices.Contains(timedOut, descriptors[i].Name)
+	}
+	return descriptors
+}
+
+// timedOutRules returns the IDs of the described rules that timed out
+func timedOutRules(descriptors []RuleDescriptor) []string {
+	var timedOut []string
+	for _, descriptor := range descriptors {
+		if descriptor.TimedOut {
+			timedOut = append(timedOut, descriptor.Name)
+		}
+	}
+	return timedOut
+}
+
+// warnTimedOutRules tells on stderr which rules timed out, since their
+// violations are missing from an otherwise complete report
+func warnTimedOutRules(report *StructuralReport, cfg *Config) {
+	timedOut := timedOutRules(report.Metrics.Rules)
+	if len(timedOut) == 0 {
+		return
+	}
+	timeouts := ruleTimeoutsFromConfig(cfg)
+	parts := make([]string, len(timedOut))
+	for i, id := range timedOut {
+		parts[i] = fmt.Sprintf("%s (%s)", ruleShortName(id), timeouts[id])
+	}
+	fmt.Fprint(os.Stderr, ColorWarn(fmt.Sprintf("Warning: rules timed out and report no violations: %s\n", strings.Join(parts, ", "))))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
+)
+
+// sleepingRule reports one violation per file after sleeping for delay
+type sleepingRule struct {
+	id    string
+	delay time.Duration
+}
+
+func (r *sleepingRule) ID() string               { return r.id }
+func (r *sleepingRule) Category() string         { return "testing" }
+func (r *sleepingRule) Severity() model.Severity { return model.SeverityWarning }
+func (r *sleepingRule) Evaluate(context rules.AnalysisContext) []model.Violation {
+	time.Sleep(r.delay)
+	violations := make([]model.Violation, 0, len(context.RepositoryFiles))
+	for _, file := range context.RepositoryFiles {
+		violations = append(violations, model.Violation{RuleID: r.id, File: file.Path})
+	}
+	return violations
+}
+
+func TestExecuteRuleSets_TimedOutRuleLeavesPartialResults(t *testing.T) {
+	registry := rules.NewRuleRegistry()
+	registry.MustRegister(&sleepingRule{id: "rule.quick"})
+	registry.MustRegister(&sleepingRule{id: "rule.stalled", delay: 300 * time.Millisecond})
+	context := rules.AnalysisContext{RepositoryFiles: []rules.RepositoryFile{{Path: "a.go"}, {Path: "b.go"}}}
+
+	result := executeRuleSets(registry, context, context, map[string]time.Duration{"rule.stalled": 20 * time.Millisecond})
+	if len(result.Violations) != 2 || result.Violations[0].RuleID != "rule.quick" || result.Violations[1].RuleID != "rule.quick" {
+		t.Fatalf("expected the quick rule's violations only, got %+v", result.Violations)
+	}
+	descriptors := markTimedOutRules([]RuleDescriptor{{Name: "rule.quick"}, {Name: "rule.stalled"}}, result.TimedOutRules)
+	if descriptors[0].TimedOut || !descriptors[1].TimedOut {
+		t.Fatalf("expected only rule.stalled to be marked timed out, got %+v", descriptors)
+	}
+}
+
+func TestRuleTimeouts_MarkedInReportMetadata(t *testing.T) {
+	report := &StructuralReport{
+		Path:    "demo",
+		Score:   &StructuralScore{TotalScore: 100, MaxScore: 100},
+		Metrics: ReportMetrics{Rules: []RuleDescriptor{{Name: "rule.size"}, {Name: "rule.god-object", TimedOut: true}}},
+	}
+
+	var v1 struct {
+		Rules []struct {
+			Name     string `json:"name"`
+			TimedOut bool   `json:"timedOut"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &v1); err != nil {
+		t.Fatalf("invalid json-v1: %v", err)
+	}
+	if len(v1.Rules) != 2 || v1.Rules[0].TimedOut || !v1.Rules[1].TimedOut {
+		t.Fatalf("expected the rules list to mark god-object as timed out, got %+v", v1.Rules)
+	}
+
+	var legacy struct {
+		Metrics struct {
+			TimedOutRules []string `json:"timedOutRules"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONLegacy).Format(report)), &legacy); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(legacy.Metrics.TimedOutRules) != 1 || legacy.Metrics.TimedOutRules[0] != "rule.god-object" {
+		t.Fatalf("expected metrics.timedOutRules to name god-object, got %+v", legacy.Metrics)
+	}
+}
+
+func TestDetermineExitCode_FailOnTimeout(t *testing.T) {
+	timedOut := []RuleDescriptor{{Name: "rule.size", TimedOut: true}}
+	tests := []struct {
+		name    string
+		metrics ReportMetrics
+		want    int
+	}{
+		{name: "timeout continues by default", metrics: ReportMetrics{Rules: timedOut}, want: 0},
+		{name: "timeout fails with -fail-on-timeout", metrics: ReportMetrics{Rules: timedOut, Exit: ExitPolicy{FailOnTimeout: true}}, want: exitCodeFailure},
+		{name: "no timeout passes with -fail-on-timeout", metrics: ReportMetrics{Rules: []RuleDescriptor{{Name: "rule.size"}}, Exit: ExitPolicy{FailOnTimeout: true}}, want: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := &StructuralReport{Score: &StructuralScore{TotalScore: 100, MaxScore: 100}, Metrics: tc.metrics}
+			if got := determineExitCode(report); got != tc.want {
+				t.Fatalf("expected exit code %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRuleTimeoutsConfig(t *testing.T) {
+	cfg := &Config{Rules: &RulesConfig{Timeouts: map[string]string{"size": "30s", "rule.god-object": "1m"}}}
+	if err := validateRuleTimeouts(cfg.Rules.Timeouts); err != nil {
+		t.Fatalf("expected valid timeouts, got %v", err)
+	}
+	timeouts := ruleTimeoutsFromConfig(cfg)
+	if len(timeouts) != 2 || timeouts["rule.size"] != 30*time.Second || timeouts["rule.god-object"] != time.Minute {
+		t.Fatalf("expected timeouts keyed by rule ID, got %v", timeouts)
+	}
+	if ruleTimeoutsFromConfig(&Config{}) != nil {
+		t.Fatal("expected no timeouts by default")
+	}
+
+	for _, invalid := range []map[string]string{{"no-such-rule": "1s"}, {"size": "soon"}, {"size": "0s"}} {
+		if err := validateRuleTimeouts(invalid); err == nil {
+			t.Errorf("expected %v to be rejected", invalid)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"maps"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"RepoDoctor/internal/engine"
 	"RepoDoctor/internal/model"
@@ -14,41 +18,150 @@ import (
 type runtimeRuleSummary struct {
 	result       *engine.ExecutionResult
 	rulesInScope int
+	ruleIDs      []string
+	// cohesion holds struct cohesion metrics when the cohesion rule runs
+	cohesion     []rules.StructCohesion
+	dependencies *DependencyInventory
+	largest      *rules.LargestArtifacts
+	descriptors  []RuleDescriptor
+	thirdParty   []ThirdPartyDir
+	// files are the files per-file rules considered, before sampling
+	files []string
+	// stats sizes files and the graph
+	stats *RepositoryStats
+	// graph is the dependency graph the rules ran on
+	graph Graph
 }
 
-func runInternalRulePipeline(absPath string, graph Graph) *runtimeRuleSummary {
+// largestArtifactsTopN is the number of files and functions listed in each
+// largest-artifacts ranking
+const largestArtifactsTopN = 10
+
+// runInternalRulePipeline runs the effective rules. Per-file rules skip
+// detected third-party code unless the config keeps it, and with a sample
+// they only see the sampled files; graph rules see every file.
+func runInternalRulePipeline(absPath string, graph Graph, cfg *Config, selection *RuleSelection, sample *SampleSpec) *runtimeRuleSummary {
+	return runInternalRulePipelineWithSources(absPath, graph, cfg, selection, sample, nil)
+}
+
+// runInternalRulePipelineWithSources runs the effective rules, taking the
+// content of provided files from provided instead of the disk
+func runInternalRulePipelineWithSources(absPath string, graph Graph, cfg *Config, selection *RuleSelection, sample *SampleSpec, provided *providedSources) *runtimeRuleSummary {
+	inventory := buildDependencyInventory(absPath, graph, cfg, previousHistoryEntry(absPath))
+	candidates := newRuntimeRuleRegistry(toRulesDependencyGraph(graph), cfg, inventory)
+
 	registry := rules.NewRuleRegistry()
-	for _, rule := range rules.GetDefaultRegistry().GetAll() {
-		registry.MustRegister(rule)
+	for _, id := range effectiveRuleIDs(candidates.ListIDs(), cfg, selection) {
+		registry.MustRegister(candidates.GetByID(id))
 	}
-	registry.MustRegister(rules.NewCircularDependencyRule(toRulesDependencyGraph(graph)))
 
-	executor := engine.NewRuleExecutor(registry)
-	context := buildRulesAnalysisContext(absPath, graph)
-	result := executor.Execute(context)
+	context := buildRulesAnalysisContext(absPath, graph, provided)
+	fileContext := context
+	thirdParty := detectThirdPartyCode(absPath, context.RepositoryFiles)
+	if len(thirdParty) > 0 && thirdPartyExcluded(cfg) {
+		fileContext.RepositoryFiles = withoutThirdPartyFiles(absPath, fileContext.RepositoryFiles, thirdParty)
+	}
+	fileContext.RepositoryFiles = withoutExcludedFiles(absPath, fileContext.RepositoryFiles, configExcludes(cfg))
+	ownFiles := fileContext.RepositoryFiles
+	fileContext.RepositoryFiles = testFileScope(absPath, ownFiles, selection.testFiles(), cfg, thirdParty)
+	if sample != nil {
+		fileContext.RepositoryFiles = sampleRepositoryFiles(absPath, fileContext.RepositoryFiles, sample)
+	}
+	loadParseCache(absPath, rules.SharedParseCache())
+	result := executeRuleSets(registry, context, fileContext, ruleTimeoutsFromConfig(cfg))
+	largest := rules.FindLargestArtifacts(fileContext.RepositoryFiles, rules.SharedParseCache(), largestArtifactsTopN)
+	if selection == nil || len(selection.Only) == 0 {
+		result.Violations = newRuleProfiles(cfg).filterViolations(absPath, result.Violations, cfg)
+	}
 	sortViolations(result.Violations)
 
-	return &runtimeRuleSummary{
+	summary := &runtimeRuleSummary{
 		result:       result,
 		rulesInScope: registry.Count(),
+		ruleIDs:      registry.ListIDs(),
+		dependencies: inventory,
+		largest:      largest,
+		descriptors:  markTimedOutRules(buildRuleDescriptors(registry, cfg), result.TimedOutRules),
+		thirdParty:   thirdParty,
+		files:        repositoryFilePaths(ownFiles),
+		stats:        computeRepositoryStats(absPath, ownFiles, graph),
+		graph:        graph,
+	}
+	if registry.GetByID("rule.struct-cohesion") != nil {
+		summary.cohesion = rules.AnalyzeStructCohesion(fileContext.RepositoryFiles)
+	}
+	return summary
+}
+
+// executeRuleSets runs the per-file rules against fileContext and all other
+// rules against context, merging the results. When both contexts hold the
+// same files, as without sampling, excludes or test files, the registry
+// runs in one pass. Rules with a timeout are stopped at it.
+func executeRuleSets(registry *rules.RuleRegistry, context, fileContext rules.AnalysisContext, timeouts map[string]time.Duration) *engine.ExecutionResult {
+	newExecutor := func(registry *rules.RuleRegistry) *engine.RuleExecutor {
+		executor := engine.NewRuleExecutor(registry)
+		executor.SetRuleTimeouts(timeouts)
+		return executor
+	}
+	if sameRepositoryFiles(fileContext.RepositoryFiles, context.RepositoryFiles) {
+		return newExecutor(registry).Execute(context)
 	}
+
+	graphRules, fileRules := rules.NewRuleRegistry(), rules.NewRuleRegistry()
+	for _, rule := range registry.GetAll() {
+		if sampledRuleIDs[rule.ID()] {
+			fileRules.MustRegister(rule)
+		} else {
+			graphRules.MustRegister(rule)
+		}
+	}
+
+	result := newExecutor(graphRules).Execute(context)
+	sampled := newExecutor(fileRules).Execute(fileContext)
+	result.Violations = append(result.Violations, sampled.Violations...)
+	result.RulesExecuted += sampled.RulesExecuted
+	result.TimedOut = result.TimedOut || sampled.TimedOut
+	result.Coverage = append(result.Coverage, sampled.Coverage...)
+	maps.Copy(result.Durations, sampled.Durations)
+	result.TimedOutRules = append(result.TimedOutRules, sampled.TimedOutRules...)
+	return result
+}
+
+// sameRepositoryFiles reports whether a and b hold the same file paths in
+// the same order
+func sameRepositoryFiles(a, b []rules.RepositoryFile) bool {
+	return slices.EqualFunc(a, b, func(x, y rules.RepositoryFile) bool {
+		return x.Path == y.Path
+	})
+}
+
+// repositoryFilePaths returns the paths of files
+func repositoryFilePaths(files []rules.RepositoryFile) []string {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.Path
+	}
+	return paths
 }
 
-func buildRulesAnalysisContext(absPath string, graph Graph) rules.AnalysisContext {
+func buildRulesAnalysisContext(absPath string, graph Graph, provided *providedSources) rules.AnalysisContext {
 	nodes := graph.GetAllNodes()
-	sort.Strings(nodes)
 
 	repoFiles := make([]rules.RepositoryFile, 0, len(nodes))
 	for _, node := range nodes {
-		content := ""
-		if data, err := os.ReadFile(node); err == nil {
-			content = string(data)
+		content, ok := provided.content(node)
+		if !ok {
+			if data, err := os.ReadFile(node); err == nil {
+				content = string(data)
+			}
 		}
 
+		imports := graph.GetDependencies(node)
 		repoFiles = append(repoFiles, rules.RepositoryFile{
-			Path:    node,
-			Content: content,
-			Imports: graph.GetDependencies(node),
+			Path:         node,
+			Content:      content,
+			Imports:      imports,
+			BlankImports: blankImportsOf(graph, node, imports),
 		})
 	}
 
@@ -62,16 +175,21 @@ func buildRulesAnalysisContext(absPath string, graph Graph) rules.AnalysisContex
 
 func toRulesDependencyGraph(graph Graph) rules.DependencyGraph {
 	nodes := graph.GetAllNodes()
-	sort.Strings(nodes)
 	edges := make(map[string][]string, len(nodes))
+	testEdges := make(map[string][]string)
+	testGraph, _ := graph.(TestEdgeGraph)
 
 	for _, node := range nodes {
-		deps := append([]string(nil), graph.GetDependencies(node)...)
-		sort.Strings(deps)
-		edges[node] = deps
+		edges[node] = graph.GetDependencies(node)
+		if testGraph == nil {
+			continue
+		}
+		if testDeps := testGraph.GetTestDependencies(node); len(testDeps) > 0 {
+			testEdges[node] = testDeps
+		}
 	}
 
-	return rules.DependencyGraph{Nodes: nodes, Edges: edges}
+	return rules.DependencyGraph{Nodes: nodes, Edges: edges, TestEdges: testEdges}
 }
 
 func sortViolations(violations []model.Violation) {
@@ -90,7 +208,7 @@ func sortViolations(violations []model.Violation) {
 }
 
 func buildReportFromRuleViolations(path string, version string, cfg *Config, violations []model.Violation) *StructuralReport {
-	report := &StructuralReport{Version: version, Path: path}
+	report := &StructuralReport{Version: version, SchemaVersion: jsonSchemaVersion, Path: path}
 
 	// Accumulate god object violations by file+struct so field and method
 	// violations for the same struct merge into a single report entry.
@@ -99,21 +217,41 @@ func buildReportFromRuleViolations(path string, version string, cfg *Config, vio
 	for _, v := range violations {
 		switch v.RuleID {
 		case "rule.circular-dependency":
-			report.Circular = append(report.Circular, CycleViolation{Path: []string{v.File}, Severity: string(v.Severity)})
+			report.Circular = append(report.Circular, CycleViolation{Path: parseCyclePath(v), Severity: v.Severity})
+		case "rule.feature-isolation":
+			report.OptIn.FeatureIsolation = append(report.OptIn.FeatureIsolation, FeatureIsolationViolation{From: v.File, Message: v.Message, Severity: v.Severity})
+		case "rule.dependency-cap":
+			report.OptIn.DependencyCap = append(report.OptIn.DependencyCap, DependencyCapViolation{Manifest: v.File, Message: v.Message, Severity: v.Severity})
 		case "rule.layer-validation":
-			report.Layer = append(report.Layer, LayerViolation{From: v.File, To: "", Message: v.Message})
+			report.Layer = append(report.Layer, LayerViolation{From: v.File, To: "", Message: v.Message, RuleID: v.RuleID, Severity: v.Severity})
 		case "rule.size":
 			report.Size = append(report.Size, parseSizeViolation(v))
 		case "rule.god-object":
 			mergeGodObjectViolation(godObjectMap, v)
+		case "rule.single-impl-interface":
+			report.OptIn.SingleImpl = append(report.OptIn.SingleImpl, parseSingleImplViolation(v))
+		case "rule.entrypoint-only", "rule.struct-cohesion", "rule.test-only-cycle", "rule.import-diversity", "rule.ignored-error":
+			report.Advisory = append(report.Advisory, AdvisoryViolation{RuleID: v.RuleID, File: v.File, Message: v.Message})
 		}
 	}
 
-	for _, gov := range godObjectMap {
-		report.GodObject = append(report.GodObject, *gov)
+	for _, key := range sortedKeys(godObjectMap) {
+		report.GodObject = append(report.GodObject, *godObjectMap[key])
 	}
 
-	report.HasViolations = len(violations) > 0
+	report.Summary = ReportSummary{
+		TotalViolations:  len(report.Circular) + len(report.Layer) + len(report.OptIn.FeatureIsolation) + len(report.OptIn.DependencyCap) + len(report.Size) + len(report.GodObject),
+		Circular:         len(report.Circular),
+		Layer:            len(report.Layer),
+		FeatureIsolation: len(report.OptIn.FeatureIsolation),
+		DependencyCap:    len(report.OptIn.DependencyCap),
+		Size:             len(report.Size),
+		GodObject:        len(report.GodObject),
+	}
+
+	// Advisories and single-implementation interfaces are informational and
+	// do not count as structural violations
+	report.HasViolations = len(violations) > len(report.Advisory)+len(report.OptIn.SingleImpl)
 	report.Score = calculateScoreFromViolations(cfg, report)
 	return report
 }
@@ -126,21 +264,48 @@ func buildReportFromRuleViolations(path string, version string, cfg *Config, vio
 // GodObject:  "<Struct> has <N> fields (threshold: <T>)"
 //
 //	"<Struct> has <N> methods (threshold: <T>)"
+//
+// SingleImpl: "Interface <iface> has a single implementation: <impl>"
 var (
 	sizeFileRe  = regexp.MustCompile(`has (\d+) lines \(threshold: (\d+)\)`)
 	sizeFuncRe  = regexp.MustCompile(`^Function '([^']+)' has (\d+) lines \(threshold: (\d+)\)`)
 	godFieldRe  = regexp.MustCompile(`^(.+) has (\d+) fields \(threshold: \d+\)`)
 	godMethodRe = regexp.MustCompile(`^(.+) has (\d+) methods \(threshold: \d+\)`)
+	singleImpRe = regexp.MustCompile(`^Interface (\S+) has a single implementation: (\S+)$`)
 )
 
+// parseCyclePath extracts every node of a cycle from a circular dependency
+// message ("[<prefix>: ]a → b → a"), falling back to the reported file
+func parseCyclePath(v model.Violation) []string {
+	message := v.Message
+	if i := strings.LastIndex(message, ": "); i >= 0 {
+		message = message[i+2:]
+	}
+	nodes := strings.Split(message, " → ")
+	if len(nodes) < 2 || nodes[0] != nodes[len(nodes)-1] {
+		return []string{v.File}
+	}
+	return nodes[:len(nodes)-1]
+}
+
+// parseSingleImplViolation extracts the interface and implementation names
+// from a single-implementation interface violation message
+func parseSingleImplViolation(v model.Violation) SingleImplInterfaceViolation {
+	if m := singleImpRe.FindStringSubmatch(v.Message); len(m) == 3 {
+		return SingleImplInterfaceViolation{Interface: m[1], Impl: m[2]}
+	}
+	return SingleImplInterfaceViolation{Interface: v.Message}
+}
+
 // parseSizeViolation extracts Lines, Threshold, and Function from a size
 // violation message instead of using hardcoded placeholder values.
 func parseSizeViolation(v model.Violation) SizeViolation {
-	sv := SizeViolation{File: v.File}
+	sv := SizeViolation{File: v.File, Severity: v.Severity}
 
 	// Try function-level match first (more specific)
 	if m := sizeFuncRe.FindStringSubmatch(v.Message); len(m) == 4 {
 		sv.Function = m[1]
+		sv.Line, sv.StartLine, sv.EndLine = v.Line, v.Line, v.EndLine
 		sv.Lines, _ = strconv.Atoi(m[2])
 		sv.Threshold, _ = strconv.Atoi(m[3])
 		return sv
@@ -148,6 +313,7 @@ func parseSizeViolation(v model.Violation) SizeViolation {
 
 	// Fall back to file-level match
 	if m := sizeFileRe.FindStringSubmatch(v.Message); len(m) == 3 {
+		sv.Line, sv.StartLine, sv.EndLine = v.Line, v.Line, v.EndLine
 		sv.Lines, _ = strconv.Atoi(m[1])
 		sv.Threshold, _ = strconv.Atoi(m[2])
 	}
@@ -177,42 +343,80 @@ func mergeGodObjectViolation(m map[string]*GodObjectViolation, v model.Violation
 	if existing, ok := m[key]; ok {
 		existing.FieldCount += fieldCount
 		existing.MethodCount += methodCount
+		existing.Severity = max(existing.Severity, v.Severity)
 	} else {
 		m[key] = &GodObjectViolation{
 			StructName:  structName,
 			File:        v.File,
+			Line:        v.Line,
+			StartLine:   v.Line,
+			EndLine:     v.EndLine,
 			FieldCount:  fieldCount,
 			MethodCount: methodCount,
+			Severity:    v.Severity,
 		}
 	}
 }
 
-func calculateScoreFromViolations(cfg *Config, report *StructuralReport) *StructuralScore {
+// scoringWeightsFromConfig returns the default weights overridden by config
+func scoringWeightsFromConfig(cfg *Config) *ScoringWeights {
 	weights := DefaultScoringWeights()
 	if cfg != nil && cfg.Weights != nil {
 		weights.CircularDependencyPenalty = cfg.Weights.Circular
 		weights.LayerViolationPenalty = cfg.Weights.Layer
 		weights.SizeViolationPenalty = cfg.Weights.Size
 		weights.GodObjectPenalty = cfg.Weights.GodObject
+		weights.FeatureIsolationPenalty = cfg.Weights.FeatureIsolation
+		weights.DependencyCapPenalty = cfg.Weights.DependencyCap
+	}
+	if cfg != nil && cfg.Penalties != nil {
+		weights.CircularCurve = penaltyCurve(cfg.Penalties.Circular)
+		weights.LayerCurve = penaltyCurve(cfg.Penalties.Layer)
+		weights.SizeCurve = penaltyCurve(cfg.Penalties.Size)
+		weights.GodObjectCurve = penaltyCurve(cfg.Penalties.GodObject)
 	}
+	return weights
+}
+
+// penaltyCurve parses a configured penalty expression; empty or invalid
+// expressions (already rejected at config load) leave the flat weight
+func penaltyCurve(source string) *PenaltyExpr {
+	if source == "" {
+		return nil
+	}
+	curve, err := ParsePenaltyExpr(source)
+	if err != nil {
+		return nil
+	}
+	return curve
+}
 
-	score := &StructuralScore{MaxScore: 100.0}
+// calculateScoreFromViolations scores a report with the configured score
+// model. The per-category penalties belong to the weighted model and stay
+// zero under any other model.
+func calculateScoreFromViolations(cfg *Config, report *StructuralReport) *StructuralScore {
+	model := scoreModelFromConfig(cfg)
+	result := model.Compute(scoreFindingsOf(report))
+
+	score := &StructuralScore{MaxScore: result.Max, TotalScore: result.Total, Model: result.Model}
 	score.CircularCount = len(report.Circular)
 	score.LayerCount = len(report.Layer)
+	score.OptIn.FeatureIsolationCount = len(report.OptIn.FeatureIsolation)
+	score.OptIn.DependencyCapCount = len(report.OptIn.DependencyCap)
 	score.SizeCount = len(report.Size)
 	score.GodObjectCount = len(report.GodObject)
-
-	score.CircularPenalty = float64(score.CircularCount) * weights.CircularDependencyPenalty
-	score.LayerPenalty = float64(score.LayerCount) * weights.LayerViolationPenalty
-	score.SizePenalty = float64(score.SizeCount) * weights.SizeViolationPenalty
-	score.GodObjectPenalty = float64(score.GodObjectCount) * weights.GodObjectPenalty
-
-	score.ViolationCount = score.CircularCount + score.LayerCount + score.SizeCount + score.GodObjectCount
-	penalty := score.CircularPenalty + score.LayerPenalty + score.SizePenalty + score.GodObjectPenalty
-	score.TotalScore = score.MaxScore - penalty
-	if score.TotalScore < 0 {
-		score.TotalScore = 0
+	score.ViolationCount = score.CircularCount + score.LayerCount + score.OptIn.FeatureIsolationCount + score.OptIn.DependencyCapCount + score.SizeCount + score.GodObjectCount
+
+	if result.Model == ScoreModelWeighted {
+		score.CircularPenalty = result.Breakdown[0].Points
+		score.LayerPenalty = result.Breakdown[1].Points
+		score.SizePenalty = result.Breakdown[2].Points
+		score.GodObjectPenalty = result.Breakdown[3].Points
+		score.OptIn.FeatureIsolationPenalty = result.Breakdown[4].Points
+		score.OptIn.DependencyCapPenalty = result.Breakdown[5].Points
+		score.Weights = model.(weightedPenaltyModel).weights
+	} else {
+		score.Breakdown = result.Breakdown
 	}
-
 	return score
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSampleSpec_SelectionIsPinnedToSeedAndPath(t *testing.T) {
+	spec := &SampleSpec{Fraction: 0.5, Seed: 42}
+	// Fixed expectations: the selection must not change between releases or
+	// operating systems, since it depends only on the slash-separated path
+	want := map[string]bool{
+		"a.go": false, "b.go": true, "c.go": false,
+		"d.go": true, "pkg/e.go": true, "pkg/f.go": false,
+	}
+	for path, included := range want {
+		if got := spec.includes(path); got != included {
+			t.Errorf("%s: expected included=%v, got %v", path, included, got)
+		}
+	}
+}
+
+func TestSampleSpec_SeedChangesSelection(t *testing.T) {
+	paths := make([]string, 200)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("pkg%d/file%d.go", i%7, i)
+	}
+	selected := func(spec *SampleSpec) []string {
+		var kept []string
+		for _, path := range paths {
+			if spec.includes(path) {
+				kept = append(kept, path)
+			}
+		}
+		return kept
+	}
+
+	first := selected(&SampleSpec{Fraction: 0.2, Seed: 42})
+	if again := selected(&SampleSpec{Fraction: 0.2, Seed: 42}); !reflect.DeepEqual(first, again) {
+		t.Fatal("expected the same seed and fraction to select the same files")
+	}
+	if other := selected(&SampleSpec{Fraction: 0.2, Seed: 7}); reflect.DeepEqual(first, other) {
+		t.Fatal("expected a different seed to select different files")
+	}
+	if len(first) < 20 || len(first) > 60 {
+		t.Fatalf("expected about 20%% of %d files, got %d", len(paths), len(first))
+	}
+	if all := selected(&SampleSpec{Fraction: 1, Seed: 42}); len(all) != len(paths) {
+		t.Fatalf("expected fraction 1 to select every file, got %d", len(all))
+	}
+}
+
+func TestParseSampleSpec_RejectsInvalidFractions(t *testing.T) {
+	if spec, err := parseSampleSpec(0, 42); err != nil || spec != nil {
+		t.Fatalf("expected no sampling for fraction 0, got %+v, %v", spec, err)
+	}
+	for _, fraction := range []float64{-0.1, 1.5} {
+		if _, err := parseSampleSpec(fraction, 0); err == nil {
+			t.Errorf("expected an error for fraction %v", fraction)
+		}
+	}
+}
+
+func TestRunInternalRulePipeline_SamplesPerFileRules(t *testing.T) {
+	dir := t.TempDir()
+	large := "package p\n\n" + strings.Repeat("var _ = 0\n", 600)
+
+	graph := NewDependencyGraph()
+	for _, rel := range []string{"a.go", "b.go", "c.go", "d.go", "pkg/e.go", "pkg/f.go"} {
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(file, []byte(large), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+		graph.AddNode(file)
+	}
+
+	sample := &SampleSpec{Fraction: 0.5, Seed: 42}
+	summary := runInternalRulePipeline(dir, graph, (&ConfigLoader{}).getDefaultConfig(), nil, sample)
+	var flagged []string
+	for _, v := range summary.result.Violations {
+		if v.RuleID == "rule.size" {
+			rel, _ := filepath.Rel(dir, v.File)
+			flagged = append(flagged, filepath.ToSlash(rel))
+		}
+	}
+	if want := []string{"b.go", "d.go", "pkg/e.go"}; !reflect.DeepEqual(flagged, want) {
+		t.Fatalf("expected size violations only for sampled files %v, got %v", want, flagged)
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"RepoDoctor/internal/model"
+	"RepoDoctor/internal/rules"
+)
+
+// FormatSARIF prints the report as a SARIF 2.1.0 log, for code scanning
+const FormatSARIF OutputFormat = "sarif"
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifSourceRoot is the uriBaseId result paths are relative to
+	sarifSourceRoot = "%SRCROOT%"
+	// repoDoctorURL is the project page; rule help links point at its README
+	repoDoctorURL = "https://github.com/AdemFurkanATA/RepoDoctor"
+)
+
+// sarifCoreRules always get a descriptor, one per violation type of the
+// report, whether or not they ran
+var sarifCoreRules = []string{"rule.circular-dependency", "rule.layer-validation", "rule.size", "rule.god-object"}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// sarifRule is a SARIF reportingDescriptor built from a RuleDescriptor
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevel maps a severity to a SARIF result level
+func sarifLevel(severity model.Severity) string {
+	switch {
+	case severity >= model.SeverityError:
+		return "error"
+	case severity == model.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// formatSARIF renders the circular, layer, size and god object violations
+// as one SARIF run with one result per violation. Rule descriptors come
+// from the same registry metadata as the json-v1 rules array: the report's
+// own descriptors first, then the runtime registry's defaults for the core
+// rules and any other rule a result refers to.
+func formatSARIF(report *StructuralReport) string {
+	builder := newSARIFBuilder(report)
+	for _, v := range report.Circular {
+		cycle := make([]string, len(v.Path))
+		for i, file := range v.Path {
+			cycle[i] = builder.relative(file)
+		}
+		message := "Circular dependency: " + formatCyclePath(cycle)
+		if note := cycleBlankImportNote(v, builder.relative); note != "" {
+			message += " (" + note + ")"
+		}
+		builder.add("rule.circular-dependency", v.Severity, message, firstOrEmpty(v.Path), 0)
+	}
+	for _, v := range report.Layer {
+		ruleID := v.RuleID
+		if ruleID == "" {
+			ruleID = "rule.layer-validation"
+		}
+		builder.add(ruleID, model.SeverityError, v.Message, v.From, 0)
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		builder.add("rule.feature-isolation", model.SeverityError, v.Message, v.From, 0)
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		builder.add("rule.dependency-cap", model.SeverityError, v.Message, v.Manifest, 0)
+	}
+	for _, v := range report.Size {
+		builder.add("rule.size", model.SeverityWarning, sizeViolationMessage(v), v.File, v.Line)
+	}
+	for _, v := range report.GodObject {
+		builder.add("rule.god-object", model.SeverityWarning, godObjectViolationMessage(v), v.File, v.Line)
+	}
+
+	data, err := json.MarshalIndent(builder.log, "", "  ")
+	if err != nil {
+		return "{}\n"
+	}
+	return string(data) + "\n"
+}
+
+// sizeViolationMessage describes a size violation in one sentence
+func sizeViolationMessage(v SizeViolation) string {
+	if v.Function != "" {
+		return fmt.Sprintf("Function '%s' has %d lines (threshold: %d)", v.Function, v.Lines, v.Threshold)
+	}
+	return fmt.Sprintf("File has %d lines (threshold: %d)", v.Lines, v.Threshold)
+}
+
+// godObjectViolationMessage describes a god object violation in one sentence
+func godObjectViolationMessage(v GodObjectViolation) string {
+	return fmt.Sprintf("%s has %d fields and %d methods", v.StructName, v.FieldCount, v.MethodCount)
+}
+
+// sarifBuilder accumulates the results of one SARIF run
+type sarifBuilder struct {
+	log       sarifLog
+	root      string
+	ruleIndex map[string]int
+	// defaults describes every runtime rule with the default configuration
+	defaults map[string]RuleDescriptor
+}
+
+func newSARIFBuilder(report *StructuralReport) *sarifBuilder {
+	builder := &sarifBuilder{root: report.Path, ruleIndex: make(map[string]int), defaults: make(map[string]RuleDescriptor)}
+	for _, descriptor := range buildRuleDescriptors(newRuntimeRuleRegistry(rules.DependencyGraph{}, nil, nil), nil) {
+		builder.defaults[descriptor.Name] = descriptor
+	}
+	builder.log = sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "RepoDoctor", Version: version, InformationURI: repoDoctorURL, Rules: []sarifRule{}}},
+			Results: []sarifResult{},
+		}},
+	}
+	for _, descriptor := range report.Metrics.Rules {
+		builder.addRule(descriptor)
+	}
+	for _, id := range sarifCoreRules {
+		builder.ruleFor(id)
+	}
+	return builder
+}
+
+// ruleFor returns the descriptor index of a rule, adding its default
+// descriptor when the run has none yet
+func (b *sarifBuilder) ruleFor(id string) int {
+	if index, ok := b.ruleIndex[id]; ok {
+		return index
+	}
+	descriptor, ok := b.defaults[id]
+	if !ok {
+		descriptor = RuleDescriptor{Name: id, DocsAnchor: ruleDocsAnchor(id)}
+	}
+	return b.addRule(descriptor)
+}
+
+// addRule appends a rule descriptor to the driver and returns its index
+func (b *sarifBuilder) addRule(descriptor RuleDescriptor) int {
+	driver := &b.log.Runs[0].Tool.Driver
+	rule := sarifRule{
+		ID:                   descriptor.Name,
+		Name:                 ruleShortName(descriptor.Name),
+		ShortDescription:     sarifMessage{Text: descriptor.Description},
+		HelpURI:              repoDoctorURL + "#" + descriptor.DocsAnchor,
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(descriptor.Severity)},
+	}
+	if rule.ShortDescription.Text == "" {
+		rule.ShortDescription.Text = descriptor.Name
+	}
+	if len(descriptor.Thresholds) > 0 {
+		rule.Properties = map[string]any{"thresholds": descriptor.Thresholds}
+	}
+	b.ruleIndex[descriptor.Name] = len(driver.Rules)
+	driver.Rules = append(driver.Rules, rule)
+	return b.ruleIndex[descriptor.Name]
+}
+
+// add appends one result; line 0 means the violation has no line
+func (b *sarifBuilder) add(ruleID string, severity model.Severity, message, file string, line int) {
+	index := b.ruleFor(ruleID)
+	location := sarifPhysicalLocation{ArtifactLocation: b.artifactLocation(file)}
+	if line > 0 {
+		location.Region = &sarifRegion{StartLine: line}
+	}
+	run := &b.log.Runs[0]
+	run.Results = append(run.Results, sarifResult{
+		RuleID:    ruleID,
+		RuleIndex: index,
+		Level:     sarifLevel(severity),
+		Message:   sarifMessage{Text: message},
+		Locations: []sarifLocation{{PhysicalLocation: location}},
+	})
+}
+
+// relative returns file relative to the analyzed directory, with slashes,
+// or unchanged when it lies outside it
+func (b *sarifBuilder) relative(file string) string {
+	if filepath.IsAbs(file) && b.root != "" {
+		if rel, err := filepath.Rel(b.root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return file
+}
+
+// artifactLocation returns a file location relative to the analyzed
+// directory, which code scanning resolves against the checkout
+func (b *sarifBuilder) artifactLocation(file string) sarifArtifactLocation {
+	file = b.relative(file)
+	if filepath.IsAbs(file) {
+		return sarifArtifactLocation{URI: "file:///" + strings.TrimPrefix(filepath.ToSlash(file), "/")}
+	}
+	return sarifArtifactLocation{URI: filepath.ToSlash(file), URIBaseID: sarifSourceRoot}
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
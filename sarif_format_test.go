@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+)
+
+func TestFormatSARIF_OneResultPerViolation(t *testing.T) {
+	report := &StructuralReport{
+		Version: version,
+		Path:    "/repo",
+		Score:   &StructuralScore{TotalScore: 80, MaxScore: 100},
+		Circular: []CycleViolation{
+			{Path: []string{"/repo/a/a.go", "/repo/b/b.go"}, Severity: model.SeverityCritical},
+		},
+		Layer: []LayerViolation{{From: "/repo/repo/store.go", To: "/repo/handler/h.go", Message: "repository imports handler", RuleID: "rule.layer-validation"}},
+		Size: []SizeViolation{
+			{File: "/repo/big.go", Lines: 600, Threshold: 500},
+			{File: "/repo/big.go", Function: "Run", Lines: 90, Threshold: 80, Line: 12},
+		},
+		GodObject: []GodObjectViolation{{StructName: "Service", File: "/repo/service.go", FieldCount: 20, Line: 7}},
+		Metrics: ReportMetrics{Rules: []RuleDescriptor{
+			{Name: "rule.size", Description: "Reports long files", Severity: model.SeverityWarning, Weight: 3, DocsAnchor: "rule-size"},
+		}},
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(NewReporter(FormatSARIF).Format(report)), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected one SARIF 2.1.0 run, got version %q and %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "RepoDoctor" || run.Tool.Driver.Version != version {
+		t.Fatalf("unexpected driver: %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 5 {
+		t.Fatalf("expected one result per violation, got %d", len(run.Results))
+	}
+	for _, result := range run.Results {
+		if result.RuleIndex >= len(run.Tool.Driver.Rules) || run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+			t.Fatalf("result %s does not point at its rule descriptor", result.RuleID)
+		}
+		if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.URI; strings.HasPrefix(uri, "/") {
+			t.Fatalf("expected a path relative to the analyzed directory, got %s", uri)
+		}
+	}
+
+	if run.Results[0].Level != "error" || run.Results[0].Message.Text != "Circular dependency: a/a.go → b/b.go → a/a.go" {
+		t.Fatalf("unexpected cycle result: %+v", run.Results[0])
+	}
+	function := run.Results[3]
+	if function.Level != "warning" || function.Locations[0].PhysicalLocation.Region == nil || function.Locations[0].PhysicalLocation.Region.StartLine != 12 {
+		t.Fatalf("expected the function result to carry its line, got %+v", function)
+	}
+	if run.Results[2].Locations[0].PhysicalLocation.Region != nil {
+		t.Fatal("expected a file size result without a region")
+	}
+	if size := run.Tool.Driver.Rules[run.Results[2].RuleIndex]; size.ShortDescription.Text != "Reports long files" {
+		t.Fatalf("expected the size rule descriptor from the report, got %+v", size)
+	}
+}
+
+func TestComposeAnalyzeRequest_RejectsUnknownFormat(t *testing.T) {
+	if _, err := parseAnalyzeFlags([]string{"-format", "sarif", "."}); err != nil {
+		t.Fatalf("expected sarif to be accepted, got %v", err)
+	}
+	_, err := parseAnalyzeFlags([]string{"-format", "xml", "."})
+	if err == nil || !strings.Contains(err.Error(), "Invalid format: xml") {
+		t.Fatalf("expected an invalid format error, got %v", err)
+	}
+}
+
+func TestFormatSARIF_DescribesCoreRulesAndFullCycles(t *testing.T) {
+	violations := []model.Violation{{
+		RuleID:   "rule.circular-dependency",
+		Severity: model.SeverityCritical,
+		Message:  "Cycle with root package /repo/main.go: /repo/main.go → /repo/wiring/w.go → /repo/app/a.go → /repo/main.go",
+		File:     "/repo/main.go",
+	}}
+	report := buildReportFromRuleViolations("/repo", version, (&ConfigLoader{}).getDefaultConfig(), violations)
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(NewReporter(FormatSARIF).Format(report)), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	run := log.Runs[0]
+	ids := make(map[string]bool)
+	for _, rule := range run.Tool.Driver.Rules {
+		if rule.ShortDescription.Text == "" || rule.DefaultConfiguration.Level == "" {
+			t.Errorf("incomplete descriptor: %+v", rule)
+		}
+		ids[rule.ID] = true
+	}
+	for _, id := range sarifCoreRules {
+		if !ids[id] {
+			t.Errorf("expected a descriptor for %s", id)
+		}
+	}
+
+	if len(run.Results) != 1 {
+		t.Fatalf("expected one result, got %d", len(run.Results))
+	}
+	if want := "Circular dependency: main.go → wiring/w.go → app/a.go → main.go"; run.Results[0].Message.Text != want {
+		t.Fatalf("expected the full cycle %q, got %q", want, run.Results[0].Message.Text)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScoreExplanation shows how a weighted score was computed: the weight each
+// violation category was scored with and the penalty it cost. The numbers
+// come from the weights that computed the score, so they follow config
+// weights and penalty curves.
+type ScoreExplanation struct {
+	BaseScore        float64           `json:"baseScore"`
+	Circular         ExplainedPenalty  `json:"circular"`
+	Layer            ExplainedPenalty  `json:"layer"`
+	FeatureIsolation *ExplainedPenalty `json:"featureIsolation,omitempty"`
+	DependencyCap    *ExplainedPenalty `json:"dependencyCap,omitempty"`
+	Size             ExplainedPenalty  `json:"size"`
+	GodObject        ExplainedPenalty  `json:"godObject"`
+	TotalPenalty     float64           `json:"totalPenalty"`
+	FinalScore       float64           `json:"finalScore"`
+}
+
+// ExplainedPenalty is the penalty of one violation category. Curve is set
+// when a penalty curve replaced the flat weight x count penalty.
+type ExplainedPenalty struct {
+	Count   int     `json:"count"`
+	Weight  float64 `json:"weight"`
+	Curve   string  `json:"curve,omitempty"`
+	Penalty float64 `json:"penalty"`
+}
+
+// newScoreExplanation explains a weighted score, or returns nil for a score
+// of another model. A score that records no weights was computed with the
+// default ones.
+func newScoreExplanation(score *StructuralScore) *ScoreExplanation {
+	if score == nil || !isWeightedScore(score) {
+		return nil
+	}
+	weights := score.Weights
+	if weights == nil {
+		weights = DefaultScoringWeights()
+	}
+	explanation := &ScoreExplanation{
+		BaseScore:    scoreScale(score),
+		Circular:     explainPenalty(score.CircularCount, weights.CircularDependencyPenalty, weights.CircularCurve, score.CircularPenalty),
+		Layer:        explainPenalty(score.LayerCount, weights.LayerViolationPenalty, weights.LayerCurve, score.LayerPenalty),
+		Size:         explainPenalty(score.SizeCount, weights.SizeViolationPenalty, weights.SizeCurve, score.SizePenalty),
+		GodObject:    explainPenalty(score.GodObjectCount, weights.GodObjectPenalty, weights.GodObjectCurve, score.GodObjectPenalty),
+		TotalPenalty: score.CircularPenalty + score.LayerPenalty + score.OptIn.FeatureIsolationPenalty + score.OptIn.DependencyCapPenalty + score.SizePenalty + score.GodObjectPenalty,
+		FinalScore:   score.TotalScore,
+	}
+	// Opt-in rule categories are only explained once they find something
+	if score.OptIn.FeatureIsolationCount > 0 {
+		featureIsolation := explainPenalty(score.OptIn.FeatureIsolationCount, weights.FeatureIsolationPenalty, nil, score.OptIn.FeatureIsolationPenalty)
+		explanation.FeatureIsolation = &featureIsolation
+	}
+	if score.OptIn.DependencyCapCount > 0 {
+		dependencyCap := explainPenalty(score.OptIn.DependencyCapCount, weights.DependencyCapPenalty, nil, score.OptIn.DependencyCapPenalty)
+		explanation.DependencyCap = &dependencyCap
+	}
+	return explanation
+}
+
+func explainPenalty(count int, weight float64, curve *PenaltyExpr, penalty float64) ExplainedPenalty {
+	explained := ExplainedPenalty{Count: count, Weight: weight, Penalty: penalty}
+	if curve != nil {
+		explained.Curve = curve.String()
+	}
+	return explained
+}
+
+// basis describes how the penalty follows from the violation count, as in
+// "3 violations x 5.0"
+func (p ExplainedPenalty) basis() string {
+	if p.Curve != "" {
+		return fmt.Sprintf("%d violations, curve %s", p.Count, p.Curve)
+	}
+	return fmt.Sprintf("%d violations x %.1f", p.Count, p.Weight)
+}
+
+// formatScoreExplanation renders the explanation one category per line,
+// ending with the total penalty and the final score
+func formatScoreExplanation(e *ScoreExplanation) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Base Score: %.1f\n", e.BaseScore))
+	type explainedCategory struct {
+		name    string
+		penalty ExplainedPenalty
+	}
+	categories := []explainedCategory{
+		{"Circular Dependencies", e.Circular},
+		{"Layer Violations", e.Layer},
+	}
+	if e.FeatureIsolation != nil {
+		categories = append(categories, explainedCategory{"Feature Isolation Violations", *e.FeatureIsolation})
+	}
+	if e.DependencyCap != nil {
+		categories = append(categories, explainedCategory{"Dependency Cap Violations", *e.DependencyCap})
+	}
+	categories = append(categories, explainedCategory{"Size Violations", e.Size}, explainedCategory{"God Objects", e.GodObject})
+	for _, category := range categories {
+		p := category.penalty
+		if p.Curve != "" {
+			sb.WriteString(fmt.Sprintf("%s: %d violation(s) on curve %s = %.1f\n", category.name, p.Count, p.Curve, p.Penalty))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s: %d violation(s) x %.1f penalty = %.1f\n", category.name, p.Count, p.Weight, p.Penalty))
+	}
+	sb.WriteString(fmt.Sprintf("Total Penalty: %.1f\n", e.TotalPenalty))
+	sb.WriteString(fmt.Sprintf("Final Score: %.1f / %.1f\n", e.FinalScore, e.BaseScore))
+	return sb.String()
+}
+
+// writeScoreExplanation writes the -explain section of the text report
+func writeScoreExplanation(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	explanation := newScoreExplanation(report.Score)
+	if explanation == nil {
+		return
+	}
+
+	writeSectionBox(sb, layout, "SCORE EXPLANATION")
+	sb.WriteString(formatScoreExplanation(explanation) + "\n")
+}
+
+// writeScoreExplanationWithColor writes the -explain section with colors
+func writeScoreExplanationWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	explanation := newScoreExplanation(report.Score)
+	if explanation == nil {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "SCORE EXPLANATION", ColorCyan)
+	sb.WriteString(formatScoreExplanation(explanation) + "\n")
+}
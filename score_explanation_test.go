@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// explainFixture analyzes a directory with one size violation under config
+// and returns the report
+func explainFixture(t *testing.T, config string) *StructuralReport {
+	t.Helper()
+	files := map[string]string{"big.go": "package big\n" + strings.Repeat("// filler\n", 600)}
+	if config != "" {
+		files[".repodoctor/config.yaml"] = config
+	}
+	report, _ := analyzeFixture(t, files, AnalyzeOptions{NoLargest: true})
+	return report
+}
+
+func TestScoreExplanation_FollowsConfiguredWeight(t *testing.T) {
+	for _, tc := range []struct {
+		config string
+		score  float64
+		weight string
+	}{
+		{config: "", score: 97, weight: "3.0"},
+		{config: "weights:\n  size: 7\n", score: 93, weight: "7.0"},
+	} {
+		report := explainFixture(t, tc.config)
+		if report.Score.TotalScore != tc.score {
+			t.Fatalf("config %q: expected score %.1f, got %.1f", tc.config, tc.score, report.Score.TotalScore)
+		}
+
+		text := newRequestReporter(FormatText, AnalyzeRequest{Report: ReportOptions{Explain: true}}).FormatColoredText(report)
+		for _, want := range []string{
+			"Size Penalty:         -" + tc.weight + " (1 violations x " + tc.weight + ")",
+			"SCORE EXPLANATION",
+			"Size Violations: 1 violation(s) x " + tc.weight + " penalty = " + tc.weight,
+		} {
+			if !strings.Contains(text, want) {
+				t.Fatalf("config %q: expected text output to contain %q, got:\n%s", tc.config, want, text)
+			}
+		}
+	}
+}
+
+func TestScoreExplanation_JSONObject(t *testing.T) {
+	report := explainFixture(t, "weights:\n  size: 7\n")
+
+	for _, format := range []OutputFormat{FormatJSON, FormatJSONV1} {
+		var payload struct {
+			Explanation *ScoreExplanation `json:"explanation"`
+		}
+		out := newRequestReporter(format, AnalyzeRequest{Report: ReportOptions{Explain: true}}).Format(report)
+		if err := json.Unmarshal([]byte(out), &payload); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", format, err)
+		}
+		e := payload.Explanation
+		if e == nil {
+			t.Fatalf("%s: expected an explanation object, got:\n%s", format, out)
+		}
+		if e.Size != (ExplainedPenalty{Count: 1, Weight: 7, Penalty: 7}) || e.Circular.Weight != 10 || e.BaseScore != 100 || e.TotalPenalty != 7 || e.FinalScore != 93 {
+			t.Fatalf("%s: unexpected explanation %+v", format, e)
+		}
+
+		if out := newRequestReporter(format, AnalyzeRequest{}).Format(report); strings.Contains(out, `"explanation"`) {
+			t.Fatalf("%s: expected no explanation without -explain, got:\n%s", format, out)
+		}
+	}
+}
+
+func TestScoreExplanation_ShowsPenaltyCurve(t *testing.T) {
+	report := explainFixture(t, "penalties:\n  size: \"count * 4\"\n")
+	explanation := newScoreExplanation(report.Score)
+	if explanation == nil || explanation.Size.Curve != "count * 4" || explanation.Size.Penalty != 4 {
+		t.Fatalf("expected the size curve and its penalty, got %+v", explanation)
+	}
+
+	text := newRequestReporter(FormatText, AnalyzeRequest{Report: ReportOptions{Explain: true}}).FormatColoredText(report)
+	for _, want := range []string{"Size Penalty:         -4.0 (1 violations, curve count * 4)", "Size Violations: 1 violation(s) on curve count * 4 = 4.0"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected text output to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestScoreExplanation_OnlyWithExplain(t *testing.T) {
+	report := explainFixture(t, "")
+	if text := newRequestReporter(FormatText, AnalyzeRequest{}).FormatColoredText(report); strings.Contains(text, "SCORE EXPLANATION") {
+		t.Fatalf("expected no explanation section without -explain, got:\n%s", text)
+	}
+
+	rubric := explainFixture(t, "scoring:\n  model: category-rubric\n")
+	if text := newRequestReporter(FormatText, AnalyzeRequest{Report: ReportOptions{Explain: true}}).FormatColoredText(rubric); strings.Contains(text, "SCORE EXPLANATION") {
+		t.Fatalf("expected no weighted explanation for the rubric model, got:\n%s", text)
+	}
+}
+
+func TestStructuralScorer_GetScoreExplanationUsesWeights(t *testing.T) {
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	cfg.Weights.Layer = 8
+	scorer := NewStructuralScorer(NewDependencyGraph(), cfg, "")
+	scorer.CalculateScore()
+
+	if got := scorer.GetScoreExplanation(); !strings.Contains(got, "Layer Violations: 0 violation(s) x 8.0 penalty = 0.0") {
+		t.Fatalf("expected the configured layer weight, got:\n%s", got)
+	}
+}
+
+func TestComposeAnalyzeRequest_Explain(t *testing.T) {
+	req, err := parseAnalyzeFlags([]string{"-explain", "."})
+	if err != nil || !req.Report.Explain {
+		t.Fatalf("expected -explain to reach the service request, got %+v, %v", req, err)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Score model names accepted by scoring.model
+const (
+	ScoreModelWeighted       = "weighted"
+	ScoreModelCategoryRubric = "category-rubric"
+)
+
+// scoreModelNames lists the built-in score models, default first
+var scoreModelNames = []string{ScoreModelWeighted, ScoreModelCategoryRubric}
+
+// ScoreModel turns the findings of a report into a score. The weighted
+// penalty model is the default; scoring.model selects another built-in one.
+type ScoreModel interface {
+	Name() string
+	Compute(findings ScoreFindings) ScoreResult
+}
+
+// ScoreFindings counts the findings of a report per category
+type ScoreFindings struct {
+	Circular int
+	Layer    int
+	// FeatureIsolation and DependencyCap count the findings of opt-in rules
+	// that are scored on their own
+	FeatureIsolation int
+	DependencyCap    int
+	Size             int
+	GodObject        int
+	Advisory         int
+	SingleImpl       int
+}
+
+// ScoreComponent is one line of a score breakdown. Points are subtracted
+// from the maximum for penalties and awarded for rubric categories; Max is
+// set when the component is scored on its own scale.
+type ScoreComponent struct {
+	Name     string  `json:"name"`
+	Findings int     `json:"findings"`
+	Points   float64 `json:"points"`
+	Max      float64 `json:"max,omitempty"`
+}
+
+// ScoreResult is the score a model computed, with its breakdown
+type ScoreResult struct {
+	Model     string
+	Total     float64
+	Max       float64
+	Breakdown []ScoreComponent
+}
+
+// scoreModelFromConfig returns the model scoring.model selects
+func scoreModelFromConfig(cfg *Config) ScoreModel {
+	if cfg != nil && cfg.Scoring != nil && cfg.Scoring.Model == ScoreModelCategoryRubric {
+		return categoryRubricModel{}
+	}
+	return weightedPenaltyModel{weights: scoringWeightsFromConfig(cfg)}
+}
+
+// scoreFindingsOf counts the findings of a report
+func scoreFindingsOf(report *StructuralReport) ScoreFindings {
+	return ScoreFindings{
+		Circular:         len(report.Circular),
+		Layer:            len(report.Layer),
+		FeatureIsolation: len(report.OptIn.FeatureIsolation),
+		DependencyCap:    len(report.OptIn.DependencyCap),
+		Size:             len(report.Size),
+		GodObject:        len(report.GodObject),
+		Advisory:         len(report.Advisory),
+		SingleImpl:       len(report.OptIn.SingleImpl),
+	}
+}
+
+// weightedPenaltyModel subtracts a weighted penalty per violation category
+// from 100
+type weightedPenaltyModel struct {
+	weights *ScoringWeights
+}
+
+func (m weightedPenaltyModel) Name() string {
+	return ScoreModelWeighted
+}
+
+func (m weightedPenaltyModel) Compute(findings ScoreFindings) ScoreResult {
+	w := m.weights
+	breakdown := []ScoreComponent{
+		{Name: "circular", Findings: findings.Circular, Points: categoryPenalty(w.CircularDependencyPenalty, w.CircularCurve, findings.Circular)},
+		{Name: "layer", Findings: findings.Layer, Points: categoryPenalty(w.LayerViolationPenalty, w.LayerCurve, findings.Layer)},
+		{Name: "size", Findings: findings.Size, Points: categoryPenalty(w.SizeViolationPenalty, w.SizeCurve, findings.Size)},
+		{Name: "godObject", Findings: findings.GodObject, Points: categoryPenalty(w.GodObjectPenalty, w.GodObjectCurve, findings.GodObject)},
+		{Name: "featureIsolation", Findings: findings.FeatureIsolation, Points: categoryPenalty(w.FeatureIsolationPenalty, nil, findings.FeatureIsolation)},
+		{Name: "dependencyCap", Findings: findings.DependencyCap, Points: categoryPenalty(w.DependencyCapPenalty, nil, findings.DependencyCap)},
+	}
+	total := 100.0
+	for _, component := range breakdown {
+		total -= component.Points
+	}
+	return ScoreResult{Model: ScoreModelWeighted, Total: math.Max(0, total), Max: 100, Breakdown: breakdown}
+}
+
+// rubricStars is the top of each category rubric
+const rubricStars = 5.0
+
+// categoryRubricModel scores structure (cycles, layer, feature isolation and
+// dependency cap violations), size (size violations, god objects) and
+// hygiene (advisories, single-implementation interfaces) from 0 to 5 stars
+// each. Every finding costs its category a
+// fixed share of a star; the total is the mean of the three categories.
+type categoryRubricModel struct{}
+
+func (m categoryRubricModel) Name() string {
+	return ScoreModelCategoryRubric
+}
+
+func (m categoryRubricModel) Compute(findings ScoreFindings) ScoreResult {
+	stars := func(cost float64, count int) float64 {
+		return math.Max(0, rubricStars-cost*float64(count))
+	}
+	structure := findings.Circular + findings.Layer + findings.FeatureIsolation + findings.DependencyCap
+	size := findings.Size + findings.GodObject
+	hygiene := findings.Advisory + findings.SingleImpl
+	breakdown := []ScoreComponent{
+		{Name: "structure", Findings: structure, Points: stars(1, structure), Max: rubricStars},
+		{Name: "size", Findings: size, Points: stars(0.5, size), Max: rubricStars},
+		{Name: "hygiene", Findings: hygiene, Points: stars(0.25, hygiene), Max: rubricStars},
+	}
+	total := 0.0
+	for _, component := range breakdown {
+		total += component.Points
+	}
+	return ScoreResult{Model: ScoreModelCategoryRubric, Total: total / float64(len(breakdown)), Max: rubricStars, Breakdown: breakdown}
+}
+
+// isWeightedScore reports whether a score was computed by the weighted
+// model; scores that record no model predate score models and are weighted
+func isWeightedScore(score *StructuralScore) bool {
+	return score == nil || score.Model == "" || score.Model == ScoreModelWeighted
+}
+
+// scoreScale returns the maximum text output shows a score out of. Weighted
+// scores are always out of 100.
+func scoreScale(score *StructuralScore) float64 {
+	if isWeightedScore(score) || score.MaxScore <= 0 {
+		return 100
+	}
+	return score.MaxScore
+}
+
+// formatRubricBreakdown renders the breakdown of a non-weighted score, one
+// category per line
+func formatRubricBreakdown(score *StructuralScore) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Model:                %s\n", score.Model))
+	for _, component := range score.Breakdown {
+		label := strings.ToUpper(component.Name[:1]) + component.Name[1:] + ":"
+		sb.WriteString(fmt.Sprintf("%-22s%.1f / %.1f (%d findings)\n", label, component.Points, component.Max, component.Findings))
+	}
+	return sb.String()
+}
+
+func validateScoringConfig(scoring *ScoringConfig) error {
+	if scoring == nil {
+		return nil
+	}
+	if err := validateDensityWeights(scoring.DensityWeights); err != nil {
+		return err
+	}
+	if scoring.Model == "" {
+		return nil
+	}
+	for _, name := range scoreModelNames {
+		if scoring.Model == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("scoring.model must be one of %s, got: %q", strings.Join(scoreModelNames, ", "), scoring.Model)
+}
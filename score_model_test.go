@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func scoreModelFixture() *StructuralReport {
+	return &StructuralReport{
+		Circular:  []CycleViolation{{Path: []string{"a.go", "b.go"}}},
+		Layer:     []LayerViolation{{From: "repo/x.go", To: "handler/y.go"}},
+		Size:      []SizeViolation{{File: "big.go", Lines: 600, Threshold: 500}, {File: "big.go", Function: "Run", Lines: 90, Threshold: 80}},
+		GodObject: []GodObjectViolation{{StructName: "Manager", File: "m.go"}},
+		Advisory:  []AdvisoryViolation{{RuleID: "rule.struct-cohesion", File: "m.go"}},
+		OptIn:     OptInViolations{SingleImpl: []SingleImplInterfaceViolation{{Interface: "Store", Impl: "sqlStore"}}},
+	}
+}
+
+func TestWeightedPenaltyModel_IsTheDefault(t *testing.T) {
+	report := scoreModelFixture()
+	report.Score = calculateScoreFromViolations(nil, report)
+	score := report.Score
+	if score.Model != ScoreModelWeighted || score.MaxScore != 100 || len(score.Breakdown) != 0 {
+		t.Fatalf("expected the weighted model out of 100 without a rubric breakdown, got %+v", score)
+	}
+	// 100 - 10 (cycle) - 5 (layer) - 2x3 (size) - 5 (god object)
+	if score.TotalScore != 74 || score.CircularPenalty != 10 || score.SizePenalty != 6 {
+		t.Fatalf("unexpected weighted score %+v", score)
+	}
+
+	report.Summary = ReportSummary{TotalViolations: 5, Circular: 1, Layer: 1, Size: 2, GodObject: 1}
+	report.HasViolations = true
+	if err := verifyReportInvariants(report, DefaultScoringWeights()); err != nil {
+		t.Fatalf("weighted score must pass the self-check: %v", err)
+	}
+}
+
+func TestCategoryRubricModel_ScoresEachCategoryOutOfFive(t *testing.T) {
+	cfg := &Config{Scoring: &ScoringConfig{Model: ScoreModelCategoryRubric}}
+	score := calculateScoreFromViolations(cfg, scoreModelFixture())
+
+	if score.Model != ScoreModelCategoryRubric || score.MaxScore != 5 {
+		t.Fatalf("expected the rubric model out of 5, got %+v", score)
+	}
+	want := map[string][2]float64{"structure": {2, 3}, "size": {3, 3.5}, "hygiene": {2, 4.5}}
+	for _, component := range score.Breakdown {
+		w := want[component.Name]
+		if float64(component.Findings) != w[0] || component.Points != w[1] || component.Max != 5 {
+			t.Errorf("%s: expected %v findings and %v stars, got %+v", component.Name, w[0], w[1], component)
+		}
+	}
+	if len(score.Breakdown) != 3 || score.TotalScore != 11.0/3 {
+		t.Fatalf("expected the mean of three categories, got %v from %+v", score.TotalScore, score.Breakdown)
+	}
+	if score.CircularPenalty != 0 || score.SizePenalty != 0 {
+		t.Fatalf("penalties belong to the weighted model, got %+v", score)
+	}
+
+	clean := calculateScoreFromViolations(cfg, &StructuralReport{})
+	if clean.TotalScore != 5 {
+		t.Fatalf("expected 5 stars without findings, got %v", clean.TotalScore)
+	}
+}
+
+func TestReporter_RendersRubricBreakdown(t *testing.T) {
+	report := scoreModelFixture()
+	report.Version, report.Path, report.HasViolations = "0.5.0-dev", "demo", true
+	report.Score = calculateScoreFromViolations(&Config{Scoring: &ScoringConfig{Model: ScoreModelCategoryRubric}}, report)
+
+	reporter := NewReporter(FormatText)
+	reporter.width = 100
+	text := reporter.Format(report)
+	for _, want := range []string{"Score: 3.7 / 5.0", "Model:                category-rubric", "Structure:            3.0 / 5.0 (2 findings)", "Final Score:          3.7 / 5.0"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in text report:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Circular Penalty") {
+		t.Errorf("rubric reports must not show weighted penalties:\n%s", text)
+	}
+
+	var payload struct {
+		Score struct {
+			Model     string           `json:"model"`
+			Max       float64          `json:"max"`
+			Breakdown []ScoreComponent `json:"breakdown"`
+		} `json:"score"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSON).Format(report)), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if payload.Score.Model != ScoreModelCategoryRubric || payload.Score.Max != 5 || len(payload.Score.Breakdown) != 3 {
+		t.Fatalf("expected the rubric model and breakdown in JSON, got %+v", payload.Score)
+	}
+}
+
+func TestConfigLoader_RejectsUnknownScoreModel(t *testing.T) {
+	if err := validateScoringConfig(&ScoringConfig{Model: "stars"}); err == nil {
+		t.Fatal("expected an unknown score model to be rejected")
+	}
+	for _, name := range scoreModelNames {
+		if err := validateScoringConfig(&ScoringConfig{Model: name}); err != nil {
+			t.Fatalf("expected %s to be accepted: %v", name, err)
+		}
+	}
+}
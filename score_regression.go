@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultRegressionWindow is how many previous runs the regression check
+// averages unless history.regression_window says otherwise
+const defaultRegressionWindow = 5
+
+// defaultRegressionThreshold is how many points below that average a score
+// must fall to be a regression unless history.regression_threshold says
+// otherwise
+const defaultRegressionThreshold = 5.0
+
+// ScoreTrend compares the run's score with the recorded history
+type ScoreTrend struct {
+	// Scores is the score history the -format html sparkline draws
+	Scores []float64
+	// Regression is set when the score fell below the average of the
+	// previous runs
+	Regression *ScoreRegression
+}
+
+// ScoreRegression is a detected regression: a score more than Threshold
+// below the moving average of the Window previous runs, which are fewer
+// than the configured window while the history is short
+type ScoreRegression struct {
+	Score     float64 `json:"score"`
+	Average   float64 `json:"average"`
+	Window    int     `json:"window"`
+	Threshold float64 `json:"threshold"`
+}
+
+// detectScoreRegression checks the report's score against the history of
+// absPath, before the run is recorded, and returns the regression it
+// detects or nil. Only a detected regression is reported, so output does
+// not change with the history otherwise. Sampled runs and unreadable
+// histories are not checked.
+func detectScoreRegression(absPath string, report *StructuralReport, cfg *Config) *ScoreRegression {
+	if report.Score == nil || report.Metrics.Sample != nil {
+		return nil
+	}
+	analyzer := NewTrendAnalyzer(absPath)
+	if err := analyzer.LoadHistory(); err != nil {
+		return nil
+	}
+	analyzer.scoreModel = report.Score.Model
+	analyzer.regressionWindow = historyRegressionWindow(cfg)
+	previous := len(modelScores(analyzer.history, analyzer.scoreModel))
+	if previous == 0 {
+		return nil
+	}
+
+	regression := &ScoreRegression{
+		Score:     report.Score.TotalScore,
+		Average:   analyzer.MovingAverage(analyzer.regressionWindow),
+		Window:    min(analyzer.regressionWindow, previous),
+		Threshold: historyRegressionThreshold(cfg),
+	}
+	// The check compares the newest entry, so the run is added in memory
+	analyzer.history = append(analyzer.history, HistoryEntry{Score: report.Score.TotalScore, ScoreModel: report.Score.Model})
+	if !analyzer.DetectRegression(regression.Threshold) {
+		return nil
+	}
+	return regression
+}
+
+// regressionDetected reports whether the report's score regressed
+func regressionDetected(report *StructuralReport) bool {
+	return report.Metrics.Trend.Regression != nil
+}
+
+// formatScoreRegression describes a detected regression
+func formatScoreRegression(regression *ScoreRegression) string {
+	return fmt.Sprintf("Score %.1f is %.1f below the average %.1f of the last %d runs (threshold %.1f)",
+		regression.Score, regression.Average-regression.Score, regression.Average, regression.Window, regression.Threshold)
+}
+
+// writeScoreRegression writes the REGRESSION DETECTED warning of the text
+// report when the score regressed
+func writeScoreRegression(sb *strings.Builder, report *StructuralReport, layout *textLayout) {
+	if !regressionDetected(report) {
+		return
+	}
+
+	writeSectionBox(sb, layout, "REGRESSION DETECTED")
+	sb.WriteString(formatScoreRegression(report.Metrics.Trend.Regression) + "\n\n")
+}
+
+// writeScoreRegressionWithColor writes the REGRESSION DETECTED warning with
+// colors
+func writeScoreRegressionWithColor(sb *strings.Builder, report *StructuralReport, formatter *ColorFormatter, layout *textLayout) {
+	if !regressionDetected(report) {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, "REGRESSION DETECTED", ColorRed)
+	sb.WriteString(formatter.Warn(formatScoreRegression(report.Metrics.Trend.Regression)) + "\n\n")
+}
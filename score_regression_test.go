@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// steadyHistory holds five weighted runs scoring around 80
+var steadyHistory = []HistoryEntry{{Score: 80}, {Score: 82}, {Score: 81}, {Score: 79}, {Score: 80}}
+
+func TestTrendAnalyzer_MovingAverage(t *testing.T) {
+	analyzer := NewTrendAnalyzer(t.TempDir())
+	if got := analyzer.MovingAverage(5); got != 0 {
+		t.Fatalf("expected 0 without history, got %.2f", got)
+	}
+
+	analyzer.history = append([]HistoryEntry{{Score: 10}, {Score: 4, ScoreModel: ScoreModelCategoryRubric}}, steadyHistory...)
+	if got := analyzer.MovingAverage(5); got != 80.4 {
+		t.Fatalf("expected the average of the last 5 runs, got %.2f", got)
+	}
+	if got := analyzer.MovingAverage(2); got != 79.5 {
+		t.Fatalf("expected the average of the last 2 runs, got %.2f", got)
+	}
+	// The rubric entry is of another model and is skipped
+	if got := analyzer.MovingAverage(7); got != 412.0/6 {
+		t.Fatalf("expected the average of every weighted run, got %v", got)
+	}
+}
+
+func TestTrendAnalyzer_DetectRegressionOnSuddenDrop(t *testing.T) {
+	analyzer := NewTrendAnalyzer(t.TempDir())
+	analyzer.history = append(steadyHistory, HistoryEntry{Score: 79})
+	if analyzer.DetectRegression(5) {
+		t.Fatal("expected no regression for a score within the threshold")
+	}
+
+	analyzer.history = append(steadyHistory, HistoryEntry{Score: 60})
+	if !analyzer.DetectRegression(5) {
+		t.Fatal("expected a regression for a drop of 20.4 below the average")
+	}
+	if analyzer.DetectRegression(25) {
+		t.Fatal("expected no regression below a threshold of 25")
+	}
+
+	analyzer.history = []HistoryEntry{{Score: 60}}
+	if analyzer.DetectRegression(5) {
+		t.Fatal("expected no regression without previous runs")
+	}
+}
+
+// regressionFixture analyzes a directory with one size violation, scoring
+// 97, after five recorded runs that scored 100
+func regressionFixture(t *testing.T, config string, exit ExitPolicy) (*StructuralReport, int) {
+	t.Helper()
+	entries := make([]string, 5)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`{"timestamp": "2026-01-0%dT00:00:00Z", "score": 100}`, i+1)
+	}
+	return analyzeFixture(t, map[string]string{
+		"big.go":                   "package big\n" + strings.Repeat("// filler\n", 600),
+		".repodoctor/config.yaml":  config,
+		".repodoctor/history.json": "[" + strings.Join(entries, ",") + "]",
+	}, AnalyzeOptions{NoLargest: true, Exit: exit})
+}
+
+func TestAnalyze_ReportsScoreRegression(t *testing.T) {
+	report, code := regressionFixture(t, "history:\n  regression_threshold: 2\n", ExitPolicy{})
+	want := ScoreRegression{Score: 97, Average: 100, Window: 5, Threshold: 2}
+	if code != exitCodeClean || report.Metrics.Trend.Regression == nil || *report.Metrics.Trend.Regression != want {
+		t.Fatalf("expected regression %+v with exit code 0, got %+v and %d", want, report.Metrics.Trend.Regression, code)
+	}
+
+	text := newRequestReporter(FormatText, AnalyzeRequest{}).FormatColoredText(report)
+	if !strings.Contains(text, "REGRESSION DETECTED") || !strings.Contains(text, "Score 97.0 is 3.0 below the average 100.0 of the last 5 runs (threshold 2.0)") {
+		t.Fatalf("expected the regression warning in the text report, got:\n%s", text)
+	}
+	var payload struct {
+		Metrics struct {
+			Regression *ScoreRegression `json:"regression"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(newRequestReporter(FormatJSON, AnalyzeRequest{}).Format(report)), &payload); err != nil || payload.Metrics.Regression == nil || *payload.Metrics.Regression != want {
+		t.Fatalf("expected the regression under metrics.regression, got %+v, %v", payload.Metrics.Regression, err)
+	}
+
+	if _, code := regressionFixture(t, "history:\n  regression_threshold: 2\n", ExitPolicy{FailOnRegression: true}); code != exitCodeScoreRegression {
+		t.Fatalf("expected -fail-on-regression to exit with %d, got %d", exitCodeScoreRegression, code)
+	}
+}
+
+func TestAnalyze_NoRegressionWithinThreshold(t *testing.T) {
+	report, code := regressionFixture(t, "", ExitPolicy{FailOnRegression: true})
+	if code != exitCodeClean || report.Metrics.Trend.Regression != nil {
+		t.Fatalf("expected a drop of 3 to pass the default threshold, got %+v and %d", report.Metrics.Trend.Regression, code)
+	}
+	if text := newRequestReporter(FormatText, AnalyzeRequest{}).FormatColoredText(report); strings.Contains(text, "REGRESSION DETECTED") {
+		t.Fatalf("expected no regression warning, got:\n%s", text)
+	}
+}
@@ -1,7 +1,5 @@
 package main
 
-import "fmt"
-
 // StructuralScore represents the overall structural health score
 type StructuralScore struct {
 	TotalScore       float64
@@ -15,14 +13,58 @@ type StructuralScore struct {
 	SizeCount        int
 	GodObjectCount   int
 	MaxScore         float64
+	// OptIn scores the categories of opt-in rules, which are weighted on
+	// their own
+	OptIn OptInScore
+	// Model names the score model that computed the score
+	Model string
+	// Breakdown is the per-category result of a model other than the
+	// weighted one, whose breakdown is the penalties above
+	Breakdown []ScoreComponent
+	// Weights are the weights the weighted model scored with; nil for other
+	// models
+	Weights *ScoringWeights `json:"-"`
+}
+
+// OptInScore holds the penalties and counts of the opt-in rule categories
+type OptInScore struct {
+	FeatureIsolationPenalty float64
+	FeatureIsolationCount   int
+	DependencyCapPenalty    float64
+	DependencyCapCount      int
 }
 
-// ScoringWeights defines penalty weights for different violation types
+// ScoringWeights defines penalty weights for different violation types.
+// A non-nil curve replaces the flat weight x count penalty of its category.
 type ScoringWeights struct {
 	CircularDependencyPenalty float64
 	LayerViolationPenalty     float64
 	SizeViolationPenalty      float64
 	GodObjectPenalty          float64
+	FeatureIsolationPenalty   float64
+	DependencyCapPenalty      float64
+
+	CircularCurve  *PenaltyExpr
+	LayerCurve     *PenaltyExpr
+	SizeCurve      *PenaltyExpr
+	GodObjectCurve *PenaltyExpr
+}
+
+// categoryPenalty returns the penalty for count violations of one category.
+// Curves are clamped at zero; a curve that cannot be evaluated (for example
+// dividing by a zero count) falls back to the flat weight.
+func categoryPenalty(weight float64, curve *PenaltyExpr, count int) float64 {
+	if curve == nil {
+		return float64(count) * weight
+	}
+	penalty, err := curve.Eval(count)
+	if err != nil {
+		return float64(count) * weight
+	}
+	if penalty < 0 {
+		return 0
+	}
+	return penalty
 }
 
 // DefaultScoringWeights returns the default scoring weights
@@ -32,6 +74,8 @@ func DefaultScoringWeights() *ScoringWeights {
 		LayerViolationPenalty:     5.0,  // Medium penalty for layer violations
 		SizeViolationPenalty:      3.0,  // Low penalty for size violations
 		GodObjectPenalty:          5.0,  // Medium penalty for god objects
+		FeatureIsolationPenalty:   5.0,  // Medium penalty, like layer violations
+		DependencyCapPenalty:      5.0,  // Medium penalty for exceeding the dependency cap
 	}
 }
 
@@ -43,6 +87,8 @@ type StructuralScorer struct {
 	sizeRule      *SizeRule
 	godObjectRule *GodObjectRule
 	score         *StructuralScore
+	// config decides which rules run, through their enable flags
+	config *Config
 }
 
 // NewStructuralScorer creates a new structural scorer with configuration
@@ -51,9 +97,13 @@ func NewStructuralScorer(graph Graph, config *Config, dirPath string) *Structura
 		config = (&ConfigLoader{}).getDefaultConfig()
 	}
 
-	// Create rules with config thresholds
+	// Create rules with config thresholds, sharing one parse of each file
+	sources := newASTCache()
 	sizeRule := NewSizeRule()
+	sizeRule.sources = sources
 	godObjectRule := NewGodObjectRule()
+	godObjectRule.sources = sources
+	sizeRule.Excludes, godObjectRule.Excludes = config.Exclude, config.Exclude
 
 	// Apply config thresholds
 	if config.Size != nil {
@@ -67,52 +117,69 @@ func NewStructuralScorer(graph Graph, config *Config, dirPath string) *Structura
 	}
 
 	scorer := &StructuralScorer{
-		weights:       DefaultScoringWeights(),
+		weights:       scoringWeightsFromConfig(config),
 		circularRule:  NewCircularDependencyRule(graph),
-		layerRule:     NewLayerValidationRule(graph),
+		layerRule:     NewLayerValidationRule(graph, config.Layers),
 		sizeRule:      sizeRule,
 		godObjectRule: godObjectRule,
 		score: &StructuralScore{
 			MaxScore: 100.0,
 		},
+		config: config,
 	}
 
-	// Run rule checks if directory path provided
-	if dirPath != "" {
+	// Run the enabled rule checks if directory path provided
+	if dirPath != "" && scorer.ruleEnabled("rule.size") {
 		sizeRule.Check(dirPath)
+	}
+	if dirPath != "" && scorer.ruleEnabled("rule.god-object") {
 		godObjectRule.Check(dirPath)
 	}
 
+	// Release the parsed files once both rules have checked
+	sizeRule.sources, godObjectRule.sources = nil, nil
+
 	return scorer
 }
 
+// ruleEnabled reports whether the config enables the rule with the given ID.
+// A disabled rule never checks, so it has no violations and no penalty.
+func (s *StructuralScorer) ruleEnabled(id string) bool {
+	return ruleEnabledByConfig(id, s.config)
+}
+
 // CalculateScore computes the structural health score
 func (s *StructuralScorer) CalculateScore() *StructuralScore {
 	s.score = &StructuralScore{
 		MaxScore: 100.0,
+		Weights:  s.weights,
 	}
 
 	// Check circular dependencies
-	s.circularRule.Check()
+	if s.ruleEnabled("rule.circular-dependency") {
+		s.circularRule.Check()
+	}
 	circularViolations := s.circularRule.Violations()
 	s.score.CircularCount = len(circularViolations)
-	s.score.CircularPenalty = float64(len(circularViolations)) * 10.0
+	s.score.CircularPenalty = categoryPenalty(s.weights.CircularDependencyPenalty, s.weights.CircularCurve, s.score.CircularCount)
 
 	// Check layer violations
-	s.layerRule.Check()
+	if s.ruleEnabled("rule.layer-validation") {
+		s.layerRule.Check()
+	}
 	layerViolations := s.layerRule.Violations()
 	s.score.LayerCount = len(layerViolations)
-	s.score.LayerPenalty = float64(len(layerViolations)) * 5.0
+	s.score.LayerPenalty = categoryPenalty(s.weights.LayerViolationPenalty, s.weights.LayerCurve, s.score.LayerCount)
 
 	// Check size violations
 	sizeViolations := s.sizeRule.Violations()
 	s.score.SizeCount = len(sizeViolations)
-	s.score.SizePenalty = float64(len(sizeViolations)) * s.weights.SizeViolationPenalty
+	s.score.SizePenalty = categoryPenalty(s.weights.SizeViolationPenalty, s.weights.SizeCurve, s.score.SizeCount)
 
 	// Check god object violations
 	godObjectViolations := s.godObjectRule.Violations()
 	s.score.GodObjectCount = len(godObjectViolations)
-	s.score.GodObjectPenalty = float64(len(godObjectViolations)) * s.weights.GodObjectPenalty
+	s.score.GodObjectPenalty = categoryPenalty(s.weights.GodObjectPenalty, s.weights.GodObjectCurve, s.score.GodObjectCount)
 
 	// Calculate total violations and penalty
 	s.score.ViolationCount = s.score.CircularCount + s.score.LayerCount + s.score.SizeCount + s.score.GodObjectCount
@@ -139,26 +206,14 @@ func (s *StructuralScorer) GetLayerRule() *LayerValidationRule {
 
 // HasCriticalViolations returns true if there are critical violations
 func (s *StructuralScorer) HasCriticalViolations() bool {
-	return s.circularRule.Check()
+	return s.ruleEnabled("rule.circular-dependency") && s.circularRule.Check()
 }
 
 // GetScoreExplanation returns a detailed explanation of the score calculation
 func (s *StructuralScorer) GetScoreExplanation() string {
-	explanation := "Structural Score Breakdown:\n"
-	explanation += "=========================\n"
-	explanation += fmt.Sprintf("Base Score: %.1f\n", s.score.MaxScore)
-	explanation += fmt.Sprintf("Circular Dependencies: %d violation(s) x %.1f penalty = %.1f\n",
-		s.score.CircularCount, s.weights.CircularDependencyPenalty, s.score.CircularPenalty)
-	explanation += fmt.Sprintf("Layer Violations: %d violation(s) x %.1f penalty = %.1f\n",
-		s.score.LayerCount, s.weights.LayerViolationPenalty, s.score.LayerPenalty)
-	explanation += fmt.Sprintf("Size Violations: %d violation(s) x %.1f penalty = %.1f\n",
-		s.score.SizeCount, s.weights.SizeViolationPenalty, s.score.SizePenalty)
-	explanation += fmt.Sprintf("God Objects: %d violation(s) x %.1f penalty = %.1f\n",
-		s.score.GodObjectCount, s.weights.GodObjectPenalty, s.score.GodObjectPenalty)
-	explanation += fmt.Sprintf("Total Penalty: %.1f\n", s.score.CircularPenalty+s.score.LayerPenalty+s.score.SizePenalty+s.score.GodObjectPenalty)
-	explanation += fmt.Sprintf("Final Score: %.1f / %.1f\n", s.score.TotalScore, s.score.MaxScore)
-
-	return explanation
+	return "Structural Score Breakdown:\n" +
+		"=========================\n" +
+		formatScoreExplanation(newScoreExplanation(s.score))
 }
 
 // GetAllViolations returns all violations from all rules
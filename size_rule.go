@@ -2,11 +2,11 @@ package main
 
 import (
 	"go/ast"
-	"go/parser"
-	"go/token"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+
+	"RepoDoctor/internal/model"
 )
 
 // SizeViolation represents a violation of size thresholds
@@ -15,14 +15,30 @@ type SizeViolation struct {
 	Function  string
 	Lines     int
 	Threshold int
+	// Line is where the function starts; 1 for file violations
+	Line int `json:",omitempty"`
+	// StartLine and EndLine span the function, or the whole file for file
+	// violations, when known
+	StartLine int `json:",omitempty"`
+	EndLine   int `json:",omitempty"`
+	// Severity is the severity the rule reported; it is not serialized so
+	// the report schema stays unchanged
+	Severity model.Severity `json:"-"`
 }
 
 // SizeRule checks file and function size thresholds
 type SizeRule struct {
 	MaxFileLines     int
 	MaxFunctionLines int
-	violations       []SizeViolation
-	fset             *token.FileSet
+	// Excludes are globs of files, relative to the checked directory, that
+	// are not checked
+	Excludes []string
+	// Tests selects whether Go test files are checked; by default they are
+	// not
+	Tests      TestFileMode
+	violations []SizeViolation
+	// sources is shared with the god object rule when set by the scorer
+	sources *astCache
 }
 
 // NewSizeRule creates a new size rule checker with default thresholds
@@ -31,19 +47,42 @@ func NewSizeRule() *SizeRule {
 		MaxFileLines:     500,
 		MaxFunctionLines: 80,
 		violations:       make([]SizeViolation, 0),
-		fset:             token.NewFileSet(),
 	}
 }
 
-// Check analyzes the given directory for size violations
+// Check analyzes the given directory for size violations. Files are
+// checked concurrently; violations are sorted by file, line and function.
 func (s *SizeRule) Check(dirPath string) error {
 	s.violations = make([]SizeViolation, 0)
 
-	err := s.checkFilesInDir(dirPath)
+	files, err := collectRuleFiles(dirPath, s.Excludes, s.Tests)
+	if err != nil {
+		return err
+	}
+
+	sources := s.sources.orNew()
+	var mu sync.Mutex
+	err = forEachFile(files, func(filePath string) error {
+		violations, err := s.checkFile(filePath, sources.parse(filePath))
+		mu.Lock()
+		defer mu.Unlock()
+		s.violations = append(s.violations, violations...)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
+	sort.Slice(s.violations, func(i, j int) bool {
+		a, b := s.violations[i], s.violations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Function < b.Function
+	})
 	return nil
 }
 
@@ -52,66 +91,38 @@ func (s *SizeRule) Violations() []SizeViolation {
 	return s.violations
 }
 
-// checkFilesInDir walks through the directory and checks all Go files
-func (s *SizeRule) checkFilesInDir(dirPath string) error {
-	return s.walkDir(dirPath, func(filePath string) error {
-		return s.checkFile(filePath)
-	})
-}
-
-// walkDir walks through a directory and calls the callback for each Go file
-func (s *SizeRule) walkDir(root string, callback func(string) error) error {
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip files with errors
-		}
-
-		// Skip directories
-		if info.IsDir() {
-			// Skip hidden directories
-			if strings.HasPrefix(info.Name(), ".") {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Skip non-Go files
-		if !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-
-		// Skip hidden files
-		if strings.HasPrefix(info.Name(), ".") {
-			return nil
-		}
-
-		return callback(path)
-	})
-}
-
-// checkFile checks a single file for size violations
-func (s *SizeRule) checkFile(filePath string) error {
-	// Read file content
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
+// checkFile returns the size violations of a single file
+func (s *SizeRule) checkFile(filePath string, source *parsedSource) ([]SizeViolation, error) {
+	if source.err != nil {
+		return nil, source.err
 	}
+	content := source.content
 
 	// Check file LOC
+	var violations []SizeViolation
 	fileLines := s.countNonEmptyLines(string(content))
 	if fileLines > s.MaxFileLines {
-		s.violations = append(s.violations, SizeViolation{
+		violations = append(violations, SizeViolation{
 			File:      filePath,
 			Function:  "",
 			Lines:     fileLines,
 			Threshold: s.MaxFileLines,
+			Line:      1,
+			StartLine: 1,
+			EndLine:   s.countLines(string(content)),
 		})
 	}
 
 	// Check function LOC
-	s.checkFunctions(filePath, content)
+	return append(violations, s.checkFunctions(filePath, source)...), nil
+}
 
-	return nil
+// countLines returns the number of lines in a file
+func (s *SizeRule) countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
 }
 
 // countNonEmptyLines counts non-empty lines in a file
@@ -127,15 +138,15 @@ func (s *SizeRule) countNonEmptyLines(content string) int {
 	return count
 }
 
-// checkFunctions checks function sizes in a file
-func (s *SizeRule) checkFunctions(filePath string, content []byte) {
-	// Parse AST
-	node, err := parser.ParseFile(s.fset, filePath, content, 0)
-	if err != nil {
-		return // Skip malformed files
+// checkFunctions returns the functions of a file over the size threshold
+func (s *SizeRule) checkFunctions(filePath string, source *parsedSource) []SizeViolation {
+	if source.node == nil {
+		return nil // Skip malformed files
 	}
+	node, fset := source.node, source.fset
 
 	// Walk through all declarations
+	var violations []SizeViolation
 	ast.Inspect(node, func(n ast.Node) bool {
 		funcDecl, ok := n.(*ast.FuncDecl)
 		if !ok {
@@ -143,21 +154,25 @@ func (s *SizeRule) checkFunctions(filePath string, content []byte) {
 		}
 
 		// Calculate function lines
-		startLine := s.fset.Position(funcDecl.Pos()).Line
-		endLine := s.fset.Position(funcDecl.End()).Line
+		startLine := fset.Position(funcDecl.Pos()).Line
+		endLine := fset.Position(funcDecl.End()).Line
 		funcLines := endLine - startLine + 1
 
 		if funcLines > s.MaxFunctionLines {
-			s.violations = append(s.violations, SizeViolation{
+			violations = append(violations, SizeViolation{
 				File:      filePath,
 				Function:  funcDecl.Name.Name,
 				Lines:     funcLines,
 				Threshold: s.MaxFunctionLines,
+				Line:      startLine,
+				StartLine: startLine,
+				EndLine:   endLine,
 			})
 		}
 
 		return true
 	})
+	return violations
 }
 
 // HasCriticalViolations returns true if any size violations found
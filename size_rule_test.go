@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -53,6 +56,9 @@ func TestSizeRule_DetectLargeFile(t *testing.T) {
 			if v.Threshold != 500 {
 				t.Errorf("Expected threshold 500, got %d", v.Threshold)
 			}
+			if v.Line != 1 {
+				t.Errorf("Expected file violation on line 1, got %d", v.Line)
+			}
 		}
 	}
 
@@ -97,6 +103,9 @@ func largeFunction() {
 			if v.Threshold != 80 {
 				t.Errorf("Expected threshold 80, got %d", v.Threshold)
 			}
+			if v.Line != 3 {
+				t.Errorf("Expected largeFunction to start on line 3, got %d", v.Line)
+			}
 		}
 	}
 
@@ -196,3 +205,69 @@ func TestSizeRule_HasCriticalViolations(t *testing.T) {
 		t.Error("Expected violations after checking large file")
 	}
 }
+
+func TestSizeRule_FileViolationSpansWholeFile(t *testing.T) {
+	// 600 statements separated by blank lines: 601 counted lines, 1201 in all
+	tmpDir := t.TempDir()
+	content := "package big\n" + strings.Repeat("\nvar _ = 1\n", 600)
+	if err := os.WriteFile(filepath.Join(tmpDir, "big.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	rule := NewSizeRule()
+	if err := rule.Check(tmpDir); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	violations := rule.Violations()
+	if len(violations) != 1 {
+		t.Fatalf("Expected one file violation, got %+v", violations)
+	}
+	if v := violations[0]; v.Lines != 601 || v.StartLine != 1 || v.EndLine != 1201 {
+		t.Errorf("Expected 601 lines spanning 1-1201, got %+v", v)
+	}
+}
+
+func TestAnalysisService_ReportsViolationLines(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	body := strings.Repeat("\t_ = 1\n", 90)
+	methods := ""
+	for i := 0; i < 11; i++ {
+		methods += fmt.Sprintf("func (s *Server) M%d() {}\n", i)
+	}
+	writeServiceFixture(t, root, map[string]string{
+		"go.mod":    "module example.com/app\n\ngo 1.24\n",
+		"big.go":    "package app\n\n// Run is too long\nfunc Run() {\n" + body + "}\n",
+		"server.go": "package app\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n\ntype Server struct {\n\tname string\n}\n\n" + methods,
+	})
+
+	report, _ := NewAnalysisService().analyze(AnalyzeRequest{Path: root, Format: string(FormatText), Quiet: true})
+	if report == nil || len(report.Size) != 1 || len(report.GodObject) != 1 {
+		t.Fatalf("expected one size and one god object violation, got %+v", report)
+	}
+	if report.Size[0].Line != 4 || report.GodObject[0].Line != 7 {
+		t.Fatalf("expected Run on line 4 and Server on line 7, got %d and %d", report.Size[0].Line, report.GodObject[0].Line)
+	}
+	if size, god := report.Size[0], report.GodObject[0]; size.StartLine != 4 || size.EndLine != 95 || god.StartLine != 7 || god.EndLine != 9 {
+		t.Fatalf("expected Run to span lines 4-95 and Server 7-9, got %+v and %+v", size, god)
+	}
+
+	text := (&Reporter{format: FormatText, width: 200, basePath: root}).Format(report)
+	for _, want := range []string{"Function 'Run' in big.go:4-95: 92 lines", "Struct 'Server' in server.go:7-9:"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the text report:\n%s", want, text)
+		}
+	}
+	type span struct {
+		Line      int `json:"line"`
+		StartLine int `json:"startLine"`
+		EndLine   int `json:"endLine"`
+	}
+	var doc struct {
+		SizeViolations      []span `json:"sizeViolations"`
+		GodObjectViolations []span `json:"godObjectViolations"`
+	}
+	if err := json.Unmarshal([]byte(NewReporter(FormatJSONV1).Format(report)), &doc); err != nil ||
+		doc.SizeViolations[0] != (span{4, 4, 95}) || doc.GodObjectViolations[0] != (span{7, 7, 9}) {
+		t.Errorf("expected json-v1 spans 4-95 and 7-9, got %+v (%v)", doc, err)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatTAP prints the report as a TAP version 13 stream, for harnesses
+// that aggregate tools through the Test Anything Protocol
+const FormatTAP OutputFormat = "tap"
+
+// tapDiagnostics is the YAML block under a failed test point
+type tapDiagnostics struct {
+	Count      int      `yaml:"count"`
+	Violations []string `yaml:"violations"`
+}
+
+// formatTAP renders one test point per rule, after the plan line. The core
+// rules always get a point, in JUnit's suite order, followed by any other
+// rule with violations. A rule with violations is not ok and lists them in
+// a YAML diagnostics block; paths are relative to the analyzed directory.
+func formatTAP(report *StructuralReport) string {
+	relative := func(file string) string {
+		return relativeToBase(file, report.Path)
+	}
+
+	order := append([]string(nil), junitCoreRules...)
+	violations := make(map[string][]string)
+	add := func(ruleID, violation string) {
+		if _, ok := violations[ruleID]; !ok && !slices.Contains(order, ruleID) {
+			order = append(order, ruleID)
+		}
+		violations[ruleID] = append(violations[ruleID], violation)
+	}
+
+	for _, v := range report.Circular {
+		cycle := make([]string, len(v.Path))
+		for i, file := range v.Path {
+			cycle[i] = relative(file)
+		}
+		add("rule.circular-dependency", formatCyclePath(cycle))
+	}
+	for _, v := range report.Layer {
+		ruleID := v.RuleID
+		if ruleID == "" {
+			ruleID = "rule.layer-validation"
+		}
+		add(ruleID, v.Message)
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		add("rule.feature-isolation", v.Message)
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		add("rule.dependency-cap", v.Message)
+	}
+	for _, v := range report.Size {
+		add("rule.size", relative(v.File)+lineSuffix(v.Line)+": "+sizeViolationMessage(v))
+	}
+	for _, v := range report.GodObject {
+		add("rule.god-object", relative(v.File)+lineSuffix(v.Line)+": "+godObjectViolationMessage(v))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("TAP version 13\n")
+	sb.WriteString(fmt.Sprintf("1..%d\n", len(order)))
+	for i, ruleID := range order {
+		found := violations[ruleID]
+		if len(found) == 0 {
+			sb.WriteString(fmt.Sprintf("ok %d - %s\n", i+1, ruleShortName(ruleID)))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("not ok %d - %s\n", i+1, ruleShortName(ruleID)))
+		writeTAPDiagnostics(&sb, tapDiagnostics{Count: len(found), Violations: found})
+	}
+	return sb.String()
+}
+
+// writeTAPDiagnostics writes a YAML block indented under its test point
+func writeTAPDiagnostics(sb *strings.Builder, diagnostics tapDiagnostics) {
+	var data strings.Builder
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(diagnostics); err != nil {
+		return
+	}
+	sb.WriteString("  ---\n")
+	for _, line := range strings.Split(strings.TrimSuffix(data.String(), "\n"), "\n") {
+		sb.WriteString("  " + line + "\n")
+	}
+	sb.WriteString("  ...\n")
+}
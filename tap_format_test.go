@@ -0,0 +1,73 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFormatTAP_CleanRepoPassesEveryRule(t *testing.T) {
+	out := NewReporter(FormatTAP).Format(&StructuralReport{Path: "/repo", Score: &StructuralScore{TotalScore: 100, MaxScore: 100}})
+	want := "TAP version 13\n1..4\nok 1 - circular-dependency\nok 2 - layer-validation\nok 3 - size\nok 4 - god-object\n"
+	if out != want {
+		t.Fatalf("expected four passing test points:\n%s\ngot:\n%s", want, out)
+	}
+}
+
+func TestFormatTAP_ViolationsAsYAMLDiagnostics(t *testing.T) {
+	report := &StructuralReport{
+		Path:     "/repo",
+		Circular: []CycleViolation{{Path: []string{"/repo/a/a.go", "/repo/b/b.go"}, Severity: model.SeverityCritical}},
+		Size:     []SizeViolation{{File: "/repo/big.go", Lines: 600, Threshold: 500, Line: 1}},
+		Layer:    []LayerViolation{{From: "/repo/x.go", To: "/repo/y.go", Message: "x imports y", RuleID: "rule.custom-boundary"}},
+	}
+
+	out := NewReporter(FormatTAP).Format(report)
+	for _, want := range []string{
+		"TAP version 13\n1..5\n",
+		"not ok 1 - circular-dependency\n  ---\n  count: 1\n  violations:\n    - a/a.go → b/b.go → a/a.go\n  ...\n",
+		"ok 2 - layer-validation\n",
+		"not ok 3 - size\n  ---\n  count: 1\n  violations:\n    - 'big.go:1: ",
+		"ok 4 - god-object\n",
+		"not ok 5 - custom-boundary\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in TAP output:\n%s", want, out)
+		}
+	}
+}
+
+func TestAnalysisService_TAPKeepsExitCode(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+	writeServiceFixture(t, root, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n",
+		"repo/store.go": "package repo\n\nimport _ \"example.com/app/handler\"\n",
+		"handler/h.go":  "package handler\n",
+	})
+
+	var textCode, tapCode int
+	captureStdout(t, func() {
+		textCode = NewAnalysisService().Run(AnalyzeRequest{Path: root, Format: string(FormatText)})
+	})
+	out := captureStdout(t, func() {
+		tapCode = NewAnalysisService().Run(AnalyzeRequest{Path: root, Format: string(FormatTAP)})
+	})
+	if tapCode != textCode {
+		t.Errorf("expected the text exit code %d, got %d", textCode, tapCode)
+	}
+	if !strings.HasPrefix(out, "TAP version 13\n1..4\nok 1 - circular-dependency\nnot ok 2 - layer-validation\n  ---\n") {
+		t.Fatalf("expected a failing layer-validation point, got:\n%s", out)
+	}
+
+	block := out[strings.Index(out, "  ---\n")+len("  ---\n") : strings.Index(out, "  ...\n")]
+	var diagnostics tapDiagnostics
+	if err := yaml.Unmarshal([]byte(block), &diagnostics); err != nil {
+		t.Fatalf("expected the diagnostics block to be YAML: %v\n%s", err, block)
+	}
+	if diagnostics.Count != 1 || len(diagnostics.Violations) != 1 || !strings.HasPrefix(diagnostics.Violations[0], "repo/store.go") {
+		t.Errorf("expected the layer violation in the diagnostics, got %+v", diagnostics)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+// terminalColumns is not supported on this platform; callers fall back to
+// COLUMNS or the default width
+func terminalColumns() int {
+	return 0
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// TestFileMode selects whether the size and god object rules check Go test
+// files. The empty mode leaves them out, so test files do not count towards
+// the score unless a run opts them in.
+type TestFileMode string
+
+const (
+	// TestFilesExclude checks production files only, the default
+	TestFilesExclude TestFileMode = ""
+	// TestFilesInclude checks test files along with production files
+	TestFilesInclude TestFileMode = "include"
+	// TestFilesOnly checks test files only
+	TestFilesOnly TestFileMode = "only"
+)
+
+// isGoTestFile reports whether path is a Go test file
+func isGoTestFile(path string) bool {
+	return strings.HasSuffix(path, "_test.go")
+}
+
+// checks reports whether the mode checks the Go file at path
+func (m TestFileMode) checks(path string) bool {
+	switch m {
+	case TestFilesInclude:
+		return true
+	case TestFilesOnly:
+		return isGoTestFile(path)
+	default:
+		return !isGoTestFile(path)
+	}
+}
+
+// testFileFlags holds the analyze flags that select the TestFileMode
+type testFileFlags struct {
+	includeTests *bool
+	testsOnly    *bool
+}
+
+// bindTestFileFlags registers the TestFileMode flags on fs
+func bindTestFileFlags(fs *flag.FlagSet) *testFileFlags {
+	return &testFileFlags{
+		includeTests: fs.Bool("include-tests", false, "Let the size and god object rules check Go test files too"),
+		testsOnly:    fs.Bool("tests-only", false, "Let the size and god object rules check Go test files only"),
+	}
+}
+
+// mode validates the parsed flags and returns the TestFileMode they select
+func (f *testFileFlags) mode() (TestFileMode, error) {
+	switch {
+	case *f.includeTests && *f.testsOnly:
+		return TestFilesExclude, NewCLIError(ErrorCLIUsage, "Flags -include-tests and -tests-only cannot be used together", "Use -include-tests to add test files or -tests-only to check them alone", nil)
+	case *f.includeTests:
+		return TestFilesInclude, nil
+	case *f.testsOnly:
+		return TestFilesOnly, nil
+	default:
+		return TestFilesExclude, nil
+	}
+}
+
+// testFileScope returns the files the per-file rules check under mode. The
+// analyzed files only hold the Go test files the graph follows, so the
+// others are walked from absPath, skipping excluded and, when configured,
+// third-party files.
+func testFileScope(absPath string, files []rules.RepositoryFile, mode TestFileMode, cfg *Config, thirdParty []ThirdPartyDir) []rules.RepositoryFile {
+	scoped := make([]rules.RepositoryFile, 0, len(files))
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		if mode.checks(file.Path) {
+			scoped = append(scoped, file)
+			seen[file.Path] = true
+		}
+	}
+	if mode == TestFilesExclude {
+		return scoped
+	}
+
+	paths, err := collectRuleFiles(absPath, configExcludes(cfg), TestFilesOnly)
+	if err != nil {
+		return scoped
+	}
+	var testFiles []rules.RepositoryFile
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		if content, err := os.ReadFile(path); err == nil {
+			testFiles = append(testFiles, rules.RepositoryFile{Path: path, Content: string(content)})
+		}
+	}
+	if thirdPartyExcluded(cfg) {
+		testFiles = withoutThirdPartyFiles(absPath, testFiles, thirdParty)
+	}
+	scoped = append(scoped, testFiles...)
+	sort.Slice(scoped, func(i, j int) bool { return scoped[i].Path < scoped[j].Path })
+	return scoped
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testFilesFixture holds a large test file next to a small and a large
+// production file
+func testFilesFixture() map[string]string {
+	return map[string]string{
+		"app/app.go":      "package app\n",
+		"app/big.go":      "package app\n" + strings.Repeat("// filler\n", 600),
+		"app/app_test.go": "package app\n" + strings.Repeat("// filler\n", 600),
+	}
+}
+
+func sizeViolationNames(violations []SizeViolation) []string {
+	var files []string
+	for _, v := range violations {
+		files = append(files, filepath.Base(v.File))
+	}
+	return files
+}
+
+func TestSizeRule_TestFileModes(t *testing.T) {
+	dir := t.TempDir()
+	writeServiceFixture(t, dir, testFilesFixture())
+
+	tests := []struct {
+		mode TestFileMode
+		want string
+	}{
+		{mode: TestFilesExclude, want: "big.go"},
+		{mode: TestFilesInclude, want: "app_test.go,big.go"},
+		{mode: TestFilesOnly, want: "app_test.go"},
+	}
+	for _, tc := range tests {
+		rule := NewSizeRule()
+		rule.Tests = tc.mode
+		if err := rule.Check(dir); err != nil {
+			t.Fatalf("mode %q: check failed: %v", tc.mode, err)
+		}
+		if got := strings.Join(sizeViolationNames(rule.Violations()), ","); got != tc.want {
+			t.Fatalf("mode %q: expected size violations in %s, got %s", tc.mode, tc.want, got)
+		}
+	}
+}
+
+func TestAnalysisService_SkipsLargeTestFilesByDefault(t *testing.T) {
+	tests := []struct {
+		mode TestFileMode
+		want string
+	}{
+		{mode: TestFilesExclude, want: "big.go"},
+		{mode: TestFilesInclude, want: "app_test.go,big.go"},
+		{mode: TestFilesOnly, want: "app_test.go"},
+	}
+	for _, tc := range tests {
+		report, _ := analyzeFixture(t, testFilesFixture(), AnalyzeOptions{NoLargest: true, Rules: &RuleSelection{Tests: tc.mode}})
+		if got := strings.Join(sizeViolationNames(sortedSize(report.Size)), ","); got != tc.want {
+			t.Fatalf("mode %q: expected size violations in %s, got %s", tc.mode, tc.want, got)
+		}
+	}
+}
+
+func TestAnalysisService_SkipsTestFilesTheGraphFollows(t *testing.T) {
+	files := testFilesFixture()
+	files[".repodoctor/config.yaml"] = "graph:\n  test_edges: include\n"
+
+	report, _ := analyzeFixture(t, files, AnalyzeOptions{NoLargest: true})
+	if got := strings.Join(sizeViolationNames(report.Size), ","); got != "big.go" {
+		t.Fatalf("expected test files in the graph to be skipped by default, got size violations in %s", got)
+	}
+}
+
+func TestComposeAnalyzeRequest_TestFileFlags(t *testing.T) {
+	for flag, want := range map[string]TestFileMode{"-include-tests": TestFilesInclude, "-tests-only": TestFilesOnly} {
+		req, err := parseAnalyzeFlags([]string{flag, "."})
+		if err != nil || req.Options.Rules.testFiles() != want {
+			t.Fatalf("expected %s to select %q, got %+v, %v", flag, want, req, err)
+		}
+	}
+	req, err := parseAnalyzeFlags([]string{"."})
+	if err != nil || req.Options.Rules.testFiles() != TestFilesExclude {
+		t.Fatalf("expected test files to be skipped by default, got %+v, %v", req, err)
+	}
+	if _, err := parseAnalyzeFlags([]string{"-include-tests", "-tests-only", "."}); err == nil {
+		t.Fatal("expected -include-tests and -tests-only to be rejected together")
+	}
+}
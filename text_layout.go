@@ -0,0 +1,196 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// defaultTextWidth is used when the terminal width cannot be detected
+	defaultTextWidth = 100
+	// minTextWidth keeps boxes and truncated paths legible on tiny terminals
+	minTextWidth = 40
+	pathEllipsis = "…"
+)
+
+// textLayout holds the rendering width of the boxed text report
+type textLayout struct {
+	width int
+}
+
+// newTextLayout creates a layout for the given width. A non-positive width
+// falls back to the detected terminal width.
+func newTextLayout(width int) *textLayout {
+	if width <= 0 {
+		width = detectTerminalWidth()
+	}
+	if width < minTextWidth {
+		width = minTextWidth
+	}
+	return &textLayout{width: width}
+}
+
+// detectTerminalWidth returns the width of the attached terminal, honouring
+// COLUMNS first and falling back to defaultTextWidth
+func detectTerminalWidth() int {
+	if columns, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS"))); err == nil && columns > 0 {
+		return columns
+	}
+	if columns := terminalColumns(); columns > 0 {
+		return columns
+	}
+	return defaultTextWidth
+}
+
+// boxTop returns the top border of a section box
+func (l *textLayout) boxTop() string {
+	return "┌" + strings.Repeat("─", l.width-2) + "┐"
+}
+
+// boxTitle returns a padded section title line
+func (l *textLayout) boxTitle(title string) string {
+	return "│" + padRight("  "+title, l.width-2) + "│"
+}
+
+// boxBottom returns the bottom border of a section box
+func (l *textLayout) boxBottom() string {
+	return "└" + strings.Repeat("─", l.width-2) + "┘"
+}
+
+// headerLines returns the double-lined report banner centred in the width
+func (l *textLayout) headerLines() []string {
+	title := "RepoDoctor Structural Analysis Report"
+	inner := l.width - 2
+	left := (inner - utf8.RuneCountInString(title)) / 2
+	if left < 0 {
+		left = 0
+	}
+	return []string{
+		"╔" + strings.Repeat("═", inner) + "╗",
+		"║" + padRight(strings.Repeat(" ", left)+title, inner) + "║",
+		"╚" + strings.Repeat("═", inner) + "╝",
+	}
+}
+
+// fitPath truncates path so that a line with reserved other characters
+// stays within the layout width
+func (l *textLayout) fitPath(path string, reserved int) string {
+	return truncatePath(path, l.width-reserved)
+}
+
+// fitCycle formats a cycle path, shrinking every element evenly so the
+// whole cycle fits on one line after the reserved prefix
+func (l *textLayout) fitCycle(path []string, reserved int) string {
+	if len(path) == 0 {
+		return ""
+	}
+	arrows := len(path) * utf8.RuneCountInString(" → ")
+	budget := (l.width - reserved - arrows) / (len(path) + 1)
+
+	fitted := make([]string, len(path))
+	for i, pkg := range path {
+		fitted[i] = truncatePath(pkg, budget)
+	}
+	return formatCyclePath(fitted)
+}
+
+// fitMessage truncates the given paths inside a preformatted message and
+// wraps what still does not fit after the reserved prefix
+func (l *textLayout) fitMessage(message string, reserved int, paths ...string) string {
+	return l.wrap(l.shortenPaths(message, reserved, paths...), reserved)
+}
+
+// shortenPaths truncates the given paths inside a preformatted message so
+// that it fits after the reserved prefix, as far as the file names allow
+func (l *textLayout) shortenPaths(message string, reserved int, paths ...string) string {
+	overflow := reserved + utf8.RuneCountInString(message) - l.width
+	if overflow <= 0 {
+		return message
+	}
+
+	present := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path != "" && strings.Contains(message, path) {
+			present = append(present, path)
+		}
+	}
+	if len(present) == 0 {
+		return message
+	}
+
+	share := (overflow + len(present) - 1) / len(present)
+	for _, path := range present {
+		shortened := truncatePath(path, utf8.RuneCountInString(path)-share)
+		message = strings.Replace(message, path, shortened, 1)
+	}
+	return message
+}
+
+// wrap breaks text that follows a reserved-rune prefix into lines that fit
+// the layout width. Lines break at spaces and continuation lines are
+// indented to line up with the first; a word longer than a whole line is
+// cut.
+func (l *textLayout) wrap(text string, reserved int) string {
+	budget := l.width - reserved
+	if utf8.RuneCountInString(text) <= budget {
+		return text
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		if current != "" && utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > budget {
+			lines = append(lines, current)
+			current = ""
+		}
+		if current != "" {
+			current += " "
+		}
+		current += word
+		for utf8.RuneCountInString(current) > budget {
+			runes := []rune(current)
+			lines = append(lines, string(runes[:budget]))
+			current = string(runes[budget:])
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return strings.Join(lines, "\n"+strings.Repeat(" ", reserved))
+}
+
+// truncatePath middle-truncates a path to at most max runes, replacing the
+// middle with an ellipsis. The file name is never cut, so the result may
+// exceed max when the file name alone does not fit.
+func truncatePath(path string, max int) string {
+	runes := []rune(path)
+	if len(runes) <= max {
+		return path
+	}
+
+	slash := strings.LastIndexAny(path, `/\`)
+	if slash < 0 {
+		return path
+	}
+
+	tail := []rune(path[slash:])
+	head := max - len(tail) - 1
+	if head < 0 {
+		head = 0
+	}
+	if head+1+len(tail) >= len(runes) {
+		return path
+	}
+	return string(runes[:head]) + pathEllipsis + string(tail)
+}
+
+// padRight pads s with spaces to width runes, truncating if it is longer
+func padRight(s string, width int) string {
+	length := utf8.RuneCountInString(s)
+	if length >= width {
+		return string([]rune(s)[:width])
+	}
+	return s + strings.Repeat(" ", width-length)
+}
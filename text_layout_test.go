@@ -0,0 +1,121 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func deepFixtureReport() *StructuralReport {
+	deep := "/home/ci/workspace/monorepo/services/payments/internal/adapters/persistence/postgres/migrations/versioned"
+	return &StructuralReport{
+		Version: "0.5.0-dev",
+		Path:    deep,
+		Score: &StructuralScore{
+			TotalScore: 72, MaxScore: 100,
+			CircularCount: 1, LayerCount: 1, SizeCount: 2, GodObjectCount: 1, ViolationCount: 5,
+		},
+		Circular: []CycleViolation{{Path: []string{deep + "/a/alpha.go", deep + "/b/beta.go", deep + "/c/gamma.go"}}},
+		Layer: []LayerViolation{{
+			From:    deep + "/repo/store.go",
+			To:      deep + "/handler/http.go",
+			Message: deep + "/repo/store.go (repo) -> " + deep + "/handler/http.go (handler): upward import not allowed",
+		}},
+		Size: []SizeViolation{
+			{File: deep + "/schema_registry_generated.go", Function: "applyMigrations", Lines: 140, Threshold: 80},
+			{File: deep + "/schema_registry_generated.go", Lines: 900, Threshold: 500},
+		},
+		GodObject:     []GodObjectViolation{{StructName: "MigrationRunner", File: deep + "/runner.go", FieldCount: 22, MethodCount: 3}},
+		HasViolations: true,
+	}
+}
+
+func TestReporter_TextRespectsForcedWidth(t *testing.T) {
+	for _, width := range []int{60, 100, 160} {
+		reporter := NewReporter(FormatText)
+		reporter.width = width
+		reporter.absPaths = true
+		out := reporter.Format(deepFixtureReport())
+
+		for _, line := range strings.Split(out, "\n") {
+			if n := utf8.RuneCountInString(line); n > width {
+				t.Errorf("width %d: line of %d runes overflows: %q", width, n, line)
+			}
+		}
+
+		border := "┌" + strings.Repeat("─", width-2) + "┐"
+		if !strings.Contains(out, border) {
+			t.Errorf("width %d: expected box border sized to width", width)
+		}
+		if !strings.Contains(out, "Path: ") || utf8.RuneCountInString(strings.Split(strings.Split(out, "Path: ")[1], "\n")[0])+len("Path: ") > width {
+			t.Errorf("width %d: expected report path to fit the width", width)
+		}
+		for _, name := range []string{"/alpha.go", "/store.go", "/schema_registry_generated.go", "/runner.go"} {
+			if !strings.Contains(out, name) {
+				t.Errorf("width %d: expected file name %s to stay visible", width, name)
+			}
+		}
+	}
+}
+
+func TestReporter_TextWideWidthKeepsFullPaths(t *testing.T) {
+	reporter := NewReporter(FormatText)
+	reporter.width = 160
+	reporter.absPaths = true
+	report := deepFixtureReport()
+	out := reporter.Format(report)
+
+	if !strings.Contains(out, "Path: "+report.Path) {
+		t.Fatalf("expected untruncated report path at width 160: %s", out)
+	}
+}
+
+func TestTruncatePath_KeepsFileName(t *testing.T) {
+	path := "/very/long/monorepo/path/to/some/package/file_name.go"
+
+	got := truncatePath(path, 30)
+	if utf8.RuneCountInString(got) != 30 {
+		t.Fatalf("expected 30 runes, got %d (%q)", utf8.RuneCountInString(got), got)
+	}
+	if !strings.HasSuffix(got, "/file_name.go") || !strings.Contains(got, pathEllipsis) {
+		t.Fatalf("expected middle truncation keeping file name, got %q", got)
+	}
+	if got := truncatePath(path, 5); got != pathEllipsis+"/file_name.go" {
+		t.Fatalf("expected file name to survive tiny budgets, got %q", got)
+	}
+	if truncatePath("short.go", 30) != "short.go" {
+		t.Fatal("short paths must be returned unchanged")
+	}
+}
+
+func TestNewTextLayout_FallsBackToDefaultWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	if layout := newTextLayout(0); layout.width <= 0 {
+		t.Fatalf("expected positive detected width, got %d", layout.width)
+	}
+
+	t.Setenv("COLUMNS", "132")
+	if layout := newTextLayout(0); layout.width != 132 {
+		t.Fatalf("expected COLUMNS to be honoured, got %d", layout.width)
+	}
+	if layout := newTextLayout(10); layout.width != minTextWidth {
+		t.Fatalf("expected width clamped to %d, got %d", minTextWidth, layout.width)
+	}
+}
+
+func TestTextLayout_WrapIndentsContinuationLines(t *testing.T) {
+	layout := &textLayout{width: 20}
+	if got := layout.wrap("fits as is", 4); got != "fits as is" {
+		t.Fatalf("expected short text unchanged, got %q", got)
+	}
+
+	got := layout.wrap("alpha beta gamma delta epsilon", 4)
+	if want := "alpha beta gamma\n    delta epsilon"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for _, line := range strings.Split("[1] "+layout.wrap("a_word_longer_than_the_line_budget", 4), "\n") {
+		if n := utf8.RuneCountInString(line); n > layout.width {
+			t.Errorf("line of %d runes overflows: %q", n, line)
+		}
+	}
+}
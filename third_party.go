@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"RepoDoctor/internal/rules"
+)
+
+// ThirdPartyDir is a directory detected as copied third-party source, with
+// the evidence the detection used. Dir is relative to the analyzed
+// directory, with slashes.
+type ThirdPartyDir struct {
+	Dir      string   `json:"dir"`
+	Evidence []string `json:"evidence"`
+}
+
+// licenseFileNames are the files that mark a directory as carrying its own
+// license
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"}
+
+// vendorLikeSegments are path segments under which copied code is usually
+// kept when it is not in vendor/: third-party folders and the code hosts
+// of GOPATH-style copies such as lib/github.com/owner/repo
+var vendorLikeSegments = map[string]bool{
+	"third_party": true, "thirdparty": true, "3rdparty": true,
+	"github.com": true, "gitlab.com": true, "bitbucket.org": true, "golang.org": true, "gopkg.in": true,
+}
+
+// importCommentRe matches a Go import comment, such as
+// package yaml // import "gopkg.in/yaml.v3"
+var importCommentRe = regexp.MustCompile(`(?m)^package\s+\w+\s*//\s*import\s+"([^"]+)"`)
+
+// detectThirdPartyCode returns the directories below absPath that hold
+// copied third-party source. A directory qualifies with a license file plus
+// an import comment outside the module of absPath, or when it sits under a
+// vendor-like path segment such as third_party/. A license file alone is not
+// enough: our own packages may carry one. Subdirectories of a detected
+// directory are covered by it and not listed.
+func detectThirdPartyCode(absPath string, files []rules.RepositoryFile) []ThirdPartyDir {
+	modulePath, _ := readGoModule(filepath.Join(absPath, "go.mod"))
+	// foreignImports maps every directory with analyzed files to the import
+	// comments of its files that lie outside the module
+	foreignImports := make(map[string][]string)
+	for _, file := range files {
+		dir := filepath.Dir(file.Path)
+		imports := foreignImports[dir]
+		for _, match := range importCommentRe.FindAllStringSubmatch(file.Content, -1) {
+			if (modulePath == "" || !isModuleImportPath(match[1], modulePath)) && !slices.Contains(imports, match[1]) {
+				imports = append(imports, match[1])
+			}
+		}
+		foreignImports[dir] = imports
+	}
+
+	var detected []ThirdPartyDir
+	for dir, imports := range foreignImports {
+		rel, err := filepath.Rel(absPath, dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if evidence := thirdPartyEvidence(dir, rel, modulePath, imports); evidence != nil {
+			detected = append(detected, ThirdPartyDir{Dir: rel, Evidence: evidence})
+		}
+	}
+	return outermostThirdPartyDirs(detected)
+}
+
+// thirdPartyEvidence returns why a directory is third-party code, or nil
+// when it is not
+func thirdPartyEvidence(dir, rel, modulePath string, foreignImports []string) []string {
+	for _, segment := range strings.Split(rel, "/") {
+		if vendorLikeSegments[segment] {
+			return []string{fmt.Sprintf("vendor-like path segment %q outside vendor/", segment)}
+		}
+	}
+
+	license := ""
+	for _, name := range licenseFileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			license = name
+			break
+		}
+	}
+	if license == "" || len(foreignImports) == 0 {
+		return nil
+	}
+	evidence := []string{"license file " + license}
+	for _, importPath := range foreignImports {
+		evidence = append(evidence, fmt.Sprintf("import comment %q outside module %q", importPath, modulePath))
+	}
+	return evidence
+}
+
+// isModuleImportPath reports whether importPath is modulePath or one of its
+// packages
+func isModuleImportPath(importPath, modulePath string) bool {
+	return importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")
+}
+
+// outermostThirdPartyDirs sorts the detected directories and drops those
+// nested in another detected directory
+func outermostThirdPartyDirs(detected []ThirdPartyDir) []ThirdPartyDir {
+	sort.Slice(detected, func(i, j int) bool { return detected[i].Dir < detected[j].Dir })
+	var outermost []ThirdPartyDir
+	for _, dir := range detected {
+		if len(outermost) > 0 && isWithinDir(dir.Dir, outermost[len(outermost)-1].Dir) {
+			continue
+		}
+		outermost = append(outermost, dir)
+	}
+	return outermost
+}
+
+// withoutThirdPartyFiles returns the files outside every third-party
+// directory
+func withoutThirdPartyFiles(absPath string, files []rules.RepositoryFile, thirdParty []ThirdPartyDir) []rules.RepositoryFile {
+	kept := make([]rules.RepositoryFile, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(absPath, file.Path)
+		if err != nil || !inThirdPartyDir(filepath.ToSlash(rel), thirdParty) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+func inThirdPartyDir(rel string, thirdParty []ThirdPartyDir) bool {
+	for _, dir := range thirdParty {
+		if isWithinDir(rel, dir.Dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithinDir reports whether the slash path rel is dir or lies below it
+func isWithinDir(rel, dir string) bool {
+	return rel == dir || strings.HasPrefix(rel, dir+"/")
+}
+
+// formatThirdPartyCode renders the detected third-party directories for
+// verbose output
+func formatThirdPartyCode(thirdParty []ThirdPartyDir, excluded bool) string {
+	if len(thirdParty) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	state := "included in"
+	if excluded {
+		state = "excluded from"
+	}
+	sb.WriteString(fmt.Sprintf("Third-party code detected (%s per-file rules):\n", state))
+	for _, dir := range thirdParty {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", dir.Dir, strings.Join(dir.Evidence, "; ")))
+	}
+	return sb.String()
+}
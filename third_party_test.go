@@ -0,0 +1,93 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"RepoDoctor/internal/rules"
+)
+
+// thirdPartyFixture lays out a module with a copied library under lib/yaml
+// and an own package that carries a LICENSE file
+func thirdPartyFixture(t *testing.T) (string, Graph) {
+	t.Helper()
+	dir := t.TempDir()
+	large := strings.Repeat("var _ = 0\n", 600)
+	files := map[string]string{
+		"go.mod":            "module example.com/app\n\ngo 1.24\n",
+		"main.go":           "package main\n\nfunc main() {}\n",
+		"lib/yaml/LICENSE":  "MIT License\n",
+		"lib/yaml/yaml.go":  "package yaml // import \"gopkg.in/yaml.v3\"\n\n" + large,
+		"pkg/own/LICENSE":   "Apache License\n",
+		"pkg/own/own.go":    "package own // import \"example.com/app/pkg/own\"\n\nfunc Own() {}\n",
+		"pkg/plain/LICENSE": "Apache License\n",
+		"pkg/plain/p.go":    "package plain\n\n" + large,
+	}
+	writeServiceFixture(t, dir, files)
+
+	graph := NewDependencyGraph()
+	for name := range files {
+		if strings.HasSuffix(name, ".go") {
+			graph.AddNode(filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+	return dir, graph
+}
+
+func sizeViolationFiles(dir string, summary *runtimeRuleSummary) []string {
+	var flagged []string
+	for _, v := range summary.result.Violations {
+		if v.RuleID == "rule.size" {
+			rel, _ := filepath.Rel(dir, v.File)
+			flagged = append(flagged, filepath.ToSlash(rel))
+		}
+	}
+	return flagged
+}
+
+func TestDetectThirdPartyCode_CopiedLibrary(t *testing.T) {
+	dir, graph := thirdPartyFixture(t)
+	detected := detectThirdPartyCode(dir, buildRulesAnalysisContext(dir, graph, nil).RepositoryFiles)
+	if len(detected) != 1 || detected[0].Dir != "lib/yaml" {
+		t.Fatalf("expected only lib/yaml to be detected, got %+v", detected)
+	}
+	evidence := strings.Join(detected[0].Evidence, "; ")
+	if !strings.Contains(evidence, "license file LICENSE") || !strings.Contains(evidence, "gopkg.in/yaml.v3") {
+		t.Fatalf("expected license and import comment evidence, got %q", evidence)
+	}
+}
+
+func TestDetectThirdPartyCode_VendorLikePath(t *testing.T) {
+	dir := t.TempDir()
+	files := []rules.RepositoryFile{
+		{Path: filepath.Join(dir, "internal", "third_party", "lz4", "lz4.go"), Content: "package lz4\n"},
+		{Path: filepath.Join(dir, "internal", "third_party", "lz4", "block", "block.go"), Content: "package block\n"},
+		{Path: filepath.Join(dir, "internal", "app", "app.go"), Content: "package app\n"},
+	}
+	detected := detectThirdPartyCode(dir, files)
+	if len(detected) != 1 || detected[0].Dir != "internal/third_party/lz4" {
+		t.Fatalf("expected the outermost third_party directory only, got %+v", detected)
+	}
+}
+
+func TestRunInternalRulePipeline_ExcludesThirdPartyFromPerFileRules(t *testing.T) {
+	dir, graph := thirdPartyFixture(t)
+
+	summary := runInternalRulePipeline(dir, graph, (&ConfigLoader{}).getDefaultConfig(), nil, nil)
+	if got, want := sizeViolationFiles(dir, summary), []string{"pkg/plain/p.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected size violations %v with third-party code excluded, got %v", want, got)
+	}
+	if len(summary.thirdParty) != 1 {
+		t.Fatalf("expected the detected directory in the summary, got %+v", summary.thirdParty)
+	}
+
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+	keep := false
+	cfg.ThirdParty = &ThirdPartyConfig{Exclude: &keep}
+	summary = runInternalRulePipeline(dir, graph, cfg, nil, nil)
+	if got, want := sizeViolationFiles(dir, summary), []string{"lib/yaml/yaml.go", "pkg/plain/p.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected size violations %v with third_party.exclude false, got %v", want, got)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FormatTree prints the directory tree annotated with per-directory health
+const FormatTree OutputFormat = "tree"
+
+// FormatTreeJSON prints the annotated directory tree as nested JSON
+const FormatTreeJSON OutputFormat = "tree-json"
+
+// isTreeFormat reports whether format prints the directory tree
+func isTreeFormat(format OutputFormat) bool {
+	return format == FormatTree || format == FormatTreeJSON
+}
+
+// validateTreeDepth rejects a negative -depth
+func validateTreeDepth(depth int) error {
+	if depth < 0 {
+		return NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid -depth: %d", depth), "Use 0 for the whole tree or a positive number of directory levels", nil)
+	}
+	return nil
+}
+
+// DirectoryHealth is one directory of the tree output. Files, Violations
+// and Score cover the directory and everything below it, so a directory
+// cut off by -depth still shows the health of its whole subtree.
+type DirectoryHealth struct {
+	Name       string             `json:"name"`
+	Path       string             `json:"path"`
+	Files      int                `json:"files"`
+	Violations int                `json:"violations"`
+	Score      float64            `json:"score"`
+	MaxScore   float64            `json:"maxScore"`
+	Children   []*DirectoryHealth `json:"children,omitempty"`
+	findings   ScoreFindings
+}
+
+// buildDirectoryTree builds the directory tree of the analyzed files, which
+// already passed every exclusion filter. Each directory is scored by the
+// configured score model over the findings of its subtree; a finding that
+// spans several directories, like a cycle, counts once in each. Children
+// are sorted by name and directories deeper than depth are dropped, unless
+// depth is 0.
+func buildDirectoryTree(absPath string, files []string, report *StructuralReport, cfg *Config, depth int) *DirectoryHealth {
+	root := &DirectoryHealth{Name: ".", Path: "."}
+	nodes := map[string]*DirectoryHealth{".": root}
+	var node func(dir string) *DirectoryHealth
+	node = func(dir string) *DirectoryHealth {
+		if existing, ok := nodes[dir]; ok {
+			return existing
+		}
+		parent := node(path.Dir(dir))
+		created := &DirectoryHealth{Name: path.Base(dir), Path: dir}
+		parent.Children = append(parent.Children, created)
+		nodes[dir] = created
+		return created
+	}
+
+	for _, file := range files {
+		if dir, ok := treeDirOf(absPath, file); ok {
+			for _, ancestor := range treeAncestors([]string{dir}) {
+				node(ancestor).Files++
+			}
+		}
+	}
+
+	count := func(bump func(*ScoreFindings), structural bool, paths ...string) {
+		var dirs []string
+		for _, p := range paths {
+			if dir, ok := treeDirOf(absPath, p); ok {
+				dirs = append(dirs, dir)
+			}
+		}
+		for _, dir := range treeAncestors(dirs) {
+			if n, ok := nodes[dir]; ok {
+				bump(&n.findings)
+				if structural {
+					n.Violations++
+				}
+			}
+		}
+	}
+	for _, v := range report.Circular {
+		count(func(f *ScoreFindings) { f.Circular++ }, true, v.Path...)
+	}
+	for _, v := range report.Layer {
+		count(func(f *ScoreFindings) { f.Layer++ }, true, v.From)
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		count(func(f *ScoreFindings) { f.FeatureIsolation++ }, true, v.From)
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		count(func(f *ScoreFindings) { f.DependencyCap++ }, true, v.Manifest)
+	}
+	for _, v := range report.Size {
+		count(func(f *ScoreFindings) { f.Size++ }, true, v.File)
+	}
+	for _, v := range report.GodObject {
+		count(func(f *ScoreFindings) { f.GodObject++ }, true, v.File)
+	}
+	for _, v := range report.Advisory {
+		count(func(f *ScoreFindings) { f.Advisory++ }, false, v.File)
+	}
+
+	finishDirectoryTree(root, scoreModelFromConfig(cfg), depth, 0)
+	return root
+}
+
+// finishDirectoryTree scores every directory, sorts children by name and
+// drops the levels below depth
+func finishDirectoryTree(node *DirectoryHealth, model ScoreModel, depth, level int) {
+	result := model.Compute(node.findings)
+	node.Score, node.MaxScore = result.Total, result.Max
+	if depth > 0 && level >= depth {
+		node.Children = nil
+		return
+	}
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Name < node.Children[j].Name
+	})
+	for _, child := range node.Children {
+		finishDirectoryTree(child, model, depth, level+1)
+	}
+}
+
+// treeDirOf returns the slash-separated directory of file relative to the
+// analyzed directory, or false when the file lies outside it
+func treeDirOf(absPath, file string) (string, bool) {
+	rel := relativeToBase(file, absPath)
+	if rel == "" || filepath.IsAbs(rel) {
+		return "", false
+	}
+	return path.Dir(rel), true
+}
+
+// treeAncestors returns every directory in dirs together with their
+// ancestors up to the root, each once
+func treeAncestors(dirs []string) []string {
+	seen := make(map[string]bool)
+	var ancestors []string
+	for _, dir := range dirs {
+		for {
+			if seen[dir] {
+				break
+			}
+			seen[dir] = true
+			ancestors = append(ancestors, dir)
+			if dir == "." {
+				break
+			}
+			dir = path.Dir(dir)
+		}
+	}
+	return ancestors
+}
+
+// formatDirectoryTree renders the tree with box-drawing connectors, one
+// directory per line with its health marker, score and violation count
+func formatDirectoryTree(root *DirectoryHealth) string {
+	if root == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(directoryHealthLine(root) + "\n")
+	writeDirectoryChildren(&sb, root.Children, "")
+	return sb.String()
+}
+
+func writeDirectoryChildren(sb *strings.Builder, children []*DirectoryHealth, prefix string) {
+	for i, child := range children {
+		connector, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		sb.WriteString(prefix + connector + directoryHealthLine(child) + "\n")
+		writeDirectoryChildren(sb, child.Children, prefix+indent)
+	}
+}
+
+// directoryHealthLine marks a directory like the report's score line:
+// ✓ from 70%, ⚠ from 50%, ✗ below
+func directoryHealthLine(node *DirectoryHealth) string {
+	percent := 100.0
+	if node.MaxScore > 0 {
+		percent = node.Score / node.MaxScore * 100
+	}
+	marker := ColorSuccess("✓")
+	if percent < 70 {
+		marker = ColorWarn("⚠")
+	}
+	if percent < 50 {
+		marker = ColorError("✗")
+	}
+	noun := "violations"
+	if node.Violations == 1 {
+		noun = "violation"
+	}
+	name := node.Name
+	if name != "." {
+		name += "/"
+	}
+	return fmt.Sprintf("%s %s  %.1f/%g  %d %s", marker, name, node.Score, node.MaxScore, node.Violations, noun)
+}
+
+// formatDirectoryTreeJSON renders the tree as nested JSON
+func formatDirectoryTreeJSON(root *DirectoryHealth) string {
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
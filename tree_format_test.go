@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// treeFixture is a nested repository whose internal/legacy subtree holds
+// every violation
+func treeFixture() (string, []string, *StructuralReport) {
+	root := filepath.FromSlash("/repo")
+	abs := func(rel string) string {
+		return filepath.Join(root, filepath.FromSlash(rel))
+	}
+	files := []string{
+		abs("main.go"),
+		abs("cmd/tool/main.go"),
+		abs("internal/api/handler.go"),
+		abs("internal/api/routes.go"),
+		abs("internal/legacy/billing/invoice.go"),
+		abs("internal/legacy/billing/ledger.go"),
+		abs("internal/legacy/store.go"),
+		abs("pkg/util/strings.go"),
+	}
+	report := &StructuralReport{
+		Circular: []CycleViolation{
+			{Path: []string{abs("internal/legacy/billing/invoice.go"), abs("internal/legacy/billing/ledger.go")}},
+			{Path: []string{abs("internal/legacy/billing/invoice.go"), abs("internal/legacy/store.go")}},
+			{Path: []string{abs("internal/legacy/billing/ledger.go"), abs("internal/legacy/store.go")}},
+		},
+		Layer:     []LayerViolation{{From: abs("internal/legacy/store.go"), To: abs("internal/api/handler.go")}},
+		Size:      []SizeViolation{{File: abs("internal/legacy/billing/ledger.go"), Lines: 900, Threshold: 500}, {File: abs("internal/legacy/billing/ledger.go"), Function: "Post", Lines: 200, Threshold: 80}},
+		GodObject: []GodObjectViolation{{StructName: "Ledger", File: abs("internal/legacy/billing/ledger.go"), FieldCount: 30}},
+	}
+	return root, files, report
+}
+
+func TestDirectoryTree_Golden(t *testing.T) {
+	previous := globalColorFormatter
+	InitColorFormatter(false)
+	t.Cleanup(func() { globalColorFormatter = previous })
+
+	root, files, report := treeFixture()
+	cfg := (&ConfigLoader{}).getDefaultConfig()
+
+	unlimited := buildDirectoryTree(root, files, report, cfg, 0)
+	assertGolden(t, "tree.txt", formatDirectoryTree(unlimited))
+	assertGolden(t, "tree.json", formatDirectoryTreeJSON(unlimited)+"\n")
+	assertGolden(t, "tree.depth2.txt", formatDirectoryTree(buildDirectoryTree(root, files, report, cfg, 2)))
+}
+
+func TestDirectoryTree_DepthKeepsSubtreeHealth(t *testing.T) {
+	root, files, report := treeFixture()
+	tree := buildDirectoryTree(root, files, report, (&ConfigLoader{}).getDefaultConfig(), 1)
+
+	var payload struct {
+		Files      int `json:"files"`
+		Violations int `json:"violations"`
+		Children   []struct {
+			Name       string            `json:"name"`
+			Violations int               `json:"violations"`
+			Score      float64           `json:"score"`
+			Children   []json.RawMessage `json:"children"`
+		} `json:"children"`
+	}
+	if err := json.Unmarshal([]byte(formatDirectoryTreeJSON(tree)), &payload); err != nil {
+		t.Fatalf("invalid tree JSON: %v", err)
+	}
+	if payload.Files != len(files) || payload.Violations != 7 {
+		t.Fatalf("expected the root to count every file and violation once, got %+v", payload)
+	}
+	names := make([]string, 0, len(payload.Children))
+	for _, child := range payload.Children {
+		names = append(names, child.Name)
+		if len(child.Children) != 0 {
+			t.Fatalf("expected -depth 1 to drop %s's children", child.Name)
+		}
+		if child.Name == "internal" && (child.Violations != 7 || child.Score >= 100) {
+			t.Fatalf("expected internal to carry its subtree's violations, got %+v", child)
+		}
+	}
+	if want := []string{"cmd", "internal", "pkg"}; len(names) != len(want) || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Fatalf("expected children %v in name order, got %v", want, names)
+	}
+}
+
+func TestComposeAnalyzeRequest_RejectsNegativeDepth(t *testing.T) {
+	if _, err := parseAnalyzeFlags([]string{"-format", "tree", "-depth", "-1"}); err == nil {
+		t.Fatal("expected a negative -depth to be rejected")
+	}
+}
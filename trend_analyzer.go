@@ -2,9 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -12,12 +15,48 @@ import (
 type HistoryEntry struct {
 	Timestamp string  `json:"timestamp"`
 	Score     float64 `json:"score"`
+	// Counts are the run's violation counts per category; entries written
+	// before counts were recorded have none, which reads as zero
+	Counts     *ReportSummary `json:"counts,omitempty"`
+	ConfigHash string         `json:"configHash,omitempty"`
+	// ExternalModules is the dependency inventory recorded by this run
+	ExternalModules []string `json:"externalModules,omitempty"`
+	// ScoreModel names the model that computed Score; entries without one
+	// were scored by the weighted model
+	ScoreModel string `json:"scoreModel,omitempty"`
+	// Density is the run's severity-weighted violations per KLOC
+	Density float64 `json:"density,omitempty"`
 }
 
+// defaultHistoryMaxEntries is how many history entries are kept unless
+// history.max_entries says otherwise
+const defaultHistoryMaxEntries = 100
+
+// ErrCorruptHistory reports a history file that is not valid JSON. Its
+// content has been copied to history.json.bak and the history starts
+// empty, so recording an entry is safe.
+var ErrCorruptHistory = errors.New("history file is corrupt")
+
 // TrendAnalyzer handles historical score tracking and trend analysis
 type TrendAnalyzer struct {
 	historyPath string
 	history     []HistoryEntry
+	// dedupeWindow is how long an identical newest entry is refreshed
+	// instead of appending a new one; zero disables deduplication
+	dedupeWindow time.Duration
+	// maxEntries caps the history when entries are recorded; the oldest
+	// are dropped first and zero keeps every entry
+	maxEntries int
+	// regressionWindow is how many runs before the newest DetectRegression
+	// averages
+	regressionWindow int
+	// scoreModel is the model of the scores passed in; trend summaries do
+	// not compare them with entries of another model
+	scoreModel string
+	// counts are the violation counts of the run passed in; trend summaries
+	// show their change per category when set
+	counts *ReportSummary
+	now    func() time.Time
 }
 
 // NewTrendAnalyzer creates a new trend analyzer
@@ -26,10 +65,15 @@ func NewTrendAnalyzer(baseDir string) *TrendAnalyzer {
 	return &TrendAnalyzer{
 		historyPath: historyPath,
 		history:     make([]HistoryEntry, 0),
+		maxEntries:  defaultHistoryMaxEntries,
+		now:         time.Now,
+
+		regressionWindow: defaultRegressionWindow,
 	}
 }
 
-// LoadHistory loads the score history from file
+// LoadHistory loads the score history from file. A corrupt file is backed
+// up and reported with ErrCorruptHistory instead of being silently dropped.
 func (t *TrendAnalyzer) LoadHistory() error {
 	// Check if file exists
 	if _, err := os.Stat(t.historyPath); os.IsNotExist(err) {
@@ -47,44 +91,105 @@ func (t *TrendAnalyzer) LoadHistory() error {
 	// Parse JSON
 	var history []HistoryEntry
 	if err := json.Unmarshal(data, &history); err != nil {
-		// Malformed file, start fresh
+		// Keep the malformed file before the next save replaces it
+		backupPath := t.historyPath + ".bak"
+		if writeErr := os.WriteFile(backupPath, data, 0644); writeErr != nil {
+			return fmt.Errorf("history file is corrupt (%v) and could not be backed up: %w", err, writeErr)
+		}
 		t.history = make([]HistoryEntry, 0)
-		return nil
+		return fmt.Errorf("%w: %v; copied to %s, starting a new history", ErrCorruptHistory, err, backupPath)
 	}
 
 	t.history = history
 	return nil
 }
 
-// AppendScore appends a new score entry to the history
+// AppendReport appends the score and violation counts of report to the
+// history
+func (t *TrendAnalyzer) AppendReport(report *StructuralReport) error {
+	_, err := t.RecordEntry(newHistoryEntry(report), true)
+	return err
+}
+
+// AppendScore appends a score without violation counts to the history.
+//
+// Deprecated: use AppendReport, which also records the violation counts.
 func (t *TrendAnalyzer) AppendScore(score float64) error {
-	// Ensure directory exists
-	configDir := filepath.Dir(t.historyPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create history directory: %w", err)
+	_, err := t.RecordEntry(HistoryEntry{Score: score}, true)
+	return err
+}
+
+// newHistoryEntry returns the history entry of report: its score, score
+// model and violation counts
+func newHistoryEntry(report *StructuralReport) HistoryEntry {
+	counts := report.Summary
+	entry := HistoryEntry{Counts: &counts}
+	if report.Score != nil {
+		entry.Score = report.Score.TotalScore
+		entry.ScoreModel = report.Score.Model
+	}
+	return entry
+}
+
+// RecordEntry stores a history entry stamped with the current time. When the
+// newest entry has the same score, counts and config hash and was recorded
+// within dedupeWindow, only its timestamp is refreshed and deduped is true.
+// force always appends.
+func (t *TrendAnalyzer) RecordEntry(entry HistoryEntry, force bool) (deduped bool, err error) {
+	if err := os.MkdirAll(filepath.Dir(t.historyPath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create history directory: %w", err)
 	}
 
-	// Create new entry
-	entry := HistoryEntry{
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Score:     score,
+	now := t.now().UTC()
+	entry.Timestamp = now.Format(time.RFC3339)
+
+	if last, ok := t.GetLastEntry(); ok && !force && isDuplicateHistoryEntry(*last, entry, now, t.dedupeWindow) {
+		last.Timestamp = entry.Timestamp
+		return true, saveHistory(t.historyPath, t.history)
 	}
 
-	// Append to history
 	t.history = append(t.history, entry)
+	t.Prune(t.maxEntries)
+	return false, saveHistory(t.historyPath, t.history)
+}
+
+// Prune drops the oldest history entries beyond max. A max of zero or less
+// keeps every entry.
+func (t *TrendAnalyzer) Prune(max int) {
+	if max <= 0 || len(t.history) <= max {
+		return
+	}
+	t.history = slices.Clone(t.history[len(t.history)-max:])
+}
 
-	// Write to file
-	return t.saveHistory()
+// isDuplicateHistoryEntry reports whether incoming repeats last within window
+func isDuplicateHistoryEntry(last, incoming HistoryEntry, now time.Time, window time.Duration) bool {
+	if window <= 0 || last.Counts == nil || incoming.Counts == nil {
+		return false
+	}
+	if last.Score != incoming.Score || *last.Counts != *incoming.Counts || last.ConfigHash != incoming.ConfigHash || last.ScoreModel != incoming.ScoreModel {
+		return false
+	}
+	if !slices.Equal(last.ExternalModules, incoming.ExternalModules) {
+		return false
+	}
+
+	recorded, err := time.Parse(time.RFC3339, last.Timestamp)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(recorded)
+	return age >= 0 && age <= window
 }
 
-// saveHistory writes the history to disk
-func (t *TrendAnalyzer) saveHistory() error {
-	data, err := json.MarshalIndent(t.history, "", "  ")
+// saveHistory writes history to the file at path
+func saveHistory(path string, history []HistoryEntry) error {
+	data, err := json.MarshalIndent(history, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal history: %w", err)
 	}
 
-	if err := os.WriteFile(t.historyPath, data, 0644); err != nil {
+	if err := os.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write history file: %w", err)
 	}
 
@@ -137,14 +242,90 @@ func (t *TrendAnalyzer) GetTrendSummary(currentScore float64) string {
 	}
 
 	prevScore, _ := t.GetPreviousScore()
+	previousModel, currentModel := historyScoreModel(t.history[len(t.history)-1].ScoreModel), historyScoreModel(t.scoreModel)
+	if previousModel != currentModel {
+		summary := fmt.Sprintf("Current Score: %.1f (%s model)\n", currentScore, currentModel)
+		summary += fmt.Sprintf("Previous Score: %.1f (%s model)\n", prevScore, previousModel)
+		summary += "Warning: scores from different score models are not comparable; no delta shown"
+		return summary
+	}
 
 	summary := fmt.Sprintf("Current Score: %.1f\n", currentScore)
 	summary += fmt.Sprintf("Previous Score: %.1f\n", prevScore)
 	summary += fmt.Sprintf("Delta: %+.1f (%s)", delta, trend)
+	if t.counts != nil {
+		summary += "\n" + formatCountDeltas(*t.counts, t.history[len(t.history)-1].Counts)
+	}
 
 	return summary
 }
 
+// formatCountDeltas shows the current violation count of each category and
+// its change since previous, as in "Circular: 2 (+1)". Missing previous
+// counts read as zero.
+func formatCountDeltas(current ReportSummary, previous *ReportSummary) string {
+	if previous == nil {
+		previous = &ReportSummary{}
+	}
+	categories := []struct {
+		name              string
+		current, previous int
+	}{
+		{"Circular", current.Circular, previous.Circular},
+		{"Layer", current.Layer, previous.Layer},
+		{"Size", current.Size, previous.Size},
+		{"God Objects", current.GodObject, previous.GodObject},
+	}
+	lines := make([]string, len(categories))
+	for i, c := range categories {
+		lines[i] = fmt.Sprintf("%s: %d (%+d)", c.name, c.current, c.current-c.previous)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MovingAverage returns the average score of the last window entries of
+// the analyzer's score model, or 0 without any
+func (t *TrendAnalyzer) MovingAverage(window int) float64 {
+	return trailingAverage(modelScores(t.history, t.scoreModel), window)
+}
+
+// DetectRegression reports whether the newest entry scored more than
+// threshold below the moving average of the regressionWindow entries before
+// it. Entries of other score models are not compared.
+func (t *TrendAnalyzer) DetectRegression(threshold float64) bool {
+	scores := modelScores(t.history, t.scoreModel)
+	if len(scores) < 2 {
+		return false
+	}
+	current := scores[len(scores)-1]
+	return trailingAverage(scores[:len(scores)-1], t.regressionWindow)-current > threshold
+}
+
+// modelScores returns the scores of the entries recorded with model
+func modelScores(history []HistoryEntry, model string) []float64 {
+	var scores []float64
+	for _, entry := range history {
+		if historyScoreModel(entry.ScoreModel) == historyScoreModel(model) {
+			scores = append(scores, entry.Score)
+		}
+	}
+	return scores
+}
+
+// trailingAverage returns the average of the last window scores, or 0
+// without any
+func trailingAverage(scores []float64, window int) float64 {
+	if window <= 0 || len(scores) == 0 {
+		return 0
+	}
+	scores = scores[max(0, len(scores)-window):]
+	var sum float64
+	for _, score := range scores {
+		sum += score
+	}
+	return sum / float64(len(scores))
+}
+
 // GetHistoryLength returns the number of entries in history
 func (t *TrendAnalyzer) GetHistoryLength() int {
 	return len(t.history)
@@ -154,3 +335,12 @@ func (t *TrendAnalyzer) GetHistoryLength() int {
 func (t *TrendAnalyzer) GetAllHistory() []HistoryEntry {
 	return t.history
 }
+
+// historyScoreModel returns the model of a recorded score; entries written
+// before score models were weighted
+func historyScoreModel(model string) string {
+	if model == "" {
+		return ScoreModelWeighted
+	}
+	return model
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTrendAnalyzer_NewAnalyzer(t *testing.T) {
@@ -272,3 +276,245 @@ func TestTrendAnalyzer_EnsureConfigDir(t *testing.T) {
 		t.Error("Expected config directory to be created")
 	}
 }
+
+func newDedupeAnalyzer(t *testing.T, clock *time.Time) *TrendAnalyzer {
+	t.Helper()
+	analyzer := NewTrendAnalyzer(t.TempDir())
+	analyzer.dedupeWindow = 10 * time.Minute
+	analyzer.now = func() time.Time { return *clock }
+	return analyzer
+}
+
+func dedupeEntry(score float64, size int) HistoryEntry {
+	return HistoryEntry{
+		Score:      score,
+		Counts:     &ReportSummary{TotalViolations: size, Size: size},
+		ConfigHash: "abc123",
+	}
+}
+
+func TestTrendAnalyzer_RecordEntry_DedupesWithinWindow(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	analyzer := newDedupeAnalyzer(t, &clock)
+
+	if _, err := analyzer.RecordEntry(dedupeEntry(91, 3), false); err != nil {
+		t.Fatalf("RecordEntry failed: %v", err)
+	}
+	clock = clock.Add(4 * time.Minute)
+	deduped, err := analyzer.RecordEntry(dedupeEntry(91, 3), false)
+	if err != nil {
+		t.Fatalf("RecordEntry failed: %v", err)
+	}
+
+	if !deduped || analyzer.GetHistoryLength() != 1 {
+		t.Fatalf("expected identical entry to be deduplicated, got deduped=%t length=%d", deduped, analyzer.GetHistoryLength())
+	}
+	if last, _ := analyzer.GetLastEntry(); last.Timestamp != "2024-01-01T12:04:00Z" {
+		t.Errorf("expected timestamp to be refreshed, got %s", last.Timestamp)
+	}
+
+	reloaded := NewTrendAnalyzer(filepath.Dir(filepath.Dir(analyzer.historyPath)))
+	if err := reloaded.LoadHistory(); err != nil || reloaded.GetHistoryLength() != 1 {
+		t.Fatalf("expected deduplicated history on disk, got length=%d err=%v", reloaded.GetHistoryLength(), err)
+	}
+}
+
+func TestTrendAnalyzer_RecordEntry_AppendsDifferentScoreWithinWindow(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	analyzer := newDedupeAnalyzer(t, &clock)
+
+	analyzer.RecordEntry(dedupeEntry(91, 3), false)
+	clock = clock.Add(time.Minute)
+	deduped, _ := analyzer.RecordEntry(dedupeEntry(88, 4), false)
+
+	if deduped || analyzer.GetHistoryLength() != 2 {
+		t.Fatalf("expected different score to append, got deduped=%t length=%d", deduped, analyzer.GetHistoryLength())
+	}
+}
+
+func TestTrendAnalyzer_RecordEntry_AppendsOutsideWindowOrWhenForced(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	analyzer := newDedupeAnalyzer(t, &clock)
+
+	analyzer.RecordEntry(dedupeEntry(91, 3), false)
+	clock = clock.Add(11 * time.Minute)
+	if deduped, _ := analyzer.RecordEntry(dedupeEntry(91, 3), false); deduped {
+		t.Fatal("expected entry outside the window to append")
+	}
+
+	clock = clock.Add(time.Minute)
+	if deduped, _ := analyzer.RecordEntry(dedupeEntry(91, 3), true); deduped {
+		t.Fatal("expected forced entry to append")
+	}
+
+	if analyzer.GetHistoryLength() != 3 {
+		t.Fatalf("expected 3 entries, got %d", analyzer.GetHistoryLength())
+	}
+}
+
+func TestTrendAnalyzer_WarnsAcrossScoreModels(t *testing.T) {
+	analyzer := NewTrendAnalyzer(t.TempDir())
+	// The first entry predates score models and counts as weighted
+	analyzer.history = []HistoryEntry{{Score: 91}, {Score: 88}}
+
+	analyzer.scoreModel = ScoreModelCategoryRubric
+	summary := analyzer.GetTrendSummary(4.5)
+	if !strings.Contains(summary, "Warning") || strings.Contains(summary, "Delta") {
+		t.Fatalf("expected a cross-model warning without a delta, got %q", summary)
+	}
+	if !strings.Contains(summary, "weighted model") || !strings.Contains(summary, "category-rubric model") {
+		t.Fatalf("expected both model names in the summary, got %q", summary)
+	}
+
+	analyzer.scoreModel = ScoreModelWeighted
+	if summary := analyzer.GetTrendSummary(90); !strings.Contains(summary, "Delta: +2.0") || strings.Contains(summary, "Warning") {
+		t.Fatalf("expected a plain delta for the same model, got %q", summary)
+	}
+}
+
+func TestTrendAnalyzer_AppendReportAfterLegacyHistory(t *testing.T) {
+	dir := t.TempDir()
+	historyPath := filepath.Join(dir, ".repodoctor", "history.json")
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		t.Fatalf("failed to create history directory: %v", err)
+	}
+	legacy := `[{"timestamp": "2025-01-01T00:00:00Z", "score": 78}, {"timestamp": "2025-01-02T00:00:00Z", "score": 80}]`
+	if err := os.WriteFile(historyPath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write legacy history: %v", err)
+	}
+
+	analyzer := NewTrendAnalyzer(dir)
+	if err := analyzer.LoadHistory(); err != nil {
+		t.Fatalf("failed to load legacy history: %v", err)
+	}
+	report := &StructuralReport{
+		Score:   &StructuralScore{TotalScore: 72, Model: ScoreModelWeighted},
+		Summary: ReportSummary{TotalViolations: 4, Circular: 1, Size: 3},
+	}
+	analyzer.counts = &report.Summary
+	// The legacy entries have no counts, so every category counts from zero
+	if summary := analyzer.GetTrendSummary(72); !strings.Contains(summary, "Circular: 1 (+1)\nLayer: 0 (+0)\nSize: 3 (+3)\nGod Objects: 0 (+0)") {
+		t.Fatalf("expected per-category deltas against the legacy entry, got:\n%s", summary)
+	}
+	if err := analyzer.AppendReport(report); err != nil {
+		t.Fatalf("failed to append report: %v", err)
+	}
+
+	reloaded := NewTrendAnalyzer(dir)
+	if err := reloaded.LoadHistory(); err != nil {
+		t.Fatalf("failed to reload history: %v", err)
+	}
+	history := reloaded.GetAllHistory()
+	if len(history) != 3 || history[1].Score != 80 || history[1].Counts != nil {
+		t.Fatalf("expected the legacy entries to be kept as is, got %+v", history)
+	}
+	if got := history[2]; got.Score != 72 || got.ScoreModel != ScoreModelWeighted || got.Counts == nil || *got.Counts != report.Summary {
+		t.Fatalf("expected the appended entry to carry the report's counts, got %+v", got)
+	}
+
+	next := ReportSummary{Circular: 0, Layer: 2, Size: 3}
+	reloaded.counts = &next
+	if summary := reloaded.GetTrendSummary(70); !strings.Contains(summary, "Circular: 0 (-1)\nLayer: 2 (+2)\nSize: 3 (+0)") {
+		t.Fatalf("expected per-category deltas against the appended entry, got:\n%s", summary)
+	}
+}
+
+func TestTrendAnalyzer_PruneKeepsMostRecentEntries(t *testing.T) {
+	dir := t.TempDir()
+	analyzer := NewTrendAnalyzer(dir)
+	analyzer.maxEntries = 3
+	for score := 1.0; score <= 5; score++ {
+		if err := analyzer.AppendScore(score); err != nil {
+			t.Fatalf("failed to append score: %v", err)
+		}
+	}
+
+	reloaded := NewTrendAnalyzer(dir)
+	if err := reloaded.LoadHistory(); err != nil {
+		t.Fatalf("failed to reload history: %v", err)
+	}
+	var scores []float64
+	for _, entry := range reloaded.GetAllHistory() {
+		scores = append(scores, entry.Score)
+	}
+	if !slices.Equal(scores, []float64{3, 4, 5}) {
+		t.Fatalf("expected the 3 most recent scores with the earliest dropped, got %v", scores)
+	}
+
+	reloaded.Prune(0)
+	if reloaded.GetHistoryLength() != 3 {
+		t.Fatalf("expected Prune(0) to keep every entry, got %d", reloaded.GetHistoryLength())
+	}
+	reloaded.Prune(1)
+	if last, _ := reloaded.GetLastEntry(); reloaded.GetHistoryLength() != 1 || last.Score != 5 {
+		t.Fatalf("expected Prune(1) to keep only the newest entry, got %+v", reloaded.GetAllHistory())
+	}
+}
+
+func TestHistoryMaxEntries_Config(t *testing.T) {
+	if got := historyMaxEntries(nil); got != defaultHistoryMaxEntries {
+		t.Fatalf("expected the default cap without config, got %d", got)
+	}
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	for content, want := range map[string]int{"history:\n  dedupe_window: 5m\n": 100, "history:\n  max_entries: 20\n": 20, "history:\n  max_entries: 0\n": 0} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := NewConfigLoader(configPath).Load()
+		if err != nil || historyMaxEntries(cfg) != want {
+			t.Fatalf("config %q: expected max entries %d, got %+v, %v", content, want, cfg.History, err)
+		}
+	}
+
+	if err := os.WriteFile(configPath, []byte("history:\n  max_entries: -1\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := NewConfigLoader(configPath).Load(); err == nil {
+		t.Fatal("expected a negative history.max_entries to be rejected")
+	}
+}
+
+// truncatedHistory is a history file cut off mid-write
+const truncatedHistory = `[{"timestamp": "2026-01-01T00:00:00Z", "score": 78}, {"timestamp": "2026-01-02T00:00:00Z", "sco`
+
+func TestTrendAnalyzer_LoadHistory_CorruptFileIsBackedUp(t *testing.T) {
+	dir := t.TempDir()
+	writeServiceFixture(t, dir, map[string]string{".repodoctor/history.json": truncatedHistory})
+
+	analyzer := NewTrendAnalyzer(dir)
+	err := analyzer.LoadHistory()
+	if !errors.Is(err, ErrCorruptHistory) {
+		t.Fatalf("expected ErrCorruptHistory, got %v", err)
+	}
+	if analyzer.GetHistoryLength() != 0 {
+		t.Fatalf("expected an empty history, got %d entries", analyzer.GetHistoryLength())
+	}
+	backup, readErr := os.ReadFile(analyzer.historyPath + ".bak")
+	if readErr != nil || string(backup) != truncatedHistory {
+		t.Fatalf("expected the original content in history.json.bak, got %q, %v", backup, readErr)
+	}
+}
+
+func TestAnalysisService_CorruptHistoryKeepsBackup(t *testing.T) {
+	dir := t.TempDir()
+	writeServiceFixture(t, dir, map[string]string{
+		"a.go":                     "package a\n",
+		".repodoctor/history.json": truncatedHistory,
+	})
+
+	report, code := NewAnalysisService().analyze(AnalyzeRequest{Path: dir, Format: string(FormatJSON), Quiet: true})
+	if report == nil || code != 0 {
+		t.Fatalf("expected the run to complete, got exit code %d", code)
+	}
+
+	backup, err := os.ReadFile(filepath.Join(dir, ".repodoctor", "history.json.bak"))
+	if err != nil || string(backup) != truncatedHistory {
+		t.Fatalf("expected the original content in history.json.bak, got %q, %v", backup, err)
+	}
+	reloaded := NewTrendAnalyzer(dir)
+	if err := reloaded.LoadHistory(); err != nil || reloaded.GetHistoryLength() != 1 {
+		t.Fatalf("expected a new history holding this run, got %d entries, %v", reloaded.GetHistoryLength(), err)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ViolationListing controls how the text report lists violations. It only
+// shortens or adds to the listing: totals and the score always cover every
+// violation.
+type ViolationListing struct {
+	// Top limits each violation category to its N worst offenders; 0 lists
+	// them all
+	Top int
+	// GroupBy adds the violation counts per directory ("dir") or per
+	// package ("package"); empty adds no grouping
+	GroupBy string
+}
+
+// -group-by values
+const (
+	groupByDir     = "dir"
+	groupByPackage = "package"
+)
+
+// ListingOmissions counts the violations -top left out of each category
+type ListingOmissions struct {
+	Circular         int
+	Layer            int
+	FeatureIsolation int
+	Size             int
+	GodObject        int
+}
+
+// violationGroup holds the violation counts of one directory or package
+type violationGroup struct {
+	Name             string
+	Circular         int
+	Layer            int
+	FeatureIsolation int
+	DependencyCap    int
+	Size             int
+	GodObject        int
+}
+
+// total returns the violations of the group; a cycle through the group
+// counts once
+func (g *violationGroup) total() int {
+	return g.Circular + g.Layer + g.FeatureIsolation + g.DependencyCap + g.Size + g.GodObject
+}
+
+// validateViolationListing rejects a negative -top and an unknown -group-by
+func validateViolationListing(listing ViolationListing) error {
+	if listing.Top < 0 {
+		return NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid -top: %d", listing.Top), "Use 0 to list every violation, or a positive limit", nil)
+	}
+	switch listing.GroupBy {
+	case "", groupByDir, groupByPackage:
+		return nil
+	default:
+		return NewCLIError(ErrorInvalidArgument, fmt.Sprintf("Invalid -group-by: %s", listing.GroupBy), "Use dir or package", nil)
+	}
+}
+
+// limitViolations returns a copy of the report listing at most top
+// violations per category, and counts the rest in Summary.Omitted. Size
+// violations are ranked by lines over their threshold and god objects by
+// fields and methods over the configured maximums, worst first; cycles and
+// layer violations keep the stable file order. Ties keep the stable order,
+// so the listing does not depend on the order rules reported in.
+func limitViolations(report *StructuralReport, top int) *StructuralReport {
+	if report == nil || top <= 0 {
+		return report
+	}
+
+	out := *report
+	size := sortedSize(report.Size)
+	sort.SliceStable(size, func(i, j int) bool {
+		return size[i].Lines-size[i].Threshold > size[j].Lines-size[j].Threshold
+	})
+	godObjects := sortedGodObject(report.GodObject)
+	maxFields, maxMethods := godObjectLimits(report)
+	overage := func(v GodObjectViolation) int {
+		return max(v.FieldCount-maxFields, 0) + max(v.MethodCount-maxMethods, 0)
+	}
+	sort.SliceStable(godObjects, func(i, j int) bool {
+		return overage(godObjects[i]) > overage(godObjects[j])
+	})
+
+	out.Circular, out.Summary.Omitted.Circular = topOf(sortedCircular(report.Circular), top)
+	out.Layer, out.Summary.Omitted.Layer = topOf(sortedLayer(report.Layer), top)
+	out.OptIn.FeatureIsolation, out.Summary.Omitted.FeatureIsolation = topOf(sortedFeatureIsolation(report.OptIn.FeatureIsolation), top)
+	out.Size, out.Summary.Omitted.Size = topOf(size, top)
+	out.GodObject, out.Summary.Omitted.GodObject = topOf(godObjects, top)
+	return &out
+}
+
+// topOf returns the first top violations and how many were left out
+func topOf[T any](violations []T, top int) ([]T, int) {
+	if len(violations) <= top {
+		return violations, 0
+	}
+	return violations[:top], len(violations) - top
+}
+
+// godObjectLimits returns the god object rule's field and method maximums
+// the report was checked with, or the rule's defaults
+func godObjectLimits(report *StructuralReport) (int, int) {
+	defaults := NewGodObjectRule()
+	maxFields, maxMethods := defaults.MaxFields, defaults.MaxMethods
+	for _, descriptor := range report.Metrics.Rules {
+		if descriptor.Name != "rule.god-object" {
+			continue
+		}
+		if value, ok := descriptor.Thresholds["max_fields"]; ok {
+			maxFields = int(value)
+		}
+		if value, ok := descriptor.Thresholds["max_methods"]; ok {
+			maxMethods = int(value)
+		}
+	}
+	return maxFields, maxMethods
+}
+
+// formatOmittedNote tells the text report's reader how many violations of a
+// category -top left out
+func formatOmittedNote(omitted int) string {
+	return fmt.Sprintf("… and %d more", omitted)
+}
+
+// groupViolations counts the violations of every directory or package,
+// most violations first and then by name. A directory is the top-level
+// directory below the analyzed one, "." for its own files; a package is a
+// file's own directory. A cycle counts once in every group it passes
+// through and a layer violation in the group of its importing file.
+func groupViolations(report *StructuralReport, groupBy string) []*violationGroup {
+	if report == nil || groupBy == "" {
+		return nil
+	}
+
+	byName := make(map[string]*violationGroup)
+	group := func(file string) *violationGroup {
+		name := violationGroupName(relativeToBase(file, report.Path), groupBy)
+		if _, ok := byName[name]; !ok {
+			byName[name] = &violationGroup{Name: name}
+		}
+		return byName[name]
+	}
+	for _, v := range report.Circular {
+		seen := make(map[*violationGroup]bool)
+		for _, file := range v.Path {
+			if g := group(file); !seen[g] {
+				seen[g] = true
+				g.Circular++
+			}
+		}
+	}
+	for _, v := range report.Layer {
+		group(v.From).Layer++
+	}
+	for _, v := range report.OptIn.FeatureIsolation {
+		group(v.From).FeatureIsolation++
+	}
+	for _, v := range report.OptIn.DependencyCap {
+		group(v.Manifest).DependencyCap++
+	}
+	for _, v := range report.Size {
+		group(v.File).Size++
+	}
+	for _, v := range report.GodObject {
+		group(v.File).GodObject++
+	}
+
+	groups := make([]*violationGroup, 0, len(byName))
+	for _, name := range sortedKeys(byName) {
+		groups = append(groups, byName[name])
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].total() > groups[j].total()
+	})
+	return groups
+}
+
+// violationGroupName returns the directory or package of a file path
+func violationGroupName(file, groupBy string) string {
+	dir := path.Dir(slashPath(file))
+	if groupBy == groupByPackage || dir == "." {
+		return dir
+	}
+	if first, _, ok := strings.Cut(strings.TrimPrefix(dir, "/"), "/"); ok {
+		return first
+	}
+	return strings.TrimPrefix(dir, "/")
+}
+
+// violationGroupsTitle names the grouping section of the text report
+func violationGroupsTitle(groupBy string) string {
+	if groupBy == groupByPackage {
+		return "VIOLATIONS BY PACKAGE"
+	}
+	return "VIOLATIONS BY DIRECTORY"
+}
+
+// formatViolationGroupLine formats one group of the grouping section. The
+// counts of opt-in rule categories are only shown when the group has any.
+func formatViolationGroupLine(index int, g *violationGroup) string {
+	counts := []string{fmt.Sprintf("circular %d", g.Circular), fmt.Sprintf("layer %d", g.Layer)}
+	if g.FeatureIsolation > 0 {
+		counts = append(counts, fmt.Sprintf("feature isolation %d", g.FeatureIsolation))
+	}
+	if g.DependencyCap > 0 {
+		counts = append(counts, fmt.Sprintf("dependency cap %d", g.DependencyCap))
+	}
+	counts = append(counts, fmt.Sprintf("size %d", g.Size), fmt.Sprintf("god object %d", g.GodObject))
+	return fmt.Sprintf("[%d] %s: %d (%s)", index, g.Name, g.total(), strings.Join(counts, ", "))
+}
+
+func writeViolationGroups(sb *strings.Builder, groups []*violationGroup, groupBy string, layout *textLayout) {
+	if len(groups) == 0 {
+		return
+	}
+
+	writeSectionBox(sb, layout, violationGroupsTitle(groupBy))
+
+	for i, g := range groups {
+		sb.WriteString(formatViolationGroupLine(i+1, g) + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+// writeViolationGroupsWithColor writes the grouping section with colors
+func writeViolationGroupsWithColor(sb *strings.Builder, groups []*violationGroup, groupBy string, formatter *ColorFormatter, layout *textLayout) {
+	if len(groups) == 0 {
+		return
+	}
+
+	writeSectionBoxWithColor(sb, formatter, layout, violationGroupsTitle(groupBy), ColorCyan)
+
+	for i, g := range groups {
+		sb.WriteString(formatViolationGroupLine(i+1, g) + "\n")
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// listingFixtureReport has tied size and god object violations, listed in
+// the given order
+func listingFixtureReport(reverse bool) *StructuralReport {
+	report := &StructuralReport{
+		Path:  ".",
+		Score: &StructuralScore{TotalScore: 80, MaxScore: 100, SizeCount: 4, GodObjectCount: 3, ViolationCount: 7},
+		Size: []SizeViolation{
+			{File: "b/b.go", Lines: 600, Threshold: 500, Line: 1},
+			{File: "a/a.go", Function: "Long", Lines: 180, Threshold: 80, Line: 3},
+			{File: "a/a.go", Lines: 550, Threshold: 500, Line: 1},
+			{File: "c/c.go", Function: "Run", Lines: 90, Threshold: 80, Line: 7},
+		},
+		GodObject: []GodObjectViolation{
+			{File: "b/b.go", StructName: "Tie", FieldCount: 17, MethodCount: 10},
+			{File: "a/a.go", StructName: "Tie", FieldCount: 15, MethodCount: 12},
+			{File: "c/c.go", StructName: "Worst", FieldCount: 30, MethodCount: 11},
+		},
+	}
+	if reverse {
+		slices.Reverse(report.Size)
+		slices.Reverse(report.GodObject)
+	}
+	return report
+}
+
+func TestLimitViolations_WorstFirstWithDeterministicTies(t *testing.T) {
+	limited := limitViolations(listingFixtureReport(false), 2)
+	if again := limitViolations(listingFixtureReport(true), 2); !reflect.DeepEqual(again, limited) {
+		t.Fatalf("expected the listing not to depend on the input order\n%+v\n%+v", limited, again)
+	}
+
+	// a/a.go:3 and b/b.go are both 100 lines over; the file order breaks the tie
+	if got := limited.Size; len(got) != 2 || got[0].File != "a/a.go" || got[0].Line != 3 || got[1].File != "b/b.go" {
+		t.Errorf("expected the two 100-line overages in file order, got %+v", got)
+	}
+	// Worst is 16 over; both Ties are 2 over, so a/a.go comes first
+	if got := limited.GodObject; len(got) != 2 || got[0].StructName != "Worst" || got[1].File != "a/a.go" {
+		t.Errorf("expected Worst then the a/a.go tie, got %+v", got)
+	}
+	if limited.Summary.Omitted != (ListingOmissions{Size: 2, GodObject: 1}) {
+		t.Errorf("expected 2 size and 1 god object violation omitted, got %+v", limited.Summary.Omitted)
+	}
+	if unlimited := listingFixtureReport(false); limitViolations(unlimited, 0) != unlimited {
+		t.Error("expected -top 0 to keep the report as is")
+	}
+}
+
+func TestLimitViolations_UsesConfiguredGodObjectLimits(t *testing.T) {
+	report := listingFixtureReport(false)
+	report.Metrics.Rules = []RuleDescriptor{{Name: "rule.god-object", Thresholds: map[string]float64{"max_fields": 10, "max_methods": 5}}}
+
+	// With these limits b/b.go's Tie is 12 over and a/a.go's 12 over too,
+	// while Worst is 26 over
+	if got := limitViolations(report, 3).GodObject; got[0].StructName != "Worst" || got[1].File != "a/a.go" || got[2].File != "b/b.go" {
+		t.Errorf("expected the configured limits to rank the god objects, got %+v", got)
+	}
+}
+
+func TestReporter_TopKeepsTotals(t *testing.T) {
+	reporter := &Reporter{format: FormatText, width: 100, listing: ViolationListing{Top: 1}}
+	out := reporter.Format(listingFixtureReport(false))
+
+	for _, want := range []string{"  - Size Violations: 4\n", "… and 3 more\n", "… and 2 more\n", "Score: 80.0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the text report:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "b/b.go") {
+		t.Errorf("expected only the worst violation of each category, got:\n%s", out)
+	}
+}
+
+func TestGroupViolations_ByDirectoryAndPackage(t *testing.T) {
+	report := &StructuralReport{
+		Path:     "/repo",
+		Score:    &StructuralScore{TotalScore: 70, MaxScore: 100},
+		Circular: []CycleViolation{{Path: []string{"/repo/internal/a/a.go", "/repo/internal/b/b.go", "/repo/cmd/main.go"}}},
+		Layer:    []LayerViolation{{From: "/repo/internal/a/a.go", To: "/repo/cmd/main.go"}},
+		Size:     []SizeViolation{{File: "/repo/main.go"}, {File: "/repo/internal/b/b.go"}},
+	}
+
+	names := func(groups []*violationGroup) []string {
+		var lines []string
+		for i, g := range groups {
+			lines = append(lines, formatViolationGroupLine(i+1, g))
+		}
+		return lines
+	}
+	wantDirs := []string{
+		"[1] internal: 3 (circular 1, layer 1, size 1, god object 0)",
+		"[2] .: 1 (circular 0, layer 0, size 1, god object 0)",
+		"[3] cmd: 1 (circular 1, layer 0, size 0, god object 0)",
+	}
+	if got := names(groupViolations(report, groupByDir)); !reflect.DeepEqual(got, wantDirs) {
+		t.Errorf("unexpected directory groups:\n%s", strings.Join(got, "\n"))
+	}
+	wantPackages := []string{
+		"[1] internal/a: 2 (circular 1, layer 1, size 0, god object 0)",
+		"[2] internal/b: 2 (circular 1, layer 0, size 1, god object 0)",
+		"[3] .: 1 (circular 0, layer 0, size 1, god object 0)",
+		"[4] cmd: 1 (circular 1, layer 0, size 0, god object 0)",
+	}
+	if got := names(groupViolations(report, groupByPackage)); !reflect.DeepEqual(got, wantPackages) {
+		t.Errorf("unexpected package groups:\n%s", strings.Join(got, "\n"))
+	}
+
+	out := (&Reporter{format: FormatText, width: 100, listing: ViolationListing{Top: 1, GroupBy: groupByDir}}).Format(report)
+	if !strings.Contains(out, "VIOLATIONS BY DIRECTORY") || !strings.Contains(out, wantDirs[0]) {
+		t.Errorf("expected the groups to count every violation despite -top, got:\n%s", out)
+	}
+}
+
+func TestComposeAnalyzeRequest_ValidatesListing(t *testing.T) {
+	for _, args := range [][]string{{"-top", "-1"}, {"-group-by", "file"}} {
+		if _, err := parseAnalyzeFlags(append(args, "-path", t.TempDir())); err == nil {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+	req, err := parseAnalyzeFlags([]string{"-top", "5", "-group-by", "package", "-path", t.TempDir()})
+	if err != nil {
+		t.Fatalf("parseAnalyzeFlags failed: %v", err)
+	}
+	if got := req.Report.Listing; got != (ViolationListing{Top: 5, GroupBy: groupByPackage}) {
+		t.Errorf("expected the listing to reach the service request, got %+v", got)
+	}
+}